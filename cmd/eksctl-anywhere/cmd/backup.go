@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up resources",
+	Long:  "Use eksctl anywhere backup to back up resources, such as etcd",
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+// etcdStoreOptions are the flags shared by the etcd backup and restore commands to select where snapshots are stored
+type etcdStoreOptions struct {
+	localPath string
+	s3        etcdbackup.S3Config
+}
+
+func (o *etcdStoreOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.localPath, "local-path", "", "Local directory to store etcd snapshots in")
+	flags.StringVar(&o.s3.Bucket, "s3-bucket", "", "S3 bucket to store etcd snapshots in. Credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars")
+	flags.StringVar(&o.s3.Prefix, "s3-prefix", "", "Prefix of the etcd snapshot object keys")
+	flags.StringVar(&o.s3.Region, "s3-region", "", "Region of the S3 bucket")
+	flags.StringVar(&o.s3.Endpoint, "s3-endpoint", "", "Endpoint of an S3-compatible service, such as MinIO")
+}
+
+func (o *etcdStoreOptions) store() (etcdbackup.Store, error) {
+	var s3 *etcdbackup.S3Config
+	if o.s3.Bucket != "" {
+		s3 = &o.s3
+	}
+	return etcdbackup.NewStore(o.localPath, s3)
+}
+
+type backupEtcdOptions struct {
+	clusterOptions
+	etcdStoreOptions
+	retention int
+}
+
+var backupEtcdOpts = &backupEtcdOptions{}
+
+var backupEtcdCmd = &cobra.Command{
+	Use:          "etcd",
+	Short:        "Take a snapshot of the etcd of a cluster",
+	Long:         "This command is used to take a snapshot of the etcd of a cluster and store it in a local directory or an S3 bucket",
+	PreRunE:      preRunBackupEtcd,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := backupEtcdOpts.backupEtcd(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to back up etcd: %v", err)
+		}
+		return nil
+	},
+}
+
+func preRunBackupEtcd(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func init() {
+	backupCmd.AddCommand(backupEtcdCmd)
+	backupEtcdCmd.Flags().StringVarP(&backupEtcdOpts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	backupEtcdCmd.Flags().StringVar(&backupEtcdOpts.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	backupEtcdCmd.Flags().StringVar(&backupEtcdOpts.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	backupEtcdCmd.Flags().IntVar(&backupEtcdOpts.retention, "retention", etcdbackup.DefaultRetention, "Number of snapshots of the cluster to keep, 0 keeps all of them")
+	backupEtcdOpts.addFlags(backupEtcdCmd.Flags())
+	err := backupEtcdCmd.MarkFlagRequired("filename")
+	if err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+}
+
+func (o *backupEtcdOptions) backupEtcd(ctx context.Context) error {
+	clusterSpec, err := newClusterSpec(o.clusterOptions)
+	if err != nil {
+		return err
+	}
+	store, err := o.store()
+	if err != nil {
+		return err
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).WithKubectl().WithDocker().Build()
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Taking etcd snapshot", "cluster", clusterSpec.Name)
+	name, err := etcdbackup.NewBackupper(deps.Kubectl, deps.DockerClient, store).Backup(ctx, specManagementCluster(clusterSpec), clusterSpec, o.retention)
+	if err != nil {
+		return err
+	}
+
+	logger.MarkSuccess("etcd backed up!")
+	logger.Info("Restore it with eksctl anywhere restore etcd", "snapshot", name)
+	return nil
+}
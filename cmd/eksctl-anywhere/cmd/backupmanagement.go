@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/managementbackup"
+)
+
+type backupManagementOptions struct {
+	clusterOptions
+	output string
+}
+
+var backupManagementOpts = &backupManagementOptions{}
+
+var backupManagementCmd = &cobra.Command{
+	Use:          "management",
+	Short:        "Back up the EKS-A and CAPI objects of a management cluster",
+	Long:         "This command is used to export the EKS-A objects of a management cluster and the CAPI objects of all its workload clusters to an archive, which can be restored with eksctl anywhere restore management",
+	PreRunE:      preRunBackupManagement,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := backupManagementOpts.backupManagement(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to back up management cluster: %v", err)
+		}
+		return nil
+	},
+}
+
+func preRunBackupManagement(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func init() {
+	backupCmd.AddCommand(backupManagementCmd)
+	backupManagementCmd.Flags().StringVarP(&backupManagementOpts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration of the management cluster")
+	backupManagementCmd.Flags().StringVar(&backupManagementOpts.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	backupManagementCmd.Flags().StringVar(&backupManagementOpts.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	backupManagementCmd.Flags().StringVarP(&backupManagementOpts.output, "output", "o", "", "Archive file to write the backup to. Defaults to a timestamped file in the cluster folder")
+	err := backupManagementCmd.MarkFlagRequired("filename")
+	if err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+}
+
+func (o *backupManagementOptions) backupManagement(ctx context.Context) error {
+	clusterSpec, err := newClusterSpec(o.clusterOptions)
+	if err != nil {
+		return err
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).WithKubectl().Build()
+	if err != nil {
+		return err
+	}
+
+	managementCluster := specManagementCluster(clusterSpec)
+	logger.Info("Backing up management cluster", "cluster", managementCluster.Name)
+	archive, err := managementbackup.NewBackupper(deps.Kubectl).Backup(ctx, managementCluster)
+	if err != nil {
+		return err
+	}
+
+	output := o.output
+	if output == "" {
+		output = filepath.Join(clusterSpec.Name, managementbackup.ArchiveName(managementCluster.Name, archive.Metadata.CreatedAt))
+	}
+	if err = os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return fmt.Errorf("error creating management cluster backup folder: %v", err)
+	}
+	if err = archive.Write(output); err != nil {
+		return err
+	}
+
+	logger.MarkSuccess("Management cluster backed up!")
+	logger.Info("Backup archive written", "archive", output, "workloadClusters", len(archive.Metadata.WorkloadClusters), "createdAt", archive.Metadata.CreatedAt.Format(time.RFC3339))
+	logger.Info("The archive contains the cluster secrets, store it securely")
+	return nil
+}
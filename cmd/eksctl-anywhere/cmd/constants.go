@@ -1,5 +1,7 @@
 package cmd
 
 const (
-	kubeconfigPattern = "%s-eks-a-cluster.kubeconfig"
+	kubeconfigPattern      = "%s-eks-a-cluster.kubeconfig"
+	effectiveConfigPattern = "%s-eks-a-cluster-effective.yaml"
+	renderedConfigPattern  = "%s-eks-a-cluster-rendered.yaml"
 )
@@ -10,14 +10,17 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/types"
 	"github.com/aws/eks-anywhere/pkg/validations"
 	"github.com/aws/eks-anywhere/pkg/validations/createvalidations"
+	"github.com/aws/eks-anywhere/pkg/version"
 	"github.com/aws/eks-anywhere/pkg/workflows"
 )
 
 type createClusterOptions struct {
 	clusterOptions
+	validationOptions
 	forceClean  bool
 	skipIpCheck bool
 }
@@ -31,6 +34,11 @@ var createClusterCmd = &cobra.Command{
 	PreRunE:      preRunCreateCluster,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cleanup, err := cc.renderConfig()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 		if err := cc.validate(cmd.Context()); err != nil {
 			return err
 		}
@@ -43,11 +51,12 @@ var createClusterCmd = &cobra.Command{
 
 func init() {
 	createCmd.AddCommand(createClusterCmd)
-	createClusterCmd.Flags().StringVarP(&cc.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	cc.clusterOptions.addConfigFlags(createClusterCmd.Flags())
 	createClusterCmd.Flags().BoolVar(&cc.forceClean, "force-cleanup", false, "Force deletion of previously created bootstrap cluster")
 	createClusterCmd.Flags().BoolVar(&cc.skipIpCheck, "skip-ip-check", false, "Skip check for whether cluster control plane ip is in use")
 	createClusterCmd.Flags().StringVar(&cc.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
 	createClusterCmd.Flags().StringVar(&cc.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	cc.validationOptions.addFlags(createClusterCmd.Flags())
 	err := createClusterCmd.MarkFlagRequired("filename")
 	if err != nil {
 		log.Fatalf("Error marking flag as required: %v", err)
@@ -76,6 +85,11 @@ func (cc *createClusterOptions) validate(ctx context.Context) error {
 }
 
 func (cc *createClusterOptions) createCluster(ctx context.Context) error {
+	runnerOpts, err := cc.runnerOpts()
+	if err != nil {
+		return err
+	}
+
 	clusterSpec, err := newClusterSpec(cc.clusterOptions)
 	if err != nil {
 		return err
@@ -98,7 +112,7 @@ func (cc *createClusterOptions) createCluster(ctx context.Context) error {
 		deps.ClusterManager,
 		deps.FluxAddonClient,
 		deps.Writer,
-	)
+	).WithValidationOpts(runnerOpts...)
 
 	var cluster *types.Cluster
 	if clusterSpec.ManagementCluster == nil {
@@ -113,6 +127,11 @@ func (cc *createClusterOptions) createCluster(ctx context.Context) error {
 		}
 	}
 
+	clusterSpec.RegistryMirrorCredentials, err = registrymirror.LoadCredentials(ctx, deps.Kubectl, clusterSpec.ManagementCluster, clusterSpec.Cluster)
+	if err != nil {
+		return err
+	}
+
 	validationOpts := &validations.Opts{
 		Kubectl: deps.Kubectl,
 		Spec:    clusterSpec,
@@ -122,6 +141,7 @@ func (cc *createClusterOptions) createCluster(ctx context.Context) error {
 		},
 		ManagementCluster: cluster,
 		Provider:          deps.Provider,
+		CliVersion:        version.Get().GitVersion,
 	}
 	createValidations := createvalidations.New(validationOpts)
 
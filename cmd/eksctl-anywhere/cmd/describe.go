@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Describe resources",
+	Long:  "Use eksctl anywhere describe to show the details of resources, such as bundles",
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/version"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+type describeBundleOptions struct {
+	kubeVersion     string
+	bundleNumber    int
+	bundlesOverride string
+}
+
+var describeBundleOpts = &describeBundleOptions{}
+
+func init() {
+	describeCmd.AddCommand(describeBundleCmd)
+	describeBundleCmd.Flags().StringVar(&describeBundleOpts.kubeVersion, "kube-version", "", "Only describe the components for this Kubernetes version")
+	describeBundleCmd.Flags().IntVar(&describeBundleOpts.bundleNumber, "bundle-number", 0, "Bundle number from the releases manifest. Defaults to the bundle for the current version of the CLI")
+	describeBundleCmd.Flags().StringVar(&describeBundleOpts.bundlesOverride, "bundles-override", "", "Describe a Bundles manifest file instead of a release bundle")
+}
+
+var describeBundleCmd = &cobra.Command{
+	Use:          "bundle",
+	Short:        "Describe the components of an EKS Anywhere bundle",
+	Long:         "This command is used to print the version, images and manifests of every component in an EKS Anywhere bundle, for each supported Kubernetes version",
+	PreRunE:      preRunDescribeBundleCmd,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundles, err := getBundles(describeBundleOpts.bundleNumber, describeBundleOpts.bundlesOverride)
+		if err != nil {
+			return err
+		}
+		return describeBundle(os.Stdout, bundles, describeBundleOpts.kubeVersion)
+	},
+}
+
+// getBundles reads the bundles manifest from the override file, the release with the bundle number
+// or the release for the current CLI version, in that order
+func getBundles(bundleNumber int, bundlesOverride string) (*v1alpha1.Bundles, error) {
+	if bundlesOverride != "" {
+		return cluster.NewSpec(cluster.WithOverrideBundlesManifest(bundlesOverride)).GetBundles(version.Get())
+	}
+	spec := cluster.NewSpec()
+	if bundleNumber != 0 {
+		return spec.GetBundlesForRelease(bundleNumber)
+	}
+	return spec.GetBundles(version.Get())
+}
+
+func describeBundle(out io.Writer, bundles *v1alpha1.Bundles, kubeVersion string) error {
+	fmt.Fprintf(out, "Bundle: %d\n", bundles.Spec.Number)
+	fmt.Fprintf(out, "CLI versions: %s - %s\n", bundles.Spec.CliMinVersion, bundles.Spec.CliMaxVersion)
+
+	found := false
+	for i := range bundles.Spec.VersionsBundles {
+		vb := &bundles.Spec.VersionsBundles[i]
+		if kubeVersion != "" && vb.KubeVersion != kubeVersion {
+			continue
+		}
+		found = true
+
+		fmt.Fprintf(out, "\nKubernetes %s\n", vb.KubeVersion)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, component := range cluster.BundleComponents(vb) {
+			fmt.Fprintf(w, "  %s\t%s\n", component.Name, component.Version)
+			for _, image := range component.Images {
+				fmt.Fprintf(w, "    image\t%s\n", image.URI)
+			}
+			for _, manifest := range component.Manifests {
+				fmt.Fprintf(w, "    manifest\t%s\n", manifest.URI)
+			}
+			for _, archive := range component.Archives {
+				fmt.Fprintf(w, "    archive\t%s\n", archive.URI)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if !found && kubeVersion != "" {
+		return fmt.Errorf("kubernetes version %s is not supported by bundle %d", kubeVersion, bundles.Spec.Number)
+	}
+	return nil
+}
+
+func preRunDescribeBundleCmd(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err := viper.BindPFlag(flag.Name, flag); err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare resources",
+	Long:  "Use eksctl anywhere diff to compare resources, such as bundles",
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+func init() {
+	diffCmd.AddCommand(diffBundleCmd)
+}
+
+var diffBundleCmd = &cobra.Command{
+	Use:          "bundle <old bundle number> <new bundle number>",
+	Short:        "Compare the components of two EKS Anywhere bundles",
+	Long:         "This command is used to show the component versions, images and manifests that change between two bundle numbers from the releases manifest, for example to review what an upgrade will pull in",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		numbers := make([]int, 0, len(args))
+		for _, arg := range args {
+			number, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid bundle number %s", arg)
+			}
+			numbers = append(numbers, number)
+		}
+
+		spec := cluster.NewSpec()
+		old, err := spec.GetBundlesForRelease(numbers[0])
+		if err != nil {
+			return err
+		}
+		new, err := spec.GetBundlesForRelease(numbers[1])
+		if err != nil {
+			return err
+		}
+		printBundlesDiff(os.Stdout, old, new)
+		return nil
+	},
+}
+
+func printBundlesDiff(out io.Writer, old, new *v1alpha1.Bundles) {
+	diffs := cluster.DiffBundles(old, new)
+	if len(diffs) == 0 {
+		fmt.Fprintf(out, "No changes between bundle %d and bundle %d\n", old.Spec.Number, new.Spec.Number)
+		return
+	}
+
+	fmt.Fprintf(out, "Changes from bundle %d to bundle %d\n", old.Spec.Number, new.Spec.Number)
+	for _, diff := range diffs {
+		switch {
+		case diff.Added:
+			fmt.Fprintf(out, "\n+ Kubernetes %s (added)\n", diff.KubeVersion)
+		case diff.Removed:
+			fmt.Fprintf(out, "\n- Kubernetes %s (removed)\n", diff.KubeVersion)
+		default:
+			fmt.Fprintf(out, "\n~ Kubernetes %s\n", diff.KubeVersion)
+		}
+		if diff.Added || diff.Removed {
+			continue
+		}
+
+		for _, component := range diff.Components {
+			fmt.Fprintf(out, "  %s: %s -> %s\n", component.Name, versionOrNone(component.OldVersion), versionOrNone(component.NewVersion))
+			for _, artifact := range component.Artifacts {
+				switch {
+				case artifact.Old == "":
+					fmt.Fprintf(out, "    + %s\n", artifact.New)
+				case artifact.New == "":
+					fmt.Fprintf(out, "    - %s\n", artifact.Old)
+				default:
+					fmt.Fprintf(out, "    - %s\n    + %s\n", artifact.Old, artifact.New)
+				}
+			}
+		}
+	}
+}
+
+func versionOrNone(version string) string {
+	if version == "" {
+		return "none"
+	}
+	return version
+}
@@ -80,7 +80,11 @@ func downloadArtifacts(context context.Context, opts *downloadArtifactsOptions)
 			logger.Info(fmt.Sprintf("Found artifact: %s\n", manifestURI))
 			continue
 		}
-		if err = downloadArtifact("", opts.downloadDir, manifestURI, reader); err != nil {
+		checksum, err := reader.PublishedChecksum(manifestURI)
+		if err != nil {
+			return err
+		}
+		if err = downloadArtifact("", opts.downloadDir, manifestURI, checksum, reader); err != nil {
 			return fmt.Errorf("error downloading artifact: %v", err)
 		}
 	}
@@ -92,7 +96,7 @@ func downloadArtifacts(context context.Context, opts *downloadArtifactsOptions)
 				logger.Info(fmt.Sprintf("Found artifact: %s\n", manifest.URI))
 				continue
 			}
-			if err = downloadArtifact(component, opts.downloadDir, manifest.URI, reader); err != nil {
+			if err = downloadArtifact(component, opts.downloadDir, manifest.URI, cluster.ManifestChecksum(manifest), reader); err != nil {
 				return fmt.Errorf("error downloading artifact for component %s: %v", component, err)
 			}
 		}
@@ -122,7 +126,7 @@ func preRunDownloadArtifactsCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func downloadArtifact(component, downloadDir, artifactUri string, reader *files.Reader) error {
+func downloadArtifact(component, downloadDir, artifactUri string, checksum files.Checksum, reader *files.Reader) error {
 	logger.V(3).Info(fmt.Sprintf("Downloading artifact: %s", artifactUri))
 
 	fileName := filepath.Base(artifactUri)
@@ -139,7 +143,7 @@ func downloadArtifact(component, downloadDir, artifactUri string, reader *files.
 
 	logger.V(3).Info(fmt.Sprintf("Creating local artifact file: %s", filePath))
 
-	contents, err := reader.ReadFile(artifactUri)
+	contents, err := reader.ReadFileWithChecksum(artifactUri, checksum)
 	if err != nil {
 		return err
 	}
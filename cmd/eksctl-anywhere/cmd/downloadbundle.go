@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/airgap"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registry"
+	"github.com/aws/eks-anywhere/pkg/version"
+)
+
+type downloadBundleOptions struct {
+	fileName    string
+	downloadDir string
+	output      string
+	retainDir   bool
+	skipOvas    bool
+	concurrency int
+}
+
+var downloadBundleOpts = &downloadBundleOptions{}
+
+func init() {
+	downloadCmd.AddCommand(downloadBundleCmd)
+	downloadBundleCmd.Flags().StringVarP(&downloadBundleOpts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	downloadBundleCmd.Flags().StringVarP(&downloadBundleOpts.downloadDir, "download-dir", "d", "eks-anywhere-bundle", "Directory to download the artifacts to")
+	downloadBundleCmd.Flags().StringVarP(&downloadBundleOpts.output, "output", "o", "eks-anywhere-bundle.tar.gz", "Path of the bundle archive")
+	downloadBundleCmd.Flags().BoolVarP(&downloadBundleOpts.retainDir, "retain-dir", "r", false, "Do not delete the download folder after creating the bundle archive")
+	downloadBundleCmd.Flags().BoolVar(&downloadBundleOpts.skipOvas, "skip-ovas", false, "Do not include the vSphere OVAs in the bundle")
+	downloadBundleCmd.Flags().IntVar(&downloadBundleOpts.concurrency, "concurrency", 4, "Number of images to download in parallel")
+	if err := downloadBundleCmd.MarkFlagRequired("filename"); err != nil {
+		log.Fatalf("Error marking filename flag as required: %v", err)
+	}
+}
+
+var downloadBundleCmd = &cobra.Command{
+	Use:          "bundle",
+	Short:        "Download an air-gap bundle with all the EKS Anywhere artifacts",
+	Long:         "This command is used to download the images, manifests and OVAs needed to create a cluster into a single archive that can be imported in an environment without internet access",
+	PreRunE:      preRunDownloadBundleCmd,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return downloadBundle(cmd.Context(), downloadBundleOpts)
+	},
+}
+
+func downloadBundle(ctx context.Context, opts *downloadBundleOptions) error {
+	cliVersion := version.Get()
+	clusterSpec, err := cluster.NewSpecFromClusterConfig(opts.fileName, cliVersion)
+	if err != nil {
+		return err
+	}
+
+	client, err := registry.NewClient()
+	if err != nil {
+		return err
+	}
+
+	downloaderOpts := []airgap.DownloaderOpt{airgap.WithConcurrency(opts.concurrency)}
+	if opts.skipOvas {
+		downloaderOpts = append(downloaderOpts, airgap.WithoutOvas())
+	}
+	verifier, err := newSignatureVerifier(clusterSpec)
+	if err != nil {
+		return err
+	}
+	if verifier != nil {
+		downloaderOpts = append(downloaderOpts, airgap.WithSignatureVerifier(verifier))
+	}
+	reader := files.NewReader(files.WithUserAgent(fmt.Sprintf("eks-a-cli-download/%s", cliVersion.GitVersion)))
+	downloader := airgap.NewDownloader(reader, client, downloaderOpts...)
+
+	if _, err = downloader.Download(ctx, clusterSpec, cliVersion, opts.downloadDir, opts.output); err != nil {
+		return fmt.Errorf("error downloading bundle: %v", err)
+	}
+
+	if !opts.retainDir {
+		if err = os.RemoveAll(opts.downloadDir); err != nil {
+			return err
+		}
+	}
+
+	logger.MarkSuccess("Bundle downloaded", "archive", opts.output)
+	return nil
+}
+
+func preRunDownloadBundleCmd(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err := viper.BindPFlag(flag.Name, flag); err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
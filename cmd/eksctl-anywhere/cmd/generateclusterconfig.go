@@ -1,32 +1,65 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
-	"strings"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	"sigs.k8s.io/yaml"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/configgenerator"
 	"github.com/aws/eks-anywhere/pkg/constants"
-	"github.com/aws/eks-anywhere/pkg/templater"
+	"github.com/aws/eks-anywhere/pkg/dependencies"
 	"github.com/aws/eks-anywhere/pkg/validations"
 )
 
+const vSphereServerEnv = "VSPHERE_SERVER"
+
+type generateClusterConfigOptions struct {
+	provider             string
+	kubernetesVersion    string
+	controlPlaneCount    int
+	workerCount          int
+	etcdCount            int
+	externalEtcd         bool
+	controlPlaneEndpoint string
+	controlPlaneMachine  configgenerator.MachineSize
+	workerMachine        configgenerator.MachineSize
+	etcdMachine          configgenerator.MachineSize
+	vsphere              configgenerator.VSphereOptions
+	osFamily             string
+	vsphereAutofill      bool
+	oidc                 v1alpha1.OIDCConfigSpec
+	awsIamRegion         string
+	awsIamBackendModes   []string
+	gitOps               v1alpha1.Github
+	httpProxy            string
+	httpsProxy           string
+	noProxy              []string
+	mirrorEndpoint       string
+	mirrorCACert         string
+	interactive          bool
+}
+
+var gco = &generateClusterConfigOptions{}
+
 var generateClusterConfigCmd = &cobra.Command{
-	Use:    "clusterconfig <cluster-name> (max 80 chars)",
-	Short:  "Generate cluster config",
-	Long:   "This command is used to generate a cluster config yaml for the create cluster command",
-	PreRun: preRunGenerateClusterConfig,
+	Use:          "clusterconfig <cluster-name> (max 80 chars)",
+	Short:        "Generate cluster config",
+	Long:         "This command is used to generate a cluster config yaml for the create cluster command",
+	PreRun:       preRunGenerateClusterConfig,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		clusterName, err := validations.ValidateClusterNameArg(args)
 		if err != nil {
 			return err
 		}
-		err = generateClusterConfig(clusterName)
+		err = gco.generateClusterConfig(cmd.Context(), cmd.Flags(), clusterName)
 		if err != nil {
 			return fmt.Errorf("failed to generate eks-a cluster config: %v", err) // need to have better error handling here in own func
 		}
@@ -45,82 +78,197 @@ func preRunGenerateClusterConfig(cmd *cobra.Command, args []string) {
 
 func init() {
 	generateCmd.AddCommand(generateClusterConfigCmd)
-	generateClusterConfigCmd.Flags().StringP("provider", "p", "", "Provider to use (vsphere or docker)")
+	flags := generateClusterConfigCmd.Flags()
+	flags.StringVarP(&gco.provider, "provider", "p", "", "Provider to use (vsphere or docker)")
+	flags.BoolVarP(&gco.interactive, "interactive", "i", false, "Prompt for the values not set with flags")
+	flags.StringVar(&gco.kubernetesVersion, "kubernetes-version", string(v1alpha1.Kube121), "Kubernetes version of the cluster")
+	flags.IntVar(&gco.controlPlaneCount, "control-plane-count", 0, "Number of control plane nodes. Defaults to the provider default")
+	flags.IntVar(&gco.workerCount, "worker-count", 0, "Number of worker nodes. Defaults to the provider default")
+	flags.BoolVar(&gco.externalEtcd, "external-etcd", true, "Run etcd in its own nodes instead of in the control plane nodes")
+	flags.IntVar(&gco.etcdCount, "etcd-count", 0, "Number of external etcd nodes. Defaults to the provider default")
+	flags.StringVar(&gco.controlPlaneEndpoint, "control-plane-endpoint", "", "IP of the control plane endpoint (vsphere)")
+	addMachineSizeFlags(flags, "control-plane", &gco.controlPlaneMachine)
+	addMachineSizeFlags(flags, "worker", &gco.workerMachine)
+	addMachineSizeFlags(flags, "etcd", &gco.etcdMachine)
+	flags.StringVar(&gco.vsphere.Server, "vsphere-server", os.Getenv(vSphereServerEnv), "vCenter server. Defaults to the VSPHERE_SERVER environment variable")
+	flags.StringVar(&gco.vsphere.Thumbprint, "vsphere-thumbprint", "", "Thumbprint of the vCenter server certificate")
+	flags.BoolVar(&gco.vsphere.Insecure, "vsphere-insecure", false, "Skip the vCenter server certificate verification")
+	flags.StringVar(&gco.vsphere.Datacenter, "vsphere-datacenter", "", "vSphere datacenter")
+	flags.StringVar(&gco.vsphere.Network, "vsphere-network", "", "vSphere network path")
+	flags.StringVar(&gco.vsphere.Datastore, "vsphere-datastore", "", "vSphere datastore path")
+	flags.StringVar(&gco.vsphere.ResourcePool, "vsphere-resource-pool", "", "vSphere resource pool path")
+	flags.StringVar(&gco.vsphere.Folder, "vsphere-folder", "", "vSphere folder path for the VMs")
+	flags.StringVar(&gco.vsphere.Template, "vsphere-template", "", "vSphere template path for the VMs")
+	flags.StringVar(&gco.osFamily, "os-family", string(v1alpha1.Bottlerocket), "OS family of the nodes (bottlerocket or ubuntu)")
+	flags.StringVar(&gco.vsphere.SSHAuthorizedKey, "ssh-authorized-key", "", "SSH public key for the nodes")
+	flags.BoolVar(&gco.vsphereAutofill, "vsphere-autofill", false, "Fill the vSphere datacenter, network, datastore, resource pool, folder and template by querying vCenter")
+	flags.StringVar(&gco.oidc.IssuerUrl, "oidc-issuer-url", "", "OIDC issuer URL. Adds an OIDCConfig to the cluster")
+	flags.StringVar(&gco.oidc.ClientId, "oidc-client-id", "", "OIDC client ID")
+	flags.StringVar(&gco.oidc.UsernameClaim, "oidc-username-claim", "", "OIDC claim to use as the username")
+	flags.StringVar(&gco.oidc.GroupsClaim, "oidc-groups-claim", "", "OIDC claim to use as the user groups")
+	flags.StringVar(&gco.awsIamRegion, "aws-iam-region", "", "AWS region of the IAM authenticator. Adds an AWSIamConfig to the cluster")
+	flags.StringSliceVar(&gco.awsIamBackendModes, "aws-iam-backend-mode", []string{"EKSConfigMap"}, "Backend modes of the AWS IAM authenticator")
+	flags.StringVar(&gco.gitOps.Owner, "gitops-owner", "", "GitHub owner of the GitOps repository. Adds a GitOpsConfig to the cluster")
+	flags.StringVar(&gco.gitOps.Repository, "gitops-repository", "", "GitHub repository for GitOps")
+	flags.StringVar(&gco.gitOps.Branch, "gitops-branch", "", "Branch of the GitOps repository")
+	flags.StringVar(&gco.gitOps.ClusterConfigPath, "gitops-cluster-config-path", "", "Path in the GitOps repository for the cluster config")
+	flags.BoolVar(&gco.gitOps.Personal, "gitops-personal", false, "The GitOps repository owner is a user instead of an organization")
+	flags.StringVar(&gco.httpProxy, "http-proxy", "", "HTTP proxy. Adds a proxy configuration to the cluster")
+	flags.StringVar(&gco.httpsProxy, "https-proxy", "", "HTTPS proxy")
+	flags.StringSliceVar(&gco.noProxy, "no-proxy", nil, "Destinations excluded from the proxy")
+	flags.StringVar(&gco.mirrorEndpoint, "registry-mirror-endpoint", "", "Registry mirror endpoint. Adds a registry mirror configuration to the cluster")
+	flags.StringVar(&gco.mirrorCACert, "registry-mirror-ca-cert", "", "Path to the CA certificate of the registry mirror")
 	err := generateClusterConfigCmd.MarkFlagRequired("provider")
 	if err != nil {
 		log.Fatalf("Error marking flag as required: %v", err)
 	}
 }
 
-func generateClusterConfig(clusterName string) error {
-	var resources [][]byte
-	var datacenterYaml []byte
-	var machineGroupYaml [][]byte
-	var clusterConfigOpts []v1alpha1.ClusterGenerateOpt
-	switch strings.ToLower(viper.GetString("provider")) {
-	case constants.DockerProviderName:
-		datacenterConfig := v1alpha1.NewDockerDatacenterConfigGenerate(clusterName)
-		clusterConfigOpts = append(clusterConfigOpts, v1alpha1.WithDatacenterRef(datacenterConfig))
-		clusterConfigOpts = append(clusterConfigOpts,
-			v1alpha1.ControlPlaneConfigCount(1),
-			v1alpha1.ExternalETCDConfigCount(1),
-			v1alpha1.WorkerNodeConfigCount(1),
-		)
-		dcyaml, err := yaml.Marshal(datacenterConfig)
-		if err != nil {
-			return fmt.Errorf("error outputting yaml: %v", err)
+func addMachineSizeFlags(flags *pflag.FlagSet, role string, size *configgenerator.MachineSize) {
+	flags.IntVar(&size.NumCPUs, role+"-cpus", 0, fmt.Sprintf("Number of CPUs of the %s nodes (vsphere)", role))
+	flags.IntVar(&size.MemoryMiB, role+"-memory-mib", 0, fmt.Sprintf("Memory in MiB of the %s nodes (vsphere)", role))
+	flags.IntVar(&size.DiskGiB, role+"-disk-gib", 0, fmt.Sprintf("Disk size in GiB of the %s nodes (vsphere)", role))
+}
+
+func (o *generateClusterConfigOptions) generateClusterConfig(ctx context.Context, flags *pflag.FlagSet, clusterName string) error {
+	opts, err := configgenerator.NewOptions(clusterName, o.provider)
+	if err != nil {
+		return err
+	}
+	if err = o.apply(opts); err != nil {
+		return err
+	}
+
+	var choose configgenerator.Chooser
+	if o.interactive {
+		p := &flagPrompter{prompter: newPrompter(os.Stdin, os.Stderr), flags: flags}
+		if err = o.prompt(p, opts); err != nil {
+			return err
 		}
-		datacenterYaml = dcyaml
-	case constants.VSphereProviderName:
-		clusterConfigOpts = append(clusterConfigOpts, v1alpha1.WithClusterEndpoint())
-		datacenterConfig := v1alpha1.NewVSphereDatacenterConfigGenerate(clusterName)
-		clusterConfigOpts = append(clusterConfigOpts, v1alpha1.WithDatacenterRef(datacenterConfig))
-		clusterConfigOpts = append(clusterConfigOpts,
-			v1alpha1.ControlPlaneConfigCount(2),
-			v1alpha1.ExternalETCDConfigCount(3),
-			v1alpha1.WorkerNodeConfigCount(2),
-		)
-		dcyaml, err := yaml.Marshal(datacenterConfig)
-		if err != nil {
-			return fmt.Errorf("error outputting yaml: %v", err)
+		choose = p.choose
+	}
+
+	if o.vsphereAutofill && opts.Provider == constants.VSphereProviderName {
+		if err = autofillVSphere(ctx, opts, choose); err != nil {
+			return err
 		}
-		datacenterYaml = dcyaml
-		// need to default control plane config name to something different from the cluster name based on assumption
-		// in controller code
-		cpMachineConfig := v1alpha1.NewVSphereMachineConfigGenerate(clusterName + "-cp")
-		workerMachineConfig := v1alpha1.NewVSphereMachineConfigGenerate(clusterName)
-		etcdMachineConfig := v1alpha1.NewVSphereMachineConfigGenerate(fmt.Sprintf("%s-etcd", clusterName))
-		clusterConfigOpts = append(clusterConfigOpts,
-			v1alpha1.WithCPMachineGroupRef(cpMachineConfig),
-			v1alpha1.WithWorkerMachineGroupRef(workerMachineConfig),
-			v1alpha1.WithEtcdMachineGroupRef(etcdMachineConfig),
-		)
-		cpMcYaml, err := yaml.Marshal(cpMachineConfig)
-		if err != nil {
-			return fmt.Errorf("error outputting yaml: %v", err)
+	}
+
+	content, err := configgenerator.Generate(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(content))
+	return nil
+}
+
+// apply overrides the provider defaults in opts with the flags set
+func (o *generateClusterConfigOptions) apply(opts *configgenerator.Options) error {
+	opts.KubernetesVersion = v1alpha1.KubernetesVersion(o.kubernetesVersion)
+	opts.ExternalEtcd = o.externalEtcd
+	opts.ControlPlaneEndpoint = o.controlPlaneEndpoint
+	setIfPositive(&opts.ControlPlaneCount, o.controlPlaneCount)
+	setIfPositive(&opts.WorkerCount, o.workerCount)
+	setIfPositive(&opts.EtcdCount, o.etcdCount)
+	setMachineSize(&opts.ControlPlaneMachine, o.controlPlaneMachine)
+	setMachineSize(&opts.WorkerMachine, o.workerMachine)
+	setMachineSize(&opts.EtcdMachine, o.etcdMachine)
+
+	sshKey := opts.VSphere.SSHAuthorizedKey
+	opts.VSphere = o.vsphere
+	opts.VSphere.OSFamily = v1alpha1.OSFamily(o.osFamily)
+	if opts.VSphere.SSHAuthorizedKey == "" {
+		opts.VSphere.SSHAuthorizedKey = sshKey
+	}
+
+	if o.oidc.IssuerUrl != "" || o.oidc.ClientId != "" {
+		oidc := o.oidc
+		opts.OIDC = &oidc
+	}
+	if o.awsIamRegion != "" {
+		opts.AWSIam = &v1alpha1.AWSIamConfigSpec{
+			AWSRegion:   o.awsIamRegion,
+			BackendMode: o.awsIamBackendModes,
+			Partition:   "aws",
 		}
-		workerMcYaml, err := yaml.Marshal(workerMachineConfig)
-		if err != nil {
-			return fmt.Errorf("error outputting yaml: %v", err)
+	}
+	if o.gitOps.Owner != "" || o.gitOps.Repository != "" {
+		opts.GitOps = &v1alpha1.GitOpsConfigSpec{Flux: v1alpha1.Flux{Github: o.gitOps}}
+	}
+	if o.httpProxy != "" || o.httpsProxy != "" {
+		opts.Proxy = &v1alpha1.ProxyConfiguration{
+			HttpProxy:  o.httpProxy,
+			HttpsProxy: o.httpsProxy,
+			NoProxy:    o.noProxy,
 		}
-		etcdMcYaml, err := yaml.Marshal(etcdMachineConfig)
-		if err != nil {
-			return fmt.Errorf("error outputting yaml: %v", err)
+	}
+	if o.mirrorEndpoint != "" {
+		return setRegistryMirror(opts, o.mirrorEndpoint, o.mirrorCACert)
+	}
+	return nil
+}
+
+func setIfPositive(field *int, value int) {
+	if value > 0 {
+		*field = value
+	}
+}
+
+func setMachineSize(size *configgenerator.MachineSize, flags configgenerator.MachineSize) {
+	setIfPositive(&size.NumCPUs, flags.NumCPUs)
+	setIfPositive(&size.MemoryMiB, flags.MemoryMiB)
+	setIfPositive(&size.DiskGiB, flags.DiskGiB)
+}
+
+func setRegistryMirror(opts *configgenerator.Options, endpoint, caCertFile string) error {
+	opts.RegistryMirror = &v1alpha1.RegistryMirrorConfiguration{Endpoint: endpoint}
+	if caCertFile == "" {
+		return nil
+	}
+	caCert, err := ioutil.ReadFile(caCertFile)
+	if err != nil {
+		return fmt.Errorf("error reading the registry mirror CA cert %s: %v", caCertFile, err)
+	}
+	opts.RegistryMirror.CACertContent = string(caCert)
+	return nil
+}
+
+// autofillVSphere queries vCenter with govc from the tools image of the Kubernetes version
+func autofillVSphere(ctx context.Context, opts *configgenerator.Options, choose configgenerator.Chooser) error {
+	bundles, err := getBundles(0, "")
+	if err != nil {
+		return err
+	}
+	var toolsImage string
+	for _, vb := range bundles.Spec.VersionsBundles {
+		if vb.KubeVersion == string(opts.KubernetesVersion) {
+			toolsImage = vb.Eksa.CliTools.VersionedImage()
 		}
-		machineGroupYaml = append(machineGroupYaml, cpMcYaml, workerMcYaml, etcdMcYaml)
-	default:
-		return fmt.Errorf("not a valid provider")
 	}
-	config := v1alpha1.NewClusterGenerate(clusterName, clusterConfigOpts...)
+	if toolsImage == "" {
+		return fmt.Errorf("kubernetes version %s is not supported by bundle %d", opts.KubernetesVersion, bundles.Spec.Number)
+	}
 
-	clusterYaml, err := yaml.Marshal(config)
+	// govc doesn't write any file needed after generating the config
+	writerFolder, err := os.MkdirTemp(".", fmt.Sprintf(".%s-generate-", opts.ClusterName))
 	if err != nil {
-		return fmt.Errorf("error outputting yaml: %v", err)
+		return fmt.Errorf("error creating temporary folder: %v", err)
 	}
-	resources = append(resources, clusterYaml, datacenterYaml)
-	if len(machineGroupYaml) > 0 {
-		resources = append(resources, machineGroupYaml...)
+	defer os.RemoveAll(writerFolder)
+
+	deps, err := dependencies.NewFactory().
+		WithExecutableBuilder(ctx, toolsImage).
+		WithWriterFolder(writerFolder).
+		WithGovc().
+		Build()
+	if err != nil {
+		return err
 	}
-	fmt.Println(string(templater.AppendYamlResources(resources...)))
-	return nil
+
+	if _, ok := os.LookupEnv(vSphereServerEnv); !ok && opts.VSphere.Server != "" {
+		if err = os.Setenv(vSphereServerEnv, opts.VSphere.Server); err != nil {
+			return fmt.Errorf("unable to set %s: %v", vSphereServerEnv, err)
+		}
+	}
+	return configgenerator.AutofillVSphere(ctx, deps.Govc, &opts.VSphere, choose)
 }
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/configgenerator"
+	"github.com/aws/eks-anywhere/pkg/constants"
+)
+
+// prompter asks questions in out and reads the answers from in, one per line
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask returns the answer to question, or def if the answer is empty
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("error reading answer to %q: %v", question, err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+func (p *prompter) askInt(question string, def int) (int, error) {
+	for {
+		answer, err := p.ask(question, strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		if value, err := strconv.Atoi(answer); err == nil && value > 0 {
+			return value, nil
+		}
+		fmt.Fprintln(p.out, "Enter a positive number")
+	}
+}
+
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	options := "y/N"
+	if def {
+		options = "Y/n"
+	}
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s (%s)", question, options), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Enter y or n")
+	}
+}
+
+// choose asks to pick one of options by number. It implements configgenerator.Chooser
+func (p *prompter) choose(field string, options []string) (string, error) {
+	fmt.Fprintf(p.out, "Found more than one vSphere %s:\n", field)
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+	for {
+		answer, err := p.ask(fmt.Sprintf("Choose the %s", field), "1")
+		if err != nil {
+			return "", err
+		}
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(options) {
+			return options[i-1], nil
+		}
+		fmt.Fprintf(p.out, "Enter a number between 1 and %d\n", len(options))
+	}
+}
+
+// flagPrompter only asks for the values of the flags that weren't set
+type flagPrompter struct {
+	*prompter
+	flags *pflag.FlagSet
+}
+
+func (p *flagPrompter) askFlag(flag, question string, field *string) error {
+	if p.flags.Changed(flag) {
+		return nil
+	}
+	answer, err := p.ask(question, *field)
+	if err != nil {
+		return err
+	}
+	*field = answer
+	return nil
+}
+
+func (p *flagPrompter) askIntFlag(flag, question string, field *int) error {
+	if p.flags.Changed(flag) {
+		return nil
+	}
+	answer, err := p.askInt(question, *field)
+	if err != nil {
+		return err
+	}
+	*field = answer
+	return nil
+}
+
+func (p *flagPrompter) confirmFlag(flag, question string, field *bool) error {
+	if p.flags.Changed(flag) {
+		return nil
+	}
+	answer, err := p.confirm(question, *field)
+	if err != nil {
+		return err
+	}
+	*field = answer
+	return nil
+}
+
+// prompt asks for the values in opts that weren't set with flags
+func (o *generateClusterConfigOptions) prompt(p *flagPrompter, opts *configgenerator.Options) error {
+	kubernetesVersion := string(opts.KubernetesVersion)
+	if err := p.askFlag("kubernetes-version", "Kubernetes version", &kubernetesVersion); err != nil {
+		return err
+	}
+	opts.KubernetesVersion = v1alpha1.KubernetesVersion(kubernetesVersion)
+	if err := p.askIntFlag("control-plane-count", "Number of control plane nodes", &opts.ControlPlaneCount); err != nil {
+		return err
+	}
+	if err := p.askIntFlag("worker-count", "Number of worker nodes", &opts.WorkerCount); err != nil {
+		return err
+	}
+	if err := p.confirmFlag("external-etcd", "Run etcd in its own nodes?", &opts.ExternalEtcd); err != nil {
+		return err
+	}
+	if opts.ExternalEtcd {
+		if err := p.askIntFlag("etcd-count", "Number of etcd nodes", &opts.EtcdCount); err != nil {
+			return err
+		}
+	}
+
+	if opts.Provider == constants.VSphereProviderName {
+		if err := o.promptVSphere(p, opts); err != nil {
+			return err
+		}
+	}
+
+	return o.promptFeatures(p, opts)
+}
+
+func (o *generateClusterConfigOptions) promptVSphere(p *flagPrompter, opts *configgenerator.Options) error {
+	if err := p.askFlag("control-plane-endpoint", "Control plane endpoint IP", &opts.ControlPlaneEndpoint); err != nil {
+		return err
+	}
+	osFamily := string(opts.VSphere.OSFamily)
+	if err := p.askFlag("os-family", "OS family (bottlerocket or ubuntu)", &osFamily); err != nil {
+		return err
+	}
+	opts.VSphere.OSFamily = v1alpha1.OSFamily(osFamily)
+	if err := p.askFlag("ssh-authorized-key", "SSH public key for the nodes", &opts.VSphere.SSHAuthorizedKey); err != nil {
+		return err
+	}
+
+	type machine struct {
+		role string
+		size *configgenerator.MachineSize
+	}
+	machines := []machine{
+		{role: "control-plane", size: &opts.ControlPlaneMachine},
+		{role: "worker", size: &opts.WorkerMachine},
+	}
+	if opts.ExternalEtcd {
+		machines = append(machines, machine{role: "etcd", size: &opts.EtcdMachine})
+	}
+	for _, m := range machines {
+		name := strings.ReplaceAll(m.role, "-", " ")
+		if err := p.askIntFlag(m.role+"-cpus", fmt.Sprintf("Number of CPUs of the %s nodes", name), &m.size.NumCPUs); err != nil {
+			return err
+		}
+		if err := p.askIntFlag(m.role+"-memory-mib", fmt.Sprintf("Memory in MiB of the %s nodes", name), &m.size.MemoryMiB); err != nil {
+			return err
+		}
+		if err := p.askIntFlag(m.role+"-disk-gib", fmt.Sprintf("Disk size in GiB of the %s nodes", name), &m.size.DiskGiB); err != nil {
+			return err
+		}
+	}
+
+	if err := p.askFlag("vsphere-server", "vCenter server", &opts.VSphere.Server); err != nil {
+		return err
+	}
+	if err := p.askFlag("vsphere-thumbprint", "vCenter server certificate thumbprint", &opts.VSphere.Thumbprint); err != nil {
+		return err
+	}
+	if err := p.confirmFlag("vsphere-autofill", "Query vCenter for the datacenter, network, datastore, resource pool, folder and template?", &o.vsphereAutofill); err != nil {
+		return err
+	}
+	if o.vsphereAutofill {
+		return nil
+	}
+
+	fields := []struct {
+		flag, question string
+		field          *string
+	}{
+		{flag: "vsphere-datacenter", question: "vSphere datacenter", field: &opts.VSphere.Datacenter},
+		{flag: "vsphere-network", question: "vSphere network path", field: &opts.VSphere.Network},
+		{flag: "vsphere-datastore", question: "vSphere datastore path", field: &opts.VSphere.Datastore},
+		{flag: "vsphere-resource-pool", question: "vSphere resource pool path", field: &opts.VSphere.ResourcePool},
+		{flag: "vsphere-folder", question: "vSphere folder path", field: &opts.VSphere.Folder},
+		{flag: "vsphere-template", question: "vSphere template path", field: &opts.VSphere.Template},
+	}
+	for _, f := range fields {
+		if err := p.askFlag(f.flag, f.question, f.field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *generateClusterConfigOptions) promptFeatures(p *flagPrompter, opts *configgenerator.Options) error {
+	enable := func(flag, question string, enabled bool) (bool, error) {
+		if p.flags.Changed(flag) {
+			return enabled, nil
+		}
+		return p.confirm(question, enabled)
+	}
+
+	enabled, err := enable("oidc-issuer-url", "Configure OIDC?", opts.OIDC != nil)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		if opts.OIDC == nil {
+			opts.OIDC = &v1alpha1.OIDCConfigSpec{}
+		}
+		if err = p.askFlag("oidc-issuer-url", "OIDC issuer URL", &opts.OIDC.IssuerUrl); err != nil {
+			return err
+		}
+		if err = p.askFlag("oidc-client-id", "OIDC client ID", &opts.OIDC.ClientId); err != nil {
+			return err
+		}
+	}
+
+	if enabled, err = enable("aws-iam-region", "Configure AWS IAM authenticator?", opts.AWSIam != nil); err != nil {
+		return err
+	}
+	if enabled {
+		if opts.AWSIam == nil {
+			opts.AWSIam = &v1alpha1.AWSIamConfigSpec{BackendMode: o.awsIamBackendModes, Partition: "aws"}
+		}
+		if err = p.askFlag("aws-iam-region", "AWS region", &opts.AWSIam.AWSRegion); err != nil {
+			return err
+		}
+	}
+
+	if enabled, err = enable("gitops-repository", "Configure GitOps?", opts.GitOps != nil); err != nil {
+		return err
+	}
+	if enabled {
+		if opts.GitOps == nil {
+			opts.GitOps = &v1alpha1.GitOpsConfigSpec{Flux: v1alpha1.Flux{Github: o.gitOps}}
+		}
+		github := &opts.GitOps.Flux.Github
+		if err = p.askFlag("gitops-owner", "GitHub owner", &github.Owner); err != nil {
+			return err
+		}
+		if err = p.askFlag("gitops-repository", "GitHub repository", &github.Repository); err != nil {
+			return err
+		}
+		if !p.flags.Changed("gitops-personal") {
+			if github.Personal, err = p.confirm("Is the owner a user instead of an organization?", github.Personal); err != nil {
+				return err
+			}
+		}
+	}
+
+	if enabled, err = enable("http-proxy", "Configure a proxy?", opts.Proxy != nil); err != nil {
+		return err
+	}
+	if enabled {
+		if opts.Proxy == nil {
+			opts.Proxy = &v1alpha1.ProxyConfiguration{NoProxy: o.noProxy}
+		}
+		if err = p.askFlag("http-proxy", "HTTP proxy", &opts.Proxy.HttpProxy); err != nil {
+			return err
+		}
+		if err = p.askFlag("https-proxy", "HTTPS proxy", &opts.Proxy.HttpsProxy); err != nil {
+			return err
+		}
+		noProxy := strings.Join(opts.Proxy.NoProxy, ",")
+		if err = p.askFlag("no-proxy", "Destinations excluded from the proxy, comma separated", &noProxy); err != nil {
+			return err
+		}
+		opts.Proxy.NoProxy = splitList(noProxy)
+	}
+
+	if enabled, err = enable("registry-mirror-endpoint", "Configure a registry mirror?", opts.RegistryMirror != nil); err != nil {
+		return err
+	}
+	if enabled && !p.flags.Changed("registry-mirror-endpoint") {
+		endpoint, caCert := o.mirrorEndpoint, o.mirrorCACert
+		if err = p.askFlag("registry-mirror-endpoint", "Registry mirror endpoint", &endpoint); err != nil {
+			return err
+		}
+		if err = p.askFlag("registry-mirror-ca-cert", "Path to the registry mirror CA certificate, if any", &caCert); err != nil {
+			return err
+		}
+		return setRegistryMirror(opts, endpoint, caCert)
+	}
+	return nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
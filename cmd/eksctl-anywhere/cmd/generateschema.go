@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/configschema"
+)
+
+const (
+	jsonSchemaFormat = "jsonschema"
+	openAPIFormat    = "openapi"
+)
+
+type generateSchemaOptions struct {
+	kinds  []string
+	format string
+}
+
+var generateSchemaOpts = &generateSchemaOptions{}
+
+var generateSchemaCmd = &cobra.Command{
+	Use:          "schema",
+	Short:        "Generate the schema of the cluster config kinds",
+	Long:         "This command is used to print the JSON Schema or OpenAPI schema of the EKS Anywhere cluster config kinds, for editors and linters",
+	PreRunE:      preRunGenerateSchema,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateSchema(os.Stdout, generateSchemaOpts)
+	},
+}
+
+func init() {
+	generateCmd.AddCommand(generateSchemaCmd)
+	generateSchemaCmd.Flags().StringSliceVar(&generateSchemaOpts.kinds, "kind", nil, fmt.Sprintf("Kinds to include, all of them by default: %v", configschema.Kinds))
+	generateSchemaCmd.Flags().StringVar(&generateSchemaOpts.format, "format", jsonSchemaFormat, "Schema format: jsonschema or openapi")
+}
+
+func preRunGenerateSchema(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func generateSchema(w io.Writer, opts *generateSchemaOptions) error {
+	schemas, err := configschema.Load()
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	switch opts.format {
+	case jsonSchemaFormat:
+		content, err = schemas.JSONSchema(opts.kinds...)
+	case openAPIFormat:
+		content, err = openAPISchemas(schemas, opts.kinds)
+	default:
+		return fmt.Errorf("unsupported format %s, supported formats: %s, %s", opts.format, jsonSchemaFormat, openAPIFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(content))
+	return err
+}
+
+// openAPISchemas returns the OpenAPI schemas of kinds, keyed by kind
+func openAPISchemas(schemas *configschema.Schemas, kinds []string) ([]byte, error) {
+	if len(kinds) == 0 {
+		kinds = configschema.Kinds
+	}
+	byKind := map[string]*configschema.Schema{}
+	for _, kind := range kinds {
+		schema := schemas.OpenAPI(kind)
+		if schema == nil {
+			return nil, fmt.Errorf("unknown kind %s, supported kinds: %v", kind, configschema.Kinds)
+		}
+		byKind[kind] = schema
+	}
+	return json.MarshalIndent(byKind, "", "  ")
+}
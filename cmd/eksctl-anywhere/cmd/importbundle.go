@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/airgap"
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+type importBundleOptions struct {
+	bundle         string
+	outputDir      string
+	registry       string
+	registryCACert string
+	fileServerURL  string
+	concurrency    int
+}
+
+var importBundleOpts = &importBundleOptions{}
+
+func init() {
+	importCmd.AddCommand(importBundleCmd)
+	importBundleCmd.Flags().StringVarP(&importBundleOpts.bundle, "bundle", "b", "", "Bundle archive created with download bundle")
+	importBundleCmd.Flags().StringVarP(&importBundleOpts.outputDir, "output-dir", "o", "eks-anywhere-bundle", "Directory to extract the bundle to, to be served by the file server")
+	importBundleCmd.Flags().StringVar(&importBundleOpts.registry, "registry", "", "Registry endpoint to push the bundle images to")
+	importBundleCmd.Flags().StringVar(&importBundleOpts.registryCACert, "registry-ca-cert", "", "CA certificate file for the registry")
+	importBundleCmd.Flags().StringVar(&importBundleOpts.fileServerURL, "file-server-url", "", "URL the output directory is served at. If not set, manifests and OVAs are referenced by their local path")
+	importBundleCmd.Flags().IntVar(&importBundleOpts.concurrency, "concurrency", 4, "Number of images to push in parallel")
+	for _, flag := range []string{"bundle", "registry"} {
+		if err := importBundleCmd.MarkFlagRequired(flag); err != nil {
+			log.Fatalf("Error marking %s flag as required: %v", flag, err)
+		}
+	}
+}
+
+var importBundleCmd = &cobra.Command{
+	Use:          "bundle",
+	Short:        "Import an air-gap bundle into a local registry and file server",
+	Long:         "This command is used to push the images of an air-gap bundle to a local registry and extract its manifests and OVAs so they can be served locally. Credentials for the registry are read from the EKSA_REGISTRY_USERNAME and EKSA_REGISTRY_PASSWORD env vars",
+	PreRunE:      preRunImportBundleCmd,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importBundle(cmd.Context(), importBundleOpts)
+	},
+}
+
+func importBundle(ctx context.Context, opts *importBundleOptions) error {
+	var caCert []byte
+	if opts.registryCACert != "" {
+		var err error
+		if caCert, err = ioutil.ReadFile(opts.registryCACert); err != nil {
+			return fmt.Errorf("error reading registry CA certificate: %v", err)
+		}
+	}
+
+	client, err := newRegistryClient(opts.registry, caCert)
+	if err != nil {
+		return err
+	}
+
+	result, err := airgap.NewImporter(client, opts.concurrency).Import(ctx, airgap.ImportConfig{
+		ArchivePath:      opts.bundle,
+		Dir:              opts.outputDir,
+		RegistryEndpoint: opts.registry,
+		FileServerURL:    opts.fileServerURL,
+	})
+	if err != nil {
+		return fmt.Errorf("error importing bundle: %v", err)
+	}
+
+	logger.MarkSuccess("Bundle imported")
+	logger.Info("Create clusters with the imported bundles manifest", "bundles-override", result.BundlesManifest)
+	return nil
+}
+
+func preRunImportBundleCmd(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err := viper.BindPFlag(flag.Name, flag); err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
@@ -4,18 +4,27 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
-	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registry"
 	"github.com/aws/eks-anywhere/pkg/version"
 )
 
+const (
+	registryUsernameKey = "EKSA_REGISTRY_USERNAME"
+	registryPasswordKey = "EKSA_REGISTRY_PASSWORD"
+)
+
 type importImagesOptions struct {
-	fileName string
+	fileName    string
+	concurrency int
 }
 
 var opts = &importImagesOptions{}
@@ -23,6 +32,7 @@ var opts = &importImagesOptions{}
 func init() {
 	rootCmd.AddCommand(importImagesCmd)
 	importImagesCmd.Flags().StringVarP(&opts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	importImagesCmd.Flags().IntVar(&opts.concurrency, "concurrency", 4, "Number of images to copy in parallel")
 	err := importImagesCmd.MarkFlagRequired("filename")
 	if err != nil {
 		log.Fatalf("Error marking filename flag as required: %v", err)
@@ -32,7 +42,7 @@ func init() {
 var importImagesCmd = &cobra.Command{
 	Use:          "import-images",
 	Short:        "Push EKS Anywhere images to a private registry",
-	Long:         "This command is used to import images from an EKS Anywhere release bundle into a private registry",
+	Long:         "This command is used to import images from an EKS Anywhere release bundle into a private registry. Credentials for the registry are read from the EKSA_REGISTRY_USERNAME and EKSA_REGISTRY_PASSWORD env vars",
 	PreRunE:      preRunImportImagesCmd,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -43,40 +53,81 @@ var importImagesCmd = &cobra.Command{
 	},
 }
 
-func importImages(context context.Context, spec string) error {
+func importImages(ctx context.Context, spec string) error {
 	clusterSpec, err := cluster.NewSpecFromClusterConfig(spec, version.Get())
 	if err != nil {
 		return err
 	}
-	de := executables.BuildDockerExecutable()
 
 	if clusterSpec.Spec.RegistryMirrorConfiguration == nil || clusterSpec.Spec.RegistryMirrorConfiguration.Endpoint == "" {
 		return fmt.Errorf("it is necessary to define a valid endpoint in your spec (registryMirrorConfiguration.endpoint)")
 	}
 	endpoint := clusterSpec.Spec.RegistryMirrorConfiguration.Endpoint
 
+	client, err := newRegistryClient(endpoint, []byte(clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent))
+	if err != nil {
+		return err
+	}
+
 	images, err := getImages(spec)
 	if err != nil {
 		return err
 	}
+	uris := make([]string, 0, len(images))
 	for _, image := range images {
-		if err := importImage(context, de, image.URI, endpoint); err != nil {
-			return fmt.Errorf("error importing image %s: %v", image.URI, err)
-		}
+		uris = append(uris, image.URI)
+	}
+
+	mirrorOpts := []registry.MirrorOpt{registry.WithConcurrency(opts.concurrency)}
+	verifier, err := newSignatureVerifier(clusterSpec)
+	if err != nil {
+		return err
 	}
+	if verifier != nil {
+		mirrorOpts = append(mirrorOpts, registry.WithSignatureVerifier(verifier))
+	}
+
+	report, err := registry.NewMirror(client, endpoint, mirrorOpts...).MirrorImages(ctx, uris)
+	if err != nil {
+		return fmt.Errorf("error importing images: %v", err)
+	}
+	logger.Info("Images imported", "copied", len(report.Copied), "skipped", len(report.Skipped))
 	return nil
 }
 
-func importImage(ctx context.Context, docker *executables.Docker, image string, endpoint string) error {
-	if err := docker.PullImage(ctx, image); err != nil {
-		return err
+// newRegistryClient builds a registry client that trusts caCert and authenticates with the
+// registry endpoint using the credentials from the env, if set
+func newRegistryClient(endpoint string, caCert []byte) (*registry.Client, error) {
+	clientOpts := []registry.ClientOpt{registry.WithCACert(caCert)}
+	if username, ok := os.LookupEnv(registryUsernameKey); ok {
+		clientOpts = append(clientOpts, registry.WithCredentials(registryHost(endpoint), registry.Credentials{
+			Username: username,
+			Password: os.Getenv(registryPasswordKey),
+		}))
 	}
+	return registry.NewClient(clientOpts...)
+}
 
-	if err := docker.TagImage(ctx, image, endpoint); err != nil {
-		return err
+// newSignatureVerifier builds a verifier with the cosign public keys from the cluster spec.
+// It returns nil if image verification is not configured.
+func newSignatureVerifier(clusterSpec *cluster.Spec) (*registry.SignatureVerifier, error) {
+	config := clusterSpec.Spec.ImageVerificationConfiguration
+	if config == nil {
+		return nil, nil
+	}
+	keys := make([][]byte, 0, len(config.CosignPublicKeys))
+	for _, key := range config.CosignPublicKeys {
+		keys = append(keys, []byte(key))
 	}
+	verifier, err := registry.NewSignatureVerifier(keys...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid imageVerificationConfiguration: %v", err)
+	}
+	return verifier, nil
+}
 
-	return docker.PushImage(ctx, image, endpoint)
+func registryHost(endpoint string) string {
+	return strings.SplitN(endpoint, "/", 2)[0]
 }
 
 func preRunImportImagesCmd(cmd *cobra.Command, args []string) error {
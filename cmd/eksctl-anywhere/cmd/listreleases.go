@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/version"
+)
+
+func init() {
+	listCmd.AddCommand(listReleasesCmd)
+}
+
+var listReleasesCmd = &cobra.Command{
+	Use:          "releases",
+	Short:        "List the available EKS Anywhere releases",
+	Long:         "This command is used to list the EKS Anywhere releases and their bundle numbers from the releases manifest. The release used by the current version of the CLI is marked with *",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listReleases()
+	},
+}
+
+func listReleases() error {
+	spec := cluster.NewSpec()
+	releases, err := spec.GetReleases()
+	if err != nil {
+		return err
+	}
+
+	current := 0
+	if release, err := spec.GetRelease(version.Get()); err == nil {
+		current = release.Number
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "\tVERSION\tBUNDLE\tDATE")
+	for _, release := range releases {
+		marker := ""
+		if release.Number == current {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", marker, release.Version, release.Number, release.Date)
+	}
+	return w.Flush()
+}
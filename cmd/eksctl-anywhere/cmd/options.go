@@ -1,20 +1,80 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/configtemplate"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
 	"github.com/aws/eks-anywhere/pkg/version"
 )
 
+const jsonOutput = "json"
+
 type clusterOptions struct {
 	fileName             string
+	valuesFiles          []string
 	bundlesOverride      string
 	managementKubeconfig string
 }
 
-func newClusterSpec(options clusterOptions) (*cluster.Spec, error) {
-	var specOpts []cluster.SpecOpt
+func (o *clusterOptions) addConfigFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	flags.StringArrayVar(&o.valuesFiles, "values", nil, "Values file merged into the cluster configuration, can be repeated and later files take precedence")
+}
+
+// renderConfig substitutes the environment variables in the cluster config and merges the values files into it.
+// The effective config is always written to the cluster folder, with the scalars using secret variables kept
+// as written. When secrets were redacted from it, the command reads a temporary copy of the rendered config
+// instead, which is removed by the returned func
+func (o *clusterOptions) renderConfig() (cleanup func(), err error) {
+	rendered, redacted, err := configtemplate.RenderFiles(o.fileName, o.valuesFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render cluster config: %v", err)
+	}
+
+	clusterName, err := configtemplate.ClusterName(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render cluster config: %v", err)
+	}
+	writer, err := filewriter.NewWriter(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	effectiveFile, err := writer.Write(fmt.Sprintf(effectiveConfigPattern, clusterName), redacted, filewriter.PersistentFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write effective cluster config: %v", err)
+	}
+	logger.V(2).Info("Wrote effective cluster config", "file", effectiveFile)
+
+	if bytes.Equal(rendered, redacted) {
+		o.fileName = effectiveFile
+		return func() {}, nil
+	}
+
+	renderedFile, err := writer.Write(fmt.Sprintf(renderedConfigPattern, clusterName), rendered, filewriter.Permission0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write rendered cluster config: %v", err)
+	}
+	logger.V(2).Info("Secrets redacted from the effective cluster config, using a temporary rendered config", "file", renderedFile)
+	o.fileName = renderedFile
+	return func() {
+		if err := os.Remove(renderedFile); err != nil {
+			logger.V(3).Info("Failed removing rendered cluster config", "file", renderedFile, "error", err)
+		}
+	}, nil
+}
+
+func newClusterSpec(options clusterOptions, opts ...cluster.SpecOpt) (*cluster.Spec, error) {
+	specOpts := opts
 	if options.bundlesOverride != "" {
 		specOpts = append(specOpts, cluster.WithOverrideBundlesManifest(options.bundlesOverride))
 	}
@@ -33,3 +93,43 @@ func newClusterSpec(options clusterOptions) (*cluster.Spec, error) {
 
 	return clusterSpec, nil
 }
+
+// specManagementCluster returns the cluster holding the CAPI objects of the cluster in clusterSpec.
+// Without a management cluster, the cluster manages itself and its kubeconfig is read from the cluster folder
+func specManagementCluster(clusterSpec *cluster.Spec) *types.Cluster {
+	if clusterSpec.ManagementCluster != nil {
+		return &types.Cluster{
+			Name:           clusterSpec.ManagementCluster.Name,
+			KubeconfigFile: clusterSpec.ManagementCluster.KubeconfigFile,
+		}
+	}
+	return &types.Cluster{
+		Name:           clusterSpec.Name,
+		KubeconfigFile: filepath.Join(clusterSpec.Name, fmt.Sprintf(kubeconfigPattern, clusterSpec.Name)),
+	}
+}
+
+type validationOptions struct {
+	skipValidations []string
+	output          string
+}
+
+func (v *validationOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(&v.skipValidations, "skip-validations", nil, "Comma separated list of non critical validations to skip")
+	flags.StringVar(&v.output, "output", "", "Format of the validations report. Supported values: json")
+}
+
+func (v *validationOptions) runnerOpts() ([]validations.RunnerOpt, error) {
+	var opts []validations.RunnerOpt
+	switch v.output {
+	case "":
+	case jsonOutput:
+		opts = append(opts, validations.WithJSONReport(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unsupported output %s, supported values: %s", v.output, jsonOutput)
+	}
+	if len(v.skipValidations) > 0 {
+		opts = append(opts, validations.WithSkippedValidations(v.skipValidations...))
+	}
+	return opts, nil
+}
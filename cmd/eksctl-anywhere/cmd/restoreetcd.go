@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+type restoreEtcdOptions struct {
+	clusterOptions
+	etcdStoreOptions
+	snapshot string
+	sshUser  string
+	sshKey   string
+}
+
+var restoreEtcdOpts = &restoreEtcdOptions{}
+
+var restoreEtcdCmd = &cobra.Command{
+	Use:          "etcd",
+	Short:        "Restore the etcd of a cluster from a snapshot",
+	Long:         "This command is used to restore all the etcd members of a cluster from a snapshot taken with eksctl anywhere backup etcd or the scheduled etcd backups. The members are restored over SSH, Bottlerocket nodes are not supported",
+	PreRunE:      preRunRestoreEtcd,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := restoreEtcdOpts.restoreEtcd(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to restore etcd: %v", err)
+		}
+		return nil
+	},
+}
+
+func preRunRestoreEtcd(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func init() {
+	restoreCmd.AddCommand(restoreEtcdCmd)
+	restoreEtcdCmd.Flags().StringVarP(&restoreEtcdOpts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	restoreEtcdCmd.Flags().StringVar(&restoreEtcdOpts.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	restoreEtcdCmd.Flags().StringVar(&restoreEtcdOpts.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	restoreEtcdCmd.Flags().StringVar(&restoreEtcdOpts.snapshot, "snapshot", "", "Name of the snapshot to restore. Defaults to the latest snapshot of the cluster")
+	restoreEtcdCmd.Flags().StringVar(&restoreEtcdOpts.sshUser, "ssh-user", "ec2-user", "User to SSH into the etcd nodes")
+	restoreEtcdCmd.Flags().StringVar(&restoreEtcdOpts.sshKey, "ssh-key", "", "Private key file to SSH into the etcd nodes. Defaults to the key generated on cluster creation")
+	restoreEtcdOpts.addFlags(restoreEtcdCmd.Flags())
+	err := restoreEtcdCmd.MarkFlagRequired("filename")
+	if err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+}
+
+func (o *restoreEtcdOptions) restoreEtcd(ctx context.Context) error {
+	clusterSpec, err := newClusterSpec(o.clusterOptions)
+	if err != nil {
+		return err
+	}
+	store, err := o.store()
+	if err != nil {
+		return err
+	}
+
+	sshKey := o.sshKey
+	if sshKey == "" {
+		sshKey = filepath.Join(clusterSpec.Name, "eks-a-id_rsa")
+	}
+	privateKey, err := ioutil.ReadFile(sshKey)
+	if err != nil {
+		return fmt.Errorf("error reading ssh private key: %v", err)
+	}
+	nodes, err := etcdbackup.NewSSHRunner(o.sshUser, privateKey)
+	if err != nil {
+		return err
+	}
+
+	snapshot := o.snapshot
+	if snapshot == "" {
+		if snapshot, err = etcdbackup.LatestSnapshot(ctx, store, clusterSpec.Name); err != nil {
+			return err
+		}
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).WithKubectl().WithDocker().Build()
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Restoring etcd snapshot", "cluster", clusterSpec.Name, "snapshot", snapshot)
+	restorer := etcdbackup.NewRestorer(deps.Kubectl, deps.DockerClient, nodes, store)
+	if err = restorer.Restore(ctx, specManagementCluster(clusterSpec), clusterSpec, snapshot); err != nil {
+		return err
+	}
+
+	logger.MarkSuccess("etcd restored!")
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/managementbackup"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+type restoreManagementOptions struct {
+	createClusterOptions
+	archive    string
+	kubeconfig string
+}
+
+var restoreManagementOpts = &restoreManagementOptions{}
+
+var restoreManagementCmd = &cobra.Command{
+	Use:          "management",
+	Short:        "Rebuild a management cluster from a backup",
+	Long:         "This command is used to create a fresh management cluster and restore in it the workload clusters of a backup taken with eksctl anywhere backup management. The workload clusters are adopted without recreating their machines",
+	PreRunE:      preRunRestoreManagement,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := restoreManagementOpts.restoreManagement(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to restore management cluster: %v", err)
+		}
+		return nil
+	},
+}
+
+func preRunRestoreManagement(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func init() {
+	restoreCmd.AddCommand(restoreManagementCmd)
+	restoreManagementCmd.Flags().StringVarP(&restoreManagementOpts.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration of the new management cluster")
+	restoreManagementCmd.Flags().StringVar(&restoreManagementOpts.archive, "archive", "", "Archive created with eksctl anywhere backup management")
+	restoreManagementCmd.Flags().StringVar(&restoreManagementOpts.kubeconfig, "kubeconfig", "", "Kubeconfig file of an already created management cluster to restore into. If not set, the management cluster is created first")
+	restoreManagementCmd.Flags().BoolVar(&restoreManagementOpts.forceClean, "force-cleanup", false, "Force deletion of previously created bootstrap cluster")
+	restoreManagementCmd.Flags().BoolVar(&restoreManagementOpts.skipIpCheck, "skip-ip-check", false, "Skip check for whether cluster control plane ip is in use")
+	restoreManagementCmd.Flags().StringVar(&restoreManagementOpts.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	for _, flag := range []string{"filename", "archive"} {
+		if err := restoreManagementCmd.MarkFlagRequired(flag); err != nil {
+			log.Fatalf("Error marking %s flag as required: %v", flag, err)
+		}
+	}
+}
+
+func (o *restoreManagementOptions) restoreManagement(ctx context.Context) error {
+	archive, err := managementbackup.ReadArchive(o.archive)
+	if err != nil {
+		return err
+	}
+
+	if o.kubeconfig == "" {
+		logger.Info("Creating management cluster")
+		if err = o.validate(ctx); err != nil {
+			return err
+		}
+		if err = o.createCluster(ctx); err != nil {
+			return fmt.Errorf("failed to create cluster: %v", err)
+		}
+	}
+
+	clusterSpec, err := newClusterSpec(o.clusterOptions)
+	if err != nil {
+		return err
+	}
+	if clusterSpec.ManagementCluster != nil {
+		return fmt.Errorf("cluster %s is managed by %s, the restored clusters can only be managed by a management cluster", clusterSpec.Name, clusterSpec.ManagementCluster.Name)
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).WithKubectl().Build()
+	if err != nil {
+		return err
+	}
+
+	managementCluster := specManagementCluster(clusterSpec)
+	if o.kubeconfig != "" {
+		managementCluster = &types.Cluster{Name: clusterSpec.Name, KubeconfigFile: o.kubeconfig}
+	}
+
+	logger.Info("Restoring workload clusters", "backup", archive.Metadata.ManagementCluster, "managementCluster", managementCluster.Name)
+	restored, err := managementbackup.NewRestorer(deps.Kubectl).Restore(ctx, managementCluster, archive)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range restored {
+		logger.Info("Workload cluster restored", "cluster", c)
+	}
+	logger.MarkSuccess("Management cluster restored!")
+	return nil
+}
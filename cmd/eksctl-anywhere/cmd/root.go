@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/aws/eks-anywhere/pkg/files"
 	"github.com/aws/eks-anywhere/pkg/logger"
 )
 
@@ -20,6 +21,7 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().IntP("verbosity", "v", 0, "Set the log level verbosity")
+	rootCmd.PersistentFlags().Bool("allow-missing-checksums", false, "Read remote manifests and artifacts that don't publish a checksum without verifying them. Checksums that are published are always verified")
 	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
 		log.Fatalf("failed to bind flags for root: %v", err)
 	}
@@ -29,6 +31,7 @@ func rootPersistentPreRun(cmd *cobra.Command, args []string) {
 	if err := initLogger(); err != nil {
 		log.Fatal(err)
 	}
+	files.AllowMissingChecksums(viper.GetBool("allow-missing-checksums"))
 }
 
 func initLogger() error {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -24,6 +25,10 @@ type createSupportBundleOptions struct {
 	since        string
 	sinceTime    string
 	bundleConfig string
+	redact       bool
+	allClusters  bool
+	kubeconfig   string
+	concurrency  int
 }
 
 func (csbo *createSupportBundleOptions) kubeConfig(clusterName string) string {
@@ -45,6 +50,12 @@ var supportbundleCmd = &cobra.Command{
 		if err := csbo.validate(cmd.Context()); err != nil {
 			return err
 		}
+		if csbo.allClusters {
+			if err := csbo.createFleetBundle(cmd.Context(), csbo.since, csbo.sinceTime); err != nil {
+				return fmt.Errorf("failed to create fleet support bundle: %v", err)
+			}
+			return nil
+		}
 		if err := csbo.createBundle(cmd.Context(), csbo.since, csbo.sinceTime, csbo.bundleConfig); err != nil {
 			return fmt.Errorf("failed to create support bundle: %v", err)
 		}
@@ -59,13 +70,19 @@ func init() {
 	supportbundleCmd.Flags().StringVarP(&csbo.bundleConfig, "bundle-config", "", "", "Bundle Config file to use when generating support bundle")
 	supportbundleCmd.Flags().StringVarP(&csbo.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
 	supportbundleCmd.Flags().StringVarP(&csbo.wConfig, "w-config", "w", "", "Kubeconfig file to use when creating support bundle for a workload cluster")
-	err := supportbundleCmd.MarkFlagRequired("filename")
-	if err != nil {
-		log.Fatalf("Error marking flag as required: %v", err)
-	}
+	supportbundleCmd.Flags().BoolVar(&csbo.redact, "redact", true, "Redact credentials and other sensitive values from the collected files")
+	supportbundleCmd.Flags().BoolVar(&csbo.allClusters, "all-clusters", false, "Collect a support bundle from every cluster managed by the management cluster in --kubeconfig")
+	supportbundleCmd.Flags().StringVar(&csbo.kubeconfig, "kubeconfig", "", "Management cluster kubeconfig file to use with --all-clusters")
+	supportbundleCmd.Flags().IntVar(&csbo.concurrency, "concurrency", diagnostics.DefaultFleetConcurrency, "Number of clusters to collect support bundles from at the same time with --all-clusters")
 }
 
 func (csbo *createSupportBundleOptions) validate(ctx context.Context) error {
+	if csbo.allClusters {
+		return csbo.validateFleet()
+	}
+	if csbo.fileName == "" {
+		return errors.New("required flag \"filename\" not set")
+	}
 	clusterConfig, err := commonValidation(ctx, csbo.fileName)
 	if err != nil {
 		return err
@@ -76,6 +93,22 @@ func (csbo *createSupportBundleOptions) validate(ctx context.Context) error {
 	return nil
 }
 
+func (csbo *createSupportBundleOptions) validateFleet() error {
+	if csbo.kubeconfig == "" {
+		return errors.New("--kubeconfig is required with --all-clusters")
+	}
+	if !validations.FileExists(csbo.kubeconfig) {
+		return fmt.Errorf("kubeconfig file %s doesn't exist", csbo.kubeconfig)
+	}
+	if csbo.fileName != "" || csbo.wConfig != "" || csbo.bundleConfig != "" {
+		return errors.New("--all-clusters can't be used with --filename, --w-config or --bundle-config")
+	}
+	if csbo.concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+	return nil
+}
+
 func preRunSupportBundle(cmd *cobra.Command, args []string) error {
 	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 		err := viper.BindPFlag(flag.Name, flag)
@@ -104,6 +137,7 @@ func (csbo *createSupportBundleOptions) createBundle(ctx context.Context, since,
 	if err != nil {
 		return fmt.Errorf("failed to parse collector: %v", err)
 	}
+	supportBundle.WithRedaction(csbo.redact)
 
 	var sinceTimeValue *time.Time
 	sinceTimeValue, err = diagnostics.ParseTimeOptions(since, sinceTime)
@@ -123,3 +157,53 @@ func (csbo *createSupportBundleOptions) createBundle(ctx context.Context, since,
 
 	return nil
 }
+
+// createFleetBundle collects a support bundle from every cluster in the management cluster, using the tools
+// and diagnostic collector images of the latest Kubernetes version in the CLI bundle
+func (csbo *createSupportBundleOptions) createFleetBundle(ctx context.Context, since, sinceTime string) error {
+	managementCluster, err := cluster.LoadManagement(csbo.kubeconfig)
+	if err != nil {
+		return fmt.Errorf("unable to load management cluster kubeconfig: %v", err)
+	}
+
+	bundles, err := getBundles(0, "")
+	if err != nil {
+		return err
+	}
+	if len(bundles.Spec.VersionsBundles) == 0 {
+		return fmt.Errorf("bundle %d doesn't have any Kubernetes version", bundles.Spec.Number)
+	}
+	versionsBundle := bundles.Spec.VersionsBundles[len(bundles.Spec.VersionsBundles)-1]
+
+	deps, err := dependencies.NewFactory().
+		WithExecutableBuilder(ctx, versionsBundle.Eksa.CliTools.VersionedImage()).
+		WithWriterFolder(managementCluster.Name).
+		WithDiagnosticCollectorImage(versionsBundle.Eksa.DiagnosticCollector.VersionedImage()).
+		WithDiagnosticBundleFactory().
+		Build()
+	if err != nil {
+		return err
+	}
+
+	sinceTimeValue, err := diagnostics.ParseTimeOptions(since, sinceTime)
+	if err != nil {
+		return fmt.Errorf("failed parse since time: %v", err)
+	}
+
+	fleetBundle := diagnostics.NewFleetBundle(diagnostics.FleetBundleOpts{
+		BundleFactory:     deps.DignosticCollectorFactory,
+		Client:            deps.Kubectl,
+		Writer:            deps.Writer,
+		ManagementCluster: managementCluster,
+		Concurrency:       csbo.concurrency,
+		Redact:            csbo.redact,
+	})
+	if _, err = fleetBundle.CollectAndAnalyze(ctx, sinceTimeValue); err != nil {
+		return fmt.Errorf("error while collecting and analyzing bundles: %v", err)
+	}
+
+	if err = fleetBundle.PrintAnalysis(); err != nil {
+		return fmt.Errorf("error when printing analysis")
+	}
+	return nil
+}
@@ -11,17 +11,24 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
+	"github.com/aws/eks-anywhere/pkg/rollback"
 	"github.com/aws/eks-anywhere/pkg/types"
 	"github.com/aws/eks-anywhere/pkg/validations"
 	"github.com/aws/eks-anywhere/pkg/validations/upgradevalidations"
+	"github.com/aws/eks-anywhere/pkg/version"
 	"github.com/aws/eks-anywhere/pkg/workflows"
 )
 
 type upgradeClusterOptions struct {
 	clusterOptions
+	validationOptions
 	wConfig    string
 	forceClean bool
+	rollback   bool
 }
 
 func (uc *upgradeClusterOptions) kubeConfig(clusterName string) string {
@@ -40,6 +47,17 @@ var upgradeClusterCmd = &cobra.Command{
 	PreRunE:      preRunUpgradeCluster,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cleanup, err := uc.renderConfig()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		if uc.rollback {
+			if err := uc.rollbackCluster(cmd.Context()); err != nil {
+				return fmt.Errorf("failed to roll back cluster upgrade: %v", err)
+			}
+			return nil
+		}
 		if err := uc.upgradeCluster(cmd.Context()); err != nil {
 			return fmt.Errorf("failed to upgrade cluster: %v", err)
 		}
@@ -59,11 +77,13 @@ func preRunUpgradeCluster(cmd *cobra.Command, args []string) error {
 
 func init() {
 	upgradeCmd.AddCommand(upgradeClusterCmd)
-	upgradeClusterCmd.Flags().StringVarP(&uc.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	uc.clusterOptions.addConfigFlags(upgradeClusterCmd.Flags())
 	upgradeClusterCmd.Flags().StringVarP(&uc.wConfig, "w-config", "w", "", "Kubeconfig file to use when upgrading a workload cluster")
 	upgradeClusterCmd.Flags().BoolVar(&uc.forceClean, "force-cleanup", false, "Force deletion of previously created bootstrap cluster")
 	upgradeClusterCmd.Flags().StringVar(&uc.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
 	upgradeClusterCmd.Flags().StringVar(&uc.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	uc.validationOptions.addFlags(upgradeClusterCmd.Flags())
+	upgradeClusterCmd.Flags().BoolVar(&uc.rollback, "rollback", false, "Roll back the last failed upgrade, restoring the node groups that haven't been upgraded yet")
 	err := upgradeClusterCmd.MarkFlagRequired("filename")
 	if err != nil {
 		log.Fatalf("Error marking flag as required: %v", err)
@@ -79,6 +99,15 @@ func (uc *upgradeClusterOptions) upgradeCluster(ctx context.Context) error {
 		return err
 	}
 
+	return uc.upgradeClusterWithSpec(ctx, clusterSpec)
+}
+
+func (uc *upgradeClusterOptions) upgradeClusterWithSpec(ctx context.Context, clusterSpec *cluster.Spec) error {
+	runnerOpts, err := uc.runnerOpts()
+	if err != nil {
+		return err
+	}
+
 	deps, err := dependencies.ForSpec(ctx, clusterSpec).
 		WithBootstrapper().
 		WithClusterManager().
@@ -99,24 +128,14 @@ func (uc *upgradeClusterOptions) upgradeCluster(ctx context.Context) error {
 		deps.ClusterManager,
 		deps.FluxAddonClient,
 		deps.Writer,
-	)
+	).WithValidationOpts(runnerOpts...)
 
-	workloadCluster := &types.Cluster{
-		Name:           clusterSpec.Name,
-		KubeconfigFile: uc.kubeConfig(clusterSpec.Name),
-	}
+	workloadCluster := uc.workloadCluster(clusterSpec)
+	cluster := uc.managementCluster(clusterSpec)
 
-	var cluster *types.Cluster
-	if clusterSpec.ManagementCluster == nil {
-		cluster = &types.Cluster{
-			Name:           clusterSpec.Name,
-			KubeconfigFile: uc.kubeConfig(clusterSpec.Name),
-		}
-	} else {
-		cluster = &types.Cluster{
-			Name:           clusterSpec.ManagementCluster.Name,
-			KubeconfigFile: clusterSpec.ManagementCluster.KubeconfigFile,
-		}
+	clusterSpec.RegistryMirrorCredentials, err = registrymirror.LoadCredentials(ctx, deps.Kubectl, cluster, clusterSpec.Cluster)
+	if err != nil {
+		return err
 	}
 
 	validationOpts := &validations.Opts{
@@ -125,6 +144,7 @@ func (uc *upgradeClusterOptions) upgradeCluster(ctx context.Context) error {
 		WorkloadCluster:   workloadCluster,
 		ManagementCluster: cluster,
 		Provider:          deps.Provider,
+		CliVersion:        version.Get().GitVersion,
 	}
 	upgradeValidations := upgradevalidations.New(validationOpts)
 
@@ -135,6 +155,83 @@ func (uc *upgradeClusterOptions) upgradeCluster(ctx context.Context) error {
 	return err
 }
 
+// rollbackCluster restores the objects saved before the last upgrade. The control plane and etcd are only
+// restored if the new version hasn't been rolled out to any of their machines yet.
+func (uc *upgradeClusterOptions) rollbackCluster(ctx context.Context) error {
+	if _, err := uc.commonValidations(ctx); err != nil {
+		return fmt.Errorf("common validations failed due to: %v", err)
+	}
+	clusterSpec, err := newClusterSpec(uc.clusterOptions)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := rollback.ReadSnapshot(filepath.Join(clusterSpec.Name, rollback.SnapshotFileName(clusterSpec.Name)))
+	if err != nil {
+		return err
+	}
+	previousSpec, err := cluster.BuildSpecFromBundles(snapshot.Cluster, snapshot.Bundles)
+	if err != nil {
+		return fmt.Errorf("error building cluster spec from upgrade snapshot: %v", err)
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).
+		WithClusterManager().
+		WithProvider(uc.fileName, clusterSpec.Cluster, cc.skipIpCheck).
+		WithFluxAddonClient(ctx, clusterSpec.Cluster, clusterSpec.GitOpsConfig).
+		WithKubectl().
+		Build()
+	if err != nil {
+		return err
+	}
+
+	managementCluster := uc.managementCluster(clusterSpec)
+	logger.Info("Rolling back cluster upgrade")
+	result, err := rollback.NewRollbacker(deps.Kubectl).Rollback(ctx, managementCluster, snapshot)
+	if err != nil {
+		return err
+	}
+
+	for _, md := range result.RestoredMachineDeployments {
+		logger.Info("Machine deployment restored", "machineDeployment", md)
+	}
+	if !result.Complete() {
+		logger.Info("The cluster can't be fully rolled back, fix the failure and run eksctl anywhere upgrade cluster again to complete the upgrade")
+		logger.Info("The EKS-A controller reconcile stays paused until the upgrade completes")
+		return nil
+	}
+
+	logger.Info("Resuming EKS-A controller reconciliation")
+	if err = deps.ClusterManager.ResumeEKSAControllerReconcile(ctx, managementCluster, previousSpec, deps.Provider); err != nil {
+		return err
+	}
+	logger.Info("Resuming Flux kustomization")
+	if err = deps.FluxAddonClient.ResumeGitOpsKustomization(ctx, managementCluster, clusterSpec); err != nil {
+		return err
+	}
+
+	logger.MarkSuccess("Cluster upgrade rolled back!")
+	return nil
+}
+
+func (uc *upgradeClusterOptions) workloadCluster(clusterSpec *cluster.Spec) *types.Cluster {
+	return &types.Cluster{
+		Name:           clusterSpec.Name,
+		KubeconfigFile: uc.kubeConfig(clusterSpec.Name),
+	}
+}
+
+// managementCluster returns the cluster holding the CAPI objects of the cluster being upgraded
+func (uc *upgradeClusterOptions) managementCluster(clusterSpec *cluster.Spec) *types.Cluster {
+	if clusterSpec.ManagementCluster == nil {
+		return uc.workloadCluster(clusterSpec)
+	}
+	return &types.Cluster{
+		Name:           clusterSpec.ManagementCluster.Name,
+		KubeconfigFile: clusterSpec.ManagementCluster.KubeconfigFile,
+	}
+}
+
 func (uc *upgradeClusterOptions) commonValidations(ctx context.Context) (cluster *v1alpha1.Cluster, err error) {
 	clusterConfig, err := commonValidation(ctx, uc.fileName)
 	if err != nil {
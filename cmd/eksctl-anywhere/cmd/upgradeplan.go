@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/upgradeplan"
+)
+
+type upgradePlanOptions struct {
+	upgradeClusterOptions
+	targetVersion string
+	execute       bool
+}
+
+var upo = &upgradePlanOptions{}
+
+var upgradePlanCmd = &cobra.Command{
+	Use:          "plan",
+	Short:        "Plan a Kubernetes upgrade across multiple minor versions",
+	Long:         "This command computes the Kubernetes minor version upgrades needed to take a cluster to the target version and, with --execute, runs them one after the other",
+	PreRunE:      preRunUpgradeCluster,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := upo.upgradePlan(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to plan cluster upgrade: %v", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	upgradeCmd.AddCommand(upgradePlanCmd)
+	upgradePlanCmd.Flags().StringVarP(&upo.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	upgradePlanCmd.Flags().StringVar(&upo.targetVersion, "to", "", "Kubernetes version to upgrade the cluster to")
+	upgradePlanCmd.Flags().BoolVar(&upo.execute, "execute", false, "Run the planned upgrades, waiting for the cluster to be healthy after each one")
+	upgradePlanCmd.Flags().StringVarP(&upo.wConfig, "w-config", "w", "", "Kubeconfig file to use when upgrading a workload cluster")
+	upgradePlanCmd.Flags().BoolVar(&upo.forceClean, "force-cleanup", false, "Force deletion of previously created bootstrap cluster")
+	upgradePlanCmd.Flags().StringVar(&upo.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	upgradePlanCmd.Flags().StringVar(&upo.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	for _, flag := range []string{"filename", "to"} {
+		if err := upgradePlanCmd.MarkFlagRequired(flag); err != nil {
+			log.Fatalf("Error marking flag as required: %v", err)
+		}
+	}
+}
+
+func (upo *upgradePlanOptions) upgradePlan(ctx context.Context) error {
+	if _, err := upo.commonValidations(ctx); err != nil {
+		return fmt.Errorf("common validations failed due to: %v", err)
+	}
+	targetVersion := v1alpha1.KubernetesVersion(upo.targetVersion)
+	clusterSpec, err := newClusterSpec(upo.clusterOptions, cluster.WithKubernetesVersion(targetVersion))
+	if err != nil {
+		return err
+	}
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).WithKubectl().Build()
+	if err != nil {
+		return err
+	}
+
+	workloadCluster := upo.workloadCluster(clusterSpec)
+	versions, err := deps.Kubectl.Version(ctx, workloadCluster)
+	if err != nil {
+		return fmt.Errorf("error getting version of cluster %s: %v", workloadCluster.Name, err)
+	}
+
+	plan, err := upgradeplan.New(clusterSpec.Cluster, versions.ServerVersion.GitVersion, targetVersion, clusterSpec.Bundles)
+	if err != nil {
+		return err
+	}
+	if err = printUpgradePlan(plan); err != nil {
+		return err
+	}
+
+	if !upo.execute || len(plan.Hops) == 0 {
+		return nil
+	}
+
+	if err = upo.validateMachineTemplates(clusterSpec, plan); err != nil {
+		return err
+	}
+
+	healthGate := upgradeplan.NewHealthGate(deps.Kubectl)
+	managementCluster := upo.managementCluster(clusterSpec)
+	for i, hop := range plan.Hops {
+		logger.Info(fmt.Sprintf("Upgrading cluster %s to Kubernetes %s (%d/%d)", plan.ClusterName, hop.KubernetesVersion, i+1, len(plan.Hops)))
+		hopSpec, err := newClusterSpec(upo.clusterOptions, cluster.WithKubernetesVersion(hop.KubernetesVersion))
+		if err != nil {
+			return err
+		}
+		if err = upo.upgradeClusterWithSpec(ctx, hopSpec); err != nil {
+			return fmt.Errorf("error upgrading cluster to Kubernetes %s: %v", hop.KubernetesVersion, err)
+		}
+
+		logger.Info("Waiting for cluster to be healthy", "kubernetesVersion", hop.KubernetesVersion)
+		if err = healthGate.Wait(ctx, managementCluster, workloadCluster); err != nil {
+			return err
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Cluster %s upgraded to Kubernetes %s, set kubernetesVersion to %s in %s", plan.ClusterName, plan.TargetVersion, plan.TargetVersion, upo.fileName))
+	return nil
+}
+
+// validateMachineTemplates checks no vSphere machine config sets a template when upgrading through more than one
+// version: templates are built for one Kubernetes version, so the CLI needs to pick the default one for each hop
+func (upo *upgradePlanOptions) validateMachineTemplates(clusterSpec *cluster.Spec, plan *upgradeplan.Plan) error {
+	if len(plan.Hops) < 2 || clusterSpec.Spec.DatacenterRef.Kind != v1alpha1.VSphereDatacenterKind {
+		return nil
+	}
+	machineConfigs, err := v1alpha1.GetVSphereMachineConfigs(upo.fileName)
+	if err != nil {
+		return err
+	}
+	for _, m := range machineConfigs {
+		if m.Spec.Template != "" {
+			return fmt.Errorf("VSphereMachineConfig %s sets template %s, which only works for one Kubernetes version: remove it to use the default template for each version or upgrade one version at a time with eksctl anywhere upgrade cluster", m.Name, m.Spec.Template)
+		}
+	}
+	return nil
+}
+
+func printUpgradePlan(plan *upgradeplan.Plan) error {
+	if len(plan.Hops) == 0 {
+		fmt.Printf("Cluster %s already runs Kubernetes %s, nothing to upgrade\n", plan.ClusterName, plan.CurrentVersion)
+		return nil
+	}
+
+	fmt.Printf("Upgrading cluster %s from Kubernetes %s to %s with bundle %d takes %d upgrades:\n", plan.ClusterName, plan.CurrentVersion, plan.TargetVersion, plan.BundleNumber, len(plan.Hops))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "STEP\tKUBERNETES VERSION\tEKS-D RELEASE\tNODE ROLLOUTS")
+	for i, hop := range plan.Hops {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", i+1, hop.KubernetesVersion, hop.EksDRelease, hop.NodeRollouts)
+	}
+	fmt.Fprintf(w, "\t\tTOTAL\t%d\n", plan.NodeRollouts())
+	return w.Flush()
+}
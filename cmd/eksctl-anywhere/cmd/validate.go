@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate resources",
+	Long:  "Use eksctl anywhere validate to validate resources, such as a cluster config file, without creating or changing them",
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/dependencies"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
+	"github.com/aws/eks-anywhere/pkg/validations/createvalidations"
+	"github.com/aws/eks-anywhere/pkg/validations/upgradevalidations"
+	"github.com/aws/eks-anywhere/pkg/version"
+)
+
+type validateClusterConfigOptions struct {
+	clusterOptions
+	validationOptions
+	wConfig     string
+	skipIpCheck bool
+}
+
+var vcc = &validateClusterConfigOptions{}
+
+var validateClusterConfigCmd = &cobra.Command{
+	Use:          "cluster-config -f <cluster-config-file> [flags]",
+	Short:        "Validate cluster config file",
+	Long:         "This command runs the validations of create cluster, or upgrade cluster if the cluster already exists, without creating or changing anything",
+	PreRunE:      preRunValidateClusterConfig,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := vcc.validateClusterConfig(cmd.Context()); err != nil {
+			return fmt.Errorf("cluster config validation failed: %v", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateClusterConfigCmd)
+	validateClusterConfigCmd.Flags().StringVarP(&vcc.fileName, "filename", "f", "", "Filename that contains EKS-A cluster configuration")
+	validateClusterConfigCmd.Flags().StringVarP(&vcc.wConfig, "w-config", "w", "", "Kubeconfig file of the cluster, if it already exists and manages itself")
+	validateClusterConfigCmd.Flags().StringVar(&vcc.managementKubeconfig, "kubeconfig", "", "Management cluster kubeconfig file")
+	validateClusterConfigCmd.Flags().StringVar(&vcc.bundlesOverride, "bundles-override", "", "Override default Bundles manifest (not recommended)")
+	validateClusterConfigCmd.Flags().BoolVar(&vcc.skipIpCheck, "skip-ip-check", false, "Skip check for whether cluster control plane ip is in use")
+	vcc.validationOptions.addFlags(validateClusterConfigCmd.Flags())
+	err := validateClusterConfigCmd.MarkFlagRequired("filename")
+	if err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+}
+
+func preRunValidateClusterConfig(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+func (o *validateClusterConfigOptions) validateClusterConfig(ctx context.Context) error {
+	runnerOpts, err := o.runnerOpts()
+	if err != nil {
+		return err
+	}
+
+	if _, err = commonValidation(ctx, o.fileName); err != nil {
+		return err
+	}
+
+	clusterSpec, err := newClusterSpec(o.clusterOptions)
+	if err != nil {
+		return err
+	}
+
+	// Files generated during the validations, like ssh keys, are discarded instead of written to the cluster folder
+	writerFolder, err := os.MkdirTemp(".", fmt.Sprintf(".%s-validate-", clusterSpec.Name))
+	if err != nil {
+		return fmt.Errorf("error creating temporary folder: %v", err)
+	}
+	defer os.RemoveAll(writerFolder)
+
+	deps, err := dependencies.ForSpec(ctx, clusterSpec).
+		WithWriterFolder(writerFolder).
+		WithReadOnlyProvider().
+		WithProvider(o.fileName, clusterSpec.Cluster, o.skipIpCheck).
+		WithFluxAddonClient(ctx, clusterSpec.Cluster, clusterSpec.GitOpsConfig).
+		WithKubectl().
+		WithWriter().
+		Build()
+	if err != nil {
+		return err
+	}
+
+	managementCluster := o.managementCluster(clusterSpec)
+	exists, err := clusterExists(ctx, deps.Kubectl, managementCluster, clusterSpec.Name)
+	if err != nil {
+		return err
+	}
+
+	clusterSpec.RegistryMirrorCredentials, err = registrymirror.LoadCredentials(ctx, deps.Kubectl, clusterSpec.ManagementCluster, clusterSpec.Cluster)
+	if err != nil {
+		return err
+	}
+
+	validationOpts := &validations.Opts{
+		Kubectl: deps.Kubectl,
+		Spec:    clusterSpec,
+		WorkloadCluster: &types.Cluster{
+			Name:           clusterSpec.Name,
+			KubeconfigFile: o.kubeConfig(clusterSpec.Name),
+		},
+		ManagementCluster: managementCluster,
+		Provider:          deps.Provider,
+		CliVersion:        version.Get().GitVersion,
+	}
+
+	runner := validations.NewRunner(runnerOpts...)
+	if exists {
+		logger.Info("Cluster already exists, running upgrade validations", "cluster", clusterSpec.Name)
+		runner.RegisterChecks(validations.ProviderUpgradeChecks(ctx, deps.Provider, managementCluster, clusterSpec)...)
+		checks := upgradevalidations.New(validationOpts).PreflightChecks(ctx)
+		checks = append(checks, validations.Check{
+			Name:    "cluster webhook rules",
+			Warning: true,
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "cluster webhook rules",
+					Remediation: "apply this change with eksctl anywhere upgrade cluster instead of kubectl or GitOps",
+					Err:         upgradevalidations.ValidateClusterWebhookRules(ctx, deps.Kubectl, managementCluster, clusterSpec),
+				}
+			},
+		})
+		runner.RegisterChecks(validations.DependOn(checks, validations.ProviderSetupCheckName)...)
+	} else {
+		logger.Info("Cluster doesn't exist, running create validations", "cluster", clusterSpec.Name)
+		runner.RegisterChecks(validations.ProviderCreateChecks(ctx, deps.Provider, clusterSpec)...)
+		runner.RegisterChecks(validations.DependOn(deps.FluxAddonClient.Validations(ctx, clusterSpec), validations.ProviderSetupCheckName)...)
+		runner.RegisterChecks(validations.DependOn(createvalidations.New(validationOpts).PreflightChecks(ctx), validations.ProviderSetupCheckName)...)
+	}
+
+	return runner.Run()
+}
+
+func (o *validateClusterConfigOptions) kubeConfig(clusterName string) string {
+	if o.wConfig == "" {
+		return filepath.Join(clusterName, fmt.Sprintf(kubeconfigPattern, clusterName))
+	}
+	return o.wConfig
+}
+
+func (o *validateClusterConfigOptions) managementCluster(clusterSpec *cluster.Spec) *types.Cluster {
+	if clusterSpec.ManagementCluster != nil {
+		return &types.Cluster{
+			Name:           clusterSpec.ManagementCluster.Name,
+			KubeconfigFile: clusterSpec.ManagementCluster.KubeconfigFile,
+		}
+	}
+	return &types.Cluster{
+		Name:           clusterSpec.Name,
+		KubeconfigFile: o.kubeConfig(clusterSpec.Name),
+	}
+}
+
+// clusterExists returns true if the EKS-A cluster object with name is in managementCluster.
+// A management cluster without a kubeconfig file hasn't been created yet
+func clusterExists(ctx context.Context, kubectl *executables.Kubectl, managementCluster *types.Cluster, name string) (bool, error) {
+	if !validations.FileExists(managementCluster.KubeconfigFile) {
+		return false, nil
+	}
+
+	clusters, err := kubectl.GetObjects(ctx, fmt.Sprintf("clusters.%s", v1alpha1.GroupVersion.Group), executables.WithCluster(managementCluster), executables.WithAllNamespaces())
+	if err != nil {
+		return false, fmt.Errorf("error checking if cluster %s exists: %v", name, err)
+	}
+	for _, c := range clusters {
+		if c.GetName() == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/aws/eks-anywhere/pkg/configschema"
+)
+
+type validateSchemaOptions struct {
+	fileNames []string
+	output    string
+}
+
+var validateSchemaOpts = &validateSchemaOptions{}
+
+var validateSchemaCmd = &cobra.Command{
+	Use:          "schema -f <cluster-config-file> [flags]",
+	Short:        "Validate cluster config files against the schema",
+	Long:         "This command checks the documents in cluster config files against the schema of their kind without connecting to any cluster or provider",
+	PreRunE:      preRunValidateSchema,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return validateSchema(os.Stdout, validateSchemaOpts)
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateSchemaCmd)
+	validateSchemaCmd.Flags().StringSliceVarP(&validateSchemaOpts.fileNames, "filename", "f", nil, "Files that contain EKS-A cluster configuration, can be repeated")
+	validateSchemaCmd.Flags().StringVar(&validateSchemaOpts.output, "output", "", "Format of the report. Supported values: json")
+	err := validateSchemaCmd.MarkFlagRequired("filename")
+	if err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+}
+
+func preRunValidateSchema(cmd *cobra.Command, args []string) error {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		err := viper.BindPFlag(flag.Name, flag)
+		if err != nil {
+			log.Fatalf("Error initializing flags: %v", err)
+		}
+	})
+	return nil
+}
+
+type fileSchemaErrors struct {
+	File   string                    `json:"file"`
+	Errors []configschema.FieldError `json:"errors"`
+}
+
+func validateSchema(w io.Writer, opts *validateSchemaOptions) error {
+	if opts.output != "" && opts.output != jsonOutput {
+		return fmt.Errorf("unsupported output %s, supported values: %s", opts.output, jsonOutput)
+	}
+
+	schemas, err := configschema.Load()
+	if err != nil {
+		return err
+	}
+
+	report := make([]fileSchemaErrors, 0, len(opts.fileNames))
+	failed := false
+	for _, f := range opts.fileNames {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("error reading cluster config file: %v", err)
+		}
+		errs := schemas.Validate(content)
+		if errs == nil {
+			errs = []configschema.FieldError{}
+		}
+		failed = failed || len(errs) > 0
+		report = append(report, fileSchemaErrors{File: f, Errors: errs})
+	}
+
+	if opts.output == jsonOutput {
+		content, err := json.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("error marshalling schema validation report: %v", err)
+		}
+		fmt.Fprintln(w, string(content))
+	} else {
+		for _, r := range report {
+			for _, e := range r.Errors {
+				fmt.Fprintf(w, "%s:%s\n", r.File, e.Error())
+			}
+		}
+	}
+
+	if failed {
+		return errors.New("cluster config files don't match the schema")
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+// Package crd embeds the CRDs generated from the kubebuilder markers in pkg/api/v1alpha1
+package crd
+
+import "embed"
+
+//go:embed bases/*.yaml
+var Bases embed.FS
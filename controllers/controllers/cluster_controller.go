@@ -8,14 +8,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aws/eks-anywhere/controllers/controllers/resource"
 	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/clusterapi"
+	"github.com/aws/eks-anywhere/pkg/clusterctl"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/loadbalancer"
+	"github.com/aws/eks-anywhere/pkg/networking"
 )
 
 // ClusterReconciler reconciles a Cluster object
@@ -27,17 +34,35 @@ type ClusterReconciler struct {
 	resourceFetcher resource.ResourceFetcher
 }
 
-func NewClusterReconciler(client client.Client, log logr.Logger, scheme *runtime.Scheme) *ClusterReconciler {
+// NewClusterReconciler builds the reconciler for EKS-A clusters. The writer folder holds the clusterctl configuration
+// when the providers in the management cluster are upgraded.
+func NewClusterReconciler(client client.Client, log logr.Logger, scheme *runtime.Scheme, writer filewriter.FileWriter) *ClusterReconciler {
 	return &ClusterReconciler{
 		Client: client,
 		Log:    log,
 		Scheme: scheme,
 		reconcilers: []resource.Reconciler{
+			// Providers are upgraded first, the new objects might need them
+			resource.NewCAPIUpgradeReconciler(
+				resource.NewCAPIResourceFetcher(client, log),
+				clusterapi.NewUpgrader(clusterctl.New(writer)),
+				resource.NewManagementProviderBuilder(resource.NewCAPIResourceFetcher(client, log), writer),
+				log),
 			resource.NewClusterReconciler(
 				resource.NewCAPIResourceFetcher(client, log),
 				resource.NewCAPIResourceUpdater(client, log),
 				time.Now,
 				log),
+			resource.NewLoadBalancerReconciler(
+				resource.NewCAPIResourceFetcher(client, log),
+				resource.NewWorkloadClusterClients(client, log),
+				loadbalancer.New(),
+				log),
+			resource.NewUpgradeReconciler(
+				resource.NewCAPIResourceFetcher(client, log),
+				resource.NewWorkloadClusterClients(client, log),
+				networking.NewCilium(),
+				log),
 		},
 		resourceFetcher: resource.NewCAPIResourceFetcher(client, log),
 	}
@@ -45,6 +70,8 @@ func NewClusterReconciler(client client.Client, log logr.Logger, scheme *runtime
 
 //+kubebuilder:rbac:groups=anywhere.eks.amazonaws.com,resources=clusters;vspheredatacenterconfigs;vspheremachineconfigs;dockerdatacenterconfigs;bundles;awsiamconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=anywhere.eks.amazonaws.com,resources=oidcconfigs,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=clusterctl.cluster.x-k8s.io,resources=providers,verbs=get;list
 //+kubebuilder:rbac:groups=anywhere.eks.amazonaws.com,resources=clusters/status;vspheredatacenterconfigs/status;vspheremachineconfigs/status;dockerdatacenterconfigs/status;bundles/status;awsiamconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=anywhere.eks.amazonaws.com,resources=clusters/finalizers;vspheredatacenterconfigs/finalizers;vspheremachineconfigs/finalizers;dockerdatacenterconfigs/finalizers;bundles/finalizers;awsiamconfigs/finalizers,verbs=update
 
@@ -105,7 +132,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, objectKey types.Names
 			errs = append(errs, err)
 		}
 		if len(errs) > 0 {
-			continue
+			break
 		}
 	}
 	return ctrl.Result{}, kerrors.NewAggregate(errs)
@@ -119,5 +146,22 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &anywherev1.VSphereMachineConfig{}}, &handler.EnqueueRequestForObject{}).
 		Watches(&source.Kind{Type: &anywherev1.DockerDatacenterConfig{}}, &handler.EnqueueRequestForObject{}).
 		Watches(&source.Kind{Type: &anywherev1.AWSIamConfig{}}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &kubeadmnv1alpha3.KubeadmControlPlane{}}, handler.EnqueueRequestsFromMapFunc(r.controlPlaneToCluster)).
 		Complete(r)
 }
+
+// controlPlaneToCluster maps a KubeadmControlPlane to its EKS-A Cluster, so upgrades continue with
+// the workers once the control plane rollout is over
+func (r *ClusterReconciler) controlPlaneToCluster(o client.Object) []reconcile.Request {
+	clusters := &anywherev1.ClusterList{}
+	if err := r.List(context.Background(), clusters); err != nil {
+		r.Log.Error(err, "Failed to list Clusters for KubeadmControlPlane", "name", o.GetName())
+		return nil
+	}
+	for _, c := range clusters.Items {
+		if c.Name == o.GetName() {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: c.Namespace, Name: c.Name}}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/clusterapi"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/providers/docker"
+	"github.com/aws/eks-anywhere/pkg/providers/vsphere"
+	anywhereTypes "github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	clusterctlProviderKind       = "Provider"
+	clusterctlProviderAPIVersion = "clusterctl.cluster.x-k8s.io/v1alpha3"
+
+	vsphereCredentialsSecretName = "vsphere-credentials"
+	vsphereUsernameEnv           = "VSPHERE_USERNAME"
+	vspherePasswordEnv           = "VSPHERE_PASSWORD"
+	expClusterResourceSetEnv     = "EXP_CLUSTER_RESOURCE_SET"
+)
+
+// CAPIUpgrader upgrades the Cluster API providers installed in a management cluster, like clusterapi.Upgrader
+type CAPIUpgrader interface {
+	Upgrade(ctx context.Context, managementCluster *anywhereTypes.Cluster, provider providers.Provider, currentSpec, newSpec *cluster.Spec) (*anywhereTypes.ChangeDiff, error)
+}
+
+// capiUpgradeReconciler upgrades the CAPI providers in the management cluster before the cluster reconciler
+// applies the objects of a workload cluster that moved to a bundle with newer providers
+type capiUpgradeReconciler struct {
+	Log logr.Logger
+	ResourceFetcher
+	capiUpgrader        CAPIUpgrader
+	managementProviders ManagementProviderBuilder
+}
+
+func NewCAPIUpgradeReconciler(resourceFetcher ResourceFetcher, capiUpgrader CAPIUpgrader, managementProviders ManagementProviderBuilder, log logr.Logger) *capiUpgradeReconciler {
+	return &capiUpgradeReconciler{
+		Log:                 log,
+		ResourceFetcher:     resourceFetcher,
+		capiUpgrader:        capiUpgrader,
+		managementProviders: managementProviders,
+	}
+}
+
+func (cur *capiUpgradeReconciler) Reconcile(ctx context.Context, objectKey types.NamespacedName, dryRun bool) error {
+	cs, err := cur.FetchCluster(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	// The providers of a management cluster are upgraded with the cluster itself by the CLI
+	if cs.IsSelfManaged() {
+		return nil
+	}
+
+	paused, err := capiClusterPaused(ctx, cur.ResourceFetcher, cs)
+	if err != nil {
+		return err
+	}
+	if paused {
+		cur.Log.Info("skipping Cluster API providers upgrade, CAPI cluster is paused", "cluster", cs.Name)
+		return nil
+	}
+
+	spec, err := cur.FetchAppliedSpec(ctx, cs)
+	if err != nil {
+		return err
+	}
+
+	return cur.upgradeManagementClusterComponents(ctx, cs, spec, dryRun)
+}
+
+// upgradeManagementClusterComponents upgrades the CAPI providers running in the management cluster with
+// clusterapi.Upgrader when the bundle of the workload cluster needs newer ones. The providers are shared by all
+// the clusters, so they are compared with the installed versions and are never downgraded by an older bundle.
+func (cur *capiUpgradeReconciler) upgradeManagementClusterComponents(ctx context.Context, cs *anywherev1.Cluster, spec *cluster.Spec, dryRun bool) error {
+	managementCluster, err := cur.FetchCluster(ctx, types.NamespacedName{Namespace: cs.Namespace, Name: cs.ManagedBy()})
+	if err != nil {
+		return fmt.Errorf("error getting management cluster %s: %v", cs.ManagedBy(), err)
+	}
+	managementSpec, err := cur.FetchAppliedSpec(ctx, managementCluster)
+	if err != nil {
+		return err
+	}
+
+	if spec.Bundles.Spec.Number <= managementSpec.Bundles.Spec.Number {
+		return nil
+	}
+
+	// The management cluster keeps its own spec, only the providers move to the workload cluster bundle
+	newSpec := managementSpec.DeepCopy()
+	newSpec.Bundles = spec.Bundles
+	newSpec.VersionsBundle = spec.VersionsBundle
+	currentSpec, err := installedProvidersSpec(ctx, cur.ResourceFetcher, newSpec)
+	if err != nil {
+		return err
+	}
+
+	if clusterapi.CoreComponentsChangeDiff(currentSpec, newSpec) == nil &&
+		infrastructureProviderVersion(managementCluster, currentSpec) == infrastructureProviderVersion(managementCluster, newSpec) {
+		return nil
+	}
+
+	if dryRun {
+		cur.Log.Info("Cluster API providers in management cluster need to be upgraded", "cluster", cs.Name, "managementCluster", managementCluster.Name, "bundle", spec.Bundles.Spec.Number)
+		return nil
+	}
+
+	provider, capiCluster, err := cur.managementProviders(ctx, managementCluster)
+	if err != nil {
+		return fmt.Errorf("error building provider for management cluster %s: %v", managementCluster.Name, err)
+	}
+
+	cur.Log.Info("upgrading Cluster API providers in management cluster", "cluster", cs.Name, "managementCluster", managementCluster.Name, "bundle", spec.Bundles.Spec.Number)
+	if _, err = cur.capiUpgrader.Upgrade(ctx, capiCluster, provider, currentSpec, newSpec); err != nil {
+		return fmt.Errorf("error upgrading Cluster API providers in management cluster %s: %v", managementCluster.Name, err)
+	}
+
+	return nil
+}
+
+func infrastructureProviderVersion(cs *anywherev1.Cluster, spec *cluster.Spec) string {
+	switch cs.Spec.DatacenterRef.Kind {
+	case anywherev1.VSphereDatacenterKind:
+		return spec.VersionsBundle.VSphere.Version
+	case anywherev1.DockerDatacenterKind:
+		return spec.VersionsBundle.Docker.Version
+	default:
+		return ""
+	}
+}
+
+// ManagementProviderBuilder returns the infrastructure provider of a management cluster and the cluster, with
+// a kubeconfig file, clusterctl uses to upgrade its Cluster API providers
+type ManagementProviderBuilder func(ctx context.Context, cs *anywherev1.Cluster) (providers.Provider, *anywhereTypes.Cluster, error)
+
+// NewManagementProviderBuilder builds the providers used to upgrade the Cluster API providers. The management
+// cluster kubeconfig is read from the secret generated by CAPI and the vSphere credentials from the secret created
+// with the cluster. Upgrading the providers only reads the bundle and the provider env, so the providers are built
+// without the clients that run commands, which aren't in the controller image.
+func NewManagementProviderBuilder(fetcher ResourceFetcher, writer filewriter.FileWriter) ManagementProviderBuilder {
+	return func(ctx context.Context, cs *anywherev1.Cluster) (providers.Provider, *anywhereTypes.Cluster, error) {
+		kubeconfigSecret := &corev1.Secret{}
+		if err := fetcher.FetchObjectByName(ctx, cs.Name+"-kubeconfig", constants.EksaSystemNamespace, kubeconfigSecret); err != nil {
+			return nil, nil, fmt.Errorf("error getting kubeconfig for management cluster %s: %v", cs.Name, err)
+		}
+		kubeconfig, err := writer.Write(cs.Name+"-eks-a-cluster.kubeconfig", kubeconfigSecret.Data["value"], filewriter.PersistentFile, filewriter.Permission0600)
+		if err != nil {
+			return nil, nil, err
+		}
+		managementCluster := &anywhereTypes.Cluster{Name: cs.Name, KubeconfigFile: kubeconfig}
+
+		switch cs.Spec.DatacenterRef.Kind {
+		case anywherev1.VSphereDatacenterKind:
+			datacenterConfig := &anywherev1.VSphereDatacenterConfig{}
+			if err := fetcher.FetchObject(ctx, types.NamespacedName{Namespace: cs.Namespace, Name: cs.Spec.DatacenterRef.Name}, datacenterConfig); err != nil {
+				return nil, nil, err
+			}
+			env, err := vsphereCredentials(ctx, fetcher)
+			if err != nil {
+				return nil, nil, err
+			}
+			provider := vsphere.NewProvider(datacenterConfig, map[string]*anywherev1.VSphereMachineConfig{}, cs, nil, nil, writer, time.Now, true, nil)
+			return &envProvider{Provider: provider, env: env}, managementCluster, nil
+		case anywherev1.DockerDatacenterKind:
+			datacenterConfig := &anywherev1.DockerDatacenterConfig{}
+			if err := fetcher.FetchObject(ctx, types.NamespacedName{Namespace: cs.Namespace, Name: cs.Spec.DatacenterRef.Name}, datacenterConfig); err != nil {
+				return nil, nil, err
+			}
+			provider := docker.NewProvider(datacenterConfig, nil, nil, time.Now)
+			return &envProvider{Provider: provider, env: map[string]string{}}, managementCluster, nil
+		default:
+			return nil, nil, fmt.Errorf("unsupport Provider %s", cs.Spec.DatacenterRef.Kind)
+		}
+	}
+}
+
+// envProvider passes its own env to clusterctl, instead of the one the provider reads from the process env
+type envProvider struct {
+	providers.Provider
+	env map[string]string
+}
+
+func (p *envProvider) EnvMap() (map[string]string, error) {
+	return p.env, nil
+}
+
+// vsphereCredentials returns the env the vSphere provider passes to clusterctl, with the credentials in the
+// secret created with the cluster
+func vsphereCredentials(ctx context.Context, fetcher ResourceFetcher) (map[string]string, error) {
+	secret := &corev1.Secret{}
+	if err := fetcher.FetchObjectByName(ctx, vsphereCredentialsSecretName, constants.EksaSystemNamespace, secret); err != nil {
+		return nil, fmt.Errorf("error getting vSphere credentials: %v", err)
+	}
+	return map[string]string{
+		vsphereUsernameEnv:       string(secret.Data["username"]),
+		vspherePasswordEnv:       string(secret.Data["password"]),
+		expClusterResourceSetEnv: "true",
+	}, nil
+}
+
+// clusterctlProvider identifies the clusterctl Provider object created for each installed Cluster API provider
+type clusterctlProvider struct {
+	name      string
+	namespace string
+	version   func(*cluster.VersionsBundle) *string
+}
+
+var clusterctlProviders = []clusterctlProvider{
+	{
+		name:      "cluster-api",
+		namespace: constants.CapiSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.ClusterAPI.Version },
+	},
+	{
+		name:      "bootstrap-kubeadm",
+		namespace: constants.CapiKubeadmBootstrapSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.Bootstrap.Version },
+	},
+	{
+		name:      "control-plane-kubeadm",
+		namespace: constants.CapiKubeadmControlPlaneSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.ControlPlane.Version },
+	},
+	{
+		name:      "bootstrap-etcdadm-bootstrap",
+		namespace: constants.EtcdAdmBootstrapProviderSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.ExternalEtcdBootstrap.Version },
+	},
+	{
+		name:      "bootstrap-etcdadm-controller",
+		namespace: constants.EtcdAdmControllerSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.ExternalEtcdController.Version },
+	},
+	{
+		name:      "infrastructure-vsphere",
+		namespace: constants.CapvSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.VSphere.Version },
+	},
+	{
+		name:      "infrastructure-docker",
+		namespace: constants.CapdSystemNamespace,
+		version:   func(b *cluster.VersionsBundle) *string { return &b.Docker.Version },
+	},
+}
+
+// installedProvidersSpec returns a copy of spec with the versions of the Cluster API providers installed in the
+// management cluster, read from the clusterctl inventory. Providers that aren't installed keep the spec version,
+// so they are never part of the upgrade.
+func installedProvidersSpec(ctx context.Context, fetcher ResourceFetcher, spec *cluster.Spec) (*cluster.Spec, error) {
+	installed := spec.DeepCopy()
+	for _, p := range clusterctlProviders {
+		provider, err := fetcher.Fetch(ctx, p.name, p.namespace, clusterctlProviderKind, clusterctlProviderAPIVersion)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting installed version of provider %s: %v", p.name, err)
+		}
+		version, _, err := unstructured.NestedString(provider.Object, "version")
+		if err != nil {
+			return nil, fmt.Errorf("error reading installed version of provider %s: %v", p.name, err)
+		}
+		*p.version(installed.VersionsBundle) = version
+	}
+
+	return installed, nil
+}
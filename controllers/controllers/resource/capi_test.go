@@ -0,0 +1,249 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-anywhere/controllers/controllers/resource"
+	"github.com/aws/eks-anywhere/controllers/controllers/resource/mocks"
+	"github.com/aws/eks-anywhere/internal/test"
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	providerMocks "github.com/aws/eks-anywhere/pkg/providers/mocks"
+	anywhereTypes "github.com/aws/eks-anywhere/pkg/types"
+)
+
+type capiUpgradeTest struct {
+	*WithT
+	ctx               context.Context
+	fetcher           *mocks.MockResourceFetcher
+	upgrader          *mocks.MockCAPIUpgrader
+	provider          *providerMocks.MockProvider
+	cluster           *anywherev1.Cluster
+	clusterSpec       *cluster.Spec
+	managementCluster *anywherev1.Cluster
+	managementSpec    *cluster.Spec
+	capiCluster       *anywhereTypes.Cluster
+	installed         map[string]string
+	builtProviders    int
+	objectKey         types.NamespacedName
+	reconciler        resource.Reconciler
+}
+
+func newCAPIUpgradeTest(t *testing.T) *capiUpgradeTest {
+	mockCtrl := gomock.NewController(t)
+	tt := &capiUpgradeTest{
+		WithT:       NewWithT(t),
+		ctx:         context.Background(),
+		fetcher:     mocks.NewMockResourceFetcher(mockCtrl),
+		upgrader:    mocks.NewMockCAPIUpgrader(mockCtrl),
+		provider:    providerMocks.NewMockProvider(mockCtrl),
+		objectKey:   types.NamespacedName{Name: "workload", Namespace: "default"},
+		capiCluster: &anywhereTypes.Cluster{Name: "management", KubeconfigFile: "management-eks-a-cluster.kubeconfig"},
+		installed: map[string]string{
+			"cluster-api":            "v0.3.19",
+			"bootstrap-kubeadm":      "v0.3.19",
+			"control-plane-kubeadm":  "v0.3.19",
+			"infrastructure-vsphere": "v0.7.8",
+		},
+	}
+	tt.cluster = &anywherev1.Cluster{}
+	tt.cluster.SetName(tt.objectKey.Name)
+	tt.cluster.SetNamespace(tt.objectKey.Namespace)
+	tt.cluster.SetManagedBy("management")
+	tt.cluster.Spec.DatacenterRef.Kind = anywherev1.VSphereDatacenterKind
+	tt.clusterSpec = test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Cluster = tt.cluster
+		s.Bundles.Spec.Number = 2
+		s.VersionsBundle.ClusterAPI.Version = "v0.3.23"
+		s.VersionsBundle.Bootstrap.Version = "v0.3.23"
+		s.VersionsBundle.ControlPlane.Version = "v0.3.23"
+		s.VersionsBundle.VSphere.Version = "v0.7.10"
+	})
+
+	tt.managementCluster = &anywherev1.Cluster{}
+	tt.managementCluster.SetName("management")
+	tt.managementCluster.SetNamespace(tt.objectKey.Namespace)
+	tt.managementCluster.SetSelfManaged()
+	tt.managementCluster.Spec.DatacenterRef.Kind = anywherev1.VSphereDatacenterKind
+	tt.managementSpec = test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Cluster = tt.managementCluster
+		s.Bundles.Spec.Number = 1
+		s.VersionsBundle.ClusterAPI.Version = "v0.3.19"
+		s.VersionsBundle.Bootstrap.Version = "v0.3.19"
+		s.VersionsBundle.ControlPlane.Version = "v0.3.19"
+		s.VersionsBundle.VSphere.Version = "v0.7.8"
+	})
+
+	managementProviders := func(ctx context.Context, cs *anywherev1.Cluster) (providers.Provider, *anywhereTypes.Cluster, error) {
+		tt.builtProviders++
+		tt.Expect(cs).To(Equal(tt.managementCluster))
+		return tt.provider, tt.capiCluster, nil
+	}
+	tt.reconciler = resource.NewCAPIUpgradeReconciler(tt.fetcher, tt.upgrader, managementProviders, log.Log)
+
+	return tt
+}
+
+func (tt *capiUpgradeTest) expectFetchClusters() {
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchObjectByName(tt.ctx, "workload", "eksa-system", gomock.AssignableToTypeOf(&clusterv1.Cluster{})).Return(nil)
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.cluster).Return(tt.clusterSpec, nil)
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, types.NamespacedName{Name: "management", Namespace: "default"}).Return(tt.managementCluster, nil)
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.managementCluster).Return(tt.managementSpec, nil)
+}
+
+func (tt *capiUpgradeTest) expectFetchInstalledProviders() {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "clusterctl.cluster.x-k8s.io", Resource: "providers"}, "")
+	tt.fetcher.EXPECT().Fetch(tt.ctx, gomock.Any(), gomock.Any(), "Provider", "clusterctl.cluster.x-k8s.io/v1alpha3").DoAndReturn(
+		func(ctx context.Context, name, namespace, kind, apiVersion string) (*unstructured.Unstructured, error) {
+			version, ok := tt.installed[name]
+			if !ok {
+				return nil, notFound
+			}
+			return &unstructured.Unstructured{Object: map[string]interface{}{"version": version}}, nil
+		},
+	).Times(7)
+}
+
+func TestCAPIUpgradeReconcilerReconcileUpgradesProviders(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+
+	tt.expectFetchClusters()
+	tt.expectFetchInstalledProviders()
+	tt.upgrader.EXPECT().Upgrade(tt.ctx, tt.capiCluster, tt.provider, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, managementCluster *anywhereTypes.Cluster, provider providers.Provider, currentSpec, newSpec *cluster.Spec) (*anywhereTypes.ChangeDiff, error) {
+			tt.Expect(currentSpec.VersionsBundle.ClusterAPI.Version).To(Equal("v0.3.19"))
+			tt.Expect(currentSpec.VersionsBundle.VSphere.Version).To(Equal("v0.7.8"))
+			tt.Expect(newSpec.Cluster.Name).To(Equal("management"))
+			tt.Expect(newSpec.Bundles.Spec.Number).To(Equal(2))
+			tt.Expect(newSpec.VersionsBundle.ClusterAPI.Version).To(Equal("v0.3.23"))
+			tt.Expect(newSpec.VersionsBundle.VSphere.Version).To(Equal("v0.7.10"))
+			return &anywhereTypes.ChangeDiff{}, nil
+		},
+	)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(tt.builtProviders).To(Equal(1))
+}
+
+func TestCAPIUpgradeReconcilerReconcileProvidersAlreadyUpgraded(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+	tt.installed = map[string]string{
+		"cluster-api":            "v0.3.23",
+		"bootstrap-kubeadm":      "v0.3.23",
+		"control-plane-kubeadm":  "v0.3.23",
+		"infrastructure-vsphere": "v0.7.10",
+	}
+
+	tt.expectFetchClusters()
+	tt.expectFetchInstalledProviders()
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(tt.builtProviders).To(Equal(0))
+}
+
+func TestCAPIUpgradeReconcilerReconcileOlderBundle(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+	tt.managementSpec.Bundles.Spec.Number = 3
+
+	tt.expectFetchClusters()
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(tt.builtProviders).To(Equal(0))
+}
+
+func TestCAPIUpgradeReconcilerReconcileDryRun(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+
+	tt.expectFetchClusters()
+	tt.expectFetchInstalledProviders()
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, true)).To(Succeed())
+	tt.Expect(tt.builtProviders).To(Equal(0))
+}
+
+func TestCAPIUpgradeReconcilerReconcileUpgradeError(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+
+	tt.expectFetchClusters()
+	tt.expectFetchInstalledProviders()
+	tt.upgrader.EXPECT().Upgrade(tt.ctx, tt.capiCluster, tt.provider, gomock.Any(), gomock.Any()).Return(nil, errors.New("clusterctl failed"))
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(MatchError(
+		"error upgrading Cluster API providers in management cluster management: clusterctl failed",
+	))
+}
+
+func TestCAPIUpgradeReconcilerReconcileCAPIClusterPaused(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchObjectByName(tt.ctx, "workload", "eksa-system", gomock.AssignableToTypeOf(&clusterv1.Cluster{})).DoAndReturn(
+		func(ctx context.Context, name, namespace string, obj client.Object) error {
+			obj.(*clusterv1.Cluster).Spec.Paused = true
+			return nil
+		},
+	)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestCAPIUpgradeReconcilerReconcileManagementCluster(t *testing.T) {
+	tt := newCAPIUpgradeTest(t)
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.managementCluster, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestManagementProviderBuilderVSphereCredentials(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fetcher := mocks.NewMockResourceFetcher(gomock.NewController(t))
+	_, writer := test.NewWriter(t)
+	cs := &anywherev1.Cluster{}
+	cs.SetName("management")
+	cs.SetNamespace("default")
+	cs.Spec.DatacenterRef = anywherev1.Ref{Kind: anywherev1.VSphereDatacenterKind, Name: "management"}
+
+	fetcher.EXPECT().FetchObjectByName(ctx, "management-kubeconfig", "eksa-system", gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(
+		func(ctx context.Context, name, namespace string, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{"value": []byte("kubeconfig")}
+			return nil
+		},
+	)
+	fetcher.EXPECT().FetchObject(ctx, types.NamespacedName{Namespace: "default", Name: "management"}, gomock.AssignableToTypeOf(&anywherev1.VSphereDatacenterConfig{})).Return(nil)
+	fetcher.EXPECT().FetchObjectByName(ctx, "vsphere-credentials", "eksa-system", gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(
+		func(ctx context.Context, name, namespace string, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{"username": []byte("user"), "password": []byte("pass")}
+			return nil
+		},
+	)
+
+	provider, managementCluster, err := resource.NewManagementProviderBuilder(fetcher, writer)(ctx, cs)
+	g.Expect(err).To(BeNil())
+	g.Expect(managementCluster.Name).To(Equal("management"))
+	g.Expect(managementCluster.KubeconfigFile).To(BeARegularFile())
+	g.Expect(provider.EnvMap()).To(Equal(map[string]string{
+		"VSPHERE_USERNAME":         "user",
+		"VSPHERE_PASSWORD":         "pass",
+		"EXP_CLUSTER_RESOURCE_SET": "true",
+	}))
+	_, set := os.LookupEnv("VSPHERE_PASSWORD")
+	g.Expect(set).To(BeFalse(), "the credentials aren't set in the controller env")
+}
@@ -0,0 +1,111 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+)
+
+const workloadClientSourceName = "eksa-controller"
+
+type LoadBalancerManifestGenerator interface {
+	GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error)
+}
+
+// WorkloadClusterClients returns the fetcher and updater used to reconcile objects inside a workload cluster
+type WorkloadClusterClients func(ctx context.Context, cs *anywherev1.Cluster) (ResourceFetcher, ResourceUpdater, error)
+
+// NewWorkloadClusterClients builds clients for the cluster API server. Self-managed clusters reuse the
+// management client, any other cluster is reached through the kubeconfig secret generated by CAPI.
+func NewWorkloadClusterClients(c client.Client, log logr.Logger) WorkloadClusterClients {
+	return func(ctx context.Context, cs *anywherev1.Cluster) (ResourceFetcher, ResourceUpdater, error) {
+		if cs.IsSelfManaged() {
+			return NewCAPIResourceFetcher(c, log), NewCAPIResourceUpdater(c, log), nil
+		}
+
+		workloadClient, err := remote.NewClusterClient(ctx, workloadClientSourceName, c, client.ObjectKey{Namespace: constants.EksaSystemNamespace, Name: cs.Name})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return NewCAPIResourceFetcher(workloadClient, log), NewCAPIResourceUpdater(workloadClient, log), nil
+	}
+}
+
+type loadBalancerReconciler struct {
+	Log logr.Logger
+	ResourceFetcher
+	workloadClients WorkloadClusterClients
+	loadBalancer    LoadBalancerManifestGenerator
+}
+
+func NewLoadBalancerReconciler(resourceFetcher ResourceFetcher, workloadClients WorkloadClusterClients, loadBalancer LoadBalancerManifestGenerator, log logr.Logger) *loadBalancerReconciler {
+	return &loadBalancerReconciler{
+		Log:             log,
+		ResourceFetcher: resourceFetcher,
+		workloadClients: workloadClients,
+		loadBalancer:    loadBalancer,
+	}
+}
+
+func (lbr *loadBalancerReconciler) Reconcile(ctx context.Context, objectKey types.NamespacedName, dryRun bool) error {
+	cs, err := lbr.FetchCluster(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	if cs.Spec.LoadBalancer == nil {
+		return nil
+	}
+
+	spec, err := lbr.FetchAppliedSpec(ctx, cs)
+	if err != nil {
+		return err
+	}
+	content, err := lbr.loadBalancer.GenerateManifest(spec)
+	if err != nil {
+		return fmt.Errorf("error generating load balancer manifest: %v", err)
+	}
+	resources, err := manifestResources(content)
+	if err != nil {
+		return err
+	}
+
+	// The metallb objects live in a namespace created by the same manifest, which makes a dry run
+	// against a cluster without it fail. Objects are only applied once the dry run is over.
+	if dryRun {
+		return nil
+	}
+
+	fetcher, updater, err := lbr.workloadClients(ctx, cs)
+	if err != nil {
+		return fmt.Errorf("error building client for cluster %s: %v", cs.Name, err)
+	}
+
+	return applyTemplates(ctx, lbr.Log, fetcher, updater, resources, dryRun)
+}
+
+func manifestResources(content []byte) ([]*unstructured.Unstructured, error) {
+	var resources []*unstructured.Unstructured
+	for _, template := range strings.Split(string(content), anywherev1.YamlSeparator) {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(template), u); err != nil {
+			return nil, fmt.Errorf("invalid object in manifest: %v", err)
+		}
+		if u.Object == nil {
+			continue
+		}
+		resources = append(resources, u)
+	}
+	return resources, nil
+}
@@ -0,0 +1,117 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-anywhere/controllers/controllers/resource"
+	"github.com/aws/eks-anywhere/controllers/controllers/resource/mocks"
+	"github.com/aws/eks-anywhere/internal/test"
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/loadbalancer"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+type loadBalancerTest struct {
+	*WithT
+	ctx            context.Context
+	fetcher        *mocks.MockResourceFetcher
+	workloadFetch  *mocks.MockResourceFetcher
+	workloadUpdate *mocks.MockResourceUpdater
+	cluster        *anywherev1.Cluster
+	clusterSpec    *cluster.Spec
+	objectKey      types.NamespacedName
+	reconciler     resource.Reconciler
+}
+
+func newLoadBalancerTest(t *testing.T) *loadBalancerTest {
+	mockCtrl := gomock.NewController(t)
+	tt := &loadBalancerTest{
+		WithT:          NewWithT(t),
+		ctx:            context.Background(),
+		fetcher:        mocks.NewMockResourceFetcher(mockCtrl),
+		workloadFetch:  mocks.NewMockResourceFetcher(mockCtrl),
+		workloadUpdate: mocks.NewMockResourceUpdater(mockCtrl),
+		objectKey:      types.NamespacedName{Name: "workload", Namespace: "default"},
+	}
+	tt.cluster = &anywherev1.Cluster{}
+	tt.cluster.SetName(tt.objectKey.Name)
+	tt.cluster.SetNamespace(tt.objectKey.Namespace)
+	tt.cluster.Spec.LoadBalancer = &anywherev1.LoadBalancerConfiguration{
+		Provider:     anywherev1.MetalLBLoadBalancer,
+		AddressPools: []anywherev1.LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.0.0.10-10.0.0.20"}}},
+	}
+	tt.clusterSpec = test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Cluster = tt.cluster
+		s.VersionsBundle.LoadBalancer.MetalLBController = v1alpha1.Image{URI: "public.ecr.aws/metallb/controller:v0.10.3-eks-a-1"}
+		s.VersionsBundle.LoadBalancer.MetalLBSpeaker = v1alpha1.Image{URI: "public.ecr.aws/metallb/speaker:v0.10.3-eks-a-1"}
+	})
+	workloadClients := func(ctx context.Context, cs *anywherev1.Cluster) (resource.ResourceFetcher, resource.ResourceUpdater, error) {
+		return tt.workloadFetch, tt.workloadUpdate, nil
+	}
+	tt.reconciler = resource.NewLoadBalancerReconciler(tt.fetcher, workloadClients, loadbalancer.New(), log.Log)
+
+	return tt
+}
+
+func TestLoadBalancerReconcilerReconcileApply(t *testing.T) {
+	tt := newLoadBalancerTest(t)
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "testgroup", Resource: "testresource"}, "")
+	var applied []string
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.cluster).Return(tt.clusterSpec, nil)
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, "config", "metallb-system", "ConfigMap", "v1").Return(&unstructured.Unstructured{}, nil)
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, notFound).AnyTimes()
+	tt.workloadUpdate.EXPECT().ForceApplyTemplate(tt.ctx, gomock.Any(), false).Do(func(ctx context.Context, template *unstructured.Unstructured, dryRun bool) {
+		applied = append(applied, template.GetKind()+"/"+template.GetName())
+	}).Return(nil).AnyTimes()
+	tt.workloadUpdate.EXPECT().ApplyUpdatedTemplate(tt.ctx, gomock.Any(), false).Do(func(ctx context.Context, template *unstructured.Unstructured, dryRun bool) {
+		applied = append(applied, template.GetKind()+"/"+template.GetName())
+	}).Return(nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(applied).To(HaveLen(12))
+	tt.Expect(applied[0]).To(Equal("Namespace/metallb-system"))
+	tt.Expect(applied[len(applied)-1]).To(Equal("ConfigMap/config"))
+}
+
+func TestLoadBalancerReconcilerReconcileDryRun(t *testing.T) {
+	tt := newLoadBalancerTest(t)
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.cluster).Return(tt.clusterSpec, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, true)).To(Succeed())
+}
+
+func TestLoadBalancerReconcilerReconcileNotConfigured(t *testing.T) {
+	tt := newLoadBalancerTest(t)
+	tt.cluster.Spec.LoadBalancer = nil
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestLoadBalancerReconcilerReconcileApplyError(t *testing.T) {
+	tt := newLoadBalancerTest(t)
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "testgroup", Resource: "testresource"}, "")
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.cluster).Return(tt.clusterSpec, nil)
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, notFound)
+	tt.workloadUpdate.EXPECT().ForceApplyTemplate(tt.ctx, gomock.Any(), false).Return(errors.New("error applying"))
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(MatchError("error applying"))
+}
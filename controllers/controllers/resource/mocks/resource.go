@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/aws/eks-anywhere/controllers/controllers/resource (interfaces: ResourceFetcher,ResourceUpdater)
+// Source: github.com/aws/eks-anywhere/controllers/controllers/resource (interfaces: ResourceFetcher,ResourceUpdater,CAPIUpgrader)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -10,10 +10,12 @@ import (
 
 	v1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	cluster "github.com/aws/eks-anywhere/pkg/cluster"
+	providers "github.com/aws/eks-anywhere/pkg/providers"
+	types "github.com/aws/eks-anywhere/pkg/types"
 	gomock "github.com/golang/mock/gomock"
 	v1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	types "k8s.io/apimachinery/pkg/types"
+	types0 "k8s.io/apimachinery/pkg/types"
 	v1alpha30 "sigs.k8s.io/cluster-api-provider-vsphere/api/v1alpha3"
 	v1alpha31 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	v1alpha32 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
@@ -179,7 +181,7 @@ func (mr *MockResourceFetcherMockRecorder) FetchAppliedSpec(arg0, arg1 interface
 }
 
 // FetchCluster mocks base method.
-func (m *MockResourceFetcher) FetchCluster(arg0 context.Context, arg1 types.NamespacedName) (*v1alpha1.Cluster, error) {
+func (m *MockResourceFetcher) FetchCluster(arg0 context.Context, arg1 types0.NamespacedName) (*v1alpha1.Cluster, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FetchCluster", arg0, arg1)
 	ret0, _ := ret[0].(*v1alpha1.Cluster)
@@ -194,7 +196,7 @@ func (mr *MockResourceFetcherMockRecorder) FetchCluster(arg0, arg1 interface{})
 }
 
 // FetchObject mocks base method.
-func (m *MockResourceFetcher) FetchObject(arg0 context.Context, arg1 types.NamespacedName, arg2 client.Object) error {
+func (m *MockResourceFetcher) FetchObject(arg0 context.Context, arg1 types0.NamespacedName, arg2 client.Object) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FetchObject", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
@@ -373,3 +375,41 @@ func (mr *MockResourceUpdaterMockRecorder) UpdateTemplate(arg0, arg1 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockResourceUpdater)(nil).UpdateTemplate), arg0, arg1)
 }
+
+// MockCAPIUpgrader is a mock of CAPIUpgrader interface.
+type MockCAPIUpgrader struct {
+	ctrl     *gomock.Controller
+	recorder *MockCAPIUpgraderMockRecorder
+}
+
+// MockCAPIUpgraderMockRecorder is the mock recorder for MockCAPIUpgrader.
+type MockCAPIUpgraderMockRecorder struct {
+	mock *MockCAPIUpgrader
+}
+
+// NewMockCAPIUpgrader creates a new mock instance.
+func NewMockCAPIUpgrader(ctrl *gomock.Controller) *MockCAPIUpgrader {
+	mock := &MockCAPIUpgrader{ctrl: ctrl}
+	mock.recorder = &MockCAPIUpgraderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCAPIUpgrader) EXPECT() *MockCAPIUpgraderMockRecorder {
+	return m.recorder
+}
+
+// Upgrade mocks base method.
+func (m *MockCAPIUpgrader) Upgrade(arg0 context.Context, arg1 *types.Cluster, arg2 providers.Provider, arg3, arg4 *cluster.Spec) (*types.ChangeDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upgrade", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*types.ChangeDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upgrade indicates an expected call of Upgrade.
+func (mr *MockCAPIUpgraderMockRecorder) Upgrade(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upgrade", reflect.TypeOf((*MockCAPIUpgrader)(nil).Upgrade), arg0, arg1, arg2, arg3, arg4)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -12,6 +13,7 @@ import (
 
 	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	anywhereTypes "github.com/aws/eks-anywhere/pkg/types"
 )
 
@@ -62,6 +64,10 @@ func (cor *clusterReconciler) Reconcile(ctx context.Context, objectKey types.Nam
 	if err != nil {
 		return err
 	}
+	err = cor.fetchRegistryMirrorCredentials(ctx, spec, objectKey.Namespace)
+	if err != nil {
+		return err
+	}
 
 	switch cs.Spec.DatacenterRef.Kind {
 	case anywherev1.VSphereDatacenterKind:
@@ -116,24 +122,33 @@ func (cor *clusterReconciler) Reconcile(ctx context.Context, objectKey types.Nam
 			resources = append(resources, r...)
 		}
 	}
+
+	resources, err = holdWorkersUpgrade(ctx, cor.Log, cor.ResourceFetcher, cs, spec, resources)
+	if err != nil {
+		return err
+	}
 	return cor.applyTemplates(ctx, resources, dryRun)
 }
 
 func (cor *clusterReconciler) applyTemplates(ctx context.Context, resources []*unstructured.Unstructured, dryRun bool) error {
+	return applyTemplates(ctx, cor.Log, cor.ResourceFetcher, cor.ResourceUpdater, resources, dryRun)
+}
+
+func applyTemplates(ctx context.Context, log logr.Logger, fetcher ResourceFetcher, updater ResourceUpdater, resources []*unstructured.Unstructured, dryRun bool) error {
 	for _, resource := range resources {
 		kind := resource.GetKind()
 		name := resource.GetName()
-		cor.Log.Info("applying object", "kind", kind, "name", name, "dryRun", dryRun)
-		fetch, err := cor.Fetch(ctx, resource.GetName(), resource.GetNamespace(), resource.GetKind(), resource.GetAPIVersion())
+		log.Info("applying object", "kind", kind, "name", name, "dryRun", dryRun)
+		fetch, err := fetcher.Fetch(ctx, resource.GetName(), resource.GetNamespace(), resource.GetKind(), resource.GetAPIVersion())
 		if err == nil {
 			resource.SetResourceVersion(fetch.GetResourceVersion())
-			if err := cor.ApplyUpdatedTemplate(ctx, resource, dryRun); err != nil {
+			if err := updater.ApplyUpdatedTemplate(ctx, resource, dryRun); err != nil {
 				return err
 			}
 			continue
 		}
 		if statusError, isStatus := err.(*errors.StatusError); isStatus && statusError.Status().Reason == metav1.StatusReasonNotFound {
-			if err := cor.ForceApplyTemplate(ctx, resource, dryRun); err != nil {
+			if err := updater.ForceApplyTemplate(ctx, resource, dryRun); err != nil {
 				return err
 			}
 			continue
@@ -162,3 +177,24 @@ func (cor *clusterReconciler) fetchIdentityProviderRefs(ctx context.Context, cs
 	}
 	return nil
 }
+
+func (cor *clusterReconciler) fetchRegistryMirrorCredentials(ctx context.Context, cs *cluster.Spec, namespace string) error {
+	mirrorConfig := cs.Spec.RegistryMirrorConfiguration
+	if mirrorConfig == nil || mirrorConfig.Authentication == nil || mirrorConfig.Authentication.CredentialsSecretRef == nil {
+		return nil
+	}
+	ref := mirrorConfig.Authentication.CredentialsSecretRef
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := cor.FetchObject(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return fmt.Errorf("error getting registry mirror credentials secret %s/%s: %v", namespace, ref.Name, err)
+	}
+	credentials, err := registrymirror.CredentialsFromSecret(secret)
+	if err != nil {
+		return err
+	}
+	cs.RegistryMirrorCredentials = credentials
+	return nil
+}
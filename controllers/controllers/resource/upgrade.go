@@ -0,0 +1,242 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/templater"
+)
+
+const (
+	machineDeploymentKind     = "MachineDeployment"
+	kubeadmConfigTemplateKind = "KubeadmConfigTemplate"
+)
+
+type NetworkingManifestGenerator interface {
+	GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error)
+}
+
+// upgradeReconciler completes Kubernetes version and bundle upgrades of workload clusters. The new CAPI
+// objects are applied by the cluster reconciler, which holds the workers until the control plane is upgraded.
+// Once that happens, this reconciler upgrades the components running inside the workload cluster.
+type upgradeReconciler struct {
+	Log logr.Logger
+	ResourceFetcher
+	workloadClients WorkloadClusterClients
+	networking      NetworkingManifestGenerator
+}
+
+func NewUpgradeReconciler(resourceFetcher ResourceFetcher, workloadClients WorkloadClusterClients, networking NetworkingManifestGenerator, log logr.Logger) *upgradeReconciler {
+	return &upgradeReconciler{
+		Log:             log,
+		ResourceFetcher: resourceFetcher,
+		workloadClients: workloadClients,
+		networking:      networking,
+	}
+}
+
+func (ur *upgradeReconciler) Reconcile(ctx context.Context, objectKey types.NamespacedName, dryRun bool) error {
+	cs, err := ur.FetchCluster(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	// Upgrading a management cluster requires upgrading the CAPI providers and the controller itself,
+	// this is still done by the CLI
+	if cs.IsSelfManaged() {
+		ur.Log.Info("skipping upgrade reconcile for management cluster", "cluster", cs.Name)
+		return nil
+	}
+
+	paused, err := capiClusterPaused(ctx, ur.ResourceFetcher, cs)
+	if err != nil {
+		return err
+	}
+	if paused {
+		ur.Log.Info("skipping upgrade reconcile, CAPI cluster is paused", "cluster", cs.Name)
+		return nil
+	}
+
+	spec, err := ur.FetchAppliedSpec(ctx, cs)
+	if err != nil {
+		return err
+	}
+
+	kcp, err := ur.ControlPlane(ctx, cs)
+	if err != nil {
+		return err
+	}
+	if !controlPlaneUpgraded(kcp, spec) {
+		ur.Log.Info("waiting for control plane to be upgraded", "cluster", cs.Name, "kubernetesVersion", spec.VersionsBundle.KubeDistro.Kubernetes.Tag)
+		return nil
+	}
+
+	// Same as the load balancer, objects in the workload cluster are only changed once the dry run is over
+	if dryRun {
+		return nil
+	}
+
+	fetcher, updater, err := ur.workloadClients(ctx, cs)
+	if err != nil {
+		return fmt.Errorf("error building client for cluster %s: %v", cs.Name, err)
+	}
+
+	if err = ur.upgradeNetworking(ctx, fetcher, updater, spec); err != nil {
+		return err
+	}
+
+	return ur.applyExtraObjects(ctx, fetcher, updater, spec)
+}
+
+func capiClusterPaused(ctx context.Context, fetcher ResourceFetcher, cs *anywherev1.Cluster) (bool, error) {
+	capiCluster := &clusterv1.Cluster{}
+	if err := fetcher.FetchObjectByName(ctx, cs.Name, constants.EksaSystemNamespace, capiCluster); err != nil {
+		return false, err
+	}
+	_, pausedAnnotation := capiCluster.Annotations[clusterv1.PausedAnnotation]
+	return capiCluster.Spec.Paused || pausedAnnotation, nil
+}
+
+// controlPlaneUpgraded returns true once all the control plane machines run the Kubernetes version from the spec
+func controlPlaneUpgraded(kcp *kubeadmnv1alpha3.KubeadmControlPlane, spec *cluster.Spec) bool {
+	return kcp.Spec.Version == spec.VersionsBundle.KubeDistro.Kubernetes.Tag &&
+		kcp.Status.ObservedGeneration == kcp.Generation &&
+		kcp.Status.Ready &&
+		kcp.Status.UnavailableReplicas == 0 &&
+		kcp.Status.UpdatedReplicas == kcp.Status.Replicas
+}
+
+// upgradeNetworking applies the objects of the rendered networking manifest that differ from the ones running
+// in the cluster, so both Cilium upgrades and Cilium configuration changes are rolled out
+func (ur *upgradeReconciler) upgradeNetworking(ctx context.Context, fetcher ResourceFetcher, updater ResourceUpdater, spec *cluster.Spec) error {
+	content, err := ur.networking.GenerateManifest(spec)
+	if err != nil {
+		return fmt.Errorf("error generating networking manifest: %v", err)
+	}
+	resources, err := manifestResources(content)
+	if err != nil {
+		return err
+	}
+
+	return applyChangedTemplates(ctx, ur.Log, fetcher, updater, resources)
+}
+
+// applyExtraObjects applies the same objects the CLI applies after a control plane upgrade, like the
+// extra CoreDNS permissions needed by some of the CoreDNS versions installed by kubeadm, the managed
+// Corefile and NodeLocal DNSCache
+func (ur *upgradeReconciler) applyExtraObjects(ctx context.Context, fetcher ResourceFetcher, updater ResourceUpdater, spec *cluster.Spec) error {
+	extraObjects, err := cluster.BuildExtraObjects(spec)
+	if err != nil {
+		return err
+	}
+	if len(extraObjects) == 0 {
+		return nil
+	}
+
+	resources, err := manifestResources(templater.AppendYamlResources(extraObjects.Values()...))
+	if err != nil {
+		return err
+	}
+
+	return applyChangedTemplates(ctx, ur.Log, fetcher, updater, resources)
+}
+
+// applyChangedTemplates creates the missing objects and updates the ones that differ from the rendered
+// templates. Objects already matching their template are left untouched so reconciling an up to date
+// cluster doesn't write to it
+func applyChangedTemplates(ctx context.Context, log logr.Logger, fetcher ResourceFetcher, updater ResourceUpdater, resources []*unstructured.Unstructured) error {
+	for _, resource := range resources {
+		current, err := fetcher.Fetch(ctx, resource.GetName(), resource.GetNamespace(), resource.GetKind(), resource.GetAPIVersion())
+		if apierrors.IsNotFound(err) {
+			log.Info("creating object", "kind", resource.GetKind(), "name", resource.GetName())
+			if err = updater.ForceApplyTemplate(ctx, resource, false); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if templateApplied(resource, current) {
+			continue
+		}
+
+		log.Info("updating object", "kind", resource.GetKind(), "name", resource.GetName())
+		resource.SetResourceVersion(current.GetResourceVersion())
+		if err = updater.ApplyUpdatedTemplate(ctx, resource, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateApplied returns true when every field set in the template has the same value in the current object.
+// Fields only set in the current object are defaulted by the API server and ignored, except for the data of
+// ConfigMaps and Secrets, where a key removed from the template has to be removed from the object
+func templateApplied(template, current *unstructured.Unstructured) bool {
+	templateData, _, _ := unstructured.NestedFieldNoCopy(template.Object, "data")
+	currentData, _, _ := unstructured.NestedFieldNoCopy(current.Object, "data")
+	if !equality.Semantic.DeepEqual(templateData, currentData) {
+		return false
+	}
+
+	return equality.Semantic.DeepDerivative(template.Object, current.Object)
+}
+
+// holdWorkersUpgrade removes the worker objects from resources when they change the Kubernetes version of the
+// workers and the control plane hasn't been upgraded yet, so workers are upgraded after the control plane
+func holdWorkersUpgrade(ctx context.Context, log logr.Logger, fetcher ResourceFetcher, cs *anywherev1.Cluster, spec *cluster.Spec, resources []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	upgradingWorkers := false
+	for _, r := range resources {
+		if r.GetKind() != machineDeploymentKind {
+			continue
+		}
+		current, err := fetcher.Fetch(ctx, r.GetName(), r.GetNamespace(), r.GetKind(), r.GetAPIVersion())
+		if apierrors.IsNotFound(err) {
+			// A new MachineDeployment doesn't upgrade any machine
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if machineDeploymentVersion(current) != machineDeploymentVersion(r) {
+			upgradingWorkers = true
+		}
+	}
+	if !upgradingWorkers {
+		return resources, nil
+	}
+
+	kcp, err := fetcher.ControlPlane(ctx, cs)
+	if err != nil {
+		return nil, err
+	}
+	if controlPlaneUpgraded(kcp, spec) {
+		return resources, nil
+	}
+
+	log.Info("holding workers upgrade until the control plane is upgraded", "cluster", cs.Name)
+	held := make([]*unstructured.Unstructured, 0, len(resources))
+	for _, r := range resources {
+		if r.GetKind() == machineDeploymentKind || r.GetKind() == kubeadmConfigTemplateKind {
+			continue
+		}
+		held = append(held, r)
+	}
+	return held, nil
+}
+
+func machineDeploymentVersion(md *unstructured.Unstructured) string {
+	version, _, _ := unstructured.NestedString(md.Object, "spec", "template", "spec", "version")
+	return version
+}
@@ -0,0 +1,325 @@
+package resource_test
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/controllers/controllers/resource"
+	"github.com/aws/eks-anywhere/controllers/controllers/resource/mocks"
+	"github.com/aws/eks-anywhere/internal/test"
+	anywherev1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/networking"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const (
+	kubernetesVersion = "v1.21.2-eks-1-21-4"
+	ciliumImage       = "public.ecr.aws/isovalent/cilium:v1.9.11-eksa.1"
+)
+
+type upgradeTest struct {
+	*WithT
+	ctx            context.Context
+	fetcher        *mocks.MockResourceFetcher
+	workloadFetch  *mocks.MockResourceFetcher
+	workloadUpdate *mocks.MockResourceUpdater
+	cluster        *anywherev1.Cluster
+	clusterSpec    *cluster.Spec
+	capiCluster    *clusterv1.Cluster
+	controlPlane   *kubeadmnv1alpha3.KubeadmControlPlane
+	objectKey      types.NamespacedName
+	reconciler     resource.Reconciler
+}
+
+func newUpgradeTest(t *testing.T) *upgradeTest {
+	mockCtrl := gomock.NewController(t)
+	tt := &upgradeTest{
+		WithT:          NewWithT(t),
+		ctx:            context.Background(),
+		fetcher:        mocks.NewMockResourceFetcher(mockCtrl),
+		workloadFetch:  mocks.NewMockResourceFetcher(mockCtrl),
+		workloadUpdate: mocks.NewMockResourceUpdater(mockCtrl),
+		objectKey:      types.NamespacedName{Name: "workload", Namespace: "default"},
+		capiCluster:    &clusterv1.Cluster{},
+	}
+	tt.cluster = &anywherev1.Cluster{}
+	tt.cluster.SetName(tt.objectKey.Name)
+	tt.cluster.SetNamespace(tt.objectKey.Namespace)
+	tt.cluster.SetManagedBy("management")
+	tt.cluster.Spec.DatacenterRef.Kind = anywherev1.VSphereDatacenterKind
+	tt.clusterSpec = test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Cluster = tt.cluster
+		s.Bundles.Spec.Number = 2
+		s.VersionsBundle.KubeDistro.Kubernetes.Tag = kubernetesVersion
+		s.VersionsBundle.ClusterAPI.Version = "v0.3.23"
+		s.VersionsBundle.VSphere.Version = "v0.7.10"
+		s.VersionsBundle.Cilium.Version = "v1.9.11-eksa.1"
+		s.VersionsBundle.Cilium.Cilium = v1alpha1.Image{URI: ciliumImage}
+		s.VersionsBundle.Cilium.Manifest = v1alpha1.Manifest{URI: "testdata/cilium.yaml"}
+	})
+
+	tt.controlPlane = &kubeadmnv1alpha3.KubeadmControlPlane{
+		Spec: kubeadmnv1alpha3.KubeadmControlPlaneSpec{Version: kubernetesVersion},
+		Status: kubeadmnv1alpha3.KubeadmControlPlaneStatus{
+			Ready:           true,
+			Replicas:        3,
+			UpdatedReplicas: 3,
+		},
+	}
+
+	workloadClients := func(ctx context.Context, cs *anywherev1.Cluster) (resource.ResourceFetcher, resource.ResourceUpdater, error) {
+		return tt.workloadFetch, tt.workloadUpdate, nil
+	}
+	tt.reconciler = resource.NewUpgradeReconciler(tt.fetcher, workloadClients, networking.NewCilium(), log.Log)
+
+	return tt
+}
+
+func (tt *upgradeTest) expectFetchClusters() {
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+	tt.fetcher.EXPECT().FetchObjectByName(tt.ctx, "workload", "eksa-system", gomock.AssignableToTypeOf(&clusterv1.Cluster{})).DoAndReturn(
+		func(ctx context.Context, name, namespace string, obj client.Object) error {
+			tt.capiCluster.DeepCopyInto(obj.(*clusterv1.Cluster))
+			return nil
+		},
+	)
+}
+
+func (tt *upgradeTest) expectFetchSpecs() {
+	tt.fetcher.EXPECT().FetchAppliedSpec(tt.ctx, tt.cluster).Return(tt.clusterSpec, nil)
+}
+
+// expectCiliumObjects returns the objects from the cilium manifest as the ones running in the workload cluster
+func (tt *upgradeTest) expectCiliumObjects(manifest string) {
+	for _, o := range tt.manifestObjects(manifest) {
+		tt.workloadFetch.EXPECT().Fetch(tt.ctx, o.GetName(), o.GetNamespace(), o.GetKind(), o.GetAPIVersion()).Return(o, nil)
+	}
+}
+
+func (tt *upgradeTest) manifestObjects(manifest string) []*unstructured.Unstructured {
+	var objects []*unstructured.Unstructured
+	for _, template := range strings.Split(manifest, "---") {
+		o := &unstructured.Unstructured{}
+		tt.Expect(yaml.Unmarshal([]byte(template), o)).To(Succeed())
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+func (tt *upgradeTest) expectApplied(applied *[]string) {
+	record := func(ctx context.Context, template *unstructured.Unstructured, dryRun bool) {
+		*applied = append(*applied, template.GetKind()+"/"+template.GetName())
+	}
+	tt.workloadUpdate.EXPECT().ForceApplyTemplate(tt.ctx, gomock.Any(), false).Do(record).Return(nil).AnyTimes()
+	tt.workloadUpdate.EXPECT().ApplyUpdatedTemplate(tt.ctx, gomock.Any(), false).Do(record).Return(nil).AnyTimes()
+}
+
+func TestUpgradeReconcilerReconcileUpgradesCilium(t *testing.T) {
+	tt := newUpgradeTest(t)
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "testgroup", Resource: "testresource"}, "")
+	var applied []string
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, "cilium", "kube-system", "ServiceAccount", "v1").Return(nil, notFound)
+	oldDaemonSet := tt.manifestObjects(ciliumManifest(t))[1]
+	tt.Expect(unstructured.SetNestedSlice(oldDaemonSet.Object, []interface{}{
+		map[string]interface{}{"name": "cilium-agent", "image": "public.ecr.aws/isovalent/cilium:v1.9.10-eksa.1"},
+	}, "spec", "template", "spec", "containers")).To(Succeed())
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, "cilium", "kube-system", "DaemonSet", "apps/v1").Return(oldDaemonSet, nil)
+	tt.expectApplied(&applied)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(applied).To(Equal([]string{"ServiceAccount/cilium", "DaemonSet/cilium"}))
+}
+
+func TestUpgradeReconcilerReconcileUpdatesCiliumConfig(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.clusterSpec.VersionsBundle.Cilium.Manifest = v1alpha1.Manifest{URI: "testdata/cilium_config.yaml"}
+	tt.clusterSpec.Spec.ClusterNetwork.Cilium = &anywherev1.CiliumConfig{PolicyEnforcementMode: "always"}
+	var applied []string
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+	// The running objects come from the manifest without the cluster cilium configuration
+	for _, o := range tt.manifestObjects(readFile(t, "testdata/cilium_config.yaml")) {
+		o := o
+		tt.workloadFetch.EXPECT().Fetch(tt.ctx, o.GetName(), o.GetNamespace(), o.GetKind(), o.GetAPIVersion()).Return(o, nil)
+	}
+	tt.expectApplied(&applied)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	// The agents are rolled through the config checksum annotation in their pod template
+	tt.Expect(applied).To(Equal([]string{"ConfigMap/cilium-config", "DaemonSet/cilium"}))
+}
+
+func TestUpgradeReconcilerReconcileAppliesExtraObjects(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.clusterSpec.VersionsBundle.KubeVersion = "1.21"
+	tt.clusterSpec.VersionsBundle.KubeDistro.CoreDNS.Tag = "v1.8.3-eks-1-21-4"
+	var applied []string
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+	tt.expectCiliumObjects(ciliumManifest(t))
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, "system:coredns", "", "ClusterRole", "rbac.authorization.k8s.io/v1").Return(&unstructured.Unstructured{}, nil)
+	tt.expectApplied(&applied)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(applied).To(Equal([]string{"ClusterRole/system:coredns"}))
+}
+
+func TestUpgradeReconcilerReconcileAppliesDNSObjects(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks = []string{"10.96.0.0/12"}
+	tt.clusterSpec.Spec.ClusterNetwork.DNS = &anywherev1.DNS{
+		UpstreamServers: []string{"10.0.0.2"},
+		NodeLocalDNS:    &anywherev1.NodeLocalDNS{Enabled: true},
+	}
+	tt.clusterSpec.VersionsBundle.NodeLocalDNS.Image = v1alpha1.Image{URI: "public.ecr.aws/eks-distro/kubernetes/dns/k8s-dns-node-cache:1.21.1"}
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "testgroup", Resource: "testresource"}, "")
+	var applied []string
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+	tt.expectCiliumObjects(ciliumManifest(t))
+	corefile := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"Corefile": ".:53 {\n    forward . /etc/resolv.conf\n}\n"},
+	}}
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, "coredns", "kube-system", "ConfigMap", "v1").Return(corefile, nil)
+	tt.workloadFetch.EXPECT().Fetch(tt.ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, notFound).AnyTimes()
+	tt.expectApplied(&applied)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+	tt.Expect(applied).To(ContainElements("ConfigMap/coredns", "DaemonSet/node-local-dns"))
+}
+
+func TestUpgradeReconcilerReconcileNothingToUpgrade(t *testing.T) {
+	tt := newUpgradeTest(t)
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+	tt.expectCiliumObjects(ciliumManifest(t))
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func ciliumManifest(t *testing.T) string {
+	return readFile(t, "testdata/cilium.yaml")
+}
+
+func readFile(t *testing.T, file string) string {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed reading %s: %v", file, err)
+	}
+	return string(content)
+}
+
+func TestUpgradeReconcilerReconcileWaitingForControlPlane(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.controlPlane.Status.UpdatedReplicas = 1
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestUpgradeReconcilerReconcileDryRun(t *testing.T) {
+	tt := newUpgradeTest(t)
+
+	tt.expectFetchClusters()
+	tt.expectFetchSpecs()
+	tt.fetcher.EXPECT().ControlPlane(tt.ctx, tt.cluster).Return(tt.controlPlane, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, true)).To(Succeed())
+}
+
+func TestUpgradeReconcilerReconcileCAPIClusterPaused(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.capiCluster.Annotations = map[string]string{clusterv1.PausedAnnotation: "true"}
+
+	tt.expectFetchClusters()
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestUpgradeReconcilerReconcileManagementCluster(t *testing.T) {
+	tt := newUpgradeTest(t)
+	tt.cluster.SetSelfManaged()
+
+	tt.fetcher.EXPECT().FetchCluster(tt.ctx, tt.objectKey).Return(tt.cluster, nil)
+
+	tt.Expect(tt.reconciler.Reconcile(tt.ctx, tt.objectKey, false)).To(Succeed())
+}
+
+func TestClusterReconcilerHoldsWorkersUpgrade(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	fetcher := mocks.NewMockResourceFetcher(mockCtrl)
+	updater := mocks.NewMockResourceUpdater(mockCtrl)
+	objectKey := types.NamespacedName{Name: "workload", Namespace: "default"}
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "testgroup", Resource: "testresource"}, "")
+
+	cs := &anywherev1.Cluster{}
+	cs.SetName(objectKey.Name)
+	cs.SetNamespace(objectKey.Namespace)
+	cs.Spec.DatacenterRef.Kind = anywherev1.DockerDatacenterKind
+	spec := test.NewFullClusterSpec(t, "testdata/eksa-cluster.yaml")
+	newVersion := spec.VersionsBundle.KubeDistro.Kubernetes.Tag
+
+	md := &clusterv1.MachineDeployment{}
+	md.Spec.Template.Spec.InfrastructureRef.Name = "workload-worker-node-template"
+	kcp := &kubeadmnv1alpha3.KubeadmControlPlane{Spec: kubeadmnv1alpha3.KubeadmControlPlaneSpec{Version: newVersion}}
+	kcp.Spec.InfrastructureTemplate.Name = "workload-control-plane-template"
+	kcp.Status.Replicas = 3
+	kcp.Status.UpdatedReplicas = 1
+
+	fetcher.EXPECT().FetchCluster(ctx, objectKey).Return(cs, nil)
+	fetcher.EXPECT().FetchAppliedSpec(ctx, cs).Return(spec, nil)
+	fetcher.EXPECT().MachineDeployment(ctx, cs).Return(md, nil)
+	fetcher.EXPECT().ControlPlane(ctx, cs).Return(kcp, nil).Times(2)
+	fetcher.EXPECT().Fetch(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, name, namespace, kind, apiVersion string) (*unstructured.Unstructured, error) {
+			if kind != "MachineDeployment" {
+				return nil, notFound
+			}
+			current := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			g.Expect(unstructured.SetNestedField(current.Object, "v1.20.7-eks-1-20-2", "spec", "template", "spec", "version")).To(Succeed())
+			return current, nil
+		},
+	).AnyTimes()
+
+	var applied []string
+	updater.EXPECT().ForceApplyTemplate(ctx, gomock.Any(), false).Do(func(ctx context.Context, template *unstructured.Unstructured, dryRun bool) {
+		applied = append(applied, template.GetKind())
+	}).Return(nil).AnyTimes()
+
+	g.Expect(resource.NewClusterReconciler(fetcher, updater, test.FakeNow, log.Log).Reconcile(ctx, objectKey, false)).To(Succeed())
+	g.Expect(applied).To(ContainElement("KubeadmControlPlane"))
+	g.Expect(applied).NotTo(ContainElement("MachineDeployment"))
+	g.Expect(applied).NotTo(ContainElement("KubeadmConfigTemplate"))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 
 	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
@@ -18,6 +19,10 @@ import (
 
 	"github.com/aws/eks-anywhere/controllers/controllers"
 	anywherev1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/logger"
 	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
@@ -26,7 +31,10 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
-const WEBHOOK = "webhook"
+const (
+	WEBHOOK           = "webhook"
+	controllerWorkDir = "/tmp/eks-anywhere-controller"
+)
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
@@ -40,14 +48,22 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == etcdbackup.StoreCommand {
+		runEtcdBackupStore(os.Args[2:])
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var allowMissingChecksums bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&allowMissingChecksums, "allow-missing-checksums", false,
+		"Read remote manifests that don't publish a checksum, like the ones from bundles released without them, without verifying them.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -55,6 +71,7 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	files.AllowMissingChecksums(allowMissingChecksums)
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -69,10 +86,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The clusterctl configuration and the providers overrides are written to the work dir
+	writer, err := filewriter.NewWriter(controllerWorkDir)
+	if err != nil {
+		setupLog.Error(err, "unable to create working directory")
+		os.Exit(1)
+	}
+
 	if err = (controllers.NewClusterReconciler(
 		mgr.GetClient(),
 		ctrl.Log.WithName("controllers").WithName(anywherev1alpha1.ClusterKind),
-		mgr.GetScheme())).SetupWithManager(mgr); err != nil {
+		mgr.GetScheme(),
+		writer)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", anywherev1alpha1.ClusterKind)
 		os.Exit(1)
 	}
@@ -118,3 +143,15 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runEtcdBackupStore stores the snapshot taken by the etcd backup CronJob, which runs this image with the store command
+func runEtcdBackupStore(args []string) {
+	if err := logger.InitZap(0); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing logger: %v\n", err)
+		os.Exit(1)
+	}
+	if err := etcdbackup.RunStoreCommand(ctrl.SetupSignalHandler(), args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error storing etcd snapshot: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const registryToken = "test-registry-token"
+
+// Registry is an in-memory OCI distribution registry served over TLS, for tests that push and pull images
+type Registry struct {
+	*httptest.Server
+	username string
+	password string
+	useToken bool
+
+	lock      sync.Mutex
+	manifests map[string]registryManifest
+	blobs     map[string][]byte
+	uploads   int
+	pushes    map[string]int
+}
+
+type registryManifest struct {
+	mediaType string
+	content   []byte
+}
+
+type RegistryOpt func(*Registry)
+
+// WithRegistryBasicAuth makes the registry require basic auth
+func WithRegistryBasicAuth(username, password string) RegistryOpt {
+	return func(r *Registry) {
+		r.username, r.password = username, password
+	}
+}
+
+// WithRegistryTokenAuth makes the registry require a bearer token, issued by its /token endpoint
+// in exchange for the basic auth credentials
+func WithRegistryTokenAuth(username, password string) RegistryOpt {
+	return func(r *Registry) {
+		r.username, r.password = username, password
+		r.useToken = true
+	}
+}
+
+func NewRegistry(t *testing.T, opts ...RegistryOpt) *Registry {
+	r := &Registry{
+		manifests: map[string]registryManifest{},
+		blobs:     map[string][]byte{},
+		pushes:    map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// Host returns the host:port of the registry, to be used in image references
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.URL, "https://")
+}
+
+// CACert returns the PEM encoded certificate of the registry
+func (r *Registry) CACert() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.Certificate().Raw})
+}
+
+// AddBlob stores a blob and returns its digest
+func (r *Registry) AddBlob(content []byte) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	digest := registryDigest(content)
+	r.blobs[digest] = content
+	return digest
+}
+
+// AddManifest stores a manifest under its digest and, if not empty, the tag. It returns the manifest digest.
+func (r *Registry) AddManifest(repository, tag, mediaType string, content []byte) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	digest := registryDigest(content)
+	m := registryManifest{mediaType: mediaType, content: content}
+	r.manifests[repository+"@"+digest] = m
+	if tag != "" {
+		r.manifests[repository+":"+tag] = m
+	}
+	return digest
+}
+
+// Manifest returns the content of the manifest stored for the repository and tag or digest
+func (r *Registry) Manifest(repository, reference string) ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	m, ok := r.manifests[manifestKey(repository, reference)]
+	return m.content, ok
+}
+
+// BlobUploads returns how many blobs have been pushed to the registry
+func (r *Registry) BlobUploads() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.uploads
+}
+
+// ManifestPushes returns how many times a manifest has been pushed for the repository and tag or digest
+func (r *Registry) ManifestPushes(repository, reference string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.pushes[manifestKey(repository, reference)]
+}
+
+func (r *Registry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		r.serveToken(w, req)
+		return
+	}
+	if !r.authorized(req) {
+		if r.useToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry"`, r.URL))
+		} else {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case req.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case strings.Contains(path, "/manifests/"):
+		i := strings.LastIndex(path, "/manifests/")
+		r.serveManifest(w, req, path[:i], path[i+len("/manifests/"):])
+	case strings.Contains(path, "/blobs/uploads/"):
+		r.serveUpload(w, req, path[:strings.LastIndex(path, "/blobs/uploads/")])
+	case strings.Contains(path, "/blobs/"):
+		i := strings.LastIndex(path, "/blobs/")
+		r.serveBlob(w, req, path[i+len("/blobs/"):])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (r *Registry) authorized(req *http.Request) bool {
+	if r.username == "" {
+		return true
+	}
+	if r.useToken {
+		return req.Header.Get("Authorization") == "Bearer "+registryToken
+	}
+	return r.validCredentials(req)
+}
+
+func (r *Registry) validCredentials(req *http.Request) bool {
+	username, password, ok := req.BasicAuth()
+	return ok && username == r.username && password == r.password
+}
+
+func (r *Registry) serveToken(w http.ResponseWriter, req *http.Request) {
+	if !r.validCredentials(req) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"token":"%s"}`, registryToken)
+}
+
+func (r *Registry) serveManifest(w http.ResponseWriter, req *http.Request, repository, reference string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		m, ok := r.manifests[manifestKey(repository, reference)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Docker-Content-Digest", registryDigest(m.content))
+		w.Header().Set("Content-Length", fmt.Sprint(len(m.content)))
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			w.Write(m.content)
+		}
+	case http.MethodPut:
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		digest := registryDigest(content)
+		if strings.Contains(reference, ":") && reference != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m := registryManifest{mediaType: req.Header.Get("Content-Type"), content: content}
+		r.manifests[repository+"@"+digest] = m
+		r.manifests[manifestKey(repository, reference)] = m
+		r.pushes[manifestKey(repository, reference)]++
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Registry) serveBlob(w http.ResponseWriter, req *http.Request, digest string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	content, ok := r.blobs[digest]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	w.WriteHeader(http.StatusOK)
+	if req.Method == http.MethodGet {
+		w.Write(content)
+	}
+}
+
+func (r *Registry) serveUpload(w http.ResponseWriter, req *http.Request, repository string) {
+	switch req.Method {
+	case http.MethodPost:
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", repository, base64.RawURLEncoding.EncodeToString([]byte(repository))))
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		digest := req.URL.Query().Get("digest")
+		if registryDigest(content) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.lock.Lock()
+		r.blobs[digest] = content
+		r.uploads++
+		r.lock.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func manifestKey(repository, reference string) string {
+	if strings.Contains(reference, ":") {
+		return repository + "@" + reference
+	}
+	return repository + ":" + reference
+}
+
+func registryDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/aws/eks-anywhere/pkg/git/gogit"
 	"github.com/aws/eks-anywhere/pkg/logger"
 	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/proxy"
 	"github.com/aws/eks-anywhere/pkg/retrier"
 	"github.com/aws/eks-anywhere/pkg/templater"
 	"github.com/aws/eks-anywhere/pkg/types"
@@ -50,6 +51,9 @@ const (
 	initialClusterconfigCommitMessage = "Initial commit of cluster configuration; generated by EKS-A CLI"
 	updateClusterconfigCommitMessage  = "Update commit of cluster configuration; generated by EKS-A CLI"
 	deleteClusterconfigCommitMessage  = "Delete commit of cluster configuration; generated by EKS-A CLI"
+
+	// fluxPathCheckName is the name of the validation that the cluster path doesn't exist in the repository
+	fluxPathCheckName = "flux path"
 )
 
 type FluxAddonClient struct {
@@ -272,7 +276,7 @@ func (f *FluxAddonClient) UpdateGitEksaSpec(ctx context.Context, clusterSpec *cl
 	return nil
 }
 
-func (f *FluxAddonClient) Validations(ctx context.Context, clusterSpec *cluster.Spec) []validations.Validation {
+func (f *FluxAddonClient) Validations(ctx context.Context, clusterSpec *cluster.Spec) []validations.Check {
 	if f.shouldSkipFlux() {
 		return nil
 	}
@@ -284,13 +288,16 @@ func (f *FluxAddonClient) Validations(ctx context.Context, clusterSpec *cluster.
 		clusterSpec:     clusterSpec,
 	}
 
-	return []validations.Validation{
-		func() *validations.ValidationResult {
-			return &validations.ValidationResult{
-				Name:        "Flux path",
-				Remediation: "Please provide a different path or different cluster name",
-				Err:         fc.validateRemoteConfigPathDoesNotExist(ctx),
-			}
+	return []validations.Check{
+		{
+			Name: fluxPathCheckName,
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "Flux path",
+					Remediation: "Please provide a different path or different cluster name",
+					Err:         fc.validateRemoteConfigPathDoesNotExist(ctx),
+				}
+			},
 		},
 	}
 }
@@ -523,12 +530,13 @@ func (f *FluxAddonClient) generateFluxSyncFile(t *templater.Templater) error {
 
 func (fc *fluxForCluster) generateFluxPatchFile(t *templater.Templater) error {
 	bundle := fc.clusterSpec.VersionsBundle
-	values := map[string]string{
+	values := map[string]interface{}{
 		"Namespace":                   fc.namespace(),
 		"SourceControllerImage":       bundle.Flux.SourceController.VersionedImage(),
 		"KustomizeControllerImage":    bundle.Flux.KustomizeController.VersionedImage(),
 		"HelmControllerImage":         bundle.Flux.HelmController.VersionedImage(),
 		"NotificationControllerImage": bundle.Flux.NotificationController.VersionedImage(),
+		"ProxyEnv":                    proxy.New(fc.clusterSpec.Cluster).EnvMap(),
 	}
 	if filePath, err := t.WriteToFile(fluxPatchContent, values, fluxPatchFileName, filewriter.PersistentFile); err != nil {
 		return fmt.Errorf("error creating flux-system patch manifest file into %s: %v", filePath, err)
@@ -162,6 +162,41 @@ func TestFluxAddonClientInstallGitOpsOnWorkloadClusterWithPrexistingRepo(t *test
 	}
 }
 
+func TestFluxAddonClientInstallGitOpsWithProxyConfiguration(t *testing.T) {
+	ctx := context.Background()
+	cluster := &types.Cluster{}
+	clusterName := "management-cluster"
+	clusterConfig := v1alpha1.NewCluster(clusterName)
+	clusterConfig.Spec.ProxyConfiguration = &v1alpha1.ProxyConfiguration{
+		HttpProxy:  "http://proxy.example.com:3128",
+		HttpsProxy: "http://proxy.example.com:3128",
+		NoProxy:    []string{"internal.example.com"},
+	}
+	f, m, g := newAddonClient(t)
+	clusterSpec := newClusterSpec(clusterConfig, "")
+
+	m.flux.EXPECT().BootstrapToolkitsComponents(ctx, cluster, clusterSpec.GitOpsConfig)
+
+	m.git.EXPECT().GetRepo(ctx).Return(&git.Repository{Name: clusterSpec.GitOpsConfig.Spec.Flux.Github.Repository}, nil)
+	m.git.EXPECT().Clone(ctx).Return(nil)
+	m.git.EXPECT().Branch(clusterSpec.GitOpsConfig.Spec.Flux.Github.Branch).Return(nil)
+	m.git.EXPECT().Add(path.Dir("clusters/management-cluster")).Return(nil)
+	m.git.EXPECT().Commit(test.OfType("string")).Return(nil)
+	m.git.EXPECT().Push(ctx).Return(nil)
+	m.git.EXPECT().Pull(ctx, clusterSpec.GitOpsConfig.Spec.Flux.Github.Branch).Return(nil)
+
+	datacenterConfig := datacenterConfig(clusterName)
+	machineConfig := machineConfig(clusterName)
+
+	err := f.InstallGitOps(ctx, cluster, clusterSpec, datacenterConfig, []providers.MachineConfig{machineConfig})
+	if err != nil {
+		t.Errorf("FluxAddonClient.InstallGitOps() error = %v, want nil", err)
+	}
+
+	expectedFluxPatchesPath := path.Join(g.Writer.Dir(), "clusters/management-cluster/flux-system", defaultFluxPatchesFileName)
+	test.AssertFilesEquals(t, expectedFluxPatchesPath, "./testdata/gotk-patches-proxy.yaml")
+}
+
 func TestFluxAddonClientInstallGitOpsNoPrexistingRepo(t *testing.T) {
 	tests := []struct {
 		testName                      string
@@ -637,12 +672,15 @@ func TestFluxAddonClientValidationsSuccess(t *testing.T) {
 	owner, repo, path := tt.setupFlux()
 	tt.provider.EXPECT().PathExists(tt.ctx, owner, repo, "main", path).Return(false, nil)
 
-	tt.Expect(runValidations(tt.f.Validations(tt.ctx, tt.clusterSpec))).To(Succeed())
+	checks := tt.f.Validations(tt.ctx, tt.clusterSpec)
+	tt.Expect(checks).To(HaveLen(1))
+	tt.Expect(checks[0].Name).To(Equal("flux path"))
+	tt.Expect(runValidations(checks)).To(Succeed())
 }
 
-func runValidations(validations []validations.Validation) error {
-	for _, v := range validations {
-		if err := v().Err; err != nil {
+func runValidations(checks []validations.Check) error {
+	for _, c := range checks {
+		if err := c.Validation().Err; err != nil {
 			return err
 		}
 	}
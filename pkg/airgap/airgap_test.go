@@ -0,0 +1,288 @@
+package airgap_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/airgap"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/registry"
+	"github.com/aws/eks-anywhere/pkg/version"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+var ovaContent = []byte("ubuntu ova")
+
+type airgapTest struct {
+	*WithT
+	source *test.Registry
+	mirror *test.Registry
+	server *httptest.Server
+	client *registry.Client
+	spec   *cluster.Spec
+	dir    string
+}
+
+func newAirgapTest(t *testing.T) *airgapTest {
+	tt := &airgapTest{
+		WithT:  NewWithT(t),
+		source: test.NewRegistry(t),
+		mirror: test.NewRegistry(t),
+		dir:    t.TempDir(),
+	}
+
+	tt.server = httptest.NewServer(http.HandlerFunc(tt.serveFile))
+	t.Cleanup(tt.server.Close)
+
+	client, err := registry.NewClient(registry.WithCACert(tt.source.CACert()))
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.client = client
+
+	cliTools := tt.addImage("eks-anywhere/cli-tools", "v0.6.0")
+	components, _ := tt.render("/capi/v0.3.19/components.yaml")
+	eksdRelease, _ := tt.render("/eksd/release.yaml")
+	componentsManifest := releasev1alpha1.Manifest{
+		URI:    tt.server.URL + "/capi/v0.3.19/components.yaml",
+		SHA256: fmt.Sprintf("%x", sha256.Sum256(components)),
+	}
+	bundles := &releasev1alpha1.Bundles{
+		Spec: releasev1alpha1.BundlesSpec{
+			Number: 1,
+			VersionsBundles: []releasev1alpha1.VersionsBundle{
+				{
+					KubeVersion: "1.21",
+					Eksa:        releasev1alpha1.EksaBundle{CliTools: releasev1alpha1.Image{URI: cliTools}},
+					ClusterAPI: releasev1alpha1.CoreClusterAPI{
+						Components: componentsManifest,
+						Metadata:   componentsManifest,
+					},
+					EksD: releasev1alpha1.EksDRelease{
+						EksDReleaseUrl: tt.server.URL + "/eksd/release.yaml",
+						ManifestSHA256: fmt.Sprintf("%x", sha256.Sum256(eksdRelease)),
+						Ova: releasev1alpha1.ArchiveBundle{
+							Ubuntu: releasev1alpha1.OvaArchive{
+								Archive: releasev1alpha1.Archive{
+									URI:    tt.server.URL + "/ovas/ubuntu.ova",
+									SHA256: fmt.Sprintf("%x", sha256.Sum256(ovaContent)),
+								},
+							},
+						},
+					},
+				},
+				{
+					KubeVersion: "1.20",
+					Eksa:        releasev1alpha1.EksaBundle{CliTools: releasev1alpha1.Image{URI: "public.ecr.aws/eks-anywhere/cli-tools:v0.5.0"}},
+				},
+			},
+		},
+	}
+
+	for _, asset := range []string{
+		"node-driver-registrar", "livenessprobe", "external-attacher", "external-provisioner",
+		"pause", "etcd", "aws-iam-authenticator", "coredns", "kube-apiserver",
+	} {
+		tt.addImage("eks-distro/"+asset, "v1.21.2-eks-1-21-4")
+	}
+
+	clusterConfig := v1alpha1.NewCluster("test-cluster")
+	clusterConfig.Spec.KubernetesVersion = v1alpha1.Kube121
+	tt.spec, err = cluster.BuildSpecFromBundles(clusterConfig, bundles, cluster.WithReleasesManifest(tt.server.URL+"/releases.yaml"))
+	tt.Expect(err).NotTo(HaveOccurred())
+
+	return tt
+}
+
+func (tt *airgapTest) addImage(repository, tag string) string {
+	config := []byte(fmt.Sprintf(`{"os":"linux","repository":"%s"}`, repository))
+	layer := []byte("layer for " + repository)
+	manifest, err := json.Marshal(registry.Manifest{
+		MediaType: registry.MediaTypeOCIManifest,
+		Config:    &registry.Descriptor{Digest: tt.source.AddBlob(config), Size: int64(len(config))},
+		Layers:    []registry.Descriptor{{Digest: tt.source.AddBlob(layer), Size: int64(len(layer))}},
+	})
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.source.AddManifest(repository, tag, registry.MediaTypeOCIManifest, manifest)
+	return fmt.Sprintf("%s/%s:%s", tt.source.Host(), repository, tag)
+}
+
+func (tt *airgapTest) serveFile(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/ovas/ubuntu.ova" {
+		w.Write(ovaContent)
+		return
+	}
+	if path := strings.TrimSuffix(r.URL.Path, files.PublishedChecksumSuffix); path != r.URL.Path {
+		if content, ok := tt.render(path); ok {
+			fmt.Fprintf(w, "%x  %s\n", sha256.Sum256(content), filepath.Base(path))
+			return
+		}
+	}
+	content, ok := tt.render(r.URL.Path)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(content)
+}
+
+func (tt *airgapTest) render(path string) ([]byte, bool) {
+	files := map[string]string{
+		"/releases.yaml":                "testdata/releases.yaml",
+		"/eksd/release.yaml":            "testdata/eksd-release.yaml",
+		"/capi/v0.3.19/components.yaml": "testdata/components.yaml",
+	}
+	file, ok := files[path]
+	if !ok {
+		return nil, false
+	}
+	content, err := ioutil.ReadFile(file)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tmpl := template.Must(template.New(file).Parse(string(content)))
+	out := &bytes.Buffer{}
+	tt.Expect(tmpl.Execute(out, map[string]string{"Registry": tt.source.Host(), "Server": tt.server.URL})).To(Succeed())
+	return out.Bytes(), true
+}
+
+func (tt *airgapTest) download(opts ...airgap.DownloaderOpt) (string, *airgap.Metadata) {
+	archive := filepath.Join(tt.dir, "bundle.tar.gz")
+	d := airgap.NewDownloader(files.NewReader(), tt.client, opts...)
+	metadata, err := d.Download(context.Background(), tt.spec, version.Info{GitVersion: "v0.6.0"}, filepath.Join(tt.dir, "download"), archive)
+	tt.Expect(err).NotTo(HaveOccurred())
+	return archive, metadata
+}
+
+func TestDownloadAndImportBundle(t *testing.T) {
+	tt := newAirgapTest(t)
+	archive, metadata := tt.download()
+
+	tt.Expect(metadata.Images).To(HaveLen(10))
+	tt.Expect(metadata.Manifests).To(ConsistOf("manifests/eksd/release.yaml", "manifests/capi/v0.3.19/components.yaml"))
+	tt.Expect(metadata.Ovas).To(ConsistOf("ovas/ovas/ubuntu.ova"))
+
+	// Nothing is fetched from the network once the bundle is built
+	tt.server.Close()
+
+	importDir := filepath.Join(tt.dir, "import")
+	importer := airgap.NewImporter(tt.client, 2)
+	result, err := importer.Import(context.Background(), airgap.ImportConfig{
+		ArchivePath:      archive,
+		Dir:              importDir,
+		RegistryEndpoint: tt.mirror.Host(),
+		FileServerURL:    "http://files.example.com/bundle",
+	})
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.BundlesManifest).To(Equal("http://files.example.com/bundle/imported-bundle-release.yaml"))
+
+	_, ok := tt.mirror.Manifest("eks-anywhere/cli-tools", "v0.6.0")
+	tt.Expect(ok).To(BeTrue())
+	_, ok = tt.mirror.Manifest("eks-distro/kube-apiserver", "v1.21.2-eks-1-21-4")
+	tt.Expect(ok).To(BeTrue())
+
+	bundles, err := cluster.NewManifestReader().GetBundles(filepath.Join(importDir, "imported-bundle-release.yaml"))
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(bundles.Spec.VersionsBundles).To(HaveLen(1))
+	vb := bundles.Spec.VersionsBundles[0]
+	tt.Expect(vb.Eksa.CliTools.URI).To(Equal(tt.mirror.Host() + "/eks-anywhere/cli-tools:v0.6.0"))
+	tt.Expect(vb.ClusterAPI.Components.URI).To(Equal("http://files.example.com/bundle/manifests/capi/v0.3.19/components.yaml"))
+	tt.Expect(vb.EksD.EksDReleaseUrl).To(Equal("http://files.example.com/bundle/manifests/eksd/release.yaml"))
+	tt.Expect(vb.EksD.Ova.Ubuntu.URI).To(Equal("http://files.example.com/bundle/ovas/ovas/ubuntu.ova"))
+
+	releases, err := cluster.NewManifestReader().GetReleases(filepath.Join(importDir, "imported-eks-a-release.yaml"))
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(releases.Spec.Releases[0].BundleManifestUrl).To(Equal(result.BundlesManifest))
+
+	fileServer := httptest.NewServer(http.FileServer(http.Dir(importDir)))
+	defer fileServer.Close()
+	_, err = cluster.NewManifestReader().GetReleases(fileServer.URL + "/imported-eks-a-release.yaml")
+	tt.Expect(err).NotTo(HaveOccurred(), "served imported manifests are verified against the checksums written next to them")
+	_, err = cluster.NewManifestReader().GetBundles(fileServer.URL + "/imported-bundle-release.yaml")
+	tt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestImportBundleLocalPaths(t *testing.T) {
+	tt := newAirgapTest(t)
+	archive, _ := tt.download(airgap.WithoutOvas())
+
+	importDir := filepath.Join(tt.dir, "import")
+	result, err := airgap.NewImporter(tt.client, 2).Import(context.Background(), airgap.ImportConfig{
+		ArchivePath:      archive,
+		Dir:              importDir,
+		RegistryEndpoint: tt.mirror.Host(),
+	})
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.BundlesManifest).To(Equal(filepath.Join(importDir, "imported-bundle-release.yaml")))
+
+	clusterConfig := v1alpha1.NewCluster("test-cluster")
+	clusterConfig.Spec.KubernetesVersion = v1alpha1.Kube121
+	bundles, err := cluster.NewManifestReader().GetBundles(result.BundlesManifest)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(bundles.Spec.VersionsBundles[0].EksD.Ova.Ubuntu.URI).To(HavePrefix("http://127.0.0.1"), "OVAs are kept as is when skipped")
+
+	// The imported bundle is usable to build a cluster spec without network access
+	tt.server.Close()
+	spec, err := cluster.BuildSpecFromBundles(clusterConfig, bundles)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(spec.VersionsBundle.KubeDistro.Pause.URI).To(HaveSuffix("/eks-distro/pause:v1.21.2-eks-1-21-4"))
+}
+
+func TestDownloadBundleOvaChecksumMismatch(t *testing.T) {
+	tt := newAirgapTest(t)
+	tt.spec.VersionsBundle.EksD.Ova.Ubuntu.SHA256 = strings.Repeat("0", 64)
+
+	d := airgap.NewDownloader(files.NewReader(), tt.client)
+	_, err := d.Download(context.Background(), tt.spec, version.Info{GitVersion: "v0.6.0"}, filepath.Join(tt.dir, "download"), filepath.Join(tt.dir, "bundle.tar.gz"))
+	tt.Expect(err).To(MatchError(ContainSubstring("checksum mismatch for " + tt.server.URL + "/ovas/ubuntu.ova")))
+}
+
+func TestDownloadBundleManifestChecksumMismatch(t *testing.T) {
+	tt := newAirgapTest(t)
+	tt.spec.VersionsBundle.ClusterAPI.Components.SHA256 = strings.Repeat("0", 64)
+	tt.spec.VersionsBundle.ClusterAPI.Metadata.SHA256 = strings.Repeat("0", 64)
+
+	d := airgap.NewDownloader(files.NewReader(), tt.client, airgap.WithoutOvas())
+	_, err := d.Download(context.Background(), tt.spec, version.Info{GitVersion: "v0.6.0"}, filepath.Join(tt.dir, "download"), filepath.Join(tt.dir, "bundle.tar.gz"))
+	tt.Expect(err).To(MatchError(ContainSubstring("checksum mismatch for " + tt.server.URL + "/capi/v0.3.19/components.yaml")))
+}
+
+func TestDownloadBundleUnsignedImages(t *testing.T) {
+	tt := newAirgapTest(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tt.Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	tt.Expect(err).NotTo(HaveOccurred())
+	verifier, err := registry.NewSignatureVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	tt.Expect(err).NotTo(HaveOccurred())
+
+	d := airgap.NewDownloader(files.NewReader(), tt.client, airgap.WithoutOvas(), airgap.WithSignatureVerifier(verifier))
+	_, err = d.Download(context.Background(), tt.spec, version.Info{GitVersion: "v0.6.0"}, filepath.Join(tt.dir, "download"), filepath.Join(tt.dir, "bundle.tar.gz"))
+	tt.Expect(err).To(MatchError(ContainSubstring("image " + tt.source.Host() + "/eks-anywhere/cli-tools:v0.6.0 is not signed")))
+}
+
+func TestDownloadBundleManifestWithoutChecksum(t *testing.T) {
+	tt := newAirgapTest(t)
+	tt.spec.VersionsBundle.ClusterAPI.Components.SHA256 = ""
+	tt.spec.VersionsBundle.ClusterAPI.Metadata.SHA256 = ""
+
+	d := airgap.NewDownloader(files.NewReader(), tt.client, airgap.WithoutOvas())
+	_, err := d.Download(context.Background(), tt.spec, version.Info{GitVersion: "v0.6.0"}, filepath.Join(tt.dir, "download"), filepath.Join(tt.dir, "bundle.tar.gz"))
+	tt.Expect(err).To(MatchError(ContainSubstring("no checksum published for file [" + tt.server.URL + "/capi/v0.3.19/components.yaml]")))
+}
@@ -0,0 +1,265 @@
+package airgap
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+// createArchive writes all the files in dir to a gzipped tarball, with paths relative to dir.
+// Files are streamed so big artifacts like OVAs are never fully loaded in memory.
+func createArchive(dir, archivePath string) (err error) {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("error creating bundle archive: %v", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("error creating bundle archive: %v", closeErr)
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer data.Close()
+		_, err = io.Copy(tarWriter, data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error creating bundle archive: %v", err)
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return fmt.Errorf("error creating bundle archive: %v", err)
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return fmt.Errorf("error creating bundle archive: %v", err)
+	}
+	logger.V(3).Info("Created bundle archive", "archive", archivePath)
+	return nil
+}
+
+// extractArchive extracts a gzipped tarball created by createArchive into dir.
+// Only regular files and directories are extracted and no path can escape dir.
+func extractArchive(archivePath, dir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening bundle archive: %v", err)
+	}
+	defer f.Close()
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("error reading bundle archive: %v", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading bundle archive: %v", err)
+		}
+
+		target, err := archiveEntryPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("error extracting bundle archive: %v", err)
+			}
+		case tar.TypeReg:
+			if err = extractFile(tarReader, target); err != nil {
+				return fmt.Errorf("error extracting %s from bundle archive: %v", header.Name, err)
+			}
+		default:
+			return fmt.Errorf("invalid entry %s in bundle archive: unsupported type", header.Name)
+		}
+	}
+}
+
+func archiveEntryPath(dir, name string) (string, error) {
+	cleaned := path.Clean("/" + name)
+	if cleaned == "/" || cleaned != "/"+strings.TrimSuffix(name, "/") {
+		return "", fmt.Errorf("invalid entry %s in bundle archive: path must be relative to the bundle root", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(cleaned)), nil
+}
+
+func extractFile(r io.Reader, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, bundleArchiveFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// writeChecksums writes the sha256 of every file in dir, in the sha256sum format
+func writeChecksums(dir string) error {
+	checksums, err := computeChecksums(dir)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(checksums))
+	for p := range checksums {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%s  %s\n", checksums[p], p)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, checksumsFileName), []byte(b.String()), bundleArchiveFileMode); err != nil {
+		return fmt.Errorf("error writing bundle checksums: %v", err)
+	}
+	return nil
+}
+
+// verifyChecksums checks every file listed in the checksums file. All missing and modified files are reported.
+func verifyChecksums(dir string) error {
+	f, err := os.Open(filepath.Join(dir, checksumsFileName))
+	if err != nil {
+		return fmt.Errorf("error reading bundle checksums: %v", err)
+	}
+	defer f.Close()
+
+	var errs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "  ", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("invalid bundle checksums line: %s", scanner.Text())
+		}
+		expected, name := fields[0], fields[1]
+		file, err := archiveEntryPath(dir, name)
+		if err != nil {
+			return err
+		}
+
+		got, err := fileChecksum(file)
+		switch {
+		case os.IsNotExist(err):
+			errs = append(errs, fmt.Sprintf("%s is missing", name))
+		case err != nil:
+			return fmt.Errorf("error computing checksum for %s: %v", name, err)
+		case got != expected:
+			errs = append(errs, fmt.Sprintf("%s has checksum %s, expected %s", name, got, expected))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("error reading bundle checksums: %v", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("bundle checksum validation failed: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func computeChecksums(dir string) (map[string]string, error) {
+	checksums := map[string]string{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil || !info.Mode().IsRegular() {
+			return walkErr
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == checksumsFileName {
+			return nil
+		}
+
+		sum, err := fileChecksum(file)
+		if err != nil {
+			return err
+		}
+		checksums[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error computing bundle checksums: %v", err)
+	}
+	return checksums, nil
+}
+
+// writePublishedChecksum writes the sha256 of file next to it, the way the release manifests are published,
+// so the CLI can verify the imported manifests when they are served with a file server
+func writePublishedChecksum(file string) error {
+	checksum, err := fileChecksum(file)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(file))
+	if err = ioutil.WriteFile(file+files.PublishedChecksumSuffix, []byte(content), bundleArchiveFileMode); err != nil {
+		return fmt.Errorf("error writing checksum for %s: %v", filepath.Base(file), err)
+	}
+	return nil
+}
+
+func fileChecksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
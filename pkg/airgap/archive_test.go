@@ -0,0 +1,54 @@
+package airgap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestArchiveRoundTripWithChecksums(t *testing.T) {
+	g := NewWithT(t)
+	src := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(src, "manifests", "capi"), 0o755)).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(src, "manifests", "capi", "components.yaml"), []byte("components"), 0o644)).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(src, MetadataFileName), []byte("cliVersion: v0.6.0"), 0o644)).To(Succeed())
+	g.Expect(writeChecksums(src)).To(Succeed())
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	g.Expect(createArchive(src, archive)).To(Succeed())
+
+	dst := t.TempDir()
+	g.Expect(extractArchive(archive, dst)).To(Succeed())
+	g.Expect(verifyChecksums(dst)).To(Succeed())
+
+	g.Expect(ioutil.WriteFile(filepath.Join(dst, "manifests", "capi", "components.yaml"), []byte("tampered"), 0o644)).To(Succeed())
+	g.Expect(os.Remove(filepath.Join(dst, MetadataFileName))).To(Succeed())
+	err := verifyChecksums(dst)
+	g.Expect(err).To(MatchError(ContainSubstring("manifests/capi/components.yaml has checksum")))
+	g.Expect(err).To(MatchError(ContainSubstring("metadata.yaml is missing")))
+}
+
+func TestExtractArchiveRejectsPathTraversal(t *testing.T) {
+	for _, name := range []string{"../escape.yaml", "/etc/escape.yaml", "manifests/../../escape.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+			f, err := os.Create(archive)
+			g.Expect(err).NotTo(HaveOccurred())
+			gzipWriter := gzip.NewWriter(f)
+			tarWriter := tar.NewWriter(gzipWriter)
+			g.Expect(tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 4, Typeflag: tar.TypeReg})).To(Succeed())
+			_, err = tarWriter.Write([]byte("evil"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tarWriter.Close()).To(Succeed())
+			g.Expect(gzipWriter.Close()).To(Succeed())
+			g.Expect(f.Close()).To(Succeed())
+
+			g.Expect(extractArchive(archive, t.TempDir())).To(MatchError(ContainSubstring("path must be relative to the bundle root")))
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package airgap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registry"
+	"github.com/aws/eks-anywhere/pkg/version"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+// Downloader builds self-contained air-gap bundles with every artifact needed to create a cluster
+type Downloader struct {
+	reader      *files.Reader
+	client      *registry.Client
+	concurrency int
+	skipOvas    bool
+	verifier    *registry.SignatureVerifier
+}
+
+type DownloaderOpt func(*Downloader)
+
+// WithConcurrency sets how many images are downloaded in parallel
+func WithConcurrency(concurrency int) DownloaderOpt {
+	return func(d *Downloader) {
+		d.concurrency = concurrency
+	}
+}
+
+// WithoutOvas skips the OVAs, which are only needed for vSphere clusters
+func WithoutOvas() DownloaderOpt {
+	return func(d *Downloader) {
+		d.skipOvas = true
+	}
+}
+
+// WithSignatureVerifier verifies the signature of every image before it's added to the bundle
+func WithSignatureVerifier(verifier *registry.SignatureVerifier) DownloaderOpt {
+	return func(d *Downloader) {
+		d.verifier = verifier
+	}
+}
+
+func NewDownloader(reader *files.Reader, client *registry.Client, opts ...DownloaderOpt) *Downloader {
+	d := &Downloader{
+		reader: reader,
+		client: client,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Download writes all the artifacts for the cluster spec kubernetes version to dir and packages them in archivePath.
+// The bundles and release manifests are rewritten to point to the artifacts with paths relative to the bundle root.
+func (d *Downloader) Download(ctx context.Context, spec *cluster.Spec, cliVersion version.Info, dir, archivePath string) (*Metadata, error) {
+	release, err := spec.GetRelease(cliVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating bundle directory: %v", err)
+	}
+
+	metadata := &Metadata{
+		CliVersion:        cliVersion.GitVersion,
+		KubernetesVersion: spec.VersionsBundle.KubeVersion,
+		BundlesManifest:   path.Join(manifestsDir, bundlesFileName),
+		ReleasesManifest:  path.Join(manifestsDir, releasesFileName),
+		ImagesLayout:      imagesDir,
+	}
+
+	if err = d.downloadImages(ctx, spec, dir, metadata); err != nil {
+		return nil, err
+	}
+
+	checksums := map[string]files.Checksum{
+		spec.VersionsBundle.EksD.EksDReleaseUrl: cluster.EksdManifestChecksum(spec.VersionsBundle.EksD),
+	}
+	for _, manifests := range spec.VersionsBundle.Manifests() {
+		for _, manifest := range manifests {
+			if checksum := cluster.ManifestChecksum(manifest); !checksum.IsEmpty() {
+				checksums[manifest.URI] = checksum
+			}
+		}
+	}
+	ovas := map[string]files.Checksum{}
+	for _, ova := range spec.VersionsBundle.Ovas() {
+		if ova.URI != "" {
+			ovas[ova.URI] = cluster.ArchiveChecksum(ova)
+		}
+	}
+
+	versionsBundle := spec.VersionsBundle.VersionsBundle.DeepCopy()
+	downloaded := map[string]string{}
+	err = rewriteArtifacts(versionsBundle, func(kind artifactKind, uri string) (string, error) {
+		if rel, ok := downloaded[uri]; ok {
+			return rel, nil
+		}
+
+		var rel string
+		var err error
+		switch kind {
+		case manifestArtifact:
+			rel, err = d.downloadManifest(uri, checksums[uri], dir)
+			metadata.Manifests = append(metadata.Manifests, rel)
+		case archiveArtifact:
+			checksum, ok := ovas[uri]
+			if d.skipOvas || !ok {
+				return uri, nil
+			}
+			rel, err = d.downloadOva(ctx, uri, checksum, dir)
+			metadata.Ovas = append(metadata.Ovas, rel)
+		default:
+			return uri, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		downloaded[uri] = rel
+		return rel, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := spec.Bundles.DeepCopy()
+	bundles.Spec.VersionsBundles = []v1alpha1.VersionsBundle{*versionsBundle}
+	if err = writeYaml(filepath.Join(dir, filepath.FromSlash(metadata.BundlesManifest)), bundles); err != nil {
+		return nil, err
+	}
+
+	release.BundleManifestUrl = metadata.BundlesManifest
+	releases := &v1alpha1.Release{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "Release"},
+		Spec: v1alpha1.ReleaseSpec{
+			LatestVersion: release.Version,
+			Releases:      []v1alpha1.EksARelease{*release},
+		},
+	}
+	if err = writeYaml(filepath.Join(dir, filepath.FromSlash(metadata.ReleasesManifest)), releases); err != nil {
+		return nil, err
+	}
+
+	if err = writeMetadata(dir, metadata); err != nil {
+		return nil, err
+	}
+	if err = writeChecksums(dir); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Creating bundle archive", "archive", archivePath)
+	if err = createArchive(dir, archivePath); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func (d *Downloader) downloadImages(ctx context.Context, spec *cluster.Spec, dir string, metadata *Metadata) error {
+	seen := map[string]struct{}{}
+	for _, image := range append(spec.VersionsBundle.Images(), spec.KubeDistroImages()...) {
+		if _, ok := seen[image.URI]; ok || image.URI == "" {
+			continue
+		}
+		seen[image.URI] = struct{}{}
+		metadata.Images = append(metadata.Images, image.URI)
+	}
+
+	if d.verifier != nil {
+		logger.Info("Verifying image signatures", "images", len(metadata.Images))
+		for _, image := range metadata.Images {
+			ref, err := registry.ParseReference(image)
+			if err != nil {
+				return err
+			}
+			if err = d.verifier.Verify(ctx, d.client, ref); err != nil {
+				return err
+			}
+		}
+	}
+
+	layout, err := registry.NewLayout(filepath.Join(dir, imagesDir))
+	if err != nil {
+		return err
+	}
+	logger.Info("Downloading images", "images", len(metadata.Images))
+	if _, err = layout.Save(ctx, d.client, metadata.Images, d.concurrency); err != nil {
+		return fmt.Errorf("error downloading images: %v", err)
+	}
+	return nil
+}
+
+func (d *Downloader) downloadManifest(uri string, checksum files.Checksum, dir string) (string, error) {
+	rel, err := artifactPath(manifestsDir, uri)
+	if err != nil {
+		return "", err
+	}
+	logger.V(3).Info("Downloading manifest", "uri", uri)
+	content, err := d.reader.ReadFileWithChecksum(uri, checksum)
+	if err != nil {
+		return "", fmt.Errorf("error downloading manifest: %v", err)
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(rel))
+	if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	if err = ioutil.WriteFile(target, content, bundleArchiveFileMode); err != nil {
+		return "", fmt.Errorf("error writing manifest %s: %v", rel, err)
+	}
+	return rel, nil
+}
+
+// downloadOva streams the OVA to disk, verifying its checksums
+func (d *Downloader) downloadOva(ctx context.Context, uri string, checksum files.Checksum, dir string) (string, error) {
+	rel, err := artifactPath(ovasDir, uri)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Downloading OVA", "uri", uri)
+
+	if err = d.reader.DownloadFile(ctx, uri, checksum, filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+		return "", fmt.Errorf("error downloading OVA: %v", err)
+	}
+	return rel, nil
+}
+
+// artifactPath maps an artifact URI to a path inside the bundle, keeping the URI path to avoid collisions
+// between files with the same name
+func artifactPath(baseDir, uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact uri %s: %v", uri, err)
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if cleaned == "" {
+		return "", fmt.Errorf("invalid artifact uri %s: empty path", uri)
+	}
+	return path.Join(baseDir, cleaned), nil
+}
+
+func writeYaml(file string, obj interface{}) error {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %v", filepath.Base(file), err)
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(file, content, bundleArchiveFileMode); err != nil {
+		return fmt.Errorf("error writing %s: %v", filepath.Base(file), err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package airgap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registry"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+// Importer loads air-gap bundles into a local registry and file server
+type Importer struct {
+	client      *registry.Client
+	concurrency int
+}
+
+func NewImporter(client *registry.Client, concurrency int) *Importer {
+	return &Importer{
+		client:      client,
+		concurrency: concurrency,
+	}
+}
+
+type ImportConfig struct {
+	// ArchivePath is the bundle archive created by Downloader
+	ArchivePath string
+	// Dir is where the bundle is extracted. It's the root of the file server, if one is used.
+	Dir string
+	// RegistryEndpoint is the registry where images are pushed to
+	RegistryEndpoint string
+	// FileServerURL is the URL Dir is served at. If empty, artifacts are referenced by their local path.
+	FileServerURL string
+}
+
+// ImportResult points to the manifests rewritten to use the imported artifacts
+type ImportResult struct {
+	BundlesManifest  string
+	ReleasesManifest string
+}
+
+// Import extracts and verifies the bundle, pushes its images to the registry and writes new bundles and release
+// manifests that reference the imported images and artifacts, so they can be used without internet access
+func (i *Importer) Import(ctx context.Context, config ImportConfig) (*ImportResult, error) {
+	dir, err := filepath.Abs(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Extracting bundle", "archive", config.ArchivePath, "dir", dir)
+	if err = extractArchive(config.ArchivePath, dir); err != nil {
+		return nil, err
+	}
+	if err = verifyChecksums(dir); err != nil {
+		return nil, err
+	}
+	metadata, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := registry.NewLayout(filepath.Join(dir, filepath.FromSlash(metadata.ImagesLayout)))
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Pushing images", "registry", config.RegistryEndpoint, "images", len(metadata.Images))
+	if _, err = layout.Push(ctx, i.client, config.RegistryEndpoint, i.concurrency); err != nil {
+		return nil, fmt.Errorf("error pushing bundle images: %v", err)
+	}
+
+	location := func(rel string) string {
+		if config.FileServerURL != "" {
+			return strings.TrimSuffix(config.FileServerURL, "/") + "/" + rel
+		}
+		return filepath.Join(dir, filepath.FromSlash(rel))
+	}
+
+	bundles := &v1alpha1.Bundles{}
+	if err = readYaml(filepath.Join(dir, filepath.FromSlash(metadata.BundlesManifest)), bundles); err != nil {
+		return nil, err
+	}
+	for idx := range bundles.Spec.VersionsBundles {
+		err = rewriteArtifacts(&bundles.Spec.VersionsBundles[idx], func(kind artifactKind, uri string) (string, error) {
+			if kind == imageArtifact {
+				ref, err := registry.ParseReference(uri)
+				if err != nil {
+					return "", err
+				}
+				return ref.InRegistry(config.RegistryEndpoint).String(), nil
+			}
+			if isRelative(uri) {
+				return location(uri), nil
+			}
+			return uri, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ImportResult{
+		BundlesManifest:  location(importedBundlesFile),
+		ReleasesManifest: location(importedReleasesFile),
+	}
+	if err = writeYaml(filepath.Join(dir, importedBundlesFile), bundles); err != nil {
+		return nil, err
+	}
+	if err = writePublishedChecksum(filepath.Join(dir, importedBundlesFile)); err != nil {
+		return nil, err
+	}
+
+	releases := &v1alpha1.Release{}
+	if err = readYaml(filepath.Join(dir, filepath.FromSlash(metadata.ReleasesManifest)), releases); err != nil {
+		return nil, err
+	}
+	for idx := range releases.Spec.Releases {
+		releases.Spec.Releases[idx].BundleManifestUrl = result.BundlesManifest
+	}
+	if err = writeYaml(filepath.Join(dir, importedReleasesFile), releases); err != nil {
+		return nil, err
+	}
+	if err = writePublishedChecksum(filepath.Join(dir, importedReleasesFile)); err != nil {
+		return nil, err
+	}
+
+	if len(metadata.Ovas) > 0 && config.FileServerURL == "" {
+		logger.Info("Warning: vCenter imports OVAs from a URL, serve the bundle directory with a file server and set its URL to use the bundle OVAs", "dir", dir)
+	}
+
+	return result, nil
+}
+
+func isRelative(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme == "" && !filepath.IsAbs(uri)
+}
+
+func readYaml(file string, obj interface{}) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading %s from bundle: %v", filepath.Base(file), err)
+	}
+	if err = yaml.Unmarshal(content, obj); err != nil {
+		return fmt.Errorf("error parsing %s from bundle: %v", filepath.Base(file), err)
+	}
+	return nil
+}
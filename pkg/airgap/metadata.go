@@ -0,0 +1,58 @@
+package airgap
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	MetadataFileName      = "metadata.yaml"
+	checksumsFileName     = "checksums.sha256"
+	imagesDir             = "images"
+	manifestsDir          = "manifests"
+	ovasDir               = "ovas"
+	bundlesFileName       = "bundle-release.yaml"
+	releasesFileName      = "eks-a-release.yaml"
+	importedBundlesFile   = "imported-bundle-release.yaml"
+	importedReleasesFile  = "imported-eks-a-release.yaml"
+	bundleArchiveFileMode = 0o644
+)
+
+// Metadata describes the content of an air-gap bundle. All paths are relative to the bundle root.
+type Metadata struct {
+	CliVersion        string   `json:"cliVersion"`
+	KubernetesVersion string   `json:"kubernetesVersion"`
+	BundlesManifest   string   `json:"bundlesManifest"`
+	ReleasesManifest  string   `json:"releasesManifest"`
+	ImagesLayout      string   `json:"imagesLayout"`
+	Images            []string `json:"images"`
+	Manifests         []string `json:"manifests"`
+	Ovas              []string `json:"ovas,omitempty"`
+}
+
+func writeMetadata(dir string, metadata *Metadata) error {
+	content, err := yaml.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("error marshalling bundle metadata: %v", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, MetadataFileName), content, bundleArchiveFileMode); err != nil {
+		return fmt.Errorf("error writing bundle metadata: %v", err)
+	}
+	return nil
+}
+
+// ReadMetadata reads the metadata of an extracted bundle
+func ReadMetadata(dir string) (*Metadata, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, MetadataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle metadata, %s is not a valid bundle: %v", dir, err)
+	}
+	metadata := &Metadata{}
+	if err = yaml.UnmarshalStrict(content, metadata); err != nil {
+		return nil, fmt.Errorf("error parsing bundle metadata: %v", err)
+	}
+	return metadata, nil
+}
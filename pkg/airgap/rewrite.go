@@ -0,0 +1,73 @@
+package airgap
+
+import (
+	"reflect"
+
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+type artifactKind int
+
+const (
+	imageArtifact artifactKind = iota
+	manifestArtifact
+	archiveArtifact
+)
+
+var artifactKinds = map[reflect.Type]artifactKind{
+	reflect.TypeOf(v1alpha1.Image{}):    imageArtifact,
+	reflect.TypeOf(v1alpha1.Manifest{}): manifestArtifact,
+	reflect.TypeOf(v1alpha1.Archive{}):  archiveArtifact,
+}
+
+// rewriteArtifacts calls rewrite with the URI of every image, manifest and archive in the versions bundle,
+// replacing it with the returned value. The eks-d release manifest URL is handled as a manifest.
+// Walking the struct instead of listing fields makes sure new bundle artifacts are never missed.
+func rewriteArtifacts(versionsBundle *v1alpha1.VersionsBundle, rewrite func(kind artifactKind, uri string) (string, error)) error {
+	if versionsBundle.EksD.EksDReleaseUrl != "" {
+		uri, err := rewrite(manifestArtifact, versionsBundle.EksD.EksDReleaseUrl)
+		if err != nil {
+			return err
+		}
+		versionsBundle.EksD.EksDReleaseUrl = uri
+	}
+	return walkArtifacts(reflect.ValueOf(versionsBundle).Elem(), rewrite)
+}
+
+func walkArtifacts(v reflect.Value, rewrite func(kind artifactKind, uri string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return walkArtifacts(v.Elem(), rewrite)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkArtifacts(v.Index(i), rewrite); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if kind, ok := artifactKinds[v.Type()]; ok {
+			uriField := v.FieldByName("URI")
+			if uriField.String() == "" {
+				return nil
+			}
+			uri, err := rewrite(kind, uriField.String())
+			if err != nil {
+				return err
+			}
+			uriField.SetString(uri)
+			return nil
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := walkArtifacts(v.Field(i), rewrite); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
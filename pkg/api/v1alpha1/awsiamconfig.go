@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/aws/eks-anywhere/pkg/logger"
 )
 
@@ -12,6 +14,31 @@ const (
 	mountedFile      = "MountedFile"
 )
 
+// Used for generating yaml for generate clusterconfig command
+func NewAWSIamConfigGenerate(name string) *AWSIamConfigGenerate {
+	return &AWSIamConfigGenerate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       AWSIamConfigKind,
+			APIVersion: SchemeBuilder.GroupVersion.String(),
+		},
+		ObjectMeta: ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func (c *AWSIamConfigGenerate) APIVersion() string {
+	return c.TypeMeta.APIVersion
+}
+
+func (c *AWSIamConfigGenerate) Kind() string {
+	return c.TypeMeta.Kind
+}
+
+func (c *AWSIamConfigGenerate) Name() string {
+	return c.ObjectMeta.Name
+}
+
 func GetAndValidateAWSIamConfig(fileName string, refName string, clusterConfig *Cluster) (*AWSIamConfig, error) {
 	config, err := getAWSIamConfig(fileName)
 	if err != nil {
@@ -1,12 +1,15 @@
 package v1alpha1
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
+	"reflect"
 	_ "regexp"
 	"strconv"
 	"strings"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/aws/eks-anywhere/pkg/crypto"
 	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/networkutils"
 )
 
 const (
@@ -135,6 +139,42 @@ func WithEtcdMachineGroupRef(ref ProviderRefAccessor) ClusterGenerateOpt {
 	}
 }
 
+func WithKubernetesVersion(version KubernetesVersion) ClusterGenerateOpt {
+	return func(c *ClusterGenerate) {
+		c.Spec.KubernetesVersion = version
+	}
+}
+
+func WithIdentityProviderRef(ref ProviderRefAccessor) ClusterGenerateOpt {
+	return func(c *ClusterGenerate) {
+		c.Spec.IdentityProviderRefs = append(c.Spec.IdentityProviderRefs, Ref{
+			Kind: ref.Kind(),
+			Name: ref.Name(),
+		})
+	}
+}
+
+func WithGitOpsRef(ref ProviderRefAccessor) ClusterGenerateOpt {
+	return func(c *ClusterGenerate) {
+		c.Spec.GitOpsRef = &Ref{
+			Kind: ref.Kind(),
+			Name: ref.Name(),
+		}
+	}
+}
+
+func WithProxyConfiguration(proxy *ProxyConfiguration) ClusterGenerateOpt {
+	return func(c *ClusterGenerate) {
+		c.Spec.ProxyConfiguration = proxy
+	}
+}
+
+func WithRegistryMirrorConfiguration(mirror *RegistryMirrorConfiguration) ClusterGenerateOpt {
+	return func(c *ClusterGenerate) {
+		c.Spec.RegistryMirrorConfiguration = mirror
+	}
+}
+
 func NewCluster(clusterName string) *Cluster {
 	c := &Cluster{
 		TypeMeta: metav1.TypeMeta{
@@ -158,11 +198,17 @@ var clusterConfigValidations = []func(*Cluster) error{
 	validateControlPlaneReplicas,
 	validateWorkerNodeGroups,
 	validateNetworking,
+	validateCiliumConfig,
+	validateDNS,
 	validateGitOps,
 	validateEtcdReplicas,
 	validateIdentityProviderRefs,
 	validateProxyConfig,
 	validateMirrorConfig,
+	validateLoadBalancer,
+	validateImageVerificationConfig,
+	validateUpgradeHealthGates,
+	validateEtcdBackup,
 }
 
 func GetClusterConfig(fileName string) (*Cluster, error) {
@@ -240,12 +286,23 @@ func (c *Cluster) ClearPauseAnnotation() {
 	}
 }
 
+// UseImageMirror returns the image in its registry mirror. Without explicit mirrors every image is pulled
+// from the mirror endpoint, otherwise only images from mirrored registries are.
 func (c *Cluster) UseImageMirror(defaultImage string) string {
-	if c.Spec.RegistryMirrorConfiguration == nil {
+	mirrorConfig := c.Spec.RegistryMirrorConfiguration
+	if mirrorConfig == nil {
 		return defaultImage
 	}
 	imageUrl, _ := url.Parse("https://" + defaultImage)
-	return c.Spec.RegistryMirrorConfiguration.Endpoint + imageUrl.Path
+	if len(mirrorConfig.Mirrors) == 0 {
+		return mirrorConfig.Endpoint + imageUrl.Path
+	}
+	for _, mirror := range mirrorConfig.Mirrors {
+		if mirror.Registry == imageUrl.Host {
+			return mirrorConfig.mirrorEndpoint(mirror) + imageUrl.Path
+		}
+	}
+	return defaultImage
 }
 
 func (c *Cluster) IsReconcilePaused() bool {
@@ -313,20 +370,23 @@ func validateNetworking(clusterConfig *Cluster) error {
 	if len(clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks) <= 0 {
 		return errors.New("services CIDR block not specified or empty")
 	}
-	if len(clusterConfig.Spec.ClusterNetwork.Pods.CidrBlocks) > 1 {
-		return fmt.Errorf("multiple CIDR blocks for Pods are not yet supported")
-	}
-	if len(clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks) > 1 {
-		return fmt.Errorf("multiple CIDR blocks for Services are not yet supported")
-	}
-	_, _, err := net.ParseCIDR(clusterConfig.Spec.ClusterNetwork.Pods.CidrBlocks[0])
+	podFamilies, err := cidrBlockFamilies(clusterConfig.Spec.ClusterNetwork.Pods.CidrBlocks)
 	if err != nil {
 		return fmt.Errorf("invalid CIDR block format for Pods: %s. Please specify a valid CIDR block for pod subnet", clusterConfig.Spec.ClusterNetwork.Pods)
 	}
-	_, _, err = net.ParseCIDR(clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks[0])
+	serviceFamilies, err := cidrBlockFamilies(clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks)
 	if err != nil {
 		return fmt.Errorf("invalid CIDR block for Services: %s. Please specify a valid CIDR block for service subnet", clusterConfig.Spec.ClusterNetwork.Services)
 	}
+	if err := validateDualStackFamilies("Pods", podFamilies); err != nil {
+		return err
+	}
+	if err := validateDualStackFamilies("Services", serviceFamilies); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(podFamilies, serviceFamilies) {
+		return fmt.Errorf("CIDR blocks for Pods and Services must have the same IP families in the same order, got %v for Pods and %v for Services", podFamilies, serviceFamilies)
+	}
 	if clusterConfig.Spec.ClusterNetwork.CNI == "" {
 		return errors.New("cni not specified or empty")
 	}
@@ -336,6 +396,177 @@ func validateNetworking(clusterConfig *Cluster) error {
 	return nil
 }
 
+func cidrBlockFamilies(cidrBlocks []string) ([]networkutils.IPFamily, error) {
+	for _, cidrBlock := range cidrBlocks {
+		if _, _, err := net.ParseCIDR(cidrBlock); err != nil {
+			return nil, err
+		}
+	}
+	return networkutils.CIDRFamilies(cidrBlocks)
+}
+
+// validateDualStackFamilies allows a single CIDR block of either family or, for dual-stack clusters,
+// one IPv4 and one IPv6 block. The first block sets the primary family of the cluster.
+func validateDualStackFamilies(name string, families []networkutils.IPFamily) error {
+	if len(families) > 2 {
+		return fmt.Errorf("at most 2 CIDR blocks for %s are supported, one per IP family", name)
+	}
+	if len(families) == 2 && families[0] == families[1] {
+		return fmt.Errorf("multiple CIDR blocks for %s must belong to different IP families for dual-stack networking", name)
+	}
+	return nil
+}
+
+func validateDNS(clusterConfig *Cluster) error {
+	dns := clusterConfig.Spec.ClusterNetwork.DNS
+	if dns == nil {
+		return nil
+	}
+	for _, server := range dns.UpstreamServers {
+		if err := validateDNSServer(server); err != nil {
+			return fmt.Errorf("invalid dns upstreamServers: %v", err)
+		}
+	}
+	domains := make(map[string]struct{}, len(dns.StubDomains))
+	for _, stub := range dns.StubDomains {
+		if stub.Domain == "" {
+			return errors.New("dns stubDomain domain not specified or empty")
+		}
+		if _, ok := domains[stub.Domain]; ok {
+			return fmt.Errorf("dns stubDomain %s is duplicated", stub.Domain)
+		}
+		domains[stub.Domain] = struct{}{}
+		if len(stub.Servers) == 0 {
+			return fmt.Errorf("dns stubDomain %s servers not specified or empty", stub.Domain)
+		}
+		for _, server := range stub.Servers {
+			if err := validateDNSServer(server); err != nil {
+				return fmt.Errorf("invalid dns stubDomain %s servers: %v", stub.Domain, err)
+			}
+		}
+	}
+	if dns.NodeLocalDNS != nil && dns.NodeLocalDNS.LocalIP != "" {
+		ip := net.ParseIP(dns.NodeLocalDNS.LocalIP)
+		if ip == nil || !ip.IsLinkLocalUnicast() {
+			return fmt.Errorf("dns nodeLocalDNS localIP %s must be a link-local address", dns.NodeLocalDNS.LocalIP)
+		}
+	}
+	return nil
+}
+
+// validateDNSServer accepts an IP address with an optional port
+func validateDNSServer(server string) error {
+	host := server
+	if h, port, err := net.SplitHostPort(server); err == nil {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("%s has an invalid port", server)
+		}
+		host = h
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%s is not a valid IP address", server)
+	}
+	return nil
+}
+
+func validateLoadBalancer(clusterConfig *Cluster) error {
+	lb := clusterConfig.Spec.LoadBalancer
+	if lb == nil {
+		return nil
+	}
+	if _, ok := validLoadBalancerProviders[lb.Provider]; !ok {
+		return fmt.Errorf("loadBalancer provider %s not supported, valid values are kube-vip and metallb", lb.Provider)
+	}
+	if len(lb.AddressPools) == 0 {
+		return errors.New("loadBalancer addressPools not specified or empty")
+	}
+
+	networkBlocks := map[string][]string{
+		"pods":     clusterConfig.Spec.ClusterNetwork.Pods.CidrBlocks,
+		"services": clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks,
+	}
+	var endpoint net.IP
+	if clusterConfig.Spec.ControlPlaneConfiguration.Endpoint != nil {
+		endpoint = net.ParseIP(clusterConfig.Spec.ControlPlaneConfiguration.Endpoint.Host)
+	}
+
+	poolNames := make(map[string]struct{}, len(lb.AddressPools))
+	var ranges []*networkutils.IPRange
+	for _, pool := range lb.AddressPools {
+		if pool.Name == "" {
+			return errors.New("loadBalancer addressPool name not specified or empty")
+		}
+		if _, ok := poolNames[pool.Name]; ok {
+			return fmt.Errorf("loadBalancer addressPool %s is duplicated", pool.Name)
+		}
+		poolNames[pool.Name] = struct{}{}
+		if len(pool.Addresses) == 0 {
+			return fmt.Errorf("loadBalancer addressPool %s addresses not specified or empty", pool.Name)
+		}
+
+		for _, addresses := range pool.Addresses {
+			r, err := networkutils.ParseIPRange(addresses)
+			if err != nil {
+				return fmt.Errorf("loadBalancer addressPool %s: %v", pool.Name, err)
+			}
+			for _, existing := range ranges {
+				if r.Overlaps(existing) {
+					return fmt.Errorf("loadBalancer addresses %s overlap with another address pool", addresses)
+				}
+			}
+			for network, blocks := range networkBlocks {
+				for _, block := range blocks {
+					overlap, err := networkutils.RangesOverlap(addresses, block)
+					if err != nil {
+						return err
+					}
+					if overlap {
+						return fmt.Errorf("loadBalancer addresses %s overlap with %s CIDR block %s", addresses, network, block)
+					}
+				}
+			}
+			if endpoint != nil && r.Contains(endpoint) {
+				return fmt.Errorf("loadBalancer addresses %s include the control plane endpoint %s", addresses, endpoint)
+			}
+			ranges = append(ranges, r)
+		}
+	}
+
+	return nil
+}
+
+func validateCiliumConfig(clusterConfig *Cluster) error {
+	cilium := clusterConfig.Spec.ClusterNetwork.Cilium
+	if cilium == nil {
+		return nil
+	}
+	if clusterConfig.Spec.ClusterNetwork.CNI != Cilium {
+		return fmt.Errorf("cilium configuration is not supported with cni %s", clusterConfig.Spec.ClusterNetwork.CNI)
+	}
+	if cilium.PolicyEnforcementMode != "" {
+		if _, ok := validCiliumPolicyEnforcementModes[cilium.PolicyEnforcementMode]; !ok {
+			return fmt.Errorf("cilium policyEnforcementMode %s not supported, valid values are default, always and never", cilium.PolicyEnforcementMode)
+		}
+	}
+	if cilium.IPAMMode != "" {
+		if _, ok := validCiliumIPAMModes[cilium.IPAMMode]; !ok {
+			return fmt.Errorf("cilium ipamMode %s not supported, valid values are kubernetes and cluster-pool", cilium.IPAMMode)
+		}
+	}
+	if cilium.Hubble != nil && cilium.Hubble.UI && !cilium.Hubble.Relay {
+		return errors.New("cilium hubble ui requires hubble relay to be enabled")
+	}
+	if cilium.Encryption != nil {
+		if _, ok := validCiliumEncryptionTypes[cilium.Encryption.Type]; !ok {
+			return fmt.Errorf("cilium encryption type %s not supported, valid values are wireguard and ipsec", cilium.Encryption.Type)
+		}
+		if cilium.Encryption.Type != CiliumEncryptionIPsec && cilium.Encryption.IPsecKeySecretName != "" {
+			return errors.New("cilium encryption ipsecKeySecretName can only be set with ipsec encryption")
+		}
+	}
+	return nil
+}
+
 func validateProxyConfig(clusterConfig *Cluster) error {
 	if clusterConfig.Spec.ProxyConfiguration == nil {
 		return nil
@@ -380,23 +611,167 @@ func validateProxyData(proxy string) error {
 }
 
 func validateMirrorConfig(clusterConfig *Cluster) error {
-	if clusterConfig.Spec.RegistryMirrorConfiguration == nil {
+	mirrorConfig := clusterConfig.Spec.RegistryMirrorConfiguration
+	if mirrorConfig == nil {
 		return nil
 	}
-	if clusterConfig.Spec.RegistryMirrorConfiguration.Endpoint == "" {
+	if mirrorConfig.Endpoint == "" && len(mirrorConfig.Mirrors) == 0 {
 		return errors.New("no value set for ECRMirror.Endpoint")
 	}
 
-	tlsValidator := crypto.NewTlsValidator(clusterConfig.Spec.RegistryMirrorConfiguration.CACertContent, clusterConfig.Spec.RegistryMirrorConfiguration.Endpoint)
+	registries := map[string]struct{}{}
+	for _, mirror := range mirrorConfig.Mirrors {
+		if mirror.Registry == "" {
+			return errors.New("registry mirror registry can't be empty")
+		}
+		if _, ok := registries[mirror.Registry]; ok {
+			return fmt.Errorf("registry %s is mirrored more than once", mirror.Registry)
+		}
+		registries[mirror.Registry] = struct{}{}
+		if mirror.Endpoint == "" && mirrorConfig.Endpoint == "" {
+			return fmt.Errorf("no endpoint set for registry %s mirror, set the mirror endpoint or registryMirrorConfiguration.endpoint", mirror.Registry)
+		}
+	}
+
+	if err := validateMirrorAuthentication(mirrorConfig.Authentication); err != nil {
+		return err
+	}
+
+	if mirrorConfig.InsecureSkipVerify {
+		logger.Info("Warning: registry mirror TLS verification is disabled")
+		return nil
+	}
+
+	for _, endpoint := range mirrorConfig.Endpoints() {
+		if err := validateMirrorEndpointCert(endpoint, mirrorConfig.CACertContent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateMirrorAuthentication(auth *RegistryMirrorAuthentication) error {
+	if auth == nil {
+		return nil
+	}
+	if auth.CredentialsSecretRef != nil {
+		if auth.Username != "" || auth.Password != "" {
+			return errors.New("registry mirror authentication can't have both credentialsSecretRef and username/password")
+		}
+		if auth.CredentialsSecretRef.Name == "" {
+			return errors.New("registry mirror credentialsSecretRef name can't be empty")
+		}
+		return nil
+	}
+	if auth.Username == "" || auth.Password == "" {
+		return errors.New("registry mirror authentication requires either username and password or credentialsSecretRef")
+	}
+	return nil
+}
+
+func validateImageVerificationConfig(clusterConfig *Cluster) error {
+	config := clusterConfig.Spec.ImageVerificationConfiguration
+	if config == nil {
+		return nil
+	}
+	if len(config.CosignPublicKeys) == 0 {
+		return errors.New("imageVerificationConfiguration requires at least one cosign public key")
+	}
+	for i, key := range config.CosignPublicKeys {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return fmt.Errorf("cosign public key %d is invalid: not PEM encoded", i)
+		}
+		if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return fmt.Errorf("cosign public key %d is invalid: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func validateUpgradeHealthGates(clusterConfig *Cluster) error {
+	gates := clusterConfig.Spec.UpgradeHealthGates
+	if gates == nil {
+		return nil
+	}
+	for _, d := range gates.Deployments {
+		if err := validateNamespacedName(d); err != nil {
+			return fmt.Errorf("invalid upgrade health gate deployment: %v", err)
+		}
+	}
+	for _, d := range gates.DaemonSets {
+		if err := validateNamespacedName(d); err != nil {
+			return fmt.Errorf("invalid upgrade health gate daemonset: %v", err)
+		}
+	}
+	for _, p := range gates.HTTPProbes {
+		u, err := url.ParseRequestURI(p.URL)
+		if err != nil {
+			return fmt.Errorf("invalid upgrade health gate http probe url %s: %v", p.URL, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid upgrade health gate http probe url %s: scheme must be http or https", p.URL)
+		}
+		if p.ExpectedStatus != 0 && (p.ExpectedStatus < 100 || p.ExpectedStatus > 599) {
+			return fmt.Errorf("invalid upgrade health gate http probe expected status %d for %s", p.ExpectedStatus, p.URL)
+		}
+	}
+	if gates.Timeout != nil && gates.Timeout.Duration <= 0 {
+		return errors.New("upgrade health gates timeout must be positive")
+	}
+	if gates.NodeDrainTimeout != nil && gates.NodeDrainTimeout.Duration <= 0 {
+		return errors.New("upgrade health gates nodeDrainTimeout must be positive")
+	}
+	return nil
+}
+
+func validateEtcdBackup(clusterConfig *Cluster) error {
+	backup := clusterConfig.Spec.EtcdBackup
+	if backup == nil {
+		return nil
+	}
+	if len(strings.Fields(backup.Schedule)) != 5 {
+		return fmt.Errorf("invalid etcd backup schedule %q: it must be a cron expression with 5 fields", backup.Schedule)
+	}
+	if backup.Retention < 0 {
+		return errors.New("etcd backup retention can't be negative")
+	}
+	if (backup.PersistentVolumeClaim == "") == (backup.S3 == nil) {
+		return errors.New("exactly one of etcd backup persistentVolumeClaim and s3 must be set")
+	}
+	if backup.S3 != nil {
+		if backup.S3.Bucket == "" {
+			return errors.New("etcd backup s3 bucket is required")
+		}
+		if backup.S3.Endpoint != "" {
+			if _, err := url.ParseRequestURI(backup.S3.Endpoint); err != nil {
+				return fmt.Errorf("invalid etcd backup s3 endpoint %s: %v", backup.S3.Endpoint, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateNamespacedName(name string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("%s must have the format namespace/name", name)
+	}
+	return nil
+}
+
+func validateMirrorEndpointCert(endpoint, caCertContent string) error {
+	tlsValidator := crypto.NewTlsValidator(caCertContent, endpoint)
 	selfSigned, err := tlsValidator.HasSelfSignedCert()
 	if err != nil {
 		return fmt.Errorf("error validating registy mirror endpoint: %v", err)
 	}
 	if selfSigned {
-		logger.V(1).Info(fmt.Sprintf("Warning: registry mirror endpoint %s is using self-signed certs", clusterConfig.Spec.RegistryMirrorConfiguration.Endpoint))
+		logger.V(1).Info(fmt.Sprintf("Warning: registry mirror endpoint %s is using self-signed certs", endpoint))
 	}
 
-	certContent := clusterConfig.Spec.RegistryMirrorConfiguration.CACertContent
+	certContent := caCertContent
 	if certContent == "" {
 		if caCert, set := os.LookupEnv(RegistryMirrorCAKey); set && len(caCert) > 0 {
 			certBuffer, err := ioutil.ReadFile(caCert)
@@ -405,7 +780,7 @@ func validateMirrorConfig(clusterConfig *Cluster) error {
 			}
 			certContent = string(certBuffer)
 		} else if selfSigned {
-			return fmt.Errorf("registry %s is using self-signed certs, please provide the certificate using caCertContent field", clusterConfig.Spec.RegistryMirrorConfiguration.Endpoint)
+			return fmt.Errorf("registry %s is using self-signed certs, please provide the certificate using caCertContent field", endpoint)
 		}
 	}
 
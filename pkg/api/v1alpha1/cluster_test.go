@@ -1,11 +1,18 @@
 package v1alpha1
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -1504,3 +1511,873 @@ func TestRefEquals(t *testing.T) {
 		})
 	}
 }
+
+func TestCiliumConfigEquals(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name string
+		want bool
+		prev *CiliumConfig
+		new  *CiliumConfig
+	}{
+		{
+			name: "previous and new == nil",
+			want: true,
+			prev: nil,
+			new:  nil,
+		},
+		{
+			name: "previous == nil",
+			want: false,
+			prev: nil,
+			new:  &CiliumConfig{},
+		},
+		{
+			name: "previous == new, all exists",
+			want: true,
+			prev: &CiliumConfig{
+				PolicyEnforcementMode: CiliumPolicyModeAlways,
+				IPAMMode:              CiliumIPAMClusterPool,
+				Hubble:                &HubbleConfig{Relay: true, UI: true},
+				Encryption:            &CiliumEncryption{Type: CiliumEncryptionWireguard},
+				EgressMasquerade:      &disabled,
+			},
+			new: &CiliumConfig{
+				PolicyEnforcementMode: CiliumPolicyModeAlways,
+				IPAMMode:              CiliumIPAMClusterPool,
+				Hubble:                &HubbleConfig{Relay: true, UI: true},
+				Encryption:            &CiliumEncryption{Type: CiliumEncryptionWireguard},
+				EgressMasquerade:      &disabled,
+			},
+		},
+		{
+			name: "previous != new, policy mode diff",
+			want: false,
+			prev: &CiliumConfig{PolicyEnforcementMode: CiliumPolicyModeDefault},
+			new:  &CiliumConfig{PolicyEnforcementMode: CiliumPolicyModeNever},
+		},
+		{
+			name: "previous != new, hubble diff",
+			want: false,
+			prev: &CiliumConfig{Hubble: &HubbleConfig{Relay: true}},
+			new:  &CiliumConfig{},
+		},
+		{
+			name: "previous != new, egress masquerade diff",
+			want: false,
+			prev: &CiliumConfig{},
+			new:  &CiliumConfig{EgressMasquerade: &disabled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want != tt.prev.Equal(tt.new) {
+				t.Errorf("CiliumConfig %+v should be equals to  %+v", tt.prev, tt.new)
+			}
+		})
+	}
+}
+
+func TestValidateCiliumConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cni     CNI
+		cilium  *CiliumConfig
+		wantErr string
+	}{
+		{
+			name:   "no cilium config",
+			cni:    Cilium,
+			cilium: nil,
+		},
+		{
+			name: "valid cilium config",
+			cni:  Cilium,
+			cilium: &CiliumConfig{
+				PolicyEnforcementMode: CiliumPolicyModeAlways,
+				IPAMMode:              CiliumIPAMKubernetes,
+				Hubble:                &HubbleConfig{Relay: true, UI: true},
+				Encryption:            &CiliumEncryption{Type: CiliumEncryptionIPsec, IPsecKeySecretName: "keys"},
+			},
+		},
+		{
+			name:    "cilium config with a different cni",
+			cni:     CiliumEnterprise,
+			cilium:  &CiliumConfig{},
+			wantErr: "cilium configuration is not supported with cni cilium-enterprise",
+		},
+		{
+			name:    "invalid policy enforcement mode",
+			cni:     Cilium,
+			cilium:  &CiliumConfig{PolicyEnforcementMode: "sometimes"},
+			wantErr: "cilium policyEnforcementMode sometimes not supported",
+		},
+		{
+			name:    "invalid ipam mode",
+			cni:     Cilium,
+			cilium:  &CiliumConfig{IPAMMode: "eni"},
+			wantErr: "cilium ipamMode eni not supported",
+		},
+		{
+			name:    "hubble ui without relay",
+			cni:     Cilium,
+			cilium:  &CiliumConfig{Hubble: &HubbleConfig{UI: true}},
+			wantErr: "cilium hubble ui requires hubble relay to be enabled",
+		},
+		{
+			name:    "invalid encryption type",
+			cni:     Cilium,
+			cilium:  &CiliumConfig{Encryption: &CiliumEncryption{Type: "tls"}},
+			wantErr: "cilium encryption type tls not supported",
+		},
+		{
+			name:    "ipsec secret with wireguard",
+			cni:     Cilium,
+			cilium:  &CiliumConfig{Encryption: &CiliumEncryption{Type: CiliumEncryptionWireguard, IPsecKeySecretName: "keys"}},
+			wantErr: "cilium encryption ipsecKeySecretName can only be set with ipsec encryption",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.ClusterNetwork.CNI = tt.cni
+			c.Spec.ClusterNetwork.Cilium = tt.cilium
+			err := validateCiliumConfig(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateCiliumConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateCiliumConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerConfigurationEquals(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+		prev *LoadBalancerConfiguration
+		new  *LoadBalancerConfiguration
+	}{
+		{
+			name: "previous and new == nil",
+			want: true,
+			prev: nil,
+			new:  nil,
+		},
+		{
+			name: "previous == nil",
+			want: false,
+			prev: nil,
+			new:  &LoadBalancerConfiguration{},
+		},
+		{
+			name: "previous == new, all exists",
+			want: true,
+			prev: &LoadBalancerConfiguration{
+				Provider:     KubeVipLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "global", Addresses: []string{"10.0.0.0/28", "10.0.1.10-10.0.1.20"}}},
+			},
+			new: &LoadBalancerConfiguration{
+				Provider:     KubeVipLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "global", Addresses: []string{"10.0.1.10-10.0.1.20", "10.0.0.0/28"}}},
+			},
+		},
+		{
+			name: "previous != new, provider diff",
+			want: false,
+			prev: &LoadBalancerConfiguration{Provider: KubeVipLoadBalancer},
+			new:  &LoadBalancerConfiguration{Provider: MetalLBLoadBalancer},
+		},
+		{
+			name: "previous != new, addresses diff",
+			want: false,
+			prev: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.0.0.0/28"}}},
+			},
+			new: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.0.0.0/27"}}},
+			},
+		},
+		{
+			name: "previous != new, pools diff",
+			want: false,
+			prev: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.0.0.0/28"}}},
+			},
+			new: &LoadBalancerConfiguration{Provider: MetalLBLoadBalancer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.new.Equal(tt.prev); got != tt.want {
+				t.Errorf("LoadBalancerConfiguration.Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name         string
+		loadBalancer *LoadBalancerConfiguration
+		wantErr      string
+	}{
+		{
+			name:         "no load balancer",
+			loadBalancer: nil,
+		},
+		{
+			name: "valid kube-vip config",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider: KubeVipLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{
+					{Name: "global", Addresses: []string{"10.0.0.0/28"}},
+					{Name: "team-a", Addresses: []string{"10.0.1.10-10.0.1.20"}},
+				},
+			},
+		},
+		{
+			name: "invalid provider",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider: "haproxy",
+			},
+			wantErr: "loadBalancer provider haproxy not supported",
+		},
+		{
+			name: "no address pools",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider: MetalLBLoadBalancer,
+			},
+			wantErr: "loadBalancer addressPools not specified or empty",
+		},
+		{
+			name: "pool without name",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Addresses: []string{"10.0.0.0/28"}}},
+			},
+			wantErr: "loadBalancer addressPool name not specified or empty",
+		},
+		{
+			name: "duplicated pool",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider: MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{
+					{Name: "default", Addresses: []string{"10.0.0.0/28"}},
+					{Name: "default", Addresses: []string{"10.0.1.0/28"}},
+				},
+			},
+			wantErr: "loadBalancer addressPool default is duplicated",
+		},
+		{
+			name: "pool without addresses",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default"}},
+			},
+			wantErr: "loadBalancer addressPool default addresses not specified or empty",
+		},
+		{
+			name: "invalid addresses",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.0.0.1"}}},
+			},
+			wantErr: "loadBalancer addressPool default: invalid address range 10.0.0.1",
+		},
+		{
+			name: "overlapping pools",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider: KubeVipLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{
+					{Name: "global", Addresses: []string{"10.0.0.0/28"}},
+					{Name: "team-a", Addresses: []string{"10.0.0.10-10.0.0.20"}},
+				},
+			},
+			wantErr: "loadBalancer addresses 10.0.0.10-10.0.0.20 overlap with another address pool",
+		},
+		{
+			name: "overlap with pods",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"192.168.1.0/24"}}},
+			},
+			wantErr: "loadBalancer addresses 192.168.1.0/24 overlap with pods CIDR block 192.168.0.0/16",
+		},
+		{
+			name: "overlap with services",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     MetalLBLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "default", Addresses: []string{"10.96.0.10-10.96.0.20"}}},
+			},
+			wantErr: "loadBalancer addresses 10.96.0.10-10.96.0.20 overlap with services CIDR block 10.96.0.0/12",
+		},
+		{
+			name: "includes control plane endpoint",
+			loadBalancer: &LoadBalancerConfiguration{
+				Provider:     KubeVipLoadBalancer,
+				AddressPools: []LoadBalancerAddressPool{{Name: "global", Addresses: []string{"10.0.2.0/24"}}},
+			},
+			wantErr: "loadBalancer addresses 10.0.2.0/24 include the control plane endpoint 10.0.2.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"192.168.0.0/16"}
+			c.Spec.ClusterNetwork.Services.CidrBlocks = []string{"10.96.0.0/12"}
+			c.Spec.ControlPlaneConfiguration.Endpoint = &Endpoint{Host: "10.0.2.1"}
+			c.Spec.LoadBalancer = tt.loadBalancer
+			err := validateLoadBalancer(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateLoadBalancer() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateLoadBalancer() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDNSEquals(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+		prev *DNS
+		new  *DNS
+	}{
+		{
+			name: "previous and new == nil",
+			want: true,
+			prev: nil,
+			new:  nil,
+		},
+		{
+			name: "previous == nil",
+			want: false,
+			prev: nil,
+			new:  &DNS{},
+		},
+		{
+			name: "previous == new, all exists",
+			want: true,
+			prev: &DNS{
+				UpstreamServers: []string{"10.0.0.2", "10.0.0.3"},
+				StubDomains:     []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0.2"}}},
+				NodeLocalDNS:    &NodeLocalDNS{Enabled: true},
+			},
+			new: &DNS{
+				UpstreamServers: []string{"10.0.0.3", "10.0.0.2"},
+				StubDomains:     []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0.2"}}},
+				NodeLocalDNS:    &NodeLocalDNS{Enabled: true, LocalIP: "169.254.20.10"},
+			},
+		},
+		{
+			name: "previous != new, upstream servers diff",
+			want: false,
+			prev: &DNS{UpstreamServers: []string{"10.0.0.2"}},
+			new:  &DNS{UpstreamServers: []string{"10.0.0.3"}},
+		},
+		{
+			name: "previous != new, stub domains diff",
+			want: false,
+			prev: &DNS{StubDomains: []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0.2"}}}},
+			new:  &DNS{StubDomains: []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0.3"}}}},
+		},
+		{
+			name: "previous != new, node local dns diff",
+			want: false,
+			prev: &DNS{},
+			new:  &DNS{NodeLocalDNS: &NodeLocalDNS{Enabled: true}},
+		},
+		{
+			name: "previous == new, node local dns disabled",
+			want: true,
+			prev: &DNS{},
+			new:  &DNS{NodeLocalDNS: &NodeLocalDNS{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.new.Equal(tt.prev); got != tt.want {
+				t.Errorf("DNS.Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDNS(t *testing.T) {
+	tests := []struct {
+		name    string
+		dns     *DNS
+		wantErr string
+	}{
+		{
+			name: "no dns config",
+			dns:  nil,
+		},
+		{
+			name: "valid dns config",
+			dns: &DNS{
+				UpstreamServers: []string{"10.0.0.2", "10.0.0.3:5353"},
+				StubDomains:     []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0.2"}}},
+				NodeLocalDNS:    &NodeLocalDNS{Enabled: true, LocalIP: "169.254.25.10"},
+			},
+		},
+		{
+			name:    "invalid upstream server",
+			dns:     &DNS{UpstreamServers: []string{"dns.example.com"}},
+			wantErr: "invalid dns upstreamServers: dns.example.com is not a valid IP address",
+		},
+		{
+			name:    "invalid upstream server port",
+			dns:     &DNS{UpstreamServers: []string{"10.0.0.2:dns"}},
+			wantErr: "invalid dns upstreamServers: 10.0.0.2:dns has an invalid port",
+		},
+		{
+			name:    "stub domain without domain",
+			dns:     &DNS{StubDomains: []StubDomain{{Servers: []string{"10.1.0.2"}}}},
+			wantErr: "dns stubDomain domain not specified or empty",
+		},
+		{
+			name: "duplicated stub domain",
+			dns: &DNS{StubDomains: []StubDomain{
+				{Domain: "corp.example.com", Servers: []string{"10.1.0.2"}},
+				{Domain: "corp.example.com", Servers: []string{"10.1.0.3"}},
+			}},
+			wantErr: "dns stubDomain corp.example.com is duplicated",
+		},
+		{
+			name:    "stub domain without servers",
+			dns:     &DNS{StubDomains: []StubDomain{{Domain: "corp.example.com"}}},
+			wantErr: "dns stubDomain corp.example.com servers not specified or empty",
+		},
+		{
+			name:    "invalid stub domain server",
+			dns:     &DNS{StubDomains: []StubDomain{{Domain: "corp.example.com", Servers: []string{"10.1.0"}}}},
+			wantErr: "invalid dns stubDomain corp.example.com servers: 10.1.0 is not a valid IP address",
+		},
+		{
+			name:    "node local dns ip not link-local",
+			dns:     &DNS{NodeLocalDNS: &NodeLocalDNS{Enabled: true, LocalIP: "10.0.0.10"}},
+			wantErr: "dns nodeLocalDNS localIP 10.0.0.10 must be a link-local address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.ClusterNetwork.DNS = tt.dns
+			err := validateDNS(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateDNS() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateDNS() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMirrorConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		mirrorConfig *RegistryMirrorConfiguration
+		wantErr      string
+	}{
+		{
+			name:         "no mirror config",
+			mirrorConfig: nil,
+		},
+		{
+			name: "valid mirrors with authentication",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint:           "1.2.3.4",
+				Mirrors:            []RegistryMirror{{Registry: "public.ecr.aws"}, {Registry: "docker.io", Endpoint: "1.2.3.5"}},
+				Authentication:     &RegistryMirrorAuthentication{Username: "user", Password: "password"},
+				InsecureSkipVerify: true,
+			},
+		},
+		{
+			name: "valid credentials secret ref",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint:           "1.2.3.4",
+				Authentication:     &RegistryMirrorAuthentication{CredentialsSecretRef: &corev1.SecretReference{Name: "mirror-credentials"}},
+				InsecureSkipVerify: true,
+			},
+		},
+		{
+			name:         "no endpoint",
+			mirrorConfig: &RegistryMirrorConfiguration{},
+			wantErr:      "no value set for ECRMirror.Endpoint",
+		},
+		{
+			name: "empty registry",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint: "1.2.3.4",
+				Mirrors:  []RegistryMirror{{Endpoint: "1.2.3.5"}},
+			},
+			wantErr: "registry mirror registry can't be empty",
+		},
+		{
+			name: "duplicated registry",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint: "1.2.3.4",
+				Mirrors:  []RegistryMirror{{Registry: "docker.io"}, {Registry: "docker.io"}},
+			},
+			wantErr: "registry docker.io is mirrored more than once",
+		},
+		{
+			name: "mirror without endpoint",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Mirrors: []RegistryMirror{{Registry: "docker.io"}},
+			},
+			wantErr: "no endpoint set for registry docker.io mirror",
+		},
+		{
+			name: "both username and secret ref",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint: "1.2.3.4",
+				Authentication: &RegistryMirrorAuthentication{
+					Username:             "user",
+					CredentialsSecretRef: &corev1.SecretReference{Name: "mirror-credentials"},
+				},
+			},
+			wantErr: "can't have both credentialsSecretRef and username/password",
+		},
+		{
+			name: "secret ref without name",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint:       "1.2.3.4",
+				Authentication: &RegistryMirrorAuthentication{CredentialsSecretRef: &corev1.SecretReference{}},
+			},
+			wantErr: "registry mirror credentialsSecretRef name can't be empty",
+		},
+		{
+			name: "username without password",
+			mirrorConfig: &RegistryMirrorConfiguration{
+				Endpoint:       "1.2.3.4",
+				Authentication: &RegistryMirrorAuthentication{Username: "user"},
+			},
+			wantErr: "requires either username and password or credentialsSecretRef",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.RegistryMirrorConfiguration = tt.mirrorConfig
+			err := validateMirrorConfig(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateMirrorConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateMirrorConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateImageVerificationConfig(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	tests := []struct {
+		name    string
+		config  *ImageVerificationConfiguration
+		wantErr string
+	}{
+		{
+			name:   "no image verification config",
+			config: nil,
+		},
+		{
+			name:   "valid key",
+			config: &ImageVerificationConfiguration{CosignPublicKeys: []string{publicKey}},
+		},
+		{
+			name:    "no keys",
+			config:  &ImageVerificationConfiguration{},
+			wantErr: "requires at least one cosign public key",
+		},
+		{
+			name:    "not PEM encoded",
+			config:  &ImageVerificationConfiguration{CosignPublicKeys: []string{publicKey, "key"}},
+			wantErr: "cosign public key 1 is invalid: not PEM encoded",
+		},
+		{
+			name:    "not a public key",
+			config:  &ImageVerificationConfiguration{CosignPublicKeys: []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("key")}))}},
+			wantErr: "cosign public key 0 is invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.ImageVerificationConfiguration = tt.config
+			err := validateImageVerificationConfig(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateImageVerificationConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateImageVerificationConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpgradeHealthGates(t *testing.T) {
+	tests := []struct {
+		name    string
+		gates   *UpgradeHealthGates
+		wantErr string
+	}{
+		{
+			name:  "no gates",
+			gates: nil,
+		},
+		{
+			name: "valid gates",
+			gates: &UpgradeHealthGates{
+				Deployments:      []string{"default/app"},
+				DaemonSets:       []string{"monitoring/node-exporter"},
+				HTTPProbes:       []HTTPProbe{{URL: "https://app.example.com/healthz", ExpectedStatus: 204}},
+				Timeout:          &metav1.Duration{Duration: 5 * time.Minute},
+				NodeDrainTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		{
+			name:    "deployment without namespace",
+			gates:   &UpgradeHealthGates{Deployments: []string{"app"}},
+			wantErr: "invalid upgrade health gate deployment: app must have the format namespace/name",
+		},
+		{
+			name:    "daemonset with empty name",
+			gates:   &UpgradeHealthGates{DaemonSets: []string{"monitoring/"}},
+			wantErr: "invalid upgrade health gate daemonset",
+		},
+		{
+			name:    "probe without scheme",
+			gates:   &UpgradeHealthGates{HTTPProbes: []HTTPProbe{{URL: "tcp://app.example.com:80"}}},
+			wantErr: "scheme must be http or https",
+		},
+		{
+			name:    "probe with invalid status",
+			gates:   &UpgradeHealthGates{HTTPProbes: []HTTPProbe{{URL: "http://app.example.com", ExpectedStatus: 42}}},
+			wantErr: "invalid upgrade health gate http probe expected status 42",
+		},
+		{
+			name:    "negative drain timeout",
+			gates:   &UpgradeHealthGates{NodeDrainTimeout: &metav1.Duration{Duration: -time.Minute}},
+			wantErr: "nodeDrainTimeout must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.UpgradeHealthGates = tt.gates
+			err := validateUpgradeHealthGates(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateUpgradeHealthGates() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateUpgradeHealthGates() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEtcdBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		backup  *EtcdBackupConfiguration
+		wantErr string
+	}{
+		{
+			name: "no backup",
+		},
+		{
+			name:   "persistent volume claim",
+			backup: &EtcdBackupConfiguration{Schedule: "0 */6 * * *", Retention: 3, PersistentVolumeClaim: "etcd-backups"},
+		},
+		{
+			name: "s3 compatible endpoint",
+			backup: &EtcdBackupConfiguration{
+				Schedule: "30 2 * * *",
+				S3:       &EtcdBackupS3Configuration{Bucket: "backups", Endpoint: "http://minio.local:9000"},
+			},
+		},
+		{
+			name:    "invalid schedule",
+			backup:  &EtcdBackupConfiguration{Schedule: "@daily", PersistentVolumeClaim: "etcd-backups"},
+			wantErr: "it must be a cron expression with 5 fields",
+		},
+		{
+			name:    "negative retention",
+			backup:  &EtcdBackupConfiguration{Schedule: "0 0 * * *", Retention: -1, PersistentVolumeClaim: "etcd-backups"},
+			wantErr: "retention can't be negative",
+		},
+		{
+			name:    "no location",
+			backup:  &EtcdBackupConfiguration{Schedule: "0 0 * * *"},
+			wantErr: "exactly one of etcd backup persistentVolumeClaim and s3 must be set",
+		},
+		{
+			name: "both locations",
+			backup: &EtcdBackupConfiguration{
+				Schedule:              "0 0 * * *",
+				PersistentVolumeClaim: "etcd-backups",
+				S3:                    &EtcdBackupS3Configuration{Bucket: "backups"},
+			},
+			wantErr: "exactly one of etcd backup persistentVolumeClaim and s3 must be set",
+		},
+		{
+			name:    "s3 without bucket",
+			backup:  &EtcdBackupConfiguration{Schedule: "0 0 * * *", S3: &EtcdBackupS3Configuration{}},
+			wantErr: "etcd backup s3 bucket is required",
+		},
+		{
+			name:    "s3 invalid endpoint",
+			backup:  &EtcdBackupConfiguration{Schedule: "0 0 * * *", S3: &EtcdBackupS3Configuration{Bucket: "backups", Endpoint: "minio"}},
+			wantErr: "invalid etcd backup s3 endpoint minio",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.EtcdBackup = tt.backup
+			err := validateEtcdBackup(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateEtcdBackup() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateEtcdBackup() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNetworking(t *testing.T) {
+	tests := []struct {
+		name     string
+		pods     []string
+		services []string
+		wantErr  string
+	}{
+		{
+			name:     "ipv4",
+			pods:     []string{"192.168.0.0/16"},
+			services: []string{"10.96.0.0/12"},
+		},
+		{
+			name:     "ipv6",
+			pods:     []string{"fd00:100::/56"},
+			services: []string{"fd00:200::/108"},
+		},
+		{
+			name:     "dual-stack",
+			pods:     []string{"192.168.0.0/16", "fd00:100::/56"},
+			services: []string{"10.96.0.0/12", "fd00:200::/108"},
+		},
+		{
+			name:     "invalid pods cidr",
+			pods:     []string{"192.168.0.0/33"},
+			services: []string{"10.96.0.0/12"},
+			wantErr:  "invalid CIDR block format for Pods",
+		},
+		{
+			name:     "invalid second services cidr",
+			pods:     []string{"192.168.0.0/16", "fd00:100::/56"},
+			services: []string{"10.96.0.0/12", "fd00:200::/129"},
+			wantErr:  "invalid CIDR block for Services",
+		},
+		{
+			name:     "too many pods cidrs",
+			pods:     []string{"192.168.0.0/16", "fd00:100::/56", "10.10.0.0/16"},
+			services: []string{"10.96.0.0/12", "fd00:200::/108"},
+			wantErr:  "at most 2 CIDR blocks for Pods are supported, one per IP family",
+		},
+		{
+			name:     "same family services cidrs",
+			pods:     []string{"192.168.0.0/16"},
+			services: []string{"10.96.0.0/12", "10.10.0.0/16"},
+			wantErr:  "multiple CIDR blocks for Services must belong to different IP families for dual-stack networking",
+		},
+		{
+			name:     "mismatched families",
+			pods:     []string{"192.168.0.0/16"},
+			services: []string{"fd00:200::/108"},
+			wantErr:  "CIDR blocks for Pods and Services must have the same IP families in the same order",
+		},
+		{
+			name:     "mismatched primary family",
+			pods:     []string{"192.168.0.0/16", "fd00:100::/56"},
+			services: []string{"fd00:200::/108", "10.96.0.0/12"},
+			wantErr:  "CIDR blocks for Pods and Services must have the same IP families in the same order",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster("test")
+			c.Spec.ClusterNetwork.Pods.CidrBlocks = tt.pods
+			c.Spec.ClusterNetwork.Services.CidrBlocks = tt.services
+			c.Spec.ClusterNetwork.CNI = Cilium
+			err := validateNetworking(c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateNetworking() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateNetworking() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterNetworkIPFamilies(t *testing.T) {
+	n := &ClusterNetwork{Pods: Pods{CidrBlocks: []string{"192.168.0.0/16"}}}
+	if n.IPv6Enabled() || n.DualStack() {
+		t.Fatalf("ClusterNetwork %v should be IPv4-only", n.Pods.CidrBlocks)
+	}
+	n.Pods.CidrBlocks = []string{"fd00:100::/56"}
+	if !n.IPv6Enabled() || n.DualStack() {
+		t.Fatalf("ClusterNetwork %v should be IPv6-only", n.Pods.CidrBlocks)
+	}
+	n.Pods.CidrBlocks = []string{"192.168.0.0/16", "fd00:100::/56"}
+	if !n.IPv6Enabled() || !n.DualStack() {
+		t.Fatalf("ClusterNetwork %v should be dual-stack", n.Pods.CidrBlocks)
+	}
+}
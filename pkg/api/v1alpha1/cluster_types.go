@@ -5,6 +5,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/pkg/networkutils"
 )
 
 const (
@@ -47,6 +49,13 @@ type ClusterSpec struct {
 	ProxyConfiguration          *ProxyConfiguration          `json:"proxyConfiguration,omitempty"`
 	RegistryMirrorConfiguration *RegistryMirrorConfiguration `json:"registryMirrorConfiguration,omitempty"`
 	ManagementCluster           ManagementCluster            `json:"managementCluster,omitempty"`
+	LoadBalancer                *LoadBalancerConfiguration   `json:"loadBalancer,omitempty"`
+	// ImageVerificationConfiguration defines the keys used to verify image signatures before they are imported
+	ImageVerificationConfiguration *ImageVerificationConfiguration `json:"imageVerificationConfiguration,omitempty"`
+	// UpgradeHealthGates defines the checks that must pass after upgrading the control plane to upgrade the worker nodes
+	UpgradeHealthGates *UpgradeHealthGates `json:"upgradeHealthGates,omitempty"`
+	// EtcdBackup defines a scheduled backup of the cluster etcd, taken by a CronJob in the cluster
+	EtcdBackup *EtcdBackupConfiguration `json:"etcdBackup,omitempty"`
 }
 
 func (n *Cluster) Equal(o *Cluster) bool {
@@ -77,6 +86,12 @@ func (n *Cluster) Equal(o *Cluster) bool {
 	if !n.Spec.ClusterNetwork.Equal(&o.Spec.ClusterNetwork) {
 		return false
 	}
+	if !n.Spec.ClusterNetwork.Cilium.Equal(o.Spec.ClusterNetwork.Cilium) {
+		return false
+	}
+	if !n.Spec.ClusterNetwork.DNS.Equal(o.Spec.ClusterNetwork.DNS) {
+		return false
+	}
 	if !n.Spec.ExternalEtcdConfiguration.Equal(o.Spec.ExternalEtcdConfiguration) {
 		return false
 	}
@@ -89,6 +104,18 @@ func (n *Cluster) Equal(o *Cluster) bool {
 	if !n.ManagementClusterEqual(o) {
 		return false
 	}
+	if !n.Spec.LoadBalancer.Equal(o.Spec.LoadBalancer) {
+		return false
+	}
+	if !n.Spec.ImageVerificationConfiguration.Equal(o.Spec.ImageVerificationConfiguration) {
+		return false
+	}
+	if !n.Spec.UpgradeHealthGates.Equal(o.Spec.UpgradeHealthGates) {
+		return false
+	}
+	if !n.Spec.EtcdBackup.Equal(o.Spec.EtcdBackup) {
+		return false
+	}
 	return true
 }
 
@@ -108,6 +135,134 @@ func (n *ProxyConfiguration) Equal(o *ProxyConfiguration) bool {
 	return n.HttpProxy == o.HttpProxy && n.HttpsProxy == o.HttpsProxy && SliceEqual(n.NoProxy, o.NoProxy)
 }
 
+// ImageVerificationConfiguration defines how image signatures are verified when images are imported
+type ImageVerificationConfiguration struct {
+	// CosignPublicKeys are the PEM encoded public keys trusted to sign images with cosign.
+	// Images must be signed by at least one of them.
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+}
+
+func (n *ImageVerificationConfiguration) Equal(o *ImageVerificationConfiguration) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return SliceEqual(n.CosignPublicKeys, o.CosignPublicKeys)
+}
+
+// UpgradeHealthGates defines the checks run in the workload cluster once the control plane has been upgraded.
+// If any of them fails, the worker nodes upgrade is paused.
+type UpgradeHealthGates struct {
+	// Deployments that must be Available, as namespace/name
+	Deployments []string `json:"deployments,omitempty"`
+
+	// DaemonSets that must be rolled out and available in all their nodes, as namespace/name
+	DaemonSets []string `json:"daemonSets,omitempty"`
+
+	// HTTPProbes are the endpoints that must answer with the expected status.
+	// They are called from the machine running the CLI.
+	HTTPProbes []HTTPProbe `json:"httpProbes,omitempty"`
+
+	// Timeout is the maximum time to wait for all the gates to pass. Defaults to 10m
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// NodeDrainTimeout is the maximum time spent draining a worker node. Pods are evicted respecting their
+	// PodDisruptionBudgets and, once the timeout expires, the node is removed anyway. Unset waits forever
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+}
+
+// HTTPProbe is an HTTP endpoint checked by the upgrade health gates
+type HTTPProbe struct {
+	URL string `json:"url"`
+
+	// ExpectedStatus is the HTTP status code the endpoint must return. Defaults to 200
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+}
+
+func (n *UpgradeHealthGates) Equal(o *UpgradeHealthGates) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	if len(n.HTTPProbes) != len(o.HTTPProbes) {
+		return false
+	}
+	for i := range n.HTTPProbes {
+		if n.HTTPProbes[i] != o.HTTPProbes[i] {
+			return false
+		}
+	}
+	return SliceEqual(n.Deployments, o.Deployments) && SliceEqual(n.DaemonSets, o.DaemonSets) &&
+		durationEqual(n.Timeout, o.Timeout) && durationEqual(n.NodeDrainTimeout, o.NodeDrainTimeout)
+}
+
+// EtcdBackupConfiguration defines when etcd snapshots are taken, where they are stored and how many are kept.
+// Exactly one of PersistentVolumeClaim and S3 must be set. Snapshots aren't stored in the nodes, they are lost
+// when the machines are replaced on upgrades and remediation.
+type EtcdBackupConfiguration struct {
+	// Schedule is the cron schedule of the backups, like "0 */6 * * *"
+	Schedule string `json:"schedule"`
+
+	// Retention is the number of snapshots kept. Older ones are deleted after each backup. Defaults to 7
+	Retention int `json:"retention,omitempty"`
+
+	// PersistentVolumeClaim is the name of an existing claim in the eksa-system namespace of the cluster the snapshots are
+	// written to. Its volume must be attachable to the control plane nodes
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// S3 is the S3 or S3-compatible bucket the snapshots are uploaded to.
+	// The credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars of the CLI when the cluster is created or
+	// upgraded and copied to a Secret in the eksa-system namespace of the cluster
+	S3 *EtcdBackupS3Configuration `json:"s3,omitempty"`
+}
+
+// EtcdBackupS3Configuration defines the bucket etcd snapshots are uploaded to
+type EtcdBackupS3Configuration struct {
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the snapshot object keys
+	Prefix string `json:"prefix,omitempty"`
+
+	Region string `json:"region,omitempty"`
+
+	// Endpoint is the URL of an S3-compatible service, like MinIO. Objects are addressed with path-style URLs when set
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+func (n *EtcdBackupConfiguration) Equal(o *EtcdBackupConfiguration) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.Schedule == o.Schedule && n.Retention == o.Retention && n.PersistentVolumeClaim == o.PersistentVolumeClaim && n.S3.Equal(o.S3)
+}
+
+func (n *EtcdBackupS3Configuration) Equal(o *EtcdBackupS3Configuration) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return *n == *o
+}
+
+func durationEqual(n, o *metav1.Duration) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.Duration == o.Duration
+}
+
 // RegistryMirrorConfiguration defines the settings for image registry mirror
 type RegistryMirrorConfiguration struct {
 	// Endpoint defines the registry mirror endpoint to use for pulling images
@@ -115,6 +270,35 @@ type RegistryMirrorConfiguration struct {
 
 	// CACertContent defines the contents registry mirror CA certificate
 	CACertContent string `json:"caCertContent,omitempty"`
+
+	// Mirrors maps upstream registries to mirror endpoints. If empty, public.ecr.aws is mirrored to Endpoint
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+
+	// Authentication defines the credentials used to pull images from the mirror endpoints
+	Authentication *RegistryMirrorAuthentication `json:"authentication,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the mirror endpoints TLS certificates
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// RegistryMirror maps an upstream registry to a mirror endpoint
+type RegistryMirror struct {
+	// Registry is the upstream registry host, e.g. public.ecr.aws or docker.io
+	Registry string `json:"registry"`
+
+	// Endpoint is the mirror endpoint for the registry. Defaults to the registry mirror configuration endpoint
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// RegistryMirrorAuthentication defines the credentials for the registry mirror endpoints,
+// either inline or from a secret
+type RegistryMirrorAuthentication struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// CredentialsSecretRef references a secret in the management cluster with username and password keys.
+	// The secret namespace defaults to the cluster namespace.
+	CredentialsSecretRef *corev1.SecretReference `json:"credentialsSecretRef,omitempty"`
 }
 
 func (n *RegistryMirrorConfiguration) Equal(o *RegistryMirrorConfiguration) bool {
@@ -124,7 +308,119 @@ func (n *RegistryMirrorConfiguration) Equal(o *RegistryMirrorConfiguration) bool
 	if n == nil || o == nil {
 		return false
 	}
-	return n.Endpoint == o.Endpoint && n.CACertContent == o.CACertContent
+	return n.Endpoint == o.Endpoint && n.CACertContent == o.CACertContent &&
+		RegistryMirrorsEqual(n.Mirrors, o.Mirrors) && n.Authentication.Equal(o.Authentication) &&
+		n.InsecureSkipVerify == o.InsecureSkipVerify
+}
+
+// Endpoints returns the unique mirror endpoints
+func (n *RegistryMirrorConfiguration) Endpoints() []string {
+	if len(n.Mirrors) == 0 {
+		return []string{n.Endpoint}
+	}
+	seen := map[string]struct{}{}
+	var endpoints []string
+	for _, mirror := range n.Mirrors {
+		endpoint := n.mirrorEndpoint(mirror)
+		if _, ok := seen[endpoint]; ok {
+			continue
+		}
+		seen[endpoint] = struct{}{}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+func (n *RegistryMirrorConfiguration) mirrorEndpoint(mirror RegistryMirror) string {
+	if mirror.Endpoint != "" {
+		return mirror.Endpoint
+	}
+	return n.Endpoint
+}
+
+func RegistryMirrorsEqual(a, b []RegistryMirror) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *RegistryMirrorAuthentication) Equal(o *RegistryMirrorAuthentication) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	if n.Username != o.Username || n.Password != o.Password {
+		return false
+	}
+	if n.CredentialsSecretRef == nil || o.CredentialsSecretRef == nil {
+		return n.CredentialsSecretRef == o.CredentialsSecretRef
+	}
+	return *n.CredentialsSecretRef == *o.CredentialsSecretRef
+}
+
+// LoadBalancerConfiguration defines the settings for the load balancer serving LoadBalancer type Services
+type LoadBalancerConfiguration struct {
+	// Provider defines the load balancer implementation installed in the cluster, kube-vip or metallb
+	Provider LoadBalancerProvider `json:"provider,omitempty"`
+	// AddressPools defines the addresses assigned to LoadBalancer type Services
+	AddressPools []LoadBalancerAddressPool `json:"addressPools,omitempty"`
+}
+
+type LoadBalancerProvider string
+
+const (
+	KubeVipLoadBalancer LoadBalancerProvider = "kube-vip"
+	MetalLBLoadBalancer LoadBalancerProvider = "metallb"
+)
+
+var validLoadBalancerProviders = map[LoadBalancerProvider]struct{}{
+	KubeVipLoadBalancer: {},
+	MetalLBLoadBalancer: {},
+}
+
+type LoadBalancerAddressPool struct {
+	// Name of the address pool. For kube-vip it's the namespace the pool is used for or "global" to be used by all namespaces
+	Name string `json:"name,omitempty"`
+	// Addresses is a list of CIDR blocks or address ranges in the form start-end
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+const GlobalLoadBalancerAddressPool = "global"
+
+func (n *LoadBalancerConfiguration) Equal(o *LoadBalancerConfiguration) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	if n.Provider != o.Provider || len(n.AddressPools) != len(o.AddressPools) {
+		return false
+	}
+	for i := range n.AddressPools {
+		if !n.AddressPools[i].Equal(&o.AddressPools[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *LoadBalancerAddressPool) Equal(o *LoadBalancerAddressPool) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.Name == o.Name && SliceEqual(n.Addresses, o.Addresses)
 }
 
 type ControlPlaneConfiguration struct {
@@ -222,6 +518,10 @@ type ClusterNetwork struct {
 	Services Services `json:"services,omitempty"`
 	// CNI specifies the CNI plugin to be installed in the cluster
 	CNI CNI `json:"cni,omitempty"`
+	// Cilium defines the configuration options for the Cilium CNI
+	Cilium *CiliumConfig `json:"cilium,omitempty"`
+	// DNS defines the configuration for CoreDNS and NodeLocal DNSCache
+	DNS *DNS `json:"dns,omitempty"`
 }
 
 func (n *ClusterNetwork) Equal(o *ClusterNetwork) bool {
@@ -236,6 +536,16 @@ func (n *ClusterNetwork) Equal(o *ClusterNetwork) bool {
 		n.CNI == o.CNI
 }
 
+// IPv6Enabled returns true for IPv6-only and dual-stack clusters
+func (n *ClusterNetwork) IPv6Enabled() bool {
+	return networkutils.HasIPv6CIDR(n.Pods.CidrBlocks)
+}
+
+// DualStack returns true when pods get addresses from both IPv4 and IPv6 CIDR blocks
+func (n *ClusterNetwork) DualStack() bool {
+	return networkutils.HasIPv4CIDR(n.Pods.CidrBlocks) && networkutils.HasIPv6CIDR(n.Pods.CidrBlocks)
+}
+
 func SliceEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -306,8 +616,191 @@ var validCNIs = map[CNI]struct{}{
 	Cilium: {},
 }
 
+// CiliumConfig defines the configuration options for the Cilium CNI.
+// Unlike the rest of ClusterNetwork, these settings can be changed during an upgrade.
+type CiliumConfig struct {
+	// PolicyEnforcementMode determines the communication allowed between pods.
+	// Accepted values are default, always and never. Defaults to default.
+	PolicyEnforcementMode CiliumPolicyEnforcementMode `json:"policyEnforcementMode,omitempty"`
+	// IPAMMode selects how pod IPs are allocated. Accepted values are kubernetes and cluster-pool.
+	// Defaults to kubernetes.
+	IPAMMode CiliumIPAMMode `json:"ipamMode,omitempty"`
+	// Hubble defines the observability components deployed along Cilium.
+	Hubble *HubbleConfig `json:"hubble,omitempty"`
+	// Encryption enables transparent encryption of the traffic between pods.
+	Encryption *CiliumEncryption `json:"encryption,omitempty"`
+	// EgressMasquerade enables masquerading of the traffic leaving the cluster. Defaults to true.
+	EgressMasquerade *bool `json:"egressMasquerade,omitempty"`
+}
+
+func (n *CiliumConfig) Equal(o *CiliumConfig) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.PolicyEnforcementMode == o.PolicyEnforcementMode &&
+		n.IPAMMode == o.IPAMMode &&
+		n.Hubble.Equal(o.Hubble) &&
+		n.Encryption.Equal(o.Encryption) &&
+		n.EgressMasqueradeEnabled() == o.EgressMasqueradeEnabled()
+}
+
+// EgressMasqueradeEnabled returns whether egress masquerading is enabled, defaulting to true when not set.
+func (n *CiliumConfig) EgressMasqueradeEnabled() bool {
+	if n == nil || n.EgressMasquerade == nil {
+		return true
+	}
+	return *n.EgressMasquerade
+}
+
+type CiliumPolicyEnforcementMode string
+
+const (
+	CiliumPolicyModeDefault CiliumPolicyEnforcementMode = "default"
+	CiliumPolicyModeAlways  CiliumPolicyEnforcementMode = "always"
+	CiliumPolicyModeNever   CiliumPolicyEnforcementMode = "never"
+)
+
+var validCiliumPolicyEnforcementModes = map[CiliumPolicyEnforcementMode]struct{}{
+	CiliumPolicyModeDefault: {},
+	CiliumPolicyModeAlways:  {},
+	CiliumPolicyModeNever:   {},
+}
+
+type CiliumIPAMMode string
+
+const (
+	CiliumIPAMKubernetes  CiliumIPAMMode = "kubernetes"
+	CiliumIPAMClusterPool CiliumIPAMMode = "cluster-pool"
+)
+
+var validCiliumIPAMModes = map[CiliumIPAMMode]struct{}{
+	CiliumIPAMKubernetes:  {},
+	CiliumIPAMClusterPool: {},
+}
+
+type HubbleConfig struct {
+	// Relay deploys hubble-relay to expose cluster wide flow visibility.
+	Relay bool `json:"relay,omitempty"`
+	// UI deploys the hubble web UI. It requires Relay to be enabled.
+	UI bool `json:"ui,omitempty"`
+}
+
+func (n *HubbleConfig) Equal(o *HubbleConfig) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.Relay == o.Relay && n.UI == o.UI
+}
+
+type CiliumEncryption struct {
+	// Type defines the encryption protocol. Accepted values are wireguard and ipsec.
+	Type CiliumEncryptionType `json:"type,omitempty"`
+	// IPsecKeySecretName is the name of the secret in kube-system holding the IPsec keys.
+	// Only used with ipsec encryption, defaults to cilium-ipsec-keys.
+	IPsecKeySecretName string `json:"ipsecKeySecretName,omitempty"`
+}
+
+func (n *CiliumEncryption) Equal(o *CiliumEncryption) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	return n.Type == o.Type && n.IPsecKeySecretName == o.IPsecKeySecretName
+}
+
+type CiliumEncryptionType string
+
+const (
+	CiliumEncryptionWireguard CiliumEncryptionType = "wireguard"
+	CiliumEncryptionIPsec     CiliumEncryptionType = "ipsec"
+)
+
+var validCiliumEncryptionTypes = map[CiliumEncryptionType]struct{}{
+	CiliumEncryptionWireguard: {},
+	CiliumEncryptionIPsec:     {},
+}
+
+const defaultCiliumIPsecKeySecretName = "cilium-ipsec-keys"
+
+// KeySecretName returns the name of the secret holding the IPsec keys.
+func (n *CiliumEncryption) KeySecretName() string {
+	if n.IPsecKeySecretName == "" {
+		return defaultCiliumIPsecKeySecretName
+	}
+	return n.IPsecKeySecretName
+}
+
+// DNS defines the cluster DNS settings. When set, the CoreDNS Corefile is managed by EKS Anywhere
+// and can be changed during an upgrade.
+type DNS struct {
+	// UpstreamServers are the resolvers used for names outside the cluster domain.
+	// Defaults to the nameservers in the node /etc/resolv.conf.
+	UpstreamServers []string `json:"upstreamServers,omitempty"`
+	// StubDomains forward the queries for a domain to a specific set of servers
+	StubDomains []StubDomain `json:"stubDomains,omitempty"`
+	// NodeLocalDNS deploys a DNS cache on every node
+	NodeLocalDNS *NodeLocalDNS `json:"nodeLocalDNS,omitempty"`
+}
+
+type StubDomain struct {
+	// Domain is the DNS zone forwarded to Servers
+	Domain string `json:"domain,omitempty"`
+	// Servers are the resolvers for Domain
+	Servers []string `json:"servers,omitempty"`
+}
+
+type NodeLocalDNS struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// LocalIP is the link-local address the cache listens on. Defaults to 169.254.20.10.
+	LocalIP string `json:"localIP,omitempty"`
+}
+
+const defaultNodeLocalDNSIP = "169.254.20.10"
+
+func (n *DNS) Equal(o *DNS) bool {
+	if n == o {
+		return true
+	}
+	if n == nil || o == nil {
+		return false
+	}
+	if !SliceEqual(n.UpstreamServers, o.UpstreamServers) || len(n.StubDomains) != len(o.StubDomains) {
+		return false
+	}
+	for i := range n.StubDomains {
+		if n.StubDomains[i].Domain != o.StubDomains[i].Domain || !SliceEqual(n.StubDomains[i].Servers, o.StubDomains[i].Servers) {
+			return false
+		}
+	}
+	return n.NodeLocalDNSEnabled() == o.NodeLocalDNSEnabled() && n.NodeLocalDNS.IP() == o.NodeLocalDNS.IP()
+}
+
+// NodeLocalDNSEnabled returns whether NodeLocal DNSCache should be deployed.
+func (n *DNS) NodeLocalDNSEnabled() bool {
+	return n != nil && n.NodeLocalDNS != nil && n.NodeLocalDNS.Enabled
+}
+
+// IP returns the address the node local cache listens on.
+func (n *NodeLocalDNS) IP() string {
+	if n == nil || n.LocalIP == "" {
+		return defaultNodeLocalDNSIP
+	}
+	return n.LocalIP
+}
+
 // ClusterStatus defines the observed state of Cluster
-type ClusterStatus struct{}
+type ClusterStatus struct {
+	// FailureMessage describes the last error that stopped an operation on the cluster and requires user action
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
 
 type Ref struct {
 	Kind string `json:"kind,omitempty"`
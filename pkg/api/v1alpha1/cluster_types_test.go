@@ -834,6 +834,74 @@ func TestClusterEqualProxyConfiguration(t *testing.T) {
 	}
 }
 
+func TestClusterEqualEtcdBackup(t *testing.T) {
+	testCases := []struct {
+		testName                       string
+		cluster1Backup, cluster2Backup *v1alpha1.EtcdBackupConfiguration
+		want                           bool
+	}{
+		{
+			testName:       "both nil",
+			cluster1Backup: nil,
+			cluster2Backup: nil,
+			want:           true,
+		},
+		{
+			testName:       "one nil, one exists",
+			cluster1Backup: &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"},
+			cluster2Backup: nil,
+			want:           false,
+		},
+		{
+			testName: "both exist, same",
+			cluster1Backup: &v1alpha1.EtcdBackupConfiguration{
+				Schedule: "0 */6 * * *",
+				S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "backups"},
+			},
+			cluster2Backup: &v1alpha1.EtcdBackupConfiguration{
+				Schedule: "0 */6 * * *",
+				S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "backups"},
+			},
+			want: true,
+		},
+		{
+			testName:       "both exist, diff schedule",
+			cluster1Backup: &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"},
+			cluster2Backup: &v1alpha1.EtcdBackupConfiguration{Schedule: "0 0 * * *", PersistentVolumeClaim: "etcd-backups"},
+			want:           false,
+		},
+		{
+			testName: "both exist, diff bucket",
+			cluster1Backup: &v1alpha1.EtcdBackupConfiguration{
+				Schedule: "0 */6 * * *",
+				S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "backups"},
+			},
+			cluster2Backup: &v1alpha1.EtcdBackupConfiguration{
+				Schedule: "0 */6 * * *",
+				S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "other-backups"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testName, func(t *testing.T) {
+			cluster1 := &v1alpha1.Cluster{
+				Spec: v1alpha1.ClusterSpec{
+					EtcdBackup: tt.cluster1Backup,
+				},
+			}
+			cluster2 := &v1alpha1.Cluster{
+				Spec: v1alpha1.ClusterSpec{
+					EtcdBackup: tt.cluster2Backup,
+				},
+			}
+
+			g := NewWithT(t)
+			g.Expect(cluster1.Equal(cluster2)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestClusterEqualRegistryMirrorConfiguration(t *testing.T) {
 	testCases := []struct {
 		testName                   string
@@ -1150,6 +1218,44 @@ func TestRegistryMirrorConfigurationEqual(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			testName: "both exist, mirrors diff",
+			cluster1Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Mirrors: []v1alpha1.RegistryMirror{{Registry: "public.ecr.aws"}},
+			},
+			cluster2Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Mirrors: []v1alpha1.RegistryMirror{{Registry: "public.ecr.aws"}, {Registry: "docker.io"}},
+			},
+			want: false,
+		},
+		{
+			testName: "both exist, same mirrors",
+			cluster1Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Mirrors: []v1alpha1.RegistryMirror{{Registry: "docker.io", Endpoint: "1.2.3.4"}},
+			},
+			cluster2Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Mirrors: []v1alpha1.RegistryMirror{{Registry: "docker.io", Endpoint: "1.2.3.4"}},
+			},
+			want: true,
+		},
+		{
+			testName: "both exist, authentication diff",
+			cluster1Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Authentication: &v1alpha1.RegistryMirrorAuthentication{Username: "user", Password: "password1"},
+			},
+			cluster2Regi: &v1alpha1.RegistryMirrorConfiguration{
+				Authentication: &v1alpha1.RegistryMirrorAuthentication{Username: "user", Password: "password2"},
+			},
+			want: false,
+		},
+		{
+			testName: "both exist, insecure diff",
+			cluster1Regi: &v1alpha1.RegistryMirrorConfiguration{
+				InsecureSkipVerify: true,
+			},
+			cluster2Regi: &v1alpha1.RegistryMirrorConfiguration{},
+			want:         false,
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -4,10 +4,37 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const GitOpsConfigKind = "GitOpsConfig"
 
+// Used for generating yaml for generate clusterconfig command
+func NewGitOpsConfigGenerate(name string) *GitOpsConfigGenerate {
+	return &GitOpsConfigGenerate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       GitOpsConfigKind,
+			APIVersion: SchemeBuilder.GroupVersion.String(),
+		},
+		ObjectMeta: ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func (c *GitOpsConfigGenerate) APIVersion() string {
+	return c.TypeMeta.APIVersion
+}
+
+func (c *GitOpsConfigGenerate) Kind() string {
+	return c.TypeMeta.Kind
+}
+
+func (c *GitOpsConfigGenerate) Name() string {
+	return c.ObjectMeta.Name
+}
+
 func GetAndValidateGitOpsConfig(fileName string, refName string, clusterConfig *Cluster) (*GitOpsConfig, error) {
 	config, err := getGitOpsConfig(fileName)
 	if err != nil {
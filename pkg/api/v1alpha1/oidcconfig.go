@@ -3,10 +3,37 @@ package v1alpha1
 import (
 	"fmt"
 	"net/url"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const OIDCConfigKind = "OIDCConfig"
 
+// Used for generating yaml for generate clusterconfig command
+func NewOIDCConfigGenerate(name string) *OIDCConfigGenerate {
+	return &OIDCConfigGenerate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       OIDCConfigKind,
+			APIVersion: SchemeBuilder.GroupVersion.String(),
+		},
+		ObjectMeta: ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func (c *OIDCConfigGenerate) APIVersion() string {
+	return c.TypeMeta.APIVersion
+}
+
+func (c *OIDCConfigGenerate) Kind() string {
+	return c.TypeMeta.Kind
+}
+
+func (c *OIDCConfigGenerate) Name() string {
+	return c.ObjectMeta.Name
+}
+
 func GetAndValidateOIDCConfig(fileName string, refName string, clusterConfig *Cluster) (*OIDCConfig, error) {
 	config, err := getOIDCConfig(fileName)
 	if err != nil {
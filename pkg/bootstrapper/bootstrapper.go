@@ -59,7 +59,7 @@ func (b *Bootstrapper) CreateBootstrapCluster(ctx context.Context, clusterSpec *
 		}
 	}
 
-	err = cluster.ApplyExtraObjects(ctx, b.clusterClient, c, clusterSpec)
+	err = cluster.ApplyExtraObjects(ctx, b.clusterClient, c, clusterSpec, cluster.WithoutDNSConfig())
 	if err != nil {
 		return nil, fmt.Errorf("error applying extra objects to bootstrap cluster: %v", err)
 	}
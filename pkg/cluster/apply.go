@@ -13,8 +13,11 @@ type ClusterClient interface {
 	ApplyKubeSpecFromBytes(ctx context.Context, cluster *types.Cluster, data []byte) error
 }
 
-func ApplyExtraObjects(ctx context.Context, clusterClient ClusterClient, cluster *types.Cluster, clusterSpec *Spec) error {
-	extraObjects := BuildExtraObjects(clusterSpec)
+func ApplyExtraObjects(ctx context.Context, clusterClient ClusterClient, cluster *types.Cluster, clusterSpec *Spec, opts ...ExtraObjectsOpt) error {
+	extraObjects, err := BuildExtraObjects(clusterSpec, opts...)
+	if err != nil {
+		return err
+	}
 	if len(extraObjects) <= 0 {
 		return nil
 	}
@@ -22,7 +25,7 @@ func ApplyExtraObjects(ctx context.Context, clusterClient ClusterClient, cluster
 	resourcesSpec := templater.AppendYamlResources(extraObjects.Values()...)
 
 	logger.V(4).Info("Applying extra objects", "cluster", clusterSpec.Name, "resources", extraObjects.Names())
-	err := clusterClient.ApplyKubeSpecFromBytes(ctx, cluster, resourcesSpec)
+	err = clusterClient.ApplyKubeSpecFromBytes(ctx, cluster, resourcesSpec)
 	if err != nil {
 		return fmt.Errorf("error applying spec for extra resources to cluster %s: %v", cluster.Name, err)
 	}
@@ -0,0 +1,354 @@
+package cluster
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+// GetReleases returns all the EKS Anywhere releases in the releases manifest, newest first
+func (s *Spec) GetReleases() ([]v1alpha1.EksARelease, error) {
+	releases, err := s.reader.GetReleases(s.releasesManifestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]v1alpha1.EksARelease{}, releases.Spec.Releases...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Number > sorted[j].Number
+	})
+	return sorted, nil
+}
+
+// GetBundlesForRelease reads the bundles manifest of the release with the given bundle number
+func (s *Spec) GetBundlesForRelease(number int) (*v1alpha1.Bundles, error) {
+	releases, err := s.GetReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range releases {
+		if release.Number == number {
+			return s.reader.GetBundles(release.BundleManifestUrl)
+		}
+	}
+
+	return nil, fmt.Errorf("bundle %d does not exist in releases manifest %s", number, s.releasesManifestURL)
+}
+
+// BundleComponent groups the artifacts a versions bundle ships for one component
+type BundleComponent struct {
+	Name      string
+	Version   string
+	Images    []v1alpha1.Image
+	Manifests []v1alpha1.Manifest
+	Archives  []v1alpha1.Archive
+}
+
+// BundleComponents returns the components of a versions bundle sorted by name, skipping the
+// images, manifests and archives that are not set
+func BundleComponents(vb *v1alpha1.VersionsBundle) []BundleComponent {
+	components := []BundleComponent{
+		{
+			Name:     "eks-distro",
+			Version:  vb.EksD.Name,
+			Images:   []v1alpha1.Image{vb.EksD.KindNode},
+			Archives: []v1alpha1.Archive{vb.EksD.Ova.Bottlerocket.Archive, vb.EksD.Ova.Ubuntu.Archive},
+			Manifests: []v1alpha1.Manifest{
+				{URI: vb.EksD.EksDReleaseUrl},
+			},
+		},
+		{
+			Name:    "cert-manager",
+			Version: vb.CertManager.Version,
+			Images:  []v1alpha1.Image{vb.CertManager.Acmesolver, vb.CertManager.Cainjector, vb.CertManager.Controller, vb.CertManager.Webhook},
+		},
+		{
+			Name:      "cluster-api",
+			Version:   vb.ClusterAPI.Version,
+			Images:    []v1alpha1.Image{vb.ClusterAPI.Controller, vb.ClusterAPI.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.ClusterAPI.Components, vb.ClusterAPI.Metadata},
+		},
+		{
+			Name:      "cluster-api-kubeadm-bootstrap",
+			Version:   vb.Bootstrap.Version,
+			Images:    []v1alpha1.Image{vb.Bootstrap.Controller, vb.Bootstrap.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.Bootstrap.Components, vb.Bootstrap.Metadata},
+		},
+		{
+			Name:      "cluster-api-kubeadm-control-plane",
+			Version:   vb.ControlPlane.Version,
+			Images:    []v1alpha1.Image{vb.ControlPlane.Controller, vb.ControlPlane.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.ControlPlane.Components, vb.ControlPlane.Metadata},
+		},
+		{
+			Name:      "cluster-api-provider-aws",
+			Version:   vb.Aws.Version,
+			Images:    []v1alpha1.Image{vb.Aws.Controller, vb.Aws.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.Aws.Components, vb.Aws.ClusterTemplate, vb.Aws.Metadata},
+		},
+		{
+			Name:      "cluster-api-provider-docker",
+			Version:   vb.Docker.Version,
+			Images:    []v1alpha1.Image{vb.Docker.Manager, vb.Docker.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.Docker.Components, vb.Docker.ClusterTemplate, vb.Docker.Metadata},
+		},
+		{
+			Name:    "cluster-api-provider-vsphere",
+			Version: vb.VSphere.Version,
+			Images: []v1alpha1.Image{
+				vb.VSphere.ClusterAPIController, vb.VSphere.KubeProxy, vb.VSphere.Manager,
+				vb.VSphere.KubeVip, vb.VSphere.Driver, vb.VSphere.Syncer,
+			},
+			Manifests: []v1alpha1.Manifest{vb.VSphere.Components, vb.VSphere.ClusterTemplate, vb.VSphere.Metadata},
+		},
+		{
+			Name:      "eks-anywhere",
+			Version:   vb.Eksa.Version,
+			Images:    []v1alpha1.Image{vb.Eksa.CliTools, vb.Eksa.ClusterController, vb.Eksa.DiagnosticCollector},
+			Manifests: []v1alpha1.Manifest{vb.Eksa.Components},
+		},
+		{
+			Name:      "cilium",
+			Version:   vb.Cilium.Version,
+			Images:    []v1alpha1.Image{vb.Cilium.Cilium, vb.Cilium.Operator, vb.Cilium.HubbleRelay, vb.Cilium.HubbleUI, vb.Cilium.HubbleUIBackend},
+			Manifests: []v1alpha1.Manifest{vb.Cilium.Manifest},
+		},
+		{
+			Name:    "flux",
+			Version: vb.Flux.Version,
+			Images:  []v1alpha1.Image{vb.Flux.SourceController, vb.Flux.KustomizeController, vb.Flux.HelmController, vb.Flux.NotificationController},
+		},
+		{
+			Name:   "bottlerocket-bootstrap",
+			Images: []v1alpha1.Image{vb.BottleRocketBootstrap.Bootstrap},
+		},
+		{
+			Name:   "bottlerocket-admin",
+			Images: []v1alpha1.Image{vb.BottleRocketAdmin.Admin},
+		},
+		{
+			Name:      "etcdadm-bootstrap-provider",
+			Version:   vb.ExternalEtcdBootstrap.Version,
+			Images:    []v1alpha1.Image{vb.ExternalEtcdBootstrap.Controller, vb.ExternalEtcdBootstrap.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.ExternalEtcdBootstrap.Components, vb.ExternalEtcdBootstrap.Metadata},
+		},
+		{
+			Name:      "etcdadm-controller",
+			Version:   vb.ExternalEtcdController.Version,
+			Images:    []v1alpha1.Image{vb.ExternalEtcdController.Controller, vb.ExternalEtcdController.KubeProxy},
+			Manifests: []v1alpha1.Manifest{vb.ExternalEtcdController.Components, vb.ExternalEtcdController.Metadata},
+		},
+		{
+			Name:    "load-balancer",
+			Version: vb.LoadBalancer.Version,
+			Images:  []v1alpha1.Image{vb.LoadBalancer.KubeVipCloudProvider, vb.LoadBalancer.MetalLBController, vb.LoadBalancer.MetalLBSpeaker},
+		},
+		{
+			Name:    "node-local-dns",
+			Version: vb.NodeLocalDNS.Version,
+			Images:  []v1alpha1.Image{vb.NodeLocalDNS.Image},
+		},
+	}
+
+	nonEmpty := make([]BundleComponent, 0, len(components))
+	for _, c := range components {
+		c.Images = nonEmptyImages(c.Images)
+		c.Manifests = nonEmptyManifests(c.Manifests)
+		c.Archives = nonEmptyArchives(c.Archives)
+		if c.Version == "" && len(c.Images) == 0 && len(c.Manifests) == 0 && len(c.Archives) == 0 {
+			continue
+		}
+		nonEmpty = append(nonEmpty, c)
+	}
+	sort.SliceStable(nonEmpty, func(i, j int) bool {
+		return nonEmpty[i].Name < nonEmpty[j].Name
+	})
+	return nonEmpty
+}
+
+func nonEmptyImages(images []v1alpha1.Image) []v1alpha1.Image {
+	var result []v1alpha1.Image
+	for _, i := range images {
+		if i.URI != "" {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func nonEmptyManifests(manifests []v1alpha1.Manifest) []v1alpha1.Manifest {
+	var result []v1alpha1.Manifest
+	for _, m := range manifests {
+		if m.URI != "" {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func nonEmptyArchives(archives []v1alpha1.Archive) []v1alpha1.Archive {
+	var result []v1alpha1.Archive
+	for _, a := range archives {
+		if a.URI != "" {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// VersionsBundleDiff lists the component changes for one kubernetes version between two bundles.
+// Added and Removed are set when the kubernetes version is only supported by one of them.
+type VersionsBundleDiff struct {
+	KubeVersion string
+	Added       bool
+	Removed     bool
+	Components  []ComponentDiff
+}
+
+// ComponentDiff describes how a component changes between two bundles. Old values are empty for
+// new components and new values are empty for removed components.
+type ComponentDiff struct {
+	Name       string
+	OldVersion string
+	NewVersion string
+	Artifacts  []ArtifactDiff
+}
+
+// ArtifactDiff is a change in the URI or digest of an image, manifest or archive
+type ArtifactDiff struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// DiffBundles compares the components of every kubernetes version supported by any of the bundles.
+// Only the kubernetes versions and components with changes are returned.
+func DiffBundles(old, new *v1alpha1.Bundles) []VersionsBundleDiff {
+	oldBundles := versionsBundlesByKubeVersion(old)
+	newBundles := versionsBundlesByKubeVersion(new)
+
+	kubeVersions := make([]string, 0, len(oldBundles)+len(newBundles))
+	for kubeVersion := range oldBundles {
+		kubeVersions = append(kubeVersions, kubeVersion)
+	}
+	for kubeVersion := range newBundles {
+		if _, ok := oldBundles[kubeVersion]; !ok {
+			kubeVersions = append(kubeVersions, kubeVersion)
+		}
+	}
+	sort.Strings(kubeVersions)
+
+	var diffs []VersionsBundleDiff
+	for _, kubeVersion := range kubeVersions {
+		oldBundle, newBundle := oldBundles[kubeVersion], newBundles[kubeVersion]
+		diff := VersionsBundleDiff{
+			KubeVersion: kubeVersion,
+			Added:       oldBundle == nil,
+			Removed:     newBundle == nil,
+			Components:  diffComponents(bundleComponentsByName(oldBundle), bundleComponentsByName(newBundle)),
+		}
+		if diff.Added || diff.Removed || len(diff.Components) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+func versionsBundlesByKubeVersion(bundles *v1alpha1.Bundles) map[string]*v1alpha1.VersionsBundle {
+	m := map[string]*v1alpha1.VersionsBundle{}
+	for i := range bundles.Spec.VersionsBundles {
+		m[bundles.Spec.VersionsBundles[i].KubeVersion] = &bundles.Spec.VersionsBundles[i]
+	}
+	return m
+}
+
+func bundleComponentsByName(vb *v1alpha1.VersionsBundle) map[string]BundleComponent {
+	m := map[string]BundleComponent{}
+	if vb == nil {
+		return m
+	}
+	for _, c := range BundleComponents(vb) {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func diffComponents(old, new map[string]BundleComponent) []ComponentDiff {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []ComponentDiff
+	for _, name := range names {
+		o, n := old[name], new[name]
+		artifacts := diffArtifacts(componentArtifacts(o), componentArtifacts(n))
+		if o.Version == n.Version && len(artifacts) == 0 {
+			continue
+		}
+		diffs = append(diffs, ComponentDiff{
+			Name:       name,
+			OldVersion: o.Version,
+			NewVersion: n.Version,
+			Artifacts:  artifacts,
+		})
+	}
+	return diffs
+}
+
+// componentArtifacts identifies every artifact in the component by name, so the same artifact can be
+// matched across bundles: images by repository, manifests by file name and archives by asset name
+func componentArtifacts(c BundleComponent) map[string]string {
+	artifacts := map[string]string{}
+	for _, image := range c.Images {
+		artifacts[image.Image()] = imageWithDigest(image)
+	}
+	for _, manifest := range c.Manifests {
+		artifacts[path.Base(manifest.URI)] = manifest.URI
+	}
+	for _, archive := range c.Archives {
+		name := archive.Name
+		if name == "" {
+			name = path.Base(archive.URI)
+		}
+		artifacts[name] = archive.URI
+	}
+	return artifacts
+}
+
+func imageWithDigest(image v1alpha1.Image) string {
+	if image.ImageDigest == "" {
+		return image.URI
+	}
+	return image.URI + "@" + image.ImageDigest
+}
+
+func diffArtifacts(old, new map[string]string) []ArtifactDiff {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []ArtifactDiff
+	for _, name := range names {
+		if old[name] != new[name] {
+			diffs = append(diffs, ArtifactDiff{Name: name, Old: old[name], New: new[name]})
+		}
+	}
+	return diffs
+}
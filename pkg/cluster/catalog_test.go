@@ -0,0 +1,132 @@
+package cluster_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+func TestSpecGetReleases(t *testing.T) {
+	g := NewWithT(t)
+	s := cluster.NewSpec(cluster.WithReleasesManifest("testdata/catalog_release.yaml"))
+
+	releases, err := s.GetReleases()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(releases).To(HaveLen(2))
+	g.Expect(releases[0].Version).To(Equal("v0.6.1"), "newest release first")
+	g.Expect(releases[1].Version).To(Equal("v0.6.0"))
+}
+
+func TestSpecGetBundlesForRelease(t *testing.T) {
+	g := NewWithT(t)
+	s := cluster.NewSpec(cluster.WithReleasesManifest("testdata/catalog_release.yaml"))
+
+	bundles, err := s.GetBundlesForRelease(2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bundles.Spec.Number).To(Equal(2))
+
+	_, err = s.GetBundlesForRelease(3)
+	g.Expect(err).To(MatchError("bundle 3 does not exist in releases manifest testdata/catalog_release.yaml"))
+}
+
+func TestBundleComponents(t *testing.T) {
+	g := NewWithT(t)
+	vb := &v1alpha1.VersionsBundle{
+		KubeVersion: "1.21",
+		EksD: v1alpha1.EksDRelease{
+			Name:           "kubernetes-1-21-eks-4",
+			EksDReleaseUrl: "https://distro.eks.amazonaws.com/kubernetes-1-21-eks-4.yaml",
+			Ova: v1alpha1.ArchiveBundle{
+				Ubuntu: v1alpha1.OvaArchive{Archive: v1alpha1.Archive{Name: "ubuntu.ova", URI: "https://ovas/ubuntu.ova"}},
+			},
+		},
+		Cilium: v1alpha1.CiliumBundle{
+			Version:  "v1.9.10-eksa.1",
+			Cilium:   v1alpha1.Image{URI: "public.ecr.aws/isovalent/cilium:v1.9.10-eksa.1"},
+			Manifest: v1alpha1.Manifest{URI: "https://cilium.yaml"},
+		},
+	}
+
+	g.Expect(cluster.BundleComponents(vb)).To(Equal([]cluster.BundleComponent{
+		{
+			Name:      "cilium",
+			Version:   "v1.9.10-eksa.1",
+			Images:    []v1alpha1.Image{{URI: "public.ecr.aws/isovalent/cilium:v1.9.10-eksa.1"}},
+			Manifests: []v1alpha1.Manifest{{URI: "https://cilium.yaml"}},
+		},
+		{
+			Name:      "eks-distro",
+			Version:   "kubernetes-1-21-eks-4",
+			Manifests: []v1alpha1.Manifest{{URI: "https://distro.eks.amazonaws.com/kubernetes-1-21-eks-4.yaml"}},
+			Archives:  []v1alpha1.Archive{{Name: "ubuntu.ova", URI: "https://ovas/ubuntu.ova"}},
+		},
+	}))
+}
+
+func TestDiffBundles(t *testing.T) {
+	g := NewWithT(t)
+	s := cluster.NewSpec(cluster.WithReleasesManifest("testdata/catalog_release.yaml"))
+	old, err := s.GetBundlesForRelease(1)
+	g.Expect(err).NotTo(HaveOccurred())
+	new, err := s.GetBundlesForRelease(2)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	diffs := cluster.DiffBundles(old, new)
+	g.Expect(diffs).To(HaveLen(3))
+
+	g.Expect(diffs[0].KubeVersion).To(Equal("1.20"))
+	g.Expect(diffs[0].Removed).To(BeTrue())
+	g.Expect(diffs[0].Components).To(HaveLen(3))
+
+	g.Expect(diffs[1].KubeVersion).To(Equal("1.21"))
+	g.Expect(diffs[1].Added || diffs[1].Removed).To(BeFalse())
+	g.Expect(diffs[1].Components).To(Equal([]cluster.ComponentDiff{
+		{
+			Name:       "cilium",
+			OldVersion: "v1.9.10-eksa.1",
+			NewVersion: "v1.9.11-eksa.1",
+			Artifacts: []cluster.ArtifactDiff{
+				{
+					Name: "cilium.yaml",
+					Old:  "https://anywhere-assets.eks.amazonaws.com/releases/bundles/1/artifacts/cilium/manifests/cilium/v1.9.10-eksa.1/cilium.yaml",
+					New:  "https://anywhere-assets.eks.amazonaws.com/releases/bundles/2/artifacts/cilium/manifests/cilium/v1.9.11-eksa.1/cilium.yaml",
+				},
+				{
+					Name: "public.ecr.aws/isovalent/cilium",
+					Old:  "public.ecr.aws/isovalent/cilium:v1.9.10-eksa.1",
+					New:  "public.ecr.aws/isovalent/cilium:v1.9.11-eksa.1",
+				},
+				{
+					Name: "public.ecr.aws/isovalent/operator-generic",
+					Old:  "public.ecr.aws/isovalent/operator-generic:v1.9.10-eksa.1",
+					New:  "public.ecr.aws/isovalent/operator-generic:v1.9.11-eksa.1",
+				},
+			},
+		},
+		{
+			Name:       "node-local-dns",
+			NewVersion: "v1.8.4",
+			Artifacts: []cluster.ArtifactDiff{
+				{
+					Name: "public.ecr.aws/eks-anywhere/kubernetes/dns/k8s-dns-node-cache",
+					New:  "public.ecr.aws/eks-anywhere/kubernetes/dns/k8s-dns-node-cache:v1.8.4",
+				},
+			},
+		},
+	}))
+
+	g.Expect(diffs[2].KubeVersion).To(Equal("1.22"))
+	g.Expect(diffs[2].Added).To(BeTrue())
+}
+
+func TestDiffBundlesNoChanges(t *testing.T) {
+	g := NewWithT(t)
+	s := cluster.NewSpec(cluster.WithReleasesManifest("testdata/catalog_release.yaml"))
+	bundles, err := s.GetBundlesForRelease(1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cluster.DiffBundles(bundles, bundles.DeepCopy())).To(BeEmpty())
+}
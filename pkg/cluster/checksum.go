@@ -0,0 +1,21 @@
+package cluster
+
+import (
+	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+// ManifestChecksum returns the checksums published in the bundle for a manifest
+func ManifestChecksum(manifest v1alpha1.Manifest) files.Checksum {
+	return files.Checksum{SHA256: manifest.SHA256, SHA512: manifest.SHA512}
+}
+
+// ArchiveChecksum returns the checksums published in the bundle for an archive
+func ArchiveChecksum(archive v1alpha1.Archive) files.Checksum {
+	return files.Checksum{SHA256: archive.SHA256, SHA512: archive.SHA512}
+}
+
+// EksdManifestChecksum returns the checksums published in the bundle for the EKS-D release manifest
+func EksdManifestChecksum(eksd v1alpha1.EksDRelease) files.Checksum {
+	return files.Checksum{SHA256: eksd.ManifestSHA256, SHA512: eksd.ManifestSHA512}
+}
@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	_ "embed"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/templater"
+)
+
+//go:embed objects/coredns_configmap.yaml
+var coreDNSConfigMapTemplate string
+
+//go:embed objects/node_local_dns.yaml
+var nodeLocalDNSTemplate string
+
+const (
+	clusterDomain          = "cluster.local"
+	defaultUpstreamServers = "/etc/resolv.conf"
+	// kubeadm assigns the kube-dns service the tenth address of the services CIDR
+	clusterDNSIPOffset = 10
+)
+
+func buildCoreDNSConfigMap(clusterSpec *Spec) ([]byte, error) {
+	dns := clusterSpec.Spec.ClusterNetwork.DNS
+	upstreamServers := defaultUpstreamServers
+	if len(dns.UpstreamServers) > 0 {
+		upstreamServers = strings.Join(dns.UpstreamServers, " ")
+	}
+
+	return templater.Execute(coreDNSConfigMapTemplate, map[string]interface{}{
+		"clusterDomain":   clusterDomain,
+		"upstreamServers": upstreamServers,
+		"stubDomains":     dns.StubDomains,
+	})
+}
+
+func buildNodeLocalDNS(clusterSpec *Spec) ([]byte, error) {
+	image := clusterSpec.VersionsBundle.NodeLocalDNS.Image
+	if image.URI == "" {
+		return nil, fmt.Errorf("bundle for kubernetes version %s doesn't include a node-local-dns image", clusterSpec.VersionsBundle.KubeVersion)
+	}
+
+	dnsIP, err := clusterDNSIP(clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return templater.Execute(nodeLocalDNSTemplate, map[string]interface{}{
+		"clusterDomain": clusterDomain,
+		"image":         image.VersionedImage(),
+		"localIP":       clusterSpec.Spec.ClusterNetwork.DNS.NodeLocalDNS.IP(),
+		"dnsIP":         dnsIP,
+	})
+}
+
+func clusterDNSIP(servicesCidrBlocks []string) (string, error) {
+	if len(servicesCidrBlocks) == 0 {
+		return "", fmt.Errorf("services CIDR block not specified or empty")
+	}
+	_, cidr, err := net.ParseCIDR(servicesCidrBlocks[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid services CIDR block %s: %v", servicesCidrBlocks[0], err)
+	}
+
+	ip := make(net.IP, len(cidr.IP))
+	copy(ip, cidr.IP)
+	ip[len(ip)-1] += clusterDNSIPOffset
+
+	return ip.String(), nil
+}
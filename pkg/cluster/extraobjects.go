@@ -2,6 +2,8 @@ package cluster
 
 import (
 	_ "embed"
+	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -10,13 +12,53 @@ var coreDNSClusterRole []byte
 
 type KubeObjects map[string][]byte
 
-func BuildExtraObjects(clusterSpec *Spec) KubeObjects {
+type extraObjectsConfig struct {
+	skipDNS bool
+}
+
+type ExtraObjectsOpt func(*extraObjectsConfig)
+
+// WithoutDNSConfig skips the objects generated from the cluster dns configuration.
+// These describe the cluster network and shouldn't be applied to the bootstrap cluster.
+func WithoutDNSConfig() ExtraObjectsOpt {
+	return func(c *extraObjectsConfig) {
+		c.skipDNS = true
+	}
+}
+
+func BuildExtraObjects(clusterSpec *Spec, opts ...ExtraObjectsOpt) (KubeObjects, error) {
+	config := &extraObjectsConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	objects := make(map[string][]byte)
 	if needExtraCoreDNSRole(clusterSpec.VersionsBundle) {
 		objects["core-dns-clusterrole"] = coreDNSClusterRole
 	}
 
-	return objects
+	if config.skipDNS {
+		return objects, nil
+	}
+
+	dns := clusterSpec.Spec.ClusterNetwork.DNS
+	if dns != nil {
+		configMap, err := buildCoreDNSConfigMap(clusterSpec)
+		if err != nil {
+			return nil, fmt.Errorf("error generating coredns configmap: %v", err)
+		}
+		objects["core-dns-configmap"] = configMap
+	}
+
+	if dns.NodeLocalDNSEnabled() {
+		nodeLocalDNS, err := buildNodeLocalDNS(clusterSpec)
+		if err != nil {
+			return nil, fmt.Errorf("error generating node-local-dns: %v", err)
+		}
+		objects["node-local-dns"] = nodeLocalDNS
+	}
+
+	return objects, nil
 }
 
 func needExtraCoreDNSRole(bundle *VersionsBundle) bool {
@@ -32,10 +74,12 @@ func imageVersion(image VersionedRepository) string {
 	return tag
 }
 
+// Values returns the objects sorted by name so the generated spec is stable
 func (objs KubeObjects) Values() [][]byte {
+	names := objs.Names()
 	v := make([][]byte, 0, len(objs))
-	for _, o := range objs {
-		v = append(v, o)
+	for _, n := range names {
+		v = append(v, objs[n])
 	}
 
 	return v
@@ -46,6 +90,7 @@ func (objs KubeObjects) Names() []string {
 	for n := range objs {
 		v = append(v, n)
 	}
+	sort.Strings(v)
 
 	return v
 }
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/cluster"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
 func TestBuildExtraObjects(t *testing.T) {
@@ -39,10 +41,28 @@ func TestBuildExtraObjects(t *testing.T) {
 				"core-dns-clusterrole": "objects/coredns_clusterrole.yaml",
 			},
 		},
+		{
+			testName:    "kube 1.21, dns config, coredns configmap",
+			clusterSpec: dnsClusterSpec(t, false),
+			resourcesFileContent: map[string]string{
+				"core-dns-configmap": "testdata/expected_coredns_configmap.yaml",
+			},
+		},
+		{
+			testName:    "kube 1.21, dns config with node local dns, coredns configmap and node-local-dns",
+			clusterSpec: dnsClusterSpec(t, true),
+			resourcesFileContent: map[string]string{
+				"core-dns-configmap": "testdata/expected_coredns_configmap.yaml",
+				"node-local-dns":     "testdata/expected_node_local_dns.yaml",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
-			got := cluster.BuildExtraObjects(tt.clusterSpec)
+			got, err := cluster.BuildExtraObjects(tt.clusterSpec)
+			if err != nil {
+				t.Fatalf("BuildExtraObjects() error = %v, want nil", err)
+			}
 			for name, content := range got {
 				file, ok := tt.resourcesFileContent[name]
 				if !ok {
@@ -61,3 +81,40 @@ func TestBuildExtraObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildExtraObjectsWithoutDNSConfig(t *testing.T) {
+	got, err := cluster.BuildExtraObjects(dnsClusterSpec(t, true), cluster.WithoutDNSConfig())
+	if err != nil {
+		t.Fatalf("BuildExtraObjects() error = %v, want nil", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("BuildExtraObjects() = %v, want no objects", got.Names())
+	}
+}
+
+func TestBuildExtraObjectsNodeLocalDNSMissingImage(t *testing.T) {
+	clusterSpec := dnsClusterSpec(t, true)
+	clusterSpec.VersionsBundle.NodeLocalDNS.Image.URI = ""
+
+	if _, err := cluster.BuildExtraObjects(clusterSpec); err == nil {
+		t.Fatal("BuildExtraObjects() error = nil, want not nil")
+	}
+}
+
+func dnsClusterSpec(t *testing.T, nodeLocalDNS bool) *cluster.Spec {
+	s := clusterSpec(t, "1.21", "v1.8.4-eks-1-21-4")
+	s.Spec.ClusterNetwork.Services.CidrBlocks = []string{"10.96.0.0/12"}
+	s.Spec.ClusterNetwork.DNS = &v1alpha1.DNS{
+		UpstreamServers: []string{"10.0.0.2", "10.0.0.3"},
+		StubDomains: []v1alpha1.StubDomain{
+			{Domain: "corp.example.com", Servers: []string{"10.1.0.2", "10.1.0.3"}},
+			{Domain: "lab.example.com", Servers: []string{"10.2.0.2"}},
+		},
+	}
+	if nodeLocalDNS {
+		s.Spec.ClusterNetwork.DNS.NodeLocalDNS = &v1alpha1.NodeLocalDNS{Enabled: true}
+		s.VersionsBundle.NodeLocalDNS.Image = releasev1alpha1.Image{URI: "public.ecr.aws/eks-distro/kubernetes/dns/k8s-dns-node-cache:1.21.1-eks-1-21-4"}
+	}
+
+	return s
+}
@@ -19,9 +19,11 @@ func NewManifestReader(opts ...files.ReaderOpt) *ManifestReader {
 	return &ManifestReader{files.NewReader(opts...)}
 }
 
+// GetReleases reads the releases manifest, verified against its published checksum since it anchors the checksums
+// of the bundles manifests
 func (m *ManifestReader) GetReleases(releasesManifest string) (*v1alpha1.Release, error) {
 	logger.V(4).Info("Reading releases manifest", "url", releasesManifest)
-	content, err := m.ReadFile(releasesManifest)
+	content, err := m.ReadFileWithPublishedChecksum(releasesManifest)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +37,7 @@ func (m *ManifestReader) GetReleases(releasesManifest string) (*v1alpha1.Release
 }
 
 func (m *ManifestReader) GetEksdRelease(versionsBundle *v1alpha1.VersionsBundle) (*eksdv1alpha1.Release, error) {
-	content, err := m.ReadFile(versionsBundle.EksD.EksDReleaseUrl)
+	content, err := m.ReadFileWithChecksum(versionsBundle.EksD.EksDReleaseUrl, EksdManifestChecksum(versionsBundle.EksD))
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +50,11 @@ func (m *ManifestReader) GetEksdRelease(versionsBundle *v1alpha1.VersionsBundle)
 	return eksd, nil
 }
 
+// GetBundles reads the bundles manifest, verified against its published checksum since it holds the checksums
+// of every artifact in the bundle
 func (m *ManifestReader) GetBundles(bundlesURL string) (*v1alpha1.Bundles, error) {
 	logger.V(4).Info("Reading bundles manifest", "url", bundlesURL)
-	content, err := m.ReadFile(bundlesURL)
+	content, err := m.ReadFileWithPublishedChecksum(bundlesURL)
 	if err != nil {
 		return nil, err
 	}
@@ -14,6 +14,7 @@ import (
 	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/features"
 	"github.com/aws/eks-anywhere/pkg/files"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/semver"
 	"github.com/aws/eks-anywhere/pkg/types"
 	"github.com/aws/eks-anywhere/pkg/version"
@@ -42,6 +43,9 @@ type Spec struct {
 	eksdRelease         *eksdv1alpha1.Release
 	Bundles             *v1alpha1.Bundles
 	ManagementCluster   *types.Cluster
+	kubernetesVersion   eksav1alpha1.KubernetesVersion
+	// RegistryMirrorCredentials are the credentials loaded from the registry mirror credentials secret, if any
+	RegistryMirrorCredentials *registrymirror.Credentials
 }
 
 func (s *Spec) DeepCopy() *Spec {
@@ -58,11 +62,18 @@ func (s *Spec) DeepCopy() *Spec {
 			VersionsBundle: s.VersionsBundle.VersionsBundle.DeepCopy(),
 			KubeDistro:     s.VersionsBundle.KubeDistro.deepCopy(),
 		},
-		eksdRelease: s.eksdRelease.DeepCopy(),
-		Bundles:     s.Bundles.DeepCopy(),
+		eksdRelease:               s.eksdRelease.DeepCopy(),
+		Bundles:                   s.Bundles.DeepCopy(),
+		RegistryMirrorCredentials: s.RegistryMirrorCredentials,
 	}
 }
 
+// RegistryMirror returns the registry mirror configuration for the cluster, authenticating with the
+// credentials loaded from the credentials secret if they are set. It returns nil if there is no mirror.
+func (s *Spec) RegistryMirror() *registrymirror.Configuration {
+	return registrymirror.New(s.Cluster).WithCredentials(s.RegistryMirrorCredentials)
+}
+
 func (cs *Spec) SetDefaultGitOps() {
 	if cs != nil && cs.GitOpsConfig != nil {
 		c := &cs.GitOpsConfig.Spec.Flux
@@ -149,6 +160,14 @@ func WithGitOpsConfig(gitOpsConfig *eksav1alpha1.GitOpsConfig) SpecOpt {
 	}
 }
 
+// WithKubernetesVersion overrides the Kubernetes version from the cluster config file,
+// used to upgrade a cluster through intermediate versions
+func WithKubernetesVersion(kubeVersion eksav1alpha1.KubernetesVersion) SpecOpt {
+	return func(s *Spec) {
+		s.kubernetesVersion = kubeVersion
+	}
+}
+
 func NewSpec(opts ...SpecOpt) *Spec {
 	s := &Spec{
 		releasesManifestURL: releasesManifestURL,
@@ -177,6 +196,9 @@ func NewSpecFromClusterConfig(clusterConfigPath string, cliVersion version.Info,
 	if err != nil {
 		return nil, err
 	}
+	if s.kubernetesVersion != "" {
+		clusterConfig.Spec.KubernetesVersion = s.kubernetesVersion
+	}
 
 	bundles, err := s.GetBundles(cliVersion)
 	if err != nil {
@@ -446,7 +468,7 @@ func (s *Spec) LoadManifest(manifest v1alpha1.Manifest) (*Manifest, error) {
 		return nil, fmt.Errorf("invalid manifest URI: %v", err)
 	}
 
-	content, err := s.reader.ReadFile(manifest.URI)
+	content, err := s.reader.ReadFileWithChecksum(manifest.URI, ManifestChecksum(manifest))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load manifest: %v", err)
 	}
@@ -476,10 +498,21 @@ func (vb *VersionsBundle) SharedImages() []v1alpha1.Image {
 
 	images = append(images, vb.Cilium.Cilium)
 	images = append(images, vb.Cilium.Operator)
+	for _, hubbleImage := range []v1alpha1.Image{vb.Cilium.HubbleRelay, vb.Cilium.HubbleUI, vb.Cilium.HubbleUIBackend} {
+		if hubbleImage.URI != "" {
+			images = append(images, hubbleImage)
+		}
+	}
 
 	images = append(images, vb.ClusterAPI.Controller)
 	images = append(images, vb.ClusterAPI.KubeProxy)
 
+	for _, lbImage := range []v1alpha1.Image{vb.LoadBalancer.KubeVipCloudProvider, vb.LoadBalancer.MetalLBController, vb.LoadBalancer.MetalLBSpeaker} {
+		if lbImage.URI != "" {
+			images = append(images, lbImage)
+		}
+	}
+
 	images = append(images, vb.ControlPlane.Controller)
 	images = append(images, vb.ControlPlane.KubeProxy)
 
@@ -487,6 +520,10 @@ func (vb *VersionsBundle) SharedImages() []v1alpha1.Image {
 	images = append(images, vb.Eksa.CliTools)
 	images = append(images, vb.Eksa.ClusterController)
 
+	if vb.NodeLocalDNS.Image.URI != "" {
+		images = append(images, vb.NodeLocalDNS.Image)
+	}
+
 	images = append(images, vb.Flux.HelmController)
 	images = append(images, vb.Flux.KustomizeController)
 	images = append(images, vb.Flux.NotificationController)
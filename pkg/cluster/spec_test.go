@@ -2,6 +2,7 @@ package cluster_test
 
 import (
 	"embed"
+	"strings"
 	"testing"
 
 	"github.com/aws/eks-anywhere/internal/test"
@@ -140,6 +141,17 @@ func TestNewSpecWithBundlesOverrideValid(t *testing.T) {
 	validateSpecFromSimpleBundle(t, gotSpec)
 }
 
+func TestNewSpecWithKubernetesVersion(t *testing.T) {
+	v := version.Info{GitVersion: "v0.0.1"}
+	_, err := cluster.NewSpecFromClusterConfig("testdata/cluster_1_19.yaml", v,
+		cluster.WithReleasesManifest("testdata/simple_release.yaml"),
+		cluster.WithKubernetesVersion("1.22"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "kubernetes version 1.22 is not supported by bundles manifest") {
+		t.Fatalf("NewSpec() error = %v, want kubernetes version 1.22 not supported", err)
+	}
+}
+
 func validateSpecFromSimpleBundle(t *testing.T, gotSpec *cluster.Spec) {
 	validateVersionedRepo(t, gotSpec.VersionsBundle.KubeDistro.Kubernetes, "public.ecr.aws/eks-distro/kubernetes", "v1.19.8-eks-1-19-4")
 	validateVersionedRepo(t, gotSpec.VersionsBundle.KubeDistro.CoreDNS, "public.ecr.aws/eks-distro/coredns", "v1.8.0-eks-1-19-4")
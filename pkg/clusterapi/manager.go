@@ -21,15 +21,20 @@ type clients struct {
 func NewManager(capiClient CAPIClient, kubectlClient KubectlClient) *Manager {
 	return &Manager{
 		Installer: NewInstaller(capiClient, kubectlClient),
-		Upgrader:  NewUpgrader(capiClient, kubectlClient),
+		Upgrader:  NewUpgrader(capiClient),
 	}
 }
 
 type CAPIClient interface {
-	Upgrade(ctx context.Context, managementCluster *types.Cluster, provider providers.Provider, newSpec *cluster.Spec, changeDiff *CAPIChangeDiff) error
+	CAPIUpgradeClient
 	InstallEtcdadmProviders(ctx context.Context, clusterSpec *cluster.Spec, cluster *types.Cluster, provider providers.Provider, installProviders []string) error
 }
 
+// CAPIUpgradeClient upgrades the Cluster API providers of a management cluster
+type CAPIUpgradeClient interface {
+	Upgrade(ctx context.Context, managementCluster *types.Cluster, provider providers.Provider, newSpec *cluster.Spec, changeDiff *CAPIChangeDiff) error
+}
+
 type KubectlClient interface {
 	CheckProviderExists(ctx context.Context, kubeconfigFile, name, namespace string) (bool, error)
 }
@@ -11,15 +11,12 @@ import (
 )
 
 type Upgrader struct {
-	*clients
+	capiClient CAPIUpgradeClient
 }
 
-func NewUpgrader(capiClient CAPIClient, kubectlClient KubectlClient) *Upgrader {
+func NewUpgrader(capiClient CAPIUpgradeClient) *Upgrader {
 	return &Upgrader{
-		clients: &clients{
-			capiClient:    capiClient,
-			kubectlClient: kubectlClient,
-		},
+		capiClient: capiClient,
 	}
 }
 
@@ -64,6 +61,29 @@ func (c *CAPIChangeDiff) toChangeDiff() *types.ChangeDiff {
 }
 
 func (u *Upgrader) capiChangeDiff(currentSpec, newSpec *cluster.Spec, provider providers.Provider) *CAPIChangeDiff {
+	changeDiff := CoreComponentsChangeDiff(currentSpec, newSpec)
+	componentChanged := changeDiff != nil
+	if changeDiff == nil {
+		changeDiff = &CAPIChangeDiff{}
+	}
+
+	if providerChangeDiff := provider.ChangeDiff(currentSpec, newSpec); providerChangeDiff != nil {
+		changeDiff.InfrastructureProvider = providerChangeDiff
+		logger.V(1).Info("CAPI Infrastrcture Provider change diff", "provider", providerChangeDiff.ComponentName, "oldVersion", providerChangeDiff.OldVersion, "newVersion", providerChangeDiff.NewVersion)
+		componentChanged = true
+	}
+
+	if !componentChanged {
+		return nil
+	}
+
+	return changeDiff
+}
+
+// CoreComponentsChangeDiff compares cert-manager and the CAPI core, control plane and bootstrap providers
+// between two specs. The infrastructure provider is left out since only the provider knows how to compare it.
+// It returns nil when all the components have the same version.
+func CoreComponentsChangeDiff(currentSpec, newSpec *cluster.Spec) *CAPIChangeDiff {
 	changeDiff := &CAPIChangeDiff{}
 	componentChanged := false
 
@@ -130,12 +150,6 @@ func (u *Upgrader) capiChangeDiff(currentSpec, newSpec *cluster.Spec, provider p
 		componentChanged = true
 	}
 
-	if providerChangeDiff := provider.ChangeDiff(currentSpec, newSpec); providerChangeDiff != nil {
-		changeDiff.InfrastructureProvider = providerChangeDiff
-		logger.V(1).Info("CAPI Infrastrcture Provider change diff", "provider", providerChangeDiff.ComponentName, "oldVersion", providerChangeDiff.OldVersion, "newVersion", providerChangeDiff.NewVersion)
-		componentChanged = true
-	}
-
 	if !componentChanged {
 		return nil
 	}
@@ -21,7 +21,6 @@ type upgraderTest struct {
 	*WithT
 	ctx                context.Context
 	capiClient         *mocks.MockCAPIClient
-	kubectlClient      *mocks.MockKubectlClient
 	upgrader           *clusterapi.Upgrader
 	currentSpec        *cluster.Spec
 	newSpec            *cluster.Spec
@@ -33,7 +32,6 @@ type upgraderTest struct {
 func newUpgraderTest(t *testing.T) *upgraderTest {
 	ctrl := gomock.NewController(t)
 	capiClient := mocks.NewMockCAPIClient(ctrl)
-	kubectlClient := mocks.NewMockKubectlClient(ctrl)
 
 	currentSpec := test.NewClusterSpec(func(s *cluster.Spec) {
 		s.Bundles.Spec.Number = 1
@@ -46,13 +44,12 @@ func newUpgraderTest(t *testing.T) *upgraderTest {
 	})
 
 	return &upgraderTest{
-		WithT:         NewWithT(t),
-		ctx:           context.Background(),
-		capiClient:    capiClient,
-		kubectlClient: kubectlClient,
-		upgrader:      clusterapi.NewUpgrader(capiClient, kubectlClient),
-		currentSpec:   currentSpec,
-		newSpec:       currentSpec.DeepCopy(),
+		WithT:       NewWithT(t),
+		ctx:         context.Background(),
+		capiClient:  capiClient,
+		upgrader:    clusterapi.NewUpgrader(capiClient),
+		currentSpec: currentSpec,
+		newSpec:     currentSpec.DeepCopy(),
 		cluster: &types.Cluster{
 			Name:           "cluster-name",
 			KubeconfigFile: "k.kubeconfig",
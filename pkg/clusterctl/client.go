@@ -0,0 +1,148 @@
+package clusterctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	clusterctlconfig "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/clusterapi"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	generatedDir = "generated"
+	overridesDir = "overrides"
+)
+
+// Client upgrades the Cluster API providers with the clusterctl library instead of the clusterctl binary,
+// for the EKS-A controller, which image only ships the manager binary.
+// The provider env, like the vSphere credentials, is passed to clusterctl as the variables of each upgrade
+// instead of through the process env
+type Client struct {
+	writer filewriter.FileWriter
+}
+
+// New returns a Client that writes the clusterctl configuration and the overrides layer with writer
+func New(writer filewriter.FileWriter) *Client {
+	return &Client{writer: writer}
+}
+
+func (c *Client) Upgrade(ctx context.Context, managementCluster *types.Cluster, provider providers.Provider, newSpec *cluster.Spec, changeDiff *clusterapi.CAPIChangeDiff) error {
+	writer, err := c.writer.WithDir(managementCluster.Name)
+	if err != nil {
+		return fmt.Errorf("error creating clusterctl folder for cluster %s: %v", managementCluster.Name, err)
+	}
+	configFile, err := executables.WriteClusterctlConfig(writer, newSpec, provider, filepath.Join(writer.Dir(), generatedDir, overridesDir))
+	if err != nil {
+		return err
+	}
+
+	providerEnvMap, err := provider.EnvMap()
+	if err != nil {
+		return fmt.Errorf("failed generating provider env map for clusterctl upgrade: %v", err)
+	}
+
+	reader := &configReader{variables: providerEnvMap}
+	config, err := clusterctlconfig.New(configFile, clusterctlconfig.InjectReader(reader))
+	if err != nil {
+		return fmt.Errorf("error reading clusterctl configuration: %v", err)
+	}
+	client, err := clusterctlclient.New(configFile, clusterctlclient.InjectConfig(config))
+	if err != nil {
+		return fmt.Errorf("error building clusterctl client: %v", err)
+	}
+
+	targets := executables.NewClusterctlUpgradeTargets(changeDiff)
+	options := clusterctlclient.ApplyUpgradeOptions{
+		Kubeconfig:         clusterctlclient.Kubeconfig{Path: managementCluster.KubeconfigFile},
+		ManagementGroup:    fmt.Sprintf("%s/cluster-api", constants.CapiSystemNamespace),
+		CoreProvider:       targets.Core,
+		BootstrapProviders: targets.Bootstrap,
+	}
+	if targets.ControlPlane != "" {
+		options.ControlPlaneProviders = []string{targets.ControlPlane}
+	}
+	if targets.Infrastructure != "" {
+		options.InfrastructureProviders = []string{targets.Infrastructure}
+	}
+
+	if err = client.ApplyUpgrade(options); err != nil {
+		return fmt.Errorf("failed running upgrade apply with clusterctl: %v", err)
+	}
+
+	return nil
+}
+
+// configReader reads the clusterctl configuration file like the default clusterctl reader, but the variables
+// come from its own map instead of the process env, and the values aren't shared with other readers
+type configReader struct {
+	values    map[string]interface{}
+	variables map[string]string
+}
+
+func (r *configReader) Init(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading clusterctl configuration file: %v", err)
+	}
+	r.values = map[string]interface{}{}
+	if err = yaml.Unmarshal(content, &r.values); err != nil {
+		return fmt.Errorf("error parsing clusterctl configuration file: %v", err)
+	}
+	if r.variables == nil {
+		r.variables = map[string]string{}
+	}
+	return nil
+}
+
+func (r *configReader) Get(key string) (string, error) {
+	if value, ok := r.variables[key]; ok {
+		return value, nil
+	}
+	value, ok := r.value(key)
+	if !ok {
+		return "", fmt.Errorf("value for variable %q is not set", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func (r *configReader) Set(key, value string) {
+	r.variables[key] = value
+}
+
+func (r *configReader) UnmarshalKey(key string, value interface{}) error {
+	v, ok := r.value(key)
+	if !ok {
+		return nil
+	}
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, value)
+}
+
+// value returns the value of key in the configuration file. Keys are case insensitive, like in clusterctl
+func (r *configReader) value(key string) (interface{}, bool) {
+	for k, v := range r.values {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
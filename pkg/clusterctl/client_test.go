@@ -0,0 +1,79 @@
+package clusterctl
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testConfig = `providers:
+  - name: "vsphere"
+    url: "/overrides/infrastructure-vsphere/v0.7.8/infrastructure-components.yaml"
+    type: "InfrastructureProvider"
+overridesFolder: /overrides
+images:
+  cluster-api/cluster-api-controller:
+    repository: public.ecr.aws/l0g8r8j6/kubernetes-sigs/cluster-api
+    tag: v0.3.19
+`
+
+func givenConfigReader(t *testing.T, variables map[string]string) *configReader {
+	configFile := filepath.Join(t.TempDir(), "clusterctl.yaml")
+	if err := ioutil.WriteFile(configFile, []byte(testConfig), 0o600); err != nil {
+		t.Fatalf("failed writing config: %v", err)
+	}
+	r := &configReader{variables: variables}
+	if err := r.Init(configFile); err != nil {
+		t.Fatalf("configReader.Init() error = %v", err)
+	}
+	return r
+}
+
+func TestConfigReaderGet(t *testing.T) {
+	g := NewWithT(t)
+	r := givenConfigReader(t, map[string]string{"VSPHERE_USERNAME": "user"})
+
+	g.Expect(r.Get("VSPHERE_USERNAME")).To(Equal("user"))
+	g.Expect(r.Get("overridesfolder")).To(Equal("/overrides"), "config keys are case insensitive")
+	_, err := r.Get("VSPHERE_PASSWORD")
+	g.Expect(err).To(MatchError(`value for variable "VSPHERE_PASSWORD" is not set`))
+
+	r.Set("VSPHERE_PASSWORD", "password")
+	g.Expect(r.Get("VSPHERE_PASSWORD")).To(Equal("password"))
+}
+
+func TestConfigReaderDoesNotReadProcessEnv(t *testing.T) {
+	g := NewWithT(t)
+	r := givenConfigReader(t, nil)
+
+	_, err := r.Get("PATH")
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestConfigReaderUnmarshalKey(t *testing.T) {
+	g := NewWithT(t)
+	r := givenConfigReader(t, nil)
+
+	var providers []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	}
+	g.Expect(r.UnmarshalKey("providers", &providers)).To(Succeed())
+	g.Expect(providers).To(HaveLen(1))
+	g.Expect(providers[0].Name).To(Equal("vsphere"))
+	g.Expect(providers[0].Type).To(Equal("InfrastructureProvider"))
+
+	images := map[string]struct {
+		Repository string `json:"repository"`
+		Tag        string `json:"tag"`
+	}{}
+	g.Expect(r.UnmarshalKey("images", &images)).To(Succeed())
+	g.Expect(images["cluster-api/cluster-api-controller"].Tag).To(Equal("v0.3.19"))
+
+	missing := []string{"unchanged"}
+	g.Expect(r.UnmarshalKey("missing", &missing)).To(Succeed())
+	g.Expect(missing).To(Equal([]string{"unchanged"}))
+}
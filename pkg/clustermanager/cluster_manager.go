@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
 	"sigs.k8s.io/yaml"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
@@ -17,10 +20,13 @@ import (
 	"github.com/aws/eks-anywhere/pkg/clustermarshaller"
 	"github.com/aws/eks-anywhere/pkg/constants"
 	"github.com/aws/eks-anywhere/pkg/diagnostics"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/executables"
 	"github.com/aws/eks-anywhere/pkg/filewriter"
 	"github.com/aws/eks-anywhere/pkg/logger"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	"github.com/aws/eks-anywhere/pkg/retrier"
+	"github.com/aws/eks-anywhere/pkg/rollback"
 	"github.com/aws/eks-anywhere/pkg/templater"
 	"github.com/aws/eks-anywhere/pkg/types"
 	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
@@ -43,12 +49,14 @@ type ClusterManager struct {
 	clusterClient      *retrierClient
 	writer             filewriter.FileWriter
 	networking         Networking
+	loadBalancer       LoadBalancer
 	diagnosticsFactory diagnostics.DiagnosticBundleFactory
 	Retrier            *retrier.Retrier
 	machineMaxWait     time.Duration
 	machineBackoff     time.Duration
 	machinesMinWait    time.Duration
 	awsIamAuth         AwsIamAuth
+	healthGatesBackoff time.Duration
 }
 
 type ClusterClient interface {
@@ -56,6 +64,7 @@ type ClusterClient interface {
 	ApplyKubeSpecFromBytes(ctx context.Context, cluster *types.Cluster, data []byte) error
 	ApplyKubeSpecFromBytesWithNamespace(ctx context.Context, cluster *types.Cluster, data []byte, namespace string) error
 	ApplyKubeSpecFromBytesForce(ctx context.Context, cluster *types.Cluster, data []byte) error
+	DeleteKubeSpecFromBytes(ctx context.Context, cluster *types.Cluster, data []byte) error
 	WaitForControlPlaneReady(ctx context.Context, cluster *types.Cluster, timeout string, newClusterName string) error
 	WaitForManagedExternalEtcdReady(ctx context.Context, cluster *types.Cluster, timeout string, newClusterName string) error
 	GetWorkloadKubeconfig(ctx context.Context, clusterName string, cluster *types.Cluster) ([]byte, error)
@@ -65,6 +74,8 @@ type ClusterClient interface {
 	DeleteOIDCConfig(ctx context.Context, managementCluster *types.Cluster, oidcConfigName, oidcConfigNamespace string) error
 	DeleteAWSIamConfig(ctx context.Context, managementCluster *types.Cluster, awsIamConfigName, awsIamConfigNamespace string) error
 	DeleteEKSACluster(ctx context.Context, managementCluster *types.Cluster, eksaClusterName, eksaClusterNamespace string) error
+	DeleteCronJob(ctx context.Context, cluster *types.Cluster, cronJobName, namespace string) error
+	DeleteSecret(ctx context.Context, cluster *types.Cluster, secretName, namespace string) error
 	InitInfrastructure(ctx context.Context, clusterSpec *cluster.Spec, cluster *types.Cluster, provider providers.Provider) error
 	WaitForDeployment(ctx context.Context, cluster *types.Cluster, timeout string, condition string, target string, namespace string) error
 	SaveLog(ctx context.Context, cluster *types.Cluster, deployment *types.Deployment, fileName string, writer filewriter.FileWriter) error
@@ -84,10 +95,20 @@ type ClusterClient interface {
 	GetApiServerUrl(ctx context.Context, cluster *types.Cluster) (string, error)
 	GetClusterCATlsCert(ctx context.Context, clusterName string, cluster *types.Cluster, namespace string) ([]byte, error)
 	KubeconfigSecretAvailable(ctx context.Context, kubeconfig string, clusterName string, namespace string) (bool, error)
+	WaitForDaemonSetRollout(ctx context.Context, cluster *types.Cluster, timeout string, target string, namespace string) error
+	GetMachineDeployments(ctx context.Context, opts ...executables.KubectlOpt) ([]clusterv1.MachineDeployment, error)
+	MergePatchResource(ctx context.Context, resourceType, objectName, patch string, cluster *types.Cluster, namespace string) error
+	GetKubeadmControlPlane(ctx context.Context, cluster *types.Cluster, clusterName string, opts ...executables.KubectlOpt) (*kubeadmnv1alpha3.KubeadmControlPlane, error)
+	GetEtcdadmCluster(ctx context.Context, cluster *types.Cluster, clusterName string, opts ...executables.KubectlOpt) (*etcdv1alpha3.EtcdadmCluster, error)
 }
 
 type Networking interface {
 	GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error)
+	GenerateRemovedManifest(currentSpec, newSpec *cluster.Spec) ([]byte, error)
+}
+
+type LoadBalancer interface {
+	GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error)
 }
 
 type AwsIamAuth interface {
@@ -98,20 +119,22 @@ type AwsIamAuth interface {
 
 type ClusterManagerOpt func(*ClusterManager)
 
-func New(clusterClient ClusterClient, networking Networking, writer filewriter.FileWriter, diagnosticBundleFactory diagnostics.DiagnosticBundleFactory, awsIamAuth AwsIamAuth, opts ...ClusterManagerOpt) *ClusterManager {
+func New(clusterClient ClusterClient, networking Networking, loadBalancer LoadBalancer, writer filewriter.FileWriter, diagnosticBundleFactory diagnostics.DiagnosticBundleFactory, awsIamAuth AwsIamAuth, opts ...ClusterManagerOpt) *ClusterManager {
 	retrier := retrier.NewWithMaxRetries(maxRetries, backOffPeriod)
 	retrierClient := NewRetrierClient(NewClient(clusterClient), retrier)
 	c := &ClusterManager{
-		Upgrader:           NewUpgrader(retrierClient),
+		Upgrader:           NewUpgrader(retrierClient, networking),
 		clusterClient:      retrierClient,
 		writer:             writer,
 		networking:         networking,
+		loadBalancer:       loadBalancer,
 		Retrier:            retrier,
 		diagnosticsFactory: diagnosticBundleFactory,
 		machineMaxWait:     machineMaxWait,
 		machineBackoff:     machineBackoff,
 		machinesMinWait:    machinesMinWait,
 		awsIamAuth:         awsIamAuth,
+		healthGatesBackoff: httpProbeBackoff,
 	}
 
 	for _, o := range opts {
@@ -121,6 +144,13 @@ func New(clusterClient ClusterClient, networking Networking, writer filewriter.F
 	return c
 }
 
+// WithUpgradeHealthGatesBackoff sets the wait time between retries of the upgrade health gates http probes
+func WithUpgradeHealthGatesBackoff(backoff time.Duration) ClusterManagerOpt {
+	return func(c *ClusterManager) {
+		c.healthGatesBackoff = backoff
+	}
+}
+
 func WithWaitForMachines(machineBackoff, machineMaxWait, machinesMinWait time.Duration) ClusterManagerOpt {
 	return func(c *ClusterManager) {
 		c.machineBackoff = machineBackoff
@@ -375,6 +405,14 @@ func (c *ClusterManager) UpgradeCluster(ctx context.Context, managementCluster,
 		return fmt.Errorf("error waiting for workload cluster control plane replicas to be ready: %v", err)
 	}
 
+	if err = c.runUpgradeHealthGates(ctx, managementCluster, workloadCluster, newClusterSpec, provider); err != nil {
+		return err
+	}
+
+	if err = c.resumeWorkersUpgrade(ctx, managementCluster, workloadCluster, currentSpec); err != nil {
+		return fmt.Errorf("error resuming worker nodes upgrade: %v", err)
+	}
+
 	err = c.Retrier.Retry(
 		func() error {
 			return c.clusterClient.ApplyKubeSpecFromBytesWithNamespace(ctx, managementCluster, mdContent, constants.EksaSystemNamespace)
@@ -532,6 +570,27 @@ func (c *ClusterManager) InstallNetworking(ctx context.Context, cluster *types.C
 	return nil
 }
 
+func (c *ClusterManager) InstallLoadBalancer(ctx context.Context, cluster *types.Cluster, clusterSpec *cluster.Spec) error {
+	if clusterSpec.Spec.LoadBalancer == nil {
+		logger.V(4).Info("Skipping load balancer installation, no load balancer configured")
+		return nil
+	}
+
+	loadBalancerManifest, err := c.loadBalancer.GenerateManifest(clusterSpec)
+	if err != nil {
+		return fmt.Errorf("error generating load balancer manifest: %v", err)
+	}
+	err = c.Retrier.Retry(
+		func() error {
+			return c.clusterClient.ApplyKubeSpecFromBytes(ctx, cluster, loadBalancerManifest)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error applying load balancer manifest spec: %v", err)
+	}
+	return nil
+}
+
 func (c *ClusterManager) InstallStorageClass(ctx context.Context, cluster *types.Cluster, provider providers.Provider) error {
 	storageClass := provider.GenerateStorageClass()
 	if storageClass == nil {
@@ -676,6 +735,11 @@ func collectDiagnosticBundle(ctx context.Context, bundle diagnostics.DiagnosticB
 	err = bundle.CollectAndAnalyze(ctx, sinceTimeValue)
 	if err != nil {
 		logger.V(5).Info("Error collecting and saving logs", "error", err)
+		return nil
+	}
+
+	for _, analysis := range bundle.FailedAnalysis() {
+		logger.Info("Diagnostic check failed", "check", analysis.Title, "message", analysis.Message)
 	}
 	return nil
 }
@@ -1006,6 +1070,61 @@ func (c *ClusterManager) GetCurrentClusterSpec(ctx context.Context, clus *types.
 	return c.buildSpecForCluster(ctx, clus, eksaCluster)
 }
 
+// SnapshotCluster saves the EKS-A and CAPI objects of the cluster described by currentSpec to the cluster folder
+// before an upgrade, so they can be restored with upgrade cluster --rollback
+func (c *ClusterManager) SnapshotCluster(ctx context.Context, cluster *types.Cluster, currentSpec *cluster.Spec) error {
+	snapshot, err := rollback.TakeSnapshot(ctx, c.clusterClient, cluster, currentSpec)
+	if err != nil {
+		return fmt.Errorf("error taking cluster snapshot: %v", err)
+	}
+	content, err := snapshot.Marshal()
+	if err != nil {
+		return fmt.Errorf("error marshalling cluster snapshot: %v", err)
+	}
+	if _, err = c.writer.Write(rollback.SnapshotFileName(currentSpec.Name), content, filewriter.PersistentFile); err != nil {
+		return fmt.Errorf("error writing cluster snapshot: %v", err)
+	}
+	return nil
+}
+
+// InstallEtcdBackup installs the CronJob that takes the scheduled etcd backups of workloadCluster.
+// managementCluster holds the EtcdadmCluster of clusters with external etcd
+func (c *ClusterManager) InstallEtcdBackup(ctx context.Context, managementCluster, workloadCluster *types.Cluster, clusterSpec *cluster.Spec) error {
+	var endpoints []string
+	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
+		etcdadmCluster, err := c.clusterClient.GetEtcdadmCluster(ctx, managementCluster, clusterSpec.Name, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+		if err != nil {
+			return fmt.Errorf("error getting etcd endpoints for etcd backup: %v", err)
+		}
+		if etcdadmCluster.Status.Endpoints != "" {
+			endpoints = strings.Split(etcdadmCluster.Status.Endpoints, ",")
+		}
+	}
+
+	manifest, err := etcdbackup.CronJobManifest(clusterSpec, endpoints)
+	if err != nil {
+		return err
+	}
+	if err = c.clusterClient.ApplyKubeSpecFromBytes(ctx, workloadCluster, manifest); err != nil {
+		return fmt.Errorf("error applying etcd backup cronjob: %v", err)
+	}
+	return nil
+}
+
+// UninstallEtcdBackup removes the etcd backup CronJob of workloadCluster and, when the backups were uploaded
+// to S3, the secret with the S3 credentials. currentSpec is the spec the CronJob was installed with
+func (c *ClusterManager) UninstallEtcdBackup(ctx context.Context, workloadCluster *types.Cluster, currentSpec *cluster.Spec) error {
+	if err := c.clusterClient.DeleteCronJob(ctx, workloadCluster, etcdbackup.CronJobName(currentSpec.Name), constants.EksaSystemNamespace); err != nil {
+		return fmt.Errorf("error deleting etcd backup cronjob: %v", err)
+	}
+	if currentSpec.Spec.EtcdBackup.S3 != nil {
+		if err := c.clusterClient.DeleteSecret(ctx, workloadCluster, etcdbackup.CredentialsSecretName(currentSpec.Name), constants.EksaSystemNamespace); err != nil {
+			return fmt.Errorf("error deleting etcd backup s3 credentials: %v", err)
+		}
+	}
+	return nil
+}
+
 func (c *ClusterManager) buildSpecForCluster(ctx context.Context, clus *types.Cluster, eksaCluster *v1alpha1.Cluster) (*cluster.Spec, error) {
 	return cluster.BuildSpecForCluster(ctx, eksaCluster, c.bundlesFetcher(clus), c.gitOpsFetcher(clus))
 }
@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
 
 	"github.com/aws/eks-anywhere/internal/test"
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
@@ -22,6 +24,7 @@ import (
 	mocksmanager "github.com/aws/eks-anywhere/pkg/clustermanager/mocks"
 	"github.com/aws/eks-anywhere/pkg/constants"
 	mocksdiagnostics "github.com/aws/eks-anywhere/pkg/diagnostics/interfaces/mocks"
+	"github.com/aws/eks-anywhere/pkg/executables"
 	mockswriter "github.com/aws/eks-anywhere/pkg/filewriter/mocks"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	mocksprovider "github.com/aws/eks-anywhere/pkg/providers/mocks"
@@ -82,6 +85,49 @@ func TestClusterManagerInstallNetworkingClientError(t *testing.T) {
 	}
 }
 
+func TestClusterManagerInstallLoadBalancerSuccess(t *testing.T) {
+	ctx := context.Background()
+	cluster := &types.Cluster{}
+
+	loadBalancerManifest := []byte("kube-vip")
+	clusterSpec := test.NewClusterSpec()
+	clusterSpec.Spec.LoadBalancer = &v1alpha1.LoadBalancerConfiguration{Provider: v1alpha1.KubeVipLoadBalancer}
+
+	c, m := newClusterManager(t)
+	m.loadBalancer.EXPECT().GenerateManifest(clusterSpec).Return(loadBalancerManifest, nil)
+	m.client.EXPECT().ApplyKubeSpecFromBytes(ctx, cluster, loadBalancerManifest)
+
+	if err := c.InstallLoadBalancer(ctx, cluster, clusterSpec); err != nil {
+		t.Errorf("ClusterManager.InstallLoadBalancer() error = %v, wantErr nil", err)
+	}
+}
+
+func TestClusterManagerInstallLoadBalancerNotConfigured(t *testing.T) {
+	ctx := context.Background()
+	cluster := &types.Cluster{}
+	clusterSpec := test.NewClusterSpec()
+
+	c, _ := newClusterManager(t)
+
+	if err := c.InstallLoadBalancer(ctx, cluster, clusterSpec); err != nil {
+		t.Errorf("ClusterManager.InstallLoadBalancer() error = %v, wantErr nil", err)
+	}
+}
+
+func TestClusterManagerInstallLoadBalancerGenerateError(t *testing.T) {
+	ctx := context.Background()
+	cluster := &types.Cluster{}
+	clusterSpec := test.NewClusterSpec()
+	clusterSpec.Spec.LoadBalancer = &v1alpha1.LoadBalancerConfiguration{Provider: v1alpha1.MetalLBLoadBalancer}
+
+	c, m := newClusterManager(t)
+	m.loadBalancer.EXPECT().GenerateManifest(clusterSpec).Return(nil, errors.New("error in load balancer"))
+
+	if err := c.InstallLoadBalancer(ctx, cluster, clusterSpec); err == nil {
+		t.Errorf("ClusterManager.InstallLoadBalancer() error = nil, wantErr not nil")
+	}
+}
+
 func TestClusterManagerInstallStorageClassSuccess(t *testing.T) {
 	ctx := context.Background()
 	cluster := &types.Cluster{}
@@ -204,9 +250,13 @@ func TestClusterManagerSaveLogsSuccess(t *testing.T) {
 	b := m.diagnosticsBundle
 	m.diagnosticsFactory.EXPECT().DiagnosticBundleManagementCluster(bootstrapCluster.KubeconfigFile).Return(b, nil)
 	b.EXPECT().CollectAndAnalyze(ctx, gomock.AssignableToTypeOf(&time.Time{}))
+	b.EXPECT().FailedAnalysis().Return([]*executables.SupportBundleAnalysis{
+		{Title: "log analysis:: etcd quorum lost", IsFail: true, Message: "etcd lost quorum"},
+	})
 
 	m.diagnosticsFactory.EXPECT().DiagnosticBundleFromSpec(clusterSpec, m.provider, workloadCluster.KubeconfigFile).Return(b, nil)
 	b.EXPECT().CollectAndAnalyze(ctx, gomock.AssignableToTypeOf(&time.Time{}))
+	b.EXPECT().FailedAnalysis().Return(nil)
 
 	if err := c.SaveLogsManagementCluster(ctx, bootstrapCluster); err != nil {
 		t.Errorf("ClusterManager.SaveLogsManagementCluster() error = %v, wantErr nil", err)
@@ -1086,6 +1136,7 @@ func newTest(t *testing.T, opts ...clustermanager.ClusterManagerOpt) *testSetup
 type clusterManagerMocks struct {
 	writer             *mockswriter.MockFileWriter
 	networking         *mocksmanager.MockNetworking
+	loadBalancer       *mocksmanager.MockLoadBalancer
 	awsIamAuth         *mocksmanager.MockAwsIamAuth
 	client             *mocksmanager.MockClusterClient
 	provider           *mocksprovider.MockProvider
@@ -1098,6 +1149,7 @@ func newClusterManager(t *testing.T, opts ...clustermanager.ClusterManagerOpt) (
 	m := &clusterManagerMocks{
 		writer:             mockswriter.NewMockFileWriter(mockCtrl),
 		networking:         mocksmanager.NewMockNetworking(mockCtrl),
+		loadBalancer:       mocksmanager.NewMockLoadBalancer(mockCtrl),
 		awsIamAuth:         mocksmanager.NewMockAwsIamAuth(mockCtrl),
 		client:             mocksmanager.NewMockClusterClient(mockCtrl),
 		provider:           mocksprovider.NewMockProvider(mockCtrl),
@@ -1105,7 +1157,7 @@ func newClusterManager(t *testing.T, opts ...clustermanager.ClusterManagerOpt) (
 		diagnosticsBundle:  mocksdiagnostics.NewMockDiagnosticBundle(mockCtrl),
 	}
 
-	c := clustermanager.New(m.client, m.networking, m.writer, m.diagnosticsFactory, m.awsIamAuth, opts...)
+	c := clustermanager.New(m.client, m.networking, m.loadBalancer, m.writer, m.diagnosticsFactory, m.awsIamAuth, opts...)
 
 	return c, m
 }
@@ -1128,3 +1180,92 @@ func TestClusterManagerGetCurrentClusterSpecGetBundlesError(t *testing.T) {
 	_, err := tt.clusterManager.GetCurrentClusterSpec(tt.ctx, tt.cluster, tt.clusterName)
 	tt.Expect(err).ToNot(BeNil())
 }
+
+func TestClusterManagerSnapshotCluster(t *testing.T) {
+	tt := newTest(t)
+	kcp := &kubeadmnv1alpha3.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: tt.clusterName},
+		Spec:       kubeadmnv1alpha3.KubeadmControlPlaneSpec{Version: "v1.20.7-eks-1-20-4"},
+	}
+	tt.mocks.client.EXPECT().GetKubeadmControlPlane(tt.ctx, tt.cluster, tt.clusterSpec.Name, gomock.Any(), gomock.Any()).Return(kcp, nil)
+	tt.mocks.client.EXPECT().GetMachineDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+	tt.mocks.writer.EXPECT().Write(tt.clusterSpec.Name+"-eks-a-upgrade-snapshot.yaml", gomock.Any(), gomock.Any())
+
+	tt.Expect(tt.clusterManager.SnapshotCluster(tt.ctx, tt.cluster, tt.clusterSpec)).To(Succeed())
+}
+
+func TestClusterManagerSnapshotClusterError(t *testing.T) {
+	tt := newTest(t)
+	tt.mocks.client.EXPECT().GetKubeadmControlPlane(tt.ctx, tt.cluster, tt.clusterSpec.Name, gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting kcp"))
+
+	tt.Expect(tt.clusterManager.SnapshotCluster(tt.ctx, tt.cluster, tt.clusterSpec)).To(MatchError("error taking cluster snapshot: error getting kcp"))
+}
+
+func TestClusterManagerInstallEtcdBackupStackedEtcd(t *testing.T) {
+	tt := newTest(t)
+	workloadCluster := &types.Cluster{Name: tt.clusterName, KubeconfigFile: "workload.kubeconfig"}
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	tt.mocks.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, workloadCluster, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *types.Cluster, data []byte) error {
+			tt.Expect(string(data)).To(ContainSubstring("--endpoints=https://127.0.0.1:2379"))
+			return nil
+		},
+	)
+
+	tt.Expect(tt.clusterManager.InstallEtcdBackup(tt.ctx, tt.cluster, workloadCluster, tt.clusterSpec)).To(Succeed())
+}
+
+func TestClusterManagerInstallEtcdBackupExternalEtcd(t *testing.T) {
+	tt := newTest(t)
+	workloadCluster := &types.Cluster{Name: tt.clusterName, KubeconfigFile: "workload.kubeconfig"}
+	tt.clusterSpec.Spec.ExternalEtcdConfiguration = &v1alpha1.ExternalEtcdConfiguration{Count: 3}
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	etcdadmCluster := &etcdv1alpha3.EtcdadmCluster{
+		Status: etcdv1alpha3.EtcdadmClusterStatus{Endpoints: "https://10.0.0.1:2379,https://10.0.0.2:2379"},
+	}
+	tt.mocks.client.EXPECT().GetEtcdadmCluster(tt.ctx, tt.cluster, tt.clusterSpec.Name, gomock.Any(), gomock.Any()).Return(etcdadmCluster, nil)
+	tt.mocks.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, workloadCluster, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *types.Cluster, data []byte) error {
+			tt.Expect(string(data)).To(ContainSubstring("--endpoints=https://10.0.0.1:2379,https://10.0.0.2:2379"))
+			return nil
+		},
+	)
+
+	tt.Expect(tt.clusterManager.InstallEtcdBackup(tt.ctx, tt.cluster, workloadCluster, tt.clusterSpec)).To(Succeed())
+}
+
+func TestClusterManagerInstallEtcdBackupApplyError(t *testing.T) {
+	tt := newTest(t)
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	tt.mocks.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.cluster, gomock.Any()).Return(errors.New("error applying"))
+
+	tt.Expect(tt.clusterManager.InstallEtcdBackup(tt.ctx, tt.cluster, tt.cluster, tt.clusterSpec)).To(MatchError("error applying etcd backup cronjob: error applying"))
+}
+
+func TestClusterManagerUninstallEtcdBackupPersistentVolumeClaim(t *testing.T) {
+	tt := newTest(t)
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	tt.mocks.client.EXPECT().DeleteCronJob(tt.ctx, tt.cluster, tt.clusterSpec.Name+"-etcd-backup", constants.EksaSystemNamespace)
+
+	tt.Expect(tt.clusterManager.UninstallEtcdBackup(tt.ctx, tt.cluster, tt.clusterSpec)).To(Succeed())
+}
+
+func TestClusterManagerUninstallEtcdBackupS3(t *testing.T) {
+	tt := newTest(t)
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{
+		Schedule: "0 */6 * * *",
+		S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "backups"},
+	}
+	tt.mocks.client.EXPECT().DeleteCronJob(tt.ctx, tt.cluster, tt.clusterSpec.Name+"-etcd-backup", constants.EksaSystemNamespace)
+	tt.mocks.client.EXPECT().DeleteSecret(tt.ctx, tt.cluster, tt.clusterSpec.Name+"-etcd-backup-s3-credentials", constants.EksaSystemNamespace)
+
+	tt.Expect(tt.clusterManager.UninstallEtcdBackup(tt.ctx, tt.cluster, tt.clusterSpec)).To(Succeed())
+}
+
+func TestClusterManagerUninstallEtcdBackupDeleteError(t *testing.T) {
+	tt := newTest(t)
+	tt.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	tt.mocks.client.EXPECT().DeleteCronJob(tt.ctx, tt.cluster, tt.clusterSpec.Name+"-etcd-backup", constants.EksaSystemNamespace).Return(errors.New("error deleting"))
+
+	tt.Expect(tt.clusterManager.UninstallEtcdBackup(tt.ctx, tt.cluster, tt.clusterSpec)).To(MatchError("error deleting etcd backup cronjob: error deleting"))
+}
@@ -0,0 +1,176 @@
+package clustermanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/retrier"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	defaultUpgradeHealthGatesTimeout = 10 * time.Minute
+	httpProbeBackoff                 = 10 * time.Second
+	httpProbeRequestTimeout          = 10 * time.Second
+	machineDeploymentResourceType    = "machinedeployments.cluster.x-k8s.io"
+)
+
+// runUpgradeHealthGates checks the upgrade health gates in the workload cluster once the control plane has been upgraded.
+// If any of them fails, the worker nodes upgrade is paused and the failure is recorded in the EKS-A cluster status.
+func (c *ClusterManager) runUpgradeHealthGates(ctx context.Context, managementCluster, eksaCluster *types.Cluster, clusterSpec *cluster.Spec, provider providers.Provider) error {
+	gates := clusterSpec.Spec.UpgradeHealthGates
+	if gates == nil {
+		return nil
+	}
+
+	kubeconfig, err := c.generateWorkloadKubeconfig(ctx, clusterSpec.Name, managementCluster, provider)
+	if err != nil {
+		return err
+	}
+	workloadCluster := &types.Cluster{Name: clusterSpec.Name, KubeconfigFile: kubeconfig}
+
+	logger.V(3).Info("Running upgrade health gates")
+	err = c.checkUpgradeHealthGates(ctx, workloadCluster, gates)
+	if err == nil {
+		return nil
+	}
+
+	gatesErr := fmt.Errorf("upgrade health gates failed after upgrading the control plane: %v", err)
+	if err = c.pauseWorkersUpgrade(ctx, managementCluster, eksaCluster, clusterSpec, gatesErr.Error()); err != nil {
+		return fmt.Errorf("%v, error pausing worker nodes upgrade: %v", gatesErr, err)
+	}
+
+	logger.Info("Worker nodes upgrade paused", "cluster", clusterSpec.Name, "reason", gatesErr.Error())
+	logger.Info("The control plane runs the new Kubernetes version and can't be downgraded, worker nodes keep the previous version")
+	logger.Info("Fix the failing workloads and run eksctl anywhere upgrade cluster again to resume the worker nodes upgrade")
+	return gatesErr
+}
+
+func (c *ClusterManager) checkUpgradeHealthGates(ctx context.Context, workloadCluster *types.Cluster, gates *v1alpha1.UpgradeHealthGates) error {
+	timeout := defaultUpgradeHealthGatesTimeout
+	if gates.Timeout != nil {
+		timeout = gates.Timeout.Duration
+	}
+	deadline := time.Now().Add(timeout)
+
+	for _, d := range gates.Deployments {
+		namespace, name := splitNamespacedName(d)
+		err := c.clusterClient.WaitForDeployment(ctx, workloadCluster, remainingWaitStr(deadline), "Available", name, namespace)
+		if err != nil {
+			return fmt.Errorf("deployment %s is not available: %v", d, err)
+		}
+	}
+
+	for _, d := range gates.DaemonSets {
+		namespace, name := splitNamespacedName(d)
+		err := c.clusterClient.WaitForDaemonSetRollout(ctx, workloadCluster, remainingWaitStr(deadline), name, namespace)
+		if err != nil {
+			return fmt.Errorf("daemonset %s is not available: %v", d, err)
+		}
+	}
+
+	probeRetrier := retrier.New(time.Until(deadline), retrier.WithRetryPolicy(func(_ int, _ error) (bool, time.Duration) {
+		return true, c.healthGatesBackoff
+	}))
+	client := &http.Client{Timeout: httpProbeRequestTimeout}
+	for _, p := range gates.HTTPProbes {
+		probe := p
+		if err := probeRetrier.Retry(func() error { return checkHTTPProbe(ctx, client, probe) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkHTTPProbe(ctx context.Context, client *http.Client, probe v1alpha1.HTTPProbe) error {
+	expectedStatus := probe.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid http probe %s: %v", probe.URL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http probe %s failed: %v", probe.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return fmt.Errorf("http probe %s failed: got status %d, want %d", probe.URL, resp.StatusCode, expectedStatus)
+	}
+	return nil
+}
+
+// pauseWorkersUpgrade pauses the cluster machine deployments, so neither the CLI nor the EKS-A controller
+// can roll out the worker nodes, and records the reason in the EKS-A cluster status
+func (c *ClusterManager) pauseWorkersUpgrade(ctx context.Context, managementCluster, eksaCluster *types.Cluster, clusterSpec *cluster.Spec, reason string) error {
+	if err := c.setMachineDeploymentsPaused(ctx, managementCluster, clusterSpec.Name, true); err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"failureMessage": reason}})
+	if err != nil {
+		return err
+	}
+	return c.clusterClient.MergePatchResource(ctx, clusterSpec.ResourceType(), clusterSpec.Name, string(patch), eksaCluster, clusterSpec.Namespace)
+}
+
+// resumeWorkersUpgrade reverts pauseWorkersUpgrade when the last upgrade of the cluster was stopped by the health gates
+func (c *ClusterManager) resumeWorkersUpgrade(ctx context.Context, managementCluster, eksaCluster *types.Cluster, currentSpec *cluster.Spec) error {
+	if currentSpec.Status.FailureMessage == nil {
+		return nil
+	}
+
+	logger.V(3).Info("Resuming worker nodes upgrade paused by a previous upgrade")
+	if err := c.setMachineDeploymentsPaused(ctx, managementCluster, currentSpec.Name, false); err != nil {
+		return err
+	}
+	return c.clusterClient.MergePatchResource(ctx, currentSpec.ResourceType(), currentSpec.Name, `{"status":{"failureMessage":null}}`, eksaCluster, currentSpec.Namespace)
+}
+
+func (c *ClusterManager) setMachineDeploymentsPaused(ctx context.Context, managementCluster *types.Cluster, clusterName string, paused bool) error {
+	mds, err := c.clusterClient.GetMachineDeployments(ctx, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return fmt.Errorf("error getting machine deployments: %v", err)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	for _, md := range mds {
+		if md.Labels[clusterv1.ClusterLabelName] != clusterName {
+			continue
+		}
+		if err = c.clusterClient.MergePatchResource(ctx, machineDeploymentResourceType, md.Name, patch, managementCluster, constants.EksaSystemNamespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitNamespacedName(name string) (namespace, objectName string) {
+	parts := strings.SplitN(name, "/", 2)
+	return parts[0], parts[1]
+}
+
+func remainingWaitStr(deadline time.Time) string {
+	remaining := time.Until(deadline).Round(time.Second)
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	return remaining.String()
+}
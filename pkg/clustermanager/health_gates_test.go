@@ -0,0 +1,147 @@
+package clustermanager_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/clustermanager"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+type healthGatesTest struct {
+	*specChangedTest
+	managementCluster *types.Cluster
+	workloadCluster   *types.Cluster
+	kubeconfigCluster *types.Cluster
+	t                 *testing.T
+}
+
+func newHealthGatesTest(t *testing.T) *healthGatesTest {
+	tt := &healthGatesTest{
+		specChangedTest:   newSpecChangedTest(t, clustermanager.WithUpgradeHealthGatesBackoff(time.Millisecond)),
+		managementCluster: &types.Cluster{Name: "cluster-name"},
+		workloadCluster:   &types.Cluster{Name: "cluster-name"},
+		kubeconfigCluster: &types.Cluster{Name: "cluster-name", KubeconfigFile: "cluster-name/cluster-name-eks-a-cluster.kubeconfig"},
+		t:                 t,
+	}
+	return tt
+}
+
+func (tt *healthGatesTest) expectControlPlaneUpgrade() {
+	tt.mocks.client.EXPECT().GetEksaCluster(tt.ctx, tt.workloadCluster, tt.clusterSpec.Name).Return(tt.oldClusterConfig, nil)
+	tt.mocks.client.EXPECT().GetBundles(tt.ctx, tt.workloadCluster.KubeconfigFile, tt.workloadCluster.Name, "").Return(test.Bundles(tt.t), nil)
+	tt.mocks.provider.EXPECT().GenerateCAPISpecForUpgrade(tt.ctx, tt.managementCluster, tt.workloadCluster, gomock.Any(), tt.clusterSpec)
+	tt.mocks.writer.EXPECT().Write("cluster-name-eks-a-cluster.yaml", gomock.Any(), gomock.Not(gomock.Nil()))
+	tt.mocks.client.EXPECT().ApplyKubeSpecFromBytesWithNamespace(tt.ctx, tt.managementCluster, test.OfType("[]uint8"), constants.EksaSystemNamespace)
+	tt.mocks.provider.EXPECT().RunPostControlPlaneUpgrade(tt.ctx, gomock.Any(), tt.clusterSpec, tt.workloadCluster, tt.managementCluster)
+	tt.mocks.client.EXPECT().WaitForControlPlaneReady(tt.ctx, tt.managementCluster, "60m", "cluster-name").Times(2)
+	tt.mocks.client.EXPECT().GetMachines(tt.ctx, tt.managementCluster, "cluster-name").Return([]types.Machine{}, nil)
+	tt.mocks.client.EXPECT().ValidateControlPlaneNodes(tt.ctx, tt.managementCluster, "cluster-name")
+}
+
+func (tt *healthGatesTest) expectWorkersUpgrade() {
+	tt.mocks.client.EXPECT().ApplyKubeSpecFromBytesWithNamespace(tt.ctx, tt.managementCluster, test.OfType("[]uint8"), constants.EksaSystemNamespace)
+	tt.mocks.client.EXPECT().GetMachines(tt.ctx, tt.managementCluster, "cluster-name").Return([]types.Machine{}, nil)
+	tt.mocks.client.EXPECT().WaitForDeployment(tt.ctx, tt.workloadCluster, "30m", "Available", gomock.Any(), gomock.Any()).AnyTimes()
+	tt.mocks.client.EXPECT().ValidateWorkerNodes(tt.ctx, tt.managementCluster, "cluster-name")
+	tt.mocks.provider.EXPECT().GetDeployments()
+}
+
+func (tt *healthGatesTest) expectWorkloadKubeconfig() {
+	kubeconfig := []byte("kubeconfig")
+	tt.mocks.client.EXPECT().GetWorkloadKubeconfig(tt.ctx, "cluster-name", tt.managementCluster).Return(kubeconfig, nil)
+	tt.mocks.provider.EXPECT().UpdateKubeConfig(&kubeconfig, "cluster-name")
+	tt.mocks.writer.EXPECT().Write("cluster-name-eks-a-cluster.kubeconfig", kubeconfig, gomock.Not(gomock.Nil())).Return(tt.kubeconfigCluster.KubeconfigFile, nil)
+}
+
+func (tt *healthGatesTest) expectMachineDeploymentsPatched(patch string) {
+	tt.mocks.client.EXPECT().GetMachineDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return([]clusterv1.MachineDeployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-name-md-0", Labels: map[string]string{clusterv1.ClusterLabelName: "cluster-name"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-cluster-md-0", Labels: map[string]string{clusterv1.ClusterLabelName: "other-cluster"}}},
+	}, nil)
+	tt.mocks.client.EXPECT().MergePatchResource(tt.ctx, "machinedeployments.cluster.x-k8s.io", "cluster-name-md-0", patch, tt.managementCluster, constants.EksaSystemNamespace)
+}
+
+func TestClusterManagerUpgradeClusterHealthGatesPass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tt := newHealthGatesTest(t)
+	tt.clusterSpec.Spec.UpgradeHealthGates = &v1alpha1.UpgradeHealthGates{
+		Deployments: []string{"default/app"},
+		DaemonSets:  []string{"monitoring/node-exporter"},
+		HTTPProbes:  []v1alpha1.HTTPProbe{{URL: server.URL, ExpectedStatus: http.StatusNoContent}},
+		Timeout:     &metav1.Duration{Duration: 5 * time.Minute},
+	}
+	tt.expectControlPlaneUpgrade()
+	tt.expectWorkloadKubeconfig()
+	tt.mocks.client.EXPECT().WaitForDeployment(tt.ctx, tt.kubeconfigCluster, gomock.Any(), "Available", "app", "default")
+	tt.mocks.client.EXPECT().WaitForDaemonSetRollout(tt.ctx, tt.kubeconfigCluster, gomock.Any(), "node-exporter", "monitoring")
+	tt.expectWorkersUpgrade()
+
+	tt.Expect(tt.clusterManager.UpgradeCluster(tt.ctx, tt.managementCluster, tt.workloadCluster, tt.clusterSpec, tt.mocks.provider)).To(Succeed())
+}
+
+func TestClusterManagerUpgradeClusterHealthGatesFailPausesWorkers(t *testing.T) {
+	tt := newHealthGatesTest(t)
+	tt.clusterSpec.Spec.UpgradeHealthGates = &v1alpha1.UpgradeHealthGates{
+		Deployments: []string{"default/app"},
+	}
+	tt.expectControlPlaneUpgrade()
+	tt.expectWorkloadKubeconfig()
+	tt.mocks.client.EXPECT().WaitForDeployment(tt.ctx, tt.kubeconfigCluster, gomock.Any(), "Available", "app", "default").Return(errors.New("timed out"))
+	tt.expectMachineDeploymentsPatched(`{"spec":{"paused":true}}`)
+	tt.mocks.client.EXPECT().MergePatchResource(
+		tt.ctx, eksaClusterResourceType, "cluster-name",
+		`{"status":{"failureMessage":"upgrade health gates failed after upgrading the control plane: deployment default/app is not available: timed out"}}`,
+		tt.workloadCluster, "",
+	)
+
+	err := tt.clusterManager.UpgradeCluster(tt.ctx, tt.managementCluster, tt.workloadCluster, tt.clusterSpec, tt.mocks.provider)
+	tt.Expect(err).To(MatchError("upgrade health gates failed after upgrading the control plane: deployment default/app is not available: timed out"))
+}
+
+func TestClusterManagerUpgradeClusterHealthGatesHTTPProbeFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tt := newHealthGatesTest(t)
+	tt.clusterSpec.Spec.UpgradeHealthGates = &v1alpha1.UpgradeHealthGates{
+		HTTPProbes: []v1alpha1.HTTPProbe{{URL: server.URL}},
+		Timeout:    &metav1.Duration{Duration: 10 * time.Millisecond},
+	}
+	tt.expectControlPlaneUpgrade()
+	tt.expectWorkloadKubeconfig()
+	tt.expectMachineDeploymentsPatched(`{"spec":{"paused":true}}`)
+	tt.mocks.client.EXPECT().MergePatchResource(tt.ctx, eksaClusterResourceType, "cluster-name", gomock.Any(), tt.workloadCluster, "")
+
+	err := tt.clusterManager.UpgradeCluster(tt.ctx, tt.managementCluster, tt.workloadCluster, tt.clusterSpec, tt.mocks.provider)
+	tt.Expect(err).To(MatchError(ContainSubstring("got status 503, want 200")))
+}
+
+func TestClusterManagerUpgradeClusterResumesPausedWorkers(t *testing.T) {
+	tt := newHealthGatesTest(t)
+	failure := "upgrade health gates failed"
+	tt.oldClusterConfig.Status.FailureMessage = &failure
+	tt.expectControlPlaneUpgrade()
+	tt.expectMachineDeploymentsPatched(`{"spec":{"paused":false}}`)
+	tt.mocks.client.EXPECT().MergePatchResource(tt.ctx, eksaClusterResourceType, "cluster-name", `{"status":{"failureMessage":null}}`, tt.workloadCluster, "")
+	tt.expectWorkersUpgrade()
+
+	tt.Expect(tt.clusterManager.UpgradeCluster(tt.ctx, tt.managementCluster, tt.workloadCluster, tt.clusterSpec, tt.mocks.provider)).To(Succeed())
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/aws/eks-anywhere/pkg/clustermanager (interfaces: ClusterClient,Networking,AwsIamAuth)
+// Source: github.com/aws/eks-anywhere/pkg/clustermanager (interfaces: ClusterClient,Networking,LoadBalancer,AwsIamAuth)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -10,11 +10,15 @@ import (
 
 	v1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	cluster "github.com/aws/eks-anywhere/pkg/cluster"
+	executables "github.com/aws/eks-anywhere/pkg/executables"
 	filewriter "github.com/aws/eks-anywhere/pkg/filewriter"
 	providers "github.com/aws/eks-anywhere/pkg/providers"
 	types "github.com/aws/eks-anywhere/pkg/types"
 	v1alpha10 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
+	v1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
+	v1alpha30 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	v1alpha31 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
 )
 
 // MockClusterClient is a mock of ClusterClient interface.
@@ -124,6 +128,20 @@ func (mr *MockClusterClientMockRecorder) DeleteCluster(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockClusterClient)(nil).DeleteCluster), arg0, arg1, arg2)
 }
 
+// DeleteCronJob mocks base method.
+func (m *MockClusterClient) DeleteCronJob(arg0 context.Context, arg1 *types.Cluster, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCronJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCronJob indicates an expected call of DeleteCronJob.
+func (mr *MockClusterClientMockRecorder) DeleteCronJob(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCronJob", reflect.TypeOf((*MockClusterClient)(nil).DeleteCronJob), arg0, arg1, arg2, arg3)
+}
+
 // DeleteEKSACluster mocks base method.
 func (m *MockClusterClient) DeleteEKSACluster(arg0 context.Context, arg1 *types.Cluster, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
@@ -152,6 +170,20 @@ func (mr *MockClusterClientMockRecorder) DeleteGitOpsConfig(arg0, arg1, arg2, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGitOpsConfig", reflect.TypeOf((*MockClusterClient)(nil).DeleteGitOpsConfig), arg0, arg1, arg2, arg3)
 }
 
+// DeleteKubeSpecFromBytes mocks base method.
+func (m *MockClusterClient) DeleteKubeSpecFromBytes(arg0 context.Context, arg1 *types.Cluster, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKubeSpecFromBytes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKubeSpecFromBytes indicates an expected call of DeleteKubeSpecFromBytes.
+func (mr *MockClusterClientMockRecorder) DeleteKubeSpecFromBytes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKubeSpecFromBytes", reflect.TypeOf((*MockClusterClient)(nil).DeleteKubeSpecFromBytes), arg0, arg1, arg2)
+}
+
 // DeleteOIDCConfig mocks base method.
 func (m *MockClusterClient) DeleteOIDCConfig(arg0 context.Context, arg1 *types.Cluster, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
@@ -166,6 +198,20 @@ func (mr *MockClusterClientMockRecorder) DeleteOIDCConfig(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOIDCConfig", reflect.TypeOf((*MockClusterClient)(nil).DeleteOIDCConfig), arg0, arg1, arg2, arg3)
 }
 
+// DeleteSecret mocks base method.
+func (m *MockClusterClient) DeleteSecret(arg0 context.Context, arg1 *types.Cluster, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecret", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecret indicates an expected call of DeleteSecret.
+func (mr *MockClusterClientMockRecorder) DeleteSecret(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockClusterClient)(nil).DeleteSecret), arg0, arg1, arg2, arg3)
+}
+
 // GetApiServerUrl mocks base method.
 func (m *MockClusterClient) GetApiServerUrl(arg0 context.Context, arg1 *types.Cluster) (string, error) {
 	m.ctrl.T.Helper()
@@ -286,6 +332,66 @@ func (mr *MockClusterClientMockRecorder) GetEksaVSphereMachineConfig(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEksaVSphereMachineConfig", reflect.TypeOf((*MockClusterClient)(nil).GetEksaVSphereMachineConfig), arg0, arg1, arg2, arg3)
 }
 
+// GetEtcdadmCluster mocks base method.
+func (m *MockClusterClient) GetEtcdadmCluster(arg0 context.Context, arg1 *types.Cluster, arg2 string, arg3 ...executables.KubectlOpt) (*v1alpha3.EtcdadmCluster, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEtcdadmCluster", varargs...)
+	ret0, _ := ret[0].(*v1alpha3.EtcdadmCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEtcdadmCluster indicates an expected call of GetEtcdadmCluster.
+func (mr *MockClusterClientMockRecorder) GetEtcdadmCluster(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEtcdadmCluster", reflect.TypeOf((*MockClusterClient)(nil).GetEtcdadmCluster), varargs...)
+}
+
+// GetKubeadmControlPlane mocks base method.
+func (m *MockClusterClient) GetKubeadmControlPlane(arg0 context.Context, arg1 *types.Cluster, arg2 string, arg3 ...executables.KubectlOpt) (*v1alpha31.KubeadmControlPlane, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetKubeadmControlPlane", varargs...)
+	ret0, _ := ret[0].(*v1alpha31.KubeadmControlPlane)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKubeadmControlPlane indicates an expected call of GetKubeadmControlPlane.
+func (mr *MockClusterClientMockRecorder) GetKubeadmControlPlane(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeadmControlPlane", reflect.TypeOf((*MockClusterClient)(nil).GetKubeadmControlPlane), varargs...)
+}
+
+// GetMachineDeployments mocks base method.
+func (m *MockClusterClient) GetMachineDeployments(arg0 context.Context, arg1 ...executables.KubectlOpt) ([]v1alpha30.MachineDeployment, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetMachineDeployments", varargs...)
+	ret0, _ := ret[0].([]v1alpha30.MachineDeployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachineDeployments indicates an expected call of GetMachineDeployments.
+func (mr *MockClusterClientMockRecorder) GetMachineDeployments(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachineDeployments", reflect.TypeOf((*MockClusterClient)(nil).GetMachineDeployments), varargs...)
+}
+
 // GetMachines mocks base method.
 func (m *MockClusterClient) GetMachines(arg0 context.Context, arg1 *types.Cluster, arg2 string) ([]types.Machine, error) {
 	m.ctrl.T.Helper()
@@ -359,6 +465,20 @@ func (mr *MockClusterClientMockRecorder) KubeconfigSecretAvailable(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KubeconfigSecretAvailable", reflect.TypeOf((*MockClusterClient)(nil).KubeconfigSecretAvailable), arg0, arg1, arg2, arg3)
 }
 
+// MergePatchResource mocks base method.
+func (m *MockClusterClient) MergePatchResource(arg0 context.Context, arg1, arg2, arg3 string, arg4 *types.Cluster, arg5 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePatchResource", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergePatchResource indicates an expected call of MergePatchResource.
+func (mr *MockClusterClientMockRecorder) MergePatchResource(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePatchResource", reflect.TypeOf((*MockClusterClient)(nil).MergePatchResource), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // MoveManagement mocks base method.
 func (m *MockClusterClient) MoveManagement(arg0 context.Context, arg1, arg2 *types.Cluster) error {
 	m.ctrl.T.Helper()
@@ -471,6 +591,20 @@ func (mr *MockClusterClientMockRecorder) WaitForControlPlaneReady(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForControlPlaneReady", reflect.TypeOf((*MockClusterClient)(nil).WaitForControlPlaneReady), arg0, arg1, arg2, arg3)
 }
 
+// WaitForDaemonSetRollout mocks base method.
+func (m *MockClusterClient) WaitForDaemonSetRollout(arg0 context.Context, arg1 *types.Cluster, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForDaemonSetRollout", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForDaemonSetRollout indicates an expected call of WaitForDaemonSetRollout.
+func (mr *MockClusterClientMockRecorder) WaitForDaemonSetRollout(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForDaemonSetRollout", reflect.TypeOf((*MockClusterClient)(nil).WaitForDaemonSetRollout), arg0, arg1, arg2, arg3, arg4)
+}
+
 // WaitForDeployment mocks base method.
 func (m *MockClusterClient) WaitForDeployment(arg0 context.Context, arg1 *types.Cluster, arg2, arg3, arg4, arg5 string) error {
 	m.ctrl.T.Helper()
@@ -537,6 +671,59 @@ func (mr *MockNetworkingMockRecorder) GenerateManifest(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateManifest", reflect.TypeOf((*MockNetworking)(nil).GenerateManifest), arg0)
 }
 
+// GenerateRemovedManifest mocks base method.
+func (m *MockNetworking) GenerateRemovedManifest(arg0, arg1 *cluster.Spec) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRemovedManifest", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRemovedManifest indicates an expected call of GenerateRemovedManifest.
+func (mr *MockNetworkingMockRecorder) GenerateRemovedManifest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRemovedManifest", reflect.TypeOf((*MockNetworking)(nil).GenerateRemovedManifest), arg0, arg1)
+}
+
+// MockLoadBalancer is a mock of LoadBalancer interface.
+type MockLoadBalancer struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoadBalancerMockRecorder
+}
+
+// MockLoadBalancerMockRecorder is the mock recorder for MockLoadBalancer.
+type MockLoadBalancerMockRecorder struct {
+	mock *MockLoadBalancer
+}
+
+// NewMockLoadBalancer creates a new mock instance.
+func NewMockLoadBalancer(ctrl *gomock.Controller) *MockLoadBalancer {
+	mock := &MockLoadBalancer{ctrl: ctrl}
+	mock.recorder = &MockLoadBalancerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoadBalancer) EXPECT() *MockLoadBalancerMockRecorder {
+	return m.recorder
+}
+
+// GenerateManifest mocks base method.
+func (m *MockLoadBalancer) GenerateManifest(arg0 *cluster.Spec) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateManifest", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateManifest indicates an expected call of GenerateManifest.
+func (mr *MockLoadBalancerMockRecorder) GenerateManifest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateManifest", reflect.TypeOf((*MockLoadBalancer)(nil).GenerateManifest), arg0)
+}
+
 // MockAwsIamAuth is a mock of AwsIamAuth interface.
 type MockAwsIamAuth struct {
 	ctrl     *gomock.Controller
@@ -3,10 +3,10 @@ package clustermanager
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/clustermanager/internal"
+	"github.com/aws/eks-anywhere/pkg/proxy"
 	"github.com/aws/eks-anywhere/pkg/retrier"
 	"github.com/aws/eks-anywhere/pkg/types"
 )
@@ -39,14 +39,8 @@ func (c *retrierClient) installCustomComponents(ctx context.Context, clusterSpec
 	}
 
 	// inject proxy env vars the eksa-controller-manager deployment if proxy is configured
-	if clusterSpec.Spec.ProxyConfiguration != nil {
-		noProxyList := append(clusterSpec.Spec.ProxyConfiguration.NoProxy, clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks...)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks...)
-		envMap := map[string]string{
-			"HTTP_PROXY":  clusterSpec.Spec.ProxyConfiguration.HttpProxy,
-			"HTTPS_PROXY": clusterSpec.Spec.ProxyConfiguration.HttpsProxy,
-			"NO_PROXY":    strings.Join(noProxyList[:], ","),
-		}
+	if proxyConfig := proxy.New(clusterSpec.Cluster); proxyConfig != nil {
+		envMap := proxyConfig.EnvMap()
 		err = c.Retrier.Retry(
 			func() error {
 				return c.UpdateEnvironmentVariablesInNamespace(ctx, "deployment", "eksa-controller-manager", envMap, cluster, "eksa-system")
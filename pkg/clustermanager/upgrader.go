@@ -10,12 +10,14 @@ import (
 )
 
 type Upgrader struct {
-	retrier *retrierClient
+	retrier    *retrierClient
+	networking Networking
 }
 
-func NewUpgrader(retrier *retrierClient) *Upgrader {
+func NewUpgrader(retrier *retrierClient, networking Networking) *Upgrader {
 	return &Upgrader{
-		retrier: retrier,
+		retrier:    retrier,
+		networking: networking,
 	}
 }
 
@@ -25,19 +27,73 @@ func (u *Upgrader) Upgrade(ctx context.Context, cluster *types.Cluster, currentS
 		logger.V(1).Info("Skipping EKS-A components upgrade, not a self-managed cluster")
 		return nil, nil
 	}
-	changeDiff := eksaChangeDiff(currentSpec, newSpec)
-	if changeDiff == nil {
+	changeDiff := types.NewChangeDiff()
+
+	if eksaDiff := eksaChangeDiff(currentSpec, newSpec); eksaDiff != nil {
+		logger.V(1).Info("Starting EKS-A components upgrade")
+		oldVersion := currentSpec.VersionsBundle.Eksa.Version
+		newVersion := newSpec.VersionsBundle.Eksa.Version
+		if err := u.retrier.installCustomComponents(ctx, newSpec, cluster); err != nil {
+			return nil, fmt.Errorf("failed upgrading EKS-A components from version %v to version %v: %v", oldVersion, newVersion, err)
+		}
+		changeDiff.Append(types.NewChangeDiff(eksaDiff))
+	} else {
 		logger.V(1).Info("Nothing to upgrade for controller and CRDs")
+	}
+
+	if ciliumDiff := ciliumChangeDiff(currentSpec, newSpec); ciliumDiff != nil {
+		logger.V(1).Info("Starting Cilium upgrade")
+		if err := u.upgradeNetworking(ctx, cluster, currentSpec, newSpec); err != nil {
+			return nil, fmt.Errorf("failed upgrading Cilium from version %v to version %v: %v", ciliumDiff.OldVersion, ciliumDiff.NewVersion, err)
+		}
+		changeDiff.Append(types.NewChangeDiff(ciliumDiff))
+	} else {
+		logger.V(1).Info("Nothing to upgrade for Cilium")
+	}
+
+	if !changeDiff.Changed() {
 		return nil, nil
 	}
-	logger.V(1).Info("Starting EKS-A components upgrade")
-	oldVersion := currentSpec.VersionsBundle.Eksa.Version
-	newVersion := newSpec.VersionsBundle.Eksa.Version
-	if err := u.retrier.installCustomComponents(ctx, newSpec, cluster); err != nil {
-		return nil, fmt.Errorf("failed upgrading EKS-A components from version %v to version %v: %v", oldVersion, newVersion, err)
+
+	return changeDiff, nil
+}
+
+// upgradeNetworking applies the networking manifest for newSpec and deletes the objects deployed for
+// currentSpec that aren't part of it anymore, like the Hubble components once they are disabled.
+func (u *Upgrader) upgradeNetworking(ctx context.Context, cluster *types.Cluster, currentSpec, newSpec *cluster.Spec) error {
+	networkingManifestContent, err := u.networking.GenerateManifest(newSpec)
+	if err != nil {
+		return fmt.Errorf("error generating networking manifest: %v", err)
+	}
+
+	removedManifestContent, err := u.networking.GenerateRemovedManifest(currentSpec, newSpec)
+	if err != nil {
+		return fmt.Errorf("error generating removed networking manifest: %v", err)
 	}
 
-	return types.NewChangeDiff(changeDiff), nil
+	err = u.retrier.Retry(
+		func() error {
+			return u.retrier.ApplyKubeSpecFromBytes(ctx, cluster, networkingManifestContent)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error applying networking manifest spec: %v", err)
+	}
+
+	if len(removedManifestContent) == 0 {
+		return nil
+	}
+
+	err = u.retrier.Retry(
+		func() error {
+			return u.retrier.DeleteKubeSpecFromBytes(ctx, cluster, removedManifestContent)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting removed networking objects: %v", err)
+	}
+
+	return nil
 }
 
 func eksaChangeDiff(currentSpec, newSpec *cluster.Spec) *types.ComponentChangeDiff {
@@ -51,3 +107,18 @@ func eksaChangeDiff(currentSpec, newSpec *cluster.Spec) *types.ComponentChangeDi
 		return nil
 	}
 }
+
+// ciliumChangeDiff reports a change when either the Cilium version or its configuration in
+// the cluster spec changes. For configuration only changes both versions are the same.
+func ciliumChangeDiff(currentSpec, newSpec *cluster.Spec) *types.ComponentChangeDiff {
+	if currentSpec.VersionsBundle.Cilium.Version == newSpec.VersionsBundle.Cilium.Version &&
+		currentSpec.Spec.ClusterNetwork.Cilium.Equal(newSpec.Spec.ClusterNetwork.Cilium) {
+		return nil
+	}
+
+	return &types.ComponentChangeDiff{
+		ComponentName: "Cilium",
+		NewVersion:    newSpec.VersionsBundle.Cilium.Version,
+		OldVersion:    currentSpec.VersionsBundle.Cilium.Version,
+	}
+}
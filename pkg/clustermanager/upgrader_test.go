@@ -2,12 +2,14 @@ package clustermanager_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 
 	"github.com/aws/eks-anywhere/internal/test"
+	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/clustermanager"
 	"github.com/aws/eks-anywhere/pkg/clustermanager/mocks"
@@ -20,6 +22,7 @@ type upgraderTest struct {
 	*WithT
 	ctx         context.Context
 	client      *mocks.MockClusterClient
+	networking  *mocks.MockNetworking
 	currentSpec *cluster.Spec
 	newSpec     *cluster.Spec
 	upgrader    *clustermanager.Upgrader
@@ -29,16 +32,19 @@ type upgraderTest struct {
 func newUpgraderTest(t *testing.T) *upgraderTest {
 	ctrl := gomock.NewController(t)
 	client := mocks.NewMockClusterClient(ctrl)
+	networking := mocks.NewMockNetworking(ctrl)
 	currentSpec := test.NewClusterSpec(func(s *cluster.Spec) {
 		s.VersionsBundle.Eksa.Version = "v0.1.0"
+		s.VersionsBundle.Cilium.Version = "v1.9.10-eksa.1"
 	})
 
 	return &upgraderTest{
-		WithT:  NewWithT(t),
-		ctx:    context.Background(),
-		client: client,
+		WithT:      NewWithT(t),
+		ctx:        context.Background(),
+		client:     client,
+		networking: networking,
 		upgrader: clustermanager.NewUpgrader(clustermanager.NewRetrierClient(clustermanager.NewClient(client),
-			retrier.NewWithMaxRetries(1, 0))),
+			retrier.NewWithMaxRetries(1, 0)), networking),
 		currentSpec: currentSpec,
 		newSpec:     currentSpec.DeepCopy(),
 		cluster: &types.Cluster{
@@ -93,3 +99,98 @@ func TestUpgraderUpgradeInstallError(t *testing.T) {
 	_, err := tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)
 	tt.Expect(err).NotTo(BeNil())
 }
+
+func TestUpgraderUpgradeCiliumVersionSuccess(t *testing.T) {
+	tt := newUpgraderTest(t)
+
+	tt.newSpec.VersionsBundle.Cilium.Version = "v1.10.1-eksa.1"
+
+	wantDiff := &types.ChangeDiff{
+		ComponentReports: []types.ComponentChangeDiff{
+			{
+				ComponentName: "Cilium",
+				NewVersion:    "v1.10.1-eksa.1",
+				OldVersion:    "v1.9.10-eksa.1",
+			},
+		},
+	}
+
+	tt.networking.EXPECT().GenerateManifest(tt.newSpec).Return([]byte("cilium manifest"), nil)
+	tt.networking.EXPECT().GenerateRemovedManifest(tt.currentSpec, tt.newSpec).Return(nil, nil)
+	tt.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("cilium manifest")).Return(nil)
+	tt.Expect(tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)).To(Equal(wantDiff))
+}
+
+func TestUpgraderUpgradeCiliumConfigSuccess(t *testing.T) {
+	tt := newUpgraderTest(t)
+
+	tt.newSpec.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+		PolicyEnforcementMode: eksav1alpha1.CiliumPolicyModeAlways,
+	}
+
+	wantDiff := &types.ChangeDiff{
+		ComponentReports: []types.ComponentChangeDiff{
+			{
+				ComponentName: "Cilium",
+				NewVersion:    "v1.9.10-eksa.1",
+				OldVersion:    "v1.9.10-eksa.1",
+			},
+		},
+	}
+
+	tt.networking.EXPECT().GenerateManifest(tt.newSpec).Return([]byte("cilium manifest"), nil)
+	tt.networking.EXPECT().GenerateRemovedManifest(tt.currentSpec, tt.newSpec).Return(nil, nil)
+	tt.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("cilium manifest")).Return(nil)
+	tt.Expect(tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)).To(Equal(wantDiff))
+}
+
+func TestUpgraderUpgradeCiliumGenerateManifestError(t *testing.T) {
+	tt := newUpgraderTest(t)
+
+	tt.newSpec.VersionsBundle.Cilium.Version = "v1.10.1-eksa.1"
+
+	tt.networking.EXPECT().GenerateManifest(tt.newSpec).Return(nil, errors.New("error generating manifest"))
+	_, err := tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)
+	tt.Expect(err).NotTo(BeNil())
+}
+
+func TestUpgraderUpgradeCiliumDisableHubbleSuccess(t *testing.T) {
+	tt := newUpgraderTest(t)
+
+	tt.currentSpec.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+		Hubble: &eksav1alpha1.HubbleConfig{Relay: true, UI: true},
+	}
+
+	wantDiff := &types.ChangeDiff{
+		ComponentReports: []types.ComponentChangeDiff{
+			{
+				ComponentName: "Cilium",
+				NewVersion:    "v1.9.10-eksa.1",
+				OldVersion:    "v1.9.10-eksa.1",
+			},
+		},
+	}
+
+	tt.networking.EXPECT().GenerateManifest(tt.newSpec).Return([]byte("cilium manifest"), nil)
+	tt.networking.EXPECT().GenerateRemovedManifest(tt.currentSpec, tt.newSpec).Return([]byte("hubble manifest"), nil)
+	gomock.InOrder(
+		tt.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("cilium manifest")).Return(nil),
+		tt.client.EXPECT().DeleteKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("hubble manifest")).Return(nil),
+	)
+	tt.Expect(tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)).To(Equal(wantDiff))
+}
+
+func TestUpgraderUpgradeCiliumDeleteRemovedError(t *testing.T) {
+	tt := newUpgraderTest(t)
+
+	tt.currentSpec.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+		Hubble: &eksav1alpha1.HubbleConfig{Relay: true},
+	}
+
+	tt.networking.EXPECT().GenerateManifest(tt.newSpec).Return([]byte("cilium manifest"), nil)
+	tt.networking.EXPECT().GenerateRemovedManifest(tt.currentSpec, tt.newSpec).Return([]byte("hubble manifest"), nil)
+	tt.client.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("cilium manifest")).Return(nil)
+	tt.client.EXPECT().DeleteKubeSpecFromBytes(tt.ctx, tt.cluster, []byte("hubble manifest")).Return(errors.New("error deleting"))
+	_, err := tt.upgrader.Upgrade(tt.ctx, tt.cluster, tt.currentSpec, tt.newSpec)
+	tt.Expect(err).NotTo(BeNil())
+}
@@ -0,0 +1,85 @@
+package configgenerator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+// VSphereInventory lists the objects in vCenter that can be used by a cluster
+type VSphereInventory interface {
+	ListDatacenters(ctx context.Context) ([]string, error)
+	ListNetworks(ctx context.Context, datacenter string) ([]string, error)
+	ListDatastores(ctx context.Context, datacenter string) ([]string, error)
+	ListResourcePools(ctx context.Context, datacenter string) ([]string, error)
+	ListFolders(ctx context.Context, datacenter string) ([]string, error)
+	ListTemplates(ctx context.Context, datacenter string) ([]string, error)
+}
+
+// Chooser returns the option picked for field when there is more than one to choose from
+type Chooser func(field string, options []string) (string, error)
+
+// AutofillVSphere fills the empty vSphere fields in opts with the objects found in vCenter.
+// A field is filled when vCenter only has one option for it or, with more than one, with the option picked by choose.
+// Without a chooser, fields with more than one option are left empty and the options are logged
+func AutofillVSphere(ctx context.Context, inventory VSphereInventory, opts *VSphereOptions, choose Chooser) error {
+	if err := autofillField(ctx, "datacenter", &opts.Datacenter, inventory.ListDatacenters, choose); err != nil {
+		return err
+	}
+	if opts.Datacenter == "" {
+		logger.Info("Warning: no vSphere datacenter selected, skipping the rest of the vSphere fields")
+		return nil
+	}
+
+	fields := []struct {
+		name  string
+		field *string
+		list  func(ctx context.Context, datacenter string) ([]string, error)
+	}{
+		{name: "network", field: &opts.Network, list: inventory.ListNetworks},
+		{name: "datastore", field: &opts.Datastore, list: inventory.ListDatastores},
+		{name: "resource pool", field: &opts.ResourcePool, list: inventory.ListResourcePools},
+		{name: "folder", field: &opts.Folder, list: inventory.ListFolders},
+		{name: "template", field: &opts.Template, list: inventory.ListTemplates},
+	}
+	for _, f := range fields {
+		list := f.list
+		listInDatacenter := func(ctx context.Context) ([]string, error) {
+			return list(ctx, opts.Datacenter)
+		}
+		if err := autofillField(ctx, f.name, f.field, listInDatacenter, choose); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func autofillField(ctx context.Context, name string, field *string, list func(ctx context.Context) ([]string, error), choose Chooser) error {
+	if *field != "" {
+		return nil
+	}
+
+	options, err := list(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing vSphere %ss: %v", name, err)
+	}
+
+	switch {
+	case len(options) == 0:
+		logger.Info(fmt.Sprintf("Warning: no vSphere %s found", name))
+	case len(options) == 1:
+		*field = options[0]
+		logger.V(2).Info(fmt.Sprintf("Using vSphere %s %s", name, options[0]))
+	case choose != nil:
+		choice, err := choose(name, options)
+		if err != nil {
+			return fmt.Errorf("failed choosing vSphere %s: %v", name, err)
+		}
+		*field = choice
+	default:
+		logger.Info(fmt.Sprintf("Warning: found more than one vSphere %s, set one in the generated config: %s", name, strings.Join(options, ", ")))
+	}
+	return nil
+}
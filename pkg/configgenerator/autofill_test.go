@@ -0,0 +1,96 @@
+package configgenerator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/configgenerator"
+	"github.com/aws/eks-anywhere/pkg/configgenerator/mocks"
+)
+
+const datacenter = "SDDC-Datacenter"
+
+func TestAutofillVSphereSingleOptions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	inventory := mocks.NewMockVSphereInventory(gomock.NewController(t))
+	inventory.EXPECT().ListDatacenters(ctx).Return([]string{datacenter}, nil)
+	inventory.EXPECT().ListNetworks(ctx, datacenter).Return([]string{"/SDDC-Datacenter/network/sddc-cgw-network-1"}, nil)
+	inventory.EXPECT().ListDatastores(ctx, datacenter).Return([]string{"/SDDC-Datacenter/datastore/WorkloadDatastore"}, nil)
+	inventory.EXPECT().ListResourcePools(ctx, datacenter).Return([]string{"/SDDC-Datacenter/host/Cluster-1/Resources"}, nil)
+	inventory.EXPECT().ListFolders(ctx, datacenter).Return(nil, nil)
+	inventory.EXPECT().ListTemplates(ctx, datacenter).Return([]string{"/SDDC-Datacenter/vm/bottlerocket-1-21"}, nil)
+
+	opts := &configgenerator.VSphereOptions{}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, nil)).To(Succeed())
+	g.Expect(opts).To(Equal(&configgenerator.VSphereOptions{
+		Datacenter:   datacenter,
+		Network:      "/SDDC-Datacenter/network/sddc-cgw-network-1",
+		Datastore:    "/SDDC-Datacenter/datastore/WorkloadDatastore",
+		ResourcePool: "/SDDC-Datacenter/host/Cluster-1/Resources",
+		Template:     "/SDDC-Datacenter/vm/bottlerocket-1-21",
+	}))
+}
+
+func TestAutofillVSphereKeepsSetFields(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	inventory := mocks.NewMockVSphereInventory(gomock.NewController(t))
+	inventory.EXPECT().ListDatastores(ctx, datacenter).Return([]string{"/SDDC-Datacenter/datastore/WorkloadDatastore"}, nil)
+
+	opts := &configgenerator.VSphereOptions{
+		Datacenter:   datacenter,
+		Network:      "network",
+		ResourcePool: "*/Resources",
+		Folder:       "folder",
+		Template:     "template",
+	}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, nil)).To(Succeed())
+	g.Expect(opts.Network).To(Equal("network"))
+	g.Expect(opts.Datastore).To(Equal("/SDDC-Datacenter/datastore/WorkloadDatastore"))
+}
+
+func TestAutofillVSphereMultipleOptions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	inventory := mocks.NewMockVSphereInventory(gomock.NewController(t))
+	networks := []string{"/SDDC-Datacenter/network/a", "/SDDC-Datacenter/network/b"}
+	inventory.EXPECT().ListNetworks(ctx, datacenter).Return(networks, nil).Times(2)
+
+	opts := &configgenerator.VSphereOptions{Datacenter: datacenter, Datastore: "ds", ResourcePool: "rp", Folder: "f", Template: "t"}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, nil)).To(Succeed())
+	g.Expect(opts.Network).To(BeEmpty())
+
+	choose := func(field string, options []string) (string, error) {
+		g.Expect(field).To(Equal("network"))
+		g.Expect(options).To(Equal(networks))
+		return options[1], nil
+	}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, choose)).To(Succeed())
+	g.Expect(opts.Network).To(Equal("/SDDC-Datacenter/network/b"))
+}
+
+func TestAutofillVSphereNoDatacenter(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	inventory := mocks.NewMockVSphereInventory(gomock.NewController(t))
+	inventory.EXPECT().ListDatacenters(ctx).Return([]string{"dc1", "dc2"}, nil)
+
+	opts := &configgenerator.VSphereOptions{}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, nil)).To(Succeed())
+	g.Expect(opts).To(Equal(&configgenerator.VSphereOptions{}))
+}
+
+func TestAutofillVSphereListError(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	inventory := mocks.NewMockVSphereInventory(gomock.NewController(t))
+	inventory.EXPECT().ListDatacenters(ctx).Return(nil, errors.New("error from govc"))
+
+	opts := &configgenerator.VSphereOptions{}
+	g.Expect(configgenerator.AutofillVSphere(ctx, inventory, opts, nil)).To(MatchError(ContainSubstring("failed listing vSphere datacenters: error from govc")))
+}
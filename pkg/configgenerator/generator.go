@@ -0,0 +1,266 @@
+package configgenerator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/configschema"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/templater"
+)
+
+const defaultSSHAuthorizedKey = "ssh-rsa AAAA..."
+
+// MachineSize is the sizing of the machines of a node group
+type MachineSize struct {
+	NumCPUs   int
+	MemoryMiB int
+	DiskGiB   int
+}
+
+// VSphereOptions are the values of the vSphere datacenter and machine configs.
+// Empty values are left for the user to fill in the generated file
+type VSphereOptions struct {
+	Server           string
+	Thumbprint       string
+	Insecure         bool
+	Datacenter       string
+	Network          string
+	Datastore        string
+	ResourcePool     string
+	Folder           string
+	Template         string
+	OSFamily         v1alpha1.OSFamily
+	SSHAuthorizedKey string
+}
+
+// Options define the cluster config to generate
+type Options struct {
+	ClusterName          string
+	Provider             string
+	KubernetesVersion    v1alpha1.KubernetesVersion
+	ControlPlaneCount    int
+	WorkerCount          int
+	ExternalEtcd         bool
+	EtcdCount            int
+	ControlPlaneEndpoint string
+	ControlPlaneMachine  MachineSize
+	WorkerMachine        MachineSize
+	EtcdMachine          MachineSize
+	VSphere              VSphereOptions
+	// OIDC, AWSIam and GitOps add the config of the kind and a reference to it in the cluster when set
+	OIDC           *v1alpha1.OIDCConfigSpec
+	AWSIam         *v1alpha1.AWSIamConfigSpec
+	GitOps         *v1alpha1.GitOpsConfigSpec
+	Proxy          *v1alpha1.ProxyConfiguration
+	RegistryMirror *v1alpha1.RegistryMirrorConfiguration
+}
+
+// NewOptions returns the options to generate the default cluster config for provider
+func NewOptions(clusterName, provider string) (*Options, error) {
+	defaultMachine := v1alpha1.NewVSphereMachineConfigGenerate(clusterName).Spec
+	machineSize := MachineSize{
+		NumCPUs:   defaultMachine.NumCPUs,
+		MemoryMiB: defaultMachine.MemoryMiB,
+		DiskGiB:   defaultMachine.DiskGiB,
+	}
+	opts := &Options{
+		ClusterName:         clusterName,
+		Provider:            strings.ToLower(provider),
+		KubernetesVersion:   v1alpha1.Kube121,
+		ExternalEtcd:        true,
+		ControlPlaneMachine: machineSize,
+		WorkerMachine:       machineSize,
+		EtcdMachine:         machineSize,
+		VSphere: VSphereOptions{
+			OSFamily:         defaultMachine.OSFamily,
+			SSHAuthorizedKey: defaultSSHAuthorizedKey,
+		},
+	}
+
+	switch opts.Provider {
+	case constants.DockerProviderName:
+		opts.ControlPlaneCount = 1
+		opts.EtcdCount = 1
+		opts.WorkerCount = 1
+	case constants.VSphereProviderName:
+		opts.ControlPlaneCount = 2
+		opts.EtcdCount = 3
+		opts.WorkerCount = 2
+	default:
+		return nil, fmt.Errorf("not a valid provider: %s", provider)
+	}
+	return opts, nil
+}
+
+// Generate returns the multi-document cluster config for opts, after validating it
+// against the config schemas and the cluster config validations
+func Generate(opts *Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	clusterOpts := []v1alpha1.ClusterGenerateOpt{
+		v1alpha1.WithKubernetesVersion(opts.KubernetesVersion),
+		v1alpha1.ControlPlaneConfigCount(opts.ControlPlaneCount),
+		v1alpha1.WorkerNodeConfigCount(opts.WorkerCount),
+		v1alpha1.WithProxyConfiguration(opts.Proxy),
+		v1alpha1.WithRegistryMirrorConfiguration(opts.RegistryMirror),
+	}
+	if opts.ExternalEtcd {
+		clusterOpts = append(clusterOpts, v1alpha1.ExternalETCDConfigCount(opts.EtcdCount))
+	}
+
+	var configs []interface{}
+	switch opts.Provider {
+	case constants.DockerProviderName:
+		datacenterConfig := v1alpha1.NewDockerDatacenterConfigGenerate(opts.ClusterName)
+		clusterOpts = append(clusterOpts, v1alpha1.WithDatacenterRef(datacenterConfig))
+		configs = append(configs, datacenterConfig)
+	case constants.VSphereProviderName:
+		providerConfigs, providerOpts := vSphereConfigs(opts)
+		clusterOpts = append(clusterOpts, providerOpts...)
+		configs = append(configs, providerConfigs...)
+	}
+
+	if opts.OIDC != nil {
+		oidcConfig := v1alpha1.NewOIDCConfigGenerate(opts.ClusterName)
+		oidcConfig.Spec = *opts.OIDC
+		clusterOpts = append(clusterOpts, v1alpha1.WithIdentityProviderRef(oidcConfig))
+		configs = append(configs, oidcConfig)
+	}
+	if opts.AWSIam != nil {
+		awsIamConfig := v1alpha1.NewAWSIamConfigGenerate(opts.ClusterName)
+		awsIamConfig.Spec = *opts.AWSIam
+		clusterOpts = append(clusterOpts, v1alpha1.WithIdentityProviderRef(awsIamConfig))
+		configs = append(configs, awsIamConfig)
+	}
+	if opts.GitOps != nil {
+		gitOpsConfig := v1alpha1.NewGitOpsConfigGenerate(opts.ClusterName)
+		gitOpsConfig.Spec = *opts.GitOps
+		clusterOpts = append(clusterOpts, v1alpha1.WithGitOpsRef(gitOpsConfig))
+		configs = append(configs, gitOpsConfig)
+	}
+
+	clusterConfig := v1alpha1.NewClusterGenerate(opts.ClusterName, clusterOpts...)
+	clusterYaml, err := yaml.Marshal(clusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error outputting yaml: %v", err)
+	}
+	resources := [][]byte{clusterYaml}
+	for _, c := range configs {
+		configYaml, err := yaml.Marshal(c)
+		if err != nil {
+			return nil, fmt.Errorf("error outputting yaml: %v", err)
+		}
+		resources = append(resources, configYaml)
+	}
+	content := templater.AppendYamlResources(resources...)
+
+	if err = validate(content, clusterYaml); err != nil {
+		return nil, fmt.Errorf("generated cluster config is invalid: %v", err)
+	}
+	return content, nil
+}
+
+func vSphereConfigs(opts *Options) ([]interface{}, []v1alpha1.ClusterGenerateOpt) {
+	datacenterConfig := v1alpha1.NewVSphereDatacenterConfigGenerate(opts.ClusterName)
+	datacenterConfig.Spec.Server = opts.VSphere.Server
+	datacenterConfig.Spec.Thumbprint = opts.VSphere.Thumbprint
+	datacenterConfig.Spec.Insecure = opts.VSphere.Insecure
+	datacenterConfig.Spec.Datacenter = opts.VSphere.Datacenter
+	datacenterConfig.Spec.Network = opts.VSphere.Network
+
+	// need to default control plane config name to something different from the cluster name based on assumption
+	// in controller code
+	cpMachineConfig := newVSphereMachineConfig(opts.ClusterName+"-cp", opts.VSphere, opts.ControlPlaneMachine)
+	workerMachineConfig := newVSphereMachineConfig(opts.ClusterName, opts.VSphere, opts.WorkerMachine)
+	configs := []interface{}{datacenterConfig, cpMachineConfig, workerMachineConfig}
+	clusterOpts := []v1alpha1.ClusterGenerateOpt{
+		withControlPlaneEndpoint(opts.ControlPlaneEndpoint),
+		v1alpha1.WithDatacenterRef(datacenterConfig),
+		v1alpha1.WithCPMachineGroupRef(cpMachineConfig),
+		v1alpha1.WithWorkerMachineGroupRef(workerMachineConfig),
+	}
+
+	if opts.ExternalEtcd {
+		etcdMachineConfig := newVSphereMachineConfig(opts.ClusterName+"-etcd", opts.VSphere, opts.EtcdMachine)
+		configs = append(configs, etcdMachineConfig)
+		clusterOpts = append(clusterOpts, v1alpha1.WithEtcdMachineGroupRef(etcdMachineConfig))
+	}
+	return configs, clusterOpts
+}
+
+func newVSphereMachineConfig(name string, vsphere VSphereOptions, size MachineSize) *v1alpha1.VSphereMachineConfigGenerate {
+	machineConfig := v1alpha1.NewVSphereMachineConfigGenerate(name)
+	machineConfig.Spec.NumCPUs = size.NumCPUs
+	machineConfig.Spec.MemoryMiB = size.MemoryMiB
+	machineConfig.Spec.DiskGiB = size.DiskGiB
+	machineConfig.Spec.Datastore = vsphere.Datastore
+	machineConfig.Spec.ResourcePool = vsphere.ResourcePool
+	machineConfig.Spec.Folder = vsphere.Folder
+	machineConfig.Spec.Template = vsphere.Template
+	machineConfig.Spec.OSFamily = vsphere.OSFamily
+	machineConfig.Spec.Users[0].SshAuthorizedKeys = []string{vsphere.SSHAuthorizedKey}
+	return machineConfig
+}
+
+func withControlPlaneEndpoint(host string) v1alpha1.ClusterGenerateOpt {
+	return func(c *v1alpha1.ClusterGenerate) {
+		c.Spec.ControlPlaneConfiguration.Endpoint = &v1alpha1.Endpoint{Host: host}
+	}
+}
+
+func (o *Options) validate() error {
+	if o.ControlPlaneCount <= 0 {
+		return errors.New("control plane node count must be positive")
+	}
+	if o.WorkerCount <= 0 {
+		return errors.New("worker node count must be positive")
+	}
+	if o.ExternalEtcd && o.EtcdCount <= 0 {
+		return errors.New("etcd node count must be positive")
+	}
+	for _, size := range []MachineSize{o.ControlPlaneMachine, o.WorkerMachine, o.EtcdMachine} {
+		if size.NumCPUs <= 0 || size.MemoryMiB <= 0 || size.DiskGiB <= 0 {
+			return errors.New("machine cpus, memory and disk size must be positive")
+		}
+	}
+	if o.Provider == constants.VSphereProviderName && o.VSphere.OSFamily != v1alpha1.Bottlerocket && o.VSphere.OSFamily != v1alpha1.Ubuntu {
+		return fmt.Errorf("os family %s is not supported, use %s or %s", o.VSphere.OSFamily, v1alpha1.Bottlerocket, v1alpha1.Ubuntu)
+	}
+	if o.OIDC != nil && (o.OIDC.IssuerUrl == "" || o.OIDC.ClientId == "") {
+		return errors.New("OIDC requires an issuer url and a client id")
+	}
+	if o.AWSIam != nil && o.AWSIam.AWSRegion == "" {
+		return errors.New("AWS IAM authenticator requires an AWS region")
+	}
+	if o.GitOps != nil && (o.GitOps.Flux.Github.Owner == "" || o.GitOps.Flux.Github.Repository == "") {
+		return errors.New("GitOps requires a github owner and repository")
+	}
+	return nil
+}
+
+func validate(content, clusterYaml []byte) error {
+	schemas, err := configschema.Load()
+	if err != nil {
+		return err
+	}
+	if fieldErrs := schemas.Validate(content); len(fieldErrs) > 0 {
+		msgs := make([]string, 0, len(fieldErrs))
+		for _, e := range fieldErrs {
+			msgs = append(msgs, e.Error())
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+
+	cluster := &v1alpha1.Cluster{}
+	if err = yaml.UnmarshalStrict(clusterYaml, cluster); err != nil {
+		return err
+	}
+	return v1alpha1.ValidateClusterConfigContent(cluster)
+}
@@ -0,0 +1,120 @@
+package configgenerator_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/configgenerator"
+)
+
+func TestGenerateDocker(t *testing.T) {
+	g := NewWithT(t)
+	opts, err := configgenerator.NewOptions("test-cluster", "docker")
+	g.Expect(err).To(BeNil())
+
+	content, err := configgenerator.Generate(opts)
+	g.Expect(err).To(BeNil())
+	test.AssertContentToFile(t, string(content), "testdata/expected_docker.yaml")
+}
+
+func TestGenerateVSphereFull(t *testing.T) {
+	g := NewWithT(t)
+	opts, err := configgenerator.NewOptions("test-cluster", "vsphere")
+	g.Expect(err).To(BeNil())
+	opts.KubernetesVersion = v1alpha1.Kube120
+	opts.ControlPlaneCount = 3
+	opts.WorkerCount = 4
+	opts.ControlPlaneEndpoint = "10.0.0.10"
+	opts.WorkerMachine = configgenerator.MachineSize{NumCPUs: 4, MemoryMiB: 16384, DiskGiB: 50}
+	opts.VSphere.Server = "vcenter.example.com"
+	opts.VSphere.Datacenter = "SDDC-Datacenter"
+	opts.VSphere.Network = "/SDDC-Datacenter/network/sddc-cgw-network-1"
+	opts.VSphere.Datastore = "/SDDC-Datacenter/datastore/WorkloadDatastore"
+	opts.VSphere.ResourcePool = "*/Resources"
+	opts.VSphere.Folder = "/SDDC-Datacenter/vm/eksa"
+	opts.VSphere.OSFamily = v1alpha1.Ubuntu
+	opts.OIDC = &v1alpha1.OIDCConfigSpec{IssuerUrl: "https://issuer.example.com", ClientId: "eksa"}
+	opts.AWSIam = &v1alpha1.AWSIamConfigSpec{AWSRegion: "us-west-2", BackendMode: []string{"EKSConfigMap"}, Partition: "aws"}
+	opts.GitOps = &v1alpha1.GitOpsConfigSpec{Flux: v1alpha1.Flux{Github: v1alpha1.Github{Owner: "owner", Repository: "clusters", Personal: true}}}
+	opts.Proxy = &v1alpha1.ProxyConfiguration{HttpProxy: "10.0.0.1:3128", HttpsProxy: "10.0.0.1:3128", NoProxy: []string{".example.com"}}
+
+	content, err := configgenerator.Generate(opts)
+	g.Expect(err).To(BeNil())
+	test.AssertContentToFile(t, string(content), "testdata/expected_vsphere_full.yaml")
+}
+
+func TestGenerateVSphereStackedEtcd(t *testing.T) {
+	g := NewWithT(t)
+	opts, err := configgenerator.NewOptions("test-cluster", "vsphere")
+	g.Expect(err).To(BeNil())
+	opts.ControlPlaneCount = 3
+	opts.ExternalEtcd = false
+
+	content, err := configgenerator.Generate(opts)
+	g.Expect(err).To(BeNil())
+	g.Expect(string(content)).NotTo(ContainSubstring("externalEtcdConfiguration"))
+	g.Expect(string(content)).NotTo(ContainSubstring("test-cluster-etcd"))
+}
+
+func TestNewOptionsInvalidProvider(t *testing.T) {
+	g := NewWithT(t)
+	_, err := configgenerator.NewOptions("test-cluster", "aws")
+	g.Expect(err).To(MatchError(ContainSubstring("not a valid provider")))
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(*configgenerator.Options)
+		wantErr string
+	}{
+		{
+			name:    "no workers",
+			update:  func(o *configgenerator.Options) { o.WorkerCount = 0 },
+			wantErr: "worker node count must be positive",
+		},
+		{
+			name:    "no cpus",
+			update:  func(o *configgenerator.Options) { o.EtcdMachine.NumCPUs = 0 },
+			wantErr: "machine cpus, memory and disk size must be positive",
+		},
+		{
+			name: "oidc without client id",
+			update: func(o *configgenerator.Options) {
+				o.OIDC = &v1alpha1.OIDCConfigSpec{IssuerUrl: "https://issuer.example.com"}
+			},
+			wantErr: "OIDC requires an issuer url and a client id",
+		},
+		{
+			name:    "even stacked control plane",
+			update:  func(o *configgenerator.Options) { o.ExternalEtcd = false },
+			wantErr: "control plane node count cannot be an even number",
+		},
+		{
+			name: "invalid proxy",
+			update: func(o *configgenerator.Options) {
+				o.Proxy = &v1alpha1.ProxyConfiguration{HttpProxy: "proxy", HttpsProxy: "proxy"}
+			},
+			wantErr: "proxy proxy is invalid",
+		},
+		{
+			name:    "invalid os family",
+			update:  func(o *configgenerator.Options) { o.VSphere.OSFamily = "windows" },
+			wantErr: "os family windows is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			opts, err := configgenerator.NewOptions("test-cluster", "vsphere")
+			g.Expect(err).To(BeNil())
+			tt.update(opts)
+
+			_, err = configgenerator.Generate(opts)
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/configgenerator/autofill.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVSphereInventory is a mock of VSphereInventory interface.
+type MockVSphereInventory struct {
+	ctrl     *gomock.Controller
+	recorder *MockVSphereInventoryMockRecorder
+}
+
+// MockVSphereInventoryMockRecorder is the mock recorder for MockVSphereInventory.
+type MockVSphereInventoryMockRecorder struct {
+	mock *MockVSphereInventory
+}
+
+// NewMockVSphereInventory creates a new mock instance.
+func NewMockVSphereInventory(ctrl *gomock.Controller) *MockVSphereInventory {
+	mock := &MockVSphereInventory{ctrl: ctrl}
+	mock.recorder = &MockVSphereInventoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVSphereInventory) EXPECT() *MockVSphereInventoryMockRecorder {
+	return m.recorder
+}
+
+// ListDatacenters mocks base method.
+func (m *MockVSphereInventory) ListDatacenters(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDatacenters", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDatacenters indicates an expected call of ListDatacenters.
+func (mr *MockVSphereInventoryMockRecorder) ListDatacenters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDatacenters", reflect.TypeOf((*MockVSphereInventory)(nil).ListDatacenters), ctx)
+}
+
+// ListDatastores mocks base method.
+func (m *MockVSphereInventory) ListDatastores(ctx context.Context, datacenter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDatastores", ctx, datacenter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDatastores indicates an expected call of ListDatastores.
+func (mr *MockVSphereInventoryMockRecorder) ListDatastores(ctx, datacenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDatastores", reflect.TypeOf((*MockVSphereInventory)(nil).ListDatastores), ctx, datacenter)
+}
+
+// ListFolders mocks base method.
+func (m *MockVSphereInventory) ListFolders(ctx context.Context, datacenter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx, datacenter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockVSphereInventoryMockRecorder) ListFolders(ctx, datacenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockVSphereInventory)(nil).ListFolders), ctx, datacenter)
+}
+
+// ListNetworks mocks base method.
+func (m *MockVSphereInventory) ListNetworks(ctx context.Context, datacenter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNetworks", ctx, datacenter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNetworks indicates an expected call of ListNetworks.
+func (mr *MockVSphereInventoryMockRecorder) ListNetworks(ctx, datacenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworks", reflect.TypeOf((*MockVSphereInventory)(nil).ListNetworks), ctx, datacenter)
+}
+
+// ListResourcePools mocks base method.
+func (m *MockVSphereInventory) ListResourcePools(ctx context.Context, datacenter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourcePools", ctx, datacenter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourcePools indicates an expected call of ListResourcePools.
+func (mr *MockVSphereInventoryMockRecorder) ListResourcePools(ctx, datacenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourcePools", reflect.TypeOf((*MockVSphereInventory)(nil).ListResourcePools), ctx, datacenter)
+}
+
+// ListTemplates mocks base method.
+func (m *MockVSphereInventory) ListTemplates(ctx context.Context, datacenter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, datacenter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockVSphereInventoryMockRecorder) ListTemplates(ctx, datacenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockVSphereInventory)(nil).ListTemplates), ctx, datacenter)
+}
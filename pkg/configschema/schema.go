@@ -0,0 +1,245 @@
+package configschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/config/crd"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Kinds are the config kinds users write in cluster config files
+var Kinds = []string{
+	v1alpha1.ClusterKind,
+	v1alpha1.VSphereDatacenterKind,
+	v1alpha1.VSphereMachineConfigKind,
+	v1alpha1.DockerDatacenterKind,
+	v1alpha1.GitOpsConfigKind,
+	v1alpha1.OIDCConfigKind,
+	v1alpha1.AWSIamConfigKind,
+}
+
+// Schema is the subset of the OpenAPI v3 schema used by the EKS-A CRDs
+type Schema struct {
+	Description            string             `json:"description,omitempty"`
+	Type                   string             `json:"type,omitempty"`
+	Format                 string             `json:"format,omitempty"`
+	Properties             map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties   *Schema            `json:"additionalProperties,omitempty"`
+	Items                  *Schema            `json:"items,omitempty"`
+	Required               []string           `json:"required,omitempty"`
+	Enum                   []interface{}      `json:"enum,omitempty"`
+	Pattern                string             `json:"pattern,omitempty"`
+	Minimum                *float64           `json:"minimum,omitempty"`
+	Maximum                *float64           `json:"maximum,omitempty"`
+	MinLength              *int64             `json:"minLength,omitempty"`
+	MaxLength              *int64             `json:"maxLength,omitempty"`
+	MinItems               *int64             `json:"minItems,omitempty"`
+	MaxItems               *int64             `json:"maxItems,omitempty"`
+	Nullable               bool               `json:"nullable,omitempty"`
+	XPreserveUnknownFields bool               `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XIntOrString           bool               `json:"x-kubernetes-int-or-string,omitempty"`
+}
+
+type crdVersion struct {
+	Name    string `json:"name"`
+	Storage bool   `json:"storage"`
+	Schema  struct {
+		OpenAPIV3Schema *Schema `json:"openAPIV3Schema"`
+	} `json:"schema"`
+}
+
+type customResourceDefinition struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+		Versions []crdVersion `json:"versions"`
+	} `json:"spec"`
+}
+
+// Schemas holds the OpenAPI schema of each config kind
+type Schemas struct {
+	kinds map[string]*Schema
+}
+
+// Load reads the schemas of Kinds from the CRDs embedded in the CLI
+func Load() (*Schemas, error) {
+	return load(crd.Bases)
+}
+
+func load(fsys fs.FS) (*Schemas, error) {
+	files, err := fs.Glob(fsys, "bases/*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("error listing CRDs: %v", err)
+	}
+
+	schemas := &Schemas{kinds: map[string]*Schema{}}
+	for _, f := range files {
+		content, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CRD %s: %v", f, err)
+		}
+		crd := &customResourceDefinition{}
+		if err = yaml.Unmarshal(content, crd); err != nil {
+			return nil, fmt.Errorf("error parsing CRD %s: %v", f, err)
+		}
+		if crd.Spec.Group != v1alpha1.GroupVersion.Group {
+			continue
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Name == v1alpha1.GroupVersion.Version && v.Schema.OpenAPIV3Schema != nil {
+				schemas.kinds[crd.Spec.Names.Kind] = v.Schema.OpenAPIV3Schema
+			}
+		}
+	}
+
+	for _, kind := range Kinds {
+		if _, ok := schemas.kinds[kind]; !ok {
+			return nil, fmt.Errorf("no schema found for %s", kind)
+		}
+	}
+
+	return schemas, nil
+}
+
+// OpenAPI returns the OpenAPI v3 schema of kind, or nil if kind isn't one of Kinds
+func (s *Schemas) OpenAPI(kind string) *Schema {
+	if !isConfigKind(kind) {
+		return nil
+	}
+	return s.kinds[kind]
+}
+
+// JSONSchema returns the JSON Schema of kinds. Without kinds, it returns a schema that accepts a document of any of Kinds.
+// Unlike the OpenAPI schema, unknown fields are not allowed, so editors can flag them
+func (s *Schemas) JSONSchema(kinds ...string) ([]byte, error) {
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+
+	definitions := map[string]interface{}{}
+	oneOf := make([]interface{}, 0, len(kinds))
+	for _, kind := range kinds {
+		schema := s.OpenAPI(kind)
+		if schema == nil {
+			return nil, fmt.Errorf("unknown kind %s, supported kinds: %v", kind, Kinds)
+		}
+		definition := toJSONSchema(schema)
+		properties := definition["properties"].(map[string]interface{})
+		properties["apiVersion"] = map[string]interface{}{"const": v1alpha1.GroupVersion.String()}
+		properties["kind"] = map[string]interface{}{"const": kind}
+		definition["required"] = appendMissing(definition["required"], "apiVersion", "kind")
+
+		definitions[kind] = definition
+		oneOf = append(oneOf, map[string]interface{}{"$ref": "#/definitions/" + kind})
+	}
+
+	root := map[string]interface{}{
+		"$schema":     jsonSchemaDraft,
+		"title":       "EKS Anywhere config",
+		"definitions": definitions,
+	}
+	if len(kinds) == 1 {
+		root["$ref"] = oneOf[0].(map[string]interface{})["$ref"]
+	} else {
+		root["oneOf"] = oneOf
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func toJSONSchema(s *Schema) map[string]interface{} {
+	j := map[string]interface{}{}
+	if s.Description != "" {
+		j["description"] = s.Description
+	}
+	switch {
+	case s.XIntOrString:
+		j["type"] = []string{"integer", "string"}
+	case s.Type != "" && s.Nullable:
+		j["type"] = []string{s.Type, "null"}
+	case s.Type != "":
+		j["type"] = s.Type
+	}
+	if s.Format != "" {
+		j["format"] = s.Format
+	}
+	if len(s.Properties) > 0 {
+		properties := map[string]interface{}{}
+		for name, p := range s.Properties {
+			properties[name] = toJSONSchema(p)
+		}
+		j["properties"] = properties
+	}
+	switch {
+	case s.AdditionalProperties != nil:
+		j["additionalProperties"] = toJSONSchema(s.AdditionalProperties)
+	case len(s.Properties) > 0 && !s.XPreserveUnknownFields:
+		j["additionalProperties"] = false
+	}
+	if s.Items != nil {
+		j["items"] = toJSONSchema(s.Items)
+	}
+	if len(s.Required) > 0 {
+		required := append([]string{}, s.Required...)
+		sort.Strings(required)
+		j["required"] = required
+	}
+	if len(s.Enum) > 0 {
+		j["enum"] = s.Enum
+	}
+	if s.Pattern != "" {
+		j["pattern"] = s.Pattern
+	}
+	setIfNotNil(j, "minimum", s.Minimum)
+	setIfNotNil(j, "maximum", s.Maximum)
+	setIfNotNil(j, "minLength", s.MinLength)
+	setIfNotNil(j, "maxLength", s.MaxLength)
+	setIfNotNil(j, "minItems", s.MinItems)
+	setIfNotNil(j, "maxItems", s.MaxItems)
+	return j
+}
+
+func setIfNotNil(j map[string]interface{}, key string, value interface{}) {
+	switch v := value.(type) {
+	case *float64:
+		if v != nil {
+			j[key] = *v
+		}
+	case *int64:
+		if v != nil {
+			j[key] = *v
+		}
+	}
+}
+
+func appendMissing(required interface{}, names ...string) []string {
+	r, _ := required.([]string)
+	for _, name := range names {
+		if !contains(r, name) {
+			r = append(r, name)
+		}
+	}
+	return r
+}
+
+func isConfigKind(kind string) bool {
+	return contains(Kinds, kind)
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package configschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/configschema"
+)
+
+func TestLoadAllKinds(t *testing.T) {
+	g := NewWithT(t)
+	schemas, err := configschema.Load()
+	g.Expect(err).To(Succeed())
+
+	for _, kind := range configschema.Kinds {
+		schema := schemas.OpenAPI(kind)
+		g.Expect(schema).NotTo(BeNil(), kind)
+		g.Expect(schema.Properties).To(HaveKey("spec"), kind)
+	}
+	g.Expect(schemas.OpenAPI("Bundles")).To(BeNil())
+}
+
+func TestJSONSchemaAllKinds(t *testing.T) {
+	g := NewWithT(t)
+	schemas, err := configschema.Load()
+	g.Expect(err).To(Succeed())
+
+	content, err := schemas.JSONSchema()
+	g.Expect(err).To(Succeed())
+
+	schema := map[string]interface{}{}
+	g.Expect(json.Unmarshal(content, &schema)).To(Succeed())
+	g.Expect(schema["$schema"]).To(Equal("http://json-schema.org/draft-07/schema#"))
+	g.Expect(schema["oneOf"]).To(HaveLen(len(configschema.Kinds)))
+	g.Expect(schema["definitions"]).To(HaveLen(len(configschema.Kinds)))
+}
+
+func TestJSONSchemaKind(t *testing.T) {
+	g := NewWithT(t)
+	schemas, err := configschema.Load()
+	g.Expect(err).To(Succeed())
+
+	content, err := schemas.JSONSchema("Cluster")
+	g.Expect(err).To(Succeed())
+
+	schema := struct {
+		Ref         string `json:"$ref"`
+		Definitions map[string]struct {
+			Required             []string                          `json:"required"`
+			AdditionalProperties bool                              `json:"additionalProperties"`
+			Properties           map[string]map[string]interface{} `json:"properties"`
+		} `json:"definitions"`
+	}{}
+	g.Expect(json.Unmarshal(content, &schema)).To(Succeed())
+	g.Expect(schema.Ref).To(Equal("#/definitions/Cluster"))
+	cluster := schema.Definitions["Cluster"]
+	g.Expect(cluster.Required).To(ContainElements("apiVersion", "kind"))
+	g.Expect(cluster.AdditionalProperties).To(BeFalse())
+	g.Expect(cluster.Properties["kind"]).To(Equal(map[string]interface{}{"const": "Cluster"}))
+	g.Expect(cluster.Properties["apiVersion"]).To(Equal(map[string]interface{}{"const": "anywhere.eks.amazonaws.com/v1alpha1"}))
+	g.Expect(cluster.Properties["spec"]["additionalProperties"]).To(BeFalse())
+}
+
+func TestJSONSchemaUnknownKind(t *testing.T) {
+	g := NewWithT(t)
+	schemas, err := configschema.Load()
+	g.Expect(err).To(Succeed())
+
+	_, err = schemas.JSONSchema("Bundles")
+	g.Expect(err).To(MatchError(ContainSubstring("unknown kind Bundles")))
+}
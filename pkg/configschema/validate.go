@@ -0,0 +1,305 @@
+package configschema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+)
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// FieldError is a problem found in a document, at the line and column where it starts
+type FieldError struct {
+	// Document is the index of the document in the file, starting at 1
+	Document int    `json:"document"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	msg := e.Message
+	if e.Path != "" {
+		msg = fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	switch {
+	case e.Line == 0:
+		return msg
+	case e.Column == 0:
+		return fmt.Sprintf("%d: %s", e.Line, msg)
+	default:
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, msg)
+	}
+}
+
+// Validate checks every document in content against the schema of its kind, without connecting to any cluster.
+// It reports unknown fields, wrong types, missing required fields and values not allowed by the schema.
+// The errors are sorted by position in content
+func (s *Schemas) Validate(content []byte) []FieldError {
+	var errs []FieldError
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for document := 1; ; document++ {
+		node := &yaml.Node{}
+		err := decoder.Decode(node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return append(errs, syntaxError(document, err))
+		}
+		v := &validator{document: document}
+		s.validateDocument(v, node)
+		errs = append(errs, v.errs...)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+func syntaxError(document int, err error) FieldError {
+	e := FieldError{Document: document, Message: err.Error()}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Message = m[2]
+	}
+	return e
+}
+
+type validator struct {
+	document int
+	errs     []FieldError
+}
+
+func (v *validator) addError(node *yaml.Node, path, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{
+		Document: v.document,
+		Line:     node.Line,
+		Column:   node.Column,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (s *Schemas) validateDocument(v *validator, document *yaml.Node) {
+	if len(document.Content) == 0 {
+		return
+	}
+	node := document.Content[0]
+	if isNull(node) {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		v.addError(node, "", "document must be an object")
+		return
+	}
+
+	apiVersion, kind := mappingValue(node, "apiVersion"), mappingValue(node, "kind")
+	if apiVersion == nil || kind == nil {
+		v.addError(node, "", "document must have apiVersion and kind")
+		return
+	}
+	if apiVersion.Value != v1alpha1.GroupVersion.String() {
+		v.addError(apiVersion, "apiVersion", "unsupported apiVersion %s, must be %s", apiVersion.Value, v1alpha1.GroupVersion.String())
+		return
+	}
+	schema := s.OpenAPI(kind.Value)
+	if schema == nil {
+		v.addError(kind, "kind", "unsupported kind %s, supported kinds: %s", kind.Value, strings.Join(Kinds, ", "))
+		return
+	}
+
+	v.validate(node, schema, "")
+}
+
+func (v *validator) validate(node *yaml.Node, schema *Schema, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// Like the api server, null values are dropped before validation
+	if isNull(node) {
+		return
+	}
+
+	switch {
+	case schema.XIntOrString:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!str") {
+			v.addError(node, path, "must be an integer or a string")
+		}
+	case schema.Type == "object":
+		v.validateObject(node, schema, path)
+	case schema.Type == "array":
+		v.validateArray(node, schema, path)
+	case schema.Type != "":
+		v.validateScalar(node, schema, path)
+	}
+}
+
+func (v *validator) validateObject(node *yaml.Node, schema *Schema, path string) {
+	if node.Kind != yaml.MappingNode {
+		v.addError(node, path, "must be an object")
+		return
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldPath := joinPath(path, key.Value)
+		if seen[key.Value] {
+			v.addError(key, fieldPath, "duplicate field")
+			continue
+		}
+		seen[key.Value] = true
+
+		if p, ok := schema.Properties[key.Value]; ok {
+			v.validate(value, p, fieldPath)
+			continue
+		}
+		switch {
+		case schema.AdditionalProperties != nil:
+			v.validate(value, schema.AdditionalProperties, fieldPath)
+		case len(schema.Properties) > 0 && !schema.XPreserveUnknownFields:
+			v.addError(key, fieldPath, "unknown field%s", suggestion(key.Value, schema.Properties))
+		}
+	}
+
+	for _, r := range schema.Required {
+		if !seen[r] {
+			v.addError(node, path, "missing required field %s", r)
+		}
+	}
+}
+
+func (v *validator) validateArray(node *yaml.Node, schema *Schema, path string) {
+	if node.Kind != yaml.SequenceNode {
+		v.addError(node, path, "must be an array")
+		return
+	}
+	if schema.MinItems != nil && int64(len(node.Content)) < *schema.MinItems {
+		v.addError(node, path, "must have at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && int64(len(node.Content)) > *schema.MaxItems {
+		v.addError(node, path, "must have at most %d items", *schema.MaxItems)
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range node.Content {
+		v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+func (v *validator) validateScalar(node *yaml.Node, schema *Schema, path string) {
+	if node.Kind != yaml.ScalarNode {
+		v.addError(node, path, "must be %s", article(schema.Type))
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		// Like the api server, quoted and unquoted values are accepted as strings unless they're a different yaml type
+		if node.Tag != "!!str" && node.Tag != "!!timestamp" {
+			v.addError(node, path, "must be a string, got %s", node.Value)
+			return
+		}
+		if schema.MinLength != nil && int64(len(node.Value)) < *schema.MinLength {
+			v.addError(node, path, "must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && int64(len(node.Value)) > *schema.MaxLength {
+			v.addError(node, path, "must be at most %d characters long", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if r, err := regexp.Compile(schema.Pattern); err == nil && !r.MatchString(node.Value) {
+				v.addError(node, path, "must match %s", schema.Pattern)
+			}
+		}
+	case "integer", "number":
+		n, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil || (node.Tag != "!!int" && node.Tag != "!!float") || (schema.Type == "integer" && n != math.Trunc(n)) {
+			v.addError(node, path, "must be %s, got %s", article(schema.Type), node.Value)
+			return
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			v.addError(node, path, "must be greater than or equal to %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			v.addError(node, path, "must be less than or equal to %v", *schema.Maximum)
+		}
+	case "boolean":
+		if node.Tag != "!!bool" {
+			v.addError(node, path, "must be a boolean, got %s", node.Value)
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(node.Value, schema.Enum) {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, e := range schema.Enum {
+			allowed = append(allowed, fmt.Sprint(e))
+		}
+		v.addError(node, path, "unsupported value %s, must be one of: %s", node.Value, strings.Join(allowed, ", "))
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func inEnum(value string, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestion returns a hint with the known field that only differs from field in case, which is a common typo
+func suggestion(field string, properties map[string]*Schema) string {
+	for p := range properties {
+		if strings.EqualFold(p, field) {
+			return fmt.Sprintf(", did you mean %s?", p)
+		}
+	}
+	return ""
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func article(t string) string {
+	switch t {
+	case "object", "array", "integer":
+		return "an " + t
+	default:
+		return "a " + t
+	}
+}
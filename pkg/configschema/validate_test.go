@@ -0,0 +1,129 @@
+package configschema_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/configschema"
+)
+
+func TestValidateFile(t *testing.T) {
+	g := NewWithT(t)
+	schemas, err := configschema.Load()
+	g.Expect(err).To(Succeed())
+
+	errs := schemas.Validate([]byte(test.ReadFile(t, "testdata/invalid_cluster.yaml")))
+	g.Expect(errs).To(Equal([]configschema.FieldError{
+		{Document: 1, Line: 15, Column: 12, Path: "spec.controlPlaneConfiguration.count", Message: "must be an integer, got three"},
+		{Document: 1, Line: 30, Column: 5, Path: "spec.workerNodeGroupConfigurations[0].Count", Message: "unknown field, did you mean count?"},
+		{Document: 1, Line: 31, Column: 3, Path: "spec.extraField", Message: "unknown field"},
+		{Document: 3, Line: 52, Column: 7, Path: "kind", Message: "unsupported kind Unknown, supported kinds: Cluster, VSphereDatacenterConfig, VSphereMachineConfig, DockerDatacenterConfig, GitOpsConfig, OIDCConfig, AWSIamConfig"},
+	}))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "valid",
+			content: `
+apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+kind: DockerDatacenterConfig
+metadata:
+  name: test
+spec:
+`,
+		},
+		{
+			name: "empty documents",
+			content: `---
+---
+# comment
+`,
+		},
+		{
+			name: "syntax error",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+kind: Cluster
+ metadata: {}
+`,
+			want: []string{"3: mapping values are not allowed in this context"},
+		},
+		{
+			name: "missing kind",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+metadata: {}
+`,
+			want: []string{"1:1: document must have apiVersion and kind"},
+		},
+		{
+			name: "wrong api version",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1
+kind: Cluster
+`,
+			want: []string{"1:13: apiVersion: unsupported apiVersion anywhere.eks.amazonaws.com/v1, must be anywhere.eks.amazonaws.com/v1alpha1"},
+		},
+		{
+			name: "duplicate field",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+kind: OIDCConfig
+spec:
+  clientId: a
+  clientId: b
+  issuerUrl: https://example.com
+`,
+			want: []string{"5:3: spec.clientId: duplicate field"},
+		},
+		{
+			name: "missing required field and wrong types",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+kind: GitOpsConfig
+spec:
+  flux:
+    github:
+      owner: [me]
+      repository: repo
+      personal: "yes"
+`,
+			want: []string{
+				"6:14: spec.flux.github.owner: must be a string",
+				"8:17: spec.flux.github.personal: must be a boolean, got yes",
+			},
+		},
+		{
+			name: "missing required field",
+			content: `apiVersion: anywhere.eks.amazonaws.com/v1alpha1
+kind: GitOpsConfig
+spec:
+  flux:
+    github:
+      repository: repo
+`,
+			want: []string{"6:7: spec.flux.github: missing required field owner"},
+		},
+	}
+
+	schemas, err := configschema.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := schemas.Validate([]byte(tt.content))
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
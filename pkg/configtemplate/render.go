@@ -0,0 +1,290 @@
+package configtemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+)
+
+var (
+	variable = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+	// secretVariable matches the names of the variables holding credentials, which are redacted from persisted configs
+	secretVariable = regexp.MustCompile(`(?i)PASSWORD|PASSWD|SECRET|TOKEN|CREDENTIAL|PRIVATE_?KEY|ACCESS_?KEY|API_?KEY`)
+)
+
+// Substitute replaces ${VAR} in the scalars of the YAML content with the value of the environment variable VAR
+// and ${VAR:-default} with default when VAR is not set. $${ is replaced with a literal ${.
+// Comments are not substituted, and a value is quoted when needed so it's always a single scalar: values with
+// YAML syntax like ": ", " #" or newlines can't change the structure of the config.
+// It returns an error with every variable that is not set and has no default
+func Substitute(content []byte) ([]byte, error) {
+	return substitute(content, false)
+}
+
+// substitute substitutes the variables in content. With redact, the scalars using a secret variable are kept
+// as written, so the result doesn't contain the secret values
+func substitute(content []byte, redact bool) ([]byte, error) {
+	var documents []*yamlv3.Node
+	decoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	for {
+		document := &yamlv3.Node{}
+		err := decoder.Decode(document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing yaml: %v", err)
+		}
+		documents = append(documents, document)
+	}
+
+	s := &substitution{missing: map[string]struct{}{}, redact: redact}
+	for _, d := range documents {
+		s.substitute(d)
+	}
+
+	if len(s.missing) > 0 {
+		names := make([]string, 0, len(s.missing))
+		for name := range s.missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("environment variables not set: %s", strings.Join(names, ", "))
+	}
+	if !s.changed {
+		return content, nil
+	}
+
+	result := &bytes.Buffer{}
+	encoder := yamlv3.NewEncoder(result)
+	encoder.SetIndent(2)
+	for _, d := range documents {
+		if err := encoder.Encode(d); err != nil {
+			return nil, fmt.Errorf("error outputting yaml: %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error outputting yaml: %v", err)
+	}
+	return result.Bytes(), nil
+}
+
+type substitution struct {
+	missing map[string]struct{}
+	redact  bool
+	changed bool
+}
+
+func (s *substitution) substitute(node *yamlv3.Node) {
+	if node.Kind != yamlv3.ScalarNode {
+		for _, n := range node.Content {
+			s.substitute(n)
+		}
+		return
+	}
+
+	matched, secret := false, false
+	value := variable.ReplaceAllStringFunc(node.Value, func(match string) string {
+		matched = true
+		if match == "$${" {
+			return "${"
+		}
+		groups := variable.FindStringSubmatch(match)
+		name := groups[1]
+		secret = secret || secretVariable.MatchString(name)
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if len(groups[2]) > 0 {
+			return groups[3]
+		}
+		s.missing[name] = struct{}{}
+		return match
+	})
+	if !matched || (s.redact && secret) {
+		return
+	}
+
+	node.Value = value
+	// A plain scalar gets the type of its substituted value, like numbers and booleans, which the encoder
+	// quotes when the value isn't a valid plain scalar
+	if node.Style == 0 {
+		node.Tag = ""
+	}
+	s.changed = true
+}
+
+// Render substitutes the environment variables in the base config and the values and merges each values document
+// into the base documents with the same kind and, if the values document has one, the same name.
+// Maps are merged, any other value, including lists, replaces the base value and null removes it from the base.
+// Without values, the base config is only substituted, keeping its comments
+func Render(base []byte, values ...[]byte) ([]byte, error) {
+	return render(false, base, values...)
+}
+
+// RenderRedacted renders the config like Render, but the scalars using a variable whose name looks like a
+// credential, like PASSWORD, SECRET, TOKEN or ACCESS_KEY, are kept as written instead of substituted.
+// Its result can be persisted without leaking the secrets read from the environment
+func RenderRedacted(base []byte, values ...[]byte) ([]byte, error) {
+	return render(true, base, values...)
+}
+
+func render(redact bool, base []byte, values ...[]byte) ([]byte, error) {
+	base, err := substitute(base, redact)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering base config: %v", err)
+	}
+	if len(values) == 0 {
+		return base, nil
+	}
+
+	documents, err := parseDocuments(base)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base config: %v", err)
+	}
+
+	for i, v := range values {
+		v, err = substitute(v, redact)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering values %d: %v", i+1, err)
+		}
+		patches, err := parseDocuments(v)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing values %d: %v", i+1, err)
+		}
+		for _, patch := range patches {
+			if err = apply(documents, patch); err != nil {
+				return nil, fmt.Errorf("error applying values %d: %v", i+1, err)
+			}
+		}
+	}
+
+	resources := make([]string, 0, len(documents))
+	for _, d := range documents {
+		content, err := yaml.Marshal(d)
+		if err != nil {
+			return nil, fmt.Errorf("error outputting yaml: %v", err)
+		}
+		resources = append(resources, strings.TrimSuffix(string(content), "\n"))
+	}
+	return []byte(strings.Join(resources, v1alpha1.YamlSeparator) + "\n"), nil
+}
+
+// RenderFiles renders the base config file with the values files. It returns the rendered config and its
+// redacted version, see RenderRedacted, which are the same when no secret variable is used
+func RenderFiles(baseFile string, valuesFiles ...string) (rendered, redacted []byte, err error) {
+	base, err := ioutil.ReadFile(baseFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read file due to: %v", err)
+	}
+	values := make([][]byte, 0, len(valuesFiles))
+	for _, f := range valuesFiles {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read values file due to: %v", err)
+		}
+		values = append(values, content)
+	}
+
+	if rendered, err = Render(base, values...); err != nil {
+		return nil, nil, err
+	}
+	if redacted, err = RenderRedacted(base, values...); err != nil {
+		return nil, nil, err
+	}
+	return rendered, redacted, nil
+}
+
+// ClusterName returns the name of the Cluster in the config
+func ClusterName(content []byte) (string, error) {
+	for _, c := range strings.Split(string(content), v1alpha1.YamlSeparator) {
+		cluster := &v1alpha1.Cluster{}
+		if err := yaml.Unmarshal([]byte(c), cluster); err != nil {
+			return "", fmt.Errorf("unable to parse config: %v", err)
+		}
+		if cluster.Kind() == v1alpha1.ClusterKind {
+			return cluster.Name, nil
+		}
+	}
+	return "", fmt.Errorf("config does not contain kind %s", v1alpha1.ClusterKind)
+}
+
+func parseDocuments(content []byte) ([]map[string]interface{}, error) {
+	var documents []map[string]interface{}
+	for _, c := range strings.Split(string(content), v1alpha1.YamlSeparator) {
+		if len(bytes.TrimSpace([]byte(c))) == 0 {
+			continue
+		}
+		document := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(c), &document); err != nil {
+			return nil, err
+		}
+		if len(document) > 0 {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+// apply merges patch into the documents it targets
+func apply(documents []map[string]interface{}, patch map[string]interface{}) error {
+	kind, _ := patch["kind"].(string)
+	if kind == "" {
+		return errors.New("values document without kind")
+	}
+	name := documentName(patch)
+
+	matched := false
+	for _, d := range documents {
+		if d["kind"] != kind || (name != "" && documentName(d) != name) {
+			continue
+		}
+		merge(d, patch)
+		matched = true
+	}
+	if !matched {
+		if name != "" {
+			return fmt.Errorf("no %s named %s in the base config", kind, name)
+		}
+		return fmt.Errorf("no %s in the base config", kind)
+	}
+	return nil
+}
+
+func documentName(document map[string]interface{}) string {
+	metadata, _ := document["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
+
+// merge merges patch into dst with JSON merge patch semantics
+func merge(dst, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+		patchMap, isMap := value.(map[string]interface{})
+		if !isMap {
+			dst[key] = value
+			continue
+		}
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if !dstIsMap {
+			dstMap = map[string]interface{}{}
+			dst[key] = dstMap
+		}
+		merge(dstMap, patchMap)
+	}
+}
@@ -19,6 +19,7 @@ const (
 	KubePublicNamespace                     = "kube-public"
 	KubeSystemNamespace                     = "kube-system"
 	LocalPathStorageNamespace               = "local-path-storage"
+	FluxSystemNamespace                     = "flux-system"
 	EtcdAdmBootstrapProviderName            = "bootstrap-etcdadm-bootstrap"
 	EtcdadmControllerProviderName           = "bootstrap-etcdadm-controller"
 
@@ -15,9 +15,11 @@ import (
 	"github.com/aws/eks-anywhere/pkg/diagnostics"
 	"github.com/aws/eks-anywhere/pkg/executables"
 	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/loadbalancer"
 	"github.com/aws/eks-anywhere/pkg/networking"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	"github.com/aws/eks-anywhere/pkg/providers/factory"
+	"github.com/aws/eks-anywhere/pkg/proxy"
 )
 
 type Dependencies struct {
@@ -32,6 +34,7 @@ type Dependencies struct {
 	Flux                      *executables.Flux
 	Troubleshoot              *executables.Troubleshoot
 	Networking                clustermanager.Networking
+	LoadBalancer              clustermanager.LoadBalancer
 	AwsIamAuth                clustermanager.AwsIamAuth
 	ClusterManager            *clustermanager.ClusterManager
 	Bootstrapper              *bootstrapper.Bootstrapper
@@ -48,7 +51,8 @@ func ForSpec(ctx context.Context, clusterSpec *cluster.Spec) *Factory {
 	return NewFactory().
 		WithExecutableBuilder(ctx, clusterSpec.UseImageMirror(eksaToolsImage.VersionedImage())).
 		WithWriterFolder(clusterSpec.Name).
-		WithDiagnosticCollectorImage(clusterSpec.VersionsBundle.Eksa.DiagnosticCollector.VersionedImage())
+		WithDiagnosticCollectorImage(clusterSpec.VersionsBundle.Eksa.DiagnosticCollector.VersionedImage()).
+		WithProxyConfiguration(proxy.New(clusterSpec.Cluster))
 }
 
 type Factory struct {
@@ -56,6 +60,8 @@ type Factory struct {
 	providerFactory          *factory.ProviderFactory
 	writerFolder             string
 	diagnosticCollectorImage string
+	proxyConfiguration       *proxy.Configuration
+	readOnlyProvider         bool
 	buildSteps               []func() error
 	dependencies             Dependencies
 }
@@ -84,6 +90,13 @@ func (f *Factory) Build() (*Dependencies, error) {
 	return &d, nil
 }
 
+// WithProxyConfiguration sets the proxy configuration for the commands run in the tools image
+// and the diagnostic collector pods
+func (f *Factory) WithProxyConfiguration(proxyConfiguration *proxy.Configuration) *Factory {
+	f.proxyConfiguration = proxyConfiguration
+	return f
+}
+
 func (f *Factory) WithWriterFolder(folder string) *Factory {
 	f.writerFolder = folder
 	return f
@@ -96,7 +109,7 @@ func (f *Factory) WithExecutableBuilder(ctx context.Context, image string) *Fact
 			return err
 		}
 
-		f.executableBuilder = b
+		f.executableBuilder = b.WithEnv(f.proxyConfiguration.EnvMap())
 		return nil
 	})
 
@@ -123,6 +136,13 @@ func (f *Factory) WithProvider(clusterConfigFile string, clusterConfig *v1alpha1
 	return f
 }
 
+// WithReadOnlyProvider builds a provider that only reads from the infrastructure during its setup and validations,
+// for commands that mustn't change anything
+func (f *Factory) WithReadOnlyProvider() *Factory {
+	f.readOnlyProvider = true
+	return f
+}
+
 func (f *Factory) WithProviderFactory() *Factory {
 	f.WithDocker().WithKubectl().WithGovc().WithWriter().WithCAPIClusterResourceSetManager()
 
@@ -138,6 +158,7 @@ func (f *Factory) WithProviderFactory() *Factory {
 			VSphereKubectlClient:      f.dependencies.Kubectl,
 			Writer:                    f.dependencies.Writer,
 			ClusterResourceSetManager: f.dependencies.ResourceSetManager,
+			ReadOnly:                  f.readOnlyProvider,
 		}
 
 		return nil
@@ -287,6 +308,19 @@ func (f *Factory) WithNetworking() *Factory {
 	return f
 }
 
+func (f *Factory) WithLoadBalancer() *Factory {
+	f.buildSteps = append(f.buildSteps, func() error {
+		if f.dependencies.LoadBalancer != nil {
+			return nil
+		}
+
+		f.dependencies.LoadBalancer = loadbalancer.New()
+		return nil
+	})
+
+	return f
+}
+
 func (f *Factory) WithAwsIamAuth() *Factory {
 	f.buildSteps = append(f.buildSteps, func() error {
 		if f.dependencies.AwsIamAuth != nil {
@@ -326,7 +360,7 @@ type clusterManagerClient struct {
 }
 
 func (f *Factory) WithClusterManager() *Factory {
-	f.WithClusterctl().WithKubectl().WithNetworking().WithWriter().WithDiagnosticBundleFactory().WithAwsIamAuth()
+	f.WithClusterctl().WithKubectl().WithNetworking().WithLoadBalancer().WithWriter().WithDiagnosticBundleFactory().WithAwsIamAuth()
 
 	f.buildSteps = append(f.buildSteps, func() error {
 		if f.dependencies.ClusterManager != nil {
@@ -339,6 +373,7 @@ func (f *Factory) WithClusterManager() *Factory {
 				f.dependencies.Kubectl,
 			},
 			f.dependencies.Networking,
+			f.dependencies.LoadBalancer,
 			f.dependencies.Writer,
 			f.dependencies.DignosticCollectorFactory,
 			f.dependencies.AwsIamAuth,
@@ -425,7 +460,7 @@ func (f *Factory) WithCollectorFactory() *Factory {
 		if f.diagnosticCollectorImage == "" {
 			f.dependencies.CollectorFactory = diagnostics.NewDefaultCollectorFactory()
 		} else {
-			f.dependencies.CollectorFactory = diagnostics.NewCollectorFactory(f.diagnosticCollectorImage)
+			f.dependencies.CollectorFactory = diagnostics.NewCollectorFactory(f.diagnosticCollectorImage, diagnostics.WithProxyEnv(f.proxyConfiguration.EnvMap()))
 		}
 		return nil
 	})
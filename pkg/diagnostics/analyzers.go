@@ -3,6 +3,7 @@ package diagnostics
 import (
 	"fmt"
 	"path"
+	"regexp"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/constants"
@@ -10,6 +11,7 @@ import (
 
 const (
 	logAnalysisAnalyzerPrefix = "log analysis:"
+	clusterResourcesPath      = "cluster-resources"
 )
 
 type analyzerFactory struct{}
@@ -20,7 +22,66 @@ func NewAnalyzerFactory() *analyzerFactory {
 
 func (a *analyzerFactory) DefaultAnalyzers() []*Analyze {
 	var analyzers []*Analyze
-	return append(analyzers, a.defaultDeploymentAnalyzers()...)
+	analyzers = append(analyzers, a.defaultDeploymentAnalyzers()...)
+	return append(analyzers, a.nodeNetworkAnalyzer())
+}
+
+// nodeNetworkAnalyzer checks for nodes that are NotReady because the CNI isn't running
+func (a *analyzerFactory) nodeNetworkAnalyzer() *Analyze {
+	return &Analyze{
+		TextAnalyze: &textAnalyze{
+			analyzeMeta: analyzeMeta{
+				CheckName: "node network plugin not ready",
+			},
+			FileName:     path.Join(clusterResourcesPath, "nodes.json"),
+			RegexPattern: `NetworkPluginNotReady|cni plugin not initialized|cni config uninitialized`,
+			Outcomes: []*outcome{
+				{
+					Fail: &singleOutcome{
+						When:    "true",
+						Message: "Nodes are NotReady because the network plugin isn't running; check the cilium pods in kube-system and that the nodes can pull the cilium images.",
+					},
+				},
+				{
+					Pass: &singleOutcome{
+						When:    "false",
+						Message: "Node network plugin is ready",
+					},
+				},
+			},
+		},
+	}
+}
+
+// EksaRegistryMirrorAnalyzers checks for pods failing to pull images from the registry mirror endpoints
+func (a *analyzerFactory) EksaRegistryMirrorAnalyzers(mirror *v1alpha1.RegistryMirrorConfiguration) []*Analyze {
+	var analyzers []*Analyze
+	for _, endpoint := range mirror.Endpoints() {
+		analyzers = append(analyzers, &Analyze{
+			TextAnalyze: &textAnalyze{
+				analyzeMeta: analyzeMeta{
+					CheckName: fmt.Sprintf("image pull from registry mirror %s", endpoint),
+				},
+				FileName:     path.Join(clusterResourcesPath, "events", "*.json"),
+				RegexPattern: fmt.Sprintf(`Failed to pull image \\"%s/`, regexp.QuoteMeta(endpoint)),
+				Outcomes: []*outcome{
+					{
+						Fail: &singleOutcome{
+							When:    "true",
+							Message: fmt.Sprintf("Pods failed to pull images from the registry mirror %s; verify it's reachable from the nodes, its CA certificate and credentials are set in the registryMirrorConfiguration and the images have been imported into it.", endpoint),
+						},
+					},
+					{
+						Pass: &singleOutcome{
+							When:    "false",
+							Message: fmt.Sprintf("Images pulled from the registry mirror %s", endpoint),
+						},
+					},
+				},
+			},
+		})
+	}
+	return analyzers
 }
 
 func (a *analyzerFactory) defaultDeploymentAnalyzers() []*Analyze {
@@ -189,36 +250,145 @@ func (a *analyzerFactory) EksaLogTextAnalyzers(collectors []*Collect) []*Analyze
 // namespaceLogTextAnalyzersMap is used to associated log text analyzers with the logs collected from a specific namespace.
 // the key of the analyzers map is the namespace name, and the value are the associated log text analyzers.
 func (a *analyzerFactory) namespaceLogTextAnalyzersMap() map[string][]*Analyze {
-	return map[string][]*Analyze{
-		constants.CapiKubeadmControlPlaneSystemNamespace: a.capiKubeadmControlPlaneSystemLogAnalyzers(),
+	analyzers := map[string][]*Analyze{}
+	for namespace, signatures := range namespaceFailureSignatures() {
+		for _, signature := range signatures {
+			analyzers[namespace] = append(analyzers[namespace], a.logTextAnalyzer(namespace, signature))
+		}
+	}
+	return analyzers
+}
+
+// failureSignature is a known failure mode, identified by a regex in the logs of a container
+type failureSignature struct {
+	name        string
+	pod         string
+	container   string
+	regex       string
+	remediation string
+	pass        string
+}
+
+func certificateExpiredSignature(pod, container string) failureSignature {
+	return failureSignature{
+		name:        "certificate expired",
+		pod:         pod,
+		container:   container,
+		regex:       `x509: certificate has expired or is not yet valid`,
+		remediation: "A certificate has expired or isn't valid yet; verify the clocks of the nodes are in sync and renew the expired certificates.",
+		pass:        "No expired certificates found",
 	}
 }
 
-func (a *analyzerFactory) capiKubeadmControlPlaneSystemLogAnalyzers() []*Analyze {
+func namespaceFailureSignatures() map[string][]failureSignature {
 	capiCpManagerPod := "capi-kubeadm-control-plane-controller-manager-*"
-	capiCpManagerContainerLogFile := path.Join(capiCpManagerPod, "manager.log")
-	fullManagerPodLogPath := path.Join(logpath(constants.CapiKubeadmControlPlaneSystemNamespace), capiCpManagerContainerLogFile)
-	return []*Analyze{
-		{
-			TextAnalyze: &textAnalyze{
-				analyzeMeta: analyzeMeta{
-					CheckName: fmt.Sprintf("%s: API server pod missing. Log: %s", logAnalysisAnalyzerPrefix, fullManagerPodLogPath),
-				},
-				CollectorName: constants.CapiKubeadmControlPlaneSystemNamespace,
-				FileName:      capiCpManagerContainerLogFile,
-				RegexPattern:  `machine (.*?) reports APIServerPodHealthy condition is false \(Error, Pod kube-apiserver-(.*?) is missing\)`,
-				Outcomes: []*outcome{
-					{
-						Fail: &singleOutcome{
-							When:    "true",
-							Message: fmt.Sprintf("Node failed to launch correctly; API server pod is missing. See %s", fullManagerPodLogPath),
-						},
+	capvManagerPod := "capv-controller-manager-*"
+	return map[string][]failureSignature{
+		constants.CapiKubeadmControlPlaneSystemNamespace: {
+			{
+				name:        "API server pod missing",
+				pod:         capiCpManagerPod,
+				container:   "manager",
+				regex:       `machine (.*?) reports APIServerPodHealthy condition is false \(Error, Pod kube-apiserver-(.*?) is missing\)`,
+				remediation: "Node failed to launch correctly; API server pod is missing.",
+				pass:        "API server pods launched correctly",
+			},
+			{
+				name:        "etcd cluster unhealthy",
+				pod:         capiCpManagerPod,
+				container:   "manager",
+				regex:       `EtcdClusterHealthy condition is false|etcd cluster is not healthy`,
+				remediation: "The control plane etcd cluster is unhealthy; verify more than half of the control plane nodes are running and can reach each other on ports 2379 and 2380.",
+				pass:        "Control plane etcd cluster is healthy",
+			},
+			certificateExpiredSignature(capiCpManagerPod, "manager"),
+		},
+		constants.CapiSystemNamespace: {
+			certificateExpiredSignature("capi-controller-manager-*", "manager"),
+		},
+		constants.CapvSystemNamespace: {
+			{
+				name:        "vSphere template clone failure",
+				pod:         capvManagerPod,
+				container:   "manager",
+				regex:       `(?i)(failed|unable) to clone|error cloning`,
+				remediation: "vSphere failed to clone the VM template; verify the template in the VSphereMachineConfig exists in the datacenter, is marked as a template and the datastore has enough free space.",
+				pass:        "No vSphere template clone failures found",
+			},
+			{
+				name:        "vCenter permission denied",
+				pod:         capvManagerPod,
+				container:   "manager",
+				regex:       `Permission to perform this operation was denied|NoPermission`,
+				remediation: "The vCenter user is missing privileges; verify the role assigned to the user has the permissions required by EKS Anywhere.",
+				pass:        "No vCenter permission errors found",
+			},
+			certificateExpiredSignature(capvManagerPod, "manager"),
+		},
+		constants.EtcdAdmControllerSystemNamespace: {
+			{
+				name:        "external etcd cluster unhealthy",
+				pod:         "etcdadm-controller-controller-manager-*",
+				container:   "manager",
+				regex:       `(?i)etcd cluster.*(unhealthy|not healthy)`,
+				remediation: "The external etcd cluster is unhealthy; verify more than half of the etcd nodes are running and can reach each other on ports 2379 and 2380.",
+				pass:        "External etcd cluster is healthy",
+			},
+		},
+		constants.FluxSystemNamespace: {
+			{
+				name:        "Flux source failure",
+				pod:         "source-controller-*",
+				container:   "manager",
+				regex:       `failed to checkout and determine revision|unable to clone|authentication required|repository not found`,
+				remediation: "Flux can't fetch the GitOps repository; verify the repository and branch in the GitOpsConfig exist and the Flux deploy key or token has access to them.",
+				pass:        "Flux fetched the GitOps repository",
+			},
+		},
+		constants.KubeSystemNamespace: {
+			{
+				name:        "kube-vip leader election failure",
+				pod:         "kube-vip-*",
+				container:   "kube-vip",
+				regex:       `error retrieving resource lock|failed to renew lease|Failed to update lock`,
+				remediation: "kube-vip failed the leader election and the control plane endpoint may be unreachable; verify the control plane endpoint IP isn't used by another host and the API server is reachable from the control plane nodes.",
+				pass:        "kube-vip leader election succeeded",
+			},
+			{
+				name:        "etcd quorum lost",
+				pod:         "etcd-*",
+				container:   "etcd",
+				regex:       `lost leader|etcdserver: no leader|etcdserver: request timed out`,
+				remediation: "etcd lost quorum; verify more than half of the etcd members are running and can reach each other on ports 2379 and 2380.",
+				pass:        "etcd has quorum",
+			},
+			certificateExpiredSignature("kube-apiserver-*", "kube-apiserver"),
+		},
+	}
+}
+
+func (a *analyzerFactory) logTextAnalyzer(namespace string, signature failureSignature) *Analyze {
+	containerLogFile := path.Join(signature.pod, signature.container+".log")
+	fullLogPath := path.Join(logpath(namespace), containerLogFile)
+	return &Analyze{
+		TextAnalyze: &textAnalyze{
+			analyzeMeta: analyzeMeta{
+				CheckName: fmt.Sprintf("%s: %s. Log: %s", logAnalysisAnalyzerPrefix, signature.name, fullLogPath),
+			},
+			CollectorName: namespace,
+			FileName:      containerLogFile,
+			RegexPattern:  signature.regex,
+			Outcomes: []*outcome{
+				{
+					Fail: &singleOutcome{
+						When:    "true",
+						Message: fmt.Sprintf("%s See %s", signature.remediation, fullLogPath),
 					},
-					{
-						Pass: &singleOutcome{
-							When:    "false",
-							Message: "API server pods launched correctly",
-						},
+				},
+				{
+					Pass: &singleOutcome{
+						When:    "false",
+						Message: signature.pass,
 					},
 				},
 			},
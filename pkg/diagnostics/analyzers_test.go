@@ -0,0 +1,130 @@
+package diagnostics_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/diagnostics"
+)
+
+func TestEksaLogTextAnalyzersManagementCluster(t *testing.T) {
+	g := NewWithT(t)
+	collectors := diagnostics.NewDefaultCollectorFactory().ManagementClusterCollectors()
+	collectors = append(collectors, diagnostics.NewDefaultCollectorFactory().DefaultCollectors()...)
+
+	analyzers := diagnostics.NewAnalyzerFactory().EksaLogTextAnalyzers(collectors)
+
+	checks := make([]string, 0, len(analyzers))
+	for _, a := range analyzers {
+		g.Expect(a.TextAnalyze).NotTo(BeNil())
+		_, err := regexp.Compile(a.TextAnalyze.RegexPattern)
+		g.Expect(err).To(BeNil(), "invalid regex in %s", a.TextAnalyze.CheckName)
+		checks = append(checks, a.TextAnalyze.CheckName)
+	}
+	for _, want := range []string{
+		"API server pod missing",
+		"vSphere template clone failure",
+		"vCenter permission denied",
+		"kube-vip leader election failure",
+		"etcd quorum lost",
+		"certificate expired",
+		"Flux source failure",
+	} {
+		g.Expect(strings.Join(checks, "\n")).To(ContainSubstring(want))
+	}
+}
+
+func TestEksaLogTextAnalyzersNoLogCollectors(t *testing.T) {
+	g := NewWithT(t)
+	analyzers := diagnostics.NewAnalyzerFactory().EksaLogTextAnalyzers([]*diagnostics.Collect{})
+	g.Expect(analyzers).To(BeEmpty())
+}
+
+func TestLogTextAnalyzersMatchFailures(t *testing.T) {
+	tests := []struct {
+		check string
+		log   string
+	}{
+		{
+			check: "vSphere template clone failure",
+			log:   `E1019 vspherevm_controller.go:150] failed to reconcile VM: unable to clone VM from template "bottlerocket-1.21": not found`,
+		},
+		{
+			check: "vCenter permission denied",
+			log:   `E1019 ServerFaultCode: Permission to perform this operation was denied.`,
+		},
+		{
+			check: "kube-vip leader election failure",
+			log:   `E1019 leaderelection.go:325] error retrieving resource lock kube-system/plndr-cp-lock: context deadline exceeded`,
+		},
+		{
+			check: "etcd quorum lost",
+			log:   `{"level":"warn","msg":"etcdserver: request timed out"}`,
+		},
+		{
+			check: "certificate expired",
+			log:   `x509: certificate has expired or is not yet valid: current time 2026-10-19T00:00:00Z is after 2026-10-18T00:00:00Z`,
+		},
+		{
+			check: "Flux source failure",
+			log:   `Reconciler error: failed to checkout and determine revision: unable to clone 'ssh://git@github.com/org/repo'`,
+		},
+	}
+	collectors := diagnostics.NewDefaultCollectorFactory().ManagementClusterCollectors()
+	collectors = append(collectors, diagnostics.NewDefaultCollectorFactory().DefaultCollectors()...)
+	analyzers := diagnostics.NewAnalyzerFactory().EksaLogTextAnalyzers(collectors)
+
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			g := NewWithT(t)
+			matched := false
+			for _, a := range analyzers {
+				if strings.Contains(a.TextAnalyze.CheckName, tt.check) && regexp.MustCompile(a.TextAnalyze.RegexPattern).MatchString(tt.log) {
+					matched = true
+				}
+			}
+			g.Expect(matched).To(BeTrue(), "no %s analyzer matched %q", tt.check, tt.log)
+		})
+	}
+}
+
+func TestDefaultAnalyzersNodeNetwork(t *testing.T) {
+	g := NewWithT(t)
+	var networkAnalyzer *diagnostics.Analyze
+	for _, a := range diagnostics.NewAnalyzerFactory().DefaultAnalyzers() {
+		if a.TextAnalyze != nil && a.TextAnalyze.CheckName == "node network plugin not ready" {
+			networkAnalyzer = a
+		}
+	}
+	g.Expect(networkAnalyzer).NotTo(BeNil())
+	g.Expect(networkAnalyzer.TextAnalyze.FileName).To(Equal("cluster-resources/nodes.json"))
+	g.Expect(regexp.MustCompile(networkAnalyzer.TextAnalyze.RegexPattern).MatchString(
+		`"message": "container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady message:Network plugin returns error: cni plugin not initialized"`,
+	)).To(BeTrue())
+}
+
+func TestEksaRegistryMirrorAnalyzers(t *testing.T) {
+	g := NewWithT(t)
+	mirror := &eksav1alpha1.RegistryMirrorConfiguration{
+		Endpoint: "harbor.local:443",
+		Mirrors: []eksav1alpha1.RegistryMirror{
+			{Registry: "public.ecr.aws"},
+			{Registry: "docker.io", Endpoint: "docker-mirror.local"},
+		},
+	}
+
+	analyzers := diagnostics.NewAnalyzerFactory().EksaRegistryMirrorAnalyzers(mirror)
+	g.Expect(analyzers).To(HaveLen(2))
+	g.Expect(analyzers[0].TextAnalyze.CheckName).To(Equal("image pull from registry mirror harbor.local:443"))
+	g.Expect(analyzers[0].TextAnalyze.FileName).To(Equal("cluster-resources/events/*.json"))
+	g.Expect(analyzers[1].TextAnalyze.CheckName).To(Equal("image pull from registry mirror docker-mirror.local"))
+
+	regex := regexp.MustCompile(analyzers[0].TextAnalyze.RegexPattern)
+	g.Expect(regex.MatchString(`"message": "Failed to pull image \"harbor.local:443/eks-anywhere/cilium:v1.9\": rpc error"`)).To(BeTrue())
+	g.Expect(regex.MatchString(`"message": "Failed to pull image \"harborXlocal:443/eks-anywhere/cilium:v1.9\": rpc error"`)).To(BeFalse())
+	g.Expect(regex.MatchString(`"message": "Failed to pull image \"public.ecr.aws/eks-anywhere/cilium:v1.9\": rpc error"`)).To(BeFalse())
+}
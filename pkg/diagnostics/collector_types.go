@@ -1,6 +1,7 @@
 package diagnostics
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -75,6 +76,7 @@ type run struct {
 	Image           string            `json:"image"`
 	Command         []string          `json:"command,omitempty"`
 	Args            []string          `json:"args,omitempty"`
+	Env             []corev1.EnvVar   `json:"env,omitempty"`
 	Timeout         string            `json:"timeout,omitempty"`
 	ImagePullPolicy string            `json:"imagePullPolicy,omitempty"`
 	ImagePullSecret *imagePullSecrets `json:"imagePullSecret,omitempty"`
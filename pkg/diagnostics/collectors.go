@@ -2,8 +2,11 @@ package diagnostics
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/constants"
 	"github.com/aws/eks-anywhere/pkg/providers"
@@ -11,12 +14,35 @@ import (
 
 type collectorFactory struct {
 	DiagnosticCollectorImage string
+	proxyEnv                 []corev1.EnvVar
 }
 
-func NewCollectorFactory(diagnosticCollectorImage string) *collectorFactory {
-	return &collectorFactory{
+type CollectorFactoryOpt func(*collectorFactory)
+
+// WithProxyEnv sets the proxy env vars in the pods run by the collectors
+func WithProxyEnv(env map[string]string) CollectorFactoryOpt {
+	return func(c *collectorFactory) {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		c.proxyEnv = make([]corev1.EnvVar, 0, len(env))
+		for _, k := range keys {
+			c.proxyEnv = append(c.proxyEnv, corev1.EnvVar{Name: k, Value: env[k]})
+		}
+	}
+}
+
+func NewCollectorFactory(diagnosticCollectorImage string, opts ...CollectorFactoryOpt) *collectorFactory {
+	c := &collectorFactory{
 		DiagnosticCollectorImage: diagnosticCollectorImage,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func NewDefaultCollectorFactory() *collectorFactory {
@@ -168,6 +194,12 @@ func (c *collectorFactory) managementClusterLogCollectors() []*Collect {
 				Name:      logpath(constants.CapiWebhookSystemNamespace),
 			},
 		},
+		{
+			Logs: &logs{
+				Namespace: constants.CapvSystemNamespace,
+				Name:      logpath(constants.CapvSystemNamespace),
+			},
+		},
 		{
 			Logs: &logs{
 				Namespace: constants.CertManagerNamespace,
@@ -186,6 +218,12 @@ func (c *collectorFactory) managementClusterLogCollectors() []*Collect {
 				Name:      logpath(constants.EtcdAdmControllerSystemNamespace),
 			},
 		},
+		{
+			Logs: &logs{
+				Namespace: constants.FluxSystemNamespace,
+				Name:      logpath(constants.FluxSystemNamespace),
+			},
+		},
 	}
 }
 
@@ -222,6 +260,7 @@ func (c *collectorFactory) crdCollector(crdType string) *Collect {
 			Image:     c.DiagnosticCollectorImage,
 			Command:   command,
 			Args:      args,
+			Env:       c.proxyEnv,
 		},
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,11 +25,14 @@ import (
 var diagnosticCollectorRbac []byte
 
 const (
-	troubleshootApiVersion      = "troubleshoot.sh/v1beta2"
-	generatedBundleNameFormat   = "%s-%s-bundle.yaml"
-	generatedAnalysisNameFormat = "%s-%s-analysis.yaml"
-	maxRetries                  = 5
-	backOffPeriod               = 5 * time.Second
+	troubleshootApiVersion       = "troubleshoot.sh/v1beta2"
+	generatedBundleNameFormat    = "%s-%s-bundle.yaml"
+	generatedAnalysisNameFormat  = "%s-%s-analysis.yaml"
+	generatedRedactorsNameFormat = "%s-%s-redactors.yaml"
+	customBundleName             = "custom"
+	maxReportedCredentials       = 20
+	maxRetries                   = 5
+	backOffPeriod                = 5 * time.Second
 )
 
 type EksaDiagnosticBundle struct {
@@ -43,6 +47,9 @@ type EksaDiagnosticBundle struct {
 	retrier          *retrier.Retrier
 	writer           filewriter.FileWriter
 	analysis         []*executables.SupportBundleAnalysis
+	redactor         *redactor
+	redact           bool
+	archivePath      string
 }
 
 func newDiagnosticBundleManagementCluster(af AnalyzerFactory, cf CollectorFactory, client BundleClient,
@@ -65,9 +72,11 @@ func newDiagnosticBundleManagementCluster(af AnalyzerFactory, cf CollectorFactor
 		kubeconfig:       kubeconfig,
 		retrier:          retrier.NewWithMaxRetries(maxRetries, backOffPeriod),
 		writer:           writer,
+		redactor:         newRedactor("bootstrap-cluster"),
+		redact:           true,
 	}
 
-	b.WithDefaultCollectors().WithDefaultAnalyzers().WithManagementCluster(true)
+	b.WithDefaultCollectors().WithDefaultAnalyzers().WithManagementCluster(true).WithLogTextAnalyzers()
 
 	err := b.WriteBundleConfig()
 	if err != nil {
@@ -77,7 +86,7 @@ func newDiagnosticBundleManagementCluster(af AnalyzerFactory, cf CollectorFactor
 	return b, nil
 }
 
-func newDiagnosticBundleFromSpec(af AnalyzerFactory, cf CollectorFactory, spec *cluster.Spec, provider providers.Provider,
+func newDiagnosticBundleFromSpec(af AnalyzerFactory, cf CollectorFactory, spec *cluster.Spec, machineConfigs []providers.MachineConfig,
 	client BundleClient, kubectl *executables.Kubectl, kubeconfig string, writer filewriter.FileWriter) (*EksaDiagnosticBundle, error) {
 	b := &EksaDiagnosticBundle{
 		bundle: &supportBundle{
@@ -98,14 +107,17 @@ func newDiagnosticBundleFromSpec(af AnalyzerFactory, cf CollectorFactory, spec *
 		kubectl:          kubectl,
 		retrier:          retrier.NewWithMaxRetries(maxRetries, backOffPeriod),
 		writer:           writer,
+		redactor:         newRedactor(spec.Name),
+		redact:           true,
 	}
 
 	b = b.
 		WithGitOpsConfig(spec.GitOpsConfig).
 		WithOidcConfig(spec.OIDCConfig).
 		WithExternalEtcd(spec.Spec.ExternalEtcdConfiguration).
+		WithRegistryMirror(spec.Spec.RegistryMirrorConfiguration).
 		WithDatacenterConfig(spec.Spec.DatacenterRef).
-		WithMachineConfigs(provider.MachineConfigs()).
+		WithMachineConfigs(machineConfigs).
 		WithManagementCluster(spec.IsSelfManaged()).
 		WithDefaultAnalyzers().
 		WithDefaultCollectors().
@@ -133,11 +145,13 @@ func newDiagnosticBundleDefault(af AnalyzerFactory, cf CollectorFactory) *EksaDi
 		},
 		analyzerFactory:  af,
 		collectorFactory: cf,
+		redactor:         newRedactor("default"),
+		redact:           true,
 	}
 	return b.WithDefaultAnalyzers().WithDefaultCollectors().WithManagementCluster(true)
 }
 
-func newDiagnosticBundleCustom(af AnalyzerFactory, cf CollectorFactory, client BundleClient, kubectl *executables.Kubectl, bundlePath string, kubeconfig string) *EksaDiagnosticBundle {
+func newDiagnosticBundleCustom(af AnalyzerFactory, cf CollectorFactory, client BundleClient, kubectl *executables.Kubectl, bundlePath string, kubeconfig string, writer filewriter.FileWriter) *EksaDiagnosticBundle {
 	return &EksaDiagnosticBundle{
 		bundlePath:       bundlePath,
 		analyzerFactory:  af,
@@ -146,19 +160,28 @@ func newDiagnosticBundleCustom(af AnalyzerFactory, cf CollectorFactory, client B
 		kubeconfig:       kubeconfig,
 		kubectl:          kubectl,
 		retrier:          retrier.NewWithMaxRetries(maxRetries, backOffPeriod),
+		writer:           writer,
+		redactor:         newRedactor(customBundleName),
+		redact:           true,
 	}
 }
 
 func (e *EksaDiagnosticBundle) CollectAndAnalyze(ctx context.Context, sinceTimeValue *time.Time) error {
 	e.createDiagnosticNamespaceAndRoles(ctx)
 
+	redactorsPath, err := e.writeRedactors()
+	if err != nil {
+		return err
+	}
+
 	logger.Info("⏳ Collecting support bundle from cluster, this can take a while", "cluster", e.clusterName(), "bundle", e.bundlePath, "since", sinceTimeValue, "kubeconfig", e.kubeconfig)
-	archivePath, err := e.client.Collect(ctx, e.bundlePath, sinceTimeValue, e.kubeconfig)
+	archivePath, err := e.client.Collect(ctx, e.bundlePath, sinceTimeValue, e.kubeconfig, redactorsPath, e.archivePath)
 	if err != nil {
 		return fmt.Errorf("failed to Collect support bundle: %v", err)
 	}
 
 	logger.Info("Support bundle archive created", "path", archivePath)
+	e.reportCredentials(archivePath)
 
 	logger.Info("Analyzing support bundle", "bundle", e.bundlePath, "archive", archivePath)
 	analysis, err := e.client.Analyze(ctx, e.bundlePath, archivePath)
@@ -177,15 +200,79 @@ func (e *EksaDiagnosticBundle) CollectAndAnalyze(ctx context.Context, sinceTimeV
 	return nil
 }
 
+// PrintBundleConfig prints the bundle config followed by its redactors, so they can be customized
 func (e *EksaDiagnosticBundle) PrintBundleConfig() error {
 	bundleYaml, err := yaml.Marshal(e.bundle)
 	if err != nil {
 		return fmt.Errorf("error outputting yaml: %v", err)
 	}
-	fmt.Println(string(bundleYaml))
+	redactorYaml, err := yaml.Marshal(e.redactor)
+	if err != nil {
+		return fmt.Errorf("error outputting yaml: %v", err)
+	}
+	fmt.Println(strings.TrimSuffix(string(bundleYaml), "\n") + v1alpha1.YamlSeparator + string(redactorYaml))
 	return nil
 }
 
+// writeRedactors writes the redactors used to collect the bundle and returns their path, or an empty path when
+// redaction is disabled. Redactor documents in a custom bundle config are added to the default redactors
+func (e *EksaDiagnosticBundle) writeRedactors() (path string, err error) {
+	if e.bundle == nil {
+		redacts, bundleConfig, err := splitBundleConfig(e.bundlePath)
+		if err != nil {
+			return "", err
+		}
+		if len(redacts) > 0 {
+			e.redactor.withRedacts(redacts)
+			timestamp := time.Now().Format(time.RFC3339)
+			e.bundlePath, err = e.writer.Write(fmt.Sprintf(generatedBundleNameFormat, e.clusterName(), timestamp), bundleConfig)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if !e.redact {
+		logger.Info("WARNING: support bundle redaction is disabled, the bundle can contain credentials")
+		return "", nil
+	}
+
+	redactorYaml, err := yaml.Marshal(e.redactor)
+	if err != nil {
+		return "", fmt.Errorf("error outputing yaml: %v", err)
+	}
+	timestamp := time.Now().Format(time.RFC3339)
+	path, err = e.writer.Write(fmt.Sprintf(generatedRedactorsNameFormat, e.clusterName(), timestamp), redactorYaml)
+	if err != nil {
+		return "", err
+	}
+	logger.V(3).Info("redactors written", "path", path)
+	return path, nil
+}
+
+// reportCredentials warns about the files in the archive that still look like they contain credentials.
+// The scan is best effort and it doesn't fail the collection
+func (e *EksaDiagnosticBundle) reportCredentials(archivePath string) {
+	findings, err := ScanArchive(archivePath)
+	if err != nil {
+		logger.Info("WARNING: failed to scan support bundle for credentials", "err", err)
+		return
+	}
+	if len(findings) == 0 {
+		logger.V(3).Info("No credentials found in support bundle", "archive", archivePath)
+		return
+	}
+
+	logger.Info("WARNING: the support bundle may contain credentials, review it before sharing it", "archive", archivePath, "findings", len(findings))
+	for i, f := range findings {
+		if i == maxReportedCredentials {
+			logger.Info("More possible credentials found in support bundle", "count", len(findings)-maxReportedCredentials)
+			break
+		}
+		logger.Info("Possible credential in support bundle", "type", f.Type, "file", f.File, "line", f.Line)
+	}
+}
+
 func (e *EksaDiagnosticBundle) WriteBundleConfig() error {
 	bundleYaml, err := yaml.Marshal(e.bundle)
 	if err != nil {
@@ -233,6 +320,29 @@ func (e *EksaDiagnosticBundle) WriteAnalysisToFile() (path string, err error) {
 	return analysisPath, nil
 }
 
+// FailedAnalysis returns the analyzers that failed in the last CollectAndAnalyze
+func (e *EksaDiagnosticBundle) FailedAnalysis() []*executables.SupportBundleAnalysis {
+	var failed []*executables.SupportBundleAnalysis
+	for _, a := range e.analysis {
+		if a.IsFail {
+			failed = append(failed, a)
+		}
+	}
+	return failed
+}
+
+// WithRedaction enables or disables the redaction of the bundle. It's enabled by default
+func (e *EksaDiagnosticBundle) WithRedaction(enabled bool) *EksaDiagnosticBundle {
+	e.redact = enabled
+	return e
+}
+
+// WithArchivePath sets the path of the support bundle archive. By default, the archive is named after the collection time
+func (e *EksaDiagnosticBundle) WithArchivePath(path string) *EksaDiagnosticBundle {
+	e.archivePath = path
+	return e
+}
+
 func (e *EksaDiagnosticBundle) WithDefaultCollectors() *EksaDiagnosticBundle {
 	e.bundle.Spec.Collectors = append(e.bundle.Spec.Collectors, e.collectorFactory.DefaultCollectors()...)
 	return e
@@ -277,6 +387,13 @@ func (e *EksaDiagnosticBundle) WithGitOpsConfig(config *v1alpha1.GitOpsConfig) *
 	return e
 }
 
+func (e *EksaDiagnosticBundle) WithRegistryMirror(config *v1alpha1.RegistryMirrorConfiguration) *EksaDiagnosticBundle {
+	if config != nil {
+		e.bundle.Spec.Analyzers = append(e.bundle.Spec.Analyzers, e.analyzerFactory.EksaRegistryMirrorAnalyzers(config)...)
+	}
+	return e
+}
+
 func (e *EksaDiagnosticBundle) WithMachineConfigs(configs []providers.MachineConfig) *EksaDiagnosticBundle {
 	e.bundle.Spec.Collectors = append(e.bundle.Spec.Collectors, e.collectorFactory.EksaHostCollectors(configs)...)
 	return e
@@ -371,5 +488,8 @@ func ParseTimeOptions(since string, sinceTime string) (*time.Time, error) {
 }
 
 func (e *EksaDiagnosticBundle) clusterName() string {
+	if e.bundle == nil {
+		return customBundleName
+	}
 	return e.bundle.Name
 }
@@ -3,6 +3,7 @@ package diagnostics_test
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	supportMocks "github.com/aws/eks-anywhere/pkg/diagnostics/interfaces/mocks"
 	"github.com/aws/eks-anywhere/pkg/executables"
 	mockexecutables "github.com/aws/eks-anywhere/pkg/executables/mocks"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
 	"github.com/aws/eks-anywhere/pkg/filewriter/mocks"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	providerMocks "github.com/aws/eks-anywhere/pkg/providers/mocks"
@@ -286,6 +288,10 @@ func TestBundleFromSpecComplete(t *testing.T) {
 		Name: "testRef",
 	}
 
+	spec.Cluster.Spec.RegistryMirrorConfiguration = &eksav1alpha1.RegistryMirrorConfiguration{
+		Endpoint: "harbor.local",
+	}
+
 	t.Run(t.Name(), func(t *testing.T) {
 		ctx := context.Background()
 		kubeconfig := "testcluster.kubeconfig"
@@ -295,6 +301,7 @@ func TestBundleFromSpecComplete(t *testing.T) {
 
 		a := givenMockAnalyzerFactory(t)
 		a.EXPECT().EksaExternalEtcdAnalyzers().Return(nil)
+		a.EXPECT().EksaRegistryMirrorAnalyzers(spec.Cluster.Spec.RegistryMirrorConfiguration).Return(nil)
 		a.EXPECT().DataCenterConfigAnalyzers(spec.Cluster.Spec.DatacenterRef).Return(nil)
 		a.EXPECT().DefaultAnalyzers().Return(nil)
 		a.EXPECT().EksaLogTextAnalyzers(gomock.Any()).Return(nil)
@@ -306,7 +313,7 @@ func TestBundleFromSpecComplete(t *testing.T) {
 		c.EXPECT().ManagementClusterCollectors().Return(nil)
 
 		w := givenWriter(t)
-		w.EXPECT().Write(gomock.Any(), gomock.Any()).Times(2)
+		w.EXPECT().Write(gomock.Any(), gomock.Any()).Times(3)
 
 		k, e := givenKubectl(t)
 		expectedParam := []string{"create", "namespace", constants.EksaDiagnosticsNamespace, "--kubeconfig", kubeconfig}
@@ -318,7 +325,7 @@ func TestBundleFromSpecComplete(t *testing.T) {
 		expectedParam = []string{"apply", "-f", "-", "--kubeconfig", kubeconfig}
 		e.EXPECT().ExecuteWithStdin(ctx, gomock.Any(), gomock.Eq(expectedParam)).Return(bytes.Buffer{}, nil)
 
-		expectedParam = []string{"delete", "-f", "-", "--kubeconfig", kubeconfig}
+		expectedParam = []string{"delete", "-f", "-", "--ignore-not-found", "--kubeconfig", kubeconfig}
 		e.EXPECT().ExecuteWithStdin(ctx, gomock.Any(), gomock.Eq(expectedParam)).Return(bytes.Buffer{}, nil)
 
 		returnAnalysis := []*executables.SupportBundleAnalysis{
@@ -330,11 +337,16 @@ func TestBundleFromSpecComplete(t *testing.T) {
 				Message: "",
 				Uri:     "",
 			},
+			{
+				Title:   "log analysis:: etcd quorum lost",
+				IsFail:  true,
+				Message: "etcd lost quorum",
+			},
 		}
 
 		tc := givenTroubleshootClient(t)
 		mockArchivePath := "/tmp/archive/path"
-		tc.EXPECT().Collect(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockArchivePath, nil)
+		tc.EXPECT().Collect(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "").Return(mockArchivePath, nil)
 		tc.EXPECT().Analyze(ctx, gomock.Any(), mockArchivePath).Return(returnAnalysis, nil)
 
 		opts := diagnostics.EksaDiagnosticBundleFactoryOpts{
@@ -359,7 +371,97 @@ func TestBundleFromSpecComplete(t *testing.T) {
 			t.Errorf("CollectAndAnalyze() error = %v, wantErr nil", err)
 			return
 		}
+		if failed := b.FailedAnalysis(); len(failed) != 1 || failed[0] != returnAnalysis[1] {
+			t.Errorf("FailedAnalysis() = %v, want %v", failed, returnAnalysis[1:])
+		}
+	})
+}
+
+func TestCustomBundleCollectWithRedactors(t *testing.T) {
+	ctx := context.Background()
+	kubeconfig := "testcluster.kubeconfig"
+	bundlePath := "testdata/support-bundle-redactors.yaml"
+
+	w := givenWriter(t)
+	var bundleConfig, redactorsConfig string
+	w.EXPECT().Write(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, content []byte, _ ...filewriter.FileOptionsFunc) (string, error) {
+		bundleConfig = string(content)
+		return "generated/" + name, nil
+	})
+	w.EXPECT().Write(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, content []byte, _ ...filewriter.FileOptionsFunc) (string, error) {
+		redactorsConfig = string(content)
+		return "generated/redactors.yaml", nil
+	})
+	w.EXPECT().Write(gomock.Any(), gomock.Any()).Return("generated/analysis.yaml", nil)
+
+	k, e := givenKubectl(t)
+	e.EXPECT().Execute(ctx, gomock.Any()).Return(bytes.Buffer{}, nil).Times(2)
+	e.EXPECT().ExecuteWithStdin(ctx, gomock.Any(), gomock.Any()).Return(bytes.Buffer{}, nil).Times(2)
+
+	tc := givenTroubleshootClient(t)
+	mockArchivePath := "/tmp/archive/path"
+	tc.EXPECT().Collect(ctx, gomock.Any(), gomock.Any(), kubeconfig, "generated/redactors.yaml", "").DoAndReturn(
+		func(_ context.Context, path string, _ *time.Time, _, _, _ string) (string, error) {
+			if !strings.HasPrefix(path, "generated/custom-") {
+				t.Errorf("Collect() bundle path = %s, want the bundle config without redactors", path)
+			}
+			return mockArchivePath, nil
+		},
+	)
+	tc.EXPECT().Analyze(ctx, gomock.Any(), mockArchivePath).Return([]*executables.SupportBundleAnalysis{}, nil)
+
+	f := diagnostics.NewFactory(diagnostics.EksaDiagnosticBundleFactoryOpts{
+		AnalyzerFactory:  givenMockAnalyzerFactory(t),
+		CollectorFactory: givenMockCollectorsFactory(t),
+		Writer:           w,
+		Kubectl:          k,
+		Client:           tc,
+	})
+	b := f.DiagnosticBundleCustom(kubeconfig, bundlePath)
+	if err := b.CollectAndAnalyze(ctx, &time.Time{}); err != nil {
+		t.Fatalf("CollectAndAnalyze() error = %v, wantErr nil", err)
+	}
+
+	if strings.Contains(bundleConfig, "Redactor") || !strings.Contains(bundleConfig, "kind: SupportBundle") {
+		t.Errorf("bundle config = %s, want only the SupportBundle", bundleConfig)
+	}
+	for _, want := range []string{"name: eksa vsphere credentials", "- my-client-id", "name: site hostnames", "- items.*.metadata.annotations"} {
+		if !strings.Contains(redactorsConfig, want) {
+			t.Errorf("redactors = %s, want to contain %s", redactorsConfig, want)
+		}
+	}
+	if strings.Contains(redactorsConfig, "oidc-client-id") {
+		t.Errorf("redactors = %s, want the default oidc redactor replaced", redactorsConfig)
+	}
+}
+
+func TestBundleCollectWithoutRedaction(t *testing.T) {
+	ctx := context.Background()
+	kubeconfig := "testcluster.kubeconfig"
+
+	w := givenWriter(t)
+	w.EXPECT().Write(gomock.Any(), gomock.Any()).Return("generated/analysis.yaml", nil)
+
+	k, e := givenKubectl(t)
+	e.EXPECT().Execute(ctx, gomock.Any()).Return(bytes.Buffer{}, nil).Times(2)
+	e.EXPECT().ExecuteWithStdin(ctx, gomock.Any(), gomock.Any()).Return(bytes.Buffer{}, nil).Times(2)
+
+	tc := givenTroubleshootClient(t)
+	mockArchivePath := "/tmp/archive/path"
+	tc.EXPECT().Collect(ctx, "testdata/support-bundle-test1.yaml", gomock.Any(), kubeconfig, "", "").Return(mockArchivePath, nil)
+	tc.EXPECT().Analyze(ctx, gomock.Any(), mockArchivePath).Return([]*executables.SupportBundleAnalysis{}, nil)
+
+	f := diagnostics.NewFactory(diagnostics.EksaDiagnosticBundleFactoryOpts{
+		AnalyzerFactory:  givenMockAnalyzerFactory(t),
+		CollectorFactory: givenMockCollectorsFactory(t),
+		Writer:           w,
+		Kubectl:          k,
+		Client:           tc,
 	})
+	b := f.DiagnosticBundleCustom(kubeconfig, "testdata/support-bundle-test1.yaml").WithRedaction(false)
+	if err := b.CollectAndAnalyze(ctx, &time.Time{}); err != nil {
+		t.Fatalf("CollectAndAnalyze() error = %v, wantErr nil", err)
+	}
 }
 
 func TestGenerateCustomBundle(t *testing.T) {
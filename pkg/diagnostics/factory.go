@@ -48,7 +48,13 @@ func (f *eksaDiagnosticBundleFactory) DiagnosticBundleManagementCluster(kubeconf
 }
 
 func (f *eksaDiagnosticBundleFactory) DiagnosticBundleFromSpec(spec *cluster.Spec, provider providers.Provider, kubeconfig string) (DiagnosticBundle, error) {
-	return newDiagnosticBundleFromSpec(f.analyzerFactory, f.collectorFactory, spec, provider, f.client, f.kubectl, kubeconfig, f.writer)
+	return f.DiagnosticBundleForCluster(spec, provider.MachineConfigs(), kubeconfig)
+}
+
+// DiagnosticBundleForCluster returns the bundle for a cluster whose machine configs are already known, like the
+// workload clusters read from a management cluster
+func (f *eksaDiagnosticBundleFactory) DiagnosticBundleForCluster(spec *cluster.Spec, machineConfigs []providers.MachineConfig, kubeconfig string) (DiagnosticBundle, error) {
+	return newDiagnosticBundleFromSpec(f.analyzerFactory, f.collectorFactory, spec, machineConfigs, f.client, f.kubectl, kubeconfig, f.writer)
 }
 
 func (f *eksaDiagnosticBundleFactory) DiagnosticBundleDefault() DiagnosticBundle {
@@ -56,5 +62,5 @@ func (f *eksaDiagnosticBundleFactory) DiagnosticBundleDefault() DiagnosticBundle
 }
 
 func (f *eksaDiagnosticBundleFactory) DiagnosticBundleCustom(kubeconfig string, bundlePath string) DiagnosticBundle {
-	return newDiagnosticBundleCustom(f.analyzerFactory, f.collectorFactory, f.client, f.kubectl, bundlePath, kubeconfig)
+	return newDiagnosticBundleCustom(f.analyzerFactory, f.collectorFactory, f.client, f.kubectl, bundlePath, kubeconfig, f.writer)
 }
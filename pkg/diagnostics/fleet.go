@@ -0,0 +1,333 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const (
+	// DefaultFleetConcurrency is the number of clusters a fleet bundle collects from at the same time by default
+	DefaultFleetConcurrency = 4
+
+	fleetArchiveNameFormat      = "%s-fleet-support-bundle-%s.tar.gz"
+	fleetClusterArchiveFormat   = "%s_%s-support-bundle-%s.tar.gz"
+	fleetKubeconfigNameFormat   = "%s_%s-eks-a-cluster.kubeconfig"
+	fleetAnalysisFileName       = "fleet-analysis.yaml"
+	fleetArchiveTimestampLayout = "2006-01-02T15_04_05"
+	kubeconfigSecretNameFormat  = "%s-kubeconfig"
+	kubeconfigSecretKey         = "value"
+)
+
+type FleetBundleOpts struct {
+	BundleFactory     DiagnosticBundleFactory
+	Client            FleetClient
+	Writer            filewriter.FileWriter
+	ManagementCluster *types.Cluster
+	Concurrency       int
+	Redact            bool
+}
+
+// FleetBundle collects a support bundle from every EKS-A cluster in a management cluster
+// and merges them in a single archive with a summary of the analysis of each cluster
+type FleetBundle struct {
+	bundleFactory     DiagnosticBundleFactory
+	client            FleetClient
+	writer            filewriter.FileWriter
+	managementCluster *types.Cluster
+	concurrency       int
+	redact            bool
+	analysis          *FleetAnalysis
+}
+
+// FleetAnalysis is the summary of the collection and analysis of the clusters in a management cluster
+type FleetAnalysis struct {
+	ManagementCluster string                  `json:"managementCluster"`
+	Clusters          []*FleetClusterAnalysis `json:"clusters"`
+}
+
+type FleetClusterAnalysis struct {
+	Name         string                               `json:"name"`
+	Namespace    string                               `json:"namespace"`
+	Collected    bool                                 `json:"collected"`
+	Error        string                               `json:"error,omitempty"`
+	FailedChecks []*executables.SupportBundleAnalysis `json:"failedChecks,omitempty"`
+	archivePath  string
+}
+
+func NewFleetBundle(opts FleetBundleOpts) *FleetBundle {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultFleetConcurrency
+	}
+	return &FleetBundle{
+		bundleFactory:     opts.BundleFactory,
+		client:            opts.Client,
+		writer:            opts.Writer,
+		managementCluster: opts.ManagementCluster,
+		concurrency:       concurrency,
+		redact:            opts.Redact,
+	}
+}
+
+// CollectAndAnalyze collects and analyzes a support bundle from each cluster in the management cluster, at most
+// concurrency at a time, and returns the path of the merged archive. A cluster failing doesn't stop the collection
+// from the rest, its error is recorded in the analysis
+func (f *FleetBundle) CollectAndAnalyze(ctx context.Context, sinceTimeValue *time.Time) (archivePath string, err error) {
+	clusters, err := f.client.GetEksaClusters(ctx, f.managementCluster)
+	if err != nil {
+		return "", fmt.Errorf("error getting clusters from management cluster: %v", err)
+	}
+	if len(clusters) == 0 {
+		return "", fmt.Errorf("no EKS-A clusters found in management cluster %s", f.managementCluster.Name)
+	}
+
+	timestamp := time.Now().Format(fleetArchiveTimestampLayout)
+	f.analysis = &FleetAnalysis{
+		ManagementCluster: f.managementCluster.Name,
+		Clusters:          make([]*FleetClusterAnalysis, len(clusters)),
+	}
+
+	logger.Info("Collecting support bundles from clusters", "clusters", len(clusters), "concurrency", f.concurrency)
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(i int, c *v1alpha1.Cluster) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			f.analysis.Clusters[i] = f.collectCluster(ctx, c, sinceTimeValue, timestamp)
+		}(i, &clusters[i])
+	}
+	wg.Wait()
+
+	archivePath, err = f.mergeArchives(timestamp)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Fleet support bundle archive created", "path", archivePath)
+	return archivePath, nil
+}
+
+func (f *FleetBundle) collectCluster(ctx context.Context, c *v1alpha1.Cluster, sinceTimeValue *time.Time, timestamp string) *FleetClusterAnalysis {
+	result := &FleetClusterAnalysis{
+		Name:        c.Name,
+		Namespace:   c.Namespace,
+		archivePath: filepath.Join(f.writer.Dir(), fmt.Sprintf(fleetClusterArchiveFormat, c.Namespace, c.Name, timestamp)),
+	}
+
+	bundle, err := f.clusterBundle(ctx, c)
+	if err != nil {
+		logger.Info("WARNING: failed to build support bundle for cluster", "cluster", c.Name, "namespace", c.Namespace, "err", err)
+		result.Error = err.Error()
+		return result
+	}
+	bundle.WithRedaction(f.redact)
+	bundle.WithArchivePath(result.archivePath)
+
+	err = bundle.CollectAndAnalyze(ctx, sinceTimeValue)
+	if _, statErr := os.Stat(result.archivePath); statErr == nil {
+		result.Collected = true
+	}
+	if err != nil {
+		logger.Info("WARNING: failed to collect support bundle from cluster", "cluster", c.Name, "namespace", c.Namespace, "err", err)
+		result.Error = err.Error()
+		return result
+	}
+	result.FailedChecks = bundle.FailedAnalysis()
+	return result
+}
+
+// clusterBundle builds the bundle for a cluster from its spec in the management cluster
+func (f *FleetBundle) clusterBundle(ctx context.Context, c *v1alpha1.Cluster) (DiagnosticBundle, error) {
+	kubeconfig, err := f.clusterKubeconfig(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := cluster.BuildSpecForCluster(ctx, c, f.bundlesFetcher(), f.gitOpsFetcher())
+	if err != nil {
+		return nil, fmt.Errorf("error building cluster spec: %v", err)
+	}
+
+	machineConfigs, err := f.machineConfigs(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.bundleFactory.DiagnosticBundleForCluster(spec, machineConfigs, kubeconfig)
+}
+
+// clusterKubeconfig returns the management cluster kubeconfig for self-managed clusters. For workload clusters,
+// it writes the kubeconfig in the secret the management cluster keeps for them, in a file named after the cluster
+// namespace and name, which can't contain "_", so clusters with the same name in different namespaces don't collide
+func (f *FleetBundle) clusterKubeconfig(ctx context.Context, c *v1alpha1.Cluster) (string, error) {
+	if c.IsSelfManaged() {
+		return f.managementCluster.KubeconfigFile, nil
+	}
+
+	secret, err := f.client.GetSecretFromNamespace(ctx, f.managementCluster.KubeconfigFile, fmt.Sprintf(kubeconfigSecretNameFormat, c.Name), constants.EksaSystemNamespace)
+	if err != nil {
+		return "", fmt.Errorf("error getting kubeconfig for cluster: %v", err)
+	}
+	kubeconfig, ok := secret.Data[kubeconfigSecretKey]
+	if !ok || len(kubeconfig) == 0 {
+		return "", fmt.Errorf("kubeconfig secret for cluster %s is empty", c.Name)
+	}
+
+	path, err := f.writer.Write(fmt.Sprintf(fleetKubeconfigNameFormat, c.Namespace, c.Name), kubeconfig, filewriter.Permission0600)
+	if err != nil {
+		return "", fmt.Errorf("error writing kubeconfig for cluster: %v", err)
+	}
+	return path, nil
+}
+
+// machineConfigs returns the vSphere machine configs of the cluster, used to pick the host collectors.
+// Other providers don't have host collectors
+func (f *FleetBundle) machineConfigs(ctx context.Context, c *v1alpha1.Cluster) ([]providers.MachineConfig, error) {
+	var configs []providers.MachineConfig
+	for _, ref := range c.MachineConfigRefs() {
+		if ref.Kind != v1alpha1.VSphereMachineConfigKind {
+			continue
+		}
+		config, err := f.client.GetEksaVSphereMachineConfig(ctx, ref.Name, f.managementCluster.KubeconfigFile, c.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error getting machine config %s: %v", ref.Name, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func (f *FleetBundle) bundlesFetcher() cluster.BundlesFetch {
+	return func(ctx context.Context, name, namespace string) (*releasev1alpha1.Bundles, error) {
+		return f.client.GetBundles(ctx, f.managementCluster.KubeconfigFile, name, namespace)
+	}
+}
+
+func (f *FleetBundle) gitOpsFetcher() cluster.GitOpsFetch {
+	return func(ctx context.Context, name, namespace string) (*v1alpha1.GitOpsConfig, error) {
+		return f.client.GetEksaGitOpsConfig(ctx, name, f.managementCluster.KubeconfigFile, namespace)
+	}
+}
+
+// mergeArchives writes the archive of each cluster under a <namespace>/<name> folder and the fleet analysis
+// in a single archive, removing the cluster archives
+func (f *FleetBundle) mergeArchives(timestamp string) (path string, err error) {
+	analysisYaml, err := yaml.Marshal(f.analysis)
+	if err != nil {
+		return "", fmt.Errorf("error outputing yaml: %v", err)
+	}
+
+	path = filepath.Join(f.writer.Dir(), fmt.Sprintf(fleetArchiveNameFormat, f.managementCluster.Name, timestamp))
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error creating fleet support bundle archive: %v", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	archive := tar.NewWriter(gz)
+	header := &tar.Header{
+		Name:    fleetAnalysisFileName,
+		Mode:    0o644,
+		Size:    int64(len(analysisYaml)),
+		ModTime: time.Now(),
+	}
+	if err = archive.WriteHeader(header); err != nil {
+		return "", fmt.Errorf("error writing fleet support bundle archive: %v", err)
+	}
+	if _, err = archive.Write(analysisYaml); err != nil {
+		return "", fmt.Errorf("error writing fleet support bundle archive: %v", err)
+	}
+
+	for _, c := range f.analysis.Clusters {
+		if !c.Collected {
+			continue
+		}
+		if err = copyArchive(archive, c.archivePath, filepath.Join(c.Namespace, c.Name)); err != nil {
+			return "", fmt.Errorf("error adding support bundle of cluster %s/%s to fleet archive: %v", c.Namespace, c.Name, err)
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		return "", fmt.Errorf("error writing fleet support bundle archive: %v", err)
+	}
+	if err = gz.Close(); err != nil {
+		return "", fmt.Errorf("error writing fleet support bundle archive: %v", err)
+	}
+
+	for _, c := range f.analysis.Clusters {
+		if c.Collected {
+			if err = os.Remove(c.archivePath); err != nil {
+				logger.V(3).Info("failed to remove cluster support bundle archive", "path", c.archivePath, "err", err)
+			}
+		}
+	}
+	return path, nil
+}
+
+// copyArchive copies the entries of the gzipped tar in archivePath to dst, under the folder prefix
+func copyArchive(dst *tar.Writer, archivePath, prefix string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	src := tar.NewReader(gz)
+	for {
+		header, err := src.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, header.Name))
+		if err = dst.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err = io.Copy(dst, src); err != nil {
+			return err
+		}
+	}
+}
+
+// PrintAnalysis prints the fleet analysis of the last CollectAndAnalyze
+func (f *FleetBundle) PrintAnalysis() error {
+	if f.analysis == nil {
+		return nil
+	}
+	analysis, err := yaml.Marshal(f.analysis)
+	if err != nil {
+		return fmt.Errorf("error outputing yaml: %v", err)
+	}
+	fmt.Println(string(analysis))
+	return nil
+}
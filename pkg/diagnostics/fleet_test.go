@@ -0,0 +1,226 @@
+package diagnostics_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/diagnostics"
+	supportMocks "github.com/aws/eks-anywhere/pkg/diagnostics/interfaces/mocks"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/filewriter"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+type fleetTest struct {
+	*WithT
+	ctx        context.Context
+	client     *supportMocks.MockFleetClient
+	factory    *supportMocks.MockDiagnosticBundleFactory
+	writer     filewriter.FileWriter
+	management *types.Cluster
+	fleet      *diagnostics.FleetBundle
+}
+
+func newFleetTest(t *testing.T) *fleetTest {
+	ctrl := gomock.NewController(t)
+	writer, err := filewriter.NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed creating writer: %v", err)
+	}
+	tt := &fleetTest{
+		WithT:      NewWithT(t),
+		ctx:        context.Background(),
+		client:     supportMocks.NewMockFleetClient(ctrl),
+		factory:    supportMocks.NewMockDiagnosticBundleFactory(ctrl),
+		writer:     writer,
+		management: &types.Cluster{Name: "mgmt", KubeconfigFile: "mgmt/mgmt-eks-a-cluster.kubeconfig"},
+	}
+	tt.fleet = diagnostics.NewFleetBundle(diagnostics.FleetBundleOpts{
+		BundleFactory:     tt.factory,
+		Client:            tt.client,
+		Writer:            writer,
+		ManagementCluster: tt.management,
+		Concurrency:       2,
+		Redact:            true,
+	})
+	return tt
+}
+
+func fleetCluster(name, managedBy string) eksav1alpha1.Cluster {
+	return eksav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: eksav1alpha1.ClusterSpec{
+			KubernetesVersion: eksav1alpha1.Kube120,
+			ControlPlaneConfiguration: eksav1alpha1.ControlPlaneConfiguration{
+				MachineGroupRef: &eksav1alpha1.Ref{Kind: eksav1alpha1.VSphereMachineConfigKind, Name: name + "-cp"},
+			},
+			DatacenterRef:     eksav1alpha1.Ref{Kind: eksav1alpha1.VSphereDatacenterKind, Name: name},
+			ManagementCluster: eksav1alpha1.ManagementCluster{Name: managedBy},
+		},
+	}
+}
+
+// expectCollect expects a bundle for the cluster that writes an archive with a single file when collected
+func (tt *fleetTest) expectCollect(t *testing.T, namespace, name, kubeconfig string, failed []*executables.SupportBundleAnalysis) {
+	tt.client.EXPECT().GetBundles(tt.ctx, tt.management.KubeconfigFile, name, namespace).Return(test.Bundles(t), nil)
+	machineConfig := &eksav1alpha1.VSphereMachineConfig{Spec: eksav1alpha1.VSphereMachineConfigSpec{OSFamily: eksav1alpha1.Bottlerocket}}
+	tt.client.EXPECT().GetEksaVSphereMachineConfig(tt.ctx, name+"-cp", tt.management.KubeconfigFile, namespace).Return(machineConfig, nil)
+
+	bundle := supportMocks.NewMockDiagnosticBundle(gomock.NewController(t))
+	tt.factory.EXPECT().DiagnosticBundleForCluster(gomock.Any(), gomock.Len(1), kubeconfig).Return(bundle, nil)
+	bundle.EXPECT().WithRedaction(true)
+	var archivePath string
+	bundle.EXPECT().WithArchivePath(gomock.Any()).Do(func(path string) { archivePath = path })
+	bundle.EXPECT().CollectAndAnalyze(tt.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, _ *time.Time) error {
+		content, err := ioutil.ReadFile(writeArchive(t, map[string]string{"logs/kube-system/kube-vip/kube-vip.log": namespace + "/" + name}))
+		if err != nil {
+			t.Fatalf("failed reading archive: %v", err)
+		}
+		return ioutil.WriteFile(archivePath, content, 0o600)
+	})
+	bundle.EXPECT().FailedAnalysis().Return(failed)
+}
+
+func readFleetArchive(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed opening fleet archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed reading fleet archive: %v", err)
+	}
+	files := map[string]string{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("failed reading fleet archive: %v", err)
+		}
+		content, err := ioutil.ReadAll(archive)
+		if err != nil {
+			t.Fatalf("failed reading fleet archive: %v", err)
+		}
+		files[header.Name] = string(content)
+	}
+}
+
+func TestFleetBundleCollectAndAnalyze(t *testing.T) {
+	tt := newFleetTest(t)
+	clusters := []eksav1alpha1.Cluster{fleetCluster("mgmt", ""), fleetCluster("w01", "mgmt"), fleetCluster("w02", "mgmt")}
+	tt.client.EXPECT().GetEksaClusters(tt.ctx, tt.management).Return(clusters, nil)
+
+	tt.expectCollect(t, "default", "mgmt", tt.management.KubeconfigFile, nil)
+
+	tt.client.EXPECT().GetSecretFromNamespace(tt.ctx, tt.management.KubeconfigFile, "w01-kubeconfig", constants.EksaSystemNamespace).
+		Return(&corev1.Secret{Data: map[string][]byte{"value": []byte("w01 kubeconfig")}}, nil)
+	failed := []*executables.SupportBundleAnalysis{{Title: "Etcd Health", IsFail: true, Message: "etcd is unhealthy"}}
+	tt.expectCollect(t, "default", "w01", tt.writer.Dir()+"/generated/default_w01-eks-a-cluster.kubeconfig", failed)
+
+	tt.client.EXPECT().GetSecretFromNamespace(tt.ctx, tt.management.KubeconfigFile, "w02-kubeconfig", constants.EksaSystemNamespace).
+		Return(nil, errors.New("secret not found"))
+
+	archivePath, err := tt.fleet.CollectAndAnalyze(tt.ctx, &time.Time{})
+	tt.Expect(err).To(BeNil())
+	tt.Expect(archivePath).To(HavePrefix(tt.writer.Dir() + "/mgmt-fleet-support-bundle-"))
+
+	kubeconfig, err := ioutil.ReadFile(tt.writer.Dir() + "/generated/default_w01-eks-a-cluster.kubeconfig")
+	tt.Expect(err).To(BeNil())
+	tt.Expect(string(kubeconfig)).To(Equal("w01 kubeconfig"))
+
+	files := readFleetArchive(t, archivePath)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tt.Expect(names).To(Equal([]string{
+		"default/mgmt/logs/kube-system/kube-vip/kube-vip.log",
+		"default/w01/logs/kube-system/kube-vip/kube-vip.log",
+		"fleet-analysis.yaml",
+	}))
+	tt.Expect(files["default/w01/logs/kube-system/kube-vip/kube-vip.log"]).To(Equal("default/w01"))
+
+	tt.Expect(files["fleet-analysis.yaml"]).To(Equal(`clusters:
+- collected: true
+  name: mgmt
+  namespace: default
+- collected: true
+  failedChecks:
+  - URI: ""
+    isFail: true
+    isPass: false
+    isWarn: false
+    message: etcd is unhealthy
+    title: Etcd Health
+  name: w01
+  namespace: default
+- collected: false
+  error: 'error getting kubeconfig for cluster: secret not found'
+  name: w02
+  namespace: default
+managementCluster: mgmt
+`))
+
+	leftovers, err := ioutil.ReadDir(tt.writer.Dir())
+	tt.Expect(err).To(BeNil())
+	for _, f := range leftovers {
+		tt.Expect(strings.Contains(f.Name(), "-support-bundle-") && !strings.Contains(f.Name(), "fleet")).To(BeFalse(), "cluster archive %s not removed", f.Name())
+	}
+}
+
+func TestFleetBundleCollectAndAnalyzeSameNameInNamespaces(t *testing.T) {
+	tt := newFleetTest(t)
+	teamA, teamB := fleetCluster("w01", "mgmt"), fleetCluster("w01", "mgmt")
+	teamA.Namespace, teamB.Namespace = "team-a", "team-b"
+	tt.client.EXPECT().GetEksaClusters(tt.ctx, tt.management).Return([]eksav1alpha1.Cluster{teamA, teamB}, nil)
+
+	tt.client.EXPECT().GetSecretFromNamespace(tt.ctx, tt.management.KubeconfigFile, "w01-kubeconfig", constants.EksaSystemNamespace).
+		Return(&corev1.Secret{Data: map[string][]byte{"value": []byte("w01 kubeconfig")}}, nil).Times(2)
+	tt.expectCollect(t, "team-a", "w01", tt.writer.Dir()+"/generated/team-a_w01-eks-a-cluster.kubeconfig", nil)
+	tt.expectCollect(t, "team-b", "w01", tt.writer.Dir()+"/generated/team-b_w01-eks-a-cluster.kubeconfig", nil)
+
+	archivePath, err := tt.fleet.CollectAndAnalyze(tt.ctx, &time.Time{})
+	tt.Expect(err).To(BeNil())
+
+	files := readFleetArchive(t, archivePath)
+	tt.Expect(files).To(HaveKeyWithValue("team-a/w01/logs/kube-system/kube-vip/kube-vip.log", "team-a/w01"))
+	tt.Expect(files).To(HaveKeyWithValue("team-b/w01/logs/kube-system/kube-vip/kube-vip.log", "team-b/w01"))
+}
+
+func TestFleetBundleCollectAndAnalyzeNoClusters(t *testing.T) {
+	tt := newFleetTest(t)
+	tt.client.EXPECT().GetEksaClusters(tt.ctx, tt.management).Return(nil, nil)
+
+	_, err := tt.fleet.CollectAndAnalyze(tt.ctx, &time.Time{})
+	tt.Expect(err).To(MatchError("no EKS-A clusters found in management cluster mgmt"))
+}
+
+func TestFleetBundleCollectAndAnalyzeGetClustersError(t *testing.T) {
+	tt := newFleetTest(t)
+	tt.client.EXPECT().GetEksaClusters(tt.ctx, tt.management).Return(nil, errors.New("connection refused"))
+
+	_, err := tt.fleet.CollectAndAnalyze(tt.ctx, &time.Time{})
+	tt.Expect(err).To(MatchError("error getting clusters from management cluster: connection refused"))
+}
@@ -4,20 +4,34 @@ import (
 	"context"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/executables"
 	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
 type BundleClient interface {
-	Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig string) (archivePath string, err error)
+	Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig, redactorsPath, archivePath string) (string, error)
 	Analyze(ctx context.Context, bundleSpecPath string, archivePath string) ([]*executables.SupportBundleAnalysis, error)
 }
 
+// FleetClient reads the clusters of a management cluster and what's needed to collect their bundles
+type FleetClient interface {
+	GetEksaClusters(ctx context.Context, cluster *types.Cluster) ([]v1alpha1.Cluster, error)
+	GetSecretFromNamespace(ctx context.Context, kubeconfigFile, name, namespace string) (*corev1.Secret, error)
+	GetBundles(ctx context.Context, kubeconfigFile, name, namespace string) (*releasev1alpha1.Bundles, error)
+	GetEksaGitOpsConfig(ctx context.Context, gitOpsConfigName string, kubeconfigFile string, namespace string) (*v1alpha1.GitOpsConfig, error)
+	GetEksaVSphereMachineConfig(ctx context.Context, vsphereMachineConfigName string, kubeconfigFile string, namespace string) (*v1alpha1.VSphereMachineConfig, error)
+}
+
 type DiagnosticBundleFactory interface {
 	DiagnosticBundle(spec *cluster.Spec, provider providers.Provider, kubeconfig string, bundlePath string) (DiagnosticBundle, error)
 	DiagnosticBundleFromSpec(spec *cluster.Spec, provider providers.Provider, kubeconfig string) (DiagnosticBundle, error)
+	DiagnosticBundleForCluster(spec *cluster.Spec, machineConfigs []providers.MachineConfig, kubeconfig string) (DiagnosticBundle, error)
 	DiagnosticBundleManagementCluster(kubeconfig string) (DiagnosticBundle, error)
 	DiagnosticBundleDefault() DiagnosticBundle
 	DiagnosticBundleCustom(kubeconfig string, bundlePath string) DiagnosticBundle
@@ -28,6 +42,7 @@ type DiagnosticBundle interface {
 	WriteBundleConfig() error
 	PrintAnalysis() error
 	WriteAnalysisToFile() (path string, err error)
+	FailedAnalysis() []*executables.SupportBundleAnalysis
 	CollectAndAnalyze(ctx context.Context, sinceTimeValue *time.Time) error
 	WithDefaultAnalyzers() *EksaDiagnosticBundle
 	WithDefaultCollectors() *EksaDiagnosticBundle
@@ -35,8 +50,11 @@ type DiagnosticBundle interface {
 	WithOidcConfig(config *v1alpha1.OIDCConfig) *EksaDiagnosticBundle
 	WithExternalEtcd(config *v1alpha1.ExternalEtcdConfiguration) *EksaDiagnosticBundle
 	WithGitOpsConfig(config *v1alpha1.GitOpsConfig) *EksaDiagnosticBundle
+	WithRegistryMirror(config *v1alpha1.RegistryMirrorConfiguration) *EksaDiagnosticBundle
 	WithMachineConfigs(configs []providers.MachineConfig) *EksaDiagnosticBundle
 	WithLogTextAnalyzers() *EksaDiagnosticBundle
+	WithRedaction(enabled bool) *EksaDiagnosticBundle
+	WithArchivePath(path string) *EksaDiagnosticBundle
 }
 
 type AnalyzerFactory interface {
@@ -45,6 +63,7 @@ type AnalyzerFactory interface {
 	EksaLogTextAnalyzers(collectors []*Collect) []*Analyze
 	EksaOidcAnalyzers() []*Analyze
 	EksaExternalEtcdAnalyzers() []*Analyze
+	EksaRegistryMirrorAnalyzers(mirror *v1alpha1.RegistryMirrorConfiguration) []*Analyze
 	DataCenterConfigAnalyzers(datacenter v1alpha1.Ref) []*Analyze
 	ManagementClusterAnalyzers() []*Analyze
 }
@@ -14,7 +14,10 @@ import (
 	diagnostics "github.com/aws/eks-anywhere/pkg/diagnostics"
 	executables "github.com/aws/eks-anywhere/pkg/executables"
 	providers "github.com/aws/eks-anywhere/pkg/providers"
+	types "github.com/aws/eks-anywhere/pkg/types"
+	v1alpha10 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 )
 
 // MockBundleClient is a mock of BundleClient interface.
@@ -56,18 +59,116 @@ func (mr *MockBundleClientMockRecorder) Analyze(ctx, bundleSpecPath, archivePath
 }
 
 // Collect mocks base method.
-func (m *MockBundleClient) Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig string) (string, error) {
+func (m *MockBundleClient) Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig, redactorsPath, archivePath string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Collect", ctx, bundlePath, sinceTime, kubeconfig)
+	ret := m.ctrl.Call(m, "Collect", ctx, bundlePath, sinceTime, kubeconfig, redactorsPath, archivePath)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Collect indicates an expected call of Collect.
-func (mr *MockBundleClientMockRecorder) Collect(ctx, bundlePath, sinceTime, kubeconfig interface{}) *gomock.Call {
+func (mr *MockBundleClientMockRecorder) Collect(ctx, bundlePath, sinceTime, kubeconfig, redactorsPath, archivePath interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockBundleClient)(nil).Collect), ctx, bundlePath, sinceTime, kubeconfig)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockBundleClient)(nil).Collect), ctx, bundlePath, sinceTime, kubeconfig, redactorsPath, archivePath)
+}
+
+// MockFleetClient is a mock of FleetClient interface.
+type MockFleetClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFleetClientMockRecorder
+}
+
+// MockFleetClientMockRecorder is the mock recorder for MockFleetClient.
+type MockFleetClientMockRecorder struct {
+	mock *MockFleetClient
+}
+
+// NewMockFleetClient creates a new mock instance.
+func NewMockFleetClient(ctrl *gomock.Controller) *MockFleetClient {
+	mock := &MockFleetClient{ctrl: ctrl}
+	mock.recorder = &MockFleetClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFleetClient) EXPECT() *MockFleetClientMockRecorder {
+	return m.recorder
+}
+
+// GetBundles mocks base method.
+func (m *MockFleetClient) GetBundles(ctx context.Context, kubeconfigFile, name, namespace string) (*v1alpha10.Bundles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundles", ctx, kubeconfigFile, name, namespace)
+	ret0, _ := ret[0].(*v1alpha10.Bundles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundles indicates an expected call of GetBundles.
+func (mr *MockFleetClientMockRecorder) GetBundles(ctx, kubeconfigFile, name, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundles", reflect.TypeOf((*MockFleetClient)(nil).GetBundles), ctx, kubeconfigFile, name, namespace)
+}
+
+// GetEksaClusters mocks base method.
+func (m *MockFleetClient) GetEksaClusters(ctx context.Context, cluster *types.Cluster) ([]v1alpha1.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEksaClusters", ctx, cluster)
+	ret0, _ := ret[0].([]v1alpha1.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEksaClusters indicates an expected call of GetEksaClusters.
+func (mr *MockFleetClientMockRecorder) GetEksaClusters(ctx, cluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEksaClusters", reflect.TypeOf((*MockFleetClient)(nil).GetEksaClusters), ctx, cluster)
+}
+
+// GetEksaGitOpsConfig mocks base method.
+func (m *MockFleetClient) GetEksaGitOpsConfig(ctx context.Context, gitOpsConfigName, kubeconfigFile, namespace string) (*v1alpha1.GitOpsConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEksaGitOpsConfig", ctx, gitOpsConfigName, kubeconfigFile, namespace)
+	ret0, _ := ret[0].(*v1alpha1.GitOpsConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEksaGitOpsConfig indicates an expected call of GetEksaGitOpsConfig.
+func (mr *MockFleetClientMockRecorder) GetEksaGitOpsConfig(ctx, gitOpsConfigName, kubeconfigFile, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEksaGitOpsConfig", reflect.TypeOf((*MockFleetClient)(nil).GetEksaGitOpsConfig), ctx, gitOpsConfigName, kubeconfigFile, namespace)
+}
+
+// GetEksaVSphereMachineConfig mocks base method.
+func (m *MockFleetClient) GetEksaVSphereMachineConfig(ctx context.Context, vsphereMachineConfigName, kubeconfigFile, namespace string) (*v1alpha1.VSphereMachineConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEksaVSphereMachineConfig", ctx, vsphereMachineConfigName, kubeconfigFile, namespace)
+	ret0, _ := ret[0].(*v1alpha1.VSphereMachineConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEksaVSphereMachineConfig indicates an expected call of GetEksaVSphereMachineConfig.
+func (mr *MockFleetClientMockRecorder) GetEksaVSphereMachineConfig(ctx, vsphereMachineConfigName, kubeconfigFile, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEksaVSphereMachineConfig", reflect.TypeOf((*MockFleetClient)(nil).GetEksaVSphereMachineConfig), ctx, vsphereMachineConfigName, kubeconfigFile, namespace)
+}
+
+// GetSecretFromNamespace mocks base method.
+func (m *MockFleetClient) GetSecretFromNamespace(ctx context.Context, kubeconfigFile, name, namespace string) (*v1.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretFromNamespace", ctx, kubeconfigFile, name, namespace)
+	ret0, _ := ret[0].(*v1.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretFromNamespace indicates an expected call of GetSecretFromNamespace.
+func (mr *MockFleetClientMockRecorder) GetSecretFromNamespace(ctx, kubeconfigFile, name, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretFromNamespace", reflect.TypeOf((*MockFleetClient)(nil).GetSecretFromNamespace), ctx, kubeconfigFile, name, namespace)
 }
 
 // MockDiagnosticBundleFactory is a mock of DiagnosticBundleFactory interface.
@@ -136,6 +237,21 @@ func (mr *MockDiagnosticBundleFactoryMockRecorder) DiagnosticBundleDefault() *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiagnosticBundleDefault", reflect.TypeOf((*MockDiagnosticBundleFactory)(nil).DiagnosticBundleDefault))
 }
 
+// DiagnosticBundleForCluster mocks base method.
+func (m *MockDiagnosticBundleFactory) DiagnosticBundleForCluster(spec *cluster.Spec, machineConfigs []providers.MachineConfig, kubeconfig string) (diagnostics.DiagnosticBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiagnosticBundleForCluster", spec, machineConfigs, kubeconfig)
+	ret0, _ := ret[0].(diagnostics.DiagnosticBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiagnosticBundleForCluster indicates an expected call of DiagnosticBundleForCluster.
+func (mr *MockDiagnosticBundleFactoryMockRecorder) DiagnosticBundleForCluster(spec, machineConfigs, kubeconfig interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiagnosticBundleForCluster", reflect.TypeOf((*MockDiagnosticBundleFactory)(nil).DiagnosticBundleForCluster), spec, machineConfigs, kubeconfig)
+}
+
 // DiagnosticBundleFromSpec mocks base method.
 func (m *MockDiagnosticBundleFactory) DiagnosticBundleFromSpec(spec *cluster.Spec, provider providers.Provider, kubeconfig string) (diagnostics.DiagnosticBundle, error) {
 	m.ctrl.T.Helper()
@@ -203,6 +319,20 @@ func (mr *MockDiagnosticBundleMockRecorder) CollectAndAnalyze(ctx, sinceTimeValu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectAndAnalyze", reflect.TypeOf((*MockDiagnosticBundle)(nil).CollectAndAnalyze), ctx, sinceTimeValue)
 }
 
+// FailedAnalysis mocks base method.
+func (m *MockDiagnosticBundle) FailedAnalysis() []*executables.SupportBundleAnalysis {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailedAnalysis")
+	ret0, _ := ret[0].([]*executables.SupportBundleAnalysis)
+	return ret0
+}
+
+// FailedAnalysis indicates an expected call of FailedAnalysis.
+func (mr *MockDiagnosticBundleMockRecorder) FailedAnalysis() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailedAnalysis", reflect.TypeOf((*MockDiagnosticBundle)(nil).FailedAnalysis))
+}
+
 // PrintAnalysis mocks base method.
 func (m *MockDiagnosticBundle) PrintAnalysis() error {
 	m.ctrl.T.Helper()
@@ -231,6 +361,20 @@ func (mr *MockDiagnosticBundleMockRecorder) PrintBundleConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintBundleConfig", reflect.TypeOf((*MockDiagnosticBundle)(nil).PrintBundleConfig))
 }
 
+// WithArchivePath mocks base method.
+func (m *MockDiagnosticBundle) WithArchivePath(path string) *diagnostics.EksaDiagnosticBundle {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithArchivePath", path)
+	ret0, _ := ret[0].(*diagnostics.EksaDiagnosticBundle)
+	return ret0
+}
+
+// WithArchivePath indicates an expected call of WithArchivePath.
+func (mr *MockDiagnosticBundleMockRecorder) WithArchivePath(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithArchivePath", reflect.TypeOf((*MockDiagnosticBundle)(nil).WithArchivePath), path)
+}
+
 // WithDatacenterConfig mocks base method.
 func (m *MockDiagnosticBundle) WithDatacenterConfig(config v1alpha1.Ref) *diagnostics.EksaDiagnosticBundle {
 	m.ctrl.T.Helper()
@@ -343,6 +487,34 @@ func (mr *MockDiagnosticBundleMockRecorder) WithOidcConfig(config interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithOidcConfig", reflect.TypeOf((*MockDiagnosticBundle)(nil).WithOidcConfig), config)
 }
 
+// WithRedaction mocks base method.
+func (m *MockDiagnosticBundle) WithRedaction(enabled bool) *diagnostics.EksaDiagnosticBundle {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRedaction", enabled)
+	ret0, _ := ret[0].(*diagnostics.EksaDiagnosticBundle)
+	return ret0
+}
+
+// WithRedaction indicates an expected call of WithRedaction.
+func (mr *MockDiagnosticBundleMockRecorder) WithRedaction(enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRedaction", reflect.TypeOf((*MockDiagnosticBundle)(nil).WithRedaction), enabled)
+}
+
+// WithRegistryMirror mocks base method.
+func (m *MockDiagnosticBundle) WithRegistryMirror(config *v1alpha1.RegistryMirrorConfiguration) *diagnostics.EksaDiagnosticBundle {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRegistryMirror", config)
+	ret0, _ := ret[0].(*diagnostics.EksaDiagnosticBundle)
+	return ret0
+}
+
+// WithRegistryMirror indicates an expected call of WithRegistryMirror.
+func (mr *MockDiagnosticBundleMockRecorder) WithRegistryMirror(config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRegistryMirror", reflect.TypeOf((*MockDiagnosticBundle)(nil).WithRegistryMirror), config)
+}
+
 // WriteAnalysisToFile mocks base method.
 func (m *MockDiagnosticBundle) WriteAnalysisToFile() (string, error) {
 	m.ctrl.T.Helper()
@@ -479,6 +651,20 @@ func (mr *MockAnalyzerFactoryMockRecorder) EksaOidcAnalyzers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EksaOidcAnalyzers", reflect.TypeOf((*MockAnalyzerFactory)(nil).EksaOidcAnalyzers))
 }
 
+// EksaRegistryMirrorAnalyzers mocks base method.
+func (m *MockAnalyzerFactory) EksaRegistryMirrorAnalyzers(mirror *v1alpha1.RegistryMirrorConfiguration) []*diagnostics.Analyze {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EksaRegistryMirrorAnalyzers", mirror)
+	ret0, _ := ret[0].([]*diagnostics.Analyze)
+	return ret0
+}
+
+// EksaRegistryMirrorAnalyzers indicates an expected call of EksaRegistryMirrorAnalyzers.
+func (mr *MockAnalyzerFactoryMockRecorder) EksaRegistryMirrorAnalyzers(mirror interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EksaRegistryMirrorAnalyzers", reflect.TypeOf((*MockAnalyzerFactory)(nil).EksaRegistryMirrorAnalyzers), mirror)
+}
+
 // ManagementClusterAnalyzers mocks base method.
 func (m *MockAnalyzerFactory) ManagementClusterAnalyzers() []*diagnostics.Analyze {
 	m.ctrl.T.Helper()
@@ -0,0 +1,171 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+)
+
+const redactorKind = "Redactor"
+
+type redactor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec redactorSpec `json:"spec,omitempty"`
+}
+
+type redactorSpec struct {
+	Redactors []*redact `json:"redactors,omitempty"`
+}
+
+type redact struct {
+	Name         string        `json:"name,omitempty"`
+	FileSelector *fileSelector `json:"fileSelector,omitempty"`
+	Removals     removals      `json:"removals,omitempty"`
+}
+
+type fileSelector struct {
+	File  string   `json:"file,omitempty"`
+	Files []string `json:"files,omitempty"`
+}
+
+type removals struct {
+	Values   []string         `json:"values,omitempty"`
+	Regex    []*regexRedactor `json:"regex,omitempty"`
+	YamlPath []string         `json:"yamlPath,omitempty"`
+}
+
+// regexRedactor masks the named group mask in the lines matching Redactor.
+// With a Selector, Redactor is only applied to the line after the lines matching the Selector
+type regexRedactor struct {
+	Selector string `json:"selector,omitempty"`
+	Redactor string `json:"redactor,omitempty"`
+}
+
+func newRedactor(name string) *redactor {
+	return &redactor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       redactorKind,
+			APIVersion: troubleshootApiVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: redactorSpec{
+			Redactors: defaultRedactors(),
+		},
+	}
+}
+
+// defaultRedactors redact the credentials EKS-A stores in the cluster or passes to its components,
+// on top of the troubleshoot default redactors
+func defaultRedactors() []*redact {
+	return []*redact{
+		{
+			Name: "eksa vsphere credentials",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(?i)("?(EKSA_)?VSPHERE_(USERNAME|PASSWORD)"?\s*[:=]\s*"?)(?P<mask>[^"\s,]+)`},
+					{Selector: `(?i)"name":\s*"(EKSA_)?VSPHERE_(USERNAME|PASSWORD)"`, Redactor: `("value":\s*")(?P<mask>[^"]*)(")`},
+					{Redactor: `(?i)^(\s*(user|password)\s*=\s*"?)(?P<mask>[^"\s]+)`},
+				},
+			},
+		},
+		{
+			Name: "eksa kubeconfigs",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(?i)("?(client-key-data|client-certificate-data|token)"?\s*:\s*"?)(?P<mask>[^"\s]+)`},
+				},
+			},
+		},
+		{
+			Name: "eksa flux git credentials",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(?P<mask>\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,})`},
+					{Redactor: `(?i)("?(EKSA_)?GITHUB_TOKEN"?\s*[:=]\s*"?)(?P<mask>[^"\s,]+)`},
+					{Redactor: `(?i)(https?://[^:/@\s"]+:)(?P<mask>[^@/\s"]+)(@)`},
+				},
+			},
+		},
+		{
+			Name: "eksa oidc client ids",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(--oidc-client-id=)(?P<mask>[^"\s,]+)`},
+					{Redactor: `("?clientId"?\s*:\s*"?)(?P<mask>[^"\s,]+)`},
+				},
+			},
+		},
+		{
+			Name: "eksa registry mirror credentials",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(?i)("?REGISTRY_(USERNAME|PASSWORD)"?\s*[:=]\s*"?)(?P<mask>[^"\s,]+)`},
+				},
+			},
+		},
+		{
+			Name: "eksa aws credentials",
+			Removals: removals{
+				Regex: []*regexRedactor{
+					{Redactor: `(?P<mask>\bAKIA[0-9A-Z]{16}\b)`},
+					{Redactor: `(?i)("?aws_secret_access_key"?\s*[:=]\s*"?)(?P<mask>[^"\s,]+)`},
+				},
+			},
+		},
+	}
+}
+
+// withRedacts adds redacts to the redactor, replacing the ones with the same name
+func (r *redactor) withRedacts(redacts []*redact) {
+	for _, redact := range redacts {
+		replaced := false
+		for i, existing := range r.Spec.Redactors {
+			if redact.Name != "" && existing.Name == redact.Name {
+				r.Spec.Redactors[i] = redact
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.Spec.Redactors = append(r.Spec.Redactors, redact)
+		}
+	}
+}
+
+// splitBundleConfig separates the Redactor documents from the rest of the bundle config in bundlePath.
+// It returns the redacts in the Redactor documents and the other documents
+func splitBundleConfig(bundlePath string) (redacts []*redact, bundleConfig []byte, err error) {
+	content, err := ioutil.ReadFile(bundlePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read bundle config: %v", err)
+	}
+
+	var documents []string
+	for _, d := range strings.Split(string(content), v1alpha1.YamlSeparator) {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		meta := &metav1.TypeMeta{}
+		if err = yaml.Unmarshal([]byte(d), meta); err != nil {
+			return nil, nil, fmt.Errorf("unable to parse bundle config: %v", err)
+		}
+		if meta.Kind != redactorKind {
+			documents = append(documents, d)
+			continue
+		}
+		r := &redactor{}
+		if err = yaml.UnmarshalStrict([]byte(d), r); err != nil {
+			return nil, nil, fmt.Errorf("unable to parse redactor in bundle config: %v", err)
+		}
+		redacts = append(redacts, r.Spec.Redactors...)
+	}
+	return redacts, []byte(strings.Join(documents, v1alpha1.YamlSeparator)), nil
+}
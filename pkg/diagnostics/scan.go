@@ -0,0 +1,79 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+const maxScannedLineSize = 16 * 1024 * 1024
+
+// CredentialFinding is a line in a support bundle archive that looks like a credential
+type CredentialFinding struct {
+	File string
+	Line int
+	Type string
+}
+
+var credentialPatterns = []struct {
+	credentialType string
+	regex          *regexp.Regexp
+}{
+	{credentialType: "private key", regex: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{credentialType: "GitHub token", regex: regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,}`)},
+	{credentialType: "AWS access key", regex: regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+	{credentialType: "kubeconfig credential", regex: regexp.MustCompile(`(client-key-data|token)"?\s*:\s*"?[A-Za-z0-9+/=._-]{20,}`)},
+	{credentialType: "password", regex: regexp.MustCompile(`(?i)password"?\s*[:=]\s*"?[^"\s,*]{4,}`)},
+	{credentialType: "JSON web token", regex: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+// ScanArchive looks for lines that still look like credentials in the files of the support bundle archive in archivePath
+func ScanArchive(archivePath string) ([]CredentialFinding, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening support bundle archive: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading support bundle archive: %v", err)
+	}
+	defer gz.Close()
+
+	var findings []CredentialFinding
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return findings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading support bundle archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		findings = append(findings, scanFile(header.Name, archive)...)
+	}
+}
+
+// scanFile reports at most one finding per line. Lines longer than maxScannedLineSize stop the scan of the file
+func scanFile(name string, content io.Reader) []CredentialFinding {
+	var findings []CredentialFinding
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 64*1024), maxScannedLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		for _, p := range credentialPatterns {
+			if p.regex.Match(scanner.Bytes()) {
+				findings = append(findings, CredentialFinding{File: name, Line: line, Type: p.credentialType})
+				break
+			}
+		}
+	}
+	return findings
+}
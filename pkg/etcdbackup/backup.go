@@ -0,0 +1,104 @@
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const snapshotFile = "snapshot.db"
+
+// Backupper takes etcd snapshots of clusters from the machine running the CLI
+type Backupper struct {
+	kubectl KubectlClient
+	runner  ContainerRunner
+	store   Store
+	now     func() time.Time
+}
+
+type BackupperOpt func(*Backupper)
+
+// WithClock sets the function used to get the time snapshots are taken at
+func WithClock(now func() time.Time) BackupperOpt {
+	return func(b *Backupper) {
+		b.now = now
+	}
+}
+
+func NewBackupper(kubectl KubectlClient, runner ContainerRunner, store Store, opts ...BackupperOpt) *Backupper {
+	b := &Backupper{
+		kubectl: kubectl,
+		runner:  runner,
+		store:   store,
+		now:     time.Now,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// Backup saves a snapshot of the etcd of the cluster in clusterSpec to the store, keeping the newest retention
+// snapshots of the cluster. It returns the name of the new snapshot
+func (b *Backupper) Backup(ctx context.Context, managementCluster *types.Cluster, clusterSpec *cluster.Spec, retention int) (string, error) {
+	etcd, err := GetEtcd(ctx, b.kubectl, managementCluster, clusterSpec)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir("", "eks-a-etcd-backup")
+	if err != nil {
+		return "", fmt.Errorf("error creating etcd backup temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctl, err := newEtcdctl(b.runner, EtcdImage(clusterSpec), dir, etcd)
+	if err != nil {
+		return "", err
+	}
+	logger.V(3).Info("Saving etcd snapshot", "endpoints", etcd.Endpoints)
+	if err = ctl.snapshotSave(ctx, snapshotFile); err != nil {
+		return "", err
+	}
+
+	name := SnapshotName(clusterSpec.Name, b.now())
+	if err = StoreSnapshot(ctx, b.store, clusterSpec.Name, name, filepath.Join(dir, snapshotFile), retention); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// StoreSnapshot saves the snapshot file as name and deletes the oldest snapshots of clusterName,
+// keeping the newest retention ones
+func StoreSnapshot(ctx context.Context, store Store, clusterName, name, file string, retention int) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("error opening etcd snapshot: %v", err)
+	}
+	defer f.Close()
+
+	if err = store.Save(ctx, name, f); err != nil {
+		return err
+	}
+
+	deleted, err := Prune(ctx, store, clusterName, retention)
+	if err != nil {
+		return err
+	}
+	for _, d := range deleted {
+		logger.V(3).Info("Deleted old etcd snapshot", "snapshot", d)
+	}
+	return nil
+}
+
+// EtcdImage returns the image etcdctl is run from, which is the etcd image of the cluster
+func EtcdImage(clusterSpec *cluster.Spec) string {
+	return clusterSpec.UseImageMirror(clusterSpec.VersionsBundle.KubeDistro.EtcdImage.VersionedImage())
+}
@@ -0,0 +1,213 @@
+package etcdbackup_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup/mocks"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const etcdImage = "public.ecr.aws/eks-distro/etcd-io/etcd:v3.4.16-eks-1-21-4"
+
+type etcdTest struct {
+	*WithT
+	ctx               context.Context
+	kubectl           *mocks.MockKubectlClient
+	runner            *mocks.MockContainerRunner
+	nodes             *mocks.MockNodeRunner
+	store             *etcdbackup.LocalStore
+	managementCluster *types.Cluster
+	clusterSpec       *cluster.Spec
+}
+
+func newEtcdTest(t *testing.T) *etcdTest {
+	ctrl := gomock.NewController(t)
+	return &etcdTest{
+		WithT:             NewWithT(t),
+		ctx:               context.Background(),
+		kubectl:           mocks.NewMockKubectlClient(ctrl),
+		runner:            mocks.NewMockContainerRunner(ctrl),
+		nodes:             mocks.NewMockNodeRunner(ctrl),
+		store:             etcdbackup.NewLocalStore(t.TempDir()),
+		managementCluster: &types.Cluster{Name: "test-cluster", KubeconfigFile: "test-cluster.kubeconfig"},
+		clusterSpec: test.NewClusterSpec(func(s *cluster.Spec) {
+			s.Name = "test-cluster"
+			s.VersionsBundle.KubeDistro.EtcdImage = releasev1alpha1.Image{URI: etcdImage}
+		}),
+	}
+}
+
+func (tt *etcdTest) useExternalEtcd() {
+	tt.clusterSpec.Spec.ExternalEtcdConfiguration = &v1alpha1.ExternalEtcdConfiguration{Count: 3}
+}
+
+func (tt *etcdTest) expectStackedEtcd() {
+	tt.kubectl.EXPECT().GetMachines(tt.ctx, tt.managementCluster, "test-cluster").Return([]types.Machine{
+		machine("10.0.0.1", true),
+		machine("10.0.0.2", true),
+		machine("10.0.0.10", false),
+	}, nil)
+	caCert, caKey := generateCA(tt.WithT)
+	tt.kubectl.EXPECT().GetSecretFromNamespace(tt.ctx, "test-cluster.kubeconfig", "test-cluster-etcd", "eksa-system").Return(&corev1.Secret{
+		Data: map[string][]byte{"tls.crt": caCert, "tls.key": caKey},
+	}, nil)
+}
+
+func (tt *etcdTest) expectExternalEtcd() {
+	tt.kubectl.EXPECT().GetEtcdadmCluster(tt.ctx, tt.managementCluster, "test-cluster", gomock.Any(), gomock.Any()).Return(&etcdv1alpha3.EtcdadmCluster{
+		Status: etcdv1alpha3.EtcdadmClusterStatus{Endpoints: "https://10.0.0.5:2379,https://10.0.0.6:2379"},
+	}, nil)
+	tt.kubectl.EXPECT().GetSecretFromNamespace(tt.ctx, "test-cluster.kubeconfig", "test-cluster-etcd", "eksa-system").Return(&corev1.Secret{
+		Data: map[string][]byte{"tls.crt": []byte("ca")},
+	}, nil)
+	tt.kubectl.EXPECT().GetSecretFromNamespace(tt.ctx, "test-cluster.kubeconfig", "test-cluster-apiserver-etcd-client", "eksa-system").Return(&corev1.Secret{
+		Data: map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}, nil)
+}
+
+// expectEtcdctl expects an etcdctl run against endpoints with args and calls do with the host dir mounted in the container
+func (tt *etcdTest) expectEtcdctl(endpoints string, args []string, do func(dir string) (string, error)) *gomock.Call {
+	params := []interface{}{"--endpoints=" + endpoints,
+		"--cacert=/etcd-backup/ca.crt", "--cert=/etcd-backup/client.crt", "--key=/etcd-backup/client.key"}
+	for _, a := range args {
+		params = append(params, a)
+	}
+	return tt.runner.EXPECT().RunContainer(tt.ctx, etcdImage, "etcdctl", gomock.Any(), params...).DoAndReturn(
+		func(_ context.Context, _, _ string, volumes []string, _ ...string) (bytes.Buffer, error) {
+			out, err := do(strings.TrimSuffix(volumes[0], ":/etcd-backup"))
+			return *bytes.NewBufferString(out), err
+		},
+	)
+}
+
+func machine(address string, controlPlane bool) types.Machine {
+	m := types.Machine{
+		Metadata: types.MachineMetadata{Labels: map[string]string{"cluster.x-k8s.io/cluster-name": "test-cluster"}},
+		Status: types.MachineStatus{Addresses: []types.MachineAddress{
+			{Type: "ExternalIP", Address: "192.168.0.1"},
+			{Type: "InternalIP", Address: address},
+		}},
+	}
+	if controlPlane {
+		m.Metadata.Labels["cluster.x-k8s.io/control-plane"] = ""
+	}
+	return m
+}
+
+func generateCA(g *WithT) (cert, key []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "etcd-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+}
+
+func writeSnapshot(dir string) (string, error) {
+	return "", ioutil.WriteFile(filepath.Join(dir, "snapshot.db"), []byte("etcd snapshot"), 0o600)
+}
+
+func TestBackupStackedEtcd(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.expectStackedEtcd()
+	tt.expectEtcdctl("https://10.0.0.1:2379", []string{"snapshot", "save", "/etcd-backup/snapshot.db"}, func(dir string) (string, error) {
+		return "", errors.New("member unavailable")
+	})
+	tt.expectEtcdctl("https://10.0.0.2:2379", []string{"snapshot", "save", "/etcd-backup/snapshot.db"}, func(dir string) (string, error) {
+		clientCert, err := ioutil.ReadFile(filepath.Join(dir, "client.crt"))
+		tt.Expect(err).NotTo(HaveOccurred())
+		block, _ := pem.Decode(clientCert)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		tt.Expect(err).NotTo(HaveOccurred())
+		tt.Expect(cert.ExtKeyUsage).To(ConsistOf(x509.ExtKeyUsageClientAuth))
+		return writeSnapshot(dir)
+	})
+
+	now := time.Date(2021, 9, 1, 14, 0, 0, 0, time.UTC)
+	backupper := etcdbackup.NewBackupper(tt.kubectl, tt.runner, tt.store, etcdbackup.WithClock(func() time.Time { return now }))
+	name, err := backupper.Backup(tt.ctx, tt.managementCluster, tt.clusterSpec, 0)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(name).To(Equal("test-cluster-etcd-20210901T140000Z.db"))
+
+	snapshot, err := tt.store.Load(tt.ctx, name)
+	tt.Expect(err).NotTo(HaveOccurred())
+	defer snapshot.Close()
+	content, err := ioutil.ReadAll(snapshot)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(string(content)).To(Equal("etcd snapshot"))
+}
+
+func TestBackupExternalEtcdWithRetention(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	tt.Expect(tt.store.Save(tt.ctx, snapshotAt("test-cluster", 1), strings.NewReader("old"))).To(Succeed())
+	tt.Expect(tt.store.Save(tt.ctx, snapshotAt("test-cluster", 2), strings.NewReader("old"))).To(Succeed())
+	tt.expectExternalEtcd()
+	tt.expectEtcdctl("https://10.0.0.5:2379", []string{"snapshot", "save", "/etcd-backup/snapshot.db"}, func(dir string) (string, error) {
+		clientCert, err := ioutil.ReadFile(filepath.Join(dir, "client.crt"))
+		tt.Expect(err).NotTo(HaveOccurred())
+		tt.Expect(string(clientCert)).To(Equal("cert"))
+		return writeSnapshot(dir)
+	})
+
+	name, err := etcdbackup.NewBackupper(tt.kubectl, tt.runner, tt.store).Backup(tt.ctx, tt.managementCluster, tt.clusterSpec, 2)
+	tt.Expect(err).NotTo(HaveOccurred())
+
+	snapshots, err := etcdbackup.ClusterSnapshots(tt.ctx, tt.store, "test-cluster")
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(snapshots).To(Equal([]string{snapshotAt("test-cluster", 2), name}))
+}
+
+func TestBackupAllEndpointsFail(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	tt.expectExternalEtcd()
+	for _, endpoint := range []string{"https://10.0.0.5:2379", "https://10.0.0.6:2379"} {
+		tt.expectEtcdctl(endpoint, []string{"snapshot", "save", "/etcd-backup/snapshot.db"}, func(string) (string, error) {
+			return "", errors.New("connection refused")
+		})
+	}
+
+	_, err := etcdbackup.NewBackupper(tt.kubectl, tt.runner, tt.store).Backup(tt.ctx, tt.managementCluster, tt.clusterSpec, 0)
+	tt.Expect(err).To(MatchError(ContainSubstring("error saving etcd snapshot: https://10.0.0.5:2379: connection refused")))
+}
+
+func TestBackupNoControlPlaneMachines(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.kubectl.EXPECT().GetMachines(tt.ctx, tt.managementCluster, "test-cluster").Return([]types.Machine{machine("10.0.0.10", false)}, nil)
+
+	_, err := etcdbackup.NewBackupper(tt.kubectl, tt.runner, tt.store).Backup(tt.ctx, tt.managementCluster, tt.clusterSpec, 0)
+	tt.Expect(err).To(MatchError("no control plane machines with an address found for cluster test-cluster"))
+}
@@ -0,0 +1,61 @@
+package etcdbackup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+// StoreCommand is the command of the EKS-A controller image that stores the snapshots taken by the backup CronJob
+const StoreCommand = "etcd-backup-store"
+
+// NewStore returns a LocalStore for localPath or an S3Store for s3Config. Exactly one of them must be set
+func NewStore(localPath string, s3Config *S3Config) (Store, error) {
+	if (localPath == "") == (s3Config == nil) {
+		return nil, errors.New("exactly one of a local path and an s3 bucket is required to store etcd snapshots")
+	}
+	if localPath != "" {
+		return NewLocalStore(localPath), nil
+	}
+	return NewS3Store(*s3Config)
+}
+
+// RunStoreCommand saves the snapshot taken by the backup CronJob to the configured store and applies the retention.
+// args are the flags of StoreCommand
+func RunStoreCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(StoreCommand, flag.ContinueOnError)
+	clusterName := flags.String("cluster", "", "Name of the cluster the snapshot belongs to")
+	snapshot := flags.String("snapshot", "", "Snapshot file to store")
+	retention := flags.Int("retention", DefaultRetention, "Number of snapshots of the cluster to keep")
+	localPath := flags.String("local-path", "", "Directory to save the snapshot to")
+	s3Config := S3Config{}
+	flags.StringVar(&s3Config.Bucket, "s3-bucket", "", "Bucket to upload the snapshot to")
+	flags.StringVar(&s3Config.Prefix, "s3-prefix", "", "Prefix of the snapshot object key")
+	flags.StringVar(&s3Config.Region, "s3-region", "", "Region of the bucket")
+	flags.StringVar(&s3Config.Endpoint, "s3-endpoint", "", "Endpoint of an S3-compatible service")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *clusterName == "" || *snapshot == "" {
+		return errors.New("cluster and snapshot are required")
+	}
+
+	var s3 *S3Config
+	if s3Config.Bucket != "" {
+		s3 = &s3Config
+	}
+	store, err := NewStore(*localPath, s3)
+	if err != nil {
+		return err
+	}
+
+	name := SnapshotName(*clusterName, time.Now())
+	if err = StoreSnapshot(ctx, store, *clusterName, name, *snapshot, *retention); err != nil {
+		return err
+	}
+	logger.Info("Stored etcd snapshot", "snapshot", name)
+	return nil
+}
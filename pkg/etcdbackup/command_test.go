@@ -0,0 +1,38 @@
+package etcdbackup_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+)
+
+func TestRunStoreCommandLocalPath(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	g.Expect(ioutil.WriteFile(snapshot, []byte("etcd snapshot"), 0o600)).To(Succeed())
+	store := etcdbackup.NewLocalStore(dir)
+	for _, hour := range []int{1, 2} {
+		g.Expect(ioutil.WriteFile(filepath.Join(dir, snapshotAt("test-cluster", hour)), []byte("old"), 0o600)).To(Succeed())
+	}
+
+	err := etcdbackup.RunStoreCommand(ctx, []string{"--cluster=test-cluster", "--snapshot=" + snapshot, "--retention=2", "--local-path=" + dir})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	snapshots, err := etcdbackup.ClusterSnapshots(ctx, store, "test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshots).To(HaveLen(2))
+	g.Expect(snapshots[0]).To(Equal(snapshotAt("test-cluster", 2)))
+}
+
+func TestRunStoreCommandNoStore(t *testing.T) {
+	g := NewWithT(t)
+	err := etcdbackup.RunStoreCommand(context.Background(), []string{"--cluster=test-cluster", "--snapshot=snapshot.db"})
+	g.Expect(err).To(MatchError("exactly one of a local path and an s3 bucket is required to store etcd snapshots"))
+}
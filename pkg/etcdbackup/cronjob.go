@@ -0,0 +1,101 @@
+package etcdbackup
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/templater"
+)
+
+//go:embed config/cronjob.yaml
+var cronJobTemplate string
+
+const (
+	accessKeyIDEnv          = "AWS_ACCESS_KEY_ID"
+	secretAccessKeyEnv      = "AWS_SECRET_ACCESS_KEY"
+	cronJobNameFormat       = "%s-etcd-backup"
+	credentialsSecretFormat = "%s-etcd-backup-s3-credentials"
+	stackedEtcdEndpoint     = "https://127.0.0.1:2379"
+)
+
+// CronJobManifest returns the CronJob that takes the scheduled etcd backups of the cluster in clusterSpec and,
+// when the snapshots are uploaded to a bucket, the secret with the S3 credentials copied from the AWS_* env vars
+// of the CLI. Otherwise the snapshots are written to the persistent volume claim from the spec.
+// externalEtcdEndpoints are only used for external etcd, stacked etcd is reached in the node the job runs in
+func CronJobManifest(clusterSpec *cluster.Spec, externalEtcdEndpoints []string) ([]byte, error) {
+	backup := clusterSpec.Spec.EtcdBackup
+	if backup == nil {
+		return nil, errors.New("etcd backup is not configured for the cluster")
+	}
+
+	endpoints := stackedEtcdEndpoint
+	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
+		if len(externalEtcdEndpoints) == 0 {
+			return nil, errors.New("external etcd endpoints are required for the etcd backup cronjob")
+		}
+		endpoints = strings.Join(externalEtcdEndpoints, ",")
+	}
+
+	retention := backup.Retention
+	if retention == 0 {
+		retention = DefaultRetention
+	}
+
+	values := map[string]interface{}{
+		"clusterName":           clusterSpec.Name,
+		"cronJobName":           CronJobName(clusterSpec.Name),
+		"namespace":             constants.EksaSystemNamespace,
+		"schedule":              backup.Schedule,
+		"retention":             retention,
+		"endpoints":             endpoints,
+		"etcdImage":             EtcdImage(clusterSpec),
+		"controllerImage":       clusterSpec.UseImageMirror(clusterSpec.VersionsBundle.Eksa.ClusterController.VersionedImage()),
+		"storeCommand":          StoreCommand,
+		"cronJobAPIVersion":     cronJobAPIVersion(clusterSpec.Spec.KubernetesVersion),
+		"persistentVolumeClaim": backup.PersistentVolumeClaim,
+	}
+
+	if backup.S3 != nil {
+		accessKeyID, secretAccessKey := os.Getenv(accessKeyIDEnv), os.Getenv(secretAccessKeyEnv)
+		if accessKeyID == "" || secretAccessKey == "" {
+			return nil, fmt.Errorf("%s and %s env vars are required to upload etcd backups to s3", accessKeyIDEnv, secretAccessKeyEnv)
+		}
+		values["s3"] = backup.S3
+		values["credentialsSecret"] = CredentialsSecretName(clusterSpec.Name)
+		values["accessKeyID"] = base64.StdEncoding.EncodeToString([]byte(accessKeyID))
+		values["secretAccessKey"] = base64.StdEncoding.EncodeToString([]byte(secretAccessKey))
+	}
+
+	content, err := templater.Execute(cronJobTemplate, values)
+	if err != nil {
+		return nil, fmt.Errorf("error generating etcd backup cronjob: %v", err)
+	}
+	return content, nil
+}
+
+// cronJobAPIVersion returns batch/v1 for the Kubernetes versions serving it, CronJobs are only beta before 1.21
+func cronJobAPIVersion(kubeVersion v1alpha1.KubernetesVersion) string {
+	switch kubeVersion {
+	case v1alpha1.Kube118, v1alpha1.Kube119, v1alpha1.Kube120:
+		return "batch/v1beta1"
+	default:
+		return "batch/v1"
+	}
+}
+
+// CronJobName returns the name of the etcd backup CronJob of clusterName
+func CronJobName(clusterName string) string {
+	return fmt.Sprintf(cronJobNameFormat, clusterName)
+}
+
+// CredentialsSecretName returns the name of the secret with the S3 credentials used by the etcd backup CronJob of clusterName
+func CredentialsSecretName(clusterName string) string {
+	return fmt.Sprintf(credentialsSecretFormat, clusterName)
+}
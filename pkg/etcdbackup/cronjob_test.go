@@ -0,0 +1,115 @@
+package etcdbackup_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+func cronJobSpec(backup *v1alpha1.EtcdBackupConfiguration) *cluster.Spec {
+	return test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Name = "test-cluster"
+		s.Spec.EtcdBackup = backup
+		s.VersionsBundle.KubeDistro.EtcdImage = releasev1alpha1.Image{URI: etcdImage}
+		s.VersionsBundle.Eksa.ClusterController = releasev1alpha1.Image{URI: "public.ecr.aws/l0g8r8j6/eks-anywhere-cluster-controller:v0.5.0"}
+	})
+}
+
+func parseCronJob(g *WithT, manifest []byte) *batchv1.CronJob {
+	docs := strings.Split(string(manifest), "\n---\n")
+	cronJob := &batchv1.CronJob{}
+	g.Expect(yaml.UnmarshalStrict([]byte(docs[len(docs)-1]), cronJob)).To(Succeed())
+	return cronJob
+}
+
+func TestCronJobManifestPersistentVolumeClaim(t *testing.T) {
+	g := NewWithT(t)
+	spec := cronJobSpec(&v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"})
+	spec.Spec.KubernetesVersion = v1alpha1.Kube121
+
+	manifest, err := etcdbackup.CronJobManifest(spec, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).NotTo(ContainSubstring("kind: Secret"))
+
+	cronJob := parseCronJob(g, manifest)
+	g.Expect(cronJob.APIVersion).To(Equal("batch/v1"))
+	g.Expect(cronJob.Name).To(Equal("test-cluster-etcd-backup"))
+	g.Expect(cronJob.Spec.Schedule).To(Equal("0 */6 * * *"))
+	pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(pod.InitContainers[0].Image).To(Equal(etcdImage))
+	g.Expect(pod.InitContainers[0].Args).To(ContainElement("--endpoints=https://127.0.0.1:2379"))
+	g.Expect(pod.Containers[0].Args).To(Equal([]string{
+		"etcd-backup-store", "--cluster=test-cluster", "--snapshot=/snapshot/snapshot.db", "--retention=7", "--local-path=/backups",
+	}))
+	g.Expect(pod.Volumes[2].HostPath).To(BeNil())
+	g.Expect(pod.Volumes[2].PersistentVolumeClaim.ClaimName).To(Equal("etcd-backups"))
+}
+
+func TestCronJobManifestBetaAPIVersion(t *testing.T) {
+	g := NewWithT(t)
+	spec := cronJobSpec(&v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"})
+	spec.Spec.KubernetesVersion = v1alpha1.Kube120
+
+	manifest, err := etcdbackup.CronJobManifest(spec, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(parseCronJob(g, manifest).APIVersion).To(Equal("batch/v1beta1"))
+}
+
+func TestCronJobManifestS3ExternalEtcd(t *testing.T) {
+	g := NewWithT(t)
+	os.Setenv("AWS_ACCESS_KEY_ID", "minio")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "minio123")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	spec := cronJobSpec(&v1alpha1.EtcdBackupConfiguration{
+		Schedule:  "30 2 * * *",
+		Retention: 14,
+		S3:        &v1alpha1.EtcdBackupS3Configuration{Bucket: "etcd-backups", Prefix: "prod", Endpoint: "http://minio.local:9000"},
+	})
+	spec.Spec.ExternalEtcdConfiguration = &v1alpha1.ExternalEtcdConfiguration{Count: 3}
+
+	manifest, err := etcdbackup.CronJobManifest(spec, []string{"https://10.0.0.5:2379", "https://10.0.0.6:2379"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(ContainSubstring("name: test-cluster-etcd-backup-s3-credentials"))
+	g.Expect(string(manifest)).To(ContainSubstring("AWS_SECRET_ACCESS_KEY: bWluaW8xMjM="))
+
+	pod := parseCronJob(g, manifest).Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(pod.InitContainers[0].Args).To(ContainElement("--endpoints=https://10.0.0.5:2379,https://10.0.0.6:2379"))
+	g.Expect(pod.Containers[0].Args).To(Equal([]string{
+		"etcd-backup-store", "--cluster=test-cluster", "--snapshot=/snapshot/snapshot.db", "--retention=14",
+		"--s3-bucket=etcd-backups", "--s3-prefix=prod", "--s3-endpoint=http://minio.local:9000",
+	}))
+	g.Expect(pod.Containers[0].EnvFrom[0].SecretRef.Name).To(Equal("test-cluster-etcd-backup-s3-credentials"))
+	g.Expect(pod.Volumes).To(HaveLen(2))
+}
+
+func TestCronJobManifestS3MissingCredentials(t *testing.T) {
+	g := NewWithT(t)
+	os.Unsetenv("AWS_ACCESS_KEY_ID")
+	spec := cronJobSpec(&v1alpha1.EtcdBackupConfiguration{
+		Schedule: "30 2 * * *",
+		S3:       &v1alpha1.EtcdBackupS3Configuration{Bucket: "etcd-backups"},
+	})
+
+	_, err := etcdbackup.CronJobManifest(spec, nil)
+	g.Expect(err).To(MatchError(ContainSubstring("env vars are required to upload etcd backups to s3")))
+}
+
+func TestCronJobManifestExternalEtcdWithoutEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	spec := cronJobSpec(&v1alpha1.EtcdBackupConfiguration{Schedule: "30 2 * * *", PersistentVolumeClaim: "etcd-backups"})
+	spec.Spec.ExternalEtcdConfiguration = &v1alpha1.ExternalEtcdConfiguration{Count: 3}
+
+	_, err := etcdbackup.CronJobManifest(spec, nil)
+	g.Expect(err).To(MatchError("external etcd endpoints are required for the etcd backup cronjob"))
+}
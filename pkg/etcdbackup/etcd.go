@@ -0,0 +1,186 @@
+package etcdbackup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	etcdClientPort          = 2379
+	etcdPeerPort            = 2380
+	etcdClusterLabel        = "cluster.x-k8s.io/etcd-cluster"
+	clientCertCommonName    = "eks-a-etcd-backup"
+	clientCertLifetime      = time.Hour
+	etcdCASecretPattern     = "%s-etcd"
+	etcdClientSecretPattern = "%s-apiserver-etcd-client"
+)
+
+type KubectlClient interface {
+	GetSecretFromNamespace(ctx context.Context, kubeconfigFile, name, namespace string) (*corev1.Secret, error)
+	GetMachines(ctx context.Context, cluster *types.Cluster, clusterName string) ([]types.Machine, error)
+	GetEtcdadmCluster(ctx context.Context, cluster *types.Cluster, clusterName string, opts ...executables.KubectlOpt) (*etcdv1alpha3.EtcdadmCluster, error)
+}
+
+// Etcd holds the endpoints of the etcd of a cluster and the TLS material to connect to it
+type Etcd struct {
+	// External is true when etcd runs in its own machines, managed by an EtcdadmCluster
+	External   bool
+	Endpoints  []string
+	CACert     []byte
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// GetEtcd reads the etcd endpoints and client certificates of the cluster in clusterSpec from managementCluster.
+// Stacked etcd runs in the KubeadmControlPlane machines and a short lived client certificate is signed with its CA.
+// External etcd uses the client certificate generated for the kube-apiserver
+func GetEtcd(ctx context.Context, kubectl KubectlClient, managementCluster *types.Cluster, clusterSpec *cluster.Spec) (*Etcd, error) {
+	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
+		return getExternalEtcd(ctx, kubectl, managementCluster, clusterSpec.Name)
+	}
+	return getStackedEtcd(ctx, kubectl, managementCluster, clusterSpec.Name)
+}
+
+func getStackedEtcd(ctx context.Context, kubectl KubectlClient, managementCluster *types.Cluster, clusterName string) (*Etcd, error) {
+	machines, err := kubectl.GetMachines(ctx, managementCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []string
+	for _, m := range machines {
+		if _, ok := m.Metadata.Labels[clusterv1.MachineControlPlaneLabelName]; !ok {
+			continue
+		}
+		if address := machineAddress(m); address != "" {
+			endpoints = append(endpoints, fmt.Sprintf("https://%s:%d", address, etcdClientPort))
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no control plane machines with an address found for cluster %s", clusterName)
+	}
+
+	ca, err := getSecret(ctx, kubectl, managementCluster, fmt.Sprintf(etcdCASecretPattern, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	cert, key, err := signClientCert(ca.Data[corev1.TLSCertKey], ca.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("error generating etcd client certificate: %v", err)
+	}
+
+	return &Etcd{
+		Endpoints:  endpoints,
+		CACert:     ca.Data[corev1.TLSCertKey],
+		ClientCert: cert,
+		ClientKey:  key,
+	}, nil
+}
+
+func getExternalEtcd(ctx context.Context, kubectl KubectlClient, managementCluster *types.Cluster, clusterName string) (*Etcd, error) {
+	etcdadmCluster, err := kubectl.GetEtcdadmCluster(ctx, managementCluster, clusterName, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+	if etcdadmCluster.Status.Endpoints == "" {
+		return nil, fmt.Errorf("etcdadmcluster %s doesn't have any endpoints yet", etcdadmCluster.Name)
+	}
+
+	ca, err := getSecret(ctx, kubectl, managementCluster, fmt.Sprintf(etcdCASecretPattern, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	client, err := getSecret(ctx, kubectl, managementCluster, fmt.Sprintf(etcdClientSecretPattern, clusterName))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Etcd{
+		External:   true,
+		Endpoints:  strings.Split(etcdadmCluster.Status.Endpoints, ","),
+		CACert:     ca.Data[corev1.TLSCertKey],
+		ClientCert: client.Data[corev1.TLSCertKey],
+		ClientKey:  client.Data[corev1.TLSPrivateKeyKey],
+	}, nil
+}
+
+func getSecret(ctx context.Context, kubectl KubectlClient, managementCluster *types.Cluster, name string) (*corev1.Secret, error) {
+	secret, err := kubectl.GetSecretFromNamespace(ctx, managementCluster.KubeconfigFile, name, constants.EksaSystemNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("error getting etcd certificates: %v", err)
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 {
+		return nil, fmt.Errorf("secret %s doesn't contain a certificate", name)
+	}
+	return secret, nil
+}
+
+func machineAddress(m types.Machine) string {
+	for _, addressType := range []string{string(clusterv1.MachineInternalIP), string(clusterv1.MachineExternalIP)} {
+		for _, a := range m.Status.Addresses {
+			if a.Type == addressType && a.Address != "" {
+				return a.Address
+			}
+		}
+	}
+	return ""
+}
+
+func signClientCert(caCertPEM, caKeyPEM []byte) (cert, key []byte, err error) {
+	caCertBlock, _ := pem.Decode(caCertPEM)
+	caKeyBlock, _ := pem.Decode(caKeyPEM)
+	if caCertBlock == nil || caKeyBlock == nil {
+		return nil, nil, errors.New("invalid etcd CA certificate or key")
+	}
+	caCert, err := x509.ParseCertificate(caCertBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, err := x509.ParsePKCS1PrivateKey(caKeyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: clientCertCommonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(clientCertLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	return cert, key, nil
+}
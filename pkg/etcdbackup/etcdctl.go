@@ -0,0 +1,97 @@
+package etcdbackup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	etcdctlEntrypoint = "etcdctl"
+	containerDir      = "/etcd-backup"
+	caCertFile        = "ca.crt"
+	clientCertFile    = "client.crt"
+	clientKeyFile     = "client.key"
+)
+
+// ContainerRunner runs a command in a container, like executables.Docker
+type ContainerRunner interface {
+	RunContainer(ctx context.Context, image, entrypoint string, volumes []string, args ...string) (bytes.Buffer, error)
+}
+
+type member struct {
+	Name       string
+	PeerURLs   []string
+	ClientURLs []string
+}
+
+// etcdctl runs etcdctl from the etcd image of the cluster. dir is mounted in the container and holds
+// the client certificates and the snapshots
+type etcdctl struct {
+	runner ContainerRunner
+	image  string
+	dir    string
+	etcd   *Etcd
+}
+
+func newEtcdctl(runner ContainerRunner, image, dir string, etcd *Etcd) (*etcdctl, error) {
+	files := map[string][]byte{
+		caCertFile:     etcd.CACert,
+		clientCertFile: etcd.ClientCert,
+		clientKeyFile:  etcd.ClientKey,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			return nil, fmt.Errorf("error writing etcd client certificates: %v", err)
+		}
+	}
+	return &etcdctl{runner: runner, image: image, dir: dir, etcd: etcd}, nil
+}
+
+// containerPath returns the path in the container of file, relative to the mounted dir
+func containerPath(file string) string {
+	return path.Join(containerDir, filepath.ToSlash(file))
+}
+
+func (e *etcdctl) run(ctx context.Context, endpoints []string, args ...string) (bytes.Buffer, error) {
+	params := []string{
+		"--endpoints=" + strings.Join(endpoints, ","),
+		"--cacert=" + containerPath(caCertFile),
+		"--cert=" + containerPath(clientCertFile),
+		"--key=" + containerPath(clientKeyFile),
+	}
+	params = append(params, args...)
+	return e.runner.RunContainer(ctx, e.image, etcdctlEntrypoint, []string{e.dir + ":" + containerDir}, params...)
+}
+
+// snapshotSave saves a snapshot to file, in dir, from the first endpoint that answers
+func (e *etcdctl) snapshotSave(ctx context.Context, file string) error {
+	var errs []string
+	for _, endpoint := range e.etcd.Endpoints {
+		_, err := e.run(ctx, []string{endpoint}, "snapshot", "save", containerPath(file))
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", endpoint, err))
+	}
+	return fmt.Errorf("error saving etcd snapshot: %s", strings.Join(errs, "; "))
+}
+
+// snapshotRestore creates the data dir of member m, in dir, from the snapshot file
+func (e *etcdctl) snapshotRestore(ctx context.Context, file string, m member, initialCluster, dataDir string) error {
+	_, err := e.run(ctx, e.etcd.Endpoints,
+		"snapshot", "restore", containerPath(file),
+		"--name="+m.Name,
+		"--initial-cluster="+initialCluster,
+		"--initial-advertise-peer-urls="+strings.Join(m.PeerURLs, ","),
+		"--data-dir="+containerPath(dataDir),
+	)
+	if err != nil {
+		return fmt.Errorf("error restoring etcd snapshot for member %s: %v", m.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/aws/eks-anywhere/pkg/etcdbackup (interfaces: KubectlClient,ContainerRunner,NodeRunner)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	bytes "bytes"
+	context "context"
+	reflect "reflect"
+
+	executables "github.com/aws/eks-anywhere/pkg/executables"
+	types "github.com/aws/eks-anywhere/pkg/types"
+	gomock "github.com/golang/mock/gomock"
+	v1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+)
+
+// MockKubectlClient is a mock of KubectlClient interface.
+type MockKubectlClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockKubectlClientMockRecorder
+}
+
+// MockKubectlClientMockRecorder is the mock recorder for MockKubectlClient.
+type MockKubectlClientMockRecorder struct {
+	mock *MockKubectlClient
+}
+
+// NewMockKubectlClient creates a new mock instance.
+func NewMockKubectlClient(ctrl *gomock.Controller) *MockKubectlClient {
+	mock := &MockKubectlClient{ctrl: ctrl}
+	mock.recorder = &MockKubectlClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKubectlClient) EXPECT() *MockKubectlClientMockRecorder {
+	return m.recorder
+}
+
+// GetEtcdadmCluster mocks base method.
+func (m *MockKubectlClient) GetEtcdadmCluster(arg0 context.Context, arg1 *types.Cluster, arg2 string, arg3 ...executables.KubectlOpt) (*v1alpha3.EtcdadmCluster, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEtcdadmCluster", varargs...)
+	ret0, _ := ret[0].(*v1alpha3.EtcdadmCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEtcdadmCluster indicates an expected call of GetEtcdadmCluster.
+func (mr *MockKubectlClientMockRecorder) GetEtcdadmCluster(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEtcdadmCluster", reflect.TypeOf((*MockKubectlClient)(nil).GetEtcdadmCluster), varargs...)
+}
+
+// GetMachines mocks base method.
+func (m *MockKubectlClient) GetMachines(arg0 context.Context, arg1 *types.Cluster, arg2 string) ([]types.Machine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachines", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Machine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachines indicates an expected call of GetMachines.
+func (mr *MockKubectlClientMockRecorder) GetMachines(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachines", reflect.TypeOf((*MockKubectlClient)(nil).GetMachines), arg0, arg1, arg2)
+}
+
+// GetSecretFromNamespace mocks base method.
+func (m *MockKubectlClient) GetSecretFromNamespace(arg0 context.Context, arg1, arg2, arg3 string) (*v1.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretFromNamespace", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretFromNamespace indicates an expected call of GetSecretFromNamespace.
+func (mr *MockKubectlClientMockRecorder) GetSecretFromNamespace(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretFromNamespace", reflect.TypeOf((*MockKubectlClient)(nil).GetSecretFromNamespace), arg0, arg1, arg2, arg3)
+}
+
+// MockContainerRunner is a mock of ContainerRunner interface.
+type MockContainerRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockContainerRunnerMockRecorder
+}
+
+// MockContainerRunnerMockRecorder is the mock recorder for MockContainerRunner.
+type MockContainerRunnerMockRecorder struct {
+	mock *MockContainerRunner
+}
+
+// NewMockContainerRunner creates a new mock instance.
+func NewMockContainerRunner(ctrl *gomock.Controller) *MockContainerRunner {
+	mock := &MockContainerRunner{ctrl: ctrl}
+	mock.recorder = &MockContainerRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContainerRunner) EXPECT() *MockContainerRunnerMockRecorder {
+	return m.recorder
+}
+
+// RunContainer mocks base method.
+func (m *MockContainerRunner) RunContainer(arg0 context.Context, arg1, arg2 string, arg3 []string, arg4 ...string) (bytes.Buffer, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3}
+	for _, a := range arg4 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunContainer", varargs...)
+	ret0, _ := ret[0].(bytes.Buffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunContainer indicates an expected call of RunContainer.
+func (mr *MockContainerRunnerMockRecorder) RunContainer(arg0, arg1, arg2, arg3 interface{}, arg4 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3}, arg4...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunContainer", reflect.TypeOf((*MockContainerRunner)(nil).RunContainer), varargs...)
+}
+
+// MockNodeRunner is a mock of NodeRunner interface.
+type MockNodeRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeRunnerMockRecorder
+}
+
+// MockNodeRunnerMockRecorder is the mock recorder for MockNodeRunner.
+type MockNodeRunnerMockRecorder struct {
+	mock *MockNodeRunner
+}
+
+// NewMockNodeRunner creates a new mock instance.
+func NewMockNodeRunner(ctrl *gomock.Controller) *MockNodeRunner {
+	mock := &MockNodeRunner{ctrl: ctrl}
+	mock.recorder = &MockNodeRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeRunner) EXPECT() *MockNodeRunnerMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockNodeRunner) Run(arg0 context.Context, arg1, arg2 string, arg3 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockNodeRunnerMockRecorder) Run(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockNodeRunner)(nil).Run), arg0, arg1, arg2, arg3)
+}
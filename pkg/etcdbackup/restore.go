@@ -0,0 +1,249 @@
+package etcdbackup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	restoreDir = "restore"
+
+	// stacked etcd runs as a static pod, it's stopped by moving its manifest out of the manifests dir
+	stopStackedEtcdCommand = "sudo mv /etc/kubernetes/manifests/etcd.yaml /etc/kubernetes/etcd.yaml.eks-a-restore && " +
+		"for i in $(seq 60); do sudo crictl ps -q --name '^etcd$' | grep -q . || exit 0; sleep 2; done; exit 1"
+	startStackedEtcdCommand = "sudo mv /etc/kubernetes/etcd.yaml.eks-a-restore /etc/kubernetes/manifests/etcd.yaml"
+
+	stopExternalEtcdCommand  = "sudo systemctl stop etcd"
+	startExternalEtcdCommand = "sudo systemctl start etcd"
+
+	// the current data is kept next to the restored one, in case it needs to be recovered manually
+	replaceDataCommand = "sudo rm -rf /var/lib/etcd/member.eks-a-restore-backup && " +
+		"sudo mv /var/lib/etcd/member /var/lib/etcd/member.eks-a-restore-backup && " +
+		"sudo tar -xzf - -C /var/lib/etcd"
+)
+
+// Restorer replaces the data of all the etcd members of a cluster with a snapshot
+type Restorer struct {
+	kubectl KubectlClient
+	runner  ContainerRunner
+	nodes   NodeRunner
+	store   Store
+}
+
+func NewRestorer(kubectl KubectlClient, runner ContainerRunner, nodes NodeRunner, store Store) *Restorer {
+	return &Restorer{
+		kubectl: kubectl,
+		runner:  runner,
+		nodes:   nodes,
+		store:   store,
+	}
+}
+
+type restoredMember struct {
+	member
+	host string
+	data []byte
+}
+
+// Restore restores the snapshot name in all the etcd members of the cluster in clusterSpec.
+// The data dirs are generated locally, then all the members are stopped before any of them is restarted with the new data
+func (r *Restorer) Restore(ctx context.Context, managementCluster *types.Cluster, clusterSpec *cluster.Spec, name string) error {
+	etcd, err := GetEtcd(ctx, r.kubectl, managementCluster, clusterSpec)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "eks-a-etcd-restore")
+	if err != nil {
+		return fmt.Errorf("error creating etcd restore temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = r.download(ctx, name, filepath.Join(dir, snapshotFile)); err != nil {
+		return err
+	}
+
+	ctl, err := newEtcdctl(r.runner, EtcdImage(clusterSpec), dir, etcd)
+	if err != nil {
+		return err
+	}
+
+	members, err := getMembers(ctx, r.kubectl, managementCluster, clusterSpec)
+	if err != nil {
+		return err
+	}
+
+	restored, err := restoreMembers(ctx, ctl, dir, members)
+	if err != nil {
+		return err
+	}
+
+	stopCommand, startCommand := stopStackedEtcdCommand, startStackedEtcdCommand
+	if etcd.External {
+		stopCommand, startCommand = stopExternalEtcdCommand, startExternalEtcdCommand
+	}
+
+	for _, m := range restored {
+		logger.V(3).Info("Stopping etcd member", "member", m.Name, "node", m.host)
+		if err = r.nodes.Run(ctx, m.host, stopCommand, nil); err != nil {
+			return fmt.Errorf("error stopping etcd member %s: %v", m.Name, err)
+		}
+	}
+
+	for _, m := range restored {
+		logger.V(3).Info("Restoring etcd member", "member", m.Name, "node", m.host)
+		if err = r.nodes.Run(ctx, m.host, replaceDataCommand, m.data); err != nil {
+			return fmt.Errorf("error replacing data of etcd member %s: %v", m.Name, err)
+		}
+		if err = r.nodes.Run(ctx, m.host, startCommand, nil); err != nil {
+			return fmt.Errorf("error starting etcd member %s: %v", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Restorer) download(ctx context.Context, name, file string) error {
+	snapshot, err := r.store.Load(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating etcd snapshot file: %v", err)
+	}
+	defer f.Close()
+	if _, err = io.Copy(f, snapshot); err != nil {
+		return fmt.Errorf("error downloading etcd snapshot %s: %v", name, err)
+	}
+	return nil
+}
+
+// getMembers returns the etcd members of the cluster in clusterSpec from its machines. etcd might not have
+// quorum when a snapshot is restored, so the members can't be listed with etcdctl. Both kubeadm and etcdadm
+// name the members after the hostname, which is the name of the machine.
+func getMembers(ctx context.Context, kubectl KubectlClient, managementCluster *types.Cluster, clusterSpec *cluster.Spec) ([]member, error) {
+	machines, err := kubectl.GetMachines(ctx, managementCluster, clusterSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	label := clusterv1.MachineControlPlaneLabelName
+	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
+		label = etcdClusterLabel
+	}
+
+	var members []member
+	for _, m := range machines {
+		if _, ok := m.Metadata.Labels[label]; !ok {
+			continue
+		}
+		address := machineAddress(m)
+		if address == "" {
+			return nil, fmt.Errorf("etcd machine %s doesn't have an address, all members must be running to restore a snapshot", m.Metadata.Name)
+		}
+		members = append(members, member{
+			Name:       m.Metadata.Name,
+			PeerURLs:   []string{fmt.Sprintf("https://%s:%d", address, etcdPeerPort)},
+			ClientURLs: []string{fmt.Sprintf("https://%s:%d", address, etcdClientPort)},
+		})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no etcd machines found for cluster %s", clusterSpec.Name)
+	}
+	return members, nil
+}
+
+func restoreMembers(ctx context.Context, ctl *etcdctl, dir string, members []member) ([]restoredMember, error) {
+	initialCluster := make([]string, 0, len(members))
+	for _, m := range members {
+		for _, peerURL := range m.PeerURLs {
+			initialCluster = append(initialCluster, m.Name+"="+peerURL)
+		}
+	}
+
+	restored := make([]restoredMember, 0, len(members))
+	for _, m := range members {
+		clientURL, err := url.Parse(m.ClientURLs[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid client url for etcd member %s: %v", m.Name, err)
+		}
+
+		dataDir := filepath.Join(restoreDir, m.Name)
+		if err = ctl.snapshotRestore(ctx, snapshotFile, m, strings.Join(initialCluster, ","), dataDir); err != nil {
+			return nil, err
+		}
+		data, err := tarGzDir(filepath.Join(dir, dataDir))
+		if err != nil {
+			return nil, fmt.Errorf("error packaging data of etcd member %s: %v", m.Name, err)
+		}
+
+		restored = append(restored, restoredMember{member: m, host: clientURL.Hostname(), data: data})
+	}
+	return restored, nil
+}
+
+// tarGzDir returns a gzipped tarball with the content of dir, with paths relative to it
+func tarGzDir(dir string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,185 @@
+package etcdbackup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+func etcdMachine(name, address string) types.Machine {
+	m := machine(address, false)
+	m.Metadata.Name = name
+	m.Metadata.Labels["cluster.x-k8s.io/etcd-cluster"] = "test-cluster-etcd"
+	return m
+}
+
+func (tt *etcdTest) expectEtcdMachines(machines ...types.Machine) {
+	tt.kubectl.EXPECT().GetMachines(tt.ctx, tt.managementCluster, "test-cluster").Return(machines, nil)
+}
+
+func (tt *etcdTest) expectExternalEtcdMachines() {
+	tt.expectEtcdMachines(
+		etcdMachine("test-cluster-etcd-a", "10.0.0.5"),
+		etcdMachine("test-cluster-etcd-b", "10.0.0.6"),
+		machine("10.0.0.1", true),
+		machine("10.0.0.10", false),
+	)
+}
+
+func (tt *etcdTest) expectSnapshotRestore(name, host string) {
+	tt.expectEtcdctl("https://10.0.0.5:2379,https://10.0.0.6:2379", []string{
+		"snapshot", "restore", "/etcd-backup/snapshot.db",
+		"--name=" + name,
+		"--initial-cluster=test-cluster-etcd-a=https://10.0.0.5:2380,test-cluster-etcd-b=https://10.0.0.6:2380",
+		"--initial-advertise-peer-urls=https://" + host + ":2380",
+		"--data-dir=/etcd-backup/restore/" + name,
+	}, func(dir string) (string, error) {
+		memberDir := filepath.Join(dir, "restore", name, "member", "snap")
+		tt.Expect(os.MkdirAll(memberDir, 0o700)).To(Succeed())
+		return "", ioutil.WriteFile(filepath.Join(memberDir, "db"), []byte(name), 0o600)
+	})
+}
+
+func (tt *etcdTest) saveSnapshot() string {
+	name := snapshotAt("test-cluster", 1)
+	tt.Expect(tt.store.Save(tt.ctx, name, strings.NewReader("etcd snapshot"))).To(Succeed())
+	return name
+}
+
+func (tt *etcdTest) expectDataReplaced(host, member string) *gomock.Call {
+	return tt.nodes.EXPECT().Run(tt.ctx, host, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, command string, stdin []byte) error {
+			tt.Expect(command).To(ContainSubstring("sudo tar -xzf - -C /var/lib/etcd"))
+			tt.Expect(untarFile(tt.WithT, stdin, "member/snap/db")).To(Equal(member))
+			return nil
+		},
+	)
+}
+
+func untarFile(g *WithT, content []byte, name string) string {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	g.Expect(err).NotTo(HaveOccurred())
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		g.Expect(err).NotTo(HaveOccurred())
+		if header.Name == name {
+			data, err := ioutil.ReadAll(tr)
+			g.Expect(err).NotTo(HaveOccurred())
+			return string(data)
+		}
+	}
+}
+
+func TestRestoreExternalEtcd(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	name := tt.saveSnapshot()
+	tt.expectExternalEtcd()
+	tt.expectExternalEtcdMachines()
+	tt.expectSnapshotRestore("test-cluster-etcd-a", "10.0.0.5")
+	tt.expectSnapshotRestore("test-cluster-etcd-b", "10.0.0.6")
+	gomock.InOrder(
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.5", "sudo systemctl stop etcd", nil),
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.6", "sudo systemctl stop etcd", nil),
+		tt.expectDataReplaced("10.0.0.5", "test-cluster-etcd-a"),
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.5", "sudo systemctl start etcd", nil),
+		tt.expectDataReplaced("10.0.0.6", "test-cluster-etcd-b"),
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.6", "sudo systemctl start etcd", nil),
+	)
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, name)).To(Succeed())
+}
+
+func TestRestoreStackedEtcdStopsStaticPods(t *testing.T) {
+	tt := newEtcdTest(t)
+	name := tt.saveSnapshot()
+	tt.expectStackedEtcd()
+	controlPlane := machine("10.0.0.1", true)
+	controlPlane.Metadata.Name = "cp-a"
+	tt.expectEtcdMachines(controlPlane, machine("10.0.0.10", false))
+	tt.runner.EXPECT().RunContainer(tt.ctx, etcdImage, "etcdctl", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _ string, volumes []string, _ ...string) (bytes.Buffer, error) {
+			dir := strings.TrimSuffix(volumes[0], ":/etcd-backup")
+			return bytes.Buffer{}, os.MkdirAll(filepath.Join(dir, "restore", "cp-a", "member"), 0o700)
+		},
+	)
+	gomock.InOrder(
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.1", gomock.Any(), nil).Do(func(_ context.Context, _, command string, _ []byte) {
+			tt.Expect(command).To(HavePrefix("sudo mv /etc/kubernetes/manifests/etcd.yaml /etc/kubernetes/etcd.yaml.eks-a-restore"))
+		}),
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.1", gomock.Any(), gomock.Not(gomock.Nil())),
+		tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.1", "sudo mv /etc/kubernetes/etcd.yaml.eks-a-restore /etc/kubernetes/manifests/etcd.yaml", nil),
+	)
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, name)).To(Succeed())
+}
+
+func TestRestoreStopsBeforeReplacingIfAMemberCantBeStopped(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	name := tt.saveSnapshot()
+	tt.expectExternalEtcd()
+	tt.expectExternalEtcdMachines()
+	tt.expectSnapshotRestore("test-cluster-etcd-a", "10.0.0.5")
+	tt.expectSnapshotRestore("test-cluster-etcd-b", "10.0.0.6")
+	tt.nodes.EXPECT().Run(tt.ctx, "10.0.0.5", "sudo systemctl stop etcd", nil).Return(errors.New("connection refused"))
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, name)).To(MatchError("error stopping etcd member test-cluster-etcd-a: connection refused"))
+}
+
+func TestRestoreWithoutQuorum(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	name := tt.saveSnapshot()
+	tt.expectExternalEtcd()
+	tt.expectExternalEtcdMachines()
+	// etcd members can't be listed when the cluster lost quorum, the restore doesn't depend on it
+	tt.expectEtcdctl("https://10.0.0.5:2379,https://10.0.0.6:2379", []string{"member", "list", "--write-out=json"}, func(string) (string, error) {
+		return "", errors.New("context deadline exceeded")
+	}).AnyTimes()
+	tt.expectSnapshotRestore("test-cluster-etcd-a", "10.0.0.5")
+	tt.expectSnapshotRestore("test-cluster-etcd-b", "10.0.0.6")
+	tt.nodes.EXPECT().Run(tt.ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(6)
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, name)).To(Succeed())
+}
+
+func TestRestoreMemberNotStarted(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	name := tt.saveSnapshot()
+	tt.expectExternalEtcd()
+	notStarted := etcdMachine("test-cluster-etcd-b", "")
+	notStarted.Status.Addresses = nil
+	tt.expectEtcdMachines(etcdMachine("test-cluster-etcd-a", "10.0.0.5"), notStarted)
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, name)).To(MatchError(ContainSubstring("all members must be running to restore a snapshot")))
+}
+
+func TestRestoreSnapshotNotFound(t *testing.T) {
+	tt := newEtcdTest(t)
+	tt.useExternalEtcd()
+	tt.expectExternalEtcd()
+
+	restorer := etcdbackup.NewRestorer(tt.kubectl, tt.runner, tt.nodes, tt.store)
+	tt.Expect(restorer.Restore(tt.ctx, tt.managementCluster, tt.clusterSpec, "missing.db")).To(MatchError(ContainSubstring("error opening etcd snapshot")))
+}
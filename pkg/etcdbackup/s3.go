@@ -0,0 +1,115 @@
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const defaultS3Region = "us-east-1"
+
+// S3Config defines an S3 or S3-compatible bucket. Credentials are read with the default AWS SDK chain,
+// usually from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars
+type S3Config struct {
+	Bucket string
+	Prefix string
+	Region string
+	// Endpoint is the URL of an S3-compatible service, like MinIO. Path-style addressing is used when set
+	Endpoint string
+}
+
+// S3Store keeps snapshots as objects in a bucket
+type S3Store struct {
+	client s3iface.S3API
+	bucket string
+	prefix string
+}
+
+func NewS3Store(config S3Config) (*S3Store, error) {
+	region := config.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	awsConfig := aws.NewConfig().WithRegion(region)
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint).WithS3ForcePathStyle(true)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws session for etcd backups: %v", err)
+	}
+	return NewS3StoreWithClient(s3.New(sess), config.Bucket, config.Prefix), nil
+}
+
+func NewS3StoreWithClient(client s3iface.S3API, bucket, prefix string) *S3Store {
+	return &S3Store{client: client, bucket: bucket, prefix: strings.Trim(prefix, "/")}
+}
+
+func (s *S3Store) key(name string) string {
+	return path.Join(s.prefix, name)
+}
+
+func (s *S3Store) Save(ctx context.Context, name string, snapshot io.Reader) error {
+	_, err := s3manager.NewUploaderWithClient(s.client).UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   snapshot,
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading etcd snapshot %s to bucket %s: %v", name, s.bucket, err)
+	}
+	return nil
+}
+
+func (s *S3Store) Load(ctx context.Context, name string) (io.ReadCloser, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error downloading etcd snapshot %s from bucket %s: %v", name, s.bucket, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3Store) List(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+
+	var names []string
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, o := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(o.Key), prefix)
+			if !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing bucket %s: %v", s.bucket, err)
+	}
+	return names, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, name string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	return err
+}
@@ -0,0 +1,74 @@
+package etcdbackup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshPort    = "22"
+	sshTimeout = 30 * time.Second
+)
+
+// NodeRunner runs shell commands in the cluster nodes
+type NodeRunner interface {
+	Run(ctx context.Context, host, command string, stdin []byte) error
+}
+
+// SSHRunner runs commands in the nodes over ssh, with the key pair configured in the machine configs
+type SSHRunner struct {
+	config *ssh.ClientConfig
+}
+
+func NewSSHRunner(user string, privateKey []byte) (*SSHRunner, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ssh private key: %v", err)
+	}
+	return &SSHRunner{
+		config: &ssh.ClientConfig{
+			User: user,
+			Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			// Node host keys are generated on first boot and aren't known by the CLI
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			Timeout:         sshTimeout,
+		},
+	}, nil
+}
+
+func (r *SSHRunner) Run(ctx context.Context, host, command string, stdin []byte) error {
+	client, err := ssh.Dial("tcp", net.JoinHostPort(host, sshPort), r.config)
+	if err != nil {
+		return fmt.Errorf("error connecting to node %s: %v", host, err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("error opening ssh session in node %s: %v", host, err)
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stdin = bytes.NewReader(stdin)
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(command)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-done:
+	}
+	if err != nil {
+		return fmt.Errorf("error running command in node %s: %v: %s", host, err, stderr.String())
+	}
+	return nil
+}
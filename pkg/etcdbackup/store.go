@@ -0,0 +1,155 @@
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetention    = 7
+	snapshotExtension   = ".db"
+	snapshotTimeLayout  = "20060102T150405Z"
+	snapshotNamePattern = "%s-etcd-%s" + snapshotExtension
+)
+
+// Store saves etcd snapshots by name
+type Store interface {
+	Save(ctx context.Context, name string, snapshot io.Reader) error
+	Load(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the names of all the snapshots in the store
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// SnapshotName returns the name of a snapshot of clusterName taken at t.
+// Names of the same cluster sort in the order the snapshots were taken
+func SnapshotName(clusterName string, t time.Time) string {
+	return fmt.Sprintf(snapshotNamePattern, clusterName, t.UTC().Format(snapshotTimeLayout))
+}
+
+// ClusterSnapshots returns the names of the snapshots of clusterName in store, from oldest to newest
+func ClusterSnapshots(ctx context.Context, store Store, clusterName string) ([]string, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing etcd snapshots: %v", err)
+	}
+
+	prefix := clusterName + "-etcd-"
+	snapshots := make([]string, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, snapshotExtension) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), snapshotExtension)
+		if _, err := time.Parse(snapshotTimeLayout, timestamp); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, name)
+	}
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// LatestSnapshot returns the name of the newest snapshot of clusterName in store
+func LatestSnapshot(ctx context.Context, store Store, clusterName string) (string, error) {
+	snapshots, err := ClusterSnapshots(ctx, store, clusterName)
+	if err != nil {
+		return "", err
+	}
+	if len(snapshots) == 0 {
+		return "", fmt.Errorf("no etcd snapshots found for cluster %s", clusterName)
+	}
+	return snapshots[len(snapshots)-1], nil
+}
+
+// Prune deletes the oldest snapshots of clusterName, keeping the newest retention ones. A retention of 0 keeps all of them
+func Prune(ctx context.Context, store Store, clusterName string, retention int) ([]string, error) {
+	if retention <= 0 {
+		return nil, nil
+	}
+	snapshots, err := ClusterSnapshots(ctx, store, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) <= retention {
+		return nil, nil
+	}
+
+	deleted := snapshots[:len(snapshots)-retention]
+	for _, name := range deleted {
+		if err := store.Delete(ctx, name); err != nil {
+			return nil, fmt.Errorf("error deleting etcd snapshot %s: %v", name, err)
+		}
+	}
+	return deleted, nil
+}
+
+// LocalStore keeps snapshots as files in a directory
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) Save(_ context.Context, name string, snapshot io.Reader) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("error creating etcd backup directory: %v", err)
+	}
+
+	// write to a temp file first so a failed backup never leaves a partial snapshot behind
+	f, err := ioutil.TempFile(s.dir, "."+name)
+	if err != nil {
+		return fmt.Errorf("error creating etcd snapshot file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = io.Copy(f, snapshot); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing etcd snapshot %s: %v", name, err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("error writing etcd snapshot %s: %v", name, err)
+	}
+	if err = os.Rename(f.Name(), filepath.Join(s.dir, name)); err != nil {
+		return fmt.Errorf("error writing etcd snapshot %s: %v", name, err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Load(_ context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("error opening etcd snapshot: %v", err)
+	}
+	return f, nil
+}
+
+func (s *LocalStore) List(_ context.Context) ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(s.dir, name))
+}
@@ -0,0 +1,171 @@
+package etcdbackup_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/etcdbackup"
+)
+
+func snapshotAt(clusterName string, hour int) string {
+	return etcdbackup.SnapshotName(clusterName, time.Date(2021, 9, 1, hour, 0, 0, 0, time.UTC))
+}
+
+func TestSnapshotName(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(snapshotAt("test-cluster", 14)).To(Equal("test-cluster-etcd-20210901T140000Z.db"))
+}
+
+func testStore(t *testing.T, store etcdbackup.Store) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	for _, name := range []string{
+		snapshotAt("test-cluster", 3),
+		snapshotAt("test-cluster", 1),
+		snapshotAt("test-cluster", 2),
+		snapshotAt("test-cluster-2", 4),
+	} {
+		g.Expect(store.Save(ctx, name, strings.NewReader("snapshot "+name))).To(Succeed())
+	}
+
+	latest, err := etcdbackup.LatestSnapshot(ctx, store, "test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(latest).To(Equal(snapshotAt("test-cluster", 3)))
+
+	snapshot, err := store.Load(ctx, latest)
+	g.Expect(err).NotTo(HaveOccurred())
+	content, err := ioutil.ReadAll(snapshot)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshot.Close()).To(Succeed())
+	g.Expect(string(content)).To(Equal("snapshot " + latest))
+
+	deleted, err := etcdbackup.Prune(ctx, store, "test-cluster", 2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(ConsistOf(snapshotAt("test-cluster", 1)))
+
+	snapshots, err := etcdbackup.ClusterSnapshots(ctx, store, "test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshots).To(Equal([]string{snapshotAt("test-cluster", 2), snapshotAt("test-cluster", 3)}))
+
+	otherSnapshots, err := etcdbackup.ClusterSnapshots(ctx, store, "test-cluster-2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherSnapshots).To(HaveLen(1))
+}
+
+func TestLocalStore(t *testing.T) {
+	testStore(t, etcdbackup.NewLocalStore(t.TempDir()))
+}
+
+func TestLocalStoreNoSnapshots(t *testing.T) {
+	g := NewWithT(t)
+	store := etcdbackup.NewLocalStore(t.TempDir() + "/missing")
+
+	_, err := etcdbackup.LatestSnapshot(context.Background(), store, "test-cluster")
+	g.Expect(err).To(MatchError("no etcd snapshots found for cluster test-cluster"))
+}
+
+func TestPruneKeepAll(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	store := etcdbackup.NewLocalStore(t.TempDir())
+	g.Expect(store.Save(ctx, snapshotAt("test-cluster", 1), strings.NewReader("snapshot"))).To(Succeed())
+
+	deleted, err := etcdbackup.Prune(ctx, store, "test-cluster", 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeEmpty())
+}
+
+func TestS3Store(t *testing.T) {
+	server := httptest.NewServer(newFakeS3("etcd-backups"))
+	defer server.Close()
+	os.Setenv("AWS_ACCESS_KEY_ID", "minio")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "minio123")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+	store, err := etcdbackup.NewS3Store(etcdbackup.S3Config{
+		Bucket:   "etcd-backups",
+		Prefix:   "clusters/",
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	testStore(t, store)
+}
+
+// fakeS3 is a minimal S3-compatible server, like a local MinIO, with a single bucket and path-style addressing
+type fakeS3 struct {
+	bucket  string
+	lock    sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}}
+}
+
+type listBucketResult struct {
+	XMLName     xml.Name `xml:"ListBucketResult"`
+	Contents    []object `xml:"Contents"`
+	IsTruncated bool     `xml:"IsTruncated"`
+}
+
+type object struct {
+	Key string `xml:"Key"`
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path != s.bucket && !strings.HasPrefix(path, s.bucket+"/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(path, s.bucket), "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		result := listBucketResult{}
+		for k := range s.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				result.Contents = append(result.Contents, object{Key: k})
+			}
+		}
+		sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+		body, _ := xml.Marshal(result)
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(body)
+	case r.Method == http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		s.objects[key] = body
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodGet:
+		body, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = bytes.NewReader(body).WriteTo(w)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
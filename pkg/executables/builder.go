@@ -16,38 +16,46 @@ type ExecutableBuilder struct {
 	useDocker bool
 	image     string
 	mountDir  string
+	env       map[string]string
+}
+
+// WithEnv sets env vars for every command run in the tools image, like the cluster proxy configuration.
+// Local executables use the environment of the CLI process.
+func (b *ExecutableBuilder) WithEnv(env map[string]string) *ExecutableBuilder {
+	b.env = env
+	return b
 }
 
 func (b *ExecutableBuilder) BuildKindExecutable(writer filewriter.FileWriter) *Kind {
-	return NewKind(buildExecutable(kindPath, b.useDocker, b.image, b.mountDir), writer)
+	return NewKind(b.buildExecutable(kindPath), writer)
 }
 
 func (b *ExecutableBuilder) BuildClusterAwsAdmExecutable() *Clusterawsadm {
-	return NewClusterawsadm(buildExecutable(clusterAwsAdminPath, b.useDocker, b.image, b.mountDir))
+	return NewClusterawsadm(b.buildExecutable(clusterAwsAdminPath))
 }
 
 func (b *ExecutableBuilder) BuildClusterCtlExecutable(writer filewriter.FileWriter) *Clusterctl {
-	return NewClusterctl(buildExecutable(clusterCtlPath, b.useDocker, b.image, b.mountDir), writer)
+	return NewClusterctl(b.buildExecutable(clusterCtlPath), writer)
 }
 
 func (b *ExecutableBuilder) BuildKubectlExecutable() *Kubectl {
-	return NewKubectl(buildExecutable(kubectlPath, b.useDocker, b.image, b.mountDir))
+	return NewKubectl(b.buildExecutable(kubectlPath))
 }
 
 func (b *ExecutableBuilder) BuildGovcExecutable(writer filewriter.FileWriter) *Govc {
-	return NewGovc(buildExecutable(govcPath, b.useDocker, b.image, b.mountDir), writer)
+	return NewGovc(b.buildExecutable(govcPath), writer)
 }
 
 func (b *ExecutableBuilder) BuildAwsCli() *AwsCli {
-	return NewAwsCli(buildExecutable(awsCliPath, b.useDocker, b.image, b.mountDir))
+	return NewAwsCli(b.buildExecutable(awsCliPath))
 }
 
 func (b *ExecutableBuilder) BuildFluxExecutable() *Flux {
-	return NewFlux(buildExecutable(fluxPath, b.useDocker, b.image, b.mountDir))
+	return NewFlux(b.buildExecutable(fluxPath))
 }
 
 func (b *ExecutableBuilder) BuildTroubleshootExecutable() *Troubleshoot {
-	return NewTroubleshoot(buildExecutable(troubleshootPath, b.useDocker, b.image, b.mountDir))
+	return NewTroubleshoot(b.buildExecutable(troubleshootPath))
 }
 
 func BuildSonobuoyExecutable() *Sonobuoy {
@@ -62,11 +70,11 @@ func BuildDockerExecutable() *Docker {
 	})
 }
 
-func buildExecutable(cli string, useDocker bool, image string, mountDir string) Executable {
-	if !useDocker {
+func (b *ExecutableBuilder) buildExecutable(cli string) Executable {
+	if !b.useDocker {
 		return NewExecutable(cli)
 	} else {
-		return NewDockerExecutable(cli, image, mountDir)
+		return newDockerExecutableWithEnv(cli, b.image, b.mountDir, b.env)
 	}
 }
 
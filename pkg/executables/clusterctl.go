@@ -23,7 +23,6 @@ import (
 const (
 	clusterCtlPath                = "clusterctl"
 	clusterctlConfigFile          = "clusterctl_tmp.yaml"
-	capiPrefix                    = "/generated/overrides"
 	etcdadmBootstrapProviderName  = "etcdadm-bootstrap"
 	etcdadmControllerProviderName = "etcdadm-controller"
 	kubeadmBootstrapProviderName  = "kubeadm"
@@ -60,18 +59,9 @@ func imageRepository(image v1alpha1.Image) string {
 // This method will write the configuration files
 // used by cluster api to install components.
 // See: https://cluster-api.sigs.k8s.io/clusterctl/configuration.html
-func buildOverridesLayer(clusterSpec *cluster.Spec, clusterName string, provider providers.Provider) error {
+func buildOverridesLayer(clusterSpec *cluster.Spec, prefix string, provider providers.Provider) error {
 	bundle := clusterSpec.VersionsBundle
 
-	// Adding cluster name to path temporarily following suggestion.
-	//
-	// This adds an implicit dependency between this method
-	// and the writer passed to NewClusterctl
-	// Ideally the writer implementation should be modified to
-	// accept a path and file name and it should create the path in case it
-	// does not exists.
-	prefix := filepath.Join(clusterName, generatedDir, overridesDir)
-
 	infraBundles := []types.InfrastructureBundle{
 		{
 			FolderName: filepath.Join("bootstrap-kubeadm", bundle.Bootstrap.Version),
@@ -212,12 +202,38 @@ func (c *Clusterctl) InitInfrastructure(ctx context.Context, clusterSpec *cluste
 }
 
 func (c *Clusterctl) buildConfig(clusterSpec *cluster.Spec, clusterName string, provider providers.Provider) (*clusterctlConfiguration, error) {
-	t := templater.New(c.writer)
+	// Adding cluster name to path temporarily following suggestion.
+	//
+	// This adds an implicit dependency between this method
+	// and the writer passed to NewClusterctl
+	// Ideally the writer implementation should be modified to
+	// accept a path and file name and it should create the path in case it
+	// does not exists.
+	filePath, err := WriteClusterctlConfig(c.writer, clusterSpec, provider, filepath.Join(clusterName, generatedDir, overridesDir))
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := clusterSpec.VersionsBundle
+	return &clusterctlConfiguration{
+		configFile:               filePath,
+		bootstrapVersion:         fmt.Sprintf("%s:%s", kubeadmBootstrapProviderName, bundle.Bootstrap.Version),
+		controlPlaneVersion:      fmt.Sprintf("kubeadm:%s", bundle.ControlPlane.Version),
+		coreVersion:              fmt.Sprintf("cluster-api:%s", bundle.ClusterAPI.Version),
+		etcdadmBootstrapVersion:  fmt.Sprintf("%s:%s", etcdadmBootstrapProviderName, bundle.ExternalEtcdBootstrap.Version),
+		etcdadmControllerVersion: fmt.Sprintf("%s:%s", etcdadmControllerProviderName, bundle.ExternalEtcdController.Version),
+	}, nil
+}
+
+// WriteClusterctlConfig writes the overrides layer with the providers of the bundle to overridesFolder and
+// the clusterctl configuration file pointing to it with writer. It returns the path of the configuration file
+func WriteClusterctlConfig(writer filewriter.FileWriter, clusterSpec *cluster.Spec, provider providers.Provider, overridesFolder string) (string, error) {
+	t := templater.New(writer)
 	bundle := clusterSpec.VersionsBundle
 
-	path, err := os.Getwd()
+	dir, err := filepath.Abs(overridesFolder)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	data := map[string]string{
@@ -268,25 +284,45 @@ func (c *Clusterctl) buildConfig(clusterSpec *cluster.Spec, clusterName string,
 		"KubeadmBootstrapProviderVersion":                 bundle.Bootstrap.Version,
 		"EtcdadmBootstrapProviderVersion":                 bundle.ExternalEtcdBootstrap.Version,
 		"EtcdadmControllerProviderVersion":                bundle.ExternalEtcdController.Version,
-		"dir":                                             path + "/" + clusterName + capiPrefix,
+		"dir":                                             dir,
 	}
 
 	filePath, err := t.WriteToFile(clusterctlConfigTemplate, data, clusterctlConfigFile)
 	if err != nil {
-		return nil, fmt.Errorf("error generating configuration file for clusterctl: %v", err)
+		return "", fmt.Errorf("error generating configuration file for clusterctl: %v", err)
 	}
-	if err := buildOverridesLayer(clusterSpec, clusterName, provider); err != nil {
-		return nil, err
+	if err := buildOverridesLayer(clusterSpec, overridesFolder, provider); err != nil {
+		return "", err
 	}
 
-	return &clusterctlConfiguration{
-		configFile:               filePath,
-		bootstrapVersion:         fmt.Sprintf("%s:%s", kubeadmBootstrapProviderName, bundle.Bootstrap.Version),
-		controlPlaneVersion:      fmt.Sprintf("kubeadm:%s", bundle.ControlPlane.Version),
-		coreVersion:              fmt.Sprintf("cluster-api:%s", bundle.ClusterAPI.Version),
-		etcdadmBootstrapVersion:  fmt.Sprintf("%s:%s", etcdadmBootstrapProviderName, bundle.ExternalEtcdBootstrap.Version),
-		etcdadmControllerVersion: fmt.Sprintf("%s:%s", etcdadmControllerProviderName, bundle.ExternalEtcdController.Version),
-	}, nil
+	return filePath, nil
+}
+
+// ClusterctlUpgradeTargets are the providers to upgrade with clusterctl upgrade apply,
+// as namespace/name:version
+type ClusterctlUpgradeTargets struct {
+	Core           string
+	ControlPlane   string
+	Infrastructure string
+	Bootstrap      []string
+}
+
+// NewClusterctlUpgradeTargets returns the providers changed in changeDiff. The ones that don't change are empty
+func NewClusterctlUpgradeTargets(changeDiff *clusterapi.CAPIChangeDiff) ClusterctlUpgradeTargets {
+	targets := ClusterctlUpgradeTargets{}
+	if changeDiff.ControlPlane != nil {
+		targets.ControlPlane = fmt.Sprintf("%s/kubeadm:%s", constants.CapiKubeadmControlPlaneSystemNamespace, changeDiff.ControlPlane.NewVersion)
+	}
+	if changeDiff.Core != nil {
+		targets.Core = fmt.Sprintf("%s/cluster-api:%s", constants.CapiSystemNamespace, changeDiff.Core.NewVersion)
+	}
+	if changeDiff.InfrastructureProvider != nil {
+		targets.Infrastructure = fmt.Sprintf("%s/%s:%s", providerNamespaces[changeDiff.InfrastructureProvider.ComponentName], changeDiff.InfrastructureProvider.ComponentName, changeDiff.InfrastructureProvider.NewVersion)
+	}
+	for _, bootstrapProvider := range changeDiff.BootstrapProviders {
+		targets.Bootstrap = append(targets.Bootstrap, fmt.Sprintf("%s/%s:%s", providerNamespaces[bootstrapProvider.ComponentName], bootstrapProvider.ComponentName, bootstrapProvider.NewVersion))
+	}
+	return targets
 }
 
 var providerNamespaces = map[string]string{
@@ -311,22 +347,21 @@ func (c *Clusterctl) Upgrade(ctx context.Context, managementCluster *types.Clust
 		"--kubeconfig", managementCluster.KubeconfigFile,
 	}
 
-	if changeDiff.ControlPlane != nil {
-		upgradeCommand = append(upgradeCommand, "--control-plane", fmt.Sprintf("%s/kubeadm:%s", constants.CapiKubeadmControlPlaneSystemNamespace, changeDiff.ControlPlane.NewVersion))
+	targets := NewClusterctlUpgradeTargets(changeDiff)
+	if targets.ControlPlane != "" {
+		upgradeCommand = append(upgradeCommand, "--control-plane", targets.ControlPlane)
 	}
 
-	if changeDiff.Core != nil {
-		upgradeCommand = append(upgradeCommand, "--core", fmt.Sprintf("%s/cluster-api:%s", constants.CapiSystemNamespace, changeDiff.Core.NewVersion))
+	if targets.Core != "" {
+		upgradeCommand = append(upgradeCommand, "--core", targets.Core)
 	}
 
-	if changeDiff.InfrastructureProvider != nil {
-		newInfraProvider := fmt.Sprintf("%s/%s:%s", providerNamespaces[changeDiff.InfrastructureProvider.ComponentName], changeDiff.InfrastructureProvider.ComponentName, changeDiff.InfrastructureProvider.NewVersion)
-		upgradeCommand = append(upgradeCommand, "--infrastructure", newInfraProvider)
+	if targets.Infrastructure != "" {
+		upgradeCommand = append(upgradeCommand, "--infrastructure", targets.Infrastructure)
 	}
 
-	for _, bootstrapProvider := range changeDiff.BootstrapProviders {
-		newBootstrapProvider := fmt.Sprintf("%s/%s:%s", providerNamespaces[bootstrapProvider.ComponentName], bootstrapProvider.ComponentName, bootstrapProvider.NewVersion)
-		upgradeCommand = append(upgradeCommand, "--bootstrap", newBootstrapProvider)
+	for _, bootstrapProvider := range targets.Bootstrap {
+		upgradeCommand = append(upgradeCommand, "--bootstrap", bootstrapProvider)
 	}
 
 	providerEnvMap, err := provider.EnvMap()
@@ -6,6 +6,7 @@ import (
 	_ "embed"
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -45,7 +46,8 @@ func newClusterctlTest(t *testing.T) *clusterctlTest {
 		WithT: NewWithT(t),
 		ctx:   context.Background(),
 		cluster: &types.Cluster{
-			Name:           "cluster-name",
+			// the overrides layer is written to a folder named after the cluster
+			Name:           filepath.Join(t.TempDir(), "cluster-name"),
 			KubeconfigFile: "config/c.kubeconfig",
 		},
 		e:              e,
@@ -1,8 +1,10 @@
 package executables
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -98,3 +100,15 @@ func (d *Docker) Login(ctx context.Context, endpoint, username, password string)
 	_, err := d.executable.ExecuteWithStdin(ctx, []byte(password), params...)
 	return err
 }
+
+// RunContainer runs entrypoint in a short lived container of image, in the host network and as the current user,
+// with volumes (in the host-dir:container-dir format) mounted
+func (d *Docker) RunContainer(ctx context.Context, image, entrypoint string, volumes []string, args ...string) (bytes.Buffer, error) {
+	params := []string{"run", "--rm", "--network", "host", "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()), "--entrypoint", entrypoint}
+	for _, v := range volumes {
+		params = append(params, "-v", v)
+	}
+	params = append(params, image)
+	params = append(params, args...)
+	return d.executable.Execute(ctx, params...)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"reflect"
 	"testing"
 
@@ -78,3 +79,23 @@ func TestDockerAllocatedMemory(t *testing.T) {
 		t.Fatalf("Docker.AllocatedMemory() error = %v, want %v", err, mem)
 	}
 }
+
+func TestDockerRunContainer(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	executable := mockexecutables.NewMockExecutable(mockCtrl)
+	user := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	executable.EXPECT().Execute(
+		ctx, "run", "--rm", "--network", "host", "--user", user, "--entrypoint", "etcdctl",
+		"-v", "/tmp/backup:/etcd-backup", "etcd:v3.4.16", "version",
+	).Return(*bytes.NewBufferString("etcdctl version: 3.4.16"), nil)
+	d := executables.NewDocker(executable)
+	out, err := d.RunContainer(ctx, "etcd:v3.4.16", "etcdctl", []string{"/tmp/backup:/etcd-backup"}, "version")
+	if err != nil {
+		t.Fatalf("Docker.RunContainer() error = %v, want nil", err)
+	}
+	if out.String() != "etcdctl version: 3.4.16" {
+		t.Fatalf("Docker.RunContainer() output = %s, want etcdctl version: 3.4.16", out.String())
+	}
+}
@@ -30,6 +30,7 @@ type linuxDockerExecutable struct {
 	cli      string
 	image    string
 	mountDir string
+	env      map[string]string
 }
 
 type Executable interface {
@@ -55,6 +56,15 @@ func NewDockerExecutable(cli, image string, mountDir string) Executable {
 	}
 }
 
+func newDockerExecutableWithEnv(cli, image, mountDir string, env map[string]string) Executable {
+	return &linuxDockerExecutable{
+		cli:      cli,
+		image:    image,
+		mountDir: mountDir,
+		env:      env,
+	}
+}
+
 func (e *linuxDockerExecutable) workingDirectory() (string, error) {
 	path, err := filepath.Abs(e.mountDir)
 	if err != nil {
@@ -91,6 +101,12 @@ func (e *linuxDockerExecutable) buildCommand(envs map[string]string, cli string,
 	}
 
 	var envVars []string
+	for k, v := range e.env {
+		if _, ok := envs[k]; ok {
+			continue
+		}
+		envVars = append(envVars, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
 	for k, v := range envs {
 		envVars = append(envVars, "-e", fmt.Sprintf("%s=%s", k, v))
 	}
@@ -283,9 +283,16 @@ func (g *Govc) DeployTemplateFromLibrary(ctx context.Context, templateDir, templ
 	return nil
 }
 
+// ImportTemplate imports an OVA in the content library. ovaURL can be a url, pulled directly by vCenter,
+// or a local file uploaded by govc.
 func (g *Govc) ImportTemplate(ctx context.Context, library, ovaURL, name string) error {
 	logger.V(4).Info("Importing template", "ova", ovaURL, "templateName", name)
-	if _, err := g.exec(ctx, "library.import", "-k", "-pull", "-n", name, library, ovaURL); err != nil {
+	params := []string{"library.import", "-k"}
+	if strings.HasPrefix(ovaURL, "http://") || strings.HasPrefix(ovaURL, "https://") {
+		params = append(params, "-pull")
+	}
+	params = append(params, "-n", name, library, ovaURL)
+	if _, err := g.exec(ctx, params...); err != nil {
 		return fmt.Errorf("error importing template: %v", err)
 	}
 	return nil
@@ -770,6 +777,64 @@ func (g *Govc) ListCategories(ctx context.Context) ([]string, error) {
 	return categoryNames, nil
 }
 
+// ListDatacenters returns the inventory paths of the datacenters in vCenter, without the leading slash
+func (g *Govc) ListDatacenters(ctx context.Context) ([]string, error) {
+	paths, err := g.find(ctx, "/", "Datacenter")
+	if err != nil {
+		return nil, err
+	}
+	datacenters := make([]string, 0, len(paths))
+	for _, p := range paths {
+		datacenters = append(datacenters, strings.TrimPrefix(p, "/"))
+	}
+	return datacenters, nil
+}
+
+// ListNetworks returns the paths of the networks in datacenter
+func (g *Govc) ListNetworks(ctx context.Context, datacenter string) ([]string, error) {
+	return g.find(ctx, "/"+datacenter+"/network", "Network")
+}
+
+// ListDatastores returns the paths of the datastores in datacenter
+func (g *Govc) ListDatastores(ctx context.Context, datacenter string) ([]string, error) {
+	return g.find(ctx, "/"+datacenter+"/datastore", "Datastore")
+}
+
+// ListResourcePools returns the paths of the resource pools in datacenter
+func (g *Govc) ListResourcePools(ctx context.Context, datacenter string) ([]string, error) {
+	return g.find(ctx, "/"+datacenter+"/host", "ResourcePool")
+}
+
+// ListFolders returns the paths of the VM folders in datacenter
+func (g *Govc) ListFolders(ctx context.Context, datacenter string) ([]string, error) {
+	return g.find(ctx, "/"+datacenter+"/vm", "Folder")
+}
+
+// ListTemplates returns the paths of the VM templates in datacenter
+func (g *Govc) ListTemplates(ctx context.Context, datacenter string) ([]string, error) {
+	return g.find(ctx, "/"+datacenter+"/vm", "VirtualMachine", "-config.template", "true")
+}
+
+func (g *Govc) find(ctx context.Context, root, kind string, filters ...string) ([]string, error) {
+	params := append([]string{"find", "-json", root, "-type", kind}, filters...)
+	response, err := g.exec(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("govc returned error when listing %s objects in %s: %v", kind, root, err)
+	}
+
+	pathsJson := strings.TrimSuffix(response.String(), "\n")
+	if pathsJson == "null" || pathsJson == "" {
+		return nil, nil
+	}
+
+	paths := make([]string, 0)
+	if err = json.Unmarshal([]byte(pathsJson), &paths); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling govc response from listing %s objects: %v", kind, err)
+	}
+
+	return paths, nil
+}
+
 type objectType string
 
 const virtualMachine objectType = "VirtualMachine"
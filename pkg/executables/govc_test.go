@@ -889,7 +889,7 @@ func TestCreateCategoryForVMError(t *testing.T) {
 }
 
 func TestImportTemplateSuccess(t *testing.T) {
-	ovaURL := "ovaURL"
+	ovaURL := "https://amazonaws.com/artifacts/ubuntu.ova"
 	name := "name"
 	ctx := context.Background()
 
@@ -901,8 +901,21 @@ func TestImportTemplateSuccess(t *testing.T) {
 	}
 }
 
+func TestImportTemplateFromFileSuccess(t *testing.T) {
+	ovaFile := "cluster-name/ovas/ubuntu.ova"
+	name := "name"
+	ctx := context.Background()
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "library.import", "-k", "-n", name, templateLibrary, ovaFile).Return(*bytes.NewBufferString(""), nil)
+
+	if err := g.ImportTemplate(ctx, templateLibrary, ovaFile, name); err != nil {
+		t.Fatalf("Govc.ImportTemplate() err = %v, want err nil", err)
+	}
+}
+
 func TestImportTemplateError(t *testing.T) {
-	ovaURL := "ovaURL"
+	ovaURL := "https://amazonaws.com/artifacts/ubuntu.ova"
 	name := "name"
 	ctx := context.Background()
 
@@ -970,3 +983,92 @@ func TestDeleteTemplateDeleteVMError(t *testing.T) {
 		t.Fatal("Govc.DeleteTemplate() err = nil, want err not nil")
 	}
 }
+
+func TestListDatacentersSuccess(t *testing.T) {
+	ctx := context.Background()
+	wantDatacenters := []string{"SDDC-Datacenter", "folder/Other-Datacenter"}
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/", "-type", "Datacenter").Return(*bytes.NewBufferString(`["/SDDC-Datacenter","/folder/Other-Datacenter"]`), nil)
+
+	gotDatacenters, err := g.ListDatacenters(ctx)
+	if err != nil {
+		t.Fatalf("Govc.ListDatacenters() err = %v, want err nil", err)
+	}
+
+	if !reflect.DeepEqual(gotDatacenters, wantDatacenters) {
+		t.Fatalf("Govc.ListDatacenters() datacenters = %v, want %v", gotDatacenters, wantDatacenters)
+	}
+}
+
+func TestListNetworksSuccessNoNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/SDDC-Datacenter/network", "-type", "Network").Return(*bytes.NewBufferString("null\n"), nil)
+
+	networks, err := g.ListNetworks(ctx, "SDDC-Datacenter")
+	if err != nil {
+		t.Fatalf("Govc.ListNetworks() err = %v, want err nil", err)
+	}
+
+	if len(networks) != 0 {
+		t.Fatalf("Govc.ListNetworks() networks size = %d, want 0", len(networks))
+	}
+}
+
+func TestListDatastoresSuccess(t *testing.T) {
+	ctx := context.Background()
+	wantDatastores := []string{"/SDDC-Datacenter/datastore/WorkloadDatastore"}
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/SDDC-Datacenter/datastore", "-type", "Datastore").Return(*bytes.NewBufferString(`["/SDDC-Datacenter/datastore/WorkloadDatastore"]`), nil)
+
+	gotDatastores, err := g.ListDatastores(ctx, "SDDC-Datacenter")
+	if err != nil {
+		t.Fatalf("Govc.ListDatastores() err = %v, want err nil", err)
+	}
+
+	if !reflect.DeepEqual(gotDatastores, wantDatastores) {
+		t.Fatalf("Govc.ListDatastores() datastores = %v, want %v", gotDatastores, wantDatastores)
+	}
+}
+
+func TestListResourcePoolsErrorGovc(t *testing.T) {
+	ctx := context.Background()
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/SDDC-Datacenter/host", "-type", "ResourcePool").Return(bytes.Buffer{}, errors.New("error from exec"))
+
+	if _, err := g.ListResourcePools(ctx, "SDDC-Datacenter"); err == nil {
+		t.Fatal("Govc.ListResourcePools() err = nil, want err not nil")
+	}
+}
+
+func TestListTemplatesSuccess(t *testing.T) {
+	ctx := context.Background()
+	wantTemplates := []string{"/SDDC-Datacenter/vm/Templates/bottlerocket-1-21"}
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/SDDC-Datacenter/vm", "-type", "VirtualMachine", "-config.template", "true").Return(*bytes.NewBufferString(`["/SDDC-Datacenter/vm/Templates/bottlerocket-1-21"]`), nil)
+
+	gotTemplates, err := g.ListTemplates(ctx, "SDDC-Datacenter")
+	if err != nil {
+		t.Fatalf("Govc.ListTemplates() err = %v, want err nil", err)
+	}
+
+	if !reflect.DeepEqual(gotTemplates, wantTemplates) {
+		t.Fatalf("Govc.ListTemplates() templates = %v, want %v", gotTemplates, wantTemplates)
+	}
+}
+
+func TestListFoldersErrorUnmarshalling(t *testing.T) {
+	ctx := context.Background()
+
+	g, executable, env := setup(t)
+	executable.EXPECT().ExecuteWithEnv(ctx, env, "find", "-json", "/SDDC-Datacenter/vm", "-type", "Folder").Return(*bytes.NewBufferString("invalid"), nil)
+
+	if _, err := g.ListFolders(ctx, "SDDC-Datacenter"); err == nil {
+		t.Fatal("Govc.ListFolders() err = nil, want err not nil")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/filewriter"
 	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/templater"
 	"github.com/aws/eks-anywhere/pkg/types"
 )
@@ -32,19 +33,19 @@ type Kind struct {
 // It's used by BootstrapClusterClientOption's to store/change information prior to a command execution
 // It must be cleaned after each execution to prevent side effects from past executions options
 type kindExecConfig struct {
-	env                    map[string]string
-	ConfigFile             string
-	KindImage              string
-	KubernetesRepository   string
-	EtcdRepository         string
-	EtcdVersion            string
-	CorednsRepository      string
-	CorednsVersion         string
-	KubernetesVersion      string
-	RegistryMirrorEndpoint string
-	RegistryCACert         string
-	DockerExtraMounts      bool
-	DisableDefaultCNI      bool
+	env                  map[string]string
+	ConfigFile           string
+	KindImage            string
+	KubernetesRepository string
+	EtcdRepository       string
+	EtcdVersion          string
+	CorednsRepository    string
+	CorednsVersion       string
+	KubernetesVersion    string
+	RegistryMirror       *registrymirror.Configuration
+	RegistryCACert       string
+	DockerExtraMounts    bool
+	DisableDefaultCNI    bool
 }
 
 func NewKind(executable Executable, writer filewriter.FileWriter) *Kind {
@@ -155,13 +156,15 @@ func (k *Kind) WithDefaultCNIDisabled() bootstrapper.BootstrapClusterClientOptio
 	}
 }
 
-func (k *Kind) WithRegistryMirror(endpoint string, caCertFile string) bootstrapper.BootstrapClusterClientOption {
+// WithRegistryMirror configures containerd in the kind node to pull images from the registry mirrors,
+// trusting the CA certificate in caCertFile for all the mirror endpoints
+func (k *Kind) WithRegistryMirror(mirror *registrymirror.Configuration, caCertFile string) bootstrapper.BootstrapClusterClientOption {
 	return func() error {
 		if k.execConfig == nil {
 			return errors.New("kind exec config is not ready")
 		}
 
-		k.execConfig.RegistryMirrorEndpoint = endpoint
+		k.execConfig.RegistryMirror = mirror
 		k.execConfig.RegistryCACert = caCertFile
 
 		return nil
@@ -190,10 +193,10 @@ func (k *Kind) setupExecConfig(clusterSpec *cluster.Spec) error {
 		CorednsVersion:       bundle.KubeDistro.CoreDNS.Tag,
 		env:                  make(map[string]string),
 	}
-	if clusterSpec.Spec.RegistryMirrorConfiguration != nil {
-		k.execConfig.RegistryMirrorEndpoint = clusterSpec.Cluster.Spec.RegistryMirrorConfiguration.Endpoint
-		if clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent != "" {
-			path, err := k.writer.Write("ca.crt", []byte(clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent))
+	if mirror := clusterSpec.RegistryMirror(); mirror != nil {
+		k.execConfig.RegistryMirror = mirror
+		if mirror.CACertContent != "" {
+			path, err := k.writer.Write("ca.crt", []byte(mirror.CACertContent))
 			if err != nil {
 				return errors.New("error writing the registry certification file")
 			}
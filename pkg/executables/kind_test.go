@@ -16,6 +16,7 @@ import (
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/executables"
 	mockexecutables "github.com/aws/eks-anywhere/pkg/executables/mocks"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/types"
 )
 
@@ -138,7 +139,10 @@ func TestKindCreateBootstrapClusterSuccess(t *testing.T) {
 			wantKubeconfig: kubeConfigFile,
 			options: []testKindOption{
 				func(k *executables.Kind) bootstrapper.BootstrapClusterClientOption {
-					return k.WithRegistryMirror(registryMirror, "")
+					return k.WithRegistryMirror(&registrymirror.Configuration{
+						Mirrors:            []registrymirror.Mirror{{Registry: "public.ecr.aws", Endpoint: registryMirror}},
+						InsecureSkipVerify: true,
+					}, "")
 				},
 			},
 			env:                map[string]string{},
@@ -150,13 +154,35 @@ func TestKindCreateBootstrapClusterSuccess(t *testing.T) {
 			wantKubeconfig: kubeConfigFile,
 			options: []testKindOption{
 				func(k *executables.Kind) bootstrapper.BootstrapClusterClientOption {
-					return k.WithRegistryMirror(registryMirror, "ca.crt")
+					return k.WithRegistryMirror(&registrymirror.Configuration{
+						Mirrors:       []registrymirror.Mirror{{Registry: "public.ecr.aws", Endpoint: registryMirror}},
+						CACertContent: "ca",
+					}, "ca.crt")
 				},
 			},
 			env:                map[string]string{},
 			wantKindConfig:     "testdata/kind_config_registry_mirror_with_ca.yaml",
 			registryMirrorTest: true,
 		},
+		{
+			name:           "With registry mirror option, multiple mirrors with CA cert and credentials",
+			wantKubeconfig: kubeConfigFile,
+			options: []testKindOption{
+				func(k *executables.Kind) bootstrapper.BootstrapClusterClientOption {
+					return k.WithRegistryMirror(&registrymirror.Configuration{
+						Mirrors: []registrymirror.Mirror{
+							{Registry: "public.ecr.aws", Endpoint: registryMirror},
+							{Registry: "docker.io", Endpoint: "docker-mirror.test:5000"},
+						},
+						CACertContent: "ca",
+						Credentials:   &registrymirror.Credentials{Username: "user", Password: "pass"},
+					}, "ca.crt")
+				},
+			},
+			env:                map[string]string{},
+			wantKindConfig:     "testdata/kind_config_registry_mirror_with_auth.yaml",
+			registryMirrorTest: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
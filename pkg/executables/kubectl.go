@@ -12,6 +12,7 @@ import (
 	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/version"
 	vspherev3 "sigs.k8s.io/cluster-api-provider-vsphere/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/api/v1alpha3"
@@ -154,7 +155,7 @@ func (k *Kubectl) ApplyKubeSpecFromBytesForce(ctx context.Context, cluster *type
 }
 
 func (k *Kubectl) DeleteKubeSpecFromBytes(ctx context.Context, cluster *types.Cluster, data []byte) error {
-	params := []string{"delete", "-f", "-"}
+	params := []string{"delete", "-f", "-", "--ignore-not-found"}
 	if cluster.KubeconfigFile != "" {
 		params = append(params, "--kubeconfig", cluster.KubeconfigFile)
 	}
@@ -177,6 +178,16 @@ func (k *Kubectl) WaitForDeployment(ctx context.Context, cluster *types.Cluster,
 	return k.Wait(ctx, cluster.KubeconfigFile, timeout, condition, "deployments/"+target, namespace)
 }
 
+// WaitForDaemonSetRollout waits until all the pods of the daemonset are updated and available
+func (k *Kubectl) WaitForDaemonSetRollout(ctx context.Context, cluster *types.Cluster, timeout string, target string, namespace string) error {
+	_, err := k.executable.Execute(ctx, "rollout", "status", "daemonsets/"+target, "--timeout", timeout,
+		"--kubeconfig", cluster.KubeconfigFile, "-n", namespace)
+	if err != nil {
+		return fmt.Errorf("error waiting for daemonset rollout: %v", err)
+	}
+	return nil
+}
+
 func (k *Kubectl) Wait(ctx context.Context, kubeconfig string, timeout string, forCondition string, property string, namespace string) error {
 	_, err := k.executable.Execute(ctx, "wait", "--timeout", timeout,
 		"--for=condition="+forCondition, property, "--kubeconfig", kubeconfig, "-n", namespace)
@@ -231,6 +242,15 @@ func (k *Kubectl) DeleteSecret(ctx context.Context, managementCluster *types.Clu
 	return nil
 }
 
+func (k *Kubectl) DeleteCronJob(ctx context.Context, cluster *types.Cluster, cronJobName, namespace string) error {
+	params := []string{"delete", "cronjob", cronJobName, "--kubeconfig", cluster.KubeconfigFile, "--namespace", namespace, "--ignore-not-found=true"}
+	_, err := k.executable.Execute(ctx, params...)
+	if err != nil {
+		return fmt.Errorf("error deleting cronjob %s in namespace %s: %v", cronJobName, namespace, err)
+	}
+	return nil
+}
+
 func (k *Kubectl) DeleteOIDCConfig(ctx context.Context, managementCluster *types.Cluster, oidcConfigName, oidcConfigNamespace string) error {
 	params := []string{"delete", eksaOIDCResourceType, oidcConfigName, "--kubeconfig", managementCluster.KubeconfigFile, "--namespace", oidcConfigNamespace, "--ignore-not-found=true"}
 	_, err := k.executable.Execute(ctx, params...)
@@ -703,6 +723,45 @@ func (k *Kubectl) GetMachineDeployments(ctx context.Context, opts ...KubectlOpt)
 	return response.Items, nil
 }
 
+// GetObjects lists the objects of resourceType, such as machines.cluster.x-k8s.io
+func (k *Kubectl) GetObjects(ctx context.Context, resourceType string, opts ...KubectlOpt) ([]unstructured.Unstructured, error) {
+	params := []string{"get", resourceType, "-o", "json"}
+	applyOpts(&params, opts...)
+	stdOut, err := k.executable.Execute(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s: %v", resourceType, err)
+	}
+
+	response := &unstructured.UnstructuredList{}
+	if err = response.UnmarshalJSON(stdOut.Bytes()); err != nil {
+		return nil, fmt.Errorf("error parsing get %s response: %v", resourceType, err)
+	}
+
+	return response.Items, nil
+}
+
+// CreateObject creates obj in cluster and returns it as stored by the api server
+func (k *Kubectl) CreateObject(ctx context.Context, cluster *types.Cluster, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling %s %s: %v", obj.GetKind(), obj.GetName(), err)
+	}
+
+	params := []string{"create", "-f", "-", "-o", "json"}
+	applyOpts(&params, WithCluster(cluster))
+	stdOut, err := k.executable.ExecuteWithStdin(ctx, data, params...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s %s: %v", obj.GetKind(), obj.GetName(), err)
+	}
+
+	created := &unstructured.Unstructured{}
+	if err = created.UnmarshalJSON(stdOut.Bytes()); err != nil {
+		return nil, fmt.Errorf("error parsing create %s response: %v", obj.GetKind(), err)
+	}
+
+	return created, nil
+}
+
 func (k *Kubectl) UpdateEnvironmentVariables(ctx context.Context, resourceType, resourceName string, envMap map[string]string, opts ...KubectlOpt) error {
 	params := []string{"set", "env", resourceType, resourceName}
 	for k, v := range envMap {
@@ -751,6 +810,34 @@ func (k *Kubectl) RemoveAnnotationInNamespace(ctx context.Context, resourceType,
 	return k.RemoveAnnotation(ctx, resourceType, objectName, key, WithCluster(cluster), WithNamespace(namespace))
 }
 
+// MergePatchResource applies a JSON merge patch to an object
+func (k *Kubectl) MergePatchResource(ctx context.Context, resourceType, objectName, patch string, cluster *types.Cluster, namespace string) error {
+	params := []string{"patch", resourceType, objectName, "--type", "merge", "-p", patch}
+	applyOpts(&params, WithCluster(cluster), WithNamespace(namespace))
+	_, err := k.executable.Execute(ctx, params...)
+	if err != nil {
+		return fmt.Errorf("error patching %s %s: %v", resourceType, objectName, err)
+	}
+	return nil
+}
+
+// GetEksaClusters returns the EKS-A clusters in all the namespaces of cluster
+func (k *Kubectl) GetEksaClusters(ctx context.Context, cluster *types.Cluster) ([]v1alpha1.Cluster, error) {
+	params := []string{"get", eksaClusterResourceType, "-A", "-o", "json", "--kubeconfig", cluster.KubeconfigFile}
+	stdOut, err := k.executable.Execute(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting eksa clusters: %v", err)
+	}
+
+	response := &v1alpha1.ClusterList{}
+	err = json.Unmarshal(stdOut.Bytes(), response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing get eksa clusters response: %v", err)
+	}
+
+	return response.Items, nil
+}
+
 func (k *Kubectl) GetEksaCluster(ctx context.Context, cluster *types.Cluster, clusterName string) (*v1alpha1.Cluster, error) {
 	params := []string{"get", "clusters", "-A", "-o", "jsonpath={.items[0]}", "--kubeconfig", cluster.KubeconfigFile, "--field-selector=metadata.name=" + clusterName}
 	stdOut, err := k.executable.Execute(ctx, params...)
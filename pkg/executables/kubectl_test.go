@@ -13,6 +13,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/cluster-api/api/v1alpha3"
 	addons "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha3"
@@ -115,7 +116,7 @@ func TestKubectlDeleteKubeSpecFromBytesSuccess(t *testing.T) {
 	var data []byte
 
 	k, ctx, cluster, e := newKubectl(t)
-	expectedParam := []string{"delete", "-f", "-", "--kubeconfig", cluster.KubeconfigFile}
+	expectedParam := []string{"delete", "-f", "-", "--ignore-not-found", "--kubeconfig", cluster.KubeconfigFile}
 	e.EXPECT().ExecuteWithStdin(ctx, data, gomock.Eq(expectedParam)).Return(bytes.Buffer{}, nil)
 	if err := k.DeleteKubeSpecFromBytes(ctx, cluster, data); err != nil {
 		t.Errorf("Kubectl.DeleteKubeSpecFromBytes() error = %v, want nil", err)
@@ -126,7 +127,7 @@ func TestKubectlDeleteSpecFromBytesError(t *testing.T) {
 	var data []byte
 
 	k, ctx, cluster, e := newKubectl(t)
-	expectedParam := []string{"delete", "-f", "-", "--kubeconfig", cluster.KubeconfigFile}
+	expectedParam := []string{"delete", "-f", "-", "--ignore-not-found", "--kubeconfig", cluster.KubeconfigFile}
 	e.EXPECT().ExecuteWithStdin(ctx, data, gomock.Eq(expectedParam)).Return(bytes.Buffer{}, errors.New("error from execute"))
 	if err := k.DeleteKubeSpecFromBytes(ctx, cluster, data); err == nil {
 		t.Errorf("Kubectl.DeleteKubeSpecFromBytes() error = nil, want not nil")
@@ -212,6 +213,15 @@ func TestKubectlDeleteSecretSuccess(t *testing.T) {
 	}
 }
 
+func TestKubectlDeleteCronJobSuccess(t *testing.T) {
+	k, ctx, cluster, e := newKubectl(t)
+	expectedParam := []string{"delete", "cronjob", "test-cluster-etcd-backup", "--kubeconfig", cluster.KubeconfigFile, "--namespace", "eksa-system", "--ignore-not-found=true"}
+	e.EXPECT().Execute(ctx, gomock.Eq(expectedParam)).Return(bytes.Buffer{}, nil)
+	if err := k.DeleteCronJob(ctx, cluster, "test-cluster-etcd-backup", "eksa-system"); err != nil {
+		t.Errorf("Kubectl.DeleteCronJob() error = %v, want nil", err)
+	}
+}
+
 func TestKubectlGetNamespaceSuccess(t *testing.T) {
 	var kubeconfig, namespace string
 
@@ -256,6 +266,15 @@ func TestKubectlWaitError(t *testing.T) {
 	}
 }
 
+func TestKubectlWaitForDaemonSetRollout(t *testing.T) {
+	k, ctx, cluster, e := newKubectl(t)
+	expectedParam := []string{"rollout", "status", "daemonsets/cilium", "--timeout", "5m", "--kubeconfig", cluster.KubeconfigFile, "-n", "kube-system"}
+	e.EXPECT().Execute(ctx, gomock.Eq(expectedParam)).Return(bytes.Buffer{}, nil)
+	if err := k.WaitForDaemonSetRollout(ctx, cluster, "5m", "cilium", "kube-system"); err != nil {
+		t.Errorf("Kubectl.WaitForDaemonSetRollout() error = %v, want nil", err)
+	}
+}
+
 func TestKubectlSaveLogSuccess(t *testing.T) {
 	filename := "testfile"
 	_, writer := test.NewWriter(t)
@@ -342,6 +361,9 @@ func TestKubectlGetMachines(t *testing.T) {
 			jsonResponseFile: "testdata/kubectl_machines_no_node_ref_no_labels.json",
 			wantMachines: []types.Machine{
 				{
+					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-control-plane-5nfdg",
+					},
 					Status: types.MachineStatus{
 						Conditions: types.Conditions{
 							{
@@ -384,6 +406,9 @@ func TestKubectlGetMachines(t *testing.T) {
 					},
 				},
 				{
+					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-md-0-bb7885f6f-gkb85",
+					},
 					Status: types.MachineStatus{
 						Conditions: types.Conditions{
 							{
@@ -413,6 +438,7 @@ func TestKubectlGetMachines(t *testing.T) {
 			wantMachines: []types.Machine{
 				{
 					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-control-plane-5nfdg",
 						Labels: map[string]string{
 							"cluster.x-k8s.io/cluster-name":  "eksa-test-capd",
 							"cluster.x-k8s.io/control-plane": "",
@@ -428,6 +454,7 @@ func TestKubectlGetMachines(t *testing.T) {
 				},
 				{
 					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-md-0-bb7885f6f-gkb85",
 						Labels: map[string]string{
 							"cluster.x-k8s.io/cluster-name":    "eksa-test-capd",
 							"cluster.x-k8s.io/deployment-name": "eksa-test-capd-md-0",
@@ -450,6 +477,7 @@ func TestKubectlGetMachines(t *testing.T) {
 			wantMachines: []types.Machine{
 				{
 					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-control-plane-5nfdg",
 						Labels: map[string]string{
 							"cluster.x-k8s.io/cluster-name":  "eksa-test-capd",
 							"cluster.x-k8s.io/control-plane": "",
@@ -503,6 +531,7 @@ func TestKubectlGetMachines(t *testing.T) {
 				},
 				{
 					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-md-0-bb7885f6f-gkb85",
 						Labels: map[string]string{
 							"cluster.x-k8s.io/cluster-name":    "eksa-test-capd",
 							"cluster.x-k8s.io/deployment-name": "eksa-test-capd-md-0",
@@ -543,6 +572,7 @@ func TestKubectlGetMachines(t *testing.T) {
 			wantMachines: []types.Machine{
 				{
 					Metadata: types.MachineMetadata{
+						Name: "eksa-test-capd-control-plane-5nfdg",
 						Labels: map[string]string{
 							"cluster.x-k8s.io/cluster-name": "eksa-test-capd",
 							"cluster.x-k8s.io/etcd-cluster": "",
@@ -706,6 +736,23 @@ metadata:
 	}
 }
 
+func TestKubectlGetEksaClusters(t *testing.T) {
+	k, ctx, cluster, e := newKubectl(t)
+	response := `{"apiVersion": "v1", "kind": "List", "items": [
+		{"apiVersion": "anywhere.eks.amazonaws.com/v1alpha1", "kind": "Cluster", "metadata": {"name": "mgmt", "namespace": "default"}},
+		{"apiVersion": "anywhere.eks.amazonaws.com/v1alpha1", "kind": "Cluster", "metadata": {"name": "workload", "namespace": "team-a"}, "spec": {"managementCluster": {"name": "mgmt"}}}
+	]}`
+	e.EXPECT().Execute(ctx, []string{"get", "clusters.anywhere.eks.amazonaws.com", "-A", "-o", "json", "--kubeconfig", cluster.KubeconfigFile}).Return(*bytes.NewBufferString(response), nil)
+
+	gotClusters, err := k.GetEksaClusters(ctx, cluster)
+	if err != nil {
+		t.Fatalf("Kubectl.GetEksaClusters() error = %v, want nil", err)
+	}
+	if len(gotClusters) != 2 || gotClusters[0].Name != "mgmt" || gotClusters[1].Namespace != "team-a" || gotClusters[1].ManagedBy() != "mgmt" {
+		t.Fatalf("Kubectl.GetEksaClusters() clusters = %+v, want mgmt and workload", gotClusters)
+	}
+}
+
 func TestKubectlGetClusters(t *testing.T) {
 	tests := []struct {
 		testName         string
@@ -1238,6 +1285,19 @@ func TestKubectlUpdateAnnotation(t *testing.T) {
 	}
 }
 
+func TestKubectlMergePatchResource(t *testing.T) {
+	k, ctx, cluster, e := newKubectl(t)
+	e.EXPECT().Execute(ctx, []string{
+		"patch", "machinedeployments", "test-cluster-md-0", "--type", "merge", "-p", `{"spec":{"paused":true}}`,
+		"--kubeconfig", cluster.KubeconfigFile, "--namespace", "eksa-system",
+	})
+
+	err := k.MergePatchResource(ctx, "machinedeployments", "test-cluster-md-0", `{"spec":{"paused":true}}`, cluster, "eksa-system")
+	if err != nil {
+		t.Fatalf("Kubectl.MergePatchResource() error = %v, want nil", err)
+	}
+}
+
 func TestKubectlRemoveAnnotation(t *testing.T) {
 	k, ctx, cluster, e := newKubectl(t)
 	e.EXPECT().Execute(ctx, []string{
@@ -1392,3 +1452,39 @@ func TestKubectlCheckCAPIProviderExistsInstalled(t *testing.T) {
 
 	tt.Expect(tt.k.CheckProviderExists(tt.ctx, tt.cluster.KubeconfigFile, providerName, providerNs))
 }
+
+func TestKubectlGetObjects(t *testing.T) {
+	tt := newKubectlTest(t)
+	tt.e.EXPECT().Execute(tt.ctx,
+		"get", "vspheremachines.infrastructure.cluster.x-k8s.io", "-o", "json", "--kubeconfig", tt.cluster.KubeconfigFile, "--namespace", tt.namespace,
+	).Return(*bytes.NewBufferString(`{"apiVersion":"v1","kind":"List","items":[{"apiVersion":"infrastructure.cluster.x-k8s.io/v1alpha3","kind":"VSphereMachine","metadata":{"name":"m-1"}}]}`), nil)
+
+	objs, err := tt.k.GetObjects(tt.ctx, "vspheremachines.infrastructure.cluster.x-k8s.io", executables.WithCluster(tt.cluster), executables.WithNamespace(tt.namespace))
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(objs).To(HaveLen(1))
+	tt.Expect(objs[0].GetName()).To(Equal("m-1"))
+}
+
+func TestKubectlGetObjectsError(t *testing.T) {
+	tt := newKubectlTest(t)
+	tt.e.EXPECT().Execute(tt.ctx, "get", "secrets", "-o", "json").Return(bytes.Buffer{}, errors.New("error from execute"))
+
+	_, err := tt.k.GetObjects(tt.ctx, "secrets")
+	tt.Expect(err).To(MatchError("error getting secrets: error from execute"))
+}
+
+func TestKubectlCreateObject(t *testing.T) {
+	tt := newKubectlTest(t)
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("cm-1")
+	data, err := obj.MarshalJSON()
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.e.EXPECT().ExecuteWithStdin(tt.ctx, data, "create", "-f", "-", "-o", "json", "--kubeconfig", tt.cluster.KubeconfigFile).
+		Return(*bytes.NewBufferString(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-1","uid":"1234"}}`), nil)
+
+	created, err := tt.k.CreateObject(tt.ctx, tt.cluster, obj)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(string(created.GetUID())).To(Equal("1234"))
+}
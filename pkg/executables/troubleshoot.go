@@ -23,17 +23,31 @@ func NewTroubleshoot(executable Executable) *Troubleshoot {
 	}
 }
 
-func (t *Troubleshoot) Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig string) (archivePath string, err error) {
+// Collect runs the collectors in bundlePath and redacts the collected files with the redactors in redactorsPath
+// and the troubleshoot default redactors. An empty redactorsPath disables redaction.
+// The archive is written to archivePath or, if empty, to a timestamped file in the current folder
+func (t *Troubleshoot) Collect(ctx context.Context, bundlePath string, sinceTime *time.Time, kubeconfig, redactorsPath, archivePath string) (string, error) {
 	marshalledTime, err := sinceTime.MarshalText()
 	if err != nil {
 		return "", fmt.Errorf("could not marshal sinceTime for Collect parameters: %v", err)
 	}
 	params := []string{bundlePath, "--kubeconfig", kubeconfig, "--interactive=false", "--since-time", string(marshalledTime)}
+	if redactorsPath == "" {
+		params = append(params, "--redact=false")
+	} else {
+		params = append(params, "--redactors", redactorsPath)
+	}
+	if archivePath != "" {
+		params = append(params, "--output", archivePath)
+	}
 
 	output, err := t.executable.Execute(ctx, params...)
 	if err != nil {
 		return "", fmt.Errorf("error when executing support-bundle: %v", err)
 	}
+	if archivePath != "" {
+		return archivePath, nil
+	}
 	archivePath, err = parseArchivePathFromCollectOutput(output.String())
 	if err != nil {
 		return "", fmt.Errorf("error when parsing support-bundle output: %v", err)
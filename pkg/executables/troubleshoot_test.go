@@ -17,6 +17,7 @@ import (
 const (
 	archivePath     = "support-bundle-2021-09-17T16_22_45.tar.gz"
 	bundlePath      = "./testBundleThatDoesNotExist.yaml"
+	redactorsPath   = "./testRedactorsThatDoNotExist.yaml"
 	sinceTimeString = "2021-09-17T16:22:45Z"
 )
 
@@ -26,15 +27,48 @@ func TestTroubleshootCollectSuccess(t *testing.T) {
 	if err != nil {
 		t.Errorf("Troubleshoot.Collect() error: failed to parse time: %v", err)
 	}
-	expectedParams := []string{bundlePath, "--kubeconfig", cluster.KubeconfigFile, "--interactive=false", "--since-time", sinceTimeString}
+	expectedParams := []string{bundlePath, "--kubeconfig", cluster.KubeconfigFile, "--interactive=false", "--since-time", sinceTimeString, "--redactors", redactorsPath}
 	returnBuffer := bytes.Buffer{}
 	returnBuffer.Write([]byte(archivePath))
 	e.EXPECT().Execute(ctx, gomock.Eq(expectedParams)).Return(returnBuffer, nil)
-	if _, err := ts.Collect(ctx, bundlePath, &sinceTime, cluster.KubeconfigFile); err != nil {
+	if _, err := ts.Collect(ctx, bundlePath, &sinceTime, cluster.KubeconfigFile, redactorsPath, ""); err != nil {
 		t.Errorf("Troubleshoot.Collect() error = %v, want nil", err)
 	}
 }
 
+func TestTroubleshootCollectWithoutRedaction(t *testing.T) {
+	ts, ctx, cluster, e := newTroubleshoot(t)
+	sinceTime, err := time.Parse(time.RFC3339, sinceTimeString)
+	if err != nil {
+		t.Errorf("Troubleshoot.Collect() error: failed to parse time: %v", err)
+	}
+	expectedParams := []string{bundlePath, "--kubeconfig", cluster.KubeconfigFile, "--interactive=false", "--since-time", sinceTimeString, "--redact=false"}
+	returnBuffer := bytes.Buffer{}
+	returnBuffer.Write([]byte(archivePath))
+	e.EXPECT().Execute(ctx, gomock.Eq(expectedParams)).Return(returnBuffer, nil)
+	if _, err := ts.Collect(ctx, bundlePath, &sinceTime, cluster.KubeconfigFile, "", ""); err != nil {
+		t.Errorf("Troubleshoot.Collect() error = %v, want nil", err)
+	}
+}
+
+func TestTroubleshootCollectWithArchivePath(t *testing.T) {
+	ts, ctx, cluster, e := newTroubleshoot(t)
+	sinceTime, err := time.Parse(time.RFC3339, sinceTimeString)
+	if err != nil {
+		t.Errorf("Troubleshoot.Collect() error: failed to parse time: %v", err)
+	}
+	output := "workload-support-bundle.tar.gz"
+	expectedParams := []string{bundlePath, "--kubeconfig", cluster.KubeconfigFile, "--interactive=false", "--since-time", sinceTimeString, "--redactors", redactorsPath, "--output", output}
+	e.EXPECT().Execute(ctx, gomock.Eq(expectedParams)).Return(bytes.Buffer{}, nil)
+	gotArchivePath, err := ts.Collect(ctx, bundlePath, &sinceTime, cluster.KubeconfigFile, redactorsPath, output)
+	if err != nil {
+		t.Errorf("Troubleshoot.Collect() error = %v, want nil", err)
+	}
+	if gotArchivePath != output {
+		t.Errorf("Troubleshoot.Collect() archive = %s, want %s", gotArchivePath, output)
+	}
+}
+
 func TestTroubleshootAnalyzeSuccess(t *testing.T) {
 	ts, ctx, _, e := newTroubleshoot(t)
 	var returnValues []*executables.SupportBundleAnalysis
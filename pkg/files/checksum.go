@@ -0,0 +1,79 @@
+package files
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// Checksum holds the published digests of a file, hex encoded. Empty digests are not verified.
+type Checksum struct {
+	SHA256 string
+	SHA512 string
+}
+
+// IsEmpty returns true if there is no digest to verify
+func (c Checksum) IsEmpty() bool {
+	return c.SHA256 == "" && c.SHA512 == ""
+}
+
+// Verify checks content against all the digests in the checksum
+func (c Checksum) Verify(name string, content []byte) error {
+	v := c.NewVerifier()
+	_, _ = v.Write(content)
+	return v.Verify(name)
+}
+
+// Verifier computes the digests of a stream as it's written, so big files can be verified without
+// keeping them in memory
+type Verifier struct {
+	checksum Checksum
+	sha256   hash.Hash
+	sha512   hash.Hash
+	writer   io.Writer
+}
+
+// NewVerifier returns a Verifier for the checksum digests
+func (c Checksum) NewVerifier() *Verifier {
+	v := &Verifier{checksum: c}
+	var writers []io.Writer
+	if c.SHA256 != "" {
+		v.sha256 = sha256.New()
+		writers = append(writers, v.sha256)
+	}
+	if c.SHA512 != "" {
+		v.sha512 = sha512.New()
+		writers = append(writers, v.sha512)
+	}
+	v.writer = io.MultiWriter(writers...)
+	return v
+}
+
+func (v *Verifier) Write(p []byte) (int, error) {
+	return v.writer.Write(p)
+}
+
+// Verify compares the digests of everything written so far with the expected ones
+func (v *Verifier) Verify(name string) error {
+	if v.sha256 != nil {
+		if err := compareDigest(name, "sha256", v.checksum.SHA256, v.sha256); err != nil {
+			return err
+		}
+	}
+	if v.sha512 != nil {
+		if err := compareDigest(name, "sha512", v.checksum.SHA512, v.sha512); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compareDigest(name, algorithm, expected string, h hash.Hash) error {
+	if got := fmt.Sprintf("%x", h.Sum(nil)); got != strings.ToLower(expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s %s, got %s", name, algorithm, expected, got)
+	}
+	return nil
+}
@@ -1,19 +1,39 @@
 package files
 
 import (
+	"context"
 	"embed"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/logger"
 )
 
 const (
+	httpScheme  = "http"
 	httpsScheme = "https"
 	embedScheme = "embed"
+
+	// PublishedChecksumSuffix is appended to the url of a file to get its published sha256 checksum
+	PublishedChecksumSuffix = ".sha256"
 )
 
+// allowMissingChecksums is only set by the CLI when the user explicitly opts out of checksum verification
+// for remote files that don't publish one, it's never set by the controller
+var allowMissingChecksums bool
+
+// AllowMissingChecksums makes readers accept remote files without a published checksum instead of failing.
+// Files with a checksum are always verified
+func AllowMissingChecksums(allow bool) {
+	allowMissingChecksums = allow
+}
+
 type Reader struct {
 	embedFS    embed.FS
 	httpClient *http.Client
@@ -55,7 +75,7 @@ func (r *Reader) ReadFile(uri string) ([]byte, error) {
 	}
 
 	switch url.Scheme {
-	case httpsScheme:
+	case httpsScheme, httpScheme:
 		return r.readHttpFile(uri)
 	case embedScheme:
 		return r.readEmbedFile(url)
@@ -64,6 +84,148 @@ func (r *Reader) ReadFile(uri string) ([]byte, error) {
 	}
 }
 
+// ReadFileWithChecksum reads a file like ReadFile and verifies its content against checksum.
+// Remote files without a checksum are rejected unless missing checksums have been explicitly allowed
+func (r *Reader) ReadFileWithChecksum(uri string, checksum Checksum) ([]byte, error) {
+	if err := checkMissingChecksum(uri, checksum); err != nil {
+		return nil, err
+	}
+
+	content, err := r.ReadFile(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checksum.Verify(uri, content); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// ReadFileWithPublishedChecksum reads a file like ReadFileWithChecksum, verifying it against its PublishedChecksum.
+// Used for the manifests that hold the checksums of every other artifact
+func (r *Reader) ReadFileWithPublishedChecksum(uri string) ([]byte, error) {
+	checksum, err := r.PublishedChecksum(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ReadFileWithChecksum(uri, checksum)
+}
+
+// PublishedChecksum reads the sha256 checksum published next to a remote file, in uri with the
+// PublishedChecksumSuffix, in the sha256sum format. Local and embed files are trusted and have no checksum
+func (r *Reader) PublishedChecksum(uri string) (Checksum, error) {
+	if !isRemote(uri) {
+		return Checksum{}, nil
+	}
+
+	published, err := r.ReadFile(uri + PublishedChecksumSuffix)
+	if err != nil {
+		if allowMissingChecksums {
+			return Checksum{}, nil
+		}
+		return Checksum{}, fmt.Errorf("failed reading published checksum for [%s]: %v", uri, err)
+	}
+
+	fields := strings.Fields(string(published))
+	if len(fields) == 0 {
+		return Checksum{}, fmt.Errorf("invalid published checksum for [%s]: empty file", uri)
+	}
+
+	return Checksum{SHA256: fields[0]}, nil
+}
+
+func checkMissingChecksum(uri string, checksum Checksum) error {
+	if !checksum.IsEmpty() || !isRemote(uri) {
+		return nil
+	}
+	if !allowMissingChecksums {
+		return fmt.Errorf("no checksum published for file [%s], its content can't be verified. Use --allow-missing-checksums to read it anyway", uri)
+	}
+
+	logger.Info("WARNING: no checksum published for file, its content can't be verified", "file", uri)
+	return nil
+}
+
+// DownloadFile streams a file to target verifying it against checksum, without loading it in memory.
+// The target file is removed if the download or the verification fails.
+func (r *Reader) DownloadFile(ctx context.Context, uri string, checksum Checksum, target string) (err error) {
+	if err = checkMissingChecksum(uri, checksum); err != nil {
+		return err
+	}
+
+	source, err := r.open(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed creating directory for file [%s]: %v", uri, err)
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed creating file for [%s]: %v", uri, err)
+	}
+	defer func() {
+		out.Close()
+		if err != nil {
+			os.Remove(target)
+		}
+	}()
+
+	verifier := checksum.NewVerifier()
+	if _, err = io.Copy(io.MultiWriter(out, verifier), source); err != nil {
+		return fmt.Errorf("failed downloading file [%s]: %v", uri, err)
+	}
+
+	return verifier.Verify(uri)
+}
+
+func isRemote(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && (u.Scheme == httpScheme || u.Scheme == httpsScheme)
+}
+
+func (r *Reader) open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file url [%s]: %v", uri, err)
+	}
+
+	switch u.Scheme {
+	case httpsScheme, httpScheme:
+		request, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating http GET request for downloading file: %v", err)
+		}
+		request.Header.Set("User-Agent", r.userAgent)
+		resp, err := r.httpClient.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading file from url [%s]: %v", uri, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed reading file from url [%s]: unexpected status %s", uri, resp.Status)
+		}
+		return resp.Body, nil
+	case embedScheme:
+		f, err := r.embedFS.Open(strings.TrimPrefix(u.Path, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("failed reading embed file [%s]: %v", u.Path, err)
+		}
+		return f, nil
+	default:
+		f, err := os.Open(uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading local file [%s]: %v", uri, err)
+		}
+		return f, nil
+	}
+}
+
 func (r *Reader) readHttpFile(uri string) ([]byte, error) {
 	request, err := http.NewRequest("GET", uri, nil)
 	if err != nil {
@@ -76,6 +238,9 @@ func (r *Reader) readHttpFile(uri string) ([]byte, error) {
 		return nil, fmt.Errorf("failed reading file from url [%s]: %v", uri, err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed reading file from url [%s]: unexpected status %s", uri, resp.Status)
+	}
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -1,7 +1,17 @@
 package files_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
 	"embed"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -71,3 +81,168 @@ func TestReaderReadFileSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestReaderReadFileHttp(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(".")))
+	defer server.Close()
+	r := files.NewReader()
+
+	got, err := r.ReadFile(server.URL + "/testdata/file.yaml")
+	g.Expect(err).To(BeNil())
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+
+	_, err = r.ReadFile(server.URL + "/testdata/missing.yaml")
+	g.Expect(err).To(MatchError(ContainSubstring("unexpected status 404 Not Found")))
+}
+
+func fileChecksum(t *testing.T, file string) files.Checksum {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files.Checksum{
+		SHA256: fmt.Sprintf("%x", sha256.Sum256(content)),
+		SHA512: fmt.Sprintf("%x", sha512.Sum512(content)),
+	}
+}
+
+func TestReaderReadFileWithChecksum(t *testing.T) {
+	g := NewWithT(t)
+	r := files.NewReader(files.WithEmbedFS(testdataFS))
+	checksum := fileChecksum(t, "testdata/file.yaml")
+
+	for _, uri := range []string{"testdata/file.yaml", "embed:///testdata/file.yaml"} {
+		got, err := r.ReadFileWithChecksum(uri, checksum)
+		g.Expect(err).To(BeNil())
+		test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+	}
+
+	got, err := r.ReadFileWithChecksum("testdata/file.yaml", files.Checksum{})
+	g.Expect(err).To(BeNil(), "empty checksums are not verified")
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+}
+
+func TestReaderReadFileWithChecksumHttpWithoutChecksum(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(".")))
+	defer server.Close()
+	r := files.NewReader()
+	uri := server.URL + "/testdata/file.yaml"
+
+	_, err := r.ReadFileWithChecksum(uri, files.Checksum{})
+	g.Expect(err).To(MatchError(ContainSubstring("no checksum published for file [" + uri + "]")))
+
+	files.AllowMissingChecksums(true)
+	defer files.AllowMissingChecksums(false)
+	got, err := r.ReadFileWithChecksum(uri, files.Checksum{})
+	g.Expect(err).To(BeNil(), "remote files without checksum are read with a warning when explicitly allowed")
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+}
+
+func TestReaderReadFileWithPublishedChecksum(t *testing.T) {
+	g := NewWithT(t)
+	checksum := fileChecksum(t, "testdata/file.yaml")
+	published := map[string]string{
+		"/file.yaml.sha256":      checksum.SHA256 + "  file.yaml\n",
+		"/corrupted.yaml.sha256": strings.Repeat("0", 64) + "  corrupted.yaml\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if content, ok := published[req.URL.Path]; ok {
+			fmt.Fprint(w, content)
+			return
+		}
+		if req.URL.Path == "/missing.yaml.sha256" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeFile(w, req, "testdata/file.yaml")
+	}))
+	defer server.Close()
+	r := files.NewReader()
+
+	got, err := r.ReadFileWithPublishedChecksum(server.URL + "/file.yaml")
+	g.Expect(err).To(BeNil())
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+
+	_, err = r.ReadFileWithPublishedChecksum(server.URL + "/corrupted.yaml")
+	g.Expect(err).To(MatchError(ContainSubstring("checksum mismatch for " + server.URL + "/corrupted.yaml")))
+
+	_, err = r.ReadFileWithPublishedChecksum(server.URL + "/missing.yaml")
+	g.Expect(err).To(MatchError(ContainSubstring("failed reading published checksum for [" + server.URL + "/missing.yaml]")))
+
+	got, err = r.ReadFileWithPublishedChecksum("testdata/file.yaml")
+	g.Expect(err).To(BeNil(), "local files are trusted")
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+}
+
+func TestReaderReadFileWithChecksumMismatch(t *testing.T) {
+	tests := []struct {
+		testName string
+		checksum func(files.Checksum) files.Checksum
+		wantErr  string
+	}{
+		{
+			testName: "sha256 mismatch",
+			checksum: func(c files.Checksum) files.Checksum {
+				c.SHA256 = strings.Repeat("0", 64)
+				return c
+			},
+			wantErr: "checksum mismatch for testdata/file.yaml: expected sha256 " + strings.Repeat("0", 64),
+		},
+		{
+			testName: "sha512 mismatch",
+			checksum: func(c files.Checksum) files.Checksum {
+				c.SHA512 = strings.Repeat("0", 128)
+				return c
+			},
+			wantErr: "checksum mismatch for testdata/file.yaml: expected sha512 " + strings.Repeat("0", 128),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			g := NewWithT(t)
+			r := files.NewReader()
+			_, err := r.ReadFileWithChecksum("testdata/file.yaml", tt.checksum(fileChecksum(t, "testdata/file.yaml")))
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestReaderDownloadFile(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(".")))
+	defer server.Close()
+	r := files.NewReader()
+	checksum := fileChecksum(t, "testdata/file.yaml")
+	target := filepath.Join(t.TempDir(), "downloads", "file.yaml")
+
+	g.Expect(r.DownloadFile(context.Background(), server.URL+"/testdata/file.yaml", checksum, target)).To(Succeed())
+	got, err := ioutil.ReadFile(target)
+	g.Expect(err).To(BeNil())
+	test.AssertContentToFile(t, string(got), "testdata/file.yaml")
+}
+
+func TestReaderDownloadFileWithoutChecksum(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(".")))
+	defer server.Close()
+	r := files.NewReader()
+	target := filepath.Join(t.TempDir(), "file.yaml")
+
+	err := r.DownloadFile(context.Background(), server.URL+"/testdata/file.yaml", files.Checksum{}, target)
+	g.Expect(err).To(MatchError(ContainSubstring("no checksum published for file")))
+	_, err = os.Stat(target)
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+}
+
+func TestReaderDownloadFileChecksumMismatch(t *testing.T) {
+	g := NewWithT(t)
+	r := files.NewReader()
+	target := filepath.Join(t.TempDir(), "file.yaml")
+
+	err := r.DownloadFile(context.Background(), "testdata/file.yaml", files.Checksum{SHA256: strings.Repeat("0", 64)}, target)
+	g.Expect(err).To(MatchError(ContainSubstring("checksum mismatch for testdata/file.yaml")))
+	_, err = os.Stat(target)
+	g.Expect(os.IsNotExist(err)).To(BeTrue(), "the file that failed verification is removed")
+}
@@ -0,0 +1,90 @@
+package loadbalancer
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/templater"
+)
+
+//go:embed config/kube-vip.yaml
+var kubeVipTemplate string
+
+//go:embed config/metallb.yaml
+var metalLBTemplate string
+
+type LoadBalancer struct{}
+
+func New() *LoadBalancer {
+	return &LoadBalancer{}
+}
+
+// GenerateManifest returns the manifest for the load balancer configured in the cluster spec
+func (l *LoadBalancer) GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error) {
+	lb := clusterSpec.Spec.LoadBalancer
+	if lb == nil {
+		return nil, fmt.Errorf("no load balancer configured for cluster %s", clusterSpec.Name)
+	}
+
+	switch lb.Provider {
+	case v1alpha1.KubeVipLoadBalancer:
+		return generateKubeVipManifest(clusterSpec)
+	case v1alpha1.MetalLBLoadBalancer:
+		return generateMetalLBManifest(clusterSpec)
+	default:
+		return nil, fmt.Errorf("load balancer provider %s not supported", lb.Provider)
+	}
+}
+
+type kubeVipPool struct {
+	Key   string
+	Value string
+}
+
+func generateKubeVipManifest(clusterSpec *cluster.Spec) ([]byte, error) {
+	bundle := clusterSpec.VersionsBundle
+	if bundle.LoadBalancer.KubeVipCloudProvider.URI == "" {
+		return nil, fmt.Errorf("bundle for kubernetes version %s doesn't include a kube-vip-cloud-provider image", bundle.KubeVersion)
+	}
+
+	// kube-vip-cloud-provider reads CIDRs and ranges from different keys, one pair per namespace
+	var pools []kubeVipPool
+	for _, pool := range clusterSpec.Spec.LoadBalancer.AddressPools {
+		var cidrs, ranges []string
+		for _, addresses := range pool.Addresses {
+			if strings.Contains(addresses, "/") {
+				cidrs = append(cidrs, addresses)
+			} else {
+				ranges = append(ranges, addresses)
+			}
+		}
+		if len(cidrs) > 0 {
+			pools = append(pools, kubeVipPool{Key: "cidr-" + pool.Name, Value: strings.Join(cidrs, ",")})
+		}
+		if len(ranges) > 0 {
+			pools = append(pools, kubeVipPool{Key: "range-" + pool.Name, Value: strings.Join(ranges, ",")})
+		}
+	}
+
+	return templater.Execute(kubeVipTemplate, map[string]interface{}{
+		"kubeVipImage":       bundle.VSphere.KubeVip.VersionedImage(),
+		"cloudProviderImage": bundle.LoadBalancer.KubeVipCloudProvider.VersionedImage(),
+		"pools":              pools,
+	})
+}
+
+func generateMetalLBManifest(clusterSpec *cluster.Spec) ([]byte, error) {
+	bundle := clusterSpec.VersionsBundle
+	if bundle.LoadBalancer.MetalLBController.URI == "" || bundle.LoadBalancer.MetalLBSpeaker.URI == "" {
+		return nil, fmt.Errorf("bundle for kubernetes version %s doesn't include the metallb images", bundle.KubeVersion)
+	}
+
+	return templater.Execute(metalLBTemplate, map[string]interface{}{
+		"controllerImage": bundle.LoadBalancer.MetalLBController.VersionedImage(),
+		"speakerImage":    bundle.LoadBalancer.MetalLBSpeaker.VersionedImage(),
+		"pools":           clusterSpec.Spec.LoadBalancer.AddressPools,
+	})
+}
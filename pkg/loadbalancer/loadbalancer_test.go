@@ -0,0 +1,94 @@
+package loadbalancer_test
+
+import (
+	"testing"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/loadbalancer"
+	"github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+var loadBalancerBundle = v1alpha1.LoadBalancerBundle{
+	KubeVipCloudProvider: v1alpha1.Image{
+		URI: "public.ecr.aws/kube-vip/kube-vip-cloud-provider:v0.0.1-eks-a-1",
+	},
+	MetalLBController: v1alpha1.Image{
+		URI: "public.ecr.aws/metallb/controller:v0.10.3-eks-a-1",
+	},
+	MetalLBSpeaker: v1alpha1.Image{
+		URI: "public.ecr.aws/metallb/speaker:v0.10.3-eks-a-1",
+	},
+}
+
+var addressPools = []eksav1alpha1.LoadBalancerAddressPool{
+	{
+		Name:      eksav1alpha1.GlobalLoadBalancerAddressPool,
+		Addresses: []string{"10.0.0.0/28", "10.0.1.10-10.0.1.20"},
+	},
+	{
+		Name:      "team-a",
+		Addresses: []string{"10.0.2.10-10.0.2.20"},
+	},
+}
+
+func TestLoadBalancerGenerateManifestKubeVip(t *testing.T) {
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.LoadBalancer = loadBalancerBundle
+		s.VersionsBundle.VSphere.KubeVip = v1alpha1.Image{URI: "public.ecr.aws/kube-vip/kube-vip:v0.3.2-eks-a-1"}
+		s.Spec.LoadBalancer = &eksav1alpha1.LoadBalancerConfiguration{
+			Provider:     eksav1alpha1.KubeVipLoadBalancer,
+			AddressPools: addressPools,
+		}
+	})
+
+	gotFileContent, err := loadbalancer.New().GenerateManifest(clusterSpec)
+	if err != nil {
+		t.Fatalf("LoadBalancer.GenerateManifest() error = %v, wantErr nil", err)
+	}
+
+	test.AssertContentToFile(t, string(gotFileContent), "testdata/expected_results_kube_vip.yaml")
+}
+
+func TestLoadBalancerGenerateManifestMetalLB(t *testing.T) {
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.LoadBalancer = loadBalancerBundle
+		s.Spec.LoadBalancer = &eksav1alpha1.LoadBalancerConfiguration{
+			Provider:     eksav1alpha1.MetalLBLoadBalancer,
+			AddressPools: addressPools,
+		}
+	})
+
+	gotFileContent, err := loadbalancer.New().GenerateManifest(clusterSpec)
+	if err != nil {
+		t.Fatalf("LoadBalancer.GenerateManifest() error = %v, wantErr nil", err)
+	}
+
+	test.AssertContentToFile(t, string(gotFileContent), "testdata/expected_results_metallb.yaml")
+}
+
+func TestLoadBalancerGenerateManifestMissingImages(t *testing.T) {
+	for _, provider := range []eksav1alpha1.LoadBalancerProvider{eksav1alpha1.KubeVipLoadBalancer, eksav1alpha1.MetalLBLoadBalancer} {
+		t.Run(string(provider), func(t *testing.T) {
+			clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+				s.Spec.LoadBalancer = &eksav1alpha1.LoadBalancerConfiguration{
+					Provider:     provider,
+					AddressPools: addressPools,
+				}
+			})
+
+			if _, err := loadbalancer.New().GenerateManifest(clusterSpec); err == nil {
+				t.Fatalf("LoadBalancer.GenerateManifest() error = nil, want not nil")
+			}
+		})
+	}
+}
+
+func TestLoadBalancerGenerateManifestNotConfigured(t *testing.T) {
+	clusterSpec := test.NewClusterSpec()
+
+	if _, err := loadbalancer.New().GenerateManifest(clusterSpec); err == nil {
+		t.Fatalf("LoadBalancer.GenerateManifest() error = nil, want not nil")
+	}
+}
@@ -0,0 +1,170 @@
+package managementbackup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	metadataFile = "metadata.yaml"
+	objectsDir   = "objects"
+	// clusterScoped is used in place of the namespace in the archive path of cluster scoped objects
+	clusterScoped = "_cluster"
+)
+
+// Metadata describes the management cluster an archive was taken from
+type Metadata struct {
+	ManagementCluster string    `json:"managementCluster"`
+	WorkloadClusters  []string  `json:"workloadClusters"`
+	CreatedAt         time.Time `json:"createdAt"`
+	// ReconcilingClusters are the CAPI clusters that weren't paused when the backup was taken. They are resumed after a restore
+	ReconcilingClusters []string `json:"reconcilingClusters"`
+	// ReconcilingEKSAClusters are the EKS-A clusters that weren't paused when the backup was taken. They are resumed after a restore
+	ReconcilingEKSAClusters []string `json:"reconcilingEKSAClusters"`
+}
+
+// Object is an object of the management cluster and the resource type it was read from
+type Object struct {
+	Resource string
+	*unstructured.Unstructured
+}
+
+func (o Object) path() string {
+	namespace := o.GetNamespace()
+	if namespace == "" {
+		namespace = clusterScoped
+	}
+	return path.Join(objectsDir, o.Resource, namespace, o.GetName()+".yaml")
+}
+
+// Archive holds the EKS-A and CAPI objects of a management cluster and all its workload clusters
+type Archive struct {
+	Metadata Metadata
+	Objects  []Object
+}
+
+// Write writes the archive to file as a gzipped tarball with a yaml file per object
+func (a *Archive) Write(file string) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating management cluster backup archive: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	content, err := yaml.Marshal(a.Metadata)
+	if err != nil {
+		return fmt.Errorf("error marshalling management cluster backup metadata: %v", err)
+	}
+	if err = writeFile(tw, metadataFile, content); err != nil {
+		return err
+	}
+
+	objects := append([]Object{}, a.Objects...)
+	sort.Slice(objects, func(i, j int) bool { return objects[i].path() < objects[j].path() })
+	for _, o := range objects {
+		content, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return fmt.Errorf("error marshalling %s %s: %v", o.GetKind(), o.GetName(), err)
+		}
+		if err = writeFile(tw, o.path(), content); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("error writing management cluster backup archive: %v", err)
+	}
+	if err = gz.Close(); err != nil {
+		return fmt.Errorf("error writing management cluster backup archive: %v", err)
+	}
+	return nil
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0o600,
+		Size:     int64(len(content)),
+		Typeflag: tar.TypeReg,
+		ModTime:  time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing %s to management cluster backup archive: %v", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("error writing %s to management cluster backup archive: %v", name, err)
+	}
+	return nil
+}
+
+// ReadArchive reads an archive written with Write
+func ReadArchive(file string) (*Archive, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening management cluster backup archive: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading management cluster backup archive %s: %v", file, err)
+	}
+	tr := tar.NewReader(gz)
+
+	a := &Archive{}
+	foundMetadata := false
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading management cluster backup archive %s: %v", file, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from management cluster backup archive: %v", header.Name, err)
+		}
+
+		if header.Name == metadataFile {
+			if err = yaml.Unmarshal(content, &a.Metadata); err != nil {
+				return nil, fmt.Errorf("error parsing management cluster backup metadata: %v", err)
+			}
+			foundMetadata = true
+			continue
+		}
+
+		parts := strings.Split(header.Name, "/")
+		if len(parts) != 4 || parts[0] != objectsDir {
+			return nil, fmt.Errorf("invalid management cluster backup archive %s: unexpected file %s", file, header.Name)
+		}
+		obj := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal(bytes.TrimSpace(content), &obj.Object); err != nil {
+			return nil, fmt.Errorf("error parsing %s from management cluster backup archive: %v", header.Name, err)
+		}
+		a.Objects = append(a.Objects, Object{Resource: parts[1], Unstructured: obj})
+	}
+
+	if !foundMetadata {
+		return nil, fmt.Errorf("invalid management cluster backup archive %s: %s not found", file, metadataFile)
+	}
+	return a, nil
+}
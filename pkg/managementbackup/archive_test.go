@@ -0,0 +1,51 @@
+package managementbackup_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/managementbackup"
+)
+
+var errBoom = errors.New("boom")
+
+func TestArchiveWriteRead(t *testing.T) {
+	g := NewWithT(t)
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+	a := &managementbackup.Archive{
+		Metadata: managementbackup.Metadata{
+			ManagementCluster:   "mgmt",
+			WorkloadClusters:    []string{"w01"},
+			CreatedAt:           time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC),
+			ReconcilingClusters: []string{"mgmt", "w01"},
+		},
+		Objects: []managementbackup.Object{
+			{Resource: eksaClusters, Unstructured: eksaCluster("w01", "mgmt")},
+			{Resource: capiClusters, Unstructured: capiCluster("w01")},
+			{Resource: "crs", Unstructured: object("addons.cluster.x-k8s.io/v1alpha3", "ClusterResourceSet", "", "global")},
+		},
+	}
+
+	g.Expect(a.Write(file)).To(Succeed())
+	read, err := managementbackup.ReadArchive(file)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(read.Metadata).To(Equal(a.Metadata))
+	g.Expect(read.Objects).To(ConsistOf(a.Objects))
+}
+
+func TestArchiveReadInvalid(t *testing.T) {
+	g := NewWithT(t)
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	_, err := managementbackup.ReadArchive(file)
+	g.Expect(err).To(MatchError(ContainSubstring("error opening management cluster backup archive")))
+}
+
+func TestArchiveName(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(managementbackup.ArchiveName("mgmt", time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC))).To(Equal("mgmt-management-backup-20211101T100000Z.tar.gz"))
+}
@@ -0,0 +1,245 @@
+package managementbackup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	crdResource    = "customresourcedefinitions.apiextensions.k8s.io"
+	secretResource = "secrets"
+	cmResource     = "configmaps"
+	// clusterctlLabel is set in the CRDs of all the CAPI providers. These are the types clusterctl move discovers
+	clusterctlLabel     = "clusterctl.cluster.x-k8s.io"
+	clusterctlCoreLabel = "clusterctl.cluster.x-k8s.io/core"
+	inventoryCoreValue  = "inventory"
+
+	serviceAccountTokenType = "kubernetes.io/service-account-token"
+	// bundlesKind objects are applied for each cluster with the cluster name
+	bundlesKind = "Bundles"
+	pausePatch  = `{"spec":{"paused":true}}`
+	resumePatch = `{"spec":{"paused":false}}`
+)
+
+var (
+	capiClusterResource = "clusters." + clusterv1.GroupVersion.Group
+	eksaClusterResource = (&v1alpha1.Cluster{}).ResourceType()
+	pausedAnnotation    = (&v1alpha1.Cluster{}).PausedAnnotation()
+)
+
+type KubectlClient interface {
+	GetObjects(ctx context.Context, resourceType string, opts ...executables.KubectlOpt) ([]unstructured.Unstructured, error)
+	CreateObject(ctx context.Context, cluster *types.Cluster, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	MergePatchResource(ctx context.Context, resourceType, objectName, patch string, cluster *types.Cluster, namespace string) error
+	RemoveAnnotationInNamespace(ctx context.Context, resourceType, objectName, key string, cluster *types.Cluster, namespace string) error
+	GetNamespace(ctx context.Context, kubeconfig string, namespace string) error
+	CreateNamespace(ctx context.Context, kubeconfig string, namespace string) error
+}
+
+type Backupper struct {
+	kubectl KubectlClient
+	now     func() time.Time
+}
+
+type BackupperOpt func(*Backupper)
+
+// WithClock sets the function used to get the time the backup is taken at
+func WithClock(now func() time.Time) BackupperOpt {
+	return func(b *Backupper) {
+		b.now = now
+	}
+}
+
+func NewBackupper(kubectl KubectlClient, opts ...BackupperOpt) *Backupper {
+	b := &Backupper{
+		kubectl: kubectl,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Backup exports the EKS-A objects of managementCluster and the CAPI objects of all its clusters.
+// Like clusterctl move, it discovers the CAPI types from the CRDs of the installed providers and pauses the clusters
+// while they are read. The archived clusters are paused, so they can be restored before any controller reconciles them
+func (b *Backupper) Backup(ctx context.Context, managementCluster *types.Cluster) (*Archive, error) {
+	capiResources, eksaResources, err := b.discoverResources(ctx, managementCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	capiClusters, err := b.kubectl.GetObjects(ctx, capiClusterResource, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{Metadata: Metadata{ManagementCluster: managementCluster.Name, CreatedAt: b.now().UTC()}}
+	for _, c := range capiClusters {
+		if paused, _, _ := unstructured.NestedBool(c.Object, "spec", "paused"); !paused {
+			a.Metadata.ReconcilingClusters = append(a.Metadata.ReconcilingClusters, c.GetName())
+		}
+	}
+	sort.Strings(a.Metadata.ReconcilingClusters)
+
+	logger.V(3).Info("Pausing CAPI clusters reconciliation", "clusters", a.Metadata.ReconcilingClusters)
+	if err = b.setClustersPause(ctx, managementCluster, a.Metadata.ReconcilingClusters, pausePatch); err != nil {
+		return nil, err
+	}
+	err = b.readObjects(ctx, managementCluster, a, capiResources, eksaResources)
+	logger.V(3).Info("Resuming CAPI clusters reconciliation", "clusters", a.Metadata.ReconcilingClusters)
+	if resumeErr := b.setClustersPause(ctx, managementCluster, a.Metadata.ReconcilingClusters, resumePatch); resumeErr != nil && err == nil {
+		err = resumeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// discoverResources returns the CAPI and EKS-A resource types installed in cluster
+func (b *Backupper) discoverResources(ctx context.Context, cluster *types.Cluster) (capiResources, eksaResources []string, err error) {
+	crds, err := b.kubectl.GetObjects(ctx, crdResource, executables.WithCluster(cluster))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, crd := range crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		resource := plural + "." + group
+		labels := crd.GetLabels()
+		switch {
+		case group == v1alpha1.GroupVersion.Group:
+			eksaResources = append(eksaResources, resource)
+		case hasKey(labels, clusterctlLabel) && labels[clusterctlCoreLabel] != inventoryCoreValue:
+			capiResources = append(capiResources, resource)
+		}
+	}
+	sort.Strings(capiResources)
+	sort.Strings(eksaResources)
+	return capiResources, eksaResources, nil
+}
+
+func (b *Backupper) readObjects(ctx context.Context, cluster *types.Cluster, a *Archive, capiResources, eksaResources []string) error {
+	clusterNames := []string{}
+	ownerUIDs := map[string]bool{}
+	for _, resource := range capiResources {
+		objs, err := b.kubectl.GetObjects(ctx, resource, executables.WithCluster(cluster), executables.WithNamespace(constants.EksaSystemNamespace))
+		if err != nil {
+			return err
+		}
+		for i := range objs {
+			if resource == capiClusterResource {
+				clusterNames = append(clusterNames, objs[i].GetName())
+			}
+			ownerUIDs[string(objs[i].GetUID())] = true
+			a.Objects = append(a.Objects, newObject(resource, &objs[i]))
+		}
+	}
+
+	for _, resource := range []string{secretResource, cmResource} {
+		objs, err := b.kubectl.GetObjects(ctx, resource, executables.WithCluster(cluster), executables.WithNamespace(constants.EksaSystemNamespace))
+		if err != nil {
+			return err
+		}
+		for i := range objs {
+			if clusterObject(&objs[i], clusterNames, ownerUIDs) {
+				a.Objects = append(a.Objects, newObject(resource, &objs[i]))
+			}
+		}
+	}
+
+	for _, resource := range eksaResources {
+		objs, err := b.kubectl.GetObjects(ctx, resource, executables.WithCluster(cluster), executables.WithAllNamespaces())
+		if err != nil {
+			return err
+		}
+		for i := range objs {
+			if resource == eksaClusterResource {
+				b.pauseEKSACluster(a, &objs[i])
+			}
+			a.Objects = append(a.Objects, newObject(resource, &objs[i]))
+		}
+	}
+	sort.Strings(a.Metadata.WorkloadClusters)
+	sort.Strings(a.Metadata.ReconcilingEKSAClusters)
+
+	return nil
+}
+
+// pauseEKSACluster records the EKS-A cluster in the metadata and pauses its archived copy
+func (b *Backupper) pauseEKSACluster(a *Archive, c *unstructured.Unstructured) {
+	if c.GetName() != a.Metadata.ManagementCluster {
+		a.Metadata.WorkloadClusters = append(a.Metadata.WorkloadClusters, c.GetName())
+	}
+	annotations := c.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[pausedAnnotation] != "true" {
+		a.Metadata.ReconcilingEKSAClusters = append(a.Metadata.ReconcilingEKSAClusters, c.GetName())
+	}
+	annotations[pausedAnnotation] = "true"
+	c.SetAnnotations(annotations)
+}
+
+func (b *Backupper) setClustersPause(ctx context.Context, cluster *types.Cluster, clusterNames []string, patch string) error {
+	for _, name := range clusterNames {
+		if err := b.kubectl.MergePatchResource(ctx, capiClusterResource, name, patch, cluster, constants.EksaSystemNamespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterObject returns true if the secret or config map obj belongs to a cluster, either because it's owned by
+// one of its objects or because it has the cluster-name label, as the kubeconfig and certificate secrets do
+func clusterObject(obj *unstructured.Unstructured, clusterNames []string, ownerUIDs map[string]bool) bool {
+	if t, _, _ := unstructured.NestedString(obj.Object, "type"); t == serviceAccountTokenType {
+		return false
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ownerUIDs[string(ref.UID)] {
+			return true
+		}
+	}
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterLabelName]
+	if !ok {
+		return false
+	}
+	for _, name := range clusterNames {
+		if clusterName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newObject(resource string, obj *unstructured.Unstructured) Object {
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	return Object{Resource: resource, Unstructured: obj}
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// ArchiveName returns the default name of the archive of clusterName taken at t
+func ArchiveName(clusterName string, t time.Time) string {
+	return fmt.Sprintf("%s-management-backup-%s.tar.gz", clusterName, t.UTC().Format("20060102T150405Z"))
+}
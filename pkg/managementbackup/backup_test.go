@@ -0,0 +1,215 @@
+package managementbackup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/managementbackup"
+	"github.com/aws/eks-anywhere/pkg/managementbackup/mocks"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	capiClusters = "clusters.cluster.x-k8s.io"
+	kcps         = "kubeadmcontrolplanes.controlplane.cluster.x-k8s.io"
+	machines     = "machines.cluster.x-k8s.io"
+	eksaClusters = "clusters.anywhere.eks.amazonaws.com"
+	eksaDCs      = "vspheredatacenterconfigs.anywhere.eks.amazonaws.com"
+	paused       = "anywhere.eks.amazonaws.com/paused"
+)
+
+type backupTest struct {
+	*WithT
+	ctx               context.Context
+	kubectl           *mocks.MockKubectlClient
+	managementCluster *types.Cluster
+}
+
+func newBackupTest(t *testing.T) *backupTest {
+	return &backupTest{
+		WithT:             NewWithT(t),
+		ctx:               context.Background(),
+		kubectl:           mocks.NewMockKubectlClient(gomock.NewController(t)),
+		managementCluster: &types.Cluster{Name: "mgmt", KubeconfigFile: "mgmt.kubeconfig"},
+	}
+}
+
+type objOpt func(*unstructured.Unstructured)
+
+func withOwner(owner *unstructured.Unstructured) objOpt {
+	return func(o *unstructured.Unstructured) {
+		o.SetOwnerReferences(append(o.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID(),
+		}))
+	}
+}
+
+func withLabel(key, value string) objOpt {
+	return func(o *unstructured.Unstructured) {
+		labels := o.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = value
+		o.SetLabels(labels)
+	}
+}
+
+func withField(value interface{}, fields ...string) objOpt {
+	return func(o *unstructured.Unstructured) {
+		_ = unstructured.SetNestedField(o.Object, value, fields...)
+	}
+}
+
+func object(apiVersion, kind, namespace, name string, opts ...objOpt) *unstructured.Unstructured {
+	o := &unstructured.Unstructured{}
+	o.SetAPIVersion(apiVersion)
+	o.SetKind(kind)
+	o.SetNamespace(namespace)
+	o.SetName(name)
+	o.SetUID(apitypes.UID("uid-" + apiVersion + "-" + kind + "-" + name))
+	o.SetResourceVersion("1")
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func crd(group, plural string, labels map[string]string) unstructured.Unstructured {
+	o := object("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", plural+"."+group)
+	o.SetLabels(labels)
+	_ = unstructured.SetNestedField(o.Object, group, "spec", "group")
+	_ = unstructured.SetNestedField(o.Object, plural, "spec", "names", "plural")
+	return *o
+}
+
+func capiCluster(name string, opts ...objOpt) *unstructured.Unstructured {
+	return object("cluster.x-k8s.io/v1alpha3", "Cluster", "eksa-system", name, opts...)
+}
+
+func eksaCluster(name, managementCluster string, opts ...objOpt) *unstructured.Unstructured {
+	return object("anywhere.eks.amazonaws.com/v1alpha1", "Cluster", "default", name,
+		append([]objOpt{withField(managementCluster, "spec", "managementCluster", "name")}, opts...)...)
+}
+
+func items(objs ...*unstructured.Unstructured) []unstructured.Unstructured {
+	l := make([]unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		l = append(l, *o.DeepCopy())
+	}
+	return l
+}
+
+func (tt *backupTest) expectGet(resource string, namespaced bool, objs ...*unstructured.Unstructured) {
+	ns := executables.WithNamespace("eksa-system")
+	if !namespaced {
+		ns = executables.WithAllNamespaces()
+	}
+	tt.kubectl.EXPECT().GetObjects(tt.ctx, resource, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, opts ...executables.KubectlOpt) ([]unstructured.Unstructured, error) {
+			tt.Expect(applyOpts(opts...)).To(Equal(applyOpts(executables.WithCluster(tt.managementCluster), ns)))
+			return items(objs...), nil
+		},
+	)
+}
+
+func applyOpts(opts ...executables.KubectlOpt) []string {
+	args := []string{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+	return args
+}
+
+func TestBackup(t *testing.T) {
+	tt := newBackupTest(t)
+	mgmt := capiCluster("mgmt")
+	workload := capiCluster("w01", withField(true, "spec", "paused"))
+	workloadPaused := capiCluster("w01", withField(true, "spec", "paused"))
+	mgmtPaused := capiCluster("mgmt", withField(true, "spec", "paused"))
+	kcp := object("controlplane.cluster.x-k8s.io/v1alpha3", "KubeadmControlPlane", "eksa-system", "w01", withOwner(workload))
+	kubeconfig := object("v1", "Secret", "eksa-system", "w01-kubeconfig", withLabel("cluster.x-k8s.io/cluster-name", "w01"))
+	unlabeled := object("v1", "Secret", "eksa-system", "w01-unlabeled")
+	ownedConfig := object("v1", "Secret", "eksa-system", "bootstrap-data", withOwner(kcp))
+	token := object("v1", "Secret", "eksa-system", "w01-token", withField("kubernetes.io/service-account-token", "type"))
+	other := object("v1", "ConfigMap", "eksa-system", "kube-root-ca.crt")
+
+	tt.kubectl.EXPECT().GetObjects(tt.ctx, "customresourcedefinitions.apiextensions.k8s.io", gomock.Any()).Return([]unstructured.Unstructured{
+		crd("cluster.x-k8s.io", "clusters", map[string]string{"clusterctl.cluster.x-k8s.io": "", "cluster.x-k8s.io/provider": "cluster-api"}),
+		crd("controlplane.cluster.x-k8s.io", "kubeadmcontrolplanes", map[string]string{"clusterctl.cluster.x-k8s.io": ""}),
+		crd("clusterctl.cluster.x-k8s.io", "providers", map[string]string{"clusterctl.cluster.x-k8s.io": "", "clusterctl.cluster.x-k8s.io/core": "inventory"}),
+		crd("anywhere.eks.amazonaws.com", "clusters", nil),
+		crd("cert-manager.io", "certificates", nil),
+	}, nil)
+	tt.expectGet(capiClusters, true, mgmt, workload)
+	gomock.InOrder(
+		tt.kubectl.EXPECT().MergePatchResource(tt.ctx, capiClusters, "mgmt", `{"spec":{"paused":true}}`, tt.managementCluster, "eksa-system"),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, capiClusters, gomock.Any(), gomock.Any()).Return(items(mgmtPaused, workloadPaused), nil),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, kcps, gomock.Any(), gomock.Any()).Return(items(kcp), nil),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, "secrets", gomock.Any(), gomock.Any()).Return(items(kubeconfig, unlabeled, ownedConfig, token), nil),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, "configmaps", gomock.Any(), gomock.Any()).Return(items(other), nil),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, eksaClusters, gomock.Any(), gomock.Any()).Return(items(
+			eksaCluster("mgmt", "mgmt"),
+			eksaCluster("w01", "mgmt", withField(map[string]interface{}{paused: "true"}, "metadata", "annotations")),
+			eksaCluster("w02", "mgmt"),
+		), nil),
+		tt.kubectl.EXPECT().MergePatchResource(tt.ctx, capiClusters, "mgmt", `{"spec":{"paused":false}}`, tt.managementCluster, "eksa-system"),
+	)
+
+	now := time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC)
+	a, err := managementbackup.NewBackupper(tt.kubectl, managementbackup.WithClock(func() time.Time { return now })).Backup(tt.ctx, tt.managementCluster)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(a.Metadata).To(Equal(managementbackup.Metadata{
+		ManagementCluster:       "mgmt",
+		WorkloadClusters:        []string{"w01", "w02"},
+		CreatedAt:               now,
+		ReconcilingClusters:     []string{"mgmt"},
+		ReconcilingEKSAClusters: []string{"mgmt", "w02"},
+	}))
+
+	names := []string{}
+	for _, o := range a.Objects {
+		names = append(names, o.Resource+"/"+o.GetName())
+		if o.Resource == capiClusters {
+			isPaused, _, _ := unstructured.NestedBool(o.Object, "spec", "paused")
+			tt.Expect(isPaused).To(BeTrue())
+		}
+		if o.Resource == eksaClusters {
+			tt.Expect(o.GetAnnotations()).To(HaveKeyWithValue(paused, "true"))
+		}
+	}
+	tt.Expect(names).To(Equal([]string{
+		capiClusters + "/mgmt",
+		capiClusters + "/w01",
+		kcps + "/w01",
+		"secrets/w01-kubeconfig",
+		"secrets/bootstrap-data",
+		eksaClusters + "/mgmt",
+		eksaClusters + "/w01",
+		eksaClusters + "/w02",
+	}))
+}
+
+func TestBackupResumesClustersOnError(t *testing.T) {
+	tt := newBackupTest(t)
+	tt.kubectl.EXPECT().GetObjects(tt.ctx, "customresourcedefinitions.apiextensions.k8s.io", gomock.Any()).Return([]unstructured.Unstructured{
+		crd("cluster.x-k8s.io", "clusters", map[string]string{"clusterctl.cluster.x-k8s.io": ""}),
+	}, nil)
+	tt.expectGet(capiClusters, true, capiCluster("mgmt"))
+	gomock.InOrder(
+		tt.kubectl.EXPECT().MergePatchResource(tt.ctx, capiClusters, "mgmt", `{"spec":{"paused":true}}`, tt.managementCluster, "eksa-system"),
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, capiClusters, gomock.Any(), gomock.Any()).Return(nil, errBoom),
+		tt.kubectl.EXPECT().MergePatchResource(tt.ctx, capiClusters, "mgmt", `{"spec":{"paused":false}}`, tt.managementCluster, "eksa-system"),
+	)
+
+	_, err := managementbackup.NewBackupper(tt.kubectl).Backup(tt.ctx, tt.managementCluster)
+	tt.Expect(err).To(MatchError(errBoom))
+}
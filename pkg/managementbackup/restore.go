@@ -0,0 +1,362 @@
+package managementbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+type Restorer struct {
+	kubectl KubectlClient
+}
+
+func NewRestorer(kubectl KubectlClient) *Restorer {
+	return &Restorer{kubectl: kubectl}
+}
+
+// Restore creates the objects of the workload clusters in the archive in managementCluster, which replaces the
+// management cluster the archive was taken from. The objects of the old management cluster itself aren't restored.
+// Like clusterctl move, objects are created after their owners, with the owner references pointing to the new owners,
+// and the clusters are only resumed once all their objects exist, so CAPI adopts the existing machines instead of
+// creating new ones. Objects that already exist in managementCluster are left untouched.
+// It returns the restored workload clusters
+func (r *Restorer) Restore(ctx context.Context, managementCluster *types.Cluster, a *Archive) ([]string, error) {
+	if err := checkArchive(a); err != nil {
+		return nil, err
+	}
+
+	objs, err := objectsToRestore(a)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range objs {
+		if o.Resource == eksaClusterResource {
+			setManagementCluster(o.Unstructured, a.Metadata.ManagementCluster, managementCluster.Name)
+		}
+	}
+
+	if err := r.createNamespaces(ctx, managementCluster, objs); err != nil {
+		return nil, err
+	}
+
+	existing, err := r.existingObjects(ctx, managementCluster, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.createObjects(ctx, managementCluster, objs, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.resumeClusters(ctx, managementCluster, a, created)
+}
+
+// objectsToRestore returns the objects in the archive that belong to its workload clusters. CAPI objects, secrets and
+// config maps belong to the CAPI cluster in their cluster-name label or owning them, directly or through their owners.
+// EKS-A objects belong to the EKS-A clusters referencing them, and bundles to the cluster they are applied for.
+// Objects shared with the management cluster are restored if a workload cluster uses them.
+// It returns an error if any object can't be matched to a cluster
+func objectsToRestore(a *Archive) ([]Object, error) {
+	byUID := map[apitypes.UID]*unstructured.Unstructured{}
+	eksaUsers := map[string][]string{}
+	for _, o := range a.Objects {
+		byUID[o.GetUID()] = o.Unstructured
+		if o.Resource == eksaClusterResource {
+			refs, err := eksaClusterRefs(o.Unstructured)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range refs {
+				eksaUsers[key] = append(eksaUsers[key], o.GetName())
+			}
+		}
+	}
+
+	objs := make([]Object, 0, len(a.Objects))
+	unresolved := []string{}
+	for _, o := range a.Objects {
+		var clusters []string
+		if strings.HasSuffix(o.Resource, "."+v1alpha1.GroupVersion.Group) {
+			clusters = eksaUsers[eksaObjectKey(o.GetKind(), o.GetNamespace(), o.GetName())]
+		} else if name := capiClusterName(o.Unstructured, o.Resource, byUID, map[apitypes.UID]bool{}); name != "" {
+			clusters = []string{name}
+		}
+
+		if len(clusters) == 0 {
+			unresolved = append(unresolved, fmt.Sprintf("%s %s/%s", o.GetKind(), o.GetNamespace(), o.GetName()))
+			continue
+		}
+		if onlyCluster(clusters, a.Metadata.ManagementCluster) {
+			continue
+		}
+		objs = append(objs, Object{Resource: o.Resource, Unstructured: o.DeepCopy()})
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("invalid management cluster backup archive, objects not matching any cluster: %s", strings.Join(unresolved, ", "))
+	}
+	return objs, nil
+}
+
+// capiClusterName returns the name of the CAPI cluster obj belongs to, from its cluster-name label or following its
+// owners in the archive. It returns an empty name if none of them leads to a cluster
+func capiClusterName(obj *unstructured.Unstructured, resource string, byUID map[apitypes.UID]*unstructured.Unstructured, visited map[apitypes.UID]bool) string {
+	if resource == capiClusterResource || (obj.GetKind() == "Cluster" && strings.HasPrefix(obj.GetAPIVersion(), clusterv1.GroupVersion.Group+"/")) {
+		return obj.GetName()
+	}
+	if name, ok := obj.GetLabels()[clusterv1.ClusterLabelName]; ok {
+		return name
+	}
+
+	visited[obj.GetUID()] = true
+	for _, ref := range obj.GetOwnerReferences() {
+		owner, ok := byUID[ref.UID]
+		if !ok || visited[ref.UID] {
+			continue
+		}
+		if name := capiClusterName(owner, "", byUID, visited); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// eksaClusterRefs returns the keys of the EKS-A objects the EKS-A cluster obj uses: itself, the objects in its
+// spec refs, all in the cluster namespace, and the bundles applied for it, which are named after the cluster
+func eksaClusterRefs(obj *unstructured.Unstructured) ([]string, error) {
+	c := &v1alpha1.Cluster{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, c); err != nil {
+		return nil, fmt.Errorf("invalid %s %s in management cluster backup archive: %v", v1alpha1.ClusterKind, obj.GetName(), err)
+	}
+
+	refs := []v1alpha1.Ref{{Kind: v1alpha1.ClusterKind, Name: c.Name}, {Kind: bundlesKind, Name: c.Name}, c.Spec.DatacenterRef}
+	refs = append(refs, c.Spec.IdentityProviderRefs...)
+	if c.Spec.GitOpsRef != nil {
+		refs = append(refs, *c.Spec.GitOpsRef)
+	}
+	if r := c.Spec.ControlPlaneConfiguration.MachineGroupRef; r != nil {
+		refs = append(refs, *r)
+	}
+	for _, w := range c.Spec.WorkerNodeGroupConfigurations {
+		if w.MachineGroupRef != nil {
+			refs = append(refs, *w.MachineGroupRef)
+		}
+	}
+	if c.Spec.ExternalEtcdConfiguration != nil && c.Spec.ExternalEtcdConfiguration.MachineGroupRef != nil {
+		refs = append(refs, *c.Spec.ExternalEtcdConfiguration.MachineGroupRef)
+	}
+
+	keys := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if r.Kind != "" && r.Name != "" {
+			keys = append(keys, eksaObjectKey(r.Kind, c.Namespace, r.Name))
+		}
+	}
+	return keys, nil
+}
+
+func eksaObjectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// onlyCluster returns true if name is the only cluster in clusters
+func onlyCluster(clusters []string, name string) bool {
+	for _, c := range clusters {
+		if c != name {
+			return false
+		}
+	}
+	return true
+}
+
+// setManagementCluster points the EKS-A cluster obj managed by oldName to newName
+func setManagementCluster(obj *unstructured.Unstructured, oldName, newName string) {
+	if oldName == newName {
+		return
+	}
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", "managementCluster", "name"); name == oldName {
+		_ = unstructured.SetNestedField(obj.Object, newName, "spec", "managementCluster", "name")
+	}
+}
+
+func (r *Restorer) createNamespaces(ctx context.Context, cluster *types.Cluster, objs []Object) error {
+	namespaces := map[string]bool{}
+	for _, o := range objs {
+		if ns := o.GetNamespace(); ns != "" && !namespaces[ns] {
+			namespaces[ns] = true
+			if err := r.kubectl.GetNamespace(ctx, cluster.KubeconfigFile, ns); err == nil {
+				continue
+			}
+			if err := r.kubectl.CreateNamespace(ctx, cluster.KubeconfigFile, ns); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func objectKey(resource, namespace, name string) string {
+	return resource + "/" + namespace + "/" + name
+}
+
+// existingObjects returns the UIDs of the objects in cluster with the same resource, namespace and name as objs
+func (r *Restorer) existingObjects(ctx context.Context, cluster *types.Cluster, objs []Object) (map[string]apitypes.UID, error) {
+	resources := []string{}
+	seen := map[string]bool{}
+	for _, o := range objs {
+		if !seen[o.Resource] {
+			seen[o.Resource] = true
+			resources = append(resources, o.Resource)
+		}
+	}
+
+	existing := map[string]apitypes.UID{}
+	for _, resource := range resources {
+		current, err := r.kubectl.GetObjects(ctx, resource, executables.WithCluster(cluster), executables.WithAllNamespaces())
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range current {
+			existing[objectKey(resource, c.GetNamespace(), c.GetName())] = c.GetUID()
+		}
+	}
+	return existing, nil
+}
+
+// createObjects creates objs in rounds, each one creating the objects whose owners already exist.
+// It returns the keys of the created objects
+func (r *Restorer) createObjects(ctx context.Context, cluster *types.Cluster, objs []Object, existing map[string]apitypes.UID) (map[string]bool, error) {
+	inArchive := map[apitypes.UID]bool{}
+	for _, o := range objs {
+		inArchive[o.GetUID()] = true
+	}
+
+	newUIDs := map[apitypes.UID]apitypes.UID{}
+	created := map[string]bool{}
+	pending := objs
+	for len(pending) > 0 {
+		next := make([]Object, 0, len(pending))
+		for _, o := range pending {
+			if !ownersRestored(o.Unstructured, inArchive, newUIDs) {
+				next = append(next, o)
+				continue
+			}
+
+			key := objectKey(o.Resource, o.GetNamespace(), o.GetName())
+			if uid, ok := existing[key]; ok {
+				logger.V(3).Info("Object already exists, skipping", "kind", o.GetKind(), "name", o.GetName())
+				newUIDs[o.GetUID()] = uid
+				continue
+			}
+
+			logger.V(3).Info("Restoring object", "kind", o.GetKind(), "name", o.GetName())
+			n, err := r.kubectl.CreateObject(ctx, cluster, targetObject(o.Unstructured, inArchive, newUIDs))
+			if err != nil {
+				return nil, err
+			}
+			newUIDs[o.GetUID()] = n.GetUID()
+			created[key] = true
+		}
+
+		if len(next) == len(pending) {
+			return nil, errors.New("error restoring management cluster objects: owner references can't be resolved")
+		}
+		pending = next
+	}
+	return created, nil
+}
+
+func ownersRestored(obj *unstructured.Unstructured, inArchive map[apitypes.UID]bool, newUIDs map[apitypes.UID]apitypes.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, ok := newUIDs[ref.UID]; inArchive[ref.UID] && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// targetObject returns obj without the fields set by the api server and with its owner references pointing to the
+// restored owners. References to owners not being restored are dropped
+func targetObject(obj *unstructured.Unstructured, inArchive map[apitypes.UID]bool, newUIDs map[apitypes.UID]apitypes.UID) *unstructured.Unstructured {
+	t := obj.DeepCopy()
+	for _, field := range []string{"uid", "resourceVersion", "creationTimestamp", "generation", "selfLink", "managedFields"} {
+		unstructured.RemoveNestedField(t.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(t.Object, "status")
+
+	refs := []metav1.OwnerReference{}
+	for _, ref := range obj.GetOwnerReferences() {
+		if !inArchive[ref.UID] {
+			continue
+		}
+		ref.UID = newUIDs[ref.UID]
+		refs = append(refs, ref)
+	}
+	t.SetOwnerReferences(refs)
+	return t
+}
+
+// resumeClusters resumes the restored clusters that were reconciling when the backup was taken
+func (r *Restorer) resumeClusters(ctx context.Context, cluster *types.Cluster, a *Archive, created map[string]bool) ([]string, error) {
+	eksaNamespaces := map[string]string{}
+	for _, o := range a.Objects {
+		if o.Resource == eksaClusterResource {
+			eksaNamespaces[o.GetName()] = o.GetNamespace()
+		}
+	}
+
+	for _, name := range a.Metadata.ReconcilingClusters {
+		if !created[objectKey(capiClusterResource, constants.EksaSystemNamespace, name)] {
+			continue
+		}
+		logger.V(3).Info("Resuming CAPI cluster reconciliation", "cluster", name)
+		if err := r.kubectl.MergePatchResource(ctx, capiClusterResource, name, resumePatch, cluster, constants.EksaSystemNamespace); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range a.Metadata.ReconcilingEKSAClusters {
+		if !created[objectKey(eksaClusterResource, eksaNamespaces[name], name)] {
+			continue
+		}
+		logger.V(3).Info("Resuming EKS-A cluster reconciliation", "cluster", name)
+		if err := r.kubectl.RemoveAnnotationInNamespace(ctx, eksaClusterResource, name, pausedAnnotation, cluster, eksaNamespaces[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	restored := []string{}
+	for _, name := range a.Metadata.WorkloadClusters {
+		if created[objectKey(eksaClusterResource, eksaNamespaces[name], name)] {
+			restored = append(restored, name)
+		}
+	}
+	return restored, nil
+}
+
+// checkArchive fails if the archive wasn't taken from an EKS-A management cluster
+func checkArchive(a *Archive) error {
+	if a.Metadata.ManagementCluster == "" {
+		return fmt.Errorf("invalid management cluster backup archive: management cluster name is missing")
+	}
+	for _, o := range a.Objects {
+		if o.Resource == eksaClusterResource && o.GetName() == a.Metadata.ManagementCluster {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid management cluster backup archive: %s %s not found", v1alpha1.ClusterKind, a.Metadata.ManagementCluster)
+}
@@ -0,0 +1,123 @@
+package managementbackup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/eks-anywhere/pkg/managementbackup"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+func obj(resource string, u *unstructured.Unstructured) managementbackup.Object {
+	return managementbackup.Object{Resource: resource, Unstructured: u}
+}
+
+func TestRestore(t *testing.T) {
+	tt := newBackupTest(t)
+	newManagementCluster := &types.Cluster{Name: "mgmt-new", KubeconfigFile: "mgmt-new.kubeconfig"}
+	mgmt := capiCluster("mgmt", withField(true, "spec", "paused"))
+	mgmtKCP := object("controlplane.cluster.x-k8s.io/v1alpha3", "KubeadmControlPlane", "eksa-system", "mgmt", withOwner(mgmt))
+	mgmtMachine := object("cluster.x-k8s.io/v1alpha3", "Machine", "eksa-system", "mgmt-abcde", withOwner(mgmtKCP))
+	workload := capiCluster("w01", withField(true, "spec", "paused"))
+	kcp := object("controlplane.cluster.x-k8s.io/v1alpha3", "KubeadmControlPlane", "eksa-system", "w01", withOwner(workload))
+	machine := object("cluster.x-k8s.io/v1alpha3", "Machine", "eksa-system", "w01-abcde",
+		withOwner(kcp), withLabel("cluster.x-k8s.io/cluster-name", "w01"), withField(map[string]interface{}{"ready": true}, "status"))
+	dc := object("anywhere.eks.amazonaws.com/v1alpha1", "VSphereDatacenterConfig", "default", "datacenter")
+	a := &managementbackup.Archive{
+		Metadata: managementbackup.Metadata{
+			ManagementCluster:       "mgmt",
+			WorkloadClusters:        []string{"w01"},
+			ReconcilingClusters:     []string{"mgmt", "w01"},
+			ReconcilingEKSAClusters: []string{"mgmt", "w01"},
+		},
+		Objects: []managementbackup.Object{
+			obj(machines, machine),
+			obj(machines, mgmtMachine),
+			obj(kcps, kcp),
+			obj(kcps, mgmtKCP),
+			obj(capiClusters, workload),
+			obj(capiClusters, mgmt),
+			obj("secrets", object("v1", "Secret", "eksa-system", "mgmt-kubeconfig", withLabel("cluster.x-k8s.io/cluster-name", "mgmt"))),
+			obj("secrets", object("v1", "Secret", "eksa-system", "w01-lookalike", withLabel("cluster.x-k8s.io/cluster-name", "mgmt"))),
+			obj(eksaClusters, eksaCluster("mgmt", "mgmt", withDatacenterRef("mgmt-datacenter"))),
+			obj(eksaClusters, eksaCluster("w01", "mgmt", withDatacenterRef("datacenter"))),
+			obj(eksaDCs, object("anywhere.eks.amazonaws.com/v1alpha1", "VSphereDatacenterConfig", "default", "mgmt-datacenter")),
+			obj(eksaDCs, dc),
+		},
+	}
+
+	tt.kubectl.EXPECT().GetNamespace(tt.ctx, "mgmt-new.kubeconfig", "eksa-system").Return(nil)
+	tt.kubectl.EXPECT().GetNamespace(tt.ctx, "mgmt-new.kubeconfig", "default").Return(errBoom)
+	tt.kubectl.EXPECT().CreateNamespace(tt.ctx, "mgmt-new.kubeconfig", "default").Return(nil)
+	for _, resource := range []string{machines, kcps, capiClusters, eksaClusters} {
+		tt.kubectl.EXPECT().GetObjects(tt.ctx, resource, gomock.Any(), gomock.Any()).Return(nil, nil)
+	}
+	tt.kubectl.EXPECT().GetObjects(tt.ctx, eksaDCs, gomock.Any(), gomock.Any()).Return(items(object("anywhere.eks.amazonaws.com/v1alpha1", "VSphereDatacenterConfig", "default", "datacenter")), nil)
+
+	created := []string{}
+	tt.kubectl.EXPECT().CreateObject(tt.ctx, newManagementCluster, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *types.Cluster, o *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			tt.Expect(o.GetUID()).To(BeEmpty())
+			tt.Expect(o.GetResourceVersion()).To(BeEmpty())
+			tt.Expect(o.Object).NotTo(HaveKey("status"))
+			for _, ref := range o.GetOwnerReferences() {
+				tt.Expect(string(ref.UID)).To(HavePrefix("new-"))
+			}
+			if o.GetKind() == "Cluster" && o.GetAPIVersion() == "anywhere.eks.amazonaws.com/v1alpha1" {
+				name, _, _ := unstructured.NestedString(o.Object, "spec", "managementCluster", "name")
+				tt.Expect(name).To(Equal("mgmt-new"))
+			}
+			created = append(created, o.GetKind()+"/"+o.GetName())
+			n := o.DeepCopy()
+			n.SetUID(apitypes.UID("new-" + o.GetName()))
+			return n, nil
+		},
+	).Times(4)
+	tt.kubectl.EXPECT().MergePatchResource(tt.ctx, capiClusters, "w01", `{"spec":{"paused":false}}`, newManagementCluster, "eksa-system")
+	tt.kubectl.EXPECT().RemoveAnnotationInNamespace(tt.ctx, eksaClusters, "w01", paused, newManagementCluster, "default")
+
+	restored, err := managementbackup.NewRestorer(tt.kubectl).Restore(tt.ctx, newManagementCluster, a)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(restored).To(Equal([]string{"w01"}))
+	tt.Expect(created).To(Equal([]string{"Cluster/w01", "Cluster/w01", "KubeadmControlPlane/w01", "Machine/w01-abcde"}))
+}
+
+func withDatacenterRef(name string) objOpt {
+	return withField(map[string]interface{}{"kind": "VSphereDatacenterConfig", "name": name}, "spec", "datacenterRef")
+}
+
+func TestRestoreObjectsWithoutCluster(t *testing.T) {
+	tt := newBackupTest(t)
+	workload := capiCluster("w01")
+	a := &managementbackup.Archive{
+		Metadata: managementbackup.Metadata{ManagementCluster: "mgmt"},
+		Objects: []managementbackup.Object{
+			obj(capiClusters, workload),
+			obj(kcps, object("controlplane.cluster.x-k8s.io/v1alpha3", "KubeadmControlPlane", "eksa-system", "w01", withOwner(workload))),
+			obj("secrets", object("v1", "Secret", "eksa-system", "w01-kubeconfig")),
+			obj(eksaClusters, eksaCluster("mgmt", "mgmt")),
+			obj(eksaClusters, eksaCluster("w01", "mgmt")),
+			obj(eksaDCs, object("anywhere.eks.amazonaws.com/v1alpha1", "VSphereDatacenterConfig", "default", "w01")),
+		},
+	}
+
+	_, err := managementbackup.NewRestorer(tt.kubectl).Restore(tt.ctx, tt.managementCluster, a)
+	tt.Expect(err).To(MatchError("invalid management cluster backup archive, objects not matching any cluster: " +
+		"Secret eksa-system/w01-kubeconfig, VSphereDatacenterConfig default/w01"))
+}
+
+func TestRestoreInvalidArchive(t *testing.T) {
+	tt := newBackupTest(t)
+	a := &managementbackup.Archive{
+		Metadata: managementbackup.Metadata{ManagementCluster: "mgmt"},
+		Objects:  []managementbackup.Object{obj(eksaClusters, eksaCluster("w01", "mgmt"))},
+	}
+
+	_, err := managementbackup.NewRestorer(tt.kubectl).Restore(tt.ctx, tt.managementCluster, a)
+	tt.Expect(err).To(MatchError("invalid management cluster backup archive: Cluster mgmt not found"))
+}
@@ -1,12 +1,21 @@
 package networking
 
 import (
+	_ "embed"
 	"fmt"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/networkutils"
+	"github.com/aws/eks-anywhere/pkg/templater"
 	"github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
+//go:embed config/hubble-relay.yaml
+var hubbleRelayTemplate string
+
+//go:embed config/hubble-ui.yaml
+var hubbleUITemplate string
+
 type Cilium struct{}
 
 func NewCilium() *Cilium {
@@ -14,7 +23,111 @@ func NewCilium() *Cilium {
 }
 
 func (c *Cilium) GenerateManifest(clusterSpec *cluster.Spec) ([]byte, error) {
-	return loadManifest(clusterSpec, clusterSpec.VersionsBundle.Cilium.Manifest)
+	manifest, err := loadManifest(clusterSpec, clusterSpec.VersionsBundle.Cilium.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	ciliumConfig := clusterSpec.Spec.ClusterNetwork.Cilium
+	if ciliumConfig == nil && !networkutils.HasIPv6CIDR(clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks) {
+		return manifest, nil
+	}
+
+	manifest, err = renderCiliumManifest(manifest, clusterSpec)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering cilium manifest: %v", err)
+	}
+
+	if !hubbleRelayEnabled(clusterSpec) {
+		return manifest, nil
+	}
+
+	hubbleManifests, err := generateHubbleManifests(clusterSpec)
+	if err != nil {
+		return nil, fmt.Errorf("error generating hubble manifests: %v", err)
+	}
+
+	return templater.AppendYamlResources(manifest, hubbleManifests), nil
+}
+
+// GenerateRemovedManifest returns the objects deployed for currentSpec that newSpec doesn't deploy anymore,
+// so they can be deleted on upgrade. These are the hubble-relay and hubble-ui components when they get disabled.
+// It returns nil if nothing was removed.
+func (c *Cilium) GenerateRemovedManifest(currentSpec, newSpec *cluster.Spec) ([]byte, error) {
+	bundle := currentSpec.VersionsBundle.Cilium
+	var manifests [][]byte
+
+	if hubbleRelayEnabled(currentSpec) && !hubbleRelayEnabled(newSpec) {
+		relay, err := renderHubbleRelay(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("error generating hubble-relay manifest: %v", err)
+		}
+		manifests = append(manifests, relay)
+	}
+
+	if hubbleUIEnabled(currentSpec) && !hubbleUIEnabled(newSpec) {
+		ui, err := renderHubbleUI(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("error generating hubble-ui manifest: %v", err)
+		}
+		manifests = append(manifests, ui)
+	}
+
+	if len(manifests) == 0 {
+		return nil, nil
+	}
+
+	return templater.AppendYamlResources(manifests...), nil
+}
+
+func hubbleRelayEnabled(clusterSpec *cluster.Spec) bool {
+	ciliumConfig := clusterSpec.Spec.ClusterNetwork.Cilium
+	return ciliumConfig != nil && ciliumConfig.Hubble != nil && ciliumConfig.Hubble.Relay
+}
+
+// hubbleUIEnabled only reports the UI as enabled together with hubble-relay, the UI isn't deployed without it
+func hubbleUIEnabled(clusterSpec *cluster.Spec) bool {
+	return hubbleRelayEnabled(clusterSpec) && clusterSpec.Spec.ClusterNetwork.Cilium.Hubble.UI
+}
+
+func generateHubbleManifests(clusterSpec *cluster.Spec) ([]byte, error) {
+	bundle := clusterSpec.VersionsBundle.Cilium
+	if bundle.HubbleRelay.URI == "" {
+		return nil, fmt.Errorf("cilium bundle %s doesn't include a hubble-relay image", bundle.Version)
+	}
+
+	manifests, err := renderHubbleRelay(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hubbleUIEnabled(clusterSpec) {
+		return manifests, nil
+	}
+
+	if bundle.HubbleUI.URI == "" || bundle.HubbleUIBackend.URI == "" {
+		return nil, fmt.Errorf("cilium bundle %s doesn't include the hubble-ui images", bundle.Version)
+	}
+
+	uiManifests, err := renderHubbleUI(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return templater.AppendYamlResources(manifests, uiManifests), nil
+}
+
+func renderHubbleRelay(bundle v1alpha1.CiliumBundle) ([]byte, error) {
+	return templater.Execute(hubbleRelayTemplate, map[string]string{
+		"relayImage": bundle.HubbleRelay.VersionedImage(),
+	})
+}
+
+func renderHubbleUI(bundle v1alpha1.CiliumBundle) ([]byte, error) {
+	return templater.Execute(hubbleUITemplate, map[string]string{
+		"uiImage":        bundle.HubbleUI.VersionedImage(),
+		"uiBackendImage": bundle.HubbleUIBackend.VersionedImage(),
+	})
 }
 
 func loadManifest(clusterSpec *cluster.Spec, manifest v1alpha1.Manifest) ([]byte, error) {
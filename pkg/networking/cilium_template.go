@@ -0,0 +1,229 @@
+package networking
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/networkutils"
+)
+
+const (
+	ciliumConfigMapName    = "cilium-config"
+	ciliumDaemonSetName    = "cilium"
+	ciliumOperatorName     = "cilium-operator"
+	ciliumAgentContainer   = "cilium-agent"
+	ciliumIPsecVolumeName  = "cilium-ipsec-secrets"
+	ciliumIPsecMountPath   = "/etc/ipsec"
+	ciliumClusterPoolMask  = "24"
+	ciliumClusterPoolMask6 = "120"
+	ciliumManifestSplitter = "\n---\n"
+
+	ciliumConfigChecksumAnnotation = "anywhere.eks.amazonaws.com/cilium-config-checksum"
+)
+
+// renderCiliumManifest applies the cluster spec cilium configuration on top of the bundled manifest.
+// Only the cilium-config ConfigMap and the pod templates of the cilium agent DaemonSet and operator Deployment
+// are modified, every other object is kept verbatim. The pod templates are annotated with a checksum of the
+// rendered config, so a config-only change rolls the pods that read it.
+func renderCiliumManifest(manifest []byte, clusterSpec *cluster.Spec) ([]byte, error) {
+	ciliumConfig := clusterSpec.Spec.ClusterNetwork.Cilium
+	ipsec := ciliumConfig != nil && ciliumConfig.Encryption != nil && ciliumConfig.Encryption.Type == v1alpha1.CiliumEncryptionIPsec
+
+	docs := strings.Split(string(manifest), ciliumManifestSplitter)
+	objs := make([]*unstructured.Unstructured, len(docs))
+	configMap := -1
+	for i, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+			return nil, fmt.Errorf("invalid object in cilium manifest: %v", err)
+		}
+		objs[i] = obj
+		if obj.GetKind() == "ConfigMap" && obj.GetName() == ciliumConfigMapName {
+			configMap = i
+		}
+	}
+
+	if configMap < 0 {
+		return nil, fmt.Errorf("configmap %s not found in cilium manifest", ciliumConfigMapName)
+	}
+
+	configChecksum, err := setConfigMapData(objs[configMap], ciliumConfigValues(clusterSpec))
+	if err != nil {
+		return nil, fmt.Errorf("failed updating ConfigMap %s: %v", ciliumConfigMapName, err)
+	}
+
+	for i, obj := range objs {
+		switch {
+		case i == configMap:
+		case obj.GetKind() == "DaemonSet" && obj.GetName() == ciliumDaemonSetName:
+			if err = setPodTemplateAnnotation(obj, ciliumConfigChecksumAnnotation, configChecksum); err == nil && ipsec {
+				err = addIPsecKeysVolume(obj, ciliumConfig.Encryption.KeySecretName())
+			}
+		case obj.GetKind() == "Deployment" && obj.GetName() == ciliumOperatorName:
+			err = setPodTemplateAnnotation(obj, ciliumConfigChecksumAnnotation, configChecksum)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed updating %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+
+		content, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		docs[i] = string(content)
+	}
+
+	return []byte(strings.Join(docs, ciliumManifestSplitter)), nil
+}
+
+func ciliumConfigValues(clusterSpec *cluster.Spec) map[string]string {
+	podCidrs := clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks
+	values := map[string]string{}
+
+	// IPv4-only clusters keep the defaults from the bundled manifest
+	if networkutils.HasIPv6CIDR(podCidrs) {
+		values["enable-ipv4"] = strconv.FormatBool(networkutils.HasIPv4CIDR(podCidrs))
+		values["enable-ipv6"] = "true"
+	}
+
+	ciliumConfig := clusterSpec.Spec.ClusterNetwork.Cilium
+	if ciliumConfig == nil {
+		return values
+	}
+
+	masquerade := strconv.FormatBool(ciliumConfig.EgressMasqueradeEnabled())
+	values["masquerade"] = masquerade
+	values["enable-bpf-masquerade"] = masquerade
+
+	if ciliumConfig.PolicyEnforcementMode != "" {
+		values["enable-policy"] = string(ciliumConfig.PolicyEnforcementMode)
+	}
+
+	if ciliumConfig.IPAMMode != "" {
+		values["ipam"] = string(ciliumConfig.IPAMMode)
+	}
+	if ciliumConfig.IPAMMode == v1alpha1.CiliumIPAMClusterPool {
+		if ipv4Cidrs := networkutils.FilterCIDRsByFamily(podCidrs, networkutils.IPv4Family); len(ipv4Cidrs) > 0 {
+			values["cluster-pool-ipv4-cidr"] = strings.Join(ipv4Cidrs, " ")
+			values["cluster-pool-ipv4-mask-size"] = ciliumClusterPoolMask
+		}
+		if ipv6Cidrs := networkutils.FilterCIDRsByFamily(podCidrs, networkutils.IPv6Family); len(ipv6Cidrs) > 0 {
+			values["cluster-pool-ipv6-cidr"] = strings.Join(ipv6Cidrs, " ")
+			values["cluster-pool-ipv6-mask-size"] = ciliumClusterPoolMask6
+		}
+	}
+
+	if ciliumConfig.Hubble != nil && ciliumConfig.Hubble.Relay {
+		// hubble-relay talks to the agents over plain gRPC, the agent port is only reachable inside the cluster
+		values["enable-hubble"] = "true"
+		values["hubble-disable-tls"] = "true"
+	}
+
+	if ciliumConfig.Encryption != nil {
+		switch ciliumConfig.Encryption.Type {
+		case v1alpha1.CiliumEncryptionWireguard:
+			values["enable-wireguard"] = "true"
+		case v1alpha1.CiliumEncryptionIPsec:
+			values["enable-ipsec"] = "true"
+			values["ipsec-key-file"] = ciliumIPsecMountPath + "/keys"
+		}
+	}
+
+	return values
+}
+
+// setConfigMapData merges values into the ConfigMap data and returns a checksum of the resulting data
+func setConfigMapData(obj *unstructured.Unstructured, values map[string]string) (string, error) {
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	for k, v := range values {
+		data[k] = v
+	}
+
+	if err = unstructured.SetNestedStringMap(obj.Object, data, "data"); err != nil {
+		return "", err
+	}
+
+	// json sorts map keys, so the checksum only depends on the content
+	content, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+func setPodTemplateAnnotation(obj *unstructured.Unstructured, key, value string) error {
+	fields := []string{"spec", "template", "metadata", "annotations"}
+	// the bundled manifests declare empty annotations as null
+	annotations := map[string]interface{}{}
+	if current, found, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...); found && current != nil {
+		existing, ok := current.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is of type %T, expected a map", strings.Join(fields, "."), current)
+		}
+		annotations = existing
+	}
+	annotations[key] = value
+
+	return unstructured.SetNestedField(obj.Object, annotations, fields...)
+}
+
+func addIPsecKeysVolume(obj *unstructured.Unstructured, secretName string) error {
+	volumes, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "volumes")
+	if err != nil {
+		return err
+	}
+	volumes = append(volumes, map[string]interface{}{
+		"name": ciliumIPsecVolumeName,
+		"secret": map[string]interface{}{
+			"secretName": secretName,
+		},
+	})
+	if err = unstructured.SetNestedSlice(obj.Object, volumes, "spec", "template", "spec", "volumes"); err != nil {
+		return err
+	}
+
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return err
+	}
+	for i, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] != ciliumAgentContainer {
+			continue
+		}
+		mounts, _, err := unstructured.NestedSlice(container, "volumeMounts")
+		if err != nil {
+			return err
+		}
+		mounts = append(mounts, map[string]interface{}{
+			"name":      ciliumIPsecVolumeName,
+			"mountPath": ciliumIPsecMountPath,
+			"readOnly":  true,
+		})
+		if err = unstructured.SetNestedSlice(container, mounts, "volumeMounts"); err != nil {
+			return err
+		}
+		containers[i] = container
+
+		return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+	}
+
+	return fmt.Errorf("container %s not found", ciliumAgentContainer)
+}
@@ -1,9 +1,11 @@
 package networking_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/aws/eks-anywhere/internal/test"
+	eksav1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/networking"
 	"github.com/aws/eks-anywhere/release/api/v1alpha1"
@@ -47,3 +49,129 @@ func TestCiliumGenerateManifestWriterError(t *testing.T) {
 		t.Fatalf("Cilium.GenerateManifestFile() error = nil, want not nil")
 	}
 }
+
+func TestCiliumGenerateManifestWithCiliumConfig(t *testing.T) {
+	disabled := false
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.Cilium = ciliumBundle
+		s.VersionsBundle.Cilium.HubbleRelay = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-relay:v1.9.10-eksa.1"}
+		s.VersionsBundle.Cilium.HubbleUI = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-ui:v0.7.9-eksa.1"}
+		s.VersionsBundle.Cilium.HubbleUIBackend = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-ui-backend:v0.7.9-eksa.1"}
+		s.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"192.168.0.0/16"}
+		s.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+			PolicyEnforcementMode: eksav1alpha1.CiliumPolicyModeAlways,
+			IPAMMode:              eksav1alpha1.CiliumIPAMClusterPool,
+			Hubble:                &eksav1alpha1.HubbleConfig{Relay: true, UI: true},
+			Encryption:            &eksav1alpha1.CiliumEncryption{Type: eksav1alpha1.CiliumEncryptionIPsec},
+			EgressMasquerade:      &disabled,
+		}
+	})
+
+	c := networking.NewCilium()
+
+	gotFileContent, err := c.GenerateManifest(clusterSpec)
+	if err != nil {
+		t.Fatalf("Cilium.GenerateManifestFile() error = %v, wantErr nil", err)
+	}
+
+	test.AssertContentToFile(t, string(gotFileContent), "testdata/expected_results_cilium_config.yaml")
+}
+
+func TestCiliumGenerateManifestHubbleRelayMissingImage(t *testing.T) {
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.Cilium = ciliumBundle
+		s.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+			Hubble: &eksav1alpha1.HubbleConfig{Relay: true},
+		}
+	})
+
+	c := networking.NewCilium()
+
+	if _, err := c.GenerateManifest(clusterSpec); err == nil {
+		t.Fatalf("Cilium.GenerateManifestFile() error = nil, want not nil")
+	}
+}
+
+func TestCiliumGenerateManifestDualStack(t *testing.T) {
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.Cilium = ciliumBundle
+		s.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"192.168.0.0/16", "fd00:100::/56"}
+		s.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+			IPAMMode: eksav1alpha1.CiliumIPAMClusterPool,
+		}
+	})
+
+	c := networking.NewCilium()
+
+	gotFileContent, err := c.GenerateManifest(clusterSpec)
+	if err != nil {
+		t.Fatalf("Cilium.GenerateManifestFile() error = %v, wantErr nil", err)
+	}
+
+	test.AssertContentToFile(t, string(gotFileContent), "testdata/expected_results_cilium_dual_stack.yaml")
+}
+
+func TestCiliumGenerateManifestIPv6WithoutCiliumConfig(t *testing.T) {
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.Cilium = ciliumBundle
+		s.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"fd00:100::/56"}
+	})
+
+	c := networking.NewCilium()
+
+	gotFileContent, err := c.GenerateManifest(clusterSpec)
+	if err != nil {
+		t.Fatalf("Cilium.GenerateManifestFile() error = %v, wantErr nil", err)
+	}
+
+	for _, want := range []string{`enable-ipv4: "false"`, `enable-ipv6: "true"`} {
+		if !strings.Contains(string(gotFileContent), want) {
+			t.Errorf("Cilium.GenerateManifestFile() content doesn't contain %s", want)
+		}
+	}
+}
+
+func TestCiliumGenerateRemovedManifestHubbleDisabled(t *testing.T) {
+	currentSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.Cilium = ciliumBundle
+		s.VersionsBundle.Cilium.HubbleRelay = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-relay:v1.9.10-eksa.1"}
+		s.VersionsBundle.Cilium.HubbleUI = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-ui:v0.7.9-eksa.1"}
+		s.VersionsBundle.Cilium.HubbleUIBackend = v1alpha1.Image{URI: "public.ecr.aws/isovalent/hubble-ui-backend:v0.7.9-eksa.1"}
+		s.Spec.ClusterNetwork.Cilium = &eksav1alpha1.CiliumConfig{
+			Hubble: &eksav1alpha1.HubbleConfig{Relay: true, UI: true},
+		}
+	})
+
+	tests := []struct {
+		name        string
+		hubble      *eksav1alpha1.HubbleConfig
+		wantRelay   bool
+		wantUI      bool
+		wantRemoved bool
+	}{
+		{name: "hubble removed", hubble: nil, wantRelay: true, wantUI: true, wantRemoved: true},
+		{name: "relay disabled", hubble: &eksav1alpha1.HubbleConfig{UI: true}, wantRelay: true, wantUI: true, wantRemoved: true},
+		{name: "ui disabled", hubble: &eksav1alpha1.HubbleConfig{Relay: true}, wantUI: true, wantRemoved: true},
+		{name: "hubble unchanged", hubble: &eksav1alpha1.HubbleConfig{Relay: true, UI: true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			newSpec := currentSpec.DeepCopy()
+			newSpec.Spec.ClusterNetwork.Cilium.Hubble = tc.hubble
+
+			got, err := networking.NewCilium().GenerateRemovedManifest(currentSpec, newSpec)
+			if err != nil {
+				t.Fatalf("Cilium.GenerateRemovedManifest() error = %v, wantErr nil", err)
+			}
+			if (len(got) > 0) != tc.wantRemoved {
+				t.Fatalf("Cilium.GenerateRemovedManifest() = %s, want removed objects %t", got, tc.wantRemoved)
+			}
+			if gotRelay := strings.Contains(string(got), "name: hubble-relay"); gotRelay != tc.wantRelay {
+				t.Errorf("Cilium.GenerateRemovedManifest() includes hubble-relay = %t, want %t", gotRelay, tc.wantRelay)
+			}
+			if gotUI := strings.Contains(string(got), "name: hubble-ui"); gotUI != tc.wantUI {
+				t.Errorf("Cilium.GenerateRemovedManifest() includes hubble-ui = %t, want %t", gotUI, tc.wantUI)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package networkutils
+
+import (
+	"fmt"
+	"net"
+)
+
+type IPFamily string
+
+const (
+	IPv4Family IPFamily = "IPv4"
+	IPv6Family IPFamily = "IPv6"
+)
+
+// IsIPv6 returns true if ip is a valid IPv6 address that is not an IPv4-mapped address
+func IsIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
+}
+
+// ParseIPFamily returns the family of an IP address
+func ParseIPFamily(ip string) (IPFamily, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("invalid IP address %s", ip)
+	}
+	return familyOf(parsed), nil
+}
+
+// ParseCIDRFamily returns the family of a CIDR block
+func ParseCIDRFamily(cidrBlock string) (IPFamily, error) {
+	ip, _, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return "", err
+	}
+	return familyOf(ip), nil
+}
+
+// CIDRFamilies returns the family of each CIDR block, in the same order
+func CIDRFamilies(cidrBlocks []string) ([]IPFamily, error) {
+	families := make([]IPFamily, 0, len(cidrBlocks))
+	for _, cidrBlock := range cidrBlocks {
+		family, err := ParseCIDRFamily(cidrBlock)
+		if err != nil {
+			return nil, err
+		}
+		families = append(families, family)
+	}
+	return families, nil
+}
+
+// HasIPv6CIDR returns true if at least one of the CIDR blocks is IPv6
+func HasIPv6CIDR(cidrBlocks []string) bool {
+	for _, cidrBlock := range cidrBlocks {
+		if family, err := ParseCIDRFamily(cidrBlock); err == nil && family == IPv6Family {
+			return true
+		}
+	}
+	return false
+}
+
+// HasIPv4CIDR returns true if at least one of the CIDR blocks is IPv4
+func HasIPv4CIDR(cidrBlocks []string) bool {
+	for _, cidrBlock := range cidrBlocks {
+		if family, err := ParseCIDRFamily(cidrBlock); err == nil && family == IPv4Family {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterCIDRsByFamily returns the CIDR blocks that belong to family
+func FilterCIDRsByFamily(cidrBlocks []string, family IPFamily) []string {
+	var filtered []string
+	for _, cidrBlock := range cidrBlocks {
+		if f, err := ParseCIDRFamily(cidrBlock); err == nil && f == family {
+			filtered = append(filtered, cidrBlock)
+		}
+	}
+	return filtered
+}
+
+func familyOf(ip net.IP) IPFamily {
+	if ip.To4() != nil {
+		return IPv4Family
+	}
+	return IPv6Family
+}
@@ -0,0 +1,51 @@
+package networkutils_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/networkutils"
+)
+
+func TestIsIPv6(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(networkutils.IsIPv6("fd00::1")).To(BeTrue())
+	g.Expect(networkutils.IsIPv6("10.0.0.1")).To(BeFalse())
+	g.Expect(networkutils.IsIPv6("::ffff:10.0.0.1")).To(BeFalse())
+	g.Expect(networkutils.IsIPv6("not-an-ip")).To(BeFalse())
+}
+
+func TestParseIPFamily(t *testing.T) {
+	g := NewWithT(t)
+	family, err := networkutils.ParseIPFamily("fd00::1")
+	g.Expect(err).To(BeNil())
+	g.Expect(family).To(Equal(networkutils.IPv6Family))
+
+	family, err = networkutils.ParseIPFamily("10.0.0.1")
+	g.Expect(err).To(BeNil())
+	g.Expect(family).To(Equal(networkutils.IPv4Family))
+
+	_, err = networkutils.ParseIPFamily("10.0.0.300")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid IP address")))
+}
+
+func TestCIDRFamilies(t *testing.T) {
+	g := NewWithT(t)
+	families, err := networkutils.CIDRFamilies([]string{"192.168.0.0/16", "fd00:100::/56"})
+	g.Expect(err).To(BeNil())
+	g.Expect(families).To(Equal([]networkutils.IPFamily{networkutils.IPv4Family, networkutils.IPv6Family}))
+
+	_, err = networkutils.CIDRFamilies([]string{"192.168.0.0/33"})
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestCIDRFamilyHelpers(t *testing.T) {
+	g := NewWithT(t)
+	cidrs := []string{"192.168.0.0/16", "fd00:100::/56"}
+	g.Expect(networkutils.HasIPv4CIDR(cidrs)).To(BeTrue())
+	g.Expect(networkutils.HasIPv6CIDR(cidrs)).To(BeTrue())
+	g.Expect(networkutils.HasIPv6CIDR(cidrs[:1])).To(BeFalse())
+	g.Expect(networkutils.HasIPv4CIDR(cidrs[1:])).To(BeFalse())
+	g.Expect(networkutils.FilterCIDRsByFamily(cidrs, networkutils.IPv6Family)).To(Equal([]string{"fd00:100::/56"}))
+}
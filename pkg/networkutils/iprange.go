@@ -0,0 +1,99 @@
+package networkutils
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IPRange is an inclusive range of IP addresses
+type IPRange struct {
+	Start net.IP
+	End   net.IP
+}
+
+// ParseIPRange parses either a CIDR block (10.0.0.0/24) or an address range (10.0.0.10-10.0.0.20)
+func ParseIPRange(addresses string) (*IPRange, error) {
+	if strings.Contains(addresses, "/") {
+		return parseCIDRRange(addresses)
+	}
+
+	bounds := strings.Split(addresses, "-")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("invalid address range %s, must be a CIDR or a range in the form start-end", addresses)
+	}
+
+	start := net.ParseIP(strings.TrimSpace(bounds[0]))
+	end := net.ParseIP(strings.TrimSpace(bounds[1]))
+	if start == nil || end == nil {
+		return nil, fmt.Errorf("invalid address range %s, start and end must be valid IP addresses", addresses)
+	}
+	if (start.To4() == nil) != (end.To4() == nil) {
+		return nil, fmt.Errorf("invalid address range %s, start and end must belong to the same IP family", addresses)
+	}
+
+	r := &IPRange{Start: normalizeIP(start), End: normalizeIP(end)}
+	if bytes.Compare(r.Start, r.End) > 0 {
+		return nil, fmt.Errorf("invalid address range %s, start is greater than end", addresses)
+	}
+
+	return r, nil
+}
+
+func parseCIDRRange(cidrBlock string) (*IPRange, error) {
+	_, cidr, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	start := normalizeIP(cidr.IP)
+	end := make(net.IP, len(start))
+	for i := range start {
+		end[i] = start[i] | ^cidr.Mask[i]
+	}
+
+	return &IPRange{Start: start, End: end}, nil
+}
+
+// Contains returns true if ip is within the range
+func (r *IPRange) Contains(ip net.IP) bool {
+	ip = normalizeIP(ip)
+	if len(ip) != len(r.Start) {
+		return false
+	}
+	return bytes.Compare(ip, r.Start) >= 0 && bytes.Compare(ip, r.End) <= 0
+}
+
+// Overlaps returns true if both ranges share at least one address
+func (r *IPRange) Overlaps(o *IPRange) bool {
+	if len(r.Start) != len(o.Start) {
+		return false
+	}
+	return bytes.Compare(r.Start, o.End) <= 0 && bytes.Compare(o.Start, r.End) <= 0
+}
+
+func (r *IPRange) String() string {
+	return fmt.Sprintf("%s-%s", r.Start, r.End)
+}
+
+// RangesOverlap returns true if the addresses, each a CIDR block or a range, have at least one address in common
+func RangesOverlap(a, b string) (bool, error) {
+	rangeA, err := ParseIPRange(a)
+	if err != nil {
+		return false, err
+	}
+	rangeB, err := ParseIPRange(b)
+	if err != nil {
+		return false, err
+	}
+
+	return rangeA.Overlaps(rangeB), nil
+}
+
+func normalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
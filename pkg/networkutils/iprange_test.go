@@ -0,0 +1,136 @@
+package networkutils_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/networkutils"
+)
+
+func TestParseIPRange(t *testing.T) {
+	tests := []struct {
+		testName  string
+		addresses string
+		wantStart string
+		wantEnd   string
+		wantErr   string
+	}{
+		{
+			testName:  "cidr block",
+			addresses: "10.0.0.0/24",
+			wantStart: "10.0.0.0",
+			wantEnd:   "10.0.0.255",
+		},
+		{
+			testName:  "address range",
+			addresses: "10.0.0.10-10.0.0.20",
+			wantStart: "10.0.0.10",
+			wantEnd:   "10.0.0.20",
+		},
+		{
+			testName:  "single address range",
+			addresses: "10.0.0.10-10.0.0.10",
+			wantStart: "10.0.0.10",
+			wantEnd:   "10.0.0.10",
+		},
+		{
+			testName:  "invalid cidr",
+			addresses: "10.0.0.0/33",
+			wantErr:   "invalid CIDR address",
+		},
+		{
+			testName:  "not a range",
+			addresses: "10.0.0.10",
+			wantErr:   "must be a CIDR or a range",
+		},
+		{
+			testName:  "invalid ip in range",
+			addresses: "10.0.0.10-10.0.0.300",
+			wantErr:   "must be valid IP addresses",
+		},
+		{
+			testName:  "mixed families",
+			addresses: "10.0.0.10-fd00::1",
+			wantErr:   "same IP family",
+		},
+		{
+			testName:  "start greater than end",
+			addresses: "10.0.0.20-10.0.0.10",
+			wantErr:   "start is greater than end",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			g := NewWithT(t)
+			r, err := networkutils.ParseIPRange(tt.addresses)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(r.Start.String()).To(Equal(tt.wantStart))
+			g.Expect(r.End.String()).To(Equal(tt.wantEnd))
+		})
+	}
+}
+
+func TestIPRangeContains(t *testing.T) {
+	g := NewWithT(t)
+	r, err := networkutils.ParseIPRange("10.0.0.10-10.0.0.20")
+	g.Expect(err).To(BeNil())
+
+	g.Expect(r.Contains(net.ParseIP("10.0.0.10"))).To(BeTrue())
+	g.Expect(r.Contains(net.ParseIP("10.0.0.15"))).To(BeTrue())
+	g.Expect(r.Contains(net.ParseIP("10.0.0.20"))).To(BeTrue())
+	g.Expect(r.Contains(net.ParseIP("10.0.0.21"))).To(BeFalse())
+	g.Expect(r.Contains(net.ParseIP("fd00::1"))).To(BeFalse())
+}
+
+func TestRangesOverlap(t *testing.T) {
+	tests := []struct {
+		testName string
+		a, b     string
+		want     bool
+	}{
+		{
+			testName: "disjoint cidrs",
+			a:        "192.168.0.0/16",
+			b:        "10.96.0.0/12",
+			want:     false,
+		},
+		{
+			testName: "nested cidrs",
+			a:        "192.168.0.0/16",
+			b:        "192.168.10.0/24",
+			want:     true,
+		},
+		{
+			testName: "range inside cidr",
+			a:        "10.0.0.10-10.0.0.20",
+			b:        "10.0.0.0/24",
+			want:     true,
+		},
+		{
+			testName: "adjacent ranges",
+			a:        "10.0.0.10-10.0.0.20",
+			b:        "10.0.0.21-10.0.0.30",
+			want:     false,
+		},
+		{
+			testName: "touching ranges",
+			a:        "10.0.0.10-10.0.0.20",
+			b:        "10.0.0.20-10.0.0.30",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := networkutils.RangesOverlap(tt.a, tt.b)
+			g.Expect(err).To(BeNil())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
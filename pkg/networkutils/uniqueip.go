@@ -55,9 +55,13 @@ func (ipgen *ipgenerator) IsIPUnique(ip string) bool {
 // generates a random ip within the specified cidr block
 func (ipgen *ipgenerator) randIp(cidr *net.IPNet) (net.IP, error) {
 	rand.Seed(time.Now().UnixNano())
-	newIp := *new(net.IP)
-	for i := 0; i < 4; i++ {
-		newIp = append(newIp, byte(rand.Intn(256))&^cidr.Mask[i]|cidr.IP[i])
+	base := normalizeIP(cidr.IP)
+	if len(base) != len(cidr.Mask) {
+		return nil, fmt.Errorf("random IP generation failed, invalid mask for %s", cidr)
+	}
+	newIp := make(net.IP, 0, len(base))
+	for i := range base {
+		newIp = append(newIp, byte(rand.Intn(256))&^cidr.Mask[i]|base[i])
 	}
 	if !cidr.Contains(newIp) {
 		return nil, fmt.Errorf("random IP generation failed")
@@ -46,3 +46,17 @@ func TestIsIPUniqueFail(t *testing.T) {
 		t.Fatalf("Expected IP: %s to be not be unique but it is", ip)
 	}
 }
+
+func TestGenerateUniqueIPv6(t *testing.T) {
+	cidrBlock := "fd00:100::/120"
+
+	ipgen := networkutils.NewIPGenerator(&DummyNetClient{})
+	ip, err := ipgen.GenerateUniqueIP(cidrBlock)
+	if err != nil {
+		t.Fatalf("GenerateUniqueIP() ip = %v error: %v", ip, err)
+	}
+	_, cidr, _ := net.ParseCIDR(cidrBlock)
+	if !cidr.Contains(net.ParseIP(ip)) {
+		t.Fatalf("GenerateUniqueIP() ip = %v not in %s", ip, cidrBlock)
+	}
+}
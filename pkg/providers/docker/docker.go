@@ -185,6 +185,8 @@ func buildTemplateMapCP(clusterSpec *cluster.Spec) map[string]interface{} {
 		"auditPolicy":            common.GetAuditPolicy(),
 		"podCidrs":               clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks,
 		"serviceCidrs":           clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks,
+		"ipv6":                   clusterSpec.Spec.ClusterNetwork.IPv6Enabled(),
+		"dualStack":              clusterSpec.Spec.ClusterNetwork.DualStack(),
 	}
 
 	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
@@ -211,7 +213,14 @@ func buildTemplateMapMD(clusterSpec *cluster.Spec) map[string]interface{} {
 		"kubernetesVersion":   bundle.KubeDistro.Kubernetes.Tag,
 		"kindNodeImage":       bundle.EksD.KindNode.VersionedImage(),
 		"eksaSystemNamespace": constants.EksaSystemNamespace,
+		"ipv6":                clusterSpec.Spec.ClusterNetwork.IPv6Enabled(),
+		"dualStack":           clusterSpec.Spec.ClusterNetwork.DualStack(),
 	}
+
+	if gates := clusterSpec.Spec.UpgradeHealthGates; gates != nil && gates.NodeDrainTimeout != nil {
+		values["nodeDrainTimeout"] = gates.NodeDrainTimeout.Duration.String()
+	}
+
 	return values
 }
 
@@ -88,11 +88,13 @@ func TestProviderFactoryBuildProvider(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 			mockCtrl := gomock.NewController(t)
+			writer := mockswriter.NewMockFileWriter(mockCtrl)
+			writer.EXPECT().Dir().Return("generated").AnyTimes()
 			p := &factory.ProviderFactory{
 				DockerClient:         dockerMocks.NewMockProviderClient(mockCtrl),
 				VSphereGovcClient:    vsphereMocks.NewMockProviderGovcClient(mockCtrl),
 				VSphereKubectlClient: vsphereMocks.NewMockProviderKubectlClient(mockCtrl),
-				Writer:               mockswriter.NewMockFileWriter(mockCtrl),
+				Writer:               writer,
 			}
 			got, err := p.BuildProvider(tt.args.clusterConfigFileName, tt.args.clusterConfig, false)
 			if err == nil {
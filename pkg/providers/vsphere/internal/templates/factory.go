@@ -3,12 +3,17 @@ package templates
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/files"
 	"github.com/aws/eks-anywhere/pkg/logger"
 	"github.com/aws/eks-anywhere/pkg/providers/vsphere/internal/tags"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
 const (
@@ -18,6 +23,8 @@ const (
 
 type Factory struct {
 	client          GovcClient
+	ovaDownloader   OVADownloader
+	ovaDir          string
 	datacenter      string
 	datastore       string
 	resourcePool    string
@@ -40,9 +47,16 @@ type GovcClient interface {
 	CreateCategoryForVM(ctx context.Context, name string) error
 }
 
-func NewFactory(client GovcClient, datacenter, datastore, resourcePool, templateLibrary string) *Factory {
+// OVADownloader downloads the OVAs and verifies their checksums before they are imported
+type OVADownloader interface {
+	DownloadFile(ctx context.Context, uri string, checksum files.Checksum, target string) error
+}
+
+func NewFactory(client GovcClient, ovaDownloader OVADownloader, ovaDir, datacenter, datastore, resourcePool, templateLibrary string) *Factory {
 	return &Factory{
 		client:          client,
+		ovaDownloader:   ovaDownloader,
+		ovaDir:          ovaDir,
 		datacenter:      datacenter,
 		datastore:       datastore,
 		resourcePool:    resourcePool,
@@ -51,7 +65,7 @@ func NewFactory(client GovcClient, datacenter, datastore, resourcePool, template
 	}
 }
 
-func (f *Factory) CreateIfMissing(ctx context.Context, datacenter string, machineConfig *v1alpha1.VSphereMachineConfig, ovaURL string, tagsByCategory map[string][]string) error {
+func (f *Factory) CreateIfMissing(ctx context.Context, datacenter string, machineConfig *v1alpha1.VSphereMachineConfig, ova releasev1alpha1.Archive, tagsByCategory map[string][]string) error {
 	templateFullPath, err := f.client.SearchTemplate(ctx, datacenter, machineConfig)
 	if err != nil {
 		return fmt.Errorf("error checking for template: %v", err)
@@ -65,7 +79,7 @@ func (f *Factory) CreateIfMissing(ctx context.Context, datacenter string, machin
 	logger.V(2).Info("Template not available. Creating", "template", machineConfig.Spec.Template)
 
 	osFamily := machineConfig.Spec.OSFamily
-	if err = f.createTemplate(ctx, machineConfig.Spec.Template, ovaURL, string(osFamily)); err != nil {
+	if err = f.createTemplate(ctx, machineConfig.Spec.Template, ova, string(osFamily)); err != nil {
 		return err
 	}
 
@@ -75,7 +89,7 @@ func (f *Factory) CreateIfMissing(ctx context.Context, datacenter string, machin
 	return nil
 }
 
-func (f *Factory) createTemplate(ctx context.Context, templatePath, ovaURL, osFamily string) error {
+func (f *Factory) createTemplate(ctx context.Context, templatePath string, ova releasev1alpha1.Archive, osFamily string) error {
 	if err := f.createLibraryIfMissing(ctx); err != nil {
 		return err
 	}
@@ -84,7 +98,7 @@ func (f *Factory) createTemplate(ctx context.Context, templatePath, ovaURL, osFa
 	templateName := filepath.Base(templatePath)
 	templateDir := filepath.Dir(templatePath)
 
-	if err := f.importOVAIfMissing(ctx, templateName, ovaURL); err != nil {
+	if err := f.importOVAIfMissing(ctx, templateName, ova); err != nil {
 		return err
 	}
 
@@ -115,7 +129,7 @@ func (f *Factory) createLibraryIfMissing(ctx context.Context) error {
 	return nil
 }
 
-func (f *Factory) importOVAIfMissing(ctx context.Context, templateName, ovaURL string) error {
+func (f *Factory) importOVAIfMissing(ctx context.Context, templateName string, ova releasev1alpha1.Archive) error {
 	contentVersion, err := f.client.GetLibraryElementContentVersion(ctx, filepath.Join(f.templateLibrary, templateName))
 	if err != nil {
 		return fmt.Errorf("failed to validate template in library for new template: %v", err)
@@ -130,11 +144,39 @@ func (f *Factory) importOVAIfMissing(ctx context.Context, templateName, ovaURL s
 	}
 
 	if contentVersion == libraryContentDoesNotExist {
-		logger.V(2).Info("Importing template from ova url", "ova", ovaURL)
-		if err = f.client.ImportTemplate(ctx, f.templateLibrary, ovaURL, templateName); err != nil {
+		ovaFile, err := f.downloadOVA(ctx, ova)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(ovaFile)
+
+		logger.V(2).Info("Importing template from ova", "ova", ova.URI)
+		if err = f.client.ImportTemplate(ctx, f.templateLibrary, ovaFile, templateName); err != nil {
 			return fmt.Errorf("failed importing template into library: %v", err)
 		}
 	}
 
 	return nil
 }
+
+// downloadOVA downloads the OVA to the local OVA dir, verifying it against the checksums published in the bundle.
+// The verified file is imported instead of letting vCenter pull the URL, so only verified content makes it to the library.
+func (f *Factory) downloadOVA(ctx context.Context, ova releasev1alpha1.Archive) (string, error) {
+	checksum := files.Checksum{SHA256: ova.SHA256, SHA512: ova.SHA512}
+	if checksum.IsEmpty() {
+		return "", fmt.Errorf("refusing to import ova %s: the bundle doesn't have a checksum for it", ova.URI)
+	}
+
+	u, err := url.Parse(ova.URI)
+	if err != nil {
+		return "", fmt.Errorf("invalid ova uri %s: %v", ova.URI, err)
+	}
+	ovaFile := filepath.Join(f.ovaDir, path.Base(u.Path))
+
+	logger.V(2).Info("Downloading and verifying ova", "ova", ova.URI)
+	if err = f.ovaDownloader.DownloadFile(ctx, ova.URI, checksum, ovaFile); err != nil {
+		return "", fmt.Errorf("failed verifying ova for new template: %v", err)
+	}
+
+	return ovaFile, nil
+}
@@ -9,8 +9,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/files"
 	"github.com/aws/eks-anywhere/pkg/providers/vsphere/internal/templates"
 	"github.com/aws/eks-anywhere/pkg/providers/vsphere/internal/templates/mocks"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
 type test struct {
@@ -21,6 +23,8 @@ type test struct {
 	templateLibrary            string
 	resizeDisk2                bool
 	govc                       *mocks.MockGovcClient
+	ovaDownloader              *mocks.MockOVADownloader
+	ovaDir                     string
 	factory                    *templates.Factory
 	ctx                        context.Context
 	dummyError                 error
@@ -37,7 +41,9 @@ type createTest struct {
 	templateName      string
 	templateDir       string
 	templateInLibrary string
-	ovaURL            string
+	ova               releasev1alpha1.Archive
+	ovaChecksum       files.Checksum
+	ovaFile           string
 	tagsByCategory    map[string][]string
 }
 
@@ -51,6 +57,8 @@ func newTest(t *testing.T) *test {
 		templateLibrary:            "library",
 		resizeDisk2:                false,
 		govc:                       mocks.NewMockGovcClient(ctrl),
+		ovaDownloader:              mocks.NewMockOVADownloader(ctrl),
+		ovaDir:                     "ovas",
 		ctx:                        context.Background(),
 		dummyError:                 errors.New("error from govc"),
 		libraryContentCorrupted:    "1",
@@ -59,6 +67,8 @@ func newTest(t *testing.T) *test {
 	}
 	f := templates.NewFactory(
 		test.govc,
+		test.ovaDownloader,
+		test.ovaDir,
 		test.datacenter,
 		test.datastore,
 		test.resourcePool,
@@ -93,13 +103,22 @@ func newCreateTest(t *testing.T) *createTest {
 		templateDir:       "/SDDC-Datacenter/vm/Templates",
 		templateName:      "ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64",
 		templateInLibrary: "library/ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64",
-		ovaURL:            "https://amazonaws.com/artifacts/0.0.1/eks-distro/ova/1-19/1-19-4/ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64.ova",
-		tagsByCategory:    map[string][]string{},
+		ova: releasev1alpha1.Archive{
+			URI:    "https://amazonaws.com/artifacts/0.0.1/eks-distro/ova/1-19/1-19-4/ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64.ova",
+			SHA256: "ova-sha256",
+			SHA512: "ova-sha512",
+		},
+		ovaChecksum: files.Checksum{
+			SHA256: "ova-sha256",
+			SHA512: "ova-sha512",
+		},
+		ovaFile:        "ovas/ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64.ova",
+		tagsByCategory: map[string][]string{},
 	}
 }
 
 func (ct *createTest) createIfMissing() error {
-	return ct.factory.CreateIfMissing(ct.ctx, ct.datacenter, ct.machineConfig, ct.ovaURL, ct.tagsByCategory)
+	return ct.factory.CreateIfMissing(ct.ctx, ct.datacenter, ct.machineConfig, ct.ova, ct.tagsByCategory)
 }
 
 func (ct *createTest) assertErrorFromCreateIfMissing() {
@@ -160,7 +179,29 @@ func TestFactoryCreateIfMissingErrorImport(t *testing.T) {
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(false, nil)
 	ct.govc.EXPECT().CreateLibrary(ct.ctx, ct.datastore, ct.templateLibrary).Return(nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName).Return(ct.dummyError)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName).Return(ct.dummyError)
+
+	ct.assertErrorFromCreateIfMissing()
+}
+
+func TestFactoryCreateIfMissingErrorOVAChecksum(t *testing.T) {
+	ct := newCreateTest(t)
+	ct.govc.EXPECT().SearchTemplate(ct.ctx, ct.datacenter, ct.machineConfig).Return("", nil) // template not present
+	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(true, nil)
+	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(ct.dummyError)
+
+	ct.assertErrorFromCreateIfMissing()
+}
+
+func TestFactoryCreateIfMissingErrorOVAWithoutChecksum(t *testing.T) {
+	ct := newCreateTest(t)
+	ct.ova.SHA256 = ""
+	ct.ova.SHA512 = ""
+	ct.govc.EXPECT().SearchTemplate(ct.ctx, ct.datacenter, ct.machineConfig).Return("", nil) // template not present
+	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(true, nil)
+	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
 
 	ct.assertErrorFromCreateIfMissing()
 }
@@ -171,7 +212,8 @@ func TestFactoryCreateIfMissingErrorDeploy(t *testing.T) {
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(false, nil)
 	ct.govc.EXPECT().CreateLibrary(ct.ctx, ct.datastore, ct.templateLibrary).Return(nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName).Return(nil)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName).Return(nil)
 	ct.govc.EXPECT().DeployTemplateFromLibrary(
 		ct.ctx, ct.templateDir, ct.templateName, ct.templateLibrary, ct.datacenter, ct.resourcePool, ct.resizeDisk2,
 	).Return(ct.dummyError)
@@ -185,7 +227,8 @@ func TestFactoryCreateIfMissingErrorFromTagFactory(t *testing.T) {
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(false, nil)
 	ct.govc.EXPECT().CreateLibrary(ct.ctx, ct.datastore, ct.templateLibrary).Return(nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName).Return(nil)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName).Return(nil)
 	ct.govc.EXPECT().DeployTemplateFromLibrary(
 		ct.ctx, ct.templateDir, ct.templateName, ct.templateLibrary, ct.datacenter, ct.resourcePool, ct.resizeDisk2,
 	).Return(nil)
@@ -202,7 +245,8 @@ func TestFactoryCreateIfMissingSuccessLibraryDoesNotExist(t *testing.T) {
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(false, nil)
 	ct.govc.EXPECT().CreateLibrary(ct.ctx, ct.datastore, ct.templateLibrary).Return(nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName).Return(nil)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName).Return(nil)
 	ct.govc.EXPECT().DeployTemplateFromLibrary(
 		ct.ctx, ct.templateDir, ct.templateName, ct.templateLibrary, ct.datacenter, ct.resourcePool, ct.resizeDisk2,
 	).Return(nil)
@@ -219,7 +263,8 @@ func TestFactoryCreateIfMissingSuccessLibraryExists(t *testing.T) {
 	ct.govc.EXPECT().SearchTemplate(ct.ctx, ct.datacenter, ct.machineConfig).Return("", nil) // template not present
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(true, nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentDoesNotExist, nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName).Return(nil)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName).Return(nil)
 	ct.govc.EXPECT().DeployTemplateFromLibrary(
 		ct.ctx, ct.templateDir, ct.templateName, ct.templateLibrary, ct.datacenter, ct.resourcePool, ct.resizeDisk2,
 	).Return(nil)
@@ -253,7 +298,8 @@ func TestFactoryCreateIfMissingSuccessTemplateInLibraryCorrupted(t *testing.T) {
 	ct.govc.EXPECT().LibraryElementExists(ct.ctx, ct.templateLibrary).Return(true, nil)
 	ct.govc.EXPECT().GetLibraryElementContentVersion(ct.ctx, ct.templateInLibrary).Return(ct.libraryContentCorrupted, nil)
 	ct.govc.EXPECT().DeleteLibraryElement(ct.ctx, ct.templateInLibrary).Return(nil)
-	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaURL, ct.templateName)
+	ct.ovaDownloader.EXPECT().DownloadFile(ct.ctx, ct.ova.URI, ct.ovaChecksum, ct.ovaFile).Return(nil)
+	ct.govc.EXPECT().ImportTemplate(ct.ctx, ct.templateLibrary, ct.ovaFile, ct.templateName)
 	ct.govc.EXPECT().DeployTemplateFromLibrary(
 		ct.ctx, ct.templateDir, ct.templateName, ct.templateLibrary, ct.datacenter, ct.resourcePool, ct.resizeDisk2,
 	).Return(nil)
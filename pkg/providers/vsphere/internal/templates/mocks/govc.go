@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	v1alpha1 "github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	files "github.com/aws/eks-anywhere/pkg/files"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -207,3 +208,40 @@ func (mr *MockGovcClientMockRecorder) SearchTemplate(ctx, datacenter, machineCon
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTemplate", reflect.TypeOf((*MockGovcClient)(nil).SearchTemplate), ctx, datacenter, machineConfig)
 }
+
+// MockOVADownloader is a mock of OVADownloader interface.
+type MockOVADownloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockOVADownloaderMockRecorder
+}
+
+// MockOVADownloaderMockRecorder is the mock recorder for MockOVADownloader.
+type MockOVADownloaderMockRecorder struct {
+	mock *MockOVADownloader
+}
+
+// NewMockOVADownloader creates a new mock instance.
+func NewMockOVADownloader(ctrl *gomock.Controller) *MockOVADownloader {
+	mock := &MockOVADownloader{ctrl: ctrl}
+	mock.recorder = &MockOVADownloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOVADownloader) EXPECT() *MockOVADownloaderMockRecorder {
+	return m.recorder
+}
+
+// DownloadFile mocks base method.
+func (m *MockOVADownloader) DownloadFile(ctx context.Context, uri string, checksum files.Checksum, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", ctx, uri, checksum, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockOVADownloaderMockRecorder) DownloadFile(ctx, uri, checksum, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockOVADownloader)(nil).DownloadFile), ctx, uri, checksum, target)
+}
@@ -6,7 +6,6 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -28,15 +27,19 @@ import (
 	"github.com/aws/eks-anywhere/pkg/constants"
 	"github.com/aws/eks-anywhere/pkg/crypto"
 	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/files"
 	"github.com/aws/eks-anywhere/pkg/filewriter"
 	"github.com/aws/eks-anywhere/pkg/logger"
 	"github.com/aws/eks-anywhere/pkg/networkutils"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	"github.com/aws/eks-anywhere/pkg/providers/common"
 	"github.com/aws/eks-anywhere/pkg/providers/vsphere/internal/templates"
+	"github.com/aws/eks-anywhere/pkg/proxy"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/retrier"
 	"github.com/aws/eks-anywhere/pkg/templater"
 	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
 	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
@@ -56,6 +59,8 @@ const (
 	publicKeyFileName                     = "eks-a-id_rsa.pub"
 	defaultTemplateLibrary                = "eks-a-templates"
 	defaultTemplatesFolder                = "vm/Templates"
+	templateSetupTimeout                  = 15 * time.Minute
+	ovasDir                               = "ovas"
 	bottlerocketDefaultUser               = "ec2-user"
 	ubuntuDefaultUser                     = "capv"
 	cloudControllerDaemonSetName          = "vsphere-cloud-controller-manager"
@@ -65,6 +70,16 @@ const (
 	backOffPeriod                         = 5 * time.Second
 )
 
+// Names of the setup and validation checks, which can be skipped with the rest of the validations
+const (
+	credentialsCheckName    = "vsphere credentials"
+	datacenterCheckName     = "vsphere datacenter"
+	templateCheckName       = "vsphere template"
+	datastoreCheckName      = "vsphere datastore"
+	machineConfigsCheckName = "vsphere machine configs"
+	controlPlaneIpCheckName = "vsphere control plane ip"
+)
+
 //go:embed config/template-cp.yaml
 var defaultCAPIConfigCP string
 
@@ -83,11 +98,6 @@ var mhcTemplate []byte
 var (
 	eksaVSphereDatacenterResourceType = fmt.Sprintf("vspheredatacenterconfigs.%s", v1alpha1.GroupVersion.Group)
 	eksaVSphereMachineResourceType    = fmt.Sprintf("vspheremachineconfigs.%s", v1alpha1.GroupVersion.Group)
-	noProxyDefaults                   = []string{
-		"localhost",
-		"127.0.0.1",
-		".svc",
-	}
 )
 
 var requiredEnvs = []string{vSphereUsernameKey, vSpherePasswordKey, expClusterResourceSetKey}
@@ -109,6 +119,8 @@ type vsphereProvider struct {
 	etcdTemplateFactory         *templates.Factory
 	templateBuilder             *VsphereTemplateBuilder
 	skipIpCheck                 bool
+	defaultTemplates            map[string]defaultTemplate
+	readOnly                    bool
 	resourceSetManager          ClusterResourceSetManager
 	Retrier                     *retrier.Retrier
 }
@@ -175,10 +187,14 @@ func NewProvider(datacenterConfig *v1alpha1.VSphereDatacenterConfig, machineConf
 func NewProviderCustomNet(datacenterConfig *v1alpha1.VSphereDatacenterConfig, machineConfigs map[string]*v1alpha1.VSphereMachineConfig, clusterConfig *v1alpha1.Cluster, providerGovcClient ProviderGovcClient, providerKubectlClient ProviderKubectlClient, writer filewriter.FileWriter, netClient networkutils.NetClient, now types.NowFunc, skipIpCheck bool, resourceSetManager ClusterResourceSetManager) *vsphereProvider {
 	var controlPlaneMachineSpec, workerNodeGroupMachineSpec, etcdMachineSpec *v1alpha1.VSphereMachineConfigSpec
 	var controlPlaneTemplateFactory, workerNodeGroupTemplateFactory, etcdTemplateFactory *templates.Factory
+	ovaReader := files.NewReader()
+	ovaDir := filepath.Join(writer.Dir(), ovasDir)
 	if clusterConfig.Spec.ControlPlaneConfiguration.MachineGroupRef != nil && machineConfigs[clusterConfig.Spec.ControlPlaneConfiguration.MachineGroupRef.Name] != nil {
 		controlPlaneMachineSpec = &machineConfigs[clusterConfig.Spec.ControlPlaneConfiguration.MachineGroupRef.Name].Spec
 		controlPlaneTemplateFactory = templates.NewFactory(
 			providerGovcClient,
+			ovaReader,
+			ovaDir,
 			datacenterConfig.Spec.Datacenter,
 			controlPlaneMachineSpec.Datastore,
 			controlPlaneMachineSpec.ResourcePool,
@@ -189,6 +205,8 @@ func NewProviderCustomNet(datacenterConfig *v1alpha1.VSphereDatacenterConfig, ma
 		workerNodeGroupMachineSpec = &machineConfigs[clusterConfig.Spec.WorkerNodeGroupConfigurations[0].MachineGroupRef.Name].Spec
 		workerNodeGroupTemplateFactory = templates.NewFactory(
 			providerGovcClient,
+			ovaReader,
+			ovaDir,
 			datacenterConfig.Spec.Datacenter,
 			workerNodeGroupMachineSpec.Datastore,
 			workerNodeGroupMachineSpec.ResourcePool,
@@ -200,6 +218,8 @@ func NewProviderCustomNet(datacenterConfig *v1alpha1.VSphereDatacenterConfig, ma
 			etcdMachineSpec = &machineConfigs[clusterConfig.Spec.ExternalEtcdConfiguration.MachineGroupRef.Name].Spec
 			etcdTemplateFactory = templates.NewFactory(
 				providerGovcClient,
+				ovaReader,
+				ovaDir,
 				datacenterConfig.Spec.Datacenter,
 				etcdMachineSpec.Datastore,
 				etcdMachineSpec.ResourcePool,
@@ -233,24 +253,19 @@ func NewProviderCustomNet(datacenterConfig *v1alpha1.VSphereDatacenterConfig, ma
 	}
 }
 
+// SetReadOnly makes the setup and validations only read from vCenter. The default templates that don't exist
+// are reported as not set instead of being imported, and no library or tags are created
+func (p *vsphereProvider) SetReadOnly(readOnly bool) {
+	p.readOnly = readOnly
+}
+
 func (p *vsphereProvider) UpdateKubeConfig(_ *[]byte, _ string) error {
 	// customize generated kube config
 	return nil
 }
 
 func (p *vsphereProvider) BootstrapClusterOpts() ([]bootstrapper.BootstrapClusterOption, error) {
-	env := map[string]string{}
-	if p.clusterConfig.Spec.ProxyConfiguration != nil {
-		noProxy := fmt.Sprintf("%s,%s", p.clusterConfig.Spec.ControlPlaneConfiguration.Endpoint.Host, p.datacenterConfig.Spec.Server)
-		for _, s := range p.clusterConfig.Spec.ProxyConfiguration.NoProxy {
-			if s != "" {
-				noProxy += "," + s
-			}
-		}
-		env["HTTP_PROXY"] = p.clusterConfig.Spec.ProxyConfiguration.HttpProxy
-		env["HTTPS_PROXY"] = p.clusterConfig.Spec.ProxyConfiguration.HttpsProxy
-		env["NO_PROXY"] = noProxy
-	}
+	env := proxy.New(p.clusterConfig, p.datacenterConfig.Spec.Server).EnvMap()
 	return []bootstrapper.BootstrapClusterOption{bootstrapper.WithEnv(env)}, nil
 }
 
@@ -377,12 +392,23 @@ func (p *vsphereProvider) generateSSHAuthKey(username string) (string, error) {
 	return key, nil
 }
 
-func (p *vsphereProvider) validateControlPlaneIp(ip string) error {
+func (p *vsphereProvider) validateControlPlaneIp(ip string, clusterNetwork v1alpha1.ClusterNetwork) error {
 	// check if controlPlaneEndpointIp is valid
-	parsedIp := net.ParseIP(ip)
-	if parsedIp == nil {
+	ipFamily, err := networkutils.ParseIPFamily(ip)
+	if err != nil {
 		return fmt.Errorf("cluster controlPlaneConfiguration.Endpoint.Host is invalid: %s", ip)
 	}
+	// the endpoint is the API server advertise address, it has to belong to the primary IP family of the cluster
+	if len(clusterNetwork.Pods.CidrBlocks) == 0 {
+		return nil
+	}
+	primaryFamily, err := networkutils.ParseCIDRFamily(clusterNetwork.Pods.CidrBlocks[0])
+	if err != nil {
+		return nil
+	}
+	if ipFamily != primaryFamily {
+		return fmt.Errorf("cluster controlPlaneConfiguration.Endpoint.Host %s is an %s address but the primary IP family of the cluster is %s", ip, ipFamily, primaryFamily)
+	}
 	return nil
 }
 
@@ -445,7 +471,9 @@ func (p *vsphereProvider) validateSSHUsername(machineConfig *v1alpha1.VSphereMac
 	return nil
 }
 
-func (p *vsphereProvider) setupAndValidateCluster(ctx context.Context, clusterSpec *cluster.Spec) error {
+// completeClusterSpec validates the provider configuration and sets the defaults of the machine configs,
+// without reaching vCenter
+func (p *vsphereProvider) completeClusterSpec(clusterSpec *cluster.Spec) error {
 	var etcdMachineConfig *v1alpha1.VSphereMachineConfig
 	if p.datacenterConfig.Spec.Insecure {
 		logger.Info("Warning: VSphereDatacenterConfig configured in insecure mode")
@@ -572,22 +600,10 @@ func (p *vsphereProvider) setupAndValidateCluster(ctx context.Context, clusterSp
 		workerNodeGroupMachineConfig.Spec.Users[0].SshAuthorizedKeys = []string{""}
 	}
 
-	err := p.validateControlPlaneIp(clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host)
-	if err != nil {
+	if err := p.validateControlPlaneIp(clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host, clusterSpec.Spec.ClusterNetwork); err != nil {
 		return err
 	}
 
-	err = p.providerGovcClient.ValidateVCenterSetup(ctx, p.datacenterConfig, &p.selfSigned)
-	if err != nil {
-		return fmt.Errorf("error validating vCenter setup: %v", err)
-	}
-	for _, config := range p.machineConfigs {
-		err = p.providerGovcClient.ValidateVCenterSetupMachineConfig(ctx, p.datacenterConfig, config, &p.selfSigned)
-		if err != nil {
-			return fmt.Errorf("error validating vCenter setup for VSphereMachineConfig %v: %v", config.Name, err)
-		}
-	}
-
 	if controlPlaneMachineConfig.Spec.OSFamily != workerNodeGroupMachineConfig.Spec.OSFamily {
 		return errors.New("control plane and worker nodes must have the same osFamily specified")
 	}
@@ -626,27 +642,66 @@ func (p *vsphereProvider) setupAndValidateCluster(ctx context.Context, clusterSp
 		return errors.New("VSphereDatacenterConfig and Cluster objects must have the same namespace specified")
 	}
 
+	p.defaultTemplates = map[string]defaultTemplate{}
 	if controlPlaneMachineConfig.Spec.Template == "" {
 		logger.V(1).Info("Control plane VSphereMachineConfig template is not set. Using default template.")
-		err := p.setupDefaultTemplate(ctx, clusterSpec, controlPlaneMachineConfig, p.controlPlaneTemplateFactory)
-		if err != nil {
-			return err
+		p.setDefaultTemplate(clusterSpec, controlPlaneMachineConfig, p.controlPlaneTemplateFactory)
+	}
+	if workerNodeGroupMachineConfig.Spec.Template == "" {
+		logger.V(1).Info("Worker VSphereMachineConfig template is not set. Using default template.")
+		p.setDefaultTemplate(clusterSpec, workerNodeGroupMachineConfig, p.workerTemplateFactory)
+	}
+	if etcdMachineConfig != nil && etcdMachineConfig.Spec.Template == "" {
+		logger.V(1).Info("Etcd VSphereMachineConfig template is not set. Using default template.")
+		p.setDefaultTemplate(clusterSpec, etcdMachineConfig, p.etcdTemplateFactory)
+	}
+
+	return nil
+}
+
+// clusterMachineConfigs returns the machine configs of the control plane, the workers and the etcd machines,
+// which is nil without external etcd. The machine configs must have been validated by completeClusterSpec
+func (p *vsphereProvider) clusterMachineConfigs(clusterSpec *cluster.Spec) (controlPlane, worker, etcd *v1alpha1.VSphereMachineConfig) {
+	controlPlane = p.machineConfigs[clusterSpec.Spec.ControlPlaneConfiguration.MachineGroupRef.Name]
+	worker = p.machineConfigs[clusterSpec.Spec.WorkerNodeGroupConfigurations[0].MachineGroupRef.Name]
+	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
+		etcd = p.machineConfigs[clusterSpec.Spec.ExternalEtcdConfiguration.MachineGroupRef.Name]
+	}
+	return controlPlane, worker, etcd
+}
+
+func (p *vsphereProvider) validateCredentials(ctx context.Context, clusterSpec *cluster.Spec) error {
+	// the CLI tools reach vCenter with the cluster proxy configuration, unless it's listed in noProxy
+	vCenter := proxy.Target{Name: "vCenter", URL: fmt.Sprintf("https://%s", p.datacenterConfig.Spec.Server)}
+	if err := proxy.NewConnectivityValidator(proxy.New(clusterSpec.Cluster)).Validate(ctx, vCenter); err != nil {
+		return err
+	}
+
+	if err := p.providerGovcClient.ValidateVCenterSetup(ctx, p.datacenterConfig, &p.selfSigned); err != nil {
+		return fmt.Errorf("error validating vCenter setup: %v", err)
+	}
+	return nil
+}
+
+func (p *vsphereProvider) validateDatacenter(ctx context.Context) error {
+	for _, config := range p.machineConfigs {
+		if err := p.providerGovcClient.ValidateVCenterSetupMachineConfig(ctx, p.datacenterConfig, config, &p.selfSigned); err != nil {
+			return fmt.Errorf("error validating vCenter setup for VSphereMachineConfig %v: %v", config.Name, err)
 		}
 	}
+	return nil
+}
 
-	if err = p.validateTemplate(ctx, clusterSpec, controlPlaneMachineConfig); err != nil {
+// setupAndValidateTemplates creates the default templates that don't exist and validates the templates of all the machines
+func (p *vsphereProvider) setupAndValidateTemplates(ctx context.Context, clusterSpec *cluster.Spec) error {
+	controlPlaneMachineConfig, workerNodeGroupMachineConfig, etcdMachineConfig := p.clusterMachineConfigs(clusterSpec)
+
+	if err := p.setupAndValidateTemplate(ctx, clusterSpec, controlPlaneMachineConfig); err != nil {
 		logger.V(1).Info("Control plane template validation failed.")
 		return err
 	}
 	if controlPlaneMachineConfig.Spec.Template != workerNodeGroupMachineConfig.Spec.Template {
-		if workerNodeGroupMachineConfig.Spec.Template == "" {
-			logger.V(1).Info("Worker VSphereMachineConfig template is not set. Using default template.")
-			err := p.setupDefaultTemplate(ctx, clusterSpec, workerNodeGroupMachineConfig, p.workerTemplateFactory)
-			if err != nil {
-				return err
-			}
-		}
-		if err = p.validateTemplate(ctx, clusterSpec, workerNodeGroupMachineConfig); err != nil {
+		if err := p.setupAndValidateTemplate(ctx, clusterSpec, workerNodeGroupMachineConfig); err != nil {
 			logger.V(1).Info("Workload template validation failed.")
 			return err
 		}
@@ -657,14 +712,7 @@ func (p *vsphereProvider) setupAndValidateCluster(ctx context.Context, clusterSp
 	logger.MarkPass("Control plane and Workload templates validated")
 
 	if etcdMachineConfig != nil {
-		if etcdMachineConfig.Spec.Template == "" {
-			logger.V(1).Info("Etcd VSphereMachineConfig template is not set. Using default template.")
-			err := p.setupDefaultTemplate(ctx, clusterSpec, etcdMachineConfig, p.etcdTemplateFactory)
-			if err != nil {
-				return err
-			}
-		}
-		if err = p.validateTemplate(ctx, clusterSpec, etcdMachineConfig); err != nil {
+		if err := p.setupAndValidateTemplate(ctx, clusterSpec, etcdMachineConfig); err != nil {
 			logger.V(1).Info("Etcd template validation failed.")
 			return err
 		}
@@ -702,6 +750,11 @@ func (p *vsphereProvider) setupAndValidateCluster(ctx context.Context, clusterSp
 		}
 	}
 
+	return nil
+}
+
+func (p *vsphereProvider) validateDatastores(ctx context.Context, clusterSpec *cluster.Spec) error {
+	controlPlaneMachineConfig, workerNodeGroupMachineConfig, etcdMachineConfig := p.clusterMachineConfigs(clusterSpec)
 	return p.checkDatastoreUsage(ctx, clusterSpec, controlPlaneMachineConfig, workerNodeGroupMachineConfig, etcdMachineConfig)
 }
 
@@ -837,19 +890,41 @@ func requiredTemplateTagsByCategory(clusterSpec *cluster.Spec, machineConfig *v1
 	}
 }
 
-func (p *vsphereProvider) setupDefaultTemplate(ctx context.Context, clusterSpec *cluster.Spec, machineConfig *v1alpha1.VSphereMachineConfig, templateFactory *templates.Factory) error {
-	ovaURL := p.defaultTemplateForClusterSpec(clusterSpec, machineConfig)
-
-	tags := requiredTemplateTagsByCategory(clusterSpec, machineConfig)
+// defaultTemplate is the template of the OVA in the bundle for a machine config without a template,
+// created by the template check if it doesn't exist
+type defaultTemplate struct {
+	factory *templates.Factory
+	ova     releasev1alpha1.Archive
+	tags    map[string][]string
+}
 
-	if err := templateFactory.CreateIfMissing(ctx, p.datacenterConfig.Spec.Datacenter, machineConfig, ovaURL, tags); err != nil {
-		return err
+func (p *vsphereProvider) setDefaultTemplate(clusterSpec *cluster.Spec, machineConfig *v1alpha1.VSphereMachineConfig, templateFactory *templates.Factory) {
+	p.defaultTemplates[machineConfig.Name] = defaultTemplate{
+		factory: templateFactory,
+		ova:     p.defaultTemplateForClusterSpec(clusterSpec, machineConfig),
+		tags:    requiredTemplateTagsByCategory(clusterSpec, machineConfig),
 	}
+}
 
-	return nil
+func (p *vsphereProvider) setupAndValidateTemplate(ctx context.Context, clusterSpec *cluster.Spec, machineConfig *v1alpha1.VSphereMachineConfig) error {
+	if t, ok := p.defaultTemplates[machineConfig.Name]; ok && p.readOnly {
+		templateFullPath, err := p.providerGovcClient.SearchTemplate(ctx, p.datacenterConfig.Spec.Datacenter, machineConfig)
+		if err != nil {
+			return fmt.Errorf("error checking for template: %v", err)
+		}
+		if len(templateFullPath) == 0 {
+			return fmt.Errorf("template not set for VSphereMachineConfig %s and the default template %s doesn't exist yet, create cluster imports it", machineConfig.Name, machineConfig.Spec.Template)
+		}
+		machineConfig.Spec.Template = templateFullPath
+	} else if ok {
+		if err := t.factory.CreateIfMissing(ctx, p.datacenterConfig.Spec.Datacenter, machineConfig, t.ova, t.tags); err != nil {
+			return err
+		}
+	}
+	return p.validateTemplate(ctx, clusterSpec, machineConfig)
 }
 
-func (p *vsphereProvider) defaultTemplateForClusterSpec(clusterSpec *cluster.Spec, machineConfig *v1alpha1.VSphereMachineConfig) string {
+func (p *vsphereProvider) defaultTemplateForClusterSpec(clusterSpec *cluster.Spec, machineConfig *v1alpha1.VSphereMachineConfig) releasev1alpha1.Archive {
 	osFamily := machineConfig.Spec.OSFamily
 	eksd := clusterSpec.VersionsBundle.EksD
 
@@ -864,7 +939,7 @@ func (p *vsphereProvider) defaultTemplateForClusterSpec(clusterSpec *cluster.Spe
 
 	templateName := fmt.Sprintf("%s-%s-%s-%s-%s", osFamily, eksd.KubeVersion, eksd.Name, strings.Join(ova.Arch, "-"), ova.SHA256[:7])
 	machineConfig.Spec.Template = filepath.Join("/", p.datacenterConfig.Spec.Datacenter, defaultTemplatesFolder, templateName)
-	return ova.URI
+	return ova.Archive
 }
 
 func (p *vsphereProvider) DeleteResources(ctx context.Context, clusterSpec *cluster.Spec) error {
@@ -877,65 +952,155 @@ func (p *vsphereProvider) DeleteResources(ctx context.Context, clusterSpec *clus
 }
 
 func (p *vsphereProvider) SetupAndValidateCreateCluster(ctx context.Context, clusterSpec *cluster.Spec) error {
-	err := p.validateEnv(ctx)
-	if err != nil {
-		return fmt.Errorf("failed setup and validations: %v", err)
+	return runChecks(p.SetupAndValidateCreateClusterChecks(ctx, clusterSpec))
+}
+
+func (p *vsphereProvider) SetupAndValidateUpgradeCluster(ctx context.Context, cluster *types.Cluster, clusterSpec *cluster.Spec) error {
+	return runChecks(p.SetupAndValidateUpgradeClusterChecks(ctx, cluster, clusterSpec))
+}
+
+// SetupAndValidateCreateClusterChecks splits the setup and validation of a new cluster in checks. Only the provider
+// setup completes the spec, vCenter is validated in the chain credentials, datacenter, template and datastore, which
+// runs at the same time as the checks of the control plane IP and the management cluster
+func (p *vsphereProvider) SetupAndValidateCreateClusterChecks(ctx context.Context, clusterSpec *cluster.Spec) []validations.Check {
+	checks := []validations.Check{
+		validations.ProviderSetupCheck(func() *validations.ValidationResult {
+			return &validations.ValidationResult{
+				Name: fmt.Sprintf("%s Provider setup is valid", p.Name()),
+				Err:  p.setupCreateCluster(ctx, clusterSpec),
+			}
+		}),
 	}
-	err = p.setupAndValidateCluster(ctx, clusterSpec)
-	if err != nil {
-		return err
+	checks = append(checks, p.vCenterChecks(ctx, clusterSpec)...)
+
+	if clusterSpec.IsManaged() {
+		checks = append(checks, providerCheck(machineConfigsCheckName, "vsphere machine configs are new", func() error {
+			return p.validateMachineConfigsDontExist(ctx, clusterSpec)
+		}, validations.ProviderSetupCheckName))
 	}
-	err = p.setupSSHAuthKeysForCreate()
-	if err != nil {
-		return fmt.Errorf("failed setup and validations: %v", err)
+
+	if p.skipIpCheck {
+		logger.Info("Skipping check for whether control plane ip is in use")
+		return checks
 	}
+	return append(checks, providerCheck(controlPlaneIpCheckName, "vsphere control plane ip is unique", func() error {
+		return p.validateControlPlaneIpUniqueness(clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host)
+	}, validations.ProviderSetupCheckName))
+}
 
-	if clusterSpec.IsManaged() {
-		for _, mc := range p.MachineConfigs() {
-			em, err := p.providerKubectlClient.SearchVsphereMachineConfig(ctx, mc.GetName(), clusterSpec.ManagementCluster.KubeconfigFile, mc.GetNamespace())
-			if err != nil {
-				return err
-			}
-			if len(em) > 0 {
-				return fmt.Errorf("VSphereMachineConfig %s already exists", mc.GetName())
+// SetupAndValidateUpgradeClusterChecks splits the setup and validation of an upgrade in checks like SetupAndValidateCreateClusterChecks
+func (p *vsphereProvider) SetupAndValidateUpgradeClusterChecks(ctx context.Context, cluster *types.Cluster, clusterSpec *cluster.Spec) []validations.Check {
+	checks := []validations.Check{
+		validations.ProviderSetupCheck(func() *validations.ValidationResult {
+			return &validations.ValidationResult{
+				Name: fmt.Sprintf("%s Provider setup is valid", p.Name()),
+				Err:  p.setupUpgradeCluster(ctx, clusterSpec),
 			}
+		}),
+	}
+	checks = append(checks, p.vCenterChecks(ctx, clusterSpec)...)
+	return append(checks, providerCheck(machineConfigsCheckName, "vsphere machine config names are unique", func() error {
+		if err := p.validateMachineConfigsNameUniqueness(ctx, cluster, clusterSpec); err != nil {
+			return fmt.Errorf("failed validate machineconfig uniqueness: %v", err)
 		}
-		existingDatacenter, err := p.providerKubectlClient.SearchVsphereDatacenterConfig(ctx, p.datacenterConfig.Name, clusterSpec.ManagementCluster.KubeconfigFile, clusterSpec.Namespace)
-		if err != nil {
-			return err
-		}
-		if len(existingDatacenter) > 0 {
-			return fmt.Errorf("VSphereDatacenter %s already exists", p.datacenterConfig.Name)
+		return nil
+	}, validations.ProviderSetupCheckName))
+}
+
+// vCenterChecks returns the vCenter checks. Besides validating, the credentials, datacenter and template checks complete
+// the provider setup: they detect self signed certificates, import the default templates and set the disk size of the
+// machines from the templates. They are critical so they can't be skipped and leave the provider half configured
+func (p *vsphereProvider) vCenterChecks(ctx context.Context, clusterSpec *cluster.Spec) []validations.Check {
+	credentialsCheck := providerCheck(credentialsCheckName, "vsphere credentials are valid", func() error {
+		return p.validateCredentials(ctx, clusterSpec)
+	}, validations.ProviderSetupCheckName)
+	credentialsCheck.Critical = true
+
+	datacenterCheck := providerCheck(datacenterCheckName, "vsphere datacenter is valid", func() error {
+		return p.validateDatacenter(ctx)
+	}, credentialsCheckName)
+	datacenterCheck.Critical = true
+
+	templateCheck := providerCheck(templateCheckName, "vsphere templates are valid", func() error {
+		return p.setupAndValidateTemplates(ctx, clusterSpec)
+	}, datacenterCheckName)
+	templateCheck.Critical = true
+	// Importing a default template can take longer than the rest of the validations
+	templateCheck.Timeout = templateSetupTimeout
+
+	return []validations.Check{
+		credentialsCheck,
+		datacenterCheck,
+		templateCheck,
+		// The disk size of the machines depends on the template snapshots
+		providerCheck(datastoreCheckName, "vsphere datastores have enough space", func() error {
+			return p.validateDatastores(ctx, clusterSpec)
+		}, templateCheckName),
+	}
+}
+
+func providerCheck(name, description string, validate func() error, dependsOn ...string) validations.Check {
+	return validations.Check{
+		Name:      name,
+		DependsOn: dependsOn,
+		Validation: func() *validations.ValidationResult {
+			return &validations.ValidationResult{Name: description, Err: validate()}
+		},
+	}
+}
+
+// runChecks runs checks in order and returns the first error. The checks are returned in the order of their dependencies
+func runChecks(checks []validations.Check) error {
+	for _, c := range checks {
+		if result := c.Validation(); result.Err != nil {
+			return result.Err
 		}
 	}
+	return nil
+}
 
-	if p.skipIpCheck {
-		logger.Info("Skipping check for whether control plane ip is in use")
-		return nil
+func (p *vsphereProvider) setupCreateCluster(ctx context.Context, clusterSpec *cluster.Spec) error {
+	if err := p.validateEnv(ctx); err != nil {
+		return fmt.Errorf("failed setup and validations: %v", err)
 	}
-	err = p.validateControlPlaneIpUniqueness(clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host)
-	if err != nil {
+	if err := p.completeClusterSpec(clusterSpec); err != nil {
 		return err
 	}
+	if err := p.setupSSHAuthKeysForCreate(); err != nil {
+		return fmt.Errorf("failed setup and validations: %v", err)
+	}
 	return nil
 }
 
-func (p *vsphereProvider) SetupAndValidateUpgradeCluster(ctx context.Context, cluster *types.Cluster, clusterSpec *cluster.Spec) error {
-	err := p.validateEnv(ctx)
-	if err != nil {
+func (p *vsphereProvider) setupUpgradeCluster(ctx context.Context, clusterSpec *cluster.Spec) error {
+	if err := p.validateEnv(ctx); err != nil {
 		return fmt.Errorf("failed setup and validations: %v", err)
 	}
-	err = p.setupAndValidateCluster(ctx, clusterSpec)
-	if err != nil {
+	if err := p.completeClusterSpec(clusterSpec); err != nil {
 		return err
 	}
-	err = p.setupSSHAuthKeysForUpgrade()
-	if err != nil {
+	if err := p.setupSSHAuthKeysForUpgrade(); err != nil {
 		return fmt.Errorf("failed setup and validations: %v", err)
 	}
-	err = p.validateMachineConfigsNameUniqueness(ctx, cluster, clusterSpec)
+	return nil
+}
+
+func (p *vsphereProvider) validateMachineConfigsDontExist(ctx context.Context, clusterSpec *cluster.Spec) error {
+	for _, mc := range p.MachineConfigs() {
+		em, err := p.providerKubectlClient.SearchVsphereMachineConfig(ctx, mc.GetName(), clusterSpec.ManagementCluster.KubeconfigFile, mc.GetNamespace())
+		if err != nil {
+			return err
+		}
+		if len(em) > 0 {
+			return fmt.Errorf("VSphereMachineConfig %s already exists", mc.GetName())
+		}
+	}
+	existingDatacenter, err := p.providerKubectlClient.SearchVsphereDatacenterConfig(ctx, p.datacenterConfig.Name, clusterSpec.ManagementCluster.KubeconfigFile, clusterSpec.Namespace)
 	if err != nil {
-		return fmt.Errorf("failed validate machineconfig uniqueness: %v", err)
+		return err
+	}
+	if len(existingDatacenter) > 0 {
+		return fmt.Errorf("VSphereDatacenter %s already exists", p.datacenterConfig.Name)
 	}
 	return nil
 }
@@ -1178,6 +1343,8 @@ func buildTemplateMapCP(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphe
 		"controlPlaneSshUsername":              controlPlaneMachineSpec.Users[0].Name,
 		"podCidrs":                             clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks,
 		"serviceCidrs":                         clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks,
+		"ipv6":                                 clusterSpec.Spec.ClusterNetwork.IPv6Enabled(),
+		"dualStack":                            clusterSpec.Spec.ClusterNetwork.DualStack(),
 		"apiserverExtraArgs":                   clusterapi.OIDCToExtraArgs(clusterSpec.OIDCConfig).Append(clusterapi.AwsIamAuthExtraArgs(clusterSpec.AWSIamConfig)).ToPartialYaml(),
 		"format":                               format,
 		"externalEtcdVersion":                  bundle.KubeDistro.EtcdVersion,
@@ -1187,33 +1354,13 @@ func buildTemplateMapCP(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphe
 		"resourceSetName":                      resourceSetName(clusterSpec),
 	}
 
-	if clusterSpec.Spec.RegistryMirrorConfiguration != nil {
-		values["registryMirrorConfiguration"] = clusterSpec.Spec.RegistryMirrorConfiguration.Endpoint
-		if len(clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent) > 0 {
-			values["registryCACert"] = clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent
-		}
-	}
+	addRegistryMirrorValues(values, clusterSpec.RegistryMirror())
 
-	if clusterSpec.Spec.ProxyConfiguration != nil {
+	if proxyConfig := proxy.New(clusterSpec.Cluster, datacenterSpec.Server); proxyConfig != nil {
 		values["proxyConfig"] = true
-		capacity := len(clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks) +
-			len(clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks) +
-			len(clusterSpec.Spec.ProxyConfiguration.NoProxy) + 4
-		noProxyList := make([]string, 0, capacity)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks...)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks...)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ProxyConfiguration.NoProxy...)
-
-		// Add no-proxy defaults
-		noProxyList = append(noProxyList, noProxyDefaults...)
-		noProxyList = append(noProxyList,
-			datacenterSpec.Server,
-			clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host,
-		)
-
-		values["httpProxy"] = clusterSpec.Spec.ProxyConfiguration.HttpProxy
-		values["httpsProxy"] = clusterSpec.Spec.ProxyConfiguration.HttpsProxy
-		values["noProxy"] = noProxyList
+		values["httpProxy"] = proxyConfig.HttpProxy
+		values["httpsProxy"] = proxyConfig.HttpsProxy
+		values["noProxy"] = proxyConfig.NoProxy
 	}
 
 	if clusterSpec.Spec.ExternalEtcdConfiguration != nil {
@@ -1248,6 +1395,39 @@ func buildTemplateMapCP(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphe
 	return values
 }
 
+// addRegistryMirrorValues sets the values used to configure containerd in Ubuntu nodes and the registry mirror
+// settings in Bottlerocket nodes. registryMirrorConfiguration is the public.ecr.aws mirror endpoint, used by the
+// Bottlerocket and etcdadm bootstrap providers.
+func addRegistryMirrorValues(values map[string]interface{}, mirror *registrymirror.Configuration) {
+	if mirror == nil {
+		return
+	}
+	values["registryMirrors"] = mirror.Mirrors
+	values["registryMirrorEndpoints"] = mirror.Endpoints()
+	values["registryMirrorContainerdConfig"] = mirror.ContainerdConfig()
+
+	var extraMirrors []registrymirror.Mirror
+	for _, m := range mirror.Mirrors {
+		if m.Registry == registrymirror.DefaultRegistry {
+			values["registryMirrorConfiguration"] = m.Endpoint
+		} else {
+			extraMirrors = append(extraMirrors, m)
+		}
+	}
+	values["registryExtraMirrors"] = extraMirrors
+
+	if len(mirror.CACertContent) > 0 {
+		values["registryCACert"] = mirror.CACertContent
+	}
+	if mirror.InsecureSkipVerify {
+		values["registryInsecureSkipVerify"] = true
+	}
+	if mirror.Credentials != nil {
+		values["registryUsername"] = mirror.Credentials.Username
+		values["registryPassword"] = mirror.Credentials.Password
+	}
+}
+
 func buildTemplateMapMD(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphereDatacenterConfigSpec, workerNodeGroupMachineSpec v1alpha1.VSphereMachineConfigSpec) map[string]interface{} {
 	bundle := clusterSpec.VersionsBundle
 	format := "cloud-config"
@@ -1271,35 +1451,17 @@ func buildTemplateMapMD(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphe
 		"workerSshUsername":              workerNodeGroupMachineSpec.Users[0].Name,
 		"format":                         format,
 		"eksaSystemNamespace":            constants.EksaSystemNamespace,
+		"ipv6":                           clusterSpec.Spec.ClusterNetwork.IPv6Enabled(),
+		"dualStack":                      clusterSpec.Spec.ClusterNetwork.DualStack(),
 	}
 
-	if clusterSpec.Spec.RegistryMirrorConfiguration != nil {
-		values["registryMirrorConfiguration"] = clusterSpec.Spec.RegistryMirrorConfiguration.Endpoint
-		if len(clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent) > 0 {
-			values["registryCACert"] = clusterSpec.Spec.RegistryMirrorConfiguration.CACertContent
-		}
-	}
+	addRegistryMirrorValues(values, clusterSpec.RegistryMirror())
 
-	if clusterSpec.Spec.ProxyConfiguration != nil {
+	if proxyConfig := proxy.New(clusterSpec.Cluster, datacenterSpec.Server); proxyConfig != nil {
 		values["proxyConfig"] = true
-		capacity := len(clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks) +
-			len(clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks) +
-			len(clusterSpec.Spec.ProxyConfiguration.NoProxy) + 4
-		noProxyList := make([]string, 0, capacity)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks...)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks...)
-		noProxyList = append(noProxyList, clusterSpec.Spec.ProxyConfiguration.NoProxy...)
-
-		// Add no-proxy defaults
-		noProxyList = append(noProxyList, noProxyDefaults...)
-		noProxyList = append(noProxyList,
-			datacenterSpec.Server,
-			clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host,
-		)
-
-		values["httpProxy"] = clusterSpec.Spec.ProxyConfiguration.HttpProxy
-		values["httpsProxy"] = clusterSpec.Spec.ProxyConfiguration.HttpsProxy
-		values["noProxy"] = noProxyList
+		values["httpProxy"] = proxyConfig.HttpProxy
+		values["httpsProxy"] = proxyConfig.HttpsProxy
+		values["noProxy"] = proxyConfig.NoProxy
 	}
 
 	if workerNodeGroupMachineSpec.OSFamily == v1alpha1.Bottlerocket {
@@ -1310,6 +1472,10 @@ func buildTemplateMapMD(clusterSpec *cluster.Spec, datacenterSpec v1alpha1.VSphe
 		values["bottlerocketBootstrapVersion"] = bundle.BottleRocketBootstrap.Bootstrap.Tag()
 	}
 
+	if gates := clusterSpec.Spec.UpgradeHealthGates; gates != nil && gates.NodeDrainTimeout != nil {
+		values["nodeDrainTimeout"] = gates.NodeDrainTimeout.Duration.String()
+	}
+
 	return values
 }
 
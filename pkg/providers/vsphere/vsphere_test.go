@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/api/v1alpha3"
 	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+	"sigs.k8s.io/yaml"
 
 	"github.com/aws/eks-anywhere/internal/test"
 	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
@@ -31,7 +32,9 @@ import (
 	"github.com/aws/eks-anywhere/pkg/executables"
 	"github.com/aws/eks-anywhere/pkg/features"
 	"github.com/aws/eks-anywhere/pkg/providers/vsphere/mocks"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
 	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
 	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
 )
 
@@ -655,6 +658,52 @@ func TestProviderGenerateCAPISpecForCreate(t *testing.T) {
 	test.AssertContentToFile(t, string(md), "testdata/expected_results_main_md.yaml")
 }
 
+func TestProviderGenerateCAPISpecForCreateIPv6(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	var tctx testContext
+	tctx.SaveContext()
+	defer tctx.RestoreContext()
+	ctx := context.Background()
+	kubectl := mocks.NewMockProviderKubectlClient(mockCtrl)
+	cluster := &types.Cluster{
+		Name: "test",
+	}
+	clusterSpec := givenClusterSpec(t, testClusterConfigMainFilename)
+	clusterSpec.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"fd00:100::/56"}
+	clusterSpec.Spec.ClusterNetwork.Services.CidrBlocks = []string{"fd00:200::/108"}
+	clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host = "fd00::10"
+
+	datacenterConfig := givenDatacenterConfig(t, testClusterConfigMainFilename)
+	machineConfigs := givenMachineConfigs(t, testClusterConfigMainFilename)
+	provider := newProviderWithKubectl(t, datacenterConfig, machineConfigs, clusterSpec.Cluster, kubectl)
+	if provider == nil {
+		t.Fatalf("provider object is nil")
+	}
+
+	err := provider.SetupAndValidateCreateCluster(ctx, clusterSpec)
+	if err != nil {
+		t.Fatalf("failed to setup and validate: %v", err)
+	}
+
+	cp, md, err := provider.GenerateCAPISpecForCreate(context.Background(), cluster, clusterSpec)
+	if err != nil {
+		t.Fatalf("failed to generate cluster api spec contents: %v", err)
+	}
+	test.AssertContentToFile(t, string(cp), "testdata/expected_results_main_ipv6_cp.yaml")
+	test.AssertContentToFile(t, string(md), "testdata/expected_results_main_ipv6_md.yaml")
+	assertValidYaml(t, cp)
+	assertValidYaml(t, md)
+}
+
+// assertValidYaml checks every document in the manifest parses, which the golden files alone don't catch
+func assertValidYaml(t *testing.T, manifest []byte) {
+	for i, document := range strings.Split(string(manifest), v1alpha1.YamlSeparator) {
+		if err := yaml.Unmarshal([]byte(document), &map[string]interface{}{}); err != nil {
+			t.Errorf("document %d of the manifest is not valid yaml: %v", i+1, err)
+		}
+	}
+}
+
 func TestProviderGenerateStorageClass(t *testing.T) {
 	provider := givenProvider(t)
 
@@ -800,6 +849,61 @@ func TestProviderGenerateDeploymentFileWithMirrorAndCertConfig(t *testing.T) {
 	test.AssertContentToFile(t, string(md), "testdata/expected_results_mirror_config_with_cert_md.yaml")
 }
 
+func TestProviderGenerateDeploymentFileWithMirrorAuthConfig(t *testing.T) {
+	clusterSpecManifest := "cluster_mirror_with_auth_config.yaml"
+	mockCtrl := gomock.NewController(t)
+	setupContext(t)
+	kubectl := mocks.NewMockProviderKubectlClient(mockCtrl)
+	cluster := &types.Cluster{Name: "test"}
+	clusterSpec := givenClusterSpec(t, clusterSpecManifest)
+	datacenterConfig := givenDatacenterConfig(t, clusterSpecManifest)
+	machineConfigs := givenMachineConfigs(t, clusterSpecManifest)
+	ctx := context.Background()
+	govc := NewDummyProviderGovcClient()
+	govc.osTag = ubuntuOSTag
+	provider := newProviderWithKubectl(t, datacenterConfig, machineConfigs, clusterSpec.Cluster, kubectl)
+
+	if err := provider.SetupAndValidateCreateCluster(ctx, clusterSpec); err != nil {
+		t.Fatalf("failed to setup and validate: %v", err)
+	}
+
+	cp, md, err := provider.GenerateCAPISpecForCreate(context.Background(), cluster, clusterSpec)
+	if err != nil {
+		t.Fatalf("failed to generate cluster api spec contents: %v", err)
+	}
+
+	test.AssertContentToFile(t, string(cp), "testdata/expected_results_mirror_config_with_auth_cp.yaml")
+	test.AssertContentToFile(t, string(md), "testdata/expected_results_mirror_config_with_auth_md.yaml")
+}
+
+func TestProviderGenerateDeploymentFileForBottleRocketWithMirrorAuthConfig(t *testing.T) {
+	clusterSpecManifest := "cluster_bottlerocket_mirror_with_auth_config.yaml"
+	mockCtrl := gomock.NewController(t)
+	setupContext(t)
+	kubectl := mocks.NewMockProviderKubectlClient(mockCtrl)
+	cluster := &types.Cluster{Name: "test"}
+	clusterSpec := givenClusterSpec(t, clusterSpecManifest)
+	clusterSpec.RegistryMirrorCredentials = &registrymirror.Credentials{Username: "secret-user", Password: "secret-password"}
+	datacenterConfig := givenDatacenterConfig(t, clusterSpecManifest)
+	machineConfigs := givenMachineConfigs(t, clusterSpecManifest)
+	ctx := context.Background()
+	govc := NewDummyProviderGovcClient()
+	govc.osTag = bottlerocketOSTag
+	provider := newProviderWithKubectl(t, datacenterConfig, machineConfigs, clusterSpec.Cluster, kubectl)
+	provider.providerGovcClient = govc
+	if err := provider.SetupAndValidateCreateCluster(ctx, clusterSpec); err != nil {
+		t.Fatalf("failed to setup and validate: %v", err)
+	}
+
+	cp, md, err := provider.GenerateCAPISpecForCreate(context.Background(), cluster, clusterSpec)
+	if err != nil {
+		t.Fatalf("failed to generate cluster api spec contents: %v", err)
+	}
+
+	test.AssertContentToFile(t, string(cp), "testdata/expected_results_bottlerocket_mirror_config_with_auth_cp.yaml")
+	test.AssertContentToFile(t, string(md), "testdata/expected_results_bottlerocket_mirror_config_with_auth_md.yaml")
+}
+
 func TestUpdateKubeConfig(t *testing.T) {
 	provider := givenProvider(t)
 	content := []byte{}
@@ -845,6 +949,40 @@ func TestSetupAndValidateCreateCluster(t *testing.T) {
 	}
 }
 
+func TestSetupAndValidateCreateClusterChecks(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	provider := givenProvider(t)
+	clusterSpec := givenEmptyClusterSpec()
+	fillClusterSpecWithClusterConfig(clusterSpec, givenClusterConfig(t, testClusterConfigMainFilename))
+	var tctx testContext
+	tctx.SaveContext()
+	defer tctx.RestoreContext()
+
+	checks := provider.SetupAndValidateCreateClusterChecks(ctx, clusterSpec)
+	dependencies := map[string][]string{}
+	critical := []string{}
+	for _, c := range checks {
+		if c.Critical {
+			critical = append(critical, c.Name)
+		}
+		dependencies[c.Name] = c.DependsOn
+	}
+	g.Expect(critical).To(ConsistOf(validations.ProviderSetupCheckName, "vsphere credentials", "vsphere datacenter", "vsphere template"), "the checks completing the provider setup can't be skipped")
+	g.Expect(dependencies).To(Equal(map[string][]string{
+		validations.ProviderSetupCheckName: nil,
+		"vsphere credentials":              {validations.ProviderSetupCheckName},
+		"vsphere datacenter":               {"vsphere credentials"},
+		"vsphere template":                 {"vsphere datacenter"},
+		"vsphere datastore":                {"vsphere template"},
+		"vsphere control plane ip":         {validations.ProviderSetupCheckName},
+	}))
+
+	runner := validations.NewRunner()
+	runner.RegisterChecks(checks...)
+	g.Expect(runner.Run()).To(Succeed())
+}
+
 func thenErrorPrefixExpected(t *testing.T, expected string, err error) {
 	if err == nil {
 		t.Fatalf("Expected=<%s> actual=<nil>", expected)
@@ -1590,6 +1728,20 @@ func TestSetupAndValidateCreateClusterBogusIp(t *testing.T) {
 	thenErrorExpected(t, "cluster controlPlaneConfiguration.Endpoint.Host is invalid: bogus", err)
 }
 
+func TestSetupAndValidateCreateClusterIPFamilyMismatch(t *testing.T) {
+	ctx := context.Background()
+	clusterSpec := givenEmptyClusterSpec()
+	fillClusterSpecWithClusterConfig(clusterSpec, givenClusterConfig(t, testClusterConfigMainFilename))
+	provider := givenProvider(t)
+	clusterSpec.Spec.ControlPlaneConfiguration.Endpoint.Host = "fd00::10"
+	var tctx testContext
+	tctx.SaveContext()
+
+	err := provider.SetupAndValidateCreateCluster(ctx, clusterSpec)
+
+	thenErrorExpected(t, "cluster controlPlaneConfiguration.Endpoint.Host fd00::10 is an IPv6 address but the primary IP family of the cluster is IPv4", err)
+}
+
 func TestSetupAndValidateCreateClusterUsedIp(t *testing.T) {
 	ctx := context.Background()
 	clusterSpec := givenEmptyClusterSpec()
@@ -2129,6 +2281,49 @@ func TestSetupAndValidateCreateClusterDefaultTemplate(t *testing.T) {
 	}
 }
 
+func TestSetupAndValidateCreateClusterReadOnlyDefaultTemplate(t *testing.T) {
+	ctx := context.Background()
+	clusterSpec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.VersionsBundle.EksD.Ova.Ubuntu.URI = "https://amazonaws.com/artifacts/0.0.1/eks-distro/ova/1-19/1-19-4/ubuntu-v1.19.8-eks-d-1-19-4-eks-a-0.0.1.build.38-amd64.ova"
+		s.VersionsBundle.EksD.Ova.Ubuntu.SHA256 = "63a8dce1683379cb8df7d15e9c5adf9462a2b9803a544dd79b16f19a4657967f"
+		s.VersionsBundle.EksD.Ova.Ubuntu.Arch = []string{"amd64"}
+		s.VersionsBundle.EksD.Name = eksd119Release
+		s.VersionsBundle.EksD.KubeVersion = "v1.19.8"
+		s.VersionsBundle.KubeVersion = "1.19"
+		s.Namespace = "test-namespace"
+	})
+	fillClusterSpecWithClusterConfig(clusterSpec, givenClusterConfig(t, testClusterConfigMainFilename))
+	govc := givenGovcMock(t)
+	provider := newProvider(
+		t,
+		givenDatacenterConfig(t, testClusterConfigMainFilename),
+		givenMachineConfigs(t, testClusterConfigMainFilename),
+		clusterSpec.Cluster,
+		govc,
+		nil,
+		nil,
+	)
+	provider.SetReadOnly(true)
+	controlPlaneMachineConfigName := clusterSpec.Spec.ControlPlaneConfiguration.MachineGroupRef.Name
+	provider.machineConfigs[controlPlaneMachineConfigName].Spec.Template = ""
+	wantTemplate := "/SDDC-Datacenter/vm/Templates/ubuntu-v1.19.8-kubernetes-1-19-eks-4-amd64-63a8dce"
+	setupContext(t)
+
+	govc.EXPECT().ValidateVCenterSetup(ctx, provider.datacenterConfig, &provider.selfSigned).Return(nil)
+	for _, config := range provider.machineConfigs {
+		govc.EXPECT().ValidateVCenterSetupMachineConfig(ctx, provider.datacenterConfig, config, &provider.selfSigned).Return(nil)
+	}
+	govc.EXPECT().SearchTemplate(ctx, provider.datacenterConfig.Spec.Datacenter, provider.machineConfigs[controlPlaneMachineConfigName]).Return("", nil)
+	govc.EXPECT().CreateLibrary(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	govc.EXPECT().ImportTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	govc.EXPECT().DeployTemplateFromLibrary(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	govc.EXPECT().CreateTag(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := provider.SetupAndValidateCreateCluster(ctx, clusterSpec)
+
+	thenErrorExpected(t, "template not set for VSphereMachineConfig "+controlPlaneMachineConfigName+" and the default template "+wantTemplate+" doesn't exist yet, create cluster imports it", err)
+}
+
 func TestGetInfrastructureBundleSuccess(t *testing.T) {
 	tests := []struct {
 		testName    string
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const defaultConnectivityTimeout = 10 * time.Second
+
+// Target is an endpoint that has to be reachable with the proxy configuration
+type Target struct {
+	Name string
+	URL  string
+}
+
+// ConnectivityValidator checks that targets can be reached the same way the cluster components reach them,
+// through the proxy or directly when they match the noProxy list
+type ConnectivityValidator struct {
+	config  *Configuration
+	timeout time.Duration
+}
+
+func NewConnectivityValidator(config *Configuration) *ConnectivityValidator {
+	return &ConnectivityValidator{
+		config:  config,
+		timeout: defaultConnectivityTimeout,
+	}
+}
+
+// Validate reaches every target and reports which hop failed: the proxy itself, the target through the proxy
+// or the target when reached directly.
+func (v *ConnectivityValidator) Validate(ctx context.Context, targets ...Target) error {
+	if v.config == nil {
+		return nil
+	}
+
+	var errs []string
+	for _, t := range targets {
+		if err := v.validateTarget(ctx, t); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("proxy connectivity validation failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (v *ConnectivityValidator) validateTarget(ctx context.Context, t Target) error {
+	targetURL, err := url.Parse(t.URL)
+	if err != nil || targetURL.Host == "" {
+		return fmt.Errorf("%s: invalid url %s", t.Name, t.URL)
+	}
+
+	proxyURL, err := v.proxyFunc()(targetURL)
+	if err != nil {
+		return fmt.Errorf("%s: invalid proxy configuration: %v", t.Name, err)
+	}
+
+	if proxyURL == nil {
+		if err = v.get(ctx, targetURL, nil); err != nil {
+			return fmt.Errorf("%s: %s matches noProxy and is not reachable directly: %v", t.Name, targetURL.Host, err)
+		}
+		return nil
+	}
+
+	dialer := &net.Dialer{Timeout: v.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return fmt.Errorf("%s: proxy %s is not reachable: %v", t.Name, proxyURL.Host, err)
+	}
+	conn.Close()
+
+	if err = v.get(ctx, targetURL, proxyURL); err != nil {
+		return fmt.Errorf("%s: %s is not reachable through proxy %s: %v", t.Name, targetURL.Host, proxyURL.Host, err)
+	}
+
+	return nil
+}
+
+// get sends a request to the target. Any response from the target counts as reachable, except for
+// gateway errors returned by the proxy when it can't connect to the target.
+func (v *ConnectivityValidator) get(ctx context.Context, target, proxyURL *url.URL) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	client := &http.Client{Transport: transport, Timeout: v.timeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if proxyURL != nil && isGatewayError(resp.StatusCode) {
+		return fmt.Errorf("proxy returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (v *ConnectivityValidator) proxyFunc() func(*url.URL) (*url.URL, error) {
+	config := &httpproxy.Config{
+		HTTPProxy:  proxyURL(v.config.HttpProxy),
+		HTTPSProxy: proxyURL(v.config.HttpsProxy),
+		NoProxy:    v.config.NoProxyString(),
+	}
+	return config.ProxyFunc()
+}
+
+// proxyURL adds the http scheme to proxies configured as host:port
+func proxyURL(proxy string) string {
+	if proxy == "" || strings.Contains(proxy, "://") {
+		return proxy
+	}
+	return "http://" + proxy
+}
+
+func isGatewayError(statusCode int) bool {
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable || statusCode == http.StatusGatewayTimeout
+}
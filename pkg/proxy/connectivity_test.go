@@ -0,0 +1,89 @@
+package proxy_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/proxy"
+)
+
+// newTestProxy returns a forward proxy that only knows how to reach bundles.example.com
+func newTestProxy(t *testing.T) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "bundles.example.com" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func closedAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+	return address
+}
+
+func TestConnectivityValidatorThroughProxy(t *testing.T) {
+	g := NewWithT(t)
+	p := newTestProxy(t)
+	config := &proxy.Configuration{HttpProxy: p.Listener.Addr().String(), HttpsProxy: p.URL}
+	v := proxy.NewConnectivityValidator(config)
+
+	g.Expect(v.Validate(context.Background(), proxy.Target{Name: "bundles", URL: "http://bundles.example.com/bundle.yaml"})).To(Succeed())
+}
+
+func TestConnectivityValidatorTargetNotReachableThroughProxy(t *testing.T) {
+	g := NewWithT(t)
+	p := newTestProxy(t)
+	config := &proxy.Configuration{HttpProxy: p.URL, HttpsProxy: p.URL}
+	v := proxy.NewConnectivityValidator(config)
+
+	err := v.Validate(context.Background(), proxy.Target{Name: "vCenter", URL: "http://vcenter.example.com"})
+	g.Expect(err).To(MatchError(ContainSubstring("vCenter: vcenter.example.com is not reachable through proxy")))
+	g.Expect(err).To(MatchError(ContainSubstring("proxy returned 502 Bad Gateway")))
+}
+
+func TestConnectivityValidatorProxyNotReachable(t *testing.T) {
+	g := NewWithT(t)
+	address := closedAddress(t)
+	config := &proxy.Configuration{HttpProxy: address, HttpsProxy: address}
+	v := proxy.NewConnectivityValidator(config)
+
+	err := v.Validate(context.Background(), proxy.Target{Name: "bundles", URL: "http://bundles.example.com/bundle.yaml"})
+	g.Expect(err).To(MatchError(ContainSubstring("bundles: proxy " + address + " is not reachable")))
+}
+
+func TestConnectivityValidatorNoProxy(t *testing.T) {
+	g := NewWithT(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer target.Close()
+	address := closedAddress(t)
+	config := &proxy.Configuration{HttpProxy: address, HttpsProxy: address, NoProxy: []string{"127.0.0.1"}}
+	v := proxy.NewConnectivityValidator(config)
+
+	g.Expect(v.Validate(context.Background(), proxy.Target{Name: "local", URL: target.URL})).To(Succeed())
+
+	err := v.Validate(context.Background(), proxy.Target{Name: "local", URL: "http://" + closedAddress(t)})
+	g.Expect(err).To(MatchError(ContainSubstring("matches noProxy and is not reachable directly")))
+}
+
+func TestConnectivityValidatorNilConfiguration(t *testing.T) {
+	g := NewWithT(t)
+	v := proxy.NewConnectivityValidator(nil)
+
+	g.Expect(v.Validate(context.Background(), proxy.Target{Name: "bundles", URL: "http://bundles.example.com"})).To(Succeed())
+}
@@ -0,0 +1,127 @@
+package proxy
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
+)
+
+const (
+	HttpProxyKey  = "HTTP_PROXY"
+	HttpsProxyKey = "HTTPS_PROXY"
+	NoProxyKey    = "NO_PROXY"
+
+	clusterDomain = ".cluster.local"
+)
+
+var noProxyDefaults = []string{
+	"localhost",
+	"127.0.0.1",
+	".svc",
+	clusterDomain,
+}
+
+// Configuration is the proxy configuration every component of a cluster is configured with,
+// both in the CLI and in the cluster nodes
+type Configuration struct {
+	HttpProxy  string
+	HttpsProxy string
+	NoProxy    []string
+}
+
+// New computes the proxy configuration for a cluster. Besides the user provided noProxy list, it includes
+// the pod and service CIDRs, the cluster domain, the control plane endpoint, the registry mirror endpoints and any extra
+// hosts, like the provider endpoint. It returns nil if the cluster doesn't use a proxy.
+func New(clusterConfig *v1alpha1.Cluster, noProxyHosts ...string) *Configuration {
+	proxyConfig := clusterConfig.Spec.ProxyConfiguration
+	if proxyConfig == nil {
+		return nil
+	}
+
+	noProxy := make([]string, 0, len(proxyConfig.NoProxy)+len(noProxyDefaults)+len(noProxyHosts)+6)
+	noProxy = append(noProxy, clusterConfig.Spec.ClusterNetwork.Pods.CidrBlocks...)
+	noProxy = append(noProxy, clusterConfig.Spec.ClusterNetwork.Services.CidrBlocks...)
+	noProxy = append(noProxy, proxyConfig.NoProxy...)
+	noProxy = append(noProxy, noProxyDefaults...)
+	noProxy = append(noProxy, noProxyHosts...)
+	if clusterConfig.Spec.ControlPlaneConfiguration.Endpoint != nil {
+		noProxy = append(noProxy, clusterConfig.Spec.ControlPlaneConfiguration.Endpoint.Host)
+	}
+	for _, endpoint := range registrymirror.New(clusterConfig).Endpoints() {
+		noProxy = append(noProxy, hostname(endpoint))
+	}
+
+	return &Configuration{
+		HttpProxy:  proxyConfig.HttpProxy,
+		HttpsProxy: proxyConfig.HttpsProxy,
+		NoProxy:    dedup(noProxy),
+	}
+}
+
+// WithNoProxy returns a copy of the configuration with hosts added to the noProxy list
+func (c *Configuration) WithNoProxy(hosts ...string) *Configuration {
+	if c == nil {
+		return nil
+	}
+	noProxy := make([]string, 0, len(c.NoProxy)+len(hosts))
+	noProxy = append(noProxy, c.NoProxy...)
+	noProxy = append(noProxy, hosts...)
+
+	return &Configuration{
+		HttpProxy:  c.HttpProxy,
+		HttpsProxy: c.HttpsProxy,
+		NoProxy:    dedup(noProxy),
+	}
+}
+
+// NoProxyString returns the noProxy list in the comma separated format used by the NO_PROXY env var
+func (c *Configuration) NoProxyString() string {
+	if c == nil {
+		return ""
+	}
+	return strings.Join(c.NoProxy, ",")
+}
+
+// EnvMap returns the proxy env vars. It's empty for a nil configuration, so it can always be merged
+// into other env maps.
+func (c *Configuration) EnvMap() map[string]string {
+	if c == nil {
+		return map[string]string{}
+	}
+	return map[string]string{
+		HttpProxyKey:  c.HttpProxy,
+		HttpsProxyKey: c.HttpsProxy,
+		NoProxyKey:    c.NoProxyString(),
+	}
+}
+
+// hostname strips the scheme, port and path from an endpoint, since noProxy entries are matched against hosts
+func hostname(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return strings.SplitN(endpoint, "/", 2)[0]
+}
+
+func dedup(entries []string) []string {
+	seen := make(map[string]struct{}, len(entries))
+	deduped := make([]string, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+		deduped = append(deduped, e)
+	}
+	return deduped
+}
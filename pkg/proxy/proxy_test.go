@@ -0,0 +1,75 @@
+package proxy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/proxy"
+)
+
+func givenCluster() *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		Spec: v1alpha1.ClusterSpec{
+			ClusterNetwork: v1alpha1.ClusterNetwork{
+				Pods:     v1alpha1.Pods{CidrBlocks: []string{"192.168.0.0/16"}},
+				Services: v1alpha1.Services{CidrBlocks: []string{"10.96.0.0/12"}},
+			},
+			ControlPlaneConfiguration: v1alpha1.ControlPlaneConfiguration{
+				Endpoint: &v1alpha1.Endpoint{Host: "1.2.3.4"},
+			},
+			ProxyConfiguration: &v1alpha1.ProxyConfiguration{
+				HttpProxy:  "1.2.3.5:3128",
+				HttpsProxy: "1.2.3.5:3128",
+				NoProxy:    []string{"corp.example.com", "localhost", ""},
+			},
+		},
+	}
+}
+
+func TestNewNoProxy(t *testing.T) {
+	g := NewWithT(t)
+	c := givenCluster()
+	c.Spec.RegistryMirrorConfiguration = &v1alpha1.RegistryMirrorConfiguration{Endpoint: "mirror.example.com:443"}
+
+	config := proxy.New(c, "vcenter.example.com")
+
+	g.Expect(config.HttpProxy).To(Equal("1.2.3.5:3128"))
+	g.Expect(config.HttpsProxy).To(Equal("1.2.3.5:3128"))
+	g.Expect(config.NoProxy).To(Equal([]string{
+		"192.168.0.0/16",
+		"10.96.0.0/12",
+		"corp.example.com",
+		"localhost",
+		"127.0.0.1",
+		".svc",
+		".cluster.local",
+		"vcenter.example.com",
+		"1.2.3.4",
+		"mirror.example.com",
+	}))
+}
+
+func TestNewWithoutProxy(t *testing.T) {
+	g := NewWithT(t)
+	c := givenCluster()
+	c.Spec.ProxyConfiguration = nil
+
+	config := proxy.New(c)
+
+	g.Expect(config).To(BeNil())
+	g.Expect(config.EnvMap()).To(BeEmpty())
+	g.Expect(config.WithNoProxy("vcenter.example.com")).To(BeNil())
+}
+
+func TestEnvMap(t *testing.T) {
+	g := NewWithT(t)
+	config := proxy.New(givenCluster()).WithNoProxy("vcenter.example.com", "localhost")
+
+	g.Expect(config.EnvMap()).To(Equal(map[string]string{
+		"HTTP_PROXY":  "1.2.3.5:3128",
+		"HTTPS_PROXY": "1.2.3.5:3128",
+		"NO_PROXY":    "192.168.0.0/16,10.96.0.0/12,corp.example.com,localhost,127.0.0.1,.svc,.cluster.local,1.2.3.4,vcenter.example.com",
+	}))
+}
@@ -0,0 +1,307 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	pullAction     = "pull"
+	pushPullAction = "pull,push"
+)
+
+// Credentials used to authenticate with a registry, either directly with basic auth or
+// to request a token from the registry token service
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Client talks to OCI distribution compatible registries without a docker daemon
+type Client struct {
+	httpClient         *http.Client
+	caCerts            [][]byte
+	insecureSkipVerify bool
+	credentials        map[string]Credentials
+
+	authLock  sync.RWMutex
+	tokens    map[string]string
+	basicAuth map[string]bool
+}
+
+type ClientOpt func(*Client)
+
+// WithCACert adds a PEM encoded CA certificate to the system pool used to verify registries
+func WithCACert(caCert []byte) ClientOpt {
+	return func(c *Client) {
+		if len(caCert) > 0 {
+			c.caCerts = append(c.caCerts, caCert)
+		}
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the registries TLS certificates
+func WithInsecureSkipVerify() ClientOpt {
+	return func(c *Client) {
+		c.insecureSkipVerify = true
+	}
+}
+
+// WithCredentials configures the credentials used for a registry host
+func WithCredentials(registry string, credentials Credentials) ClientOpt {
+	return func(c *Client) {
+		c.credentials[registry] = credentials
+	}
+}
+
+func NewClient(opts ...ClientOpt) (*Client, error) {
+	c := &Client{
+		credentials: map[string]Credentials{},
+		tokens:      map[string]string{},
+		basicAuth:   map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	} else if len(c.caCerts) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, caCert := range c.caCerts {
+			if !pool.AppendCertsFromPEM(caCert) {
+				return nil, fmt.Errorf("error creating registry client: invalid CA certificate")
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	c.httpClient = &http.Client{Transport: transport}
+
+	return c, nil
+}
+
+// Login checks the client can access the registry, authenticating with the configured credentials if the
+// registry requires it
+func (c *Client) Login(ctx context.Context, registry string) error {
+	ref := Reference{Registry: registry}
+	newRequest := func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/v2/", ref.apiHost()), nil)
+	}
+
+	resp, err := c.send(ctx, ref, "", newRequest)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err = c.authenticate(ctx, ref, "", challenge); err != nil {
+			return err
+		}
+		if resp, err = c.send(ctx, ref, "", newRequest); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("invalid credentials for registry %s", registry)
+	default:
+		return unexpectedStatusError(resp.Request, resp)
+	}
+}
+
+// do sends the request built by newRequest, authenticating with the registry if it responds with
+// a challenge. newRequest might be called more than once, so it should return a fresh body each time.
+func (c *Client) do(ctx context.Context, ref Reference, actions string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	resp, err := c.send(ctx, ref, actions, newRequest)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if err = c.authenticate(ctx, ref, actions, challenge); err != nil {
+		return nil, err
+	}
+
+	resp, err = c.send(ctx, ref, actions, newRequest)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unauthorized to %s %s/%s", actions, ref.Registry, ref.Repository)
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, ref Reference, actions string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	c.authorize(req, ref, actions)
+	return c.httpClient.Do(req.WithContext(ctx))
+}
+
+func (c *Client) authorize(req *http.Request, ref Reference, actions string) {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+	if token, ok := c.tokens[authKey(ref, actions)]; ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return
+	}
+	if c.basicAuth[ref.Registry] {
+		creds := c.credentials[ref.Registry]
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+}
+
+func (c *Client) authenticate(ctx context.Context, ref Reference, actions, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	creds, hasCreds := c.credentials[ref.Registry]
+
+	switch scheme {
+	case "basic":
+		if !hasCreds {
+			return fmt.Errorf("registry %s requires credentials", ref.Registry)
+		}
+		c.authLock.Lock()
+		c.basicAuth[ref.Registry] = true
+		c.authLock.Unlock()
+		return nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, ref, actions, params, creds, hasCreds)
+		if err != nil {
+			return fmt.Errorf("error authenticating with registry %s: %v", ref.Registry, err)
+		}
+		c.authLock.Lock()
+		c.tokens[authKey(ref, actions)] = token
+		c.authLock.Unlock()
+		return nil
+	default:
+		return fmt.Errorf("registry %s returned unsupported authentication challenge [%s]", ref.Registry, challenge)
+	}
+}
+
+func (c *Client) fetchToken(ctx context.Context, ref Reference, actions string, params map[string]string, creds Credentials, hasCreds bool) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("token challenge without realm")
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %s: %v", realm, err)
+	}
+	query := tokenURL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	if ref.Repository != "" {
+		query.Set("scope", fmt.Sprintf("repository:%s:%s", ref.Repository, actions))
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCreds {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", unexpectedStatusError(req, resp)
+	}
+
+	token := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return "", fmt.Errorf("error parsing token response: %v", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token response from %s doesn't contain a token", realm)
+}
+
+func authKey(ref Reference, actions string) string {
+	return ref.Registry + "/" + ref.Repository + ":" + actions
+}
+
+// parseChallenge parses a WWW-Authenticate header like
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseChallenge(challenge string) (scheme string, params map[string]string) {
+	params = map[string]string{}
+	challenge = strings.TrimSpace(challenge)
+	i := strings.Index(challenge, " ")
+	if i < 0 {
+		return strings.ToLower(challenge), params
+	}
+	scheme = strings.ToLower(challenge[:i])
+
+	rest := challenge[i+1:]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.Index(rest, ","); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return scheme, params
+}
+
+func (c *Client) url(ref Reference, format string, args ...interface{}) string {
+	return fmt.Sprintf("https://%s/v2/%s/%s", ref.apiHost(), ref.Repository, fmt.Sprintf(format, args...))
+}
+
+func unexpectedStatusError(req *http.Request, resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %s from %s %s: %s", resp.Status, req.Method, req.URL.Redacted(), strings.TrimSpace(string(body)))
+}
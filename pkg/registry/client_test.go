@@ -0,0 +1,86 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+func TestClientLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry *test.Registry
+		password string
+		wantErr  string
+	}{
+		{
+			name:     "anonymous",
+			registry: test.NewRegistry(t),
+		},
+		{
+			name:     "basic auth",
+			registry: test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password")),
+			password: "password",
+		},
+		{
+			name:     "token auth",
+			registry: test.NewRegistry(t, test.WithRegistryTokenAuth("user", "password")),
+			password: "password",
+		},
+		{
+			name:     "basic auth wrong password",
+			registry: test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password")),
+			password: "wrong",
+			wantErr:  "invalid credentials for registry",
+		},
+		{
+			name:     "token auth wrong password",
+			registry: test.NewRegistry(t, test.WithRegistryTokenAuth("user", "password")),
+			password: "wrong",
+			wantErr:  "401 Unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			client, err := registry.NewClient(
+				registry.WithCACert(tt.registry.CACert()),
+				registry.WithCredentials(tt.registry.Host(), registry.Credentials{Username: "user", Password: tt.password}),
+			)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = client.Login(context.Background(), tt.registry.Host())
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			}
+		})
+	}
+}
+
+func TestClientLoginWithoutCredentials(t *testing.T) {
+	g := NewWithT(t)
+	r := test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password"))
+	client, err := registry.NewClient(registry.WithCACert(r.CACert()))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(client.Login(context.Background(), r.Host())).To(MatchError(ContainSubstring("requires credentials")))
+}
+
+func TestClientLoginInsecureSkipVerify(t *testing.T) {
+	g := NewWithT(t)
+	r := test.NewRegistry(t)
+
+	client, err := registry.NewClient()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(client.Login(context.Background(), r.Host())).To(MatchError(ContainSubstring("certificate")))
+
+	client, err = registry.NewClient(registry.WithInsecureSkipVerify())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(client.Login(context.Background(), r.Host())).To(Succeed())
+}
@@ -0,0 +1,368 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	MediaTypeOCIIndex            = "application/vnd.oci.image.index.v1+json"
+	MediaTypeOCIManifest         = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifestList  = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeDockerManifest      = "application/vnd.docker.distribution.manifest.v2+json"
+	dockerContentDigestHeader    = "Docker-Content-Digest"
+	defaultManifestAcceptHeaders = MediaTypeOCIIndex + ", " + MediaTypeDockerManifestList + ", " + MediaTypeOCIManifest + ", " + MediaTypeDockerManifest
+)
+
+// Descriptor references a blob or a manifest by digest
+type Descriptor struct {
+	MediaType   string            `json:"mediaType,omitempty"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest covers the fields needed to walk both image manifests and image indexes,
+// in the OCI and docker formats
+type Manifest struct {
+	MediaType string       `json:"mediaType,omitempty"`
+	Manifests []Descriptor `json:"manifests,omitempty"`
+	Config    *Descriptor  `json:"config,omitempty"`
+	Layers    []Descriptor `json:"layers,omitempty"`
+}
+
+// IsIndex returns true for multi-platform manifests
+func (m *Manifest) IsIndex() bool {
+	return m.MediaType == MediaTypeOCIIndex || m.MediaType == MediaTypeDockerManifestList || len(m.Manifests) > 0
+}
+
+// Blobs returns the config and layers of an image manifest
+func (m *Manifest) Blobs() []Descriptor {
+	blobs := make([]Descriptor, 0, len(m.Layers)+1)
+	if m.Config != nil {
+		blobs = append(blobs, *m.Config)
+	}
+	return append(blobs, m.Layers...)
+}
+
+// Digest computes the sha256 digest of a manifest or blob content
+func Digest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// GetManifest returns the raw manifest, its media type and digest
+func (c *Client) GetManifest(ctx context.Context, ref Reference) (content []byte, mediaType, digest string, err error) {
+	resp, err := c.do(ctx, ref, pullAction, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, c.url(ref, "manifests/%s", ref.Identifier()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", defaultManifestAcceptHeaders)
+		return req, nil
+	})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("error getting manifest for %s: %v", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("error getting manifest for %s: %v", ref, unexpectedStatusError(resp.Request, resp))
+	}
+
+	content, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("error reading manifest for %s: %v", ref, err)
+	}
+
+	digest = Digest(content)
+	if ref.Digest != "" && ref.Digest != digest {
+		return nil, "", "", fmt.Errorf("manifest for %s has digest %s", ref, digest)
+	}
+
+	mediaType = strings.Split(resp.Header.Get("Content-Type"), ";")[0]
+	if mediaType == "" || mediaType == "application/json" {
+		m, err := parseManifest(content)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("error parsing manifest for %s: %v", ref, err)
+		}
+		mediaType = m.MediaType
+	}
+
+	return content, mediaType, digest, nil
+}
+
+// ManifestDigest returns the digest of the manifest the reference points to, and false if it doesn't exist
+func (c *Client) ManifestDigest(ctx context.Context, ref Reference) (digest string, exists bool, err error) {
+	resp, err := c.do(ctx, ref, pullAction, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodHead, c.url(ref, "manifests/%s", ref.Identifier()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", defaultManifestAcceptHeaders)
+		return req, nil
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("error checking manifest for %s: %v", ref, err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("error checking manifest for %s: %v", ref, unexpectedStatusError(resp.Request, resp))
+	}
+
+	if digest = resp.Header.Get(dockerContentDigestHeader); digest != "" {
+		return digest, true, nil
+	}
+
+	// Not all registries return the digest header for HEAD requests
+	_, _, digest, err = c.GetManifest(ctx, ref)
+	if err != nil {
+		return "", false, err
+	}
+	return digest, true, nil
+}
+
+// PutManifest uploads the manifest content as is, so its digest is preserved
+func (c *Client) PutManifest(ctx context.Context, ref Reference, mediaType string, content []byte) error {
+	resp, err := c.do(ctx, ref, pushPullAction, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, c.url(ref, "manifests/%s", ref.Identifier()), bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", mediaType)
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error pushing manifest for %s: %v", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error pushing manifest for %s: %v", ref, unexpectedStatusError(resp.Request, resp))
+	}
+	return nil
+}
+
+// BlobExists checks if a blob is already present in the repository
+func (c *Client) BlobExists(ctx context.Context, ref Reference, digest string) (bool, error) {
+	resp, err := c.do(ctx, ref, pullAction, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodHead, c.url(ref, "blobs/%s", digest), nil)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking blob %s in %s: %v", digest, ref, err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("error checking blob %s in %s: %v", digest, ref, unexpectedStatusError(resp.Request, resp))
+	}
+}
+
+// GetBlob opens a blob for reading. The caller is responsible for closing it.
+func (c *Client) GetBlob(ctx context.Context, ref Reference, digest string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, ref, pullAction, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, c.url(ref, "blobs/%s", digest), nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob %s from %s: %v", digest, ref, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("error getting blob %s from %s: %v", digest, ref, unexpectedStatusError(resp.Request, resp))
+	}
+	return resp.Body, nil
+}
+
+// PushBlob uploads a blob with a monolithic upload. open is called to get the blob content
+// and it might be called more than once if the registry requests authentication.
+func (c *Client) PushBlob(ctx context.Context, ref Reference, desc Descriptor, open func() (io.ReadCloser, error)) error {
+	location, err := c.startUpload(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("error pushing blob %s to %s: %v", desc.Digest, ref, err)
+	}
+	query := location.Query()
+	query.Set("digest", desc.Digest)
+	location.RawQuery = query.Encode()
+
+	resp, err := c.do(ctx, ref, pushPullAction, func() (*http.Request, error) {
+		body, err := open()
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(http.MethodPut, location.String(), body)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		req.ContentLength = desc.Size
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error pushing blob %s to %s: %v", desc.Digest, ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("error pushing blob %s to %s: %v", desc.Digest, ref, unexpectedStatusError(resp.Request, resp))
+	}
+	return nil
+}
+
+func (c *Client) startUpload(ctx context.Context, ref Reference) (*url.URL, error) {
+	uploadsURL := c.url(ref, "blobs/uploads/")
+	resp, err := c.do(ctx, ref, pushPullAction, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, uploadsURL, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, unexpectedStatusError(resp.Request, resp)
+	}
+
+	base, err := url.Parse(uploadsURL)
+	if err != nil {
+		return nil, err
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload location: %v", err)
+	}
+	// Location can be relative to the registry host
+	return base.ResolveReference(location), nil
+}
+
+// imageSource is where images are copied from, a registry or an OCI layout
+type imageSource interface {
+	GetManifest(ctx context.Context, ref Reference) (content []byte, mediaType, digest string, err error)
+	GetBlob(ctx context.Context, ref Reference, digest string) (io.ReadCloser, error)
+}
+
+// imageDestination is where images are copied to, a registry or an OCI layout
+type imageDestination interface {
+	ManifestDigest(ctx context.Context, ref Reference) (digest string, exists bool, err error)
+	PutManifest(ctx context.Context, ref Reference, mediaType string, content []byte) error
+	BlobExists(ctx context.Context, ref Reference, digest string) (bool, error)
+	PushBlob(ctx context.Context, ref Reference, desc Descriptor, open func() (io.ReadCloser, error)) error
+}
+
+// Copy copies an image and everything it references between repositories, for every platform.
+// Manifests are copied byte by byte so digests are preserved. It returns false without copying
+// anything if the destination already has the same manifest.
+func (c *Client) Copy(ctx context.Context, src, dst Reference) (copied bool, err error) {
+	return copyImage(ctx, c, c, src, dst)
+}
+
+func copyImage(ctx context.Context, from imageSource, to imageDestination, src, dst Reference) (copied bool, err error) {
+	content, mediaType, digest, err := from.GetManifest(ctx, src)
+	if err != nil {
+		return false, err
+	}
+
+	existingDigest, exists, err := to.ManifestDigest(ctx, dst)
+	if err != nil {
+		return false, err
+	}
+	if exists && existingDigest == digest {
+		return false, nil
+	}
+
+	if err = copyManifest(ctx, from, to, src, dst, content, mediaType); err != nil {
+		return false, fmt.Errorf("error copying %s to %s: %v", src, dst, err)
+	}
+	return true, nil
+}
+
+func copyManifest(ctx context.Context, from imageSource, to imageDestination, src, dst Reference, content []byte, mediaType string) error {
+	m, err := parseManifest(content)
+	if err != nil {
+		return err
+	}
+
+	if m.IsIndex() {
+		for _, child := range m.Manifests {
+			if err = copyChildManifest(ctx, from, to, src, dst, child); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, blob := range m.Blobs() {
+			if err = copyBlob(ctx, from, to, src, dst, blob); err != nil {
+				return err
+			}
+		}
+	}
+
+	return to.PutManifest(ctx, dst, mediaType, content)
+}
+
+func copyChildManifest(ctx context.Context, from imageSource, to imageDestination, src, dst Reference, desc Descriptor) error {
+	childSrc := src
+	childSrc.Tag, childSrc.Digest = "", desc.Digest
+	childDst := dst
+	childDst.Tag, childDst.Digest = "", desc.Digest
+
+	_, exists, err := to.ManifestDigest(ctx, childDst)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	content, mediaType, _, err := from.GetManifest(ctx, childSrc)
+	if err != nil {
+		return err
+	}
+	if desc.MediaType != "" {
+		mediaType = desc.MediaType
+	}
+	return copyManifest(ctx, from, to, childSrc, childDst, content, mediaType)
+}
+
+func copyBlob(ctx context.Context, from imageSource, to imageDestination, src, dst Reference, desc Descriptor) error {
+	exists, err := to.BlobExists(ctx, dst, desc.Digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	return to.PushBlob(ctx, dst, desc, func() (io.ReadCloser, error) {
+		return from.GetBlob(ctx, src, desc.Digest)
+	})
+}
+
+func parseManifest(content []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, err
+	}
+	if m.MediaType == "" {
+		if len(m.Manifests) > 0 {
+			m.MediaType = MediaTypeOCIIndex
+		} else {
+			m.MediaType = MediaTypeOCIManifest
+		}
+	}
+	return m, nil
+}
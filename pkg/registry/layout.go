@@ -0,0 +1,280 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	layoutFileName     = "oci-layout"
+	layoutIndexName    = "index.json"
+	layoutBlobsDir     = "blobs"
+	layoutVersion      = "1.0.0"
+	refNameAnnotation  = "org.opencontainers.image.ref.name"
+	indexSchemaVersion = 2
+)
+
+// Layout is an OCI image layout on disk. Images are stored with their original reference
+// so they can be pushed back to a registry keeping their repository and tag.
+type Layout struct {
+	dir string
+
+	lock  sync.Mutex
+	index layoutIndex
+}
+
+type layoutIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// NewLayout opens the OCI layout in dir, creating it if it doesn't exist
+func NewLayout(dir string) (*Layout, error) {
+	l := &Layout{
+		dir: dir,
+		index: layoutIndex{
+			SchemaVersion: indexSchemaVersion,
+			MediaType:     MediaTypeOCIIndex,
+			Manifests:     []Descriptor{},
+		},
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, layoutIndexName))
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(content, &l.index); err != nil {
+			return nil, fmt.Errorf("error parsing OCI layout index in %s: %v", dir, err)
+		}
+		return l, nil
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("error reading OCI layout index in %s: %v", dir, err)
+	}
+
+	if err = os.MkdirAll(filepath.Join(dir, layoutBlobsDir, "sha256"), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating OCI layout in %s: %v", dir, err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, layoutFileName), []byte(fmt.Sprintf(`{"imageLayoutVersion":"%s"}`, layoutVersion)), 0o644); err != nil {
+		return nil, fmt.Errorf("error creating OCI layout in %s: %v", dir, err)
+	}
+	if err = l.writeIndex(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Images returns the references of all the images stored in the layout
+func (l *Layout) Images() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	images := make([]string, 0, len(l.index.Manifests))
+	for _, m := range l.index.Manifests {
+		images = append(images, m.Annotations[refNameAnnotation])
+	}
+	sort.Strings(images)
+	return images
+}
+
+// Save pulls the images from their registries into the layout
+func (l *Layout) Save(ctx context.Context, client *Client, images []string, concurrency int) (*MirrorReport, error) {
+	return copyImages(ctx, images, concurrency, func(ctx context.Context, image string) (bool, error) {
+		ref, err := ParseReference(image)
+		if err != nil {
+			return false, err
+		}
+		return copyImage(ctx, client, l, ref, ref)
+	})
+}
+
+// Push copies all the images in the layout to the registry endpoint, keeping their repository and tag
+func (l *Layout) Push(ctx context.Context, client *Client, endpoint string, concurrency int) (*MirrorReport, error) {
+	return copyImages(ctx, l.Images(), concurrency, func(ctx context.Context, image string) (bool, error) {
+		ref, err := ParseReference(image)
+		if err != nil {
+			return false, err
+		}
+		return copyImage(ctx, l, client, ref, ref.InRegistry(endpoint))
+	})
+}
+
+func (l *Layout) GetManifest(_ context.Context, ref Reference) (content []byte, mediaType, digest string, err error) {
+	digest = ref.Digest
+	if digest == "" {
+		desc, ok := l.lookup(ref)
+		if !ok {
+			return nil, "", "", fmt.Errorf("image %s not found in OCI layout %s", ref, l.dir)
+		}
+		digest, mediaType = desc.Digest, desc.MediaType
+	}
+
+	if !validDigest(digest) {
+		return nil, "", "", fmt.Errorf("invalid digest %s", digest)
+	}
+	content, err = ioutil.ReadFile(l.blobPath(digest))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("error reading manifest for %s from OCI layout: %v", ref, err)
+	}
+	if Digest(content) != digest {
+		return nil, "", "", fmt.Errorf("manifest %s in OCI layout is corrupted", digest)
+	}
+	if mediaType == "" {
+		m, err := parseManifest(content)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("error parsing manifest for %s: %v", ref, err)
+		}
+		mediaType = m.MediaType
+	}
+	return content, mediaType, digest, nil
+}
+
+func (l *Layout) GetBlob(_ context.Context, _ Reference, digest string) (io.ReadCloser, error) {
+	if !validDigest(digest) {
+		return nil, fmt.Errorf("invalid digest %s", digest)
+	}
+	f, err := os.Open(l.blobPath(digest))
+	if err != nil {
+		return nil, fmt.Errorf("error reading blob %s from OCI layout: %v", digest, err)
+	}
+	return f, nil
+}
+
+func (l *Layout) ManifestDigest(ctx context.Context, ref Reference) (digest string, exists bool, err error) {
+	if ref.Digest == "" {
+		desc, ok := l.lookup(ref)
+		return desc.Digest, ok, nil
+	}
+	exists, err = l.BlobExists(ctx, ref, ref.Digest)
+	return ref.Digest, exists, err
+}
+
+func (l *Layout) PutManifest(_ context.Context, ref Reference, mediaType string, content []byte) error {
+	digest := Digest(content)
+	if err := l.writeBlob(digest, bytes.NewReader(content)); err != nil {
+		return err
+	}
+	if ref.Tag == "" {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	desc := Descriptor{
+		MediaType:   mediaType,
+		Digest:      digest,
+		Size:        int64(len(content)),
+		Annotations: map[string]string{refNameAnnotation: ref.String()},
+	}
+	replaced := false
+	for i, m := range l.index.Manifests {
+		if m.Annotations[refNameAnnotation] == ref.String() {
+			l.index.Manifests[i] = desc
+			replaced = true
+		}
+	}
+	if !replaced {
+		l.index.Manifests = append(l.index.Manifests, desc)
+	}
+	return l.writeIndex()
+}
+
+func (l *Layout) BlobExists(_ context.Context, _ Reference, digest string) (bool, error) {
+	if !validDigest(digest) {
+		return false, fmt.Errorf("invalid digest %s", digest)
+	}
+	_, err := os.Stat(l.blobPath(digest))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (l *Layout) PushBlob(_ context.Context, _ Reference, desc Descriptor, open func() (io.ReadCloser, error)) error {
+	r, err := open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return l.writeBlob(desc.Digest, r)
+}
+
+func (l *Layout) lookup(ref Reference) (Descriptor, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	name := ref.String()
+	for _, m := range l.index.Manifests {
+		if m.Annotations[refNameAnnotation] == name {
+			return m, true
+		}
+	}
+	return Descriptor{}, false
+}
+
+// writeBlob stores the content in a temp file first and only moves it to its final path once
+// the digest has been verified, so the layout never has partial or corrupted blobs
+func (l *Layout) writeBlob(digest string, r io.Reader) error {
+	if !validDigest(digest) {
+		return fmt.Errorf("invalid digest %s", digest)
+	}
+	path := l.blobPath(digest)
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".blob-")
+	if err != nil {
+		return fmt.Errorf("error writing blob %s to OCI layout: %v", digest, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tmp, hash), r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing blob %s to OCI layout: %v", digest, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing blob %s to OCI layout: %v", digest, err)
+	}
+	if got := fmt.Sprintf("sha256:%x", hash.Sum(nil)); got != digest {
+		return fmt.Errorf("blob digest mismatch, expected %s but got %s", digest, got)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Layout) writeIndex() error {
+	content, err := json.Marshal(l.index)
+	if err != nil {
+		return fmt.Errorf("error writing OCI layout index: %v", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(l.dir, layoutIndexName), content, 0o644); err != nil {
+		return fmt.Errorf("error writing OCI layout index: %v", err)
+	}
+	return nil
+}
+
+func (l *Layout) blobPath(digest string) string {
+	return filepath.Join(l.dir, layoutBlobsDir, strings.Replace(digest, ":", string(filepath.Separator), 1))
+}
+
+// validDigest only accepts sha256 digests, which are the only ones stored in the layout,
+// and makes sure a digest can be safely used as a file name
+func validDigest(digest string) bool {
+	hex := strings.TrimPrefix(digest, "sha256:")
+	if len(hex) != sha256.Size*2 || hex == digest {
+		return false
+	}
+	for _, c := range hex {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package registry_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+func TestLayoutSaveAndPush(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t, test.WithRegistryTokenAuth("reader", "reader-password"))
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("admin", "admin-password"))
+	indexDigest := addMultiArchImage(g, source, "v0.1.0")
+	image := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+	client := newClient(g, source, mirror)
+	dir := t.TempDir()
+
+	layout, err := registry.NewLayout(dir)
+	g.Expect(err).NotTo(HaveOccurred())
+	report, err := layout.Save(ctx, client, []string{image}, 2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Copied).To(ConsistOf(image))
+
+	report, err = layout.Save(ctx, client, []string{image}, 2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Skipped).To(ConsistOf(image))
+
+	reopened, err := registry.NewLayout(dir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reopened.Images()).To(ConsistOf(image))
+
+	report, err = reopened.Push(ctx, client, mirror.Host()+"/imported", 2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Copied).To(ConsistOf(image))
+
+	got, ok := mirror.Manifest("imported/"+imageRepository, "v0.1.0")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(registry.Digest(got)).To(Equal(indexDigest))
+	g.Expect(mirror.BlobUploads()).To(Equal(4))
+}
+
+func TestLayoutPushMissingBlob(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	mirror := test.NewRegistry(t)
+	client := newClient(g, mirror, mirror)
+
+	layout, err := registry.NewLayout(t.TempDir())
+	g.Expect(err).NotTo(HaveOccurred())
+	ref, err := registry.ParseReference("public.ecr.aws/eks-anywhere/image:v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	manifest := []byte(`{"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"digest":"sha256:0000000000000000000000000000000000000000000000000000000000000000","size":2}}`)
+	g.Expect(layout.PutManifest(ctx, ref, registry.MediaTypeOCIManifest, manifest)).To(Succeed())
+
+	_, err = layout.Push(ctx, client, mirror.Host(), 1)
+	g.Expect(err).To(MatchError(ContainSubstring("error reading blob")))
+}
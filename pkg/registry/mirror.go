@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/eks-anywhere/pkg/logger"
+)
+
+const defaultMirrorConcurrency = 4
+
+// Mirror copies images to a registry mirror endpoint
+type Mirror struct {
+	client      *Client
+	endpoint    string
+	concurrency int
+	verifier    *SignatureVerifier
+}
+
+type MirrorOpt func(*Mirror)
+
+// WithConcurrency sets how many images are copied in parallel
+func WithConcurrency(concurrency int) MirrorOpt {
+	return func(m *Mirror) {
+		if concurrency > 0 {
+			m.concurrency = concurrency
+		}
+	}
+}
+
+// WithSignatureVerifier verifies the signature of every image before it's copied
+func WithSignatureVerifier(verifier *SignatureVerifier) MirrorOpt {
+	return func(m *Mirror) {
+		m.verifier = verifier
+	}
+}
+
+func NewMirror(client *Client, endpoint string, opts ...MirrorOpt) *Mirror {
+	m := &Mirror{
+		client:      client,
+		endpoint:    endpoint,
+		concurrency: defaultMirrorConcurrency,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// MirrorReport summarizes the result of mirroring a list of images
+type MirrorReport struct {
+	Copied  []string
+	Skipped []string
+	Failed  map[string]error
+}
+
+// MirrorImages copies all the images to the mirror endpoint, keeping their repository and tag.
+// Images already present in the mirror with the same digest are skipped. It keeps going when an
+// image fails and returns an error listing all the failures.
+func (m *Mirror) MirrorImages(ctx context.Context, images []string) (*MirrorReport, error) {
+	return copyImages(ctx, images, m.concurrency, m.mirrorImage)
+}
+
+// copyImages runs copyFn for every image with bounded parallelism, logging progress
+func copyImages(ctx context.Context, images []string, concurrency int, copyFn func(ctx context.Context, image string) (bool, error)) (*MirrorReport, error) {
+	if concurrency <= 0 {
+		concurrency = defaultMirrorConcurrency
+	}
+	images = dedupImages(images)
+	report := &MirrorReport{Failed: map[string]error{}}
+	total := len(images)
+
+	var lock sync.Mutex
+	done := 0
+	record := func(image string, copied bool, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		done++
+		progress := fmt.Sprintf("%d/%d", done, total)
+		switch {
+		case err != nil:
+			report.Failed[image] = err
+			logger.Info("Failed to copy image", "image", image, "progress", progress, "error", err.Error())
+		case copied:
+			report.Copied = append(report.Copied, image)
+			logger.Info("Copied image", "image", image, "progress", progress)
+		default:
+			report.Skipped = append(report.Skipped, image)
+			logger.Info("Image already present, skipping", "image", image, "progress", progress)
+		}
+	}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for image := range queue {
+				if err := ctx.Err(); err != nil {
+					record(image, false, err)
+					continue
+				}
+				copied, err := copyFn(ctx, image)
+				record(image, copied, err)
+			}
+		}()
+	}
+
+	for _, image := range images {
+		queue <- image
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(report.Failed) > 0 {
+		failures := make([]string, 0, len(report.Failed))
+		for _, image := range images {
+			if err, ok := report.Failed[image]; ok {
+				failures = append(failures, fmt.Sprintf("%s: %v", image, err))
+			}
+		}
+		return report, fmt.Errorf("failed to copy %d of %d images: %s", len(failures), total, strings.Join(failures, "; "))
+	}
+
+	return report, nil
+}
+
+func (m *Mirror) mirrorImage(ctx context.Context, image string) (bool, error) {
+	src, err := ParseReference(image)
+	if err != nil {
+		return false, err
+	}
+	if m.verifier != nil {
+		if err = m.verifier.Verify(ctx, m.client, src); err != nil {
+			return false, err
+		}
+	}
+	return m.client.Copy(ctx, src, src.InRegistry(m.endpoint))
+}
+
+func dedupImages(images []string) []string {
+	seen := make(map[string]struct{}, len(images))
+	unique := make([]string, 0, len(images))
+	for _, image := range images {
+		if _, ok := seen[image]; ok {
+			continue
+		}
+		seen[image] = struct{}{}
+		unique = append(unique, image)
+	}
+	return unique
+}
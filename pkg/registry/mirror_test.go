@@ -0,0 +1,131 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+const imageRepository = "eks-anywhere/cluster-controller"
+
+// addMultiArchImage stores an index with an image manifest per platform and returns the index digest
+func addMultiArchImage(g *WithT, r *test.Registry, tag string) string {
+	index := registry.Manifest{MediaType: registry.MediaTypeOCIIndex}
+	for _, arch := range []string{"amd64", "arm64"} {
+		config := []byte(fmt.Sprintf(`{"architecture":"%s","os":"linux"}`, arch))
+		layer := []byte("layer for " + arch)
+		manifest := registry.Manifest{
+			MediaType: registry.MediaTypeOCIManifest,
+			Config:    &registry.Descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: r.AddBlob(config), Size: int64(len(config))},
+			Layers: []registry.Descriptor{
+				{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: r.AddBlob(layer), Size: int64(len(layer))},
+			},
+		}
+		content, err := json.Marshal(manifest)
+		g.Expect(err).NotTo(HaveOccurred())
+		digest := r.AddManifest(imageRepository, "", registry.MediaTypeOCIManifest, content)
+		index.Manifests = append(index.Manifests, registry.Descriptor{MediaType: registry.MediaTypeOCIManifest, Digest: digest, Size: int64(len(content))})
+	}
+
+	content, err := json.Marshal(index)
+	g.Expect(err).NotTo(HaveOccurred())
+	return r.AddManifest(imageRepository, tag, registry.MediaTypeOCIIndex, content)
+}
+
+func newClient(g *WithT, source, mirror *test.Registry) *registry.Client {
+	c, err := registry.NewClient(
+		registry.WithCACert(source.CACert()),
+		registry.WithCredentials(source.Host(), registry.Credentials{Username: "reader", Password: "reader-password"}),
+		registry.WithCredentials(mirror.Host(), registry.Credentials{Username: "admin", Password: "admin-password"}),
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+	return c
+}
+
+func TestMirrorImagesCopiesAllPlatforms(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t, test.WithRegistryTokenAuth("reader", "reader-password"))
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("admin", "admin-password"))
+	indexDigest := addMultiArchImage(g, source, "v0.1.0")
+	image := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+
+	m := registry.NewMirror(newClient(g, source, mirror), mirror.Host(), registry.WithConcurrency(2))
+	report, err := m.MirrorImages(ctx, []string{image, image})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Copied).To(ConsistOf(image))
+
+	got, ok := mirror.Manifest(imageRepository, "v0.1.0")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(registry.Digest(got)).To(Equal(indexDigest))
+	g.Expect(mirror.BlobUploads()).To(Equal(4))
+
+	index := &registry.Manifest{}
+	g.Expect(json.Unmarshal(got, index)).To(Succeed())
+	for _, platform := range index.Manifests {
+		_, ok := mirror.Manifest(imageRepository, platform.Digest)
+		g.Expect(ok).To(BeTrue(), "platform manifest %s should be mirrored", platform.Digest)
+	}
+}
+
+func TestMirrorImagesSkipsExistingImages(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t)
+	mirror := test.NewRegistry(t)
+	addMultiArchImage(g, source, "v0.1.0")
+	image := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+	m := registry.NewMirror(newClient(g, source, mirror), mirror.Host())
+
+	_, err := m.MirrorImages(ctx, []string{image})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	report, err := m.MirrorImages(ctx, []string{image})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Skipped).To(ConsistOf(image))
+	g.Expect(report.Copied).To(BeEmpty())
+	g.Expect(mirror.ManifestPushes(imageRepository, "v0.1.0")).To(Equal(1))
+	g.Expect(mirror.BlobUploads()).To(Equal(4))
+}
+
+func TestMirrorImagesReportsFailures(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t)
+	mirror := test.NewRegistry(t)
+	addMultiArchImage(g, source, "v0.1.0")
+	image := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+	missingImage := fmt.Sprintf("%s/%s:missing", source.Host(), imageRepository)
+	m := registry.NewMirror(newClient(g, source, mirror), mirror.Host())
+
+	report, err := m.MirrorImages(ctx, []string{missingImage, image})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to copy 1 of 2 images")))
+	g.Expect(err).To(MatchError(ContainSubstring(missingImage)))
+	g.Expect(report.Copied).To(ConsistOf(image))
+	g.Expect(report.Failed).To(HaveKey(missingImage))
+}
+
+func TestMirrorImagesWrongCredentials(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t)
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("admin", "other-password"))
+	addMultiArchImage(g, source, "v0.1.0")
+	image := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+	m := registry.NewMirror(newClient(g, source, mirror), mirror.Host())
+
+	_, err := m.MirrorImages(ctx, []string{image})
+	g.Expect(err).To(MatchError(ContainSubstring("unauthorized to pull")))
+}
+
+func TestNewClientInvalidCACert(t *testing.T) {
+	g := NewWithT(t)
+	_, err := registry.NewClient(registry.WithCACert([]byte("not a cert")))
+	g.Expect(err).To(MatchError(ContainSubstring("invalid CA certificate")))
+}
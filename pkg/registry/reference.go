@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry    = "docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+	defaultTag           = "latest"
+)
+
+// Reference points to an image in a registry, either by tag or by digest
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses image references in the docker format, like public.ecr.aws/eks-anywhere/image:v1.0.0
+// or registry:5000/image@sha256:abc. Images without a registry default to docker hub.
+func ParseReference(image string) (Reference, error) {
+	if image == "" {
+		return Reference{}, fmt.Errorf("invalid image reference: empty reference")
+	}
+
+	ref := Reference{}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+		if !strings.Contains(ref.Digest, ":") {
+			return Reference{}, fmt.Errorf("invalid image reference %s: invalid digest %s", image, ref.Digest)
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i+1:], "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && isRegistryHost(parts[0]) {
+		ref.Registry = parts[0]
+		ref.Repository = parts[1]
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = name
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+
+	if ref.Repository == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %s: empty repository", image)
+	}
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	return ref, nil
+}
+
+func isRegistryHost(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+// Identifier returns the digest if the reference has one, the tag otherwise
+func (r Reference) Identifier() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// InRegistry returns the same reference in a different registry. The endpoint can include a path
+// prefix, like mirror.example.com/eks-anywhere, which is prepended to the repository
+func (r Reference) InRegistry(endpoint string) Reference {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	parts := strings.SplitN(endpoint, "/", 2)
+	r.Registry = parts[0]
+	if len(parts) == 2 && parts[1] != "" {
+		r.Repository = parts[1] + "/" + r.Repository
+	}
+	return r
+}
+
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+func (r Reference) apiHost() string {
+	if r.Registry == dockerHubRegistry {
+		return dockerHubAPIRegistry
+	}
+	return r.Registry
+}
@@ -0,0 +1,77 @@
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image   string
+		want    registry.Reference
+		wantErr bool
+	}{
+		{
+			image: "public.ecr.aws/eks-anywhere/cluster-controller:v0.1.0",
+			want:  registry.Reference{Registry: "public.ecr.aws", Repository: "eks-anywhere/cluster-controller", Tag: "v0.1.0"},
+		},
+		{
+			image: "localhost:5000/image@sha256:abc",
+			want:  registry.Reference{Registry: "localhost:5000", Repository: "image", Digest: "sha256:abc"},
+		},
+		{
+			image: "registry.example.com:5000/org/image:v1@sha256:abc",
+			want:  registry.Reference{Registry: "registry.example.com:5000", Repository: "org/image", Tag: "v1", Digest: "sha256:abc"},
+		},
+		{
+			image: "nginx",
+			want:  registry.Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
+		},
+		{
+			image: "bitnami/nginx:1.21",
+			want:  registry.Reference{Registry: "docker.io", Repository: "bitnami/nginx", Tag: "1.21"},
+		},
+		{
+			image:   "",
+			wantErr: true,
+		},
+		{
+			image:   "public.ecr.aws/image@abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := registry.ParseReference(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferenceInRegistry(t *testing.T) {
+	ref, err := registry.ParseReference("public.ecr.aws/eks-anywhere/cluster-controller:v0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "mirror.example.com:443", want: "mirror.example.com:443/eks-anywhere/cluster-controller:v0.1.0"},
+		{endpoint: "mirror.example.com/prefix/", want: "mirror.example.com/prefix/eks-anywhere/cluster-controller:v0.1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if got := ref.InRegistry(tt.endpoint).String(); got != tt.want {
+				t.Fatalf("InRegistry() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
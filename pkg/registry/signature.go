@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix  = ".sig"
+	// maxSignaturePayloadSize protects from registries serving huge blobs as signature payloads
+	maxSignaturePayloadSize = 1 << 20
+)
+
+// SignatureVerifier verifies images are signed with cosign by any of a set of trusted public keys
+type SignatureVerifier struct {
+	keys []crypto.PublicKey
+}
+
+// NewSignatureVerifier builds a verifier that trusts the PEM encoded ECDSA, RSA or ed25519 public keys
+func NewSignatureVerifier(publicKeys ...[]byte) (*SignatureVerifier, error) {
+	v := &SignatureVerifier{}
+	for _, publicKey := range publicKeys {
+		key, err := ParsePublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, key)
+	}
+	if len(v.keys) == 0 {
+		return nil, fmt.Errorf("at least one public key is required to verify image signatures")
+	}
+	return v, nil
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA, RSA or ed25519 public key, the formats cosign signs with
+func ParsePublicKey(publicKey []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key: not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("invalid public key: unsupported key type %T", key)
+	}
+}
+
+// simpleSigningPayload is the cosign signature payload, in the simple signing format
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Verify checks the image has a cosign signature for its manifest digest from any of the trusted keys.
+// The signatures are read from the same repository, following the cosign sha256-<digest>.sig tag convention.
+func (v *SignatureVerifier) Verify(ctx context.Context, client *Client, ref Reference) error {
+	_, _, digest, err := client.GetManifest(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("error verifying signature for image %s: %v", ref, err)
+	}
+
+	signatures := Reference{
+		Registry:   ref.Registry,
+		Repository: ref.Repository,
+		Tag:        strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix,
+	}
+	content, _, _, err := client.GetManifest(ctx, signatures)
+	if err != nil {
+		return fmt.Errorf("image %s is not signed: %v", ref, err)
+	}
+	manifest, err := parseManifest(content)
+	if err != nil {
+		return fmt.Errorf("invalid signatures for image %s: %v", ref, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := readSignaturePayload(ctx, client, signatures, layer)
+		if err != nil {
+			return fmt.Errorf("invalid signatures for image %s: %v", ref, err)
+		}
+		if v.verifySignature(digest, payload, signature) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("image %s doesn't have a valid signature from the trusted keys", ref)
+}
+
+func readSignaturePayload(ctx context.Context, client *Client, ref Reference, layer Descriptor) ([]byte, error) {
+	if layer.Size > maxSignaturePayloadSize {
+		return nil, fmt.Errorf("signature payload %s is too big", layer.Digest)
+	}
+	blob, err := client.GetBlob(ctx, ref, layer.Digest)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	payload, err := ioutil.ReadAll(io.LimitReader(blob, maxSignaturePayloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading signature payload %s: %v", layer.Digest, err)
+	}
+	if Digest(payload) != layer.Digest {
+		return nil, fmt.Errorf("signature payload has digest %s, expected %s", Digest(payload), layer.Digest)
+	}
+	return payload, nil
+}
+
+// verifySignature checks the payload signs the image digest and the signature is valid for any of the keys
+func (v *SignatureVerifier) verifySignature(digest string, payload []byte, signature string) bool {
+	p := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, p); err != nil || p.Critical.Image.DockerManifestDigest != digest {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	hash := sha256.Sum256(payload)
+	for _, key := range v.keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hash[:], sig) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, sig) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package registry_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+func publicKeyPEM(g *WithT, key crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// addSignature signs the image digest with key and stores the signature following the cosign conventions
+func addSignature(g *WithT, r *test.Registry, digest string, signer crypto.Signer) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, imageRepository, digest))
+	var signature []byte
+	var err error
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		signature, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		hash := sha256.Sum256(payload)
+		signature, err = signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := []byte(`{"architecture":"","os":""}`)
+	manifest, err := json.Marshal(registry.Manifest{
+		MediaType: registry.MediaTypeOCIManifest,
+		Config:    &registry.Descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: r.AddBlob(config), Size: int64(len(config))},
+		Layers: []registry.Descriptor{{
+			MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+			Digest:      r.AddBlob(payload),
+			Size:        int64(len(payload)),
+			Annotations: map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(signature)},
+		}},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	r.AddManifest(imageRepository, strings.Replace(digest, ":", "-", 1)+".sig", registry.MediaTypeOCIManifest, manifest)
+}
+
+func newECDSAKey(g *WithT) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	return key
+}
+
+func TestSignatureVerifierVerify(t *testing.T) {
+	g := NewWithT(t)
+	ecdsaKey := newECDSAKey(g)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		name   string
+		signer crypto.Signer
+	}{
+		{name: "ecdsa", signer: ecdsaKey},
+		{name: "ed25519", signer: ed25519Key},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := test.NewRegistry(t)
+			addSignature(g, r, addMultiArchImage(g, r, "v0.1.0"), tt.signer)
+			client, err := registry.NewClient(registry.WithCACert(r.CACert()))
+			g.Expect(err).NotTo(HaveOccurred())
+			ref, err := registry.ParseReference(fmt.Sprintf("%s/%s:v0.1.0", r.Host(), imageRepository))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			otherKey := newECDSAKey(g)
+			verifier, err := registry.NewSignatureVerifier(publicKeyPEM(g, otherKey.Public()), publicKeyPEM(g, tt.signer.Public()))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(verifier.Verify(context.Background(), client, ref)).To(Succeed())
+		})
+	}
+}
+
+func TestSignatureVerifierVerifyErrors(t *testing.T) {
+	trustedKey := newECDSAKey(NewWithT(t))
+	tests := []struct {
+		name    string
+		sign    func(g *WithT, r *test.Registry, digest string)
+		wantErr string
+	}{
+		{
+			name:    "unsigned image",
+			sign:    func(g *WithT, r *test.Registry, digest string) {},
+			wantErr: "is not signed",
+		},
+		{
+			name: "signed with an untrusted key",
+			sign: func(g *WithT, r *test.Registry, digest string) {
+				addSignature(g, r, digest, newECDSAKey(g))
+			},
+			wantErr: "doesn't have a valid signature from the trusted keys",
+		},
+		{
+			name: "signature for another image",
+			sign: func(g *WithT, r *test.Registry, digest string) {
+				otherDigest := "sha256:" + strings.Repeat("0", 64)
+				addSignature(g, r, otherDigest, trustedKey)
+				manifest, ok := r.Manifest(imageRepository, "sha256-"+strings.Repeat("0", 64)+".sig")
+				g.Expect(ok).To(BeTrue())
+				r.AddManifest(imageRepository, strings.Replace(digest, ":", "-", 1)+".sig", registry.MediaTypeOCIManifest, manifest)
+			},
+			wantErr: "doesn't have a valid signature from the trusted keys",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := test.NewRegistry(t)
+			tt.sign(g, r, addMultiArchImage(g, r, "v0.1.0"))
+			client, err := registry.NewClient(registry.WithCACert(r.CACert()))
+			g.Expect(err).NotTo(HaveOccurred())
+			ref, err := registry.ParseReference(fmt.Sprintf("%s/%s:v0.1.0", r.Host(), imageRepository))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			verifier, err := registry.NewSignatureVerifier(publicKeyPEM(g, trustedKey.Public()))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(verifier.Verify(context.Background(), client, ref)).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestNewSignatureVerifierErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := registry.NewSignatureVerifier()
+	g.Expect(err).To(MatchError(ContainSubstring("at least one public key is required")))
+
+	_, err = registry.NewSignatureVerifier([]byte("not a key"))
+	g.Expect(err).To(MatchError(ContainSubstring("invalid public key: not PEM encoded")))
+}
+
+func TestMirrorImagesVerifiesSignatures(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	source := test.NewRegistry(t, test.WithRegistryTokenAuth("reader", "reader-password"))
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("admin", "admin-password"))
+	key := newECDSAKey(g)
+	addSignature(g, source, addMultiArchImage(g, source, "v0.1.0"), key)
+	config := []byte(`{"architecture":"amd64","os":"linux","unsigned":true}`)
+	manifest, err := json.Marshal(registry.Manifest{
+		MediaType: registry.MediaTypeOCIManifest,
+		Config:    &registry.Descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: source.AddBlob(config), Size: int64(len(config))},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	source.AddManifest(imageRepository, "v0.2.0", registry.MediaTypeOCIManifest, manifest)
+	signed := fmt.Sprintf("%s/%s:v0.1.0", source.Host(), imageRepository)
+	unsigned := fmt.Sprintf("%s/%s:v0.2.0", source.Host(), imageRepository)
+
+	verifier, err := registry.NewSignatureVerifier(publicKeyPEM(g, key.Public()))
+	g.Expect(err).NotTo(HaveOccurred())
+	m := registry.NewMirror(newClient(g, source, mirror), mirror.Host(), registry.WithSignatureVerifier(verifier))
+	report, err := m.MirrorImages(ctx, []string{signed, unsigned})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to copy 1 of 2 images")))
+	g.Expect(report.Copied).To(ConsistOf(signed))
+	g.Expect(report.Failed).To(HaveKey(unsigned))
+
+	_, ok := mirror.Manifest(imageRepository, "v0.2.0")
+	g.Expect(ok).To(BeFalse(), "unsigned images are not copied")
+}
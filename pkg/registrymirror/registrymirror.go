@@ -0,0 +1,178 @@
+package registrymirror
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	// DefaultRegistry is the registry mirrored when the configuration doesn't list explicit mirrors
+	DefaultRegistry = "public.ecr.aws"
+
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// Mirror is an upstream registry and the endpoint it's mirrored to
+type Mirror struct {
+	Registry string
+	Endpoint string
+}
+
+// Credentials to authenticate with the mirror endpoints
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Configuration is the registry mirror configuration every container runtime in a cluster is configured with,
+// both in the bootstrap cluster and in the cluster nodes
+type Configuration struct {
+	Mirrors            []Mirror
+	CACertContent      string
+	InsecureSkipVerify bool
+	Credentials        *Credentials
+}
+
+// New computes the registry mirror configuration for a cluster, defaulting the mirror endpoints and using the
+// inline credentials, if any. It returns nil if the cluster doesn't use a registry mirror.
+func New(clusterConfig *v1alpha1.Cluster) *Configuration {
+	mirrorConfig := clusterConfig.Spec.RegistryMirrorConfiguration
+	if mirrorConfig == nil {
+		return nil
+	}
+
+	c := &Configuration{
+		CACertContent:      mirrorConfig.CACertContent,
+		InsecureSkipVerify: mirrorConfig.InsecureSkipVerify,
+	}
+	if len(mirrorConfig.Mirrors) == 0 {
+		c.Mirrors = []Mirror{{Registry: DefaultRegistry, Endpoint: mirrorConfig.Endpoint}}
+	}
+	for _, m := range mirrorConfig.Mirrors {
+		endpoint := m.Endpoint
+		if endpoint == "" {
+			endpoint = mirrorConfig.Endpoint
+		}
+		c.Mirrors = append(c.Mirrors, Mirror{Registry: m.Registry, Endpoint: endpoint})
+	}
+	if auth := mirrorConfig.Authentication; auth != nil && auth.Username != "" {
+		c.Credentials = &Credentials{Username: auth.Username, Password: auth.Password}
+	}
+
+	return c
+}
+
+// WithCredentials returns a copy of the configuration that authenticates with credentials.
+// It returns the same configuration if credentials is nil.
+func (c *Configuration) WithCredentials(credentials *Credentials) *Configuration {
+	if c == nil || credentials == nil {
+		return c
+	}
+	withCredentials := *c
+	withCredentials.Credentials = credentials
+	return &withCredentials
+}
+
+// Endpoints returns the unique mirror endpoints
+func (c *Configuration) Endpoints() []string {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(c.Mirrors))
+	endpoints := make([]string, 0, len(c.Mirrors))
+	for _, m := range c.Mirrors {
+		if _, ok := seen[m.Endpoint]; ok {
+			continue
+		}
+		seen[m.Endpoint] = struct{}{}
+		endpoints = append(endpoints, m.Endpoint)
+	}
+	return endpoints
+}
+
+// CACertPath is where the CA certificate for an endpoint is written in the nodes,
+// following the containerd certs.d layout
+func CACertPath(endpoint string) string {
+	return fmt.Sprintf("/etc/containerd/certs.d/%s/ca.crt", endpoint)
+}
+
+// ContainerdConfig renders the containerd CRI registry configuration: the mirror endpoints for every upstream
+// registry and the TLS and auth settings for every endpoint. CA certificates are expected at CACertPath.
+func (c *Configuration) ContainerdConfig() string {
+	if c == nil {
+		return ""
+	}
+
+	const plugin = `plugins."io.containerd.grpc.v1.cri".registry`
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s.mirrors]\n", plugin)
+	for _, m := range c.Mirrors {
+		fmt.Fprintf(&b, "  [%s.mirrors.%q]\n", plugin, m.Registry)
+		fmt.Fprintf(&b, "    endpoint = [%q]\n", "https://"+m.Endpoint)
+	}
+	for _, endpoint := range c.Endpoints() {
+		switch {
+		case c.InsecureSkipVerify:
+			fmt.Fprintf(&b, "  [%s.configs.%q.tls]\n", plugin, endpoint)
+			b.WriteString("    insecure_skip_verify = true\n")
+		case c.CACertContent != "":
+			fmt.Fprintf(&b, "  [%s.configs.%q.tls]\n", plugin, endpoint)
+			fmt.Fprintf(&b, "    ca_file = %q\n", CACertPath(endpoint))
+		}
+		if c.Credentials != nil {
+			fmt.Fprintf(&b, "  [%s.configs.%q.auth]\n", plugin, endpoint)
+			fmt.Fprintf(&b, "    username = %s\n", strconv.Quote(c.Credentials.Username))
+			fmt.Fprintf(&b, "    password = %s\n", strconv.Quote(c.Credentials.Password))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+type SecretGetter interface {
+	GetSecretFromNamespace(ctx context.Context, kubeconfigFile, name, namespace string) (*corev1.Secret, error)
+}
+
+// LoadCredentials reads the credentials from the secret referenced in the cluster registry mirror authentication.
+// The secret lives in the management cluster, so it can't be used when creating a management cluster.
+// It returns nil if the cluster doesn't reference a credentials secret.
+func LoadCredentials(ctx context.Context, secrets SecretGetter, managementCluster *types.Cluster, clusterConfig *v1alpha1.Cluster) (*Credentials, error) {
+	mirrorConfig := clusterConfig.Spec.RegistryMirrorConfiguration
+	if mirrorConfig == nil || mirrorConfig.Authentication == nil || mirrorConfig.Authentication.CredentialsSecretRef == nil {
+		return nil, nil
+	}
+	if managementCluster == nil {
+		return nil, fmt.Errorf("registry mirror credentialsSecretRef requires an existing management cluster, use username and password instead")
+	}
+
+	ref := mirrorConfig.Authentication.CredentialsSecretRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = clusterConfig.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	secret, err := secrets.GetSecretFromNamespace(ctx, managementCluster.KubeconfigFile, ref.Name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error getting registry mirror credentials secret %s/%s: %v", namespace, ref.Name, err)
+	}
+	return CredentialsFromSecret(secret)
+}
+
+// CredentialsFromSecret reads the username and password keys of a secret
+func CredentialsFromSecret(secret *corev1.Secret) (*Credentials, error) {
+	username, password := string(secret.Data[usernameKey]), string(secret.Data[passwordKey])
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("registry mirror credentials secret %s/%s must have %s and %s keys", secret.Namespace, secret.Name, usernameKey, passwordKey)
+	}
+	return &Credentials{Username: username, Password: password}, nil
+}
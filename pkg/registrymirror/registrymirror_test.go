@@ -0,0 +1,227 @@
+package registrymirror_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+func givenCluster(mirrorConfig *v1alpha1.RegistryMirrorConfiguration) *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+		Spec: v1alpha1.ClusterSpec{
+			RegistryMirrorConfiguration: mirrorConfig,
+		},
+	}
+}
+
+func TestNewWithoutMirror(t *testing.T) {
+	g := NewWithT(t)
+	config := registrymirror.New(givenCluster(nil))
+	g.Expect(config).To(BeNil())
+	g.Expect(config.Endpoints()).To(BeEmpty())
+	g.Expect(config.ContainerdConfig()).To(BeEmpty())
+}
+
+func TestNewDefaultMirror(t *testing.T) {
+	g := NewWithT(t)
+	config := registrymirror.New(givenCluster(&v1alpha1.RegistryMirrorConfiguration{
+		Endpoint:      "1.2.3.4:443",
+		CACertContent: "ca",
+	}))
+
+	g.Expect(config.Mirrors).To(Equal([]registrymirror.Mirror{{Registry: "public.ecr.aws", Endpoint: "1.2.3.4:443"}}))
+	g.Expect(config.Credentials).To(BeNil())
+	g.Expect(config.ContainerdConfig()).To(Equal(`[plugins."io.containerd.grpc.v1.cri".registry.mirrors]
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."public.ecr.aws"]
+    endpoint = ["https://1.2.3.4:443"]
+  [plugins."io.containerd.grpc.v1.cri".registry.configs."1.2.3.4:443".tls]
+    ca_file = "/etc/containerd/certs.d/1.2.3.4:443/ca.crt"`))
+}
+
+func TestNewMirrorsWithAuthentication(t *testing.T) {
+	g := NewWithT(t)
+	config := registrymirror.New(givenCluster(&v1alpha1.RegistryMirrorConfiguration{
+		Endpoint: "1.2.3.4",
+		Mirrors: []v1alpha1.RegistryMirror{
+			{Registry: "public.ecr.aws"},
+			{Registry: "docker.io", Endpoint: "1.2.3.5"},
+			{Registry: "quay.io"},
+		},
+		Authentication: &v1alpha1.RegistryMirrorAuthentication{
+			Username: "user",
+			Password: `pass"word`,
+		},
+		InsecureSkipVerify: true,
+	}))
+
+	g.Expect(config.Mirrors).To(Equal([]registrymirror.Mirror{
+		{Registry: "public.ecr.aws", Endpoint: "1.2.3.4"},
+		{Registry: "docker.io", Endpoint: "1.2.3.5"},
+		{Registry: "quay.io", Endpoint: "1.2.3.4"},
+	}))
+	g.Expect(config.Endpoints()).To(Equal([]string{"1.2.3.4", "1.2.3.5"}))
+	g.Expect(config.ContainerdConfig()).To(Equal(`[plugins."io.containerd.grpc.v1.cri".registry.mirrors]
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."public.ecr.aws"]
+    endpoint = ["https://1.2.3.4"]
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."docker.io"]
+    endpoint = ["https://1.2.3.5"]
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."quay.io"]
+    endpoint = ["https://1.2.3.4"]
+  [plugins."io.containerd.grpc.v1.cri".registry.configs."1.2.3.4".tls]
+    insecure_skip_verify = true
+  [plugins."io.containerd.grpc.v1.cri".registry.configs."1.2.3.4".auth]
+    username = "user"
+    password = "pass\"word"
+  [plugins."io.containerd.grpc.v1.cri".registry.configs."1.2.3.5".tls]
+    insecure_skip_verify = true
+  [plugins."io.containerd.grpc.v1.cri".registry.configs."1.2.3.5".auth]
+    username = "user"
+    password = "pass\"word"`))
+}
+
+func TestWithCredentials(t *testing.T) {
+	g := NewWithT(t)
+	config := registrymirror.New(givenCluster(&v1alpha1.RegistryMirrorConfiguration{Endpoint: "1.2.3.4"}))
+	credentials := &registrymirror.Credentials{Username: "user", Password: "password"}
+
+	g.Expect(config.WithCredentials(nil)).To(BeIdenticalTo(config))
+	g.Expect(config.WithCredentials(credentials).Credentials).To(Equal(credentials))
+	g.Expect(config.Credentials).To(BeNil(), "the original configuration is not modified")
+}
+
+type fakeSecretGetter struct {
+	secret                *corev1.Secret
+	err                   error
+	kubeconfig, namespace string
+}
+
+func (f *fakeSecretGetter) GetSecretFromNamespace(ctx context.Context, kubeconfigFile, name, namespace string) (*corev1.Secret, error) {
+	f.kubeconfig, f.namespace = kubeconfigFile, namespace
+	return f.secret, f.err
+}
+
+func givenClusterWithSecretRef(namespace string) *v1alpha1.Cluster {
+	return givenCluster(&v1alpha1.RegistryMirrorConfiguration{
+		Endpoint: "1.2.3.4",
+		Authentication: &v1alpha1.RegistryMirrorAuthentication{
+			CredentialsSecretRef: &corev1.SecretReference{Name: "mirror-credentials", Namespace: namespace},
+		},
+	})
+}
+
+func TestLoadCredentials(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		wantNamespace string
+	}{
+		{name: "cluster namespace", wantNamespace: "test-namespace"},
+		{name: "secret namespace", namespace: "secrets", wantNamespace: "secrets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			secrets := &fakeSecretGetter{secret: &corev1.Secret{
+				Data: map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+			}}
+			managementCluster := &types.Cluster{Name: "management", KubeconfigFile: "management.kubeconfig"}
+
+			credentials, err := registrymirror.LoadCredentials(context.Background(), secrets, managementCluster, givenClusterWithSecretRef(tt.namespace))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(credentials).To(Equal(&registrymirror.Credentials{Username: "user", Password: "password"}))
+			g.Expect(secrets.kubeconfig).To(Equal("management.kubeconfig"))
+			g.Expect(secrets.namespace).To(Equal(tt.wantNamespace))
+		})
+	}
+}
+
+func TestLoadCredentialsWithoutSecretRef(t *testing.T) {
+	g := NewWithT(t)
+	cluster := givenCluster(&v1alpha1.RegistryMirrorConfiguration{
+		Endpoint:       "1.2.3.4",
+		Authentication: &v1alpha1.RegistryMirrorAuthentication{Username: "user", Password: "password"},
+	})
+
+	credentials, err := registrymirror.LoadCredentials(context.Background(), &fakeSecretGetter{}, nil, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(credentials).To(BeNil())
+}
+
+func TestLoadCredentialsErrors(t *testing.T) {
+	managementCluster := &types.Cluster{Name: "management", KubeconfigFile: "management.kubeconfig"}
+	tests := []struct {
+		name              string
+		secrets           *fakeSecretGetter
+		managementCluster *types.Cluster
+		wantErr           string
+	}{
+		{
+			name:    "no management cluster",
+			secrets: &fakeSecretGetter{},
+			wantErr: "requires an existing management cluster",
+		},
+		{
+			name:              "secret not found",
+			secrets:           &fakeSecretGetter{err: errors.New("not found")},
+			managementCluster: managementCluster,
+			wantErr:           "error getting registry mirror credentials secret test-namespace/mirror-credentials: not found",
+		},
+		{
+			name:              "missing keys",
+			secrets:           &fakeSecretGetter{secret: &corev1.Secret{Data: map[string][]byte{"username": []byte("user")}}},
+			managementCluster: managementCluster,
+			wantErr:           "must have username and password keys",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := registrymirror.LoadCredentials(context.Background(), tt.secrets, tt.managementCluster, givenClusterWithSecretRef(""))
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestValidateAuthentication(t *testing.T) {
+	g := NewWithT(t)
+	ecrMirror := test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password"))
+	dockerMirror := test.NewRegistry(t, test.WithRegistryTokenAuth("user", "password"))
+	config := &registrymirror.Configuration{
+		Mirrors: []registrymirror.Mirror{
+			{Registry: "public.ecr.aws", Endpoint: ecrMirror.Host()},
+			{Registry: "docker.io", Endpoint: dockerMirror.Host()},
+		},
+		InsecureSkipVerify: true,
+		Credentials:        &registrymirror.Credentials{Username: "user", Password: "password"},
+	}
+
+	g.Expect(registrymirror.ValidateAuthentication(context.Background(), config)).To(Succeed())
+}
+
+func TestValidateAuthenticationInvalidCredentials(t *testing.T) {
+	g := NewWithT(t)
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password"))
+	config := &registrymirror.Configuration{
+		Mirrors:       []registrymirror.Mirror{{Registry: "public.ecr.aws", Endpoint: mirror.Host()}},
+		CACertContent: string(mirror.CACert()),
+		Credentials:   &registrymirror.Credentials{Username: "user", Password: "wrong"},
+	}
+
+	err := registrymirror.ValidateAuthentication(context.Background(), config)
+	g.Expect(err).To(MatchError(ContainSubstring("registry mirror authentication failed: " + mirror.Host() + ": invalid credentials")))
+}
+
+func TestValidateAuthenticationWithoutMirror(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(registrymirror.ValidateAuthentication(context.Background(), nil)).To(Succeed())
+}
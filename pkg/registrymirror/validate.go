@@ -0,0 +1,46 @@
+package registrymirror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/registry"
+)
+
+// ValidateAuthentication logs in to every mirror endpoint with the configuration CA certificate and credentials,
+// the same way the container runtime in the nodes will. All failing endpoints are reported.
+func ValidateAuthentication(ctx context.Context, config *Configuration) error {
+	if config == nil {
+		return nil
+	}
+
+	endpoints := config.Endpoints()
+	opts := []registry.ClientOpt{registry.WithCACert([]byte(config.CACertContent))}
+	if config.InsecureSkipVerify {
+		opts = append(opts, registry.WithInsecureSkipVerify())
+	}
+	if config.Credentials != nil {
+		for _, endpoint := range endpoints {
+			opts = append(opts, registry.WithCredentials(endpoint, registry.Credentials{
+				Username: config.Credentials.Username,
+				Password: config.Credentials.Password,
+			}))
+		}
+	}
+	client, err := registry.NewClient(opts...)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, endpoint := range endpoints {
+		if err = client.Login(ctx, endpoint); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", endpoint, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("registry mirror authentication failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
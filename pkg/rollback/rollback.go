@@ -0,0 +1,280 @@
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+var (
+	kubeadmControlPlaneResourceType = fmt.Sprintf("kubeadmcontrolplanes.controlplane.%s", clusterv1.GroupVersion.Group)
+	machineDeploymentResourceType   = fmt.Sprintf("machinedeployments.%s", clusterv1.GroupVersion.Group)
+)
+
+type KubectlClient interface {
+	SnapshotClient
+	GetEksaCluster(ctx context.Context, cluster *types.Cluster, clusterName string) (*v1alpha1.Cluster, error)
+	GetBundles(ctx context.Context, kubeconfigFile, name, namespace string) (*releasev1alpha1.Bundles, error)
+	GetResource(ctx context.Context, resourceType string, name string, kubeconfig string, namespace string) (bool, error)
+	ApplyKubeSpecFromBytes(ctx context.Context, cluster *types.Cluster, data []byte) error
+	MergePatchResource(ctx context.Context, resourceType, objectName, patch string, cluster *types.Cluster, namespace string) error
+}
+
+// Rollbacker restores the objects of a cluster saved in a Snapshot before an upgrade
+type Rollbacker struct {
+	kubectl KubectlClient
+}
+
+// Result describes what a rollback restored and what was kept with the new version
+type Result struct {
+	ControlPlaneRestored bool
+	// ControlPlaneCommitted is true when the new version already rolled out to etcd or control plane machines
+	ControlPlaneCommitted      bool
+	RestoredMachineDeployments []string
+	UpgradedMachineDeployments []string
+}
+
+// Complete returns true when the cluster is back to the snapshot state
+func (r *Result) Complete() bool {
+	return !r.ControlPlaneCommitted && len(r.UpgradedMachineDeployments) == 0
+}
+
+func NewRollbacker(kubectl KubectlClient) *Rollbacker {
+	return &Rollbacker{kubectl: kubectl}
+}
+
+// Rollback restores the control plane, unless the new version has already been rolled out to any etcd or
+// control plane machine, and the machine deployments that haven't finished their rollout. The EKS-A Cluster
+// and Bundles are only restored when the control plane is restored.
+func (r *Rollbacker) Rollback(ctx context.Context, managementCluster *types.Cluster, snapshot *Snapshot) (*Result, error) {
+	clusterName := snapshot.Cluster.Name
+	result := &Result{}
+
+	etcdCommitted, err := r.etcdCommitted(ctx, managementCluster, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	cp, err := r.kubectl.GetKubeadmControlPlane(ctx, managementCluster, clusterName, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	if controlPlaneChanged(cp, snapshot.ControlPlane) {
+		if etcdCommitted || cp.Status.UpdatedReplicas > 0 {
+			result.ControlPlaneCommitted = true
+			logger.Info("Warning: the control plane already runs the new version and can't be rolled back", "kubernetesVersion", cp.Spec.Version)
+		} else {
+			if err = r.restoreControlPlane(ctx, managementCluster, snapshot.ControlPlane); err != nil {
+				return nil, err
+			}
+			result.ControlPlaneRestored = true
+		}
+	} else if etcdCommitted {
+		result.ControlPlaneCommitted = true
+	}
+
+	currentMds, err := clusterMachineDeployments(ctx, r.kubectl, managementCluster, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, previous := range snapshot.MachineDeployments {
+		current := findMachineDeployment(currentMds, previous.Name)
+		if current == nil || !machineDeploymentChanged(current, &previous) {
+			continue
+		}
+		if rolloutFinished(current) {
+			logger.Info("Warning: machine deployment already finished its rollout, keeping the new version", "machineDeployment", current.Name)
+			result.UpgradedMachineDeployments = append(result.UpgradedMachineDeployments, current.Name)
+			continue
+		}
+		if err = r.restoreMachineDeployment(ctx, managementCluster, &previous); err != nil {
+			return nil, err
+		}
+		result.RestoredMachineDeployments = append(result.RestoredMachineDeployments, current.Name)
+	}
+
+	if !result.ControlPlaneCommitted {
+		if err = r.restoreEksaObjects(ctx, managementCluster, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// etcdCommitted returns true when the external etcd spec differs from the snapshot: the etcdadm controller
+// replaces the etcd machines as soon as it changes and etcd data can't be downgraded
+func (r *Rollbacker) etcdCommitted(ctx context.Context, managementCluster *types.Cluster, snapshot *Snapshot) (bool, error) {
+	if snapshot.Etcd == nil {
+		return false, nil
+	}
+	etcd, err := r.kubectl.GetEtcdadmCluster(ctx, managementCluster, snapshot.Cluster.Name, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return false, err
+	}
+	if etcd.Spec.InfrastructureTemplate.Name == snapshot.Etcd.Spec.InfrastructureTemplate.Name &&
+		equality.Semantic.DeepEqual(etcd.Spec.EtcdadmConfigSpec, snapshot.Etcd.Spec.EtcdadmConfigSpec) {
+		return false, nil
+	}
+	logger.Info("Warning: external etcd has already been upgraded and can't be rolled back", "etcdadmCluster", etcd.Name)
+	return true, nil
+}
+
+func (r *Rollbacker) restoreControlPlane(ctx context.Context, managementCluster *types.Cluster, previous *kubeadmnv1alpha3.KubeadmControlPlane) error {
+	if err := r.checkTemplateExists(ctx, managementCluster, previous.Spec.InfrastructureTemplate); err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version":                previous.Spec.Version,
+			"infrastructureTemplate": previous.Spec.InfrastructureTemplate,
+			"kubeadmConfigSpec":      previous.Spec.KubeadmConfigSpec,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.V(3).Info("Restoring control plane", "kubeadmControlPlane", previous.Name, "version", previous.Spec.Version)
+	if err = r.kubectl.MergePatchResource(ctx, kubeadmControlPlaneResourceType, previous.Name, string(patch), managementCluster, constants.EksaSystemNamespace); err != nil {
+		return fmt.Errorf("error restoring control plane: %v", err)
+	}
+	return nil
+}
+
+func (r *Rollbacker) restoreMachineDeployment(ctx context.Context, managementCluster *types.Cluster, previous *clusterv1.MachineDeployment) error {
+	if err := r.checkTemplateExists(ctx, managementCluster, previous.Spec.Template.Spec.InfrastructureRef); err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"version":           previous.Spec.Template.Spec.Version,
+					"infrastructureRef": previous.Spec.Template.Spec.InfrastructureRef,
+					"bootstrap":         previous.Spec.Template.Spec.Bootstrap,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.V(3).Info("Restoring machine deployment", "machineDeployment", previous.Name)
+	if err = r.kubectl.MergePatchResource(ctx, machineDeploymentResourceType, previous.Name, string(patch), managementCluster, constants.EksaSystemNamespace); err != nil {
+		return fmt.Errorf("error restoring machine deployment %s: %v", previous.Name, err)
+	}
+	return nil
+}
+
+// restoreEksaObjects reapplies the EKS-A Cluster and Bundles from the snapshot if the upgrade already updated them
+func (r *Rollbacker) restoreEksaObjects(ctx context.Context, managementCluster *types.Cluster, snapshot *Snapshot) error {
+	eksaCluster, err := r.kubectl.GetEksaCluster(ctx, managementCluster, snapshot.Cluster.Name)
+	if err != nil {
+		return err
+	}
+	if !eksaCluster.Equal(snapshot.Cluster) {
+		logger.V(3).Info("Restoring EKS-A cluster", "cluster", snapshot.Cluster.Name)
+		previous := &v1alpha1.Cluster{TypeMeta: snapshot.Cluster.TypeMeta, Spec: snapshot.Cluster.Spec}
+		previous.Name = snapshot.Cluster.Name
+		previous.Namespace = snapshot.Cluster.Namespace
+		if err = r.apply(ctx, managementCluster, previous); err != nil {
+			return fmt.Errorf("error restoring EKS-A cluster: %v", err)
+		}
+	}
+
+	if snapshot.Bundles == nil {
+		return nil
+	}
+	bundles, err := r.kubectl.GetBundles(ctx, managementCluster.KubeconfigFile, snapshot.Bundles.Name, snapshot.Bundles.Namespace)
+	if err != nil {
+		return err
+	}
+	if bundles.Spec.Number != snapshot.Bundles.Spec.Number {
+		logger.V(3).Info("Restoring Bundles", "number", snapshot.Bundles.Spec.Number)
+		previous := &releasev1alpha1.Bundles{TypeMeta: snapshot.Bundles.TypeMeta, Spec: snapshot.Bundles.Spec}
+		previous.Name = snapshot.Bundles.Name
+		previous.Namespace = snapshot.Bundles.Namespace
+		if err = r.apply(ctx, managementCluster, previous); err != nil {
+			return fmt.Errorf("error restoring Bundles: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *Rollbacker) apply(ctx context.Context, managementCluster *types.Cluster, obj interface{}) error {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return r.kubectl.ApplyKubeSpecFromBytes(ctx, managementCluster, content)
+}
+
+// checkTemplateExists makes sure a machine template from the snapshot hasn't been deleted since the upgrade
+func (r *Rollbacker) checkTemplateExists(ctx context.Context, managementCluster *types.Cluster, ref corev1.ObjectReference) error {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return fmt.Errorf("invalid machine template reference %s: %v", ref.Name, err)
+	}
+	resourceType := fmt.Sprintf("%ss.%s", strings.ToLower(ref.Kind), gv.Group)
+	found, err := r.kubectl.GetResource(ctx, resourceType, ref.Name, managementCluster.KubeconfigFile, constants.EksaSystemNamespace)
+	if err != nil {
+		return fmt.Errorf("error getting machine template %s: %v", ref.Name, err)
+	}
+	if !found {
+		return fmt.Errorf("machine template %s %s from before the upgrade doesn't exist anymore, can't roll back", ref.Kind, ref.Name)
+	}
+	return nil
+}
+
+func controlPlaneChanged(current, previous *kubeadmnv1alpha3.KubeadmControlPlane) bool {
+	return current.Spec.Version != previous.Spec.Version ||
+		current.Spec.InfrastructureTemplate.Name != previous.Spec.InfrastructureTemplate.Name ||
+		!equality.Semantic.DeepEqual(current.Spec.KubeadmConfigSpec, previous.Spec.KubeadmConfigSpec)
+}
+
+func machineDeploymentChanged(current, previous *clusterv1.MachineDeployment) bool {
+	return !equality.Semantic.DeepEqual(current.Spec.Template.Spec.Version, previous.Spec.Template.Spec.Version) ||
+		current.Spec.Template.Spec.InfrastructureRef.Name != previous.Spec.Template.Spec.InfrastructureRef.Name ||
+		!equality.Semantic.DeepEqual(current.Spec.Template.Spec.Bootstrap.ConfigRef, previous.Spec.Template.Spec.Bootstrap.ConfigRef)
+}
+
+// rolloutFinished returns true when all the machines of the machine deployment have been replaced and are available
+func rolloutFinished(md *clusterv1.MachineDeployment) bool {
+	replicas := int32(1)
+	if md.Spec.Replicas != nil {
+		replicas = *md.Spec.Replicas
+	}
+	return md.Status.ObservedGeneration >= md.Generation &&
+		md.Status.UpdatedReplicas == replicas &&
+		md.Status.Replicas == replicas &&
+		md.Status.UnavailableReplicas == 0
+}
+
+func findMachineDeployment(mds []clusterv1.MachineDeployment, name string) *clusterv1.MachineDeployment {
+	for i := range mds {
+		if mds[i].Name == name {
+			return &mds[i]
+		}
+	}
+	return nil
+}
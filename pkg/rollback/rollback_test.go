@@ -0,0 +1,202 @@
+package rollback_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/rollback"
+	"github.com/aws/eks-anywhere/pkg/rollback/mocks"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const (
+	kcpResourceType      = "kubeadmcontrolplanes.controlplane.cluster.x-k8s.io"
+	mdResourceType       = "machinedeployments.cluster.x-k8s.io"
+	templateResourceType = "vspheremachinetemplates.infrastructure.cluster.x-k8s.io"
+)
+
+type rollbackTest struct {
+	*WithT
+	ctx               context.Context
+	kubectl           *mocks.MockKubectlClient
+	managementCluster *types.Cluster
+	snapshot          *rollback.Snapshot
+	rollbacker        *rollback.Rollbacker
+}
+
+func newRollbackTest(t *testing.T) *rollbackTest {
+	kubectl := mocks.NewMockKubectlClient(gomock.NewController(t))
+	return &rollbackTest{
+		WithT:             NewWithT(t),
+		ctx:               context.Background(),
+		kubectl:           kubectl,
+		managementCluster: &types.Cluster{Name: "test-cluster", KubeconfigFile: "test-cluster.kubeconfig"},
+		snapshot: &rollback.Snapshot{
+			Cluster: &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Spec:       v1alpha1.ClusterSpec{KubernetesVersion: v1alpha1.Kube120},
+			},
+			Bundles: &releasev1alpha1.Bundles{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Spec:       releasev1alpha1.BundlesSpec{Number: 1},
+			},
+			ControlPlane:       controlPlane("v1.20.7-eks-1-20-4", "test-cluster-control-plane-template-1", 0),
+			MachineDeployments: []clusterv1.MachineDeployment{machineDeployment("test-cluster-md-0", "v1.20.7-eks-1-20-4", "test-cluster-worker-template-1", 3, 3)},
+		},
+		rollbacker: rollback.NewRollbacker(kubectl),
+	}
+}
+
+func controlPlane(version, template string, updatedReplicas int32) *kubeadmnv1alpha3.KubeadmControlPlane {
+	return &kubeadmnv1alpha3.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec: kubeadmnv1alpha3.KubeadmControlPlaneSpec{
+			Version: version,
+			InfrastructureTemplate: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "VSphereMachineTemplate",
+				Name:       template,
+			},
+		},
+		Status: kubeadmnv1alpha3.KubeadmControlPlaneStatus{UpdatedReplicas: updatedReplicas},
+	}
+}
+
+func machineDeployment(name, version, template string, replicas, updatedReplicas int32) clusterv1.MachineDeployment {
+	return clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: &replicas,
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version: &version,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "VSphereMachineTemplate",
+						Name:       template,
+					},
+				},
+			},
+		},
+		Status: clusterv1.MachineDeploymentStatus{
+			Replicas:        replicas,
+			UpdatedReplicas: updatedReplicas,
+		},
+	}
+}
+
+func (tt *rollbackTest) expectGetControlPlane(cp *kubeadmnv1alpha3.KubeadmControlPlane) {
+	tt.kubectl.EXPECT().GetKubeadmControlPlane(tt.ctx, tt.managementCluster, "test-cluster", gomock.Any(), gomock.Any()).Return(cp, nil)
+}
+
+func (tt *rollbackTest) expectGetMachineDeployments(mds ...clusterv1.MachineDeployment) {
+	tt.kubectl.EXPECT().GetMachineDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return(mds, nil)
+}
+
+func (tt *rollbackTest) expectEksaObjectsUnchanged() {
+	tt.kubectl.EXPECT().GetEksaCluster(tt.ctx, tt.managementCluster, "test-cluster").Return(tt.snapshot.Cluster.DeepCopy(), nil)
+	tt.kubectl.EXPECT().GetBundles(tt.ctx, "test-cluster.kubeconfig", "test-cluster", "").Return(tt.snapshot.Bundles.DeepCopy(), nil)
+}
+
+func (tt *rollbackTest) expectTemplateExists(name string) {
+	tt.kubectl.EXPECT().GetResource(tt.ctx, templateResourceType, name, "test-cluster.kubeconfig", "eksa-system").Return(true, nil)
+}
+
+func TestRollbackRestoresControlPlaneAndMachineDeployments(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.expectGetControlPlane(controlPlane("v1.21.2-eks-1-21-4", "test-cluster-control-plane-template-2", 0))
+	tt.expectTemplateExists("test-cluster-control-plane-template-1")
+	tt.kubectl.EXPECT().MergePatchResource(tt.ctx, kcpResourceType, "test-cluster", gomock.Any(), tt.managementCluster, "eksa-system")
+	tt.expectGetMachineDeployments(machineDeployment("test-cluster-md-0", "v1.21.2-eks-1-21-4", "test-cluster-worker-template-2", 3, 0))
+	tt.expectTemplateExists("test-cluster-worker-template-1")
+	tt.kubectl.EXPECT().MergePatchResource(
+		tt.ctx, mdResourceType, "test-cluster-md-0",
+		`{"spec":{"template":{"spec":{"bootstrap":{},"infrastructureRef":{"kind":"VSphereMachineTemplate","name":"test-cluster-worker-template-1","apiVersion":"infrastructure.cluster.x-k8s.io/v1alpha3"},"version":"v1.20.7-eks-1-20-4"}}}}`,
+		tt.managementCluster, "eksa-system",
+	)
+	tt.expectEksaObjectsUnchanged()
+
+	result, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.ControlPlaneRestored).To(BeTrue())
+	tt.Expect(result.RestoredMachineDeployments).To(ConsistOf("test-cluster-md-0"))
+	tt.Expect(result.Complete()).To(BeTrue())
+}
+
+func TestRollbackKeepsCommittedControlPlane(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.expectGetControlPlane(controlPlane("v1.21.2-eks-1-21-4", "test-cluster-control-plane-template-2", 1))
+	tt.expectGetMachineDeployments(machineDeployment("test-cluster-md-0", "v1.21.2-eks-1-21-4", "test-cluster-worker-template-2", 3, 1))
+	tt.expectTemplateExists("test-cluster-worker-template-1")
+	tt.kubectl.EXPECT().MergePatchResource(tt.ctx, mdResourceType, "test-cluster-md-0", gomock.Any(), tt.managementCluster, "eksa-system")
+
+	result, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.ControlPlaneRestored).To(BeFalse())
+	tt.Expect(result.ControlPlaneCommitted).To(BeTrue())
+	tt.Expect(result.RestoredMachineDeployments).To(ConsistOf("test-cluster-md-0"))
+	tt.Expect(result.Complete()).To(BeFalse())
+}
+
+func TestRollbackKeepsFinishedMachineDeployments(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.snapshot.MachineDeployments = append(tt.snapshot.MachineDeployments, machineDeployment("test-cluster-md-1", "v1.20.7-eks-1-20-4", "test-cluster-worker-template-1", 2, 2))
+	tt.expectGetControlPlane(controlPlane("v1.21.2-eks-1-21-4", "test-cluster-control-plane-template-2", 3))
+	tt.expectGetMachineDeployments(
+		machineDeployment("test-cluster-md-0", "v1.21.2-eks-1-21-4", "test-cluster-worker-template-2", 3, 3),
+		machineDeployment("test-cluster-md-1", "v1.20.7-eks-1-20-4", "test-cluster-worker-template-1", 2, 2),
+	)
+
+	result, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.RestoredMachineDeployments).To(BeEmpty())
+	tt.Expect(result.UpgradedMachineDeployments).To(ConsistOf("test-cluster-md-0"))
+}
+
+func TestRollbackRestoresEksaObjects(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.expectGetControlPlane(tt.snapshot.ControlPlane.DeepCopy())
+	tt.expectGetMachineDeployments(tt.snapshot.MachineDeployments...)
+	current := tt.snapshot.Cluster.DeepCopy()
+	current.Spec.KubernetesVersion = v1alpha1.Kube121
+	tt.kubectl.EXPECT().GetEksaCluster(tt.ctx, tt.managementCluster, "test-cluster").Return(current, nil)
+	tt.kubectl.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.managementCluster, gomock.Any())
+	currentBundles := tt.snapshot.Bundles.DeepCopy()
+	currentBundles.Spec.Number = 2
+	tt.kubectl.EXPECT().GetBundles(tt.ctx, "test-cluster.kubeconfig", "test-cluster", "").Return(currentBundles, nil)
+	tt.kubectl.EXPECT().ApplyKubeSpecFromBytes(tt.ctx, tt.managementCluster, gomock.Any())
+
+	result, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).NotTo(HaveOccurred())
+	tt.Expect(result.Complete()).To(BeTrue())
+}
+
+func TestRollbackMissingTemplate(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.expectGetControlPlane(controlPlane("v1.21.2-eks-1-21-4", "test-cluster-control-plane-template-2", 0))
+	tt.kubectl.EXPECT().GetResource(tt.ctx, templateResourceType, "test-cluster-control-plane-template-1", "test-cluster.kubeconfig", "eksa-system").Return(false, nil)
+
+	_, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).To(MatchError(ContainSubstring("doesn't exist anymore")))
+}
+
+func TestRollbackGetControlPlaneError(t *testing.T) {
+	tt := newRollbackTest(t)
+	tt.kubectl.EXPECT().GetKubeadmControlPlane(tt.ctx, tt.managementCluster, "test-cluster", gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting kcp"))
+
+	_, err := tt.rollbacker.Rollback(tt.ctx, tt.managementCluster, tt.snapshot)
+	tt.Expect(err).To(MatchError("error getting kcp"))
+}
@@ -0,0 +1,105 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	etcdv1alpha3 "github.com/mrajashree/etcdadm-controller/api/v1alpha3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	kubeadmnv1alpha3 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const snapshotFilePattern = "%s-eks-a-upgrade-snapshot.yaml"
+
+// Snapshot holds the EKS-A and CAPI objects of a cluster before an upgrade, so a failed upgrade can be rolled back
+type Snapshot struct {
+	Cluster            *v1alpha1.Cluster                     `json:"cluster"`
+	Bundles            *releasev1alpha1.Bundles              `json:"bundles"`
+	ControlPlane       *kubeadmnv1alpha3.KubeadmControlPlane `json:"controlPlane"`
+	Etcd               *etcdv1alpha3.EtcdadmCluster          `json:"etcd,omitempty"`
+	MachineDeployments []clusterv1.MachineDeployment         `json:"machineDeployments"`
+}
+
+type SnapshotClient interface {
+	GetKubeadmControlPlane(ctx context.Context, cluster *types.Cluster, clusterName string, opts ...executables.KubectlOpt) (*kubeadmnv1alpha3.KubeadmControlPlane, error)
+	GetEtcdadmCluster(ctx context.Context, cluster *types.Cluster, clusterName string, opts ...executables.KubectlOpt) (*etcdv1alpha3.EtcdadmCluster, error)
+	GetMachineDeployments(ctx context.Context, opts ...executables.KubectlOpt) ([]clusterv1.MachineDeployment, error)
+}
+
+// SnapshotFileName returns the name of the file the snapshot of clusterName is written to, in the cluster folder
+func SnapshotFileName(clusterName string) string {
+	return fmt.Sprintf(snapshotFilePattern, clusterName)
+}
+
+// TakeSnapshot reads the objects of the cluster described by currentSpec from managementCluster
+func TakeSnapshot(ctx context.Context, client SnapshotClient, managementCluster *types.Cluster, currentSpec *cluster.Spec) (*Snapshot, error) {
+	s := &Snapshot{
+		Cluster: currentSpec.Cluster.DeepCopy(),
+		Bundles: currentSpec.Bundles.DeepCopy(),
+	}
+
+	var err error
+	s.ControlPlane, err = client.GetKubeadmControlPlane(ctx, managementCluster, currentSpec.Name, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	if currentSpec.Spec.ExternalEtcdConfiguration != nil {
+		s.Etcd, err = client.GetEtcdadmCluster(ctx, managementCluster, currentSpec.Name, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.MachineDeployments, err = clusterMachineDeployments(ctx, client, managementCluster, currentSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ReadSnapshot reads a snapshot written with Marshal
+func ReadSnapshot(file string) (*Snapshot, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading upgrade snapshot: %v", err)
+	}
+
+	s := &Snapshot{}
+	if err = yaml.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("error parsing upgrade snapshot %s: %v", file, err)
+	}
+	if s.Cluster == nil || s.ControlPlane == nil {
+		return nil, fmt.Errorf("invalid upgrade snapshot %s: cluster and control plane are required", file)
+	}
+	return s, nil
+}
+
+func (s *Snapshot) Marshal() ([]byte, error) {
+	return yaml.Marshal(s)
+}
+
+func clusterMachineDeployments(ctx context.Context, client SnapshotClient, managementCluster *types.Cluster, clusterName string) ([]clusterv1.MachineDeployment, error) {
+	mds, err := client.GetMachineDeployments(ctx, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	clusterMds := make([]clusterv1.MachineDeployment, 0, len(mds))
+	for _, md := range mds {
+		if md.Labels[clusterv1.ClusterLabelName] == clusterName {
+			clusterMds = append(clusterMds, md)
+		}
+	}
+	return clusterMds, nil
+}
@@ -0,0 +1,62 @@
+package rollback_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/rollback"
+	"github.com/aws/eks-anywhere/pkg/rollback/mocks"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+func TestTakeSnapshotAndRead(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	kubectl := mocks.NewMockKubectlClient(gomock.NewController(t))
+	managementCluster := &types.Cluster{Name: "test-cluster", KubeconfigFile: "test-cluster.kubeconfig"}
+	spec := test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Name = "test-cluster"
+		s.Spec.KubernetesVersion = v1alpha1.Kube120
+	})
+
+	cp := controlPlane("v1.20.7-eks-1-20-4", "test-cluster-control-plane-template-1", 1)
+	md := machineDeployment("test-cluster-md-0", "v1.20.7-eks-1-20-4", "test-cluster-worker-template-1", 3, 3)
+	otherMd := machineDeployment("other-cluster-md-0", "v1.20.7-eks-1-20-4", "other-cluster-worker-template-1", 3, 3)
+	otherMd.Labels[clusterv1.ClusterLabelName] = "other-cluster"
+	kubectl.EXPECT().GetKubeadmControlPlane(ctx, managementCluster, "test-cluster", gomock.Any(), gomock.Any()).Return(cp, nil)
+	kubectl.EXPECT().GetMachineDeployments(ctx, gomock.Any(), gomock.Any()).Return([]clusterv1.MachineDeployment{md, otherMd}, nil)
+
+	snapshot, err := rollback.TakeSnapshot(ctx, kubectl, managementCluster, spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshot.Etcd).To(BeNil())
+	g.Expect(snapshot.MachineDeployments).To(HaveLen(1))
+
+	content, err := snapshot.Marshal()
+	g.Expect(err).NotTo(HaveOccurred())
+	file := filepath.Join(t.TempDir(), rollback.SnapshotFileName("test-cluster"))
+	g.Expect(os.WriteFile(file, content, 0o644)).To(Succeed())
+
+	read, err := rollback.ReadSnapshot(file)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(read.Cluster.Spec.KubernetesVersion).To(Equal(v1alpha1.Kube120))
+	g.Expect(read.ControlPlane.Spec.Version).To(Equal("v1.20.7-eks-1-20-4"))
+	g.Expect(read.MachineDeployments[0].Name).To(Equal("test-cluster-md-0"))
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	g := NewWithT(t)
+	file := filepath.Join(t.TempDir(), "snapshot.yaml")
+	g.Expect(os.WriteFile(file, []byte("cluster:\n  metadata:\n    name: test-cluster\n"), 0o644)).To(Succeed())
+
+	_, err := rollback.ReadSnapshot(file)
+	g.Expect(err).To(MatchError(ContainSubstring("cluster and control plane are required")))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/aws/eks-anywhere/pkg/logger"
 	"github.com/aws/eks-anywhere/pkg/providers"
 	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
 	"github.com/aws/eks-anywhere/pkg/workflows/interfaces"
 )
 
@@ -25,6 +26,7 @@ type CommandContext struct {
 	ClusterManager     interfaces.ClusterManager
 	AddonManager       interfaces.AddonManager
 	Validations        interfaces.Validator
+	ValidationOpts     []validations.RunnerOpt
 	Writer             filewriter.FileWriter
 	CAPIManager        interfaces.CAPIManager
 	ClusterSpec        *cluster.Spec
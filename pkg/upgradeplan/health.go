@@ -0,0 +1,109 @@
+package upgradeplan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/retrier"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	defaultHealthGateTimeout = 30 * time.Minute
+	healthCheckBackOff       = 10 * time.Second
+)
+
+type KubectlClient interface {
+	ValidateNodes(ctx context.Context, kubeconfig string) error
+	ValidateControlPlaneNodes(ctx context.Context, cluster *types.Cluster, clusterName string) error
+	ValidateWorkerNodes(ctx context.Context, cluster *types.Cluster, clusterName string) error
+	GetDeployments(ctx context.Context, opts ...executables.KubectlOpt) ([]appsv1.Deployment, error)
+}
+
+// HealthGate waits for a cluster to be healthy after a hop before moving to the next one
+type HealthGate struct {
+	kubectl KubectlClient
+	retrier *retrier.Retrier
+}
+
+type HealthGateOpt func(*HealthGate)
+
+func WithRetrier(r *retrier.Retrier) HealthGateOpt {
+	return func(h *HealthGate) {
+		h.retrier = r
+	}
+}
+
+func NewHealthGate(kubectl KubectlClient, opts ...HealthGateOpt) *HealthGate {
+	h := &HealthGate{
+		kubectl: kubectl,
+		retrier: retrier.New(defaultHealthGateTimeout, retrier.WithRetryPolicy(func(_ int, _ error) (bool, time.Duration) {
+			return true, healthCheckBackOff
+		})),
+	}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
+}
+
+// Wait blocks until all the machines of workloadCluster are ready, all its nodes are Ready and the core
+// deployments are available. managementCluster holds the CAPI objects of workloadCluster, they can be the same.
+func (h *HealthGate) Wait(ctx context.Context, managementCluster, workloadCluster *types.Cluster) error {
+	logger.V(3).Info("Waiting for cluster to be healthy", "cluster", workloadCluster.Name)
+	err := h.retrier.Retry(func() error {
+		return h.check(ctx, managementCluster, workloadCluster)
+	})
+	if err != nil {
+		return fmt.Errorf("cluster %s is not healthy: %v", workloadCluster.Name, err)
+	}
+	return nil
+}
+
+func (h *HealthGate) check(ctx context.Context, managementCluster, workloadCluster *types.Cluster) error {
+	if err := h.kubectl.ValidateControlPlaneNodes(ctx, managementCluster, workloadCluster.Name); err != nil {
+		return err
+	}
+	if err := h.kubectl.ValidateWorkerNodes(ctx, managementCluster, workloadCluster.Name); err != nil {
+		return err
+	}
+	if err := h.kubectl.ValidateNodes(ctx, workloadCluster.KubeconfigFile); err != nil {
+		return err
+	}
+
+	namespaces := []string{constants.KubeSystemNamespace}
+	if managementCluster.Name == workloadCluster.Name {
+		namespaces = append(namespaces, constants.EksaSystemNamespace)
+	}
+	for _, namespace := range namespaces {
+		if err := h.checkDeployments(ctx, workloadCluster, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *HealthGate) checkDeployments(ctx context.Context, cluster *types.Cluster, namespace string) error {
+	deployments, err := h.kubectl.GetDeployments(ctx, executables.WithCluster(cluster), executables.WithNamespace(namespace))
+	if err != nil {
+		return fmt.Errorf("error getting deployments in namespace %s: %v", namespace, err)
+	}
+
+	for _, d := range deployments {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.UpdatedReplicas < desired || d.Status.AvailableReplicas < desired {
+			return fmt.Errorf("deployment %s/%s is not available: %d updated and %d available replicas out of %d",
+				namespace, d.Name, d.Status.UpdatedReplicas, d.Status.AvailableReplicas, desired)
+		}
+	}
+	return nil
+}
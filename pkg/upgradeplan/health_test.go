@@ -0,0 +1,91 @@
+package upgradeplan_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/pkg/retrier"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/upgradeplan"
+	"github.com/aws/eks-anywhere/pkg/upgradeplan/mocks"
+)
+
+type healthGateTest struct {
+	*WithT
+	ctx               context.Context
+	kubectl           *mocks.MockKubectlClient
+	managementCluster *types.Cluster
+	workloadCluster   *types.Cluster
+	gate              *upgradeplan.HealthGate
+}
+
+func newHealthGateTest(t *testing.T) *healthGateTest {
+	kubectl := mocks.NewMockKubectlClient(gomock.NewController(t))
+	return &healthGateTest{
+		WithT:             NewWithT(t),
+		ctx:               context.Background(),
+		kubectl:           kubectl,
+		managementCluster: &types.Cluster{Name: "management", KubeconfigFile: "management.kubeconfig"},
+		workloadCluster:   &types.Cluster{Name: "workload", KubeconfigFile: "workload.kubeconfig"},
+		gate:              upgradeplan.NewHealthGate(kubectl, upgradeplan.WithRetrier(retrier.NewWithMaxRetries(2, 0))),
+	}
+}
+
+func (tt *healthGateTest) expectNodesReady() {
+	tt.kubectl.EXPECT().ValidateControlPlaneNodes(tt.ctx, tt.managementCluster, "workload").Return(nil)
+	tt.kubectl.EXPECT().ValidateWorkerNodes(tt.ctx, tt.managementCluster, "workload").Return(nil)
+	tt.kubectl.EXPECT().ValidateNodes(tt.ctx, "workload.kubeconfig").Return(nil)
+}
+
+func deployment(name string, replicas, available int32) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: replicas, AvailableReplicas: available},
+	}
+}
+
+func TestHealthGateWaitSuccess(t *testing.T) {
+	tt := newHealthGateTest(t)
+	tt.expectNodesReady()
+	tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return([]appsv1.Deployment{deployment("coredns", 2, 2)}, nil)
+
+	tt.Expect(tt.gate.Wait(tt.ctx, tt.managementCluster, tt.workloadCluster)).To(Succeed())
+}
+
+func TestHealthGateWaitRetriesUntilDeploymentsAvailable(t *testing.T) {
+	tt := newHealthGateTest(t)
+	tt.expectNodesReady()
+	tt.expectNodesReady()
+	gomock.InOrder(
+		tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return([]appsv1.Deployment{deployment("coredns", 2, 1)}, nil),
+		tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return([]appsv1.Deployment{deployment("coredns", 2, 2)}, nil),
+	)
+
+	tt.Expect(tt.gate.Wait(tt.ctx, tt.managementCluster, tt.workloadCluster)).To(Succeed())
+}
+
+func TestHealthGateWaitManagementClusterChecksEksaDeployments(t *testing.T) {
+	tt := newHealthGateTest(t)
+	tt.workloadCluster = tt.managementCluster
+	tt.kubectl.EXPECT().ValidateControlPlaneNodes(tt.ctx, tt.managementCluster, "management").Return(nil)
+	tt.kubectl.EXPECT().ValidateWorkerNodes(tt.ctx, tt.managementCluster, "management").Return(nil)
+	tt.kubectl.EXPECT().ValidateNodes(tt.ctx, "management.kubeconfig").Return(nil)
+	tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	tt.Expect(tt.gate.Wait(tt.ctx, tt.managementCluster, tt.workloadCluster)).To(Succeed())
+}
+
+func TestHealthGateWaitNodesNotReady(t *testing.T) {
+	tt := newHealthGateTest(t)
+	tt.kubectl.EXPECT().ValidateControlPlaneNodes(tt.ctx, tt.managementCluster, "workload").Return(nil).Times(2)
+	tt.kubectl.EXPECT().ValidateWorkerNodes(tt.ctx, tt.managementCluster, "workload").Return(errors.New("worker nodes not ready")).Times(2)
+
+	tt.Expect(tt.gate.Wait(tt.ctx, tt.managementCluster, tt.workloadCluster)).To(MatchError("cluster workload is not healthy: worker nodes not ready"))
+}
@@ -0,0 +1,105 @@
+package upgradeplan
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+// Hop is an upgrade to the next Kubernetes minor version, the biggest version increment supported in one upgrade
+type Hop struct {
+	KubernetesVersion v1alpha1.KubernetesVersion
+	EksDRelease       string
+	// NodeRollouts is the number of machines replaced by the upgrade
+	NodeRollouts int
+}
+
+// Plan is the sequence of upgrades needed to move a cluster from its current Kubernetes version to the target one
+type Plan struct {
+	ClusterName    string
+	CurrentVersion v1alpha1.KubernetesVersion
+	TargetVersion  v1alpha1.KubernetesVersion
+	BundleNumber   int
+	Hops           []Hop
+}
+
+// New computes the upgrade plan for cluster, currently running the Kubernetes serverVersion, to reach
+// targetVersion. Every intermediate minor version must be available in bundles.
+func New(cluster *v1alpha1.Cluster, serverVersion string, targetVersion v1alpha1.KubernetesVersion, bundles *releasev1alpha1.Bundles) (*Plan, error) {
+	current, err := version.ParseSemantic(serverVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cluster version: %v", err)
+	}
+	target, err := version.ParseGeneric(string(targetVersion))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing target version: %v", err)
+	}
+
+	if current.Major() != target.Major() {
+		return nil, fmt.Errorf("upgrading from Kubernetes %d.%d to %d.%d is not supported, major version upgrades are not supported",
+			current.Major(), current.Minor(), target.Major(), target.Minor())
+	}
+	if target.Minor() < current.Minor() {
+		return nil, fmt.Errorf("target version %d.%d is older than cluster version %d.%d, downgrades are not supported",
+			target.Major(), target.Minor(), current.Major(), current.Minor())
+	}
+
+	p := &Plan{
+		ClusterName:    cluster.Name,
+		CurrentVersion: v1alpha1.KubernetesVersion(fmt.Sprintf("%d.%d", current.Major(), current.Minor())),
+		TargetVersion:  v1alpha1.KubernetesVersion(fmt.Sprintf("%d.%d", target.Major(), target.Minor())),
+		BundleNumber:   bundles.Spec.Number,
+	}
+
+	machines := machineCount(cluster)
+	for minor := current.Minor() + 1; minor <= target.Minor(); minor++ {
+		kubeVersion := fmt.Sprintf("%d.%d", target.Major(), minor)
+		versionsBundle := versionsBundleForKubeVersion(bundles, kubeVersion)
+		if versionsBundle == nil {
+			return nil, fmt.Errorf("kubernetes version %s is not supported by bundles manifest %d, can't upgrade cluster %s from %s to %s",
+				kubeVersion, bundles.Spec.Number, cluster.Name, p.CurrentVersion, p.TargetVersion)
+		}
+
+		p.Hops = append(p.Hops, Hop{
+			KubernetesVersion: v1alpha1.KubernetesVersion(kubeVersion),
+			EksDRelease:       versionsBundle.EksD.Name,
+			NodeRollouts:      machines,
+		})
+	}
+
+	return p, nil
+}
+
+// NodeRollouts returns the total number of machines replaced by all the hops of the plan
+func (p *Plan) NodeRollouts() int {
+	total := 0
+	for _, h := range p.Hops {
+		total += h.NodeRollouts
+	}
+	return total
+}
+
+func versionsBundleForKubeVersion(bundles *releasev1alpha1.Bundles, kubeVersion string) *releasev1alpha1.VersionsBundle {
+	for _, vb := range bundles.Spec.VersionsBundles {
+		if vb.KubeVersion == kubeVersion {
+			return &vb
+		}
+	}
+	return nil
+}
+
+// machineCount returns the number of machines in the cluster. A Kubernetes version upgrade replaces all of them:
+// control plane, workers and external etcd, since every new EKS-D release comes with a new etcd build.
+func machineCount(cluster *v1alpha1.Cluster) int {
+	count := cluster.Spec.ControlPlaneConfiguration.Count
+	for _, w := range cluster.Spec.WorkerNodeGroupConfigurations {
+		count += w.Count
+	}
+	if cluster.Spec.ExternalEtcdConfiguration != nil {
+		count += cluster.Spec.ExternalEtcdConfiguration.Count
+	}
+	return count
+}
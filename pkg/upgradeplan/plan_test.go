@@ -0,0 +1,85 @@
+package upgradeplan_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/upgradeplan"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+func newCluster() *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		Spec: v1alpha1.ClusterSpec{
+			ControlPlaneConfiguration: v1alpha1.ControlPlaneConfiguration{Count: 3},
+			WorkerNodeGroupConfigurations: []v1alpha1.WorkerNodeGroupConfiguration{
+				{Count: 2},
+				{Count: 1},
+			},
+			ExternalEtcdConfiguration: &v1alpha1.ExternalEtcdConfiguration{Count: 3},
+		},
+	}
+}
+
+func newBundles(kubeVersions ...string) *releasev1alpha1.Bundles {
+	b := &releasev1alpha1.Bundles{Spec: releasev1alpha1.BundlesSpec{Number: 2}}
+	for _, v := range kubeVersions {
+		b.Spec.VersionsBundles = append(b.Spec.VersionsBundles, releasev1alpha1.VersionsBundle{
+			KubeVersion: v,
+			EksD:        releasev1alpha1.EksDRelease{Name: "kubernetes-" + v + "-eks-1"},
+		})
+	}
+	return b
+}
+
+func TestNewMultipleHops(t *testing.T) {
+	g := NewWithT(t)
+
+	plan, err := upgradeplan.New(newCluster(), "v1.20.7-eks-1-20-8", "1.22", newBundles("1.20", "1.21", "1.22"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan).To(Equal(&upgradeplan.Plan{
+		ClusterName:    "test-cluster",
+		CurrentVersion: "1.20",
+		TargetVersion:  "1.22",
+		BundleNumber:   2,
+		Hops: []upgradeplan.Hop{
+			{KubernetesVersion: "1.21", EksDRelease: "kubernetes-1.21-eks-1", NodeRollouts: 9},
+			{KubernetesVersion: "1.22", EksDRelease: "kubernetes-1.22-eks-1", NodeRollouts: 9},
+		},
+	}))
+	g.Expect(plan.NodeRollouts()).To(Equal(18))
+}
+
+func TestNewAlreadyInTargetVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	plan, err := upgradeplan.New(newCluster(), "v1.21.2-eks-1-21-4", "1.21", newBundles("1.21"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan.Hops).To(BeEmpty())
+	g.Expect(plan.NodeRollouts()).To(Equal(0))
+}
+
+func TestNewMissingHopInBundles(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := upgradeplan.New(newCluster(), "v1.20.7-eks-1-20-8", "1.22", newBundles("1.20", "1.22"))
+	g.Expect(err).To(MatchError("kubernetes version 1.21 is not supported by bundles manifest 2, can't upgrade cluster test-cluster from 1.20 to 1.22"))
+}
+
+func TestNewDowngrade(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := upgradeplan.New(newCluster(), "v1.21.2-eks-1-21-4", "1.20", newBundles("1.20", "1.21"))
+	g.Expect(err).To(MatchError("target version 1.20 is older than cluster version 1.21, downgrades are not supported"))
+}
+
+func TestNewInvalidServerVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := upgradeplan.New(newCluster(), "1.21", "1.22", newBundles("1.22"))
+	g.Expect(err).To(MatchError(ContainSubstring("error parsing cluster version")))
+}
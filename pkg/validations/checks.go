@@ -0,0 +1,75 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/providers"
+	"github.com/aws/eks-anywhere/pkg/types"
+)
+
+const (
+	// ProviderSetupCheckName is the name of the provider setup and validation check
+	ProviderSetupCheckName = "provider setup"
+	providerSetupTimeout   = 15 * time.Minute
+)
+
+// ProviderSetupCheck wraps the provider setup and validation. It completes the cluster spec, so every
+// other validation depends on it and it can't be skipped
+func ProviderSetupCheck(validation Validation) Check {
+	return Check{
+		Name:       ProviderSetupCheckName,
+		Validation: validation,
+		Timeout:    providerSetupTimeout,
+		Critical:   true,
+	}
+}
+
+// CreateClusterChecker is implemented by providers that split the setup and validation of a new cluster in checks.
+// The cluster spec is completed by the check named ProviderSetupCheckName, which the rest of the checks depend on
+type CreateClusterChecker interface {
+	SetupAndValidateCreateClusterChecks(ctx context.Context, clusterSpec *cluster.Spec) []Check
+}
+
+// UpgradeClusterChecker is the same as CreateClusterChecker for the setup and validation of a cluster upgrade
+type UpgradeClusterChecker interface {
+	SetupAndValidateUpgradeClusterChecks(ctx context.Context, cluster *types.Cluster, clusterSpec *cluster.Spec) []Check
+}
+
+// ProviderCreateChecks returns the create checks of providers that implement CreateClusterChecker and
+// a single ProviderSetupCheck running SetupAndValidateCreateCluster for the rest
+func ProviderCreateChecks(ctx context.Context, provider providers.Provider, clusterSpec *cluster.Spec) []Check {
+	if checker, ok := provider.(CreateClusterChecker); ok {
+		return checker.SetupAndValidateCreateClusterChecks(ctx, clusterSpec)
+	}
+	return []Check{ProviderSetupCheck(func() *ValidationResult {
+		return &ValidationResult{
+			Name: fmt.Sprintf("%s Provider setup is valid", provider.Name()),
+			Err:  provider.SetupAndValidateCreateCluster(ctx, clusterSpec),
+		}
+	})}
+}
+
+// ProviderUpgradeChecks returns the upgrade checks of providers that implement UpgradeClusterChecker and
+// a single ProviderSetupCheck running SetupAndValidateUpgradeCluster for the rest
+func ProviderUpgradeChecks(ctx context.Context, provider providers.Provider, cluster *types.Cluster, clusterSpec *cluster.Spec) []Check {
+	if checker, ok := provider.(UpgradeClusterChecker); ok {
+		return checker.SetupAndValidateUpgradeClusterChecks(ctx, cluster, clusterSpec)
+	}
+	return []Check{ProviderSetupCheck(func() *ValidationResult {
+		return &ValidationResult{
+			Name: fmt.Sprintf("%s Provider setup is valid", provider.Name()),
+			Err:  provider.SetupAndValidateUpgradeCluster(ctx, cluster, clusterSpec),
+		}
+	})}
+}
+
+// DependOn adds names to the dependencies of all checks
+func DependOn(checks []Check, names ...string) []Check {
+	for i := range checks {
+		checks[i].DependsOn = append(checks[i].DependsOn, names...)
+	}
+	return checks
+}
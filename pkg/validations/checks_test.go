@@ -0,0 +1,73 @@
+package validations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/providers/mocks"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
+)
+
+func TestDependOn(t *testing.T) {
+	g := NewWithT(t)
+	checks := []validations.Check{
+		{Name: "a"},
+		{Name: "b", DependsOn: []string{"a"}},
+	}
+
+	checks = validations.DependOn(checks, validations.ProviderSetupCheckName)
+	g.Expect(checks[0].DependsOn).To(Equal([]string{"provider setup"}))
+	g.Expect(checks[1].DependsOn).To(Equal([]string{"a", "provider setup"}))
+}
+
+func TestProviderCreateChecksSetupCheck(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	provider := mocks.NewMockProvider(gomock.NewController(t))
+	clusterSpec := test.NewClusterSpec()
+	provider.EXPECT().Name().Return("docker")
+	provider.EXPECT().SetupAndValidateCreateCluster(ctx, clusterSpec).Return(errors.New("missing config"))
+
+	checks := validations.ProviderCreateChecks(ctx, provider, clusterSpec)
+	g.Expect(checks).To(HaveLen(1))
+	g.Expect(checks[0].Name).To(Equal(validations.ProviderSetupCheckName))
+	g.Expect(checks[0].Critical).To(BeTrue())
+	result := checks[0].Validation()
+	g.Expect(result.Name).To(Equal("docker Provider setup is valid"))
+	g.Expect(result.Err).To(MatchError("missing config"))
+}
+
+type checkedProvider struct {
+	*mocks.MockProvider
+	checks []validations.Check
+}
+
+func (p *checkedProvider) SetupAndValidateCreateClusterChecks(_ context.Context, _ *cluster.Spec) []validations.Check {
+	return p.checks
+}
+
+func (p *checkedProvider) SetupAndValidateUpgradeClusterChecks(_ context.Context, _ *types.Cluster, _ *cluster.Spec) []validations.Check {
+	return p.checks
+}
+
+func TestProviderChecksFromChecker(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	provider := &checkedProvider{
+		MockProvider: mocks.NewMockProvider(gomock.NewController(t)),
+		checks: []validations.Check{
+			validations.ProviderSetupCheck(passing("setup")),
+			{Name: "credentials", Validation: passing("credentials"), DependsOn: []string{validations.ProviderSetupCheckName}},
+		},
+	}
+
+	g.Expect(validations.ProviderCreateChecks(ctx, provider, test.NewClusterSpec())).To(HaveLen(2))
+	g.Expect(validations.ProviderUpgradeChecks(ctx, provider, &types.Cluster{}, test.NewClusterSpec())).To(HaveLen(2))
+}
@@ -0,0 +1,126 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/constants"
+	"github.com/aws/eks-anywhere/pkg/executables"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/semver"
+	"github.com/aws/eks-anywhere/pkg/types"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const (
+	eksaControllerDeploymentName = "eksa-controller-manager"
+	eksaControllerContainerName  = "manager"
+)
+
+// ValidateManagementClusterCompatibility checks the CLI can manage clusters with the management cluster:
+// the CLI version must be supported by the Bundles installed in the management cluster, the new bundle
+// can't be older than the installed one and the running eks-a controller must be the one from the
+// installed Bundles. When the management cluster itself is the one being upgraded, a newer CLI and a
+// controller mismatch are expected, since the upgrade fixes them.
+func ValidateManagementClusterCompatibility(ctx context.Context, k KubectlClient, managementCluster *types.Cluster, spec *cluster.Spec, cliVersion string) error {
+	if cliVersion == "" {
+		logger.V(5).Info("skipping ValidateManagementClusterCompatibility, unknown CLI version")
+		return nil
+	}
+	cli, err := semver.New(cliVersion)
+	if err != nil {
+		return fmt.Errorf("invalid CLI version %s: %v", cliVersion, err)
+	}
+
+	managementEksaCluster, err := k.GetEksaCluster(ctx, managementCluster, managementCluster.Name)
+	if err != nil {
+		return fmt.Errorf("error getting management cluster %s: %v", managementCluster.Name, err)
+	}
+	installed, err := k.GetBundles(ctx, managementCluster.KubeconfigFile, managementEksaCluster.Name, managementEksaCluster.Namespace)
+	if err != nil {
+		return fmt.Errorf("error getting the Bundles installed in management cluster %s: %v", managementCluster.Name, err)
+	}
+
+	upgradingManagementCluster := spec.IsSelfManaged() && spec.Name == managementEksaCluster.Name
+
+	if err = validateCliVersionForBundles(cli, installed, managementCluster.Name, upgradingManagementCluster); err != nil {
+		return err
+	}
+
+	if spec.Bundles != nil && spec.Bundles.Spec.Number < installed.Spec.Number {
+		return fmt.Errorf(
+			"bundle %d used by this CLI is older than bundle %d installed in management cluster %s, downgrades are not supported: use eksctl anywhere %s or newer",
+			spec.Bundles.Spec.Number, installed.Spec.Number, managementCluster.Name, installed.Spec.CliMinVersion,
+		)
+	}
+
+	err = validateEksaControllerImage(ctx, k, managementCluster, installed)
+	if err != nil && upgradingManagementCluster {
+		logger.Info(fmt.Sprintf("Warning: %v, the upgrade will replace it", err))
+		return nil
+	}
+	return err
+}
+
+func validateCliVersionForBundles(cli *semver.Version, installed *releasev1alpha1.Bundles, managementClusterName string, upgradingManagementCluster bool) error {
+	if installed.Spec.CliMinVersion != "" {
+		min, err := semver.New(installed.Spec.CliMinVersion)
+		if err != nil {
+			return fmt.Errorf("invalid cliMinVersion in installed bundle %d: %v", installed.Spec.Number, err)
+		}
+		if cli.LessThan(min) {
+			return fmt.Errorf(
+				"eksctl anywhere v%d.%d.%d is older than the versions supported by bundle %d installed in management cluster %s (%s - %s): use eksctl anywhere %s or newer",
+				cli.Major, cli.Minor, cli.Patch, installed.Spec.Number, managementClusterName, installed.Spec.CliMinVersion, installed.Spec.CliMaxVersion, installed.Spec.CliMinVersion,
+			)
+		}
+	}
+
+	if installed.Spec.CliMaxVersion != "" && !upgradingManagementCluster {
+		max, err := semver.New(installed.Spec.CliMaxVersion)
+		if err != nil {
+			return fmt.Errorf("invalid cliMaxVersion in installed bundle %d: %v", installed.Spec.Number, err)
+		}
+		if cli.GreaterThan(max) {
+			return fmt.Errorf(
+				"eksctl anywhere v%d.%d.%d is newer than the versions supported by bundle %d installed in management cluster %s (%s - %s): upgrade management cluster %s with this CLI first or use eksctl anywhere %s",
+				cli.Major, cli.Minor, cli.Patch, installed.Spec.Number, managementClusterName, installed.Spec.CliMinVersion, installed.Spec.CliMaxVersion, managementClusterName, installed.Spec.CliMaxVersion,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateEksaControllerImage(ctx context.Context, k KubectlClient, managementCluster *types.Cluster, installed *releasev1alpha1.Bundles) error {
+	deployments, err := k.GetDeployments(ctx, executables.WithCluster(managementCluster), executables.WithNamespace(constants.EksaSystemNamespace))
+	if err != nil {
+		return fmt.Errorf("error getting deployments in namespace %s: %v", constants.EksaSystemNamespace, err)
+	}
+
+	var image string
+	for _, d := range deployments {
+		if d.Name != eksaControllerDeploymentName {
+			continue
+		}
+		for _, c := range d.Spec.Template.Spec.Containers {
+			if c.Name == eksaControllerContainerName {
+				image = c.Image
+			}
+		}
+	}
+	if image == "" {
+		return fmt.Errorf("failed to find EKS-A controller deployment %s in namespace %s", eksaControllerDeploymentName, constants.EksaSystemNamespace)
+	}
+
+	for _, vb := range installed.Spec.VersionsBundles {
+		if vb.Eksa.ClusterController.URI == image {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"EKS-A controller image %s in management cluster %s doesn't match bundle %d installed there, the last management cluster upgrade didn't complete: re-run eksctl anywhere upgrade cluster for management cluster %s",
+		image, managementCluster.Name, installed.Spec.Number, managementCluster.Name,
+	)
+}
@@ -0,0 +1,156 @@
+package validations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
+	"github.com/aws/eks-anywhere/pkg/validations/mocks"
+	releasev1alpha1 "github.com/aws/eks-anywhere/release/api/v1alpha1"
+)
+
+const controllerImage = "public.ecr.aws/eks-anywhere/eks-anywhere-cluster-controller:v0.6.0-eks-a-5"
+
+type compatibilityTest struct {
+	*WithT
+	ctx               context.Context
+	kubectl           *mocks.MockKubectlClient
+	managementCluster *types.Cluster
+	spec              *cluster.Spec
+	installed         *releasev1alpha1.Bundles
+}
+
+func newCompatibilityTest(t *testing.T) *compatibilityTest {
+	tt := &compatibilityTest{
+		WithT:             NewWithT(t),
+		ctx:               context.Background(),
+		kubectl:           mocks.NewMockKubectlClient(gomock.NewController(t)),
+		managementCluster: &types.Cluster{Name: "management", KubeconfigFile: "management.kubeconfig"},
+		installed: &releasev1alpha1.Bundles{
+			Spec: releasev1alpha1.BundlesSpec{
+				Number:        5,
+				CliMinVersion: "v0.6.0",
+				CliMaxVersion: "v0.6.0",
+				VersionsBundles: []releasev1alpha1.VersionsBundle{
+					{Eksa: releasev1alpha1.EksaBundle{ClusterController: releasev1alpha1.Image{URI: controllerImage}}},
+				},
+			},
+		},
+	}
+	tt.spec = test.NewClusterSpec(func(s *cluster.Spec) {
+		s.Name = "workload"
+		s.SetManagedBy("management")
+		s.Bundles.Spec.Number = 5
+	})
+
+	tt.kubectl.EXPECT().GetEksaCluster(tt.ctx, tt.managementCluster, "management").Return(
+		&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "management", Namespace: "default"}}, nil,
+	)
+	tt.kubectl.EXPECT().GetBundles(tt.ctx, "management.kubeconfig", "management", "default").Return(tt.installed, nil)
+	return tt
+}
+
+func (tt *compatibilityTest) expectControllerImage(image string) {
+	tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return([]appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "eksa-controller-manager"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager", Image: image}}},
+				},
+			},
+		},
+	}, nil)
+}
+
+func (tt *compatibilityTest) validate(cliVersion string) error {
+	return validations.ValidateManagementClusterCompatibility(tt.ctx, tt.kubectl, tt.managementCluster, tt.spec, cliVersion)
+}
+
+func TestValidateManagementClusterCompatibilitySuccess(t *testing.T) {
+	tt := newCompatibilityTest(t)
+	tt.expectControllerImage(controllerImage)
+
+	tt.Expect(tt.validate("v0.6.0")).To(Succeed())
+}
+
+func TestValidateManagementClusterCompatibilityCliTooOld(t *testing.T) {
+	tt := newCompatibilityTest(t)
+
+	tt.Expect(tt.validate("v0.5.2")).To(MatchError(
+		"eksctl anywhere v0.5.2 is older than the versions supported by bundle 5 installed in management cluster management (v0.6.0 - v0.6.0): use eksctl anywhere v0.6.0 or newer",
+	))
+}
+
+func TestValidateManagementClusterCompatibilityCliTooNew(t *testing.T) {
+	tt := newCompatibilityTest(t)
+
+	tt.Expect(tt.validate("v0.7.0")).To(MatchError(ContainSubstring(
+		"upgrade management cluster management with this CLI first or use eksctl anywhere v0.6.0",
+	)))
+}
+
+func TestValidateManagementClusterCompatibilityUpgradingManagementCluster(t *testing.T) {
+	tt := newCompatibilityTest(t)
+	tt.spec.Name = "management"
+	tt.spec.SetSelfManaged()
+	tt.spec.Bundles.Spec.Number = 6
+	tt.expectControllerImage("public.ecr.aws/eks-anywhere/eks-anywhere-cluster-controller:v0.5.2-eks-a-4")
+
+	tt.Expect(tt.validate("v0.7.0")).To(Succeed())
+}
+
+func TestValidateManagementClusterCompatibilityBundleDowngrade(t *testing.T) {
+	tt := newCompatibilityTest(t)
+	tt.spec.Bundles.Spec.Number = 4
+
+	tt.Expect(tt.validate("v0.6.0")).To(MatchError(ContainSubstring(
+		"bundle 4 used by this CLI is older than bundle 5 installed in management cluster management, downgrades are not supported",
+	)))
+}
+
+func TestValidateManagementClusterCompatibilityControllerMismatch(t *testing.T) {
+	tt := newCompatibilityTest(t)
+	tt.expectControllerImage("public.ecr.aws/eks-anywhere/eks-anywhere-cluster-controller:v0.5.2-eks-a-4")
+
+	tt.Expect(tt.validate("v0.6.0")).To(MatchError(ContainSubstring(
+		"the last management cluster upgrade didn't complete: re-run eksctl anywhere upgrade cluster for management cluster management",
+	)))
+}
+
+func TestValidateManagementClusterCompatibilityNoController(t *testing.T) {
+	tt := newCompatibilityTest(t)
+	tt.kubectl.EXPECT().GetDeployments(tt.ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	tt.Expect(tt.validate("v0.6.0")).To(MatchError("failed to find EKS-A controller deployment eksa-controller-manager in namespace eksa-system"))
+}
+
+func TestValidateManagementClusterCompatibilityBundlesError(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	kubectl := mocks.NewMockKubectlClient(gomock.NewController(t))
+	managementCluster := &types.Cluster{Name: "management", KubeconfigFile: "management.kubeconfig"}
+	kubectl.EXPECT().GetEksaCluster(ctx, managementCluster, "management").Return(&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "management"}}, nil)
+	kubectl.EXPECT().GetBundles(ctx, "management.kubeconfig", "management", "").Return(nil, errors.New("not found"))
+
+	err := validations.ValidateManagementClusterCompatibility(ctx, kubectl, managementCluster, test.NewClusterSpec(), "v0.6.0")
+	g.Expect(err).To(MatchError("error getting the Bundles installed in management cluster management: not found"))
+}
+
+func TestValidateManagementClusterCompatibilityUnknownCliVersion(t *testing.T) {
+	g := NewWithT(t)
+	kubectl := mocks.NewMockKubectlClient(gomock.NewController(t))
+
+	g.Expect(validations.ValidateManagementClusterCompatibility(context.Background(), kubectl, &types.Cluster{}, test.NewClusterSpec(), "")).To(Succeed())
+}
@@ -7,57 +7,115 @@ import (
 	"github.com/aws/eks-anywhere/pkg/validations"
 )
 
+const validateManagementClusterCRDs = "validate management cluster has eksa crds"
+
 func (u *CreateValidations) PreflightValidations(ctx context.Context) (err error) {
+	return validations.RunChecks(u.PreflightChecks(ctx)...)
+}
+
+// PreflightChecks returns the create validations. Validations that read EKS-A objects from the
+// management cluster depend on its CRDs being installed
+func (u *CreateValidations) PreflightChecks(ctx context.Context) []validations.Check {
 	k := u.Opts.Kubectl
 
 	targetCluster := &types.Cluster{
 		Name:           u.Opts.WorkloadCluster.Name,
 		KubeconfigFile: u.Opts.ManagementCluster.KubeconfigFile,
 	}
-	var createValidations []validations.ValidationResult
+	createValidations := []validations.Check{
+		{
+			Name: "validate proxy connectivity",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "validate proxy connectivity",
+					Remediation: "ensure the proxy is reachable and can reach the release endpoints, or add them to noProxy",
+					Err:         ValidateProxyConnectivity(ctx, u.Opts.Spec),
+				}
+			},
+		},
+		{
+			Name: "validate registry mirror authentication",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "validate registry mirror authentication",
+					Remediation: "ensure the registry mirror credentials are valid and the mirror endpoints are reachable",
+					Err:         ValidateRegistryMirrorAuthentication(ctx, u.Opts.Spec),
+				}
+			},
+		},
+	}
 
 	if u.Opts.Spec.IsManaged() {
 		createValidations = append(
 			createValidations,
-			validations.ValidationResult{
-				Name:        "validate cluster name",
-				Remediation: "",
-				Err:         ValidateClusterNameIsUnique(ctx, k, targetCluster, u.Opts.Spec.Name),
-			},
-			validations.ValidationResult{
-				Name:        "validate taints support",
-				Remediation: "",
-				Err:         ValidateTaintsSupport(ctx, u.Opts.Spec),
-			},
-			validations.ValidationResult{
-				Name:        "validate gitops",
-				Remediation: "",
-				Err:         ValidateGitOps(ctx, k, u.Opts.ManagementCluster, u.Opts.Spec),
-			},
-			validations.ValidationResult{
-				Name:        "validate identityproviders name",
-				Remediation: "",
-				Err:         ValidateIdentityProviderNameIsUnique(ctx, k, targetCluster, u.Opts.Spec),
-			},
-			validations.ValidationResult{
-				Name:        "validate management cluster has eksa crds",
-				Remediation: "",
-				Err:         ValidateManagementCluster(ctx, k, targetCluster),
+			validations.Check{
+				Name:      "validate cluster name",
+				DependsOn: []string{validateManagementClusterCRDs},
+				Critical:  true,
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        "validate cluster name",
+						Remediation: "",
+						Err:         ValidateClusterNameIsUnique(ctx, k, targetCluster, u.Opts.Spec.Name),
+					}
+				},
+			},
+			validations.Check{
+				Name: "validate taints support",
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        "validate taints support",
+						Remediation: "",
+						Err:         ValidateTaintsSupport(ctx, u.Opts.Spec),
+					}
+				},
+			},
+			validations.Check{
+				Name:      "validate gitops",
+				DependsOn: []string{validateManagementClusterCRDs},
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        "validate gitops",
+						Remediation: "",
+						Err:         ValidateGitOps(ctx, k, u.Opts.ManagementCluster, u.Opts.Spec),
+					}
+				},
+			},
+			validations.Check{
+				Name:      "validate identityproviders name",
+				DependsOn: []string{validateManagementClusterCRDs},
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        "validate identityproviders name",
+						Remediation: "",
+						Err:         ValidateIdentityProviderNameIsUnique(ctx, k, targetCluster, u.Opts.Spec),
+					}
+				},
+			},
+			validations.Check{
+				Name:     validateManagementClusterCRDs,
+				Critical: true,
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        validateManagementClusterCRDs,
+						Remediation: "",
+						Err:         ValidateManagementCluster(ctx, k, targetCluster),
+					}
+				},
+			},
+			validations.Check{
+				Name:      "validate management cluster compatibility",
+				DependsOn: []string{validateManagementClusterCRDs},
+				Validation: func() *validations.ValidationResult {
+					return &validations.ValidationResult{
+						Name:        "validate management cluster compatibility",
+						Remediation: "use the CLI version required by the Bundles installed in the management cluster",
+						Err:         validations.ValidateManagementClusterCompatibility(ctx, k, u.Opts.ManagementCluster, u.Opts.Spec, u.Opts.CliVersion),
+					}
+				},
 			},
 		)
 	}
 
-	var errs []string
-	for _, validation := range createValidations {
-		if validation.Err != nil {
-			errs = append(errs, validation.Err.Error())
-		} else {
-			validation.LogPass()
-		}
-	}
-
-	if len(errs) > 0 {
-		return &validations.ValidationError{Errs: errs}
-	}
-	return nil
+	return createValidations
 }
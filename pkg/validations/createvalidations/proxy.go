@@ -0,0 +1,41 @@
+package createvalidations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/proxy"
+)
+
+// ValidateProxyConnectivity checks that the release and bundle artifact endpoints used during
+// cluster creation are reachable with the cluster proxy configuration.
+func ValidateProxyConnectivity(ctx context.Context, spec *cluster.Spec) error {
+	if spec.Spec.ProxyConfiguration == nil {
+		logger.V(5).Info("skipping ValidateProxyConnectivity")
+		return nil
+	}
+
+	return proxy.NewConnectivityValidator(proxy.New(spec.Cluster)).Validate(ctx, proxyConnectivityTargets(spec)...)
+}
+
+func proxyConnectivityTargets(spec *cluster.Spec) []proxy.Target {
+	candidates := []proxy.Target{
+		{Name: "release manifest", URL: spec.GetReleaseManifestUrl()},
+	}
+	if spec.VersionsBundle != nil && spec.VersionsBundle.VersionsBundle != nil {
+		candidates = append(candidates,
+			proxy.Target{Name: "eks-a components", URL: spec.VersionsBundle.Eksa.Components.URI},
+			proxy.Target{Name: "cilium manifest", URL: spec.VersionsBundle.Cilium.Manifest.URI},
+		)
+	}
+
+	var targets []proxy.Target
+	for _, t := range candidates {
+		if strings.HasPrefix(t.URL, "http://") || strings.HasPrefix(t.URL, "https://") {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,69 @@
+package createvalidations_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/validations/createvalidations"
+)
+
+func TestValidateProxyConnectivity(t *testing.T) {
+	p := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "releases.example.com" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer p.Close()
+
+	tests := []struct {
+		name        string
+		proxyConfig *v1alpha1.ProxyConfiguration
+		manifestURL string
+		wantErr     string
+	}{
+		{
+			name:        "no proxy configuration",
+			proxyConfig: nil,
+			manifestURL: "http://unreachable.example.com/manifest.yaml",
+		},
+		{
+			name:        "release manifest reachable through proxy",
+			proxyConfig: &v1alpha1.ProxyConfiguration{HttpProxy: p.URL, HttpsProxy: p.URL},
+			manifestURL: "http://releases.example.com/manifest.yaml",
+		},
+		{
+			name:        "local release manifest",
+			proxyConfig: &v1alpha1.ProxyConfiguration{HttpProxy: p.URL, HttpsProxy: p.URL},
+			manifestURL: "manifests/releases.yaml",
+		},
+		{
+			name:        "release manifest not reachable through proxy",
+			proxyConfig: &v1alpha1.ProxyConfiguration{HttpProxy: p.URL, HttpsProxy: p.URL},
+			manifestURL: "http://unreachable.example.com/manifest.yaml",
+			wantErr:     "release manifest: unreachable.example.com is not reachable through proxy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := cluster.NewSpec(cluster.WithReleasesManifest(tt.manifestURL))
+			spec.Cluster = v1alpha1.NewCluster("test-cluster")
+			spec.Cluster.Spec.ProxyConfiguration = tt.proxyConfig
+
+			err := createvalidations.ValidateProxyConnectivity(context.Background(), spec)
+			if tt.wantErr == "" {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			}
+		})
+	}
+}
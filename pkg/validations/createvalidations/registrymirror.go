@@ -0,0 +1,20 @@
+package createvalidations
+
+import (
+	"context"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/logger"
+	"github.com/aws/eks-anywhere/pkg/registrymirror"
+)
+
+// ValidateRegistryMirrorAuthentication checks that the registry mirror endpoints accept the configured credentials.
+func ValidateRegistryMirrorAuthentication(ctx context.Context, spec *cluster.Spec) error {
+	mirror := spec.RegistryMirror()
+	if mirror == nil || mirror.Credentials == nil {
+		logger.V(5).Info("skipping ValidateRegistryMirrorAuthentication")
+		return nil
+	}
+
+	return registrymirror.ValidateAuthentication(ctx, mirror)
+}
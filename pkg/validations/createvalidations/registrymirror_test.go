@@ -0,0 +1,64 @@
+package createvalidations_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/validations/createvalidations"
+)
+
+func TestValidateRegistryMirrorAuthentication(t *testing.T) {
+	mirror := test.NewRegistry(t, test.WithRegistryBasicAuth("user", "password"))
+
+	tests := []struct {
+		name         string
+		mirrorConfig *v1alpha1.RegistryMirrorConfiguration
+		wantErr      string
+	}{
+		{
+			name:         "no registry mirror",
+			mirrorConfig: nil,
+		},
+		{
+			name:         "no authentication",
+			mirrorConfig: &v1alpha1.RegistryMirrorConfiguration{Endpoint: "unreachable.example.com"},
+		},
+		{
+			name: "valid credentials",
+			mirrorConfig: &v1alpha1.RegistryMirrorConfiguration{
+				Endpoint:       mirror.Host(),
+				CACertContent:  string(mirror.CACert()),
+				Authentication: &v1alpha1.RegistryMirrorAuthentication{Username: "user", Password: "password"},
+			},
+		},
+		{
+			name: "invalid credentials",
+			mirrorConfig: &v1alpha1.RegistryMirrorConfiguration{
+				Endpoint:       mirror.Host(),
+				CACertContent:  string(mirror.CACert()),
+				Authentication: &v1alpha1.RegistryMirrorAuthentication{Username: "user", Password: "wrong"},
+			},
+			wantErr: "registry mirror authentication failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := cluster.NewSpec()
+			spec.Cluster = v1alpha1.NewCluster("test-cluster")
+			spec.Cluster.Spec.RegistryMirrorConfiguration = tt.mirrorConfig
+
+			err := createvalidations.ValidateRegistryMirrorAuthentication(context.Background(), spec)
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			}
+		})
+	}
+}
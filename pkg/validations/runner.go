@@ -1,36 +1,392 @@
 package validations
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
 
 var errRunnerValidation = errors.New("validations failed")
 
+const (
+	// DefaultConcurrency is the number of validations run at the same time by default
+	DefaultConcurrency = 8
+	// DefaultTimeout is the time a validation can run for before it's reported as failed, unless its check sets a different one
+	DefaultTimeout = 5 * time.Minute
+)
+
 type Validation func() *ValidationResult
 
+// Check is a validation with the information the runner needs to schedule it
+type Check struct {
+	// Name identifies the check for dependencies and skipping. Checks without a name can't be referenced
+	Name       string
+	Validation Validation
+	// DependsOn are the names of the checks that need to pass before this one runs.
+	// If any of them fails, this check is skipped
+	DependsOn []string
+	// Timeout overrides the runner timeout for this check
+	Timeout time.Duration
+	// Warning reports the failure of the check as a warning that doesn't fail the validations
+	Warning bool
+	// Critical checks can't be skipped
+	Critical bool
+}
+
 type Runner struct {
-	validations []Validation
+	checks       []Check
+	concurrency  int
+	timeout      time.Duration
+	skip         []string
+	reportWriter io.Writer
+	results      []*ValidationResult
+	durations    []time.Duration
+}
+
+type RunnerOpt func(*Runner)
+
+// WithConcurrency sets the maximum number of validations run at the same time
+func WithConcurrency(concurrency int) RunnerOpt {
+	return func(r *Runner) {
+		r.concurrency = concurrency
+	}
+}
+
+// WithTimeout sets the time a validation can run for before it's reported as failed
+func WithTimeout(timeout time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.timeout = timeout
+	}
+}
+
+// WithSkippedValidations skips the checks with names. Critical checks can't be skipped
+func WithSkippedValidations(names ...string) RunnerOpt {
+	return func(r *Runner) {
+		r.skip = append(r.skip, names...)
+	}
 }
 
-func NewRunner() *Runner {
-	return &Runner{validations: make([]Validation, 0)}
+// WithJSONReport writes the Report of the run to w as json, instead of logging the result of each validation
+func WithJSONReport(w io.Writer) RunnerOpt {
+	return func(r *Runner) {
+		r.reportWriter = w
+	}
 }
 
+func NewRunner(opts ...RunnerOpt) *Runner {
+	r := &Runner{
+		checks:      make([]Check, 0),
+		concurrency: DefaultConcurrency,
+		timeout:     DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.concurrency < 1 {
+		r.concurrency = 1
+	}
+	return r
+}
+
+// Register adds validations without a name or dependencies
 func (r *Runner) Register(validations ...Validation) {
-	r.validations = append(r.validations, validations...)
+	for _, v := range validations {
+		r.checks = append(r.checks, Check{Validation: v})
+	}
 }
 
+// RegisterChecks adds checks, which can depend on any other check registered before Run
+func (r *Runner) RegisterChecks(checks ...Check) {
+	r.checks = append(r.checks, checks...)
+}
+
+// Run runs the registered validations concurrently, each one as soon as its dependencies have passed.
+// A validation that doesn't finish before its timeout is reported as failed, although it can't be stopped.
+// The results are reported in the order the validations were registered once all of them have finished
 func (r *Runner) Run() error {
-	failed := false
-	for _, v := range r.validations {
-		result := v()
-		result.Report()
-		if result.Err != nil {
-			failed = true
+	if err := r.run(); err != nil {
+		return err
+	}
+	return r.report()
+}
+
+// RunChecks runs checks concurrently like Runner.Run and returns a ValidationError with the errors of the
+// failed checks in the order they were passed
+func RunChecks(checks ...Check) error {
+	r := NewRunner()
+	r.RegisterChecks(checks...)
+	if err := r.run(); err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, result := range r.results {
+		switch result.Status() {
+		case StatusPassed:
+			result.LogPass()
+		case StatusError:
+			errs = append(errs, result.Err.Error())
+		default:
+			result.Report()
 		}
 	}
 
-	if failed {
-		return errRunnerValidation
+	if len(errs) > 0 {
+		return &ValidationError{Errs: errs}
+	}
+	return nil
+}
+
+func (r *Runner) run() error {
+	skipped, err := r.plan()
+	if err != nil {
+		return err
+	}
+
+	r.results = make([]*ValidationResult, len(r.checks))
+	r.durations = make([]time.Duration, len(r.checks))
+	byName := map[string]int{}
+	for i, c := range r.checks {
+		if c.Name != "" {
+			byName[c.Name] = i
+		}
+	}
+
+	type finished struct {
+		index    int
+		result   *ValidationResult
+		duration time.Duration
+	}
+	done := make(chan finished)
+	sem := make(chan struct{}, r.concurrency)
+	started := make([]bool, len(r.checks))
+	running := 0
+	pending := len(r.checks)
+
+	for pending > 0 {
+		for i, c := range r.checks {
+			if started[i] {
+				continue
+			}
+			ready, reason := r.dependenciesReady(c, byName, skipped)
+			if !ready {
+				continue
+			}
+
+			started[i] = true
+			switch {
+			case skipped[c.Name]:
+				r.results[i] = &ValidationResult{Name: c.Name, Skipped: true, Err: errors.New("skipped by user")}
+				pending--
+			case reason != "":
+				r.results[i] = &ValidationResult{Name: c.Name, Skipped: true, Err: errors.New(reason)}
+				pending--
+			default:
+				running++
+				go func(i int, c Check) {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+					start := time.Now()
+					result := r.runCheck(c)
+					done <- finished{index: i, result: result, duration: time.Since(start)}
+				}(i, c)
+			}
+		}
+
+		if running == 0 {
+			// Every check left is waiting on a check that was just resolved, schedule them in the next iteration
+			continue
+		}
+		f := <-done
+		running--
+		pending--
+		r.results[f.index] = f.result
+		r.durations[f.index] = f.duration
 	}
 
 	return nil
 }
+
+// plan validates the dependencies and the checks to skip and returns the skipped checks
+func (r *Runner) plan() (map[string]bool, error) {
+	checks := map[string]Check{}
+	for _, c := range r.checks {
+		if c.Validation == nil {
+			return nil, fmt.Errorf("validation %s has no function", c.Name)
+		}
+		if c.Name == "" {
+			continue
+		}
+		if _, ok := checks[c.Name]; ok {
+			return nil, fmt.Errorf("validation %s is registered more than once", c.Name)
+		}
+		checks[c.Name] = c
+	}
+
+	for _, c := range r.checks {
+		for _, d := range c.DependsOn {
+			if _, ok := checks[d]; !ok {
+				return nil, fmt.Errorf("validation %s depends on unknown validation %s", c.Name, d)
+			}
+		}
+	}
+
+	visiting := map[string]int{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch visiting[name] {
+		case 1:
+			return fmt.Errorf("circular dependency between validations involving %s", name)
+		case 2:
+			return nil
+		}
+		visiting[name] = 1
+		for _, d := range checks[name].DependsOn {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		visiting[name] = 2
+		return nil
+	}
+	for name := range checks {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	skipped := map[string]bool{}
+	for _, name := range r.skip {
+		c, ok := checks[name]
+		if !ok {
+			return nil, fmt.Errorf("can't skip unknown validation %s", name)
+		}
+		if c.Critical {
+			return nil, fmt.Errorf("validation %s is critical and can't be skipped", name)
+		}
+		skipped[name] = true
+	}
+	return skipped, nil
+}
+
+// dependenciesReady returns true if all the dependencies of c have finished, with the reason to skip c if any of them didn't pass.
+// Dependencies skipped by the user count as passed
+func (r *Runner) dependenciesReady(c Check, byName map[string]int, userSkipped map[string]bool) (ready bool, skipReason string) {
+	failed := []string{}
+	for _, d := range c.DependsOn {
+		result := r.results[byName[d]]
+		if result == nil {
+			return false, ""
+		}
+		if userSkipped[d] {
+			continue
+		}
+		if status := result.Status(); status == StatusError || status == StatusSkipped {
+			failed = append(failed, d)
+		}
+	}
+	if len(failed) > 0 {
+		return true, fmt.Sprintf("depends on failed validations: %s", strings.Join(failed, ", "))
+	}
+	return true, ""
+}
+
+func (r *Runner) runCheck(c Check) *ValidationResult {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = r.timeout
+	}
+
+	resultCh := make(chan *ValidationResult, 1)
+	go func() {
+		resultCh <- c.Validation()
+	}()
+
+	var result *ValidationResult
+	if timeout <= 0 {
+		result = <-resultCh
+	} else {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case result = <-resultCh:
+		case <-timer.C:
+			result = &ValidationResult{Err: fmt.Errorf("validation timed out after %s", timeout)}
+		}
+	}
+
+	if result.Name == "" {
+		result.Name = c.Name
+	}
+	if c.Warning {
+		result.Warning = true
+	}
+	return result
+}
+
+func (r *Runner) report() error {
+	if r.reportWriter != nil {
+		content, err := json.Marshal(r.Report())
+		if err != nil {
+			return fmt.Errorf("error marshalling validations report: %v", err)
+		}
+		if _, err = fmt.Fprintln(r.reportWriter, string(content)); err != nil {
+			return fmt.Errorf("error writing validations report: %v", err)
+		}
+	} else {
+		for _, result := range r.results {
+			result.Report()
+		}
+	}
+
+	for _, result := range r.results {
+		if result.Status() == StatusError {
+			return errRunnerValidation
+		}
+	}
+	return nil
+}
+
+// Report is the outcome of all the validations of a run
+type Report struct {
+	Passed  bool           `json:"passed"`
+	Results []ReportResult `json:"results"`
+}
+
+type ReportResult struct {
+	Name            string       `json:"name"`
+	Status          ResultStatus `json:"status"`
+	Error           string       `json:"error,omitempty"`
+	Remediation     string       `json:"remediation,omitempty"`
+	DurationSeconds float64      `json:"durationSeconds"`
+}
+
+// Report returns the results of the last Run in the order the validations were registered
+func (r *Runner) Report() *Report {
+	report := &Report{Passed: true, Results: make([]ReportResult, 0, len(r.results))}
+	for i, result := range r.results {
+		status := result.Status()
+		if status == StatusError {
+			report.Passed = false
+		}
+		// The name of the check is the one that can be skipped
+		name := r.checks[i].Name
+		if name == "" {
+			name = result.Name
+		}
+		reportResult := ReportResult{
+			Name:            name,
+			Status:          status,
+			Remediation:     result.Remediation,
+			DurationSeconds: r.durations[i].Seconds(),
+		}
+		if result.Err != nil {
+			reportResult.Error = result.Err.Error()
+		}
+		report.Results = append(report.Results, reportResult)
+	}
+	return report
+}
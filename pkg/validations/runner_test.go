@@ -1,8 +1,11 @@
 package validations_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -44,3 +47,171 @@ func TestRunnerRunSuccess(t *testing.T) {
 
 	g.Expect(r.Run()).To(Succeed())
 }
+
+func passing(name string) validations.Validation {
+	return func() *validations.ValidationResult {
+		return &validations.ValidationResult{Name: name}
+	}
+}
+
+func failing(name string) validations.Validation {
+	return func() *validations.ValidationResult {
+		return &validations.ValidationResult{Name: name, Err: errors.New("failed"), Remediation: "fix it"}
+	}
+}
+
+func TestRunnerRunChecksConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner(validations.WithConcurrency(2))
+	started := make(chan struct{})
+	r.RegisterChecks(
+		validations.Check{Name: "a", Validation: func() *validations.ValidationResult {
+			started <- struct{}{}
+			return &validations.ValidationResult{}
+		}},
+		validations.Check{Name: "b", Validation: func() *validations.ValidationResult {
+			<-started
+			return &validations.ValidationResult{}
+		}},
+	)
+
+	g.Expect(r.Run()).To(Succeed())
+}
+
+func TestRunnerRunChecksWithDependencies(t *testing.T) {
+	g := NewWithT(t)
+	var out bytes.Buffer
+	r := validations.NewRunner(validations.WithJSONReport(&out))
+	aDone := false
+	r.RegisterChecks(
+		validations.Check{Name: "b", DependsOn: []string{"a"}, Validation: func() *validations.ValidationResult {
+			if !aDone {
+				return &validations.ValidationResult{Err: errors.New("a didn't run first")}
+			}
+			return &validations.ValidationResult{}
+		}},
+		validations.Check{Name: "a", Validation: func() *validations.ValidationResult {
+			aDone = true
+			return &validations.ValidationResult{}
+		}},
+		validations.Check{Name: "c", Validation: failing("c")},
+		validations.Check{Name: "d", DependsOn: []string{"c"}, Validation: passing("d")},
+		validations.Check{Name: "e", DependsOn: []string{"d"}, Validation: passing("e")},
+	)
+
+	g.Expect(r.Run()).To(MatchError("validations failed"))
+	report := r.Report()
+	g.Expect(report.Passed).To(BeFalse())
+	g.Expect(statuses(report)).To(Equal(map[string]validations.ResultStatus{
+		"b": validations.StatusPassed,
+		"a": validations.StatusPassed,
+		"c": validations.StatusError,
+		"d": validations.StatusSkipped,
+		"e": validations.StatusSkipped,
+	}))
+	g.Expect(report.Results[3].Error).To(Equal("depends on failed validations: c"))
+	g.Expect(report.Results[4].Error).To(Equal("depends on failed validations: d"))
+}
+
+func TestRunnerRunTimeout(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner(validations.WithTimeout(time.Millisecond))
+	block := make(chan struct{})
+	defer close(block)
+	r.RegisterChecks(validations.Check{Name: "slow", Validation: func() *validations.ValidationResult {
+		<-block
+		return &validations.ValidationResult{}
+	}})
+
+	g.Expect(r.Run()).To(MatchError("validations failed"))
+	g.Expect(r.Report().Results[0].Error).To(Equal("validation timed out after 1ms"))
+}
+
+func TestRunnerRunWarning(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner()
+	r.RegisterChecks(validations.Check{Name: "a", Warning: true, Validation: failing("a")})
+
+	g.Expect(r.Run()).To(Succeed())
+	g.Expect(r.Report().Results[0].Status).To(Equal(validations.StatusWarning))
+}
+
+func TestRunnerRunSkippedValidations(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner(validations.WithSkippedValidations("a"))
+	r.RegisterChecks(
+		validations.Check{Name: "a", Validation: failing("a")},
+		validations.Check{Name: "b", DependsOn: []string{"a"}, Validation: passing("b")},
+	)
+
+	g.Expect(r.Run()).To(Succeed())
+	g.Expect(statuses(r.Report())).To(Equal(map[string]validations.ResultStatus{
+		"a": validations.StatusSkipped,
+		"b": validations.StatusPassed,
+	}))
+}
+
+func TestRunnerRunSkipCriticalValidation(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner(validations.WithSkippedValidations("a"))
+	r.RegisterChecks(validations.Check{Name: "a", Critical: true, Validation: passing("a")})
+
+	g.Expect(r.Run()).To(MatchError("validation a is critical and can't be skipped"))
+}
+
+func TestRunnerRunSkipUnknownValidation(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner(validations.WithSkippedValidations("b"))
+	r.RegisterChecks(validations.Check{Name: "a", Validation: passing("a")})
+
+	g.Expect(r.Run()).To(MatchError("can't skip unknown validation b"))
+}
+
+func TestRunnerRunCircularDependency(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner()
+	r.RegisterChecks(
+		validations.Check{Name: "a", DependsOn: []string{"b"}, Validation: passing("a")},
+		validations.Check{Name: "b", DependsOn: []string{"a"}, Validation: passing("b")},
+	)
+
+	g.Expect(r.Run()).To(MatchError(ContainSubstring("circular dependency between validations")))
+}
+
+func TestRunnerRunUnknownDependency(t *testing.T) {
+	g := NewWithT(t)
+	r := validations.NewRunner()
+	r.RegisterChecks(validations.Check{Name: "a", DependsOn: []string{"b"}, Validation: passing("a")})
+
+	g.Expect(r.Run()).To(MatchError("validation a depends on unknown validation b"))
+}
+
+func TestRunnerRunJSONReport(t *testing.T) {
+	g := NewWithT(t)
+	var out bytes.Buffer
+	r := validations.NewRunner(validations.WithJSONReport(&out))
+	r.RegisterChecks(
+		validations.Check{Name: "a", Validation: passing("a")},
+		validations.Check{Name: "b", Validation: failing("b")},
+	)
+
+	g.Expect(r.Run()).To(MatchError("validations failed"))
+	report := &validations.Report{}
+	g.Expect(json.Unmarshal(out.Bytes(), report)).To(Succeed())
+	g.Expect(report.Passed).To(BeFalse())
+	g.Expect(report.Results).To(HaveLen(2))
+	g.Expect(report.Results[0].Name).To(Equal("a"))
+	g.Expect(report.Results[0].Status).To(Equal(validations.StatusPassed))
+	g.Expect(report.Results[1].Name).To(Equal("b"))
+	g.Expect(report.Results[1].Status).To(Equal(validations.StatusError))
+	g.Expect(report.Results[1].Error).To(Equal("failed"))
+	g.Expect(report.Results[1].Remediation).To(Equal("fix it"))
+}
+
+func statuses(report *validations.Report) map[string]validations.ResultStatus {
+	s := map[string]validations.ResultStatus{}
+	for _, r := range report.Results {
+		s[r.Name] = r.Status
+	}
+	return s
+}
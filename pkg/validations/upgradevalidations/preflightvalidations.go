@@ -11,68 +11,109 @@ import (
 )
 
 func (u *UpgradeValidations) PreflightValidations(ctx context.Context) (err error) {
+	return validations.RunChecks(u.PreflightChecks(ctx)...)
+}
+
+// PreflightChecks returns the upgrade validations. They don't depend on each other, so all of them can run at the same time
+func (u *UpgradeValidations) PreflightChecks(ctx context.Context) []validations.Check {
 	k := u.Opts.Kubectl
 
 	targetCluster := &types.Cluster{
 		Name:           u.Opts.WorkloadCluster.Name,
 		KubeconfigFile: u.Opts.ManagementCluster.KubeconfigFile,
 	}
-	var upgradeValidations []validations.ValidationResult
-	upgradeValidations = append(
-		upgradeValidations,
-		validations.ValidationResult{
-			Name:        "validate taints support",
-			Remediation: "ensure TAINTS_SUPPORT env variable is set",
-			Err:         ValidateTaintsSupport(ctx, u.Opts.Spec),
+	return []validations.Check{
+		{
+			Name: "validate taints support",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "validate taints support",
+					Remediation: "ensure TAINTS_SUPPORT env variable is set",
+					Err:         ValidateTaintsSupport(ctx, u.Opts.Spec),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "control plane ready",
-			Remediation: fmt.Sprintf("ensure control plane nodes and pods for cluster %s are Ready", u.Opts.WorkloadCluster.Name),
-			Err:         k.ValidateControlPlaneNodes(ctx, targetCluster, targetCluster.Name),
+		{
+			Name: "control plane ready",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "control plane ready",
+					Remediation: fmt.Sprintf("ensure control plane nodes and pods for cluster %s are Ready", u.Opts.WorkloadCluster.Name),
+					Err:         k.ValidateControlPlaneNodes(ctx, targetCluster, targetCluster.Name),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "worker nodes ready",
-			Remediation: fmt.Sprintf("ensure machine deployments for cluster %s are Ready", u.Opts.WorkloadCluster.Name),
-			Err:         k.ValidateWorkerNodes(ctx, targetCluster, u.Opts.Spec.Name),
+		{
+			Name: "worker nodes ready",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "worker nodes ready",
+					Remediation: fmt.Sprintf("ensure machine deployments for cluster %s are Ready", u.Opts.WorkloadCluster.Name),
+					Err:         k.ValidateWorkerNodes(ctx, targetCluster, u.Opts.Spec.Name),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "nodes ready",
-			Remediation: fmt.Sprintf("check the Status of the control plane and worker nodes in cluster %s and verify they are Ready", u.Opts.WorkloadCluster.Name),
-			Err:         k.ValidateNodes(ctx, u.Opts.WorkloadCluster.KubeconfigFile),
+		{
+			Name: "nodes ready",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "nodes ready",
+					Remediation: fmt.Sprintf("check the Status of the control plane and worker nodes in cluster %s and verify they are Ready", u.Opts.WorkloadCluster.Name),
+					Err:         k.ValidateNodes(ctx, u.Opts.WorkloadCluster.KubeconfigFile),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "cluster CRDs ready",
-			Remediation: "",
-			Err:         k.ValidateClustersCRD(ctx, u.Opts.ManagementCluster),
+		{
+			Name: "cluster CRDs ready",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "cluster CRDs ready",
+					Remediation: "",
+					Err:         k.ValidateClustersCRD(ctx, u.Opts.ManagementCluster),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "cluster object present on workload cluster",
-			Remediation: fmt.Sprintf("ensure that the CAPI cluster object %s representing cluster %s is present", v1alpha3.GroupVersion, u.Opts.WorkloadCluster.Name),
-			Err:         ValidateClusterObjectExists(ctx, k, u.Opts.ManagementCluster),
+		{
+			Name: "validate management cluster compatibility",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "validate management cluster compatibility",
+					Remediation: "use the CLI version required by the Bundles installed in the management cluster",
+					Err:         validations.ValidateManagementClusterCompatibility(ctx, k, u.Opts.ManagementCluster, u.Opts.Spec, u.Opts.CliVersion),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "upgrade cluster kubernetes version increment",
-			Remediation: "ensure that the cluster kubernetes version is incremented by one minor version exactly (e.g. 1.18 -> 1.19)",
-			Err:         ValidateServerVersionSkew(ctx, u.Opts.Spec.Spec.KubernetesVersion, u.Opts.WorkloadCluster, k),
+		{
+			Name:     "cluster object present on workload cluster",
+			Critical: true,
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "cluster object present on workload cluster",
+					Remediation: fmt.Sprintf("ensure that the CAPI cluster object %s representing cluster %s is present", v1alpha3.GroupVersion, u.Opts.WorkloadCluster.Name),
+					Err:         ValidateClusterObjectExists(ctx, k, u.Opts.ManagementCluster),
+				}
+			},
 		},
-		validations.ValidationResult{
-			Name:        "validate immutable fields",
-			Remediation: "",
-			Err:         ValidateImmutableFields(ctx, k, targetCluster, u.Opts.Spec, u.Opts.Provider),
+		{
+			Name: "upgrade cluster kubernetes version increment",
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "upgrade cluster kubernetes version increment",
+					Remediation: "ensure that the cluster kubernetes version is incremented by one minor version exactly (e.g. 1.18 -> 1.19)",
+					Err:         ValidateServerVersionSkew(ctx, u.Opts.Spec.Spec.KubernetesVersion, u.Opts.WorkloadCluster, k),
+				}
+			},
+		},
+		{
+			Name:     "validate immutable fields",
+			Critical: true,
+			Validation: func() *validations.ValidationResult {
+				return &validations.ValidationResult{
+					Name:        "validate immutable fields",
+					Remediation: "",
+					Err:         ValidateImmutableFields(ctx, k, targetCluster, u.Opts.Spec, u.Opts.Provider),
+				}
+			},
 		},
-	)
-
-	var errs []string
-	for _, validation := range upgradeValidations {
-		if validation.Err != nil {
-			errs = append(errs, validation.Err.Error())
-		} else {
-			validation.LogPass()
-		}
-	}
-
-	if len(errs) > 0 {
-		return &validations.ValidationError{Errs: errs}
 	}
-	return nil
 }
@@ -0,0 +1,20 @@
+package upgradevalidations
+
+import (
+	"context"
+
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
+)
+
+// ValidateClusterWebhookRules checks that the EKS-A cluster webhook accepts the update of the existing cluster to spec.
+// These rules apply when the cluster object is changed with kubectl or GitOps, which is stricter than an upgrade with the CLI
+func ValidateClusterWebhookRules(ctx context.Context, k validations.KubectlClient, cluster *types.Cluster, spec *cluster.Spec) error {
+	prevSpec, err := k.GetEksaCluster(ctx, cluster, spec.Name)
+	if err != nil {
+		return err
+	}
+
+	return spec.Cluster.ValidateUpdate(prevSpec)
+}
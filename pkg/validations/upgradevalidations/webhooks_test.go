@@ -0,0 +1,71 @@
+package upgradevalidations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/eks-anywhere/internal/test"
+	"github.com/aws/eks-anywhere/pkg/api/v1alpha1"
+	"github.com/aws/eks-anywhere/pkg/cluster"
+	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations/mocks"
+	"github.com/aws/eks-anywhere/pkg/validations/upgradevalidations"
+)
+
+func TestValidateClusterWebhookRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(s *cluster.Spec)
+		wantErr string
+	}{
+		{
+			name:   "no changes",
+			update: func(s *cluster.Spec) {},
+		},
+		{
+			name: "self managed kubernetes version changed",
+			update: func(s *cluster.Spec) {
+				s.Spec.KubernetesVersion = v1alpha1.Kube121
+			},
+			wantErr: "spec.kubernetesVersion: Invalid value",
+		},
+		{
+			name: "cluster network changed",
+			update: func(s *cluster.Spec) {
+				s.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"10.0.0.0/16"}
+			},
+			wantErr: "spec.ClusterNetwork: Invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			k := mocks.NewMockKubectlClient(gomock.NewController(t))
+			managementCluster := &types.Cluster{Name: "test-cluster", KubeconfigFile: "kubeconfig"}
+			newSpec := func() *cluster.Spec {
+				return test.NewClusterSpec(func(s *cluster.Spec) {
+					s.Name = "test-cluster"
+					s.Spec.KubernetesVersion = v1alpha1.Kube120
+					s.Spec.ClusterNetwork.Pods.CidrBlocks = []string{"192.168.0.0/16"}
+					s.SetSelfManaged()
+				})
+			}
+			spec := newSpec()
+			tt.update(spec)
+
+			k.EXPECT().GetEksaCluster(ctx, managementCluster, "test-cluster").Return(newSpec().Cluster, nil)
+
+			err := upgradevalidations.ValidateClusterWebhookRules(ctx, k, managementCluster, spec)
+			if tt.wantErr == "" {
+				g.Expect(err).To(Succeed())
+			} else {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			}
+		})
+	}
+}
@@ -6,18 +6,50 @@ import (
 	"github.com/aws/eks-anywhere/pkg/logger"
 )
 
+type ResultStatus string
+
+const (
+	StatusPassed  ResultStatus = "passed"
+	StatusError   ResultStatus = "error"
+	StatusWarning ResultStatus = "warning"
+	StatusSkipped ResultStatus = "skipped"
+)
+
 type ValidationResult struct {
 	Name        string
 	Err         error
 	Remediation string
+	// Warning reports Err as a warning that doesn't fail the validations
+	Warning bool
+	// Skipped is set when the validation didn't run, because it was skipped explicitly or one of its
+	// dependencies failed. Err holds the reason
+	Skipped bool
+}
+
+func (v *ValidationResult) Status() ResultStatus {
+	switch {
+	case v.Skipped:
+		return StatusSkipped
+	case v.Err == nil:
+		return StatusPassed
+	case v.Warning:
+		return StatusWarning
+	default:
+		return StatusError
+	}
 }
 
 func (v *ValidationResult) Report() {
-	if v.Err != nil {
+	switch v.Status() {
+	case StatusError:
 		logger.MarkFail("Validation failed", "validation", v.Name, "error", v.Err, "remediation", v.Remediation)
-		return
+	case StatusWarning:
+		logger.MarkWarning("Validation warning", "validation", v.Name, "warning", v.Err, "remediation", v.Remediation)
+	case StatusSkipped:
+		logger.Info("Validation skipped", "validation", v.Name, "reason", v.Err)
+	default:
+		v.LogPass()
 	}
-	v.LogPass()
 }
 
 func (v *ValidationResult) LogPass() {
@@ -12,4 +12,6 @@ type Opts struct {
 	WorkloadCluster   *types.Cluster
 	ManagementCluster *types.Cluster
 	Provider          providers.Provider
+	// CliVersion is the version of the CLI running the validations, used to check it's compatible with the management cluster
+	CliVersion string
 }
@@ -2,7 +2,6 @@ package workflows
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/clustermarshaller"
@@ -21,6 +20,7 @@ type Create struct {
 	clusterManager interfaces.ClusterManager
 	addonManager   interfaces.AddonManager
 	writer         filewriter.FileWriter
+	validationOpts []validations.RunnerOpt
 }
 
 func NewCreate(bootstrapper interfaces.Bootstrapper, provider providers.Provider,
@@ -34,6 +34,12 @@ func NewCreate(bootstrapper interfaces.Bootstrapper, provider providers.Provider
 	}
 }
 
+// WithValidationOpts configures the runner of the setup and preflight validations
+func (c *Create) WithValidationOpts(opts ...validations.RunnerOpt) *Create {
+	c.validationOpts = append(c.validationOpts, opts...)
+	return c
+}
+
 func (c *Create) Run(ctx context.Context, clusterSpec *cluster.Spec, validator interfaces.Validator, forceCleanup bool) error {
 	if forceCleanup {
 		if err := c.bootstrapper.DeleteBootstrapCluster(ctx, &types.Cluster{
@@ -50,6 +56,7 @@ func (c *Create) Run(ctx context.Context, clusterSpec *cluster.Spec, validator i
 		ClusterSpec:    clusterSpec,
 		Writer:         c.writer,
 		Validations:    validator,
+		ValidationOpts: c.validationOpts,
 	}
 
 	if clusterSpec.ManagementCluster != nil {
@@ -134,10 +141,10 @@ func (s *CreateBootStrapClusterTask) Name() string {
 
 func (s *SetAndValidateTask) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
 	logger.Info("Performing setup and validations")
-	runner := validations.NewRunner()
-	runner.Register(s.providerValidation(ctx, commandContext)...)
-	runner.Register(commandContext.AddonManager.Validations(ctx, commandContext.ClusterSpec)...)
-	runner.Register(s.validations(ctx, commandContext)...)
+	runner := validations.NewRunner(commandContext.ValidationOpts...)
+	runner.RegisterChecks(validations.ProviderCreateChecks(ctx, commandContext.Provider, commandContext.ClusterSpec)...)
+	runner.RegisterChecks(validations.DependOn(commandContext.AddonManager.Validations(ctx, commandContext.ClusterSpec), validations.ProviderSetupCheckName)...)
+	runner.RegisterChecks(validations.DependOn(commandContext.Validations.PreflightChecks(ctx), validations.ProviderSetupCheckName)...)
 
 	err := runner.Run()
 	if err != nil {
@@ -147,28 +154,6 @@ func (s *SetAndValidateTask) Run(ctx context.Context, commandContext *task.Comma
 	return &CreateBootStrapClusterTask{}
 }
 
-func (s *SetAndValidateTask) validations(ctx context.Context, commandContext *task.CommandContext) []validations.Validation {
-	return []validations.Validation{
-		func() *validations.ValidationResult {
-			return &validations.ValidationResult{
-				Name: "create preflight validations pass",
-				Err:  commandContext.Validations.PreflightValidations(ctx),
-			}
-		},
-	}
-}
-
-func (s *SetAndValidateTask) providerValidation(ctx context.Context, commandContext *task.CommandContext) []validations.Validation {
-	return []validations.Validation{
-		func() *validations.ValidationResult {
-			return &validations.ValidationResult{
-				Name: fmt.Sprintf("%s Provider setup is valid", commandContext.Provider.Name()),
-				Err:  commandContext.Provider.SetupAndValidateCreateCluster(ctx, commandContext.ClusterSpec),
-			}
-		},
-	}
-}
-
 func (s *SetAndValidateTask) Name() string {
 	return "setup-validate"
 }
@@ -200,6 +185,15 @@ func (s *CreateWorkloadClusterTask) Run(ctx context.Context, commandContext *tas
 		}
 	}
 
+	if commandContext.ClusterSpec.Spec.LoadBalancer != nil {
+		logger.Info("Installing load balancer on workload cluster")
+		err = commandContext.ClusterManager.InstallLoadBalancer(ctx, workloadCluster, commandContext.ClusterSpec)
+		if err != nil {
+			commandContext.SetError(err)
+			return &CollectDiagnosticsTask{}
+		}
+	}
+
 	logger.Info("Installing storage class on workload cluster")
 	err = commandContext.ClusterManager.InstallStorageClass(ctx, workloadCluster, commandContext.Provider)
 	if err != nil {
@@ -284,6 +278,15 @@ func (s *InstallEksaComponentsTask) Run(ctx context.Context, commandContext *tas
 		commandContext.SetError(err)
 		return &CollectDiagnosticsTask{}
 	}
+
+	if commandContext.ClusterSpec.Spec.EtcdBackup != nil {
+		logger.Info("Installing etcd backup schedule on workload cluster")
+		err = commandContext.ClusterManager.InstallEtcdBackup(ctx, targetCluster, commandContext.WorkloadCluster, commandContext.ClusterSpec)
+		if err != nil {
+			commandContext.SetError(err)
+			return &CollectDiagnosticsTask{}
+		}
+	}
 	return &InstallAddonManagerTask{}
 }
 
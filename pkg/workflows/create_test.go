@@ -2,6 +2,7 @@ package workflows_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -14,6 +15,7 @@ import (
 	"github.com/aws/eks-anywhere/pkg/providers"
 	providermocks "github.com/aws/eks-anywhere/pkg/providers/mocks"
 	"github.com/aws/eks-anywhere/pkg/types"
+	"github.com/aws/eks-anywhere/pkg/validations"
 	"github.com/aws/eks-anywhere/pkg/workflows"
 	"github.com/aws/eks-anywhere/pkg/workflows/interfaces/mocks"
 )
@@ -91,7 +93,7 @@ func (c *createTestSetup) expectCreateBootstrap() {
 }
 
 func (c *createTestSetup) expectCreateWorkload() {
-	gomock.InOrder(
+	calls := []*gomock.Call{
 		c.clusterManager.EXPECT().CreateWorkloadCluster(
 			c.ctx, c.bootstrapCluster, c.clusterSpec, c.provider,
 		).Return(c.workloadCluster, nil),
@@ -99,6 +101,13 @@ func (c *createTestSetup) expectCreateWorkload() {
 		c.clusterManager.EXPECT().InstallNetworking(
 			c.ctx, c.workloadCluster, c.clusterSpec,
 		),
+	}
+	if c.clusterSpec.Spec.LoadBalancer != nil {
+		calls = append(calls, c.clusterManager.EXPECT().InstallLoadBalancer(
+			c.ctx, c.workloadCluster, c.clusterSpec,
+		))
+	}
+	calls = append(calls,
 		c.clusterManager.EXPECT().InstallStorageClass(
 			c.ctx, c.workloadCluster, c.provider,
 		),
@@ -106,6 +115,7 @@ func (c *createTestSetup) expectCreateWorkload() {
 			c.ctx, c.clusterSpec, c.workloadCluster, c.provider,
 		),
 	)
+	gomock.InOrder(calls...)
 }
 
 func (c *createTestSetup) expectCreateWorkloadSkipCAPI() {
@@ -211,7 +221,7 @@ func (c *createTestSetup) run() error {
 }
 
 func (c *createTestSetup) expectPreflightValidationsToPass() {
-	c.validator.EXPECT().PreflightValidations(c.ctx).Return(nil)
+	c.validator.EXPECT().PreflightChecks(c.ctx).Return(nil)
 }
 
 func TestCreateRunSuccess(t *testing.T) {
@@ -234,6 +244,49 @@ func TestCreateRunSuccess(t *testing.T) {
 	}
 }
 
+func TestCreateRunSuccessWithLoadBalancer(t *testing.T) {
+	test := newCreateTest(t)
+	test.clusterSpec.Spec.LoadBalancer = &v1alpha1.LoadBalancerConfiguration{Provider: v1alpha1.KubeVipLoadBalancer}
+
+	test.expectSetup()
+	test.expectCreateBootstrap()
+	test.expectCreateWorkload()
+	test.expectMoveManagement()
+	test.expectInstallEksaComponents()
+	test.expectInstallAddonManager()
+	test.expectWriteClusterConfig()
+	test.expectDeleteBootstrap()
+	test.expectInstallMHC()
+	test.expectPreflightValidationsToPass()
+
+	err := test.run()
+	if err != nil {
+		t.Fatalf("Create.Run() err = %v, want err = nil", err)
+	}
+}
+
+func TestCreateRunSuccessWithEtcdBackup(t *testing.T) {
+	test := newCreateTest(t)
+	test.clusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+
+	test.expectSetup()
+	test.expectCreateBootstrap()
+	test.expectCreateWorkload()
+	test.expectMoveManagement()
+	test.expectInstallEksaComponents()
+	test.clusterManager.EXPECT().InstallEtcdBackup(test.ctx, test.workloadCluster, test.workloadCluster, test.clusterSpec)
+	test.expectInstallAddonManager()
+	test.expectWriteClusterConfig()
+	test.expectDeleteBootstrap()
+	test.expectInstallMHC()
+	test.expectPreflightValidationsToPass()
+
+	err := test.run()
+	if err != nil {
+		t.Fatalf("Create.Run() err = %v, want err = nil", err)
+	}
+}
+
 func TestCreateRunSuccessForceCleanup(t *testing.T) {
 	test := newCreateTest(t)
 	test.forceCleanup = true
@@ -283,3 +336,23 @@ func TestCreateWorkloadClusterRunSuccess(t *testing.T) {
 		t.Fatalf("Create.Run() err = %v, want err = nil", err)
 	}
 }
+
+func TestCreateRunProviderSetupFailsSkipsPreflightValidations(t *testing.T) {
+	test := newCreateTest(t)
+	test.provider.EXPECT().SetupAndValidateCreateCluster(test.ctx, test.clusterSpec).Return(errors.New("invalid datacenter"))
+	test.provider.EXPECT().Name().Return("vsphere")
+	test.addonManager.EXPECT().Validations(test.ctx, test.clusterSpec)
+	test.validator.EXPECT().PreflightChecks(test.ctx).Return([]validations.Check{
+		{
+			Name: "preflight",
+			Validation: func() *validations.ValidationResult {
+				t.Error("preflight validation shouldn't run when provider setup fails")
+				return &validations.ValidationResult{}
+			},
+		},
+	})
+
+	if err := test.run(); err == nil {
+		t.Fatal("Create.Run() err = nil, want err not nil")
+	}
+}
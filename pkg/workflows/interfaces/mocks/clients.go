@@ -239,6 +239,34 @@ func (mr *MockClusterManagerMockRecorder) InstallCustomComponents(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallCustomComponents", reflect.TypeOf((*MockClusterManager)(nil).InstallCustomComponents), arg0, arg1, arg2)
 }
 
+// InstallEtcdBackup mocks base method.
+func (m *MockClusterManager) InstallEtcdBackup(arg0 context.Context, arg1, arg2 *types.Cluster, arg3 *cluster.Spec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallEtcdBackup", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallEtcdBackup indicates an expected call of InstallEtcdBackup.
+func (mr *MockClusterManagerMockRecorder) InstallEtcdBackup(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallEtcdBackup", reflect.TypeOf((*MockClusterManager)(nil).InstallEtcdBackup), arg0, arg1, arg2, arg3)
+}
+
+// InstallLoadBalancer mocks base method.
+func (m *MockClusterManager) InstallLoadBalancer(arg0 context.Context, arg1 *types.Cluster, arg2 *cluster.Spec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallLoadBalancer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallLoadBalancer indicates an expected call of InstallLoadBalancer.
+func (mr *MockClusterManagerMockRecorder) InstallLoadBalancer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallLoadBalancer", reflect.TypeOf((*MockClusterManager)(nil).InstallLoadBalancer), arg0, arg1, arg2)
+}
+
 // InstallMachineHealthChecks mocks base method.
 func (m *MockClusterManager) InstallMachineHealthChecks(arg0 context.Context, arg1 *types.Cluster, arg2 providers.Provider) error {
 	m.ctrl.T.Helper()
@@ -356,6 +384,34 @@ func (mr *MockClusterManagerMockRecorder) SaveLogsWorkloadCluster(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLogsWorkloadCluster", reflect.TypeOf((*MockClusterManager)(nil).SaveLogsWorkloadCluster), arg0, arg1, arg2, arg3)
 }
 
+// SnapshotCluster mocks base method.
+func (m *MockClusterManager) SnapshotCluster(arg0 context.Context, arg1 *types.Cluster, arg2 *cluster.Spec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotCluster", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SnapshotCluster indicates an expected call of SnapshotCluster.
+func (mr *MockClusterManagerMockRecorder) SnapshotCluster(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotCluster", reflect.TypeOf((*MockClusterManager)(nil).SnapshotCluster), arg0, arg1, arg2)
+}
+
+// UninstallEtcdBackup mocks base method.
+func (m *MockClusterManager) UninstallEtcdBackup(arg0 context.Context, arg1 *types.Cluster, arg2 *cluster.Spec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallEtcdBackup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallEtcdBackup indicates an expected call of UninstallEtcdBackup.
+func (mr *MockClusterManagerMockRecorder) UninstallEtcdBackup(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallEtcdBackup", reflect.TypeOf((*MockClusterManager)(nil).UninstallEtcdBackup), arg0, arg1, arg2)
+}
+
 // Upgrade mocks base method.
 func (m *MockClusterManager) Upgrade(arg0 context.Context, arg1 *types.Cluster, arg2, arg3 *cluster.Spec) (*types.ChangeDiff, error) {
 	m.ctrl.T.Helper()
@@ -522,10 +578,10 @@ func (mr *MockAddonManagerMockRecorder) Upgrade(arg0, arg1, arg2, arg3 interface
 }
 
 // Validations mocks base method.
-func (m *MockAddonManager) Validations(arg0 context.Context, arg1 *cluster.Spec) []validations.Validation {
+func (m *MockAddonManager) Validations(arg0 context.Context, arg1 *cluster.Spec) []validations.Check {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Validations", arg0, arg1)
-	ret0, _ := ret[0].([]validations.Validation)
+	ret0, _ := ret[0].([]validations.Check)
 	return ret0
 }
 
@@ -558,18 +614,18 @@ func (m *MockValidator) EXPECT() *MockValidatorMockRecorder {
 	return m.recorder
 }
 
-// PreflightValidations mocks base method.
-func (m *MockValidator) PreflightValidations(arg0 context.Context) error {
+// PreflightChecks mocks base method.
+func (m *MockValidator) PreflightChecks(arg0 context.Context) []validations.Check {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PreflightValidations", arg0)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "PreflightChecks", arg0)
+	ret0, _ := ret[0].([]validations.Check)
 	return ret0
 }
 
-// PreflightValidations indicates an expected call of PreflightValidations.
-func (mr *MockValidatorMockRecorder) PreflightValidations(arg0 interface{}) *gomock.Call {
+// PreflightChecks indicates an expected call of PreflightChecks.
+func (mr *MockValidatorMockRecorder) PreflightChecks(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreflightValidations", reflect.TypeOf((*MockValidator)(nil).PreflightValidations), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreflightChecks", reflect.TypeOf((*MockValidator)(nil).PreflightChecks), arg0)
 }
 
 // MockCAPIManager is a mock of CAPIManager interface.
@@ -2,7 +2,6 @@ package workflows
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/aws/eks-anywhere/pkg/cluster"
 	"github.com/aws/eks-anywhere/pkg/clustermarshaller"
@@ -23,6 +22,7 @@ type Upgrade struct {
 	writer            filewriter.FileWriter
 	capiManager       interfaces.CAPIManager
 	upgradeChangeDiff *types.ChangeDiff
+	validationOpts    []validations.RunnerOpt
 }
 
 func NewUpgrade(bootstrapper interfaces.Bootstrapper, provider providers.Provider,
@@ -40,6 +40,12 @@ func NewUpgrade(bootstrapper interfaces.Bootstrapper, provider providers.Provide
 	}
 }
 
+// WithValidationOpts configures the runner of the setup and preflight validations
+func (c *Upgrade) WithValidationOpts(opts ...validations.RunnerOpt) *Upgrade {
+	c.validationOpts = append(c.validationOpts, opts...)
+	return c
+}
+
 func (c *Upgrade) Run(ctx context.Context, clusterSpec *cluster.Spec, workloadCluster *types.Cluster, validator interfaces.Validator, forceCleanup bool) error {
 	if forceCleanup {
 		if err := c.bootstrapper.DeleteBootstrapCluster(ctx, &types.Cluster{
@@ -57,6 +63,7 @@ func (c *Upgrade) Run(ctx context.Context, clusterSpec *cluster.Spec, workloadCl
 		WorkloadCluster:   workloadCluster,
 		ClusterSpec:       clusterSpec,
 		Validations:       validator,
+		ValidationOpts:    c.validationOpts,
 		Writer:            c.writer,
 		CAPIManager:       c.capiManager,
 		UpgradeChangeDiff: c.upgradeChangeDiff,
@@ -79,6 +86,8 @@ type upgradeCoreComponents struct{}
 
 type upgradeNeeded struct{}
 
+type snapshotClusterTask struct{}
+
 type pauseEksaAndFluxReconcile struct{}
 
 type createBootstrapClusterTask struct{}
@@ -99,6 +108,8 @@ type deleteBootstrapClusterTask struct {
 	*CollectDiagnosticsTask
 }
 
+type reconcileEtcdBackupTask struct{}
+
 type updateClusterAndGitResources struct{}
 
 type resumeFluxReconcile struct{}
@@ -107,8 +118,9 @@ type writeClusterConfigTask struct{}
 
 func (s *setupAndValidateTasks) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
 	logger.Info("Performing setup and validations")
-	runner := validations.NewRunner()
-	runner.Register(s.validations(ctx, commandContext)...)
+	runner := validations.NewRunner(commandContext.ValidationOpts...)
+	runner.RegisterChecks(validations.ProviderUpgradeChecks(ctx, commandContext.Provider, getManagementCluster(commandContext), commandContext.ClusterSpec)...)
+	runner.RegisterChecks(validations.DependOn(commandContext.Validations.PreflightChecks(ctx), validations.ProviderSetupCheckName)...)
 
 	err := runner.Run()
 	if err != nil {
@@ -119,24 +131,6 @@ func (s *setupAndValidateTasks) Run(ctx context.Context, commandContext *task.Co
 	return &updateSecrets{}
 }
 
-func (s *setupAndValidateTasks) validations(ctx context.Context, commandContext *task.CommandContext) []validations.Validation {
-	return []validations.Validation{
-		func() *validations.ValidationResult {
-			target := getManagementCluster(commandContext)
-			return &validations.ValidationResult{
-				Name: fmt.Sprintf("%s Provider setup is valid", commandContext.Provider.Name()),
-				Err:  commandContext.Provider.SetupAndValidateUpgradeCluster(ctx, target, commandContext.ClusterSpec),
-			}
-		},
-		func() *validations.ValidationResult {
-			return &validations.ValidationResult{
-				Name: "upgrade preflight validations pass",
-				Err:  commandContext.Validations.PreflightValidations(ctx),
-			}
-		},
-	}
-}
-
 func (s *setupAndValidateTasks) Name() string {
 	return "setup-and-validate"
 }
@@ -221,7 +215,7 @@ func (s *upgradeNeeded) Run(ctx context.Context, commandContext *task.CommandCon
 		return nil
 	} else if upgradeNeeded {
 		logger.V(3).Info("Provider needs a cluster upgrade")
-		return &pauseEksaAndFluxReconcile{}
+		return &snapshotClusterTask{}
 	}
 
 	target := getManagementCluster(commandContext)
@@ -240,13 +234,29 @@ func (s *upgradeNeeded) Run(ctx context.Context, commandContext *task.CommandCon
 		return nil
 	}
 
-	return &pauseEksaAndFluxReconcile{}
+	return &snapshotClusterTask{}
 }
 
 func (s *upgradeNeeded) Name() string {
 	return "upgrade-needed"
 }
 
+func (s *snapshotClusterTask) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
+	target := getManagementCluster(commandContext)
+
+	logger.Info("Saving cluster objects for rollback")
+	err := commandContext.ClusterManager.SnapshotCluster(ctx, target, commandContext.CurrentClusterSpec)
+	if err != nil {
+		commandContext.SetError(err)
+		return &CollectDiagnosticsTask{}
+	}
+	return &pauseEksaAndFluxReconcile{}
+}
+
+func (s *snapshotClusterTask) Name() string {
+	return "snapshot-cluster"
+}
+
 func (s *pauseEksaAndFluxReconcile) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
 	target := getManagementCluster(commandContext)
 
@@ -352,7 +362,7 @@ func (s *upgradeWorkloadClusterTask) Name() string {
 
 func (s *moveManagementToWorkloadTask) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
 	if commandContext.BootstrapCluster.ExistingManagement {
-		return &updateClusterAndGitResources{}
+		return &reconcileEtcdBackupTask{}
 	}
 	logger.Info("Moving cluster management from bootstrap to workload cluster")
 	err := commandContext.ClusterManager.MoveCAPI(ctx, commandContext.BootstrapCluster, commandContext.WorkloadCluster, commandContext.WorkloadCluster.Name, types.WithNodeRef(), types.WithNodeHealthy())
@@ -360,7 +370,7 @@ func (s *moveManagementToWorkloadTask) Run(ctx context.Context, commandContext *
 		commandContext.SetError(err)
 		return &CollectDiagnosticsTask{}
 	}
-	return &updateClusterAndGitResources{}
+	return &reconcileEtcdBackupTask{}
 }
 
 func (s *moveManagementToWorkloadTaskAndExit) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
@@ -372,6 +382,30 @@ func (s *moveManagementToWorkloadTask) Name() string {
 	return "capi-management-move-to-workload"
 }
 
+func (s *reconcileEtcdBackupTask) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
+	newBackup := commandContext.ClusterSpec.Spec.EtcdBackup
+	currentBackup := commandContext.CurrentClusterSpec.Spec.EtcdBackup
+
+	var err error
+	switch {
+	case newBackup != nil && (!newBackup.Equal(currentBackup) || commandContext.UpgradeChangeDiff.Changed()):
+		logger.Info("Installing etcd backup schedule on workload cluster")
+		err = commandContext.ClusterManager.InstallEtcdBackup(ctx, getManagementCluster(commandContext), commandContext.WorkloadCluster, commandContext.ClusterSpec)
+	case newBackup == nil && currentBackup != nil:
+		logger.Info("Removing etcd backup schedule from workload cluster")
+		err = commandContext.ClusterManager.UninstallEtcdBackup(ctx, commandContext.WorkloadCluster, commandContext.CurrentClusterSpec)
+	}
+	if err != nil {
+		commandContext.SetError(err)
+		return &CollectDiagnosticsTask{}
+	}
+	return &updateClusterAndGitResources{}
+}
+
+func (s *reconcileEtcdBackupTask) Name() string {
+	return "reconcile-etcd-backup"
+}
+
 func (s *updateClusterAndGitResources) Run(ctx context.Context, commandContext *task.CommandContext) task.Task {
 	target := getManagementCluster(commandContext)
 
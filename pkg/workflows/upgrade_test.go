@@ -53,21 +53,22 @@ func newUpgradeTest(t *testing.T) *upgradeTestSetup {
 	workflow := workflows.NewUpgrade(bootstrapper, provider, capiUpgrader, clusterManager, addonManager, writer)
 
 	return &upgradeTestSetup{
-		t:                t,
-		bootstrapper:     bootstrapper,
-		clusterManager:   clusterManager,
-		addonManager:     addonManager,
-		provider:         provider,
-		writer:           writer,
-		validator:        validator,
-		capiManager:      capiUpgrader,
-		datacenterConfig: datacenterConfig,
-		machineConfigs:   machineConfigs,
-		workflow:         workflow,
-		ctx:              context.Background(),
-		newClusterSpec:   test.NewClusterSpec(func(s *cluster.Spec) { s.Name = "cluster-name" }),
-		bootstrapCluster: &types.Cluster{Name: "bootstrap"},
-		workloadCluster:  &types.Cluster{Name: "workload"},
+		t:                  t,
+		bootstrapper:       bootstrapper,
+		clusterManager:     clusterManager,
+		addonManager:       addonManager,
+		provider:           provider,
+		writer:             writer,
+		validator:          validator,
+		capiManager:        capiUpgrader,
+		datacenterConfig:   datacenterConfig,
+		machineConfigs:     machineConfigs,
+		workflow:           workflow,
+		ctx:                context.Background(),
+		newClusterSpec:     test.NewClusterSpec(func(s *cluster.Spec) { s.Name = "cluster-name" }),
+		currentClusterSpec: test.NewClusterSpec(func(s *cluster.Spec) { s.Name = "cluster-name" }),
+		bootstrapCluster:   &types.Cluster{Name: "bootstrap"},
+		workloadCluster:    &types.Cluster{Name: "workload"},
 	}
 }
 
@@ -194,6 +195,10 @@ func (c *upgradeTestSetup) expectNotToMoveManagementToWorkload() {
 	c.clusterManager.EXPECT().MoveCAPI(c.ctx, c.bootstrapCluster, c.workloadCluster, gomock.Any(), gomock.Any()).Times(0)
 }
 
+func (c *upgradeTestSetup) expectSnapshotCluster(expectedCluster *types.Cluster) {
+	c.clusterManager.EXPECT().SnapshotCluster(c.ctx, expectedCluster, c.currentClusterSpec)
+}
+
 func (c *upgradeTestSetup) expectPauseEKSAControllerReconcile(expectedCluster *types.Cluster) {
 	gomock.InOrder(
 		c.clusterManager.EXPECT().PauseEKSAControllerReconcile(
@@ -313,8 +318,16 @@ func (c *upgradeTestSetup) expectCreateBootstrapNotToBeCalled() {
 	c.clusterManager.EXPECT().InstallCAPI(c.ctx, gomock.Not(gomock.Nil()), c.bootstrapCluster, c.provider).Times(0)
 }
 
+func (c *upgradeTestSetup) expectInstallEtcdBackup(expectedManagementCluster *types.Cluster) {
+	c.clusterManager.EXPECT().InstallEtcdBackup(c.ctx, expectedManagementCluster, c.workloadCluster, c.newClusterSpec)
+}
+
+func (c *upgradeTestSetup) expectUninstallEtcdBackup() {
+	c.clusterManager.EXPECT().UninstallEtcdBackup(c.ctx, c.workloadCluster, c.currentClusterSpec)
+}
+
 func (c *upgradeTestSetup) expectPreflightValidationsToPass() {
-	c.validator.EXPECT().PreflightValidations(c.ctx).Return(nil)
+	c.validator.EXPECT().PreflightChecks(c.ctx).Return(nil)
 }
 
 func TestSkipUpgradeRunSuccess(t *testing.T) {
@@ -345,6 +358,7 @@ func TestUpgradeRunSuccess(t *testing.T) {
 	test.expectUpgradeCoreComponents(test.workloadCluster)
 	test.expectProviderNoUpgradeNeeded()
 	test.expectVerifyClusterSpecChanged(test.workloadCluster)
+	test.expectSnapshotCluster(test.workloadCluster)
 	test.expectPauseEKSAControllerReconcile(test.workloadCluster)
 	test.expectPauseGitOpsKustomization(test.workloadCluster)
 	test.expectCreateBootstrap()
@@ -375,6 +389,7 @@ func TestUpgradeRunProviderNeedsUpgradeSuccess(t *testing.T) {
 	test.expectEnsureEtcdCAPIComponentsExistTask(test.workloadCluster)
 	test.expectUpgradeCoreComponents(test.workloadCluster)
 	test.expectProviderUpgradeNeeded()
+	test.expectSnapshotCluster(test.workloadCluster)
 	test.expectPauseEKSAControllerReconcile(test.workloadCluster)
 	test.expectPauseGitOpsKustomization(test.workloadCluster)
 	test.expectCreateBootstrap()
@@ -406,6 +421,7 @@ func TestUpgradeRunFailedUpgrade(t *testing.T) {
 	test.expectUpgradeCoreComponents(test.workloadCluster)
 	test.expectProviderNoUpgradeNeeded()
 	test.expectVerifyClusterSpecChanged(test.workloadCluster)
+	test.expectSnapshotCluster(test.workloadCluster)
 	test.expectPauseEKSAControllerReconcile(test.workloadCluster)
 	test.expectPauseGitOpsKustomization(test.workloadCluster)
 	test.expectCreateBootstrap()
@@ -420,6 +436,25 @@ func TestUpgradeRunFailedUpgrade(t *testing.T) {
 	}
 }
 
+func TestUpgradeRunSnapshotClusterFails(t *testing.T) {
+	test := newUpgradeTest(t)
+	test.expectSetup()
+	test.expectPreflightValidationsToPass()
+	test.expectUpdateSecrets(test.workloadCluster)
+	test.expectEnsureEtcdCAPIComponentsExistTask(test.workloadCluster)
+	test.expectUpgradeCoreComponents(test.workloadCluster)
+	test.expectProviderUpgradeNeeded()
+	test.clusterManager.EXPECT().SnapshotCluster(test.ctx, test.workloadCluster, test.currentClusterSpec).Return(errors.New("failed snapshot"))
+	test.expectPauseEKSAControllerReconcileNotToBeCalled()
+	test.clusterManager.EXPECT().SaveLogsManagementCluster(test.ctx, nil)
+	test.clusterManager.EXPECT().SaveLogsWorkloadCluster(test.ctx, test.provider, test.newClusterSpec, test.workloadCluster)
+
+	err := test.run()
+	if err == nil {
+		t.Fatal("Upgrade.Run() err = nil, want err not nil")
+	}
+}
+
 func TestUpgradeWorkloadRunSuccess(t *testing.T) {
 	test := newUpgradeTest(t)
 	test.newClusterSpec.SetSelfManaged()
@@ -443,6 +478,7 @@ func TestUpgradeWorkloadRunSuccess(t *testing.T) {
 	test.expectUpgradeCoreComponents(test.bootstrapCluster)
 	test.expectProviderNoUpgradeNeeded()
 	test.expectVerifyClusterSpecChanged(test.bootstrapCluster)
+	test.expectSnapshotCluster(test.bootstrapCluster)
 	test.expectPauseEKSAControllerReconcile(test.bootstrapCluster)
 	test.expectPauseGitOpsKustomization(test.bootstrapCluster)
 	test.expectNotToCreateBootstrap()
@@ -464,3 +500,71 @@ func TestUpgradeWorkloadRunSuccess(t *testing.T) {
 		t.Fatalf("Upgrade.Run() err = %v, want err = nil", err)
 	}
 }
+
+func (c *upgradeTestSetup) expectUpgradeUntilEtcdBackup() {
+	c.expectSetup()
+	c.expectPreflightValidationsToPass()
+	c.expectUpdateSecrets(c.workloadCluster)
+	c.expectEnsureEtcdCAPIComponentsExistTask(c.workloadCluster)
+	c.expectUpgradeCoreComponents(c.workloadCluster)
+	c.expectProviderNoUpgradeNeeded()
+	c.expectVerifyClusterSpecChanged(c.workloadCluster)
+	c.expectSnapshotCluster(c.workloadCluster)
+	c.expectPauseEKSAControllerReconcile(c.workloadCluster)
+	c.expectPauseGitOpsKustomization(c.workloadCluster)
+	c.expectCreateBootstrap()
+	c.expectMoveManagementToBootstrap()
+	c.expectUpgradeWorkload(c.workloadCluster)
+	c.expectMoveManagementToWorkload()
+}
+
+func (c *upgradeTestSetup) expectUpgradeAfterEtcdBackup() {
+	c.expectWriteClusterConfig()
+	c.expectDeleteBootstrap()
+	c.expectDatacenterConfig()
+	c.expectMachineConfigs()
+	c.expectCreateEKSAResources(c.workloadCluster)
+	c.expectResumeEKSAControllerReconcile(c.workloadCluster)
+	c.expectUpdateGitEksaSpec()
+	c.expectForceReconcileGitRepo(c.workloadCluster)
+	c.expectResumeGitOpsKustomization(c.workloadCluster)
+}
+
+func TestUpgradeRunInstallEtcdBackup(t *testing.T) {
+	test := newUpgradeTest(t)
+	test.newClusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	test.expectUpgradeUntilEtcdBackup()
+	test.expectInstallEtcdBackup(test.workloadCluster)
+	test.expectUpgradeAfterEtcdBackup()
+
+	err := test.run()
+	if err != nil {
+		t.Fatalf("Upgrade.Run() err = %v, want err = nil", err)
+	}
+}
+
+func TestUpgradeRunUninstallEtcdBackup(t *testing.T) {
+	test := newUpgradeTest(t)
+	test.currentClusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	test.expectUpgradeUntilEtcdBackup()
+	test.expectUninstallEtcdBackup()
+	test.expectUpgradeAfterEtcdBackup()
+
+	err := test.run()
+	if err != nil {
+		t.Fatalf("Upgrade.Run() err = %v, want err = nil", err)
+	}
+}
+
+func TestUpgradeRunInstallEtcdBackupFails(t *testing.T) {
+	test := newUpgradeTest(t)
+	test.newClusterSpec.Spec.EtcdBackup = &v1alpha1.EtcdBackupConfiguration{Schedule: "0 */6 * * *", PersistentVolumeClaim: "etcd-backups"}
+	test.expectUpgradeUntilEtcdBackup()
+	test.clusterManager.EXPECT().InstallEtcdBackup(test.ctx, test.workloadCluster, test.workloadCluster, test.newClusterSpec).Return(errors.New("failed applying cronjob"))
+	test.expectSaveLogs(test.workloadCluster)
+
+	err := test.run()
+	if err == nil {
+		t.Fatal("Upgrade.Run() err = nil, want err not nil")
+	}
+}
@@ -82,4 +82,10 @@ type Manifest struct {
 	// +kubebuilder:validation:Required
 	// URI points to the manifest yaml file
 	URI string `json:"uri,omitempty"`
+	// +optional
+	// The sha512 of the manifest
+	SHA512 string `json:"sha512,omitempty"`
+	// +optional
+	// The sha256 of the manifest
+	SHA256 string `json:"sha256,omitempty"`
 }
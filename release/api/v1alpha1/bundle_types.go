@@ -72,6 +72,8 @@ type VersionsBundle struct {
 	BottleRocketAdmin      BottlerocketAdminBundle     `json:"bottlerocketAdmin"`
 	ExternalEtcdBootstrap  EtcdadmBootstrapBundle      `json:"etcdadmBootstrap"`
 	ExternalEtcdController EtcdadmControllerBundle     `json:"etcdadmController"`
+	LoadBalancer           LoadBalancerBundle          `json:"loadBalancer,omitempty"`
+	NodeLocalDNS           NodeLocalDNSBundle          `json:"nodeLocalDNS,omitempty"`
 }
 
 type EksDRelease struct {
@@ -91,6 +93,14 @@ type EksDRelease struct {
 	// assets where created
 	EksDReleaseUrl string `json:"manifestUrl,omitempty"`
 
+	// +optional
+	// The sha512 of the EKS-D release manifest
+	ManifestSHA512 string `json:"manifestSha512,omitempty"`
+
+	// +optional
+	// The sha256 of the EKS-D release manifest
+	ManifestSHA256 string `json:"manifestSha256,omitempty"`
+
 	// +kubebuilder:validation:Required
 	// Git commit the component is built from, before any patches
 	GitCommit string `json:"gitCommit,omitempty"`
@@ -185,10 +195,25 @@ type DockerBundle struct {
 }
 
 type CiliumBundle struct {
-	Version  string   `json:"version,omitempty"`
-	Cilium   Image    `json:"cilium"`
-	Operator Image    `json:"operator"`
-	Manifest Manifest `json:"manifest"`
+	Version         string   `json:"version,omitempty"`
+	Cilium          Image    `json:"cilium"`
+	Operator        Image    `json:"operator"`
+	HubbleRelay     Image    `json:"hubbleRelay,omitempty"`
+	HubbleUI        Image    `json:"hubbleUI,omitempty"`
+	HubbleUIBackend Image    `json:"hubbleUIBackend,omitempty"`
+	Manifest        Manifest `json:"manifest"`
+}
+
+type LoadBalancerBundle struct {
+	Version              string `json:"version,omitempty"`
+	KubeVipCloudProvider Image  `json:"kubeVipCloudProvider,omitempty"`
+	MetalLBController    Image  `json:"metallbController,omitempty"`
+	MetalLBSpeaker       Image  `json:"metallbSpeaker,omitempty"`
+}
+
+type NodeLocalDNSBundle struct {
+	Version string `json:"version,omitempty"`
+	Image   Image  `json:"image,omitempty"`
 }
 
 type FluxBundle struct {
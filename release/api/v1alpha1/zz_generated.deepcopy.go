@@ -249,6 +249,9 @@ func (in *CiliumBundle) DeepCopyInto(out *CiliumBundle) {
 	*out = *in
 	in.Cilium.DeepCopyInto(&out.Cilium)
 	in.Operator.DeepCopyInto(&out.Operator)
+	in.HubbleRelay.DeepCopyInto(&out.HubbleRelay)
+	in.HubbleUI.DeepCopyInto(&out.HubbleUI)
+	in.HubbleUIBackend.DeepCopyInto(&out.HubbleUIBackend)
 	out.Manifest = in.Manifest
 }
 
@@ -468,6 +471,24 @@ func (in *KubeadmControlPlaneBundle) DeepCopy() *KubeadmControlPlaneBundle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerBundle) DeepCopyInto(out *LoadBalancerBundle) {
+	*out = *in
+	in.KubeVipCloudProvider.DeepCopyInto(&out.KubeVipCloudProvider)
+	in.MetalLBController.DeepCopyInto(&out.MetalLBController)
+	in.MetalLBSpeaker.DeepCopyInto(&out.MetalLBSpeaker)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerBundle.
+func (in *LoadBalancerBundle) DeepCopy() *LoadBalancerBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manifest) DeepCopyInto(out *Manifest) {
 	*out = *in
@@ -483,6 +504,22 @@ func (in *Manifest) DeepCopy() *Manifest {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLocalDNSBundle) DeepCopyInto(out *NodeLocalDNSBundle) {
+	*out = *in
+	in.Image.DeepCopyInto(&out.Image)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLocalDNSBundle.
+func (in *NodeLocalDNSBundle) DeepCopy() *NodeLocalDNSBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLocalDNSBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OvaArchive) DeepCopyInto(out *OvaArchive) {
 	*out = *in
@@ -639,6 +676,8 @@ func (in *VersionsBundle) DeepCopyInto(out *VersionsBundle) {
 	in.BottleRocketAdmin.DeepCopyInto(&out.BottleRocketAdmin)
 	in.ExternalEtcdBootstrap.DeepCopyInto(&out.ExternalEtcdBootstrap)
 	in.ExternalEtcdController.DeepCopyInto(&out.ExternalEtcdController)
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	in.NodeLocalDNS.DeepCopyInto(&out.NodeLocalDNS)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionsBundle.
@@ -199,7 +199,7 @@ var releaseCmd = &cobra.Command{
 			} else {
 				bundleReleaseManifestKey = fmt.Sprintf("/releases/bundles/%d/manifest.yaml", releaseConfig.BundleNumber)
 			}
-			err = pkg.UploadFileToS3(bundleReleaseManifestFile, aws.String(releaseConfig.ReleaseBucket), aws.String(bundleReleaseManifestKey), releaseClients.S3.Uploader)
+			err = pkg.UploadFileWithChecksumToS3(bundleReleaseManifestFile, aws.String(releaseConfig.ReleaseBucket), aws.String(bundleReleaseManifestKey), releaseClients.S3.Uploader)
 			if err != nil {
 				fmt.Printf("Error uploading bundle manifest to release bucket: %+v", err)
 				os.Exit(1)
@@ -286,7 +286,7 @@ var releaseCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
-			err = pkg.UploadFileToS3(eksAReleaseManifestFile, aws.String(releaseConfig.ReleaseBucket), aws.String(eksAReleaseManifestKey), releaseClients.S3.Uploader)
+			err = pkg.UploadFileWithChecksumToS3(eksAReleaseManifestFile, aws.String(releaseConfig.ReleaseBucket), aws.String(eksAReleaseManifestKey), releaseClients.S3.Uploader)
 			if err != nil {
 				fmt.Printf("Error uploading bundle manifest to release bucket: %+v", err)
 				os.Exit(1)
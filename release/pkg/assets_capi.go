@@ -169,8 +169,14 @@ func (r *ReleaseConfig) GetCoreClusterAPIBundle(imageDigests map[string]string)
 					continue
 				}
 
+				sha256, sha512, err := r.readManifestShaSums(manifestArtifact)
+				if err != nil {
+					return anywherev1alpha1.CoreClusterAPI{}, errors.Wrapf(err, "Error getting checksums for manifest %s", manifestArtifact.ReleaseName)
+				}
 				bundleManifestArtifact := anywherev1alpha1.Manifest{
-					URI: manifestArtifact.ReleaseCdnURI,
+					URI:    manifestArtifact.ReleaseCdnURI,
+					SHA256: sha256,
+					SHA512: sha512,
 				}
 
 				bundleManifestArtifacts[manifestArtifact.ReleaseName] = bundleManifestArtifact
@@ -235,8 +241,14 @@ func (r *ReleaseConfig) GetKubeadmBootstrapBundle(imageDigests map[string]string
 					continue
 				}
 
+				sha256, sha512, err := r.readManifestShaSums(manifestArtifact)
+				if err != nil {
+					return anywherev1alpha1.KubeadmBootstrapBundle{}, errors.Wrapf(err, "Error getting checksums for manifest %s", manifestArtifact.ReleaseName)
+				}
 				bundleManifestArtifact := anywherev1alpha1.Manifest{
-					URI: manifestArtifact.ReleaseCdnURI,
+					URI:    manifestArtifact.ReleaseCdnURI,
+					SHA256: sha256,
+					SHA512: sha512,
 				}
 
 				bundleManifestArtifacts[manifestArtifact.ReleaseName] = bundleManifestArtifact
@@ -301,8 +313,14 @@ func (r *ReleaseConfig) GetKubeadmControlPlaneBundle(imageDigests map[string]str
 					continue
 				}
 
+				sha256, sha512, err := r.readManifestShaSums(manifestArtifact)
+				if err != nil {
+					return anywherev1alpha1.KubeadmControlPlaneBundle{}, errors.Wrapf(err, "Error getting checksums for manifest %s", manifestArtifact.ReleaseName)
+				}
 				bundleManifestArtifact := anywherev1alpha1.Manifest{
-					URI: manifestArtifact.ReleaseCdnURI,
+					URI:    manifestArtifact.ReleaseCdnURI,
+					SHA256: sha256,
+					SHA512: sha512,
 				}
 
 				bundleManifestArtifacts[manifestArtifact.ReleaseName] = bundleManifestArtifact
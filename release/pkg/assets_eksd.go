@@ -15,6 +15,8 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -208,7 +210,7 @@ func (r *ReleaseConfig) GetEksDReleaseBundle(eksDReleaseChannel, kubeVer, eksDRe
 		}
 	}
 
-	eksdRelease, err := getEksdRelease(eksDManifestUrl)
+	eksdRelease, eksdManifest, err := getEksdRelease(eksDManifestUrl)
 	if err != nil {
 		return anywherev1alpha1.EksDRelease{}, err
 	}
@@ -218,6 +220,8 @@ func (r *ReleaseConfig) GetEksDReleaseBundle(eksDReleaseChannel, kubeVer, eksDRe
 		ReleaseChannel: eksDReleaseChannel,
 		KubeVersion:    kubeVer,
 		EksDReleaseUrl: eksDManifestUrl,
+		ManifestSHA256: fmt.Sprintf("%x", sha256.Sum256(eksdManifest)),
+		ManifestSHA512: fmt.Sprintf("%x", sha512.Sum512(eksdManifest)),
 		GitCommit:      r.BuildRepoHead,
 		KindNode:       bundleImageArtifacts["kind-node"],
 		Ova: anywherev1alpha1.ArchiveBundle{
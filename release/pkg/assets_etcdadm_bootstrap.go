@@ -141,8 +141,14 @@ func (r *ReleaseConfig) GetEtcdadmBootstrapBundle(imageDigests map[string]string
 
 			if artifact.Manifest != nil {
 				manifestArtifact := artifact.Manifest
+				sha256, sha512, err := r.readManifestShaSums(manifestArtifact)
+				if err != nil {
+					return anywherev1alpha1.EtcdadmBootstrapBundle{}, errors.Wrapf(err, "Error getting checksums for manifest %s", manifestArtifact.ReleaseName)
+				}
 				bundleManifestArtifact := anywherev1alpha1.Manifest{
-					URI: manifestArtifact.ReleaseCdnURI,
+					URI:    manifestArtifact.ReleaseCdnURI,
+					SHA256: sha256,
+					SHA512: sha512,
 				}
 
 				bundleManifestArtifacts[manifestArtifact.ReleaseName] = bundleManifestArtifact
@@ -360,18 +360,20 @@ func getEcrPublicAuthConfig(ecrPublicClient *ecrpublic.ECRPublic) (*docker.AuthC
 	return authConfig, nil
 }
 
-func getEksdRelease(eksdReleaseURL string) (*eksdv1alpha1.Release, error) {
+// getEksdRelease returns the eks-d release and the manifest content it was read from, so the manifest
+// checksums can be published in the bundle
+func getEksdRelease(eksdReleaseURL string) (*eksdv1alpha1.Release, []byte, error) {
 	content, err := ReadHttpFile(eksdReleaseURL)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	eksd := &eksdv1alpha1.Release{}
 	if err = yaml.UnmarshalStrict(content, eksd); err != nil {
-		return nil, errors.Wrapf(err, "failed to unmarshal eksd manifest")
+		return nil, nil, errors.Wrapf(err, "failed to unmarshal eksd manifest")
 	}
 
-	return eksd, nil
+	return eksd, content, nil
 }
 
 func ReadHttpFile(uri string) ([]byte, error) {
@@ -15,6 +15,8 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -44,6 +46,16 @@ func (r *ReleaseConfig) readShaSums(filename string) (sha256, sha512 string, err
 	return sha256, sha512, nil
 }
 
+// readManifestShaSums computes the checksums of a manifest prepared for release, so the CLI can verify
+// the manifest it downloads
+func (r *ReleaseConfig) readManifestShaSums(manifest *ManifestArtifact) (sha256sum, sha512sum string, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(manifest.ArtifactPath, manifest.ReleaseName))
+	if err != nil {
+		return "", "", errors.Cause(err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), fmt.Sprintf("%x", sha512.Sum512(data)), nil
+}
+
 func readShaFile(filename string) (string, error) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
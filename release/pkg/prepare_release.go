@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -383,6 +384,25 @@ func UploadFileToS3(filePath string, bucketName, key *string, s3Uploader *s3mana
 	return nil
 }
 
+// UploadFileWithChecksumToS3 uploads the file to S3 together with its sha256 checksum, in the sha256sum format
+// and the key with the .sha256 suffix, for the manifests the CLI verifies against their published checksum
+func UploadFileWithChecksumToS3(filePath string, bucketName, key *string, s3Uploader *s3manager.Uploader) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return errors.Cause(err)
+	}
+	checksumFile := filePath + ".sha256"
+	checksum := fmt.Sprintf("%x  %s\n", sha256.Sum256(data), filepath.Base(aws.StringValue(key)))
+	if err = ioutil.WriteFile(checksumFile, []byte(checksum), 0o644); err != nil {
+		return errors.Cause(err)
+	}
+
+	if err = UploadFileToS3(filePath, bucketName, key, s3Uploader); err != nil {
+		return err
+	}
+	return UploadFileToS3(checksumFile, bucketName, aws.String(aws.StringValue(key)+".sha256"), s3Uploader)
+}
+
 // Gets the head commit has of the repo in the path provided
 func GetHead(path string) (string, error) {
 	repo, err := git.PlainOpen(path)